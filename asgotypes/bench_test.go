@@ -0,0 +1,108 @@
+package asgotypes
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// benchDisks returns a list of `n` disks.
+func benchDisks(n int) tftypes.Value {
+	disks := make([]tftypes.Value, n)
+	for i := range disks {
+		disks[i] = tftypes.NewValue(testDiskType, map[string]tftypes.Value{
+			"size": tftypes.NewValue(tftypes.Number, big.NewFloat(float64(i))),
+			"boot": tftypes.NewValue(tftypes.Bool, i == 0),
+		})
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: testDiskType}, disks)
+}
+
+// benchServer returns a server with `n` tags and disks.
+func benchServer(n int) tftypes.Value {
+	tags := make(map[string]tftypes.Value, n)
+	aliases := make([]tftypes.Value, n)
+	for i := 0; i < n; i++ {
+		tags[fmt.Sprintf("tag%d", i)] = tftypes.NewValue(tftypes.String, "value")
+		aliases[i] = tftypes.NewValue(tftypes.String, fmt.Sprintf("alias%d", i))
+	}
+	return tftypes.NewValue(testServerType, map[string]tftypes.Value{
+		"id":      tftypes.NewValue(tftypes.String, "abc"),
+		"name":    tftypes.NewValue(tftypes.String, "web"),
+		"port":    tftypes.NewValue(tftypes.Number, 80),
+		"tags":    tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tags),
+		"aliases": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, aliases),
+		"disk":    benchDisks(n),
+	})
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		val := benchServer(n)
+		b.Run(fmt.Sprintf("struct/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var s testServer
+				if err := Decode(val, &s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("interface/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var s interface{}
+				if err := Decode(val, &s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	str := tftypes.NewValue(tftypes.String, "abc")
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var s string
+			if err := Decode(str, &s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	num := tftypes.NewValue(tftypes.Number, 80)
+	b.Run("int", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var n int
+			if err := Decode(num, &n); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		var s testServer
+		if err := Decode(benchServer(n), &s); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("struct/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Encode(testServerType, s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := Encode(tftypes.String, "abc"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
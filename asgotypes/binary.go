@@ -0,0 +1,461 @@
+package asgotypes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// binaryVersion is the version of the format written by
+// GoPrimitive.MarshalBinary, written as its first byte so the format can
+// change without misreading older data.
+const binaryVersion = 1
+
+// Tags identifying the Go types of values, and the Terraform types, in the
+// binary format.
+const (
+	tagNil       byte = 0
+	tagString    byte = 's'
+	tagNumber    byte = 'n'
+	tagBool      byte = 'b'
+	tagInterface byte = 'i'
+	tagSlice     byte = 'l'
+	tagMap       byte = 'm'
+
+	tagTFString  byte = 'S'
+	tagTFNumber  byte = 'N'
+	tagTFBool    byte = 'B'
+	tagTFDynamic byte = 'D'
+	tagTFList    byte = 'L'
+	tagTFSet     byte = 'E'
+	tagTFMap     byte = 'M'
+	tagTFTuple   byte = 'T'
+	tagTFObject  byte = 'O'
+)
+
+var bigFloatPtrType = reflect.TypeOf((*big.Float)(nil))
+
+// MarshalBinary encodes the GoPrimitive, including its Type and
+// Collections, in a compact binary format, so it can be cached or passed
+// to another process and decoded by UnmarshalBinary without losing any
+// information, as a JSON round trip would. Numbers keep their full
+// precision, and typed slices and maps, like []string, keep their Go type.
+//
+// It also makes GoPrimitives encodable with encoding/gob.
+func (dt GoPrimitive) MarshalBinary() ([]byte, error) {
+	b := []byte{binaryVersion, byte(dt.Collections)}
+	b = appendType(b, dt.Type)
+	return appendPrimitive(b, tftypes.NewAttributePath(), reflect.ValueOf(dt.Value))
+}
+
+// UnmarshalBinary decodes a GoPrimitive encoded by MarshalBinary.
+func (dt *GoPrimitive) UnmarshalBinary(data []byte) error {
+	r := &binaryReader{b: data}
+	version, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if version != binaryVersion {
+		return fmt.Errorf("unsupported GoPrimitive encoding version %d", version)
+	}
+	collections, err := r.byte()
+	if err != nil {
+		return err
+	}
+	typ, err := r.tfType()
+	if err != nil {
+		return err
+	}
+	v, err := r.value()
+	if err != nil {
+		return err
+	}
+	if len(r.b) != 0 {
+		return fmt.Errorf("%d unexpected bytes after GoPrimitive", len(r.b))
+	}
+	*dt = GoPrimitive{Collections: CollectionMode(collections), Type: typ}
+	if v.IsValid() {
+		dt.Value = v.Interface()
+	}
+	return nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendType(b []byte, typ tftypes.Type) []byte {
+	switch t := typ.(type) {
+	case nil:
+		return append(b, tagNil)
+	case tftypes.List:
+		return appendType(append(b, tagTFList), t.ElementType)
+	case tftypes.Set:
+		return appendType(append(b, tagTFSet), t.ElementType)
+	case tftypes.Map:
+		return appendType(append(b, tagTFMap), t.ElementType)
+	case tftypes.Tuple:
+		b = binary.AppendUvarint(append(b, tagTFTuple), uint64(len(t.ElementTypes)))
+		for _, et := range t.ElementTypes {
+			b = appendType(b, et)
+		}
+		return b
+	case tftypes.Object:
+		names := make([]string, 0, len(t.AttributeTypes))
+		for name := range t.AttributeTypes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		b = binary.AppendUvarint(append(b, tagTFObject), uint64(len(names)))
+		for _, name := range names {
+			_, optional := t.OptionalAttributes[name]
+			b = appendString(b, name)
+			b = append(b, boolByte(optional))
+			b = appendType(b, t.AttributeTypes[name])
+		}
+		return b
+	}
+	switch {
+	case typ.Is(tfString):
+		return append(b, tagTFString)
+	case typ.Is(tfNumber):
+		return append(b, tagTFNumber)
+	case typ.Is(tfBool):
+		return append(b, tagTFBool)
+	}
+	return append(b, tagTFDynamic)
+}
+
+// appendGoType appends the tag of the Go type `typ`, which must be one a
+// GoPrimitive's Value can hold.
+func appendGoType(b []byte, path *tftypes.AttributePath, typ reflect.Type) ([]byte, error) {
+	switch {
+	case typ == interfaceType:
+		return append(b, tagInterface), nil
+	case typ == bigFloatPtrType:
+		return append(b, tagNumber), nil
+	case typ.Kind() == reflect.String:
+		return append(b, tagString), nil
+	case typ.Kind() == reflect.Bool:
+		return append(b, tagBool), nil
+	case typ.Kind() == reflect.Slice:
+		return appendGoType(append(b, tagSlice), path, typ.Elem())
+	case typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String:
+		return appendGoType(append(b, tagMap), path, typ.Elem())
+	}
+	return nil, path.NewErrorf("cannot encode %s in a GoPrimitive", typ)
+}
+
+// appendPrimitive appends `v`, prefixed with the tag of its Go type.
+func appendPrimitive(b []byte, path *tftypes.AttributePath, v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return append(b, tagNil), nil
+	}
+	b, err := appendGoType(b, path, v.Type())
+	if err != nil {
+		return nil, err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return appendString(b, v.String()), nil
+	case reflect.Bool:
+		return append(b, boolByte(v.Bool())), nil
+	case reflect.Ptr:
+		num, err := v.Interface().(*big.Float).GobEncode()
+		if err != nil {
+			return nil, path.NewError(err)
+		}
+		return appendString(b, string(num)), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return binary.AppendUvarint(b, 0), nil
+		}
+		b = binary.AppendUvarint(b, uint64(v.Len())+1)
+		for i := 0; i < v.Len(); i++ {
+			if b, err = appendPrimitive(b, path.WithElementKeyInt(i), v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	default:
+		if v.IsNil() {
+			return binary.AppendUvarint(b, 0), nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		b = binary.AppendUvarint(b, uint64(len(keys))+1)
+		for _, k := range keys {
+			b = appendString(b, k.String())
+			if b, err = appendPrimitive(b, path.WithElementKeyString(k.String()), v.MapIndex(k)); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+var errTruncated = errors.New("truncated GoPrimitive encoding")
+
+// binaryReader reads the format written by GoPrimitive.MarshalBinary.
+type binaryReader struct {
+	b []byte
+}
+
+func (r *binaryReader) byte() (byte, error) {
+	if len(r.b) == 0 {
+		return 0, errTruncated
+	}
+	c := r.b[0]
+	r.b = r.b[1:]
+	return c, nil
+}
+
+func (r *binaryReader) uvarint() (uint64, error) {
+	n, size := binary.Uvarint(r.b)
+	if size <= 0 {
+		return 0, errTruncated
+	}
+	r.b = r.b[size:]
+	return n, nil
+}
+
+func (r *binaryReader) string() (string, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return "", err
+	}
+	if uint64(len(r.b)) < n {
+		return "", errTruncated
+	}
+	s := string(r.b[:n])
+	r.b = r.b[n:]
+	return s, nil
+}
+
+// count reads a number of elements or attributes.
+func (r *binaryReader) count() (int, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	// Every element takes at least a byte, so a larger count can only be
+	// corrupt, and would otherwise allocate an arbitrary amount.
+	if n > uint64(len(r.b)) {
+		return 0, errTruncated
+	}
+	return int(n), nil
+}
+
+// length reads the length of a slice or map, which is written one higher
+// so that zero can mean nil, and whether it's nil.
+func (r *binaryReader) length() (int, bool, error) {
+	n, err := r.count()
+	if err != nil || n == 0 {
+		return 0, err == nil, err
+	}
+	return n - 1, false, nil
+}
+
+func (r *binaryReader) tfType() (tftypes.Type, error) {
+	tag, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagNil:
+		return nil, nil
+	case tagTFString:
+		return tftypes.String, nil
+	case tagTFNumber:
+		return tftypes.Number, nil
+	case tagTFBool:
+		return tftypes.Bool, nil
+	case tagTFDynamic:
+		return tftypes.DynamicPseudoType, nil
+	case tagTFList, tagTFSet, tagTFMap:
+		elem, err := r.tfType()
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case tagTFList:
+			return tftypes.List{ElementType: elem}, nil
+		case tagTFSet:
+			return tftypes.Set{ElementType: elem}, nil
+		}
+		return tftypes.Map{ElementType: elem}, nil
+	case tagTFTuple:
+		n, err := r.count()
+		if err != nil {
+			return nil, err
+		}
+		types := make([]tftypes.Type, 0, n)
+		for i := 0; i < n; i++ {
+			et, err := r.tfType()
+			if err != nil {
+				return nil, err
+			}
+			types = append(types, et)
+		}
+		return tftypes.Tuple{ElementTypes: types}, nil
+	case tagTFObject:
+		n, err := r.count()
+		if err != nil {
+			return nil, err
+		}
+		obj := tftypes.Object{AttributeTypes: make(map[string]tftypes.Type, n)}
+		for i := 0; i < n; i++ {
+			name, err := r.string()
+			if err != nil {
+				return nil, err
+			}
+			optional, err := r.byte()
+			if err != nil {
+				return nil, err
+			}
+			if obj.AttributeTypes[name], err = r.tfType(); err != nil {
+				return nil, err
+			}
+			if optional == 1 {
+				if obj.OptionalAttributes == nil {
+					obj.OptionalAttributes = map[string]struct{}{}
+				}
+				obj.OptionalAttributes[name] = struct{}{}
+			}
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("unknown type tag %q in GoPrimitive encoding", tag)
+}
+
+func (r *binaryReader) goType() (reflect.Type, error) {
+	tag, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	return r.goTypeOf(tag)
+}
+
+func (r *binaryReader) goTypeOf(tag byte) (reflect.Type, error) {
+	switch tag {
+	case tagInterface:
+		return interfaceType, nil
+	case tagString:
+		return stringType, nil
+	case tagNumber:
+		return bigFloatPtrType, nil
+	case tagBool:
+		return boolType, nil
+	case tagSlice, tagMap:
+		elem, err := r.goType()
+		if err != nil {
+			return nil, err
+		}
+		if tag == tagSlice {
+			return reflect.SliceOf(elem), nil
+		}
+		return reflect.MapOf(stringType, elem), nil
+	}
+	return nil, fmt.Errorf("unknown value tag %q in GoPrimitive encoding", tag)
+}
+
+// value reads a value, returning the zero reflect.Value for nil.
+func (r *binaryReader) value() (reflect.Value, error) {
+	tag, err := r.byte()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if tag == tagNil {
+		return reflect.Value{}, nil
+	}
+	typ, err := r.goTypeOf(tag)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		s, err := r.string()
+		return reflect.ValueOf(s), err
+	case reflect.Bool:
+		c, err := r.byte()
+		return reflect.ValueOf(c == 1), err
+	case reflect.Ptr:
+		s, err := r.string()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		f := new(big.Float)
+		if err := f.GobDecode([]byte(s)); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(f), nil
+	case reflect.Slice:
+		n, isNil, err := r.length()
+		if err != nil || isNil {
+			return reflect.Zero(typ), err
+		}
+		res := reflect.MakeSlice(typ, 0, n)
+		for i := 0; i < n; i++ {
+			elem, err := r.value()
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if elem, err = elementOf(typ.Elem(), elem); err != nil {
+				return reflect.Value{}, err
+			}
+			res = reflect.Append(res, elem)
+		}
+		return res, nil
+	default:
+		n, isNil, err := r.length()
+		if err != nil || isNil {
+			return reflect.Zero(typ), err
+		}
+		res := reflect.MakeMapWithSize(typ, n)
+		for i := 0; i < n; i++ {
+			k, err := r.string()
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			elem, err := r.value()
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if elem, err = elementOf(typ.Elem(), elem); err != nil {
+				return reflect.Value{}, err
+			}
+			res.SetMapIndex(reflect.ValueOf(k), elem)
+		}
+		return res, nil
+	}
+}
+
+// elementOf returns `v` as an element of a collection of `typ`, which is
+// its zero value if `v` is nil.
+func elementOf(typ reflect.Type, v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Zero(typ), nil
+	}
+	if !v.Type().AssignableTo(typ) {
+		return reflect.Value{}, fmt.Errorf("cannot use %s as an element of %s in GoPrimitive encoding", v.Type(), typ)
+	}
+	res := reflect.New(typ).Elem()
+	res.Set(v)
+	return res, nil
+}
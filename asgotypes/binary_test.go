@@ -0,0 +1,144 @@
+package asgotypes
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGoPrimitiveBinary(t *testing.T) {
+	precise, _, _ := big.ParseFloat("123456789012345678901234567890.125", 10, 256, big.ToNearestEven)
+	objType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"name":  tftypes.String,
+			"size":  tftypes.Number,
+			"tags":  tftypes.Set{ElementType: tftypes.String},
+			"rules": tftypes.List{ElementType: tftypes.Map{ElementType: tftypes.Bool}},
+			"pair":  tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.DynamicPseudoType}},
+			"empty": tftypes.List{ElementType: tftypes.String},
+		},
+		OptionalAttributes: map[string]struct{}{"tags": {}},
+	}
+	val := tftypes.NewValue(objType, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "web"),
+		"size": tftypes.NewValue(tftypes.Number, precise),
+		"tags": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+			tftypes.NewValue(tftypes.String, nil),
+		}),
+		"rules": tftypes.NewValue(tftypes.List{ElementType: tftypes.Map{ElementType: tftypes.Bool}}, []tftypes.Value{
+			tftypes.NewValue(tftypes.Map{ElementType: tftypes.Bool}, map[string]tftypes.Value{
+				"allow": tftypes.NewValue(tftypes.Bool, true),
+			}),
+		}),
+		"pair": tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.DynamicPseudoType}}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "x"),
+			tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+		}),
+		"empty": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{}),
+	})
+
+	type testCase struct {
+		val  tftypes.Value
+		mode CollectionMode
+	}
+	cases := map[string]testCase{
+		"typed":   {val: val, mode: CollectionsTyped},
+		"lenient": {val: val, mode: CollectionsLenient},
+		"null":    {val: tftypes.NewValue(objType, nil)},
+		"number":  {val: tftypes.NewValue(tftypes.Number, precise)},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			expected := GoPrimitive{Collections: tc.mode}
+			if err := expected.FromTerraform5Value(tc.val); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			b, err := expected.MarshalBinary()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var got GoPrimitive
+			if err := got.UnmarshalBinary(b); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			// Numbers must keep their precision exactly, not just compare
+			// equal.
+			if diff := cmp.Diff(expected, got, cmp.Comparer(func(a, b *big.Float) bool {
+				return a.Cmp(b) == 0 && a.Prec() == b.Prec()
+			})); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGoPrimitiveGob(t *testing.T) {
+	expected := GoPrimitive{
+		Value: map[string][]string{"a": {"b", "c"}},
+		Type:  tftypes.Map{ElementType: tftypes.List{ElementType: tftypes.String}},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(expected); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got GoPrimitive
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestGoPrimitiveBinaryErrors(t *testing.T) {
+	type testCase struct {
+		primitive   GoPrimitive
+		data        []byte
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"unsupported-go-type": {
+			primitive:   GoPrimitive{Value: map[string]interface{}{"a": 1}},
+			expectedErr: `ElementKeyString("a"): cannot encode int in a GoPrimitive`,
+		},
+		"version": {
+			data:        []byte{2},
+			expectedErr: "unsupported GoPrimitive encoding version 2",
+		},
+		"truncated": {
+			data:        []byte{binaryVersion, 0, tagTFString, tagString, 5, 'a'},
+			expectedErr: "truncated GoPrimitive encoding",
+		},
+		"trailing": {
+			data:        []byte{binaryVersion, 0, tagNil, tagNil, tagNil},
+			expectedErr: "1 unexpected bytes after GoPrimitive",
+		},
+		"mismatched-element": {
+			data:        []byte{binaryVersion, 0, tagNil, tagSlice, tagString, 2, tagBool, 1},
+			expectedErr: "cannot use bool as an element of string in GoPrimitive encoding",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var err error
+			if tc.data != nil {
+				var got GoPrimitive
+				err = got.UnmarshalBinary(tc.data)
+			} else {
+				_, err = tc.primitive.MarshalBinary()
+			}
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -12,6 +12,13 @@ func numberComparer() cmp.Option {
 	})
 }
 
+func intComparer() cmp.Option {
+	return cmp.Comparer(func(i, j *big.Int) bool {
+		return (i == nil && j == nil) || (i != nil && j != nil && i.Cmp(j) == 0)
+	})
+}
+
 var cmpOpts = []cmp.Option{
 	numberComparer(),
+	intComparer(),
 }
@@ -0,0 +1,73 @@
+package asgotypes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// canceler cancels a context when it's decoded or encoded.
+type canceler struct {
+	cancel context.CancelFunc
+}
+
+func (c *canceler) FromTerraform5Value(tftypes.Value) error {
+	c.cancel()
+	return nil
+}
+
+func (c canceler) ToTerraform5Value() (interface{}, error) {
+	c.cancel()
+	return "x", nil
+}
+
+func TestDecodeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"a": tftypes.String,
+		"b": tftypes.String,
+	}}
+	val := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.String, "a"),
+		"b": tftypes.NewValue(tftypes.String, "b"),
+	})
+	got := struct {
+		A canceler `tfsdk:"a"`
+		B canceler `tfsdk:"b"`
+	}{A: canceler{cancel: cancel}, B: canceler{cancel: cancel}}
+	err := DecodeContext(ctx, val, &got)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error wrapping %q, got %v", context.Canceled, err)
+	}
+	expectedErr := `AttributeName("b"): context canceled`
+	if err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+
+	d := Decoder{MaxDepth: 1}
+	var s string
+	err = d.DecodeContext(ctx, tftypes.NewValue(tftypes.String, "a"), &s)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error wrapping %q, got %v", context.Canceled, err)
+	}
+	if err := d.Decode(tftypes.NewValue(tftypes.String, "a"), &s); err != nil {
+		t.Errorf("expected the Decoder not to keep the context, got %v", err)
+	}
+}
+
+func TestEncodeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	typ := tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.String}}
+	_, err := EncodeContext(ctx, typ, []canceler{{cancel: cancel}, {cancel: cancel}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error wrapping %q, got %v", context.Canceled, err)
+	}
+	expectedErr := "ElementKeyInt(1): context canceled"
+	if err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
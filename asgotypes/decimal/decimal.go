@@ -0,0 +1,205 @@
+// Package decimal provides a decimal number type that tftypes.Number values
+// can be decoded into and encoded from without binary rounding, for
+// providers working with money, quotas, and other quantities where
+// floating point errors are unacceptable.
+//
+// Decimals implement tftypes.ValueConverter and tftypes.ValueCreator, so
+// the asgotypes Decoder and Encoder use them like any other field:
+//
+//	type Budget struct {
+//		Amount decimal.Decimal  `tfsdk:"amount"`
+//		Limit  *decimal.Decimal `tfsdk:"limit"`
+//	}
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// precision is the precision Decimals are converted to big.Floats with,
+// matching that Terraform parses numbers with.
+const precision = 512
+
+var ten = big.NewInt(10)
+
+// Decimal is an arbitrary-precision decimal number: an integer coefficient
+// multiplied by a power of ten. The zero value is 0.
+type Decimal struct {
+	coef *big.Int
+	exp  int32
+}
+
+// New returns the Decimal `coef` × 10^`exp`, so New(12345, -2) is 123.45.
+func New(coef int64, exp int32) Decimal {
+	return Decimal{coef: big.NewInt(coef), exp: exp}
+}
+
+// Parse parses a number written in decimal, optionally with a sign and an
+// exponent, like "-123.45" or "1.5e6".
+func Parse(s string) (Decimal, error) {
+	mant, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mant = s[:i]
+		exp, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+	sign := ""
+	if mant != "" && (mant[0] == '-' || mant[0] == '+') {
+		sign, mant = mant[:1], mant[1:]
+	}
+	whole, frac, _ := strings.Cut(mant, ".")
+	digits := whole + frac
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	coef, _ := new(big.Int).SetString(sign+digits, 10)
+	exp -= int64(len(frac))
+	if exp < -1<<31 || exp > 1<<31-1 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q, its exponent is out of range", s)
+	}
+	return Decimal{coef: coef, exp: int32(exp)}, nil
+}
+
+// MustParse is like Parse, but panics if `s` isn't a valid decimal. It
+// should only be used with constants.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// FromBigFloat returns the shortest Decimal that converts back to `f` at
+// the precision of `f`, so numbers written in decimal in configurations
+// come back exactly as they were written.
+func FromBigFloat(f *big.Float) (Decimal, error) {
+	if f.IsInf() {
+		return Decimal{}, errors.New("cannot convert infinity to a decimal")
+	}
+	return Parse(f.Text('g', -1))
+}
+
+// BigFloat returns `d` as a big.Float with the precision Terraform uses for
+// numbers.
+func (d Decimal) BigFloat() *big.Float {
+	f, _, err := big.ParseFloat(d.String(), 10, precision, big.ToNearestEven)
+	if err != nil {
+		// String always returns a valid number
+		panic(err)
+	}
+	return f
+}
+
+// String returns `d` written in decimal, without an exponent.
+func (d Decimal) String() string {
+	coef := d.coefficient()
+	if d.exp >= 0 {
+		return new(big.Int).Mul(coef, pow10(d.exp)).String()
+	}
+	digits := new(big.Int).Abs(coef).String()
+	scale := int(-d.exp)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	s := digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	if coef.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// Sign returns -1, 0, or 1, depending on the sign of `d`.
+func (d Decimal) Sign() int {
+	return d.coefficient().Sign()
+}
+
+// Cmp returns -1 if `d` is less than `o`, 0 if they're equal, and 1 if `d`
+// is greater than `o`. Decimals written with different numbers of trailing
+// zeros, like 1.5 and 1.50, are equal.
+func (d Decimal) Cmp(o Decimal) int {
+	a, b, _ := align(d, o)
+	return a.Cmp(b)
+}
+
+// Add returns `d` + `o`.
+func (d Decimal) Add(o Decimal) Decimal {
+	a, b, exp := align(d, o)
+	return Decimal{coef: a.Add(a, b), exp: exp}
+}
+
+// Sub returns `d` - `o`.
+func (d Decimal) Sub(o Decimal) Decimal {
+	a, b, exp := align(d, o)
+	return Decimal{coef: a.Sub(a, b), exp: exp}
+}
+
+// Mul returns `d` × `o`.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.coefficient(), o.coefficient()), exp: d.exp + o.exp}
+}
+
+// FromTerraform5Value decodes a tftypes.Number into `d`. Null numbers are
+// decoded as 0, so use a *Decimal to tell them apart.
+func (d *Decimal) FromTerraform5Value(val tftypes.Value) error {
+	if !val.Type().Is(tftypes.Number) {
+		return fmt.Errorf("cannot decode %s into a decimal", val.Type())
+	}
+	if !val.IsKnown() {
+		return errors.New("cannot decode unknown value into a decimal")
+	}
+	if val.IsNull() {
+		*d = Decimal{}
+		return nil
+	}
+	f := new(big.Float)
+	if err := val.As(&f); err != nil {
+		return err
+	}
+	res, err := FromBigFloat(f)
+	if err != nil {
+		return err
+	}
+	*d = res
+	return nil
+}
+
+// ToTerraform5Value returns `d` as a big.Float, for encoding it as a
+// tftypes.Number.
+func (d Decimal) ToTerraform5Value() (interface{}, error) {
+	return d.BigFloat(), nil
+}
+
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// align returns new copies of the coefficients of `a` and `b` scaled to the
+// same exponent, and the exponent.
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	ac, bc := new(big.Int).Set(a.coefficient()), new(big.Int).Set(b.coefficient())
+	switch {
+	case a.exp > b.exp:
+		ac.Mul(ac, pow10(a.exp-b.exp))
+		return ac, bc, b.exp
+	case b.exp > a.exp:
+		bc.Mul(bc, pow10(b.exp-a.exp))
+	}
+	return ac, bc, a.exp
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(ten, big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,150 @@
+package decimal
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		in          string
+		expected    string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"integer":        {in: "42", expected: "42"},
+		"negative":       {in: "-123.45", expected: "-123.45"},
+		"plus":           {in: "+0.5", expected: "0.5"},
+		"leading-point":  {in: ".05", expected: "0.05"},
+		"trailing-zeros": {in: "1.50", expected: "1.50"},
+		"exponent":       {in: "1.5e6", expected: "1500000"},
+		"negative-exp":   {in: "-25E-4", expected: "-0.0025"},
+		"empty":          {in: "", expectedErr: `invalid decimal ""`},
+		"point":          {in: ".", expectedErr: `invalid decimal "."`},
+		"letters":        {in: "1x", expectedErr: `invalid decimal "1x"`},
+		"bad-exponent":   {in: "1e", expectedErr: `invalid decimal "1e"`},
+		"double-sign":    {in: "--1", expectedErr: `invalid decimal "--1"`},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tc.in)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.String() != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := MustParse("0.1"), MustParse("0.2")
+	if got := a.Add(b); got.Cmp(MustParse("0.3")) != 0 {
+		t.Errorf("expected 0.3, got %s", got)
+	}
+	if got := a.Sub(b).String(); got != "-0.1" {
+		t.Errorf("expected -0.1, got %s", got)
+	}
+	if got := New(125, -2).Mul(New(4, 0)).String(); got != "5.00" {
+		t.Errorf("expected 5.00, got %s", got)
+	}
+	if MustParse("1.5").Cmp(MustParse("1.50")) != 0 || MustParse("2").Cmp(MustParse("10")) != -1 {
+		t.Errorf("unexpected comparison")
+	}
+	var zero Decimal
+	if zero.String() != "0" || zero.Sign() != 0 || zero.Add(New(1, 3)).String() != "1000" {
+		t.Errorf("unexpected zero value %s", zero)
+	}
+	if a.String() != "0.1" {
+		t.Errorf("operations modified their operands")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	type budget struct {
+		Amount Decimal  `tfsdk:"amount"`
+		Limit  *Decimal `tfsdk:"limit"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"amount": tftypes.Number,
+		"limit":  tftypes.Number,
+	}}
+	amount, _, err := big.ParseFloat("1234567890123456789.01", 10, 512, big.ToNearestEven)
+	if err != nil {
+		t.Fatal(err)
+	}
+	val := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"amount": tftypes.NewValue(tftypes.Number, amount),
+		"limit":  tftypes.NewValue(tftypes.Number, nil),
+	})
+	dv, err := tfprotov5.NewDynamicValue(typ, val)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	val, err = dv.Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var got budget
+	if err := asgotypes.Decode(val, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.Amount.String() != "1234567890123456789.01" || got.Limit != nil {
+		t.Errorf("unexpected result %s, %v", got.Amount, got.Limit)
+	}
+
+	limit := MustParse("0.1")
+	got.Limit = &limit
+	encoded, err := asgotypes.Encode(typ, got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var back budget
+	if err := asgotypes.Decode(encoded, &back); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"1234567890123456789.01", "0.1"}, []string{back.Amount.String(), back.Limit.String()}); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	type testCase struct {
+		val         tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"string": {
+			val:         tftypes.NewValue(tftypes.String, "1"),
+			expectedErr: "cannot decode tftypes.String into a decimal",
+		},
+		"unknown": {
+			val:         tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+			expectedErr: "cannot decode unknown value into a decimal",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var d Decimal
+			err := asgotypes.Decode(tc.val, &d)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,797 @@
+package asgotypes
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	valueType          = reflect.TypeOf(tftypes.Value{})
+	valueConverterType = reflect.TypeOf((*tftypes.ValueConverter)(nil)).Elem()
+	bigFloatType       = reflect.TypeOf(big.Float{})
+	bigIntType         = reflect.TypeOf(big.Int{})
+	stringType         = reflect.TypeOf("")
+	boolType           = reflect.TypeOf(false)
+)
+
+// The tftypes.Types that values' types are checked against with Is, as
+// interface values, so the checks don't allocate.
+var (
+	tfString  tftypes.Type = tftypes.String
+	tfNumber  tftypes.Type = tftypes.Number
+	tfBool    tftypes.Type = tftypes.Bool
+	tfDynamic tftypes.Type = tftypes.DynamicPseudoType
+	tfObject  tftypes.Type = tftypes.Object{}
+	tfMap     tftypes.Type = tftypes.Map{}
+	tfList    tftypes.Type = tftypes.List{}
+	tfSet     tftypes.Type = tftypes.Set{}
+	tfTuple   tftypes.Type = tftypes.Tuple{}
+)
+
+// Decoder converts tftypes.Values into Go values, using reflection to
+// populate structs, slices, arrays, maps, pointers, and primitive Go types.
+//
+// Objects are decoded into structs using the `tfsdk` struct tag to map
+// attribute names to fields:
+//
+//	type Server struct {
+//		ID   string  `tfsdk:"id"`
+//		Name *string `tfsdk:"name"`
+//		Internal string `tfsdk:"-"`
+//	}
+//
+// Fields without a tfsdk tag, or with a tag of "-", are ignored, as are
+// attributes of the object that have no corresponding field, unless the
+// struct has a field tagged ",remain" to collect them into, which must be a
+// map[string]tftypes.Value or a GoPrimitive:
+//
+//	type Server struct {
+//		ID    string                   `tfsdk:"id"`
+//		Extra map[string]tftypes.Value `tfsdk:",remain"`
+//	}
+//
+// Setting ErrorUnused makes such attributes an error instead.
+//
+// Fields with the "required" tag option make decoding an error if their
+// attribute is null or missing, so values that must be set don't need
+// checking for nil or zero values after decoding:
+//
+//	type Server struct {
+//		ID string `tfsdk:"id,required"`
+//	}
+//
+// Sets can be decoded into Sets, or other maps with struct{} values, so their
+// elements don't appear to have an order. Lists, sets, and tuples can be
+// decoded into slices, or into arrays of the
+// same length, which keep the fixed arity of tuples:
+//
+//	var pair [2]interface{}
+//	err := asgotypes.Decode(val, &pair)
+//
+// Numbers can be decoded into Go's integer and floating point types, so
+// long as they fit, and into big.Float, or big.Int if they're integers,
+// without losing precision. Numbers decoded into floats are rounded to the
+// nearest float, unless ExactFloats is set, and infinite numbers are
+// decoded as infinite floats.
+//
+// Null values are decoded as the zero value of their target, so a pointer,
+// slice, or map target is the only way to distinguish null from an empty or
+// zero value. Targets of type tftypes.Value receive the value unaltered, and
+// targets implementing tftypes.ValueConverter are handed the value to decode
+// themselves. Targets of type interface{} are populated as if they were a
+// GoPrimitive.
+//
+// Any error returned by the Decoder that relates to a specific part of the
+// value will be a tftypes.AttributePathError, identifying the path the
+// error occurred at.
+type Decoder struct {
+	// AllowUnknown controls how unknown values are decoded into targets
+	// that can't represent them. If false, an error is returned. If true,
+	// the target is left as its zero value.
+	AllowUnknown bool
+
+	// NonFinite controls how values written by an Encoder with the same
+	// policy are decoded into floats, so they decode as NaN or infinity.
+	NonFinite NonFinitePolicy
+
+	// ExactFloats makes decoding a number into a float32 or float64 that
+	// can't hold it exactly an error. By default, it's rounded to the
+	// nearest float.
+	ExactFloats bool
+
+	// Collections controls the Go types lists, sets, and maps are decoded
+	// as when their target is an interface{}.
+	Collections CollectionMode
+
+	// NullCollectionsAsEmpty decodes null lists, sets, and maps into empty
+	// slices and maps, rather than nil ones, for APIs that don't accept
+	// nil. Pointers to slices and maps are still nil.
+	NullCollectionsAsEmpty bool
+
+	// EmptyStringsAsNull decodes empty strings into pointers as nil, like
+	// null strings, for APIs that don't tell them apart. It can be set
+	// for a single field with the "emptyasnull" tag option:
+	//
+	//	Description *string `tfsdk:"description,emptyasnull"`
+	EmptyStringsAsNull bool
+
+	// ErrorUnused makes decoding an object into a struct an error if the
+	// object has attributes that no field maps to, rather than ignoring
+	// them, to catch structs that have drifted from their schema. The
+	// error joins a tftypes.AttributePathError for each of the object's
+	// unused attributes with errors.Join, and is returned before any of
+	// its attributes are decoded. Structs with a ",remain" field use every
+	// attribute.
+	ErrorUnused bool
+
+	// MaxDepth, MaxElements, and MaxStringLength limit how deeply values
+	// can be nested, how many elements lists, sets, tuples, and maps can
+	// have, and how many bytes strings can have, for decoding values from
+	// untrusted sources. The whole value is checked before any of it is
+	// decoded, including parts decoded into tftypes.Values or
+	// interface{}s, and errors for values that exceed a limit wrap a
+	// *LimitError. Zero means no limit.
+	MaxDepth        int
+	MaxElements     int
+	MaxStringLength int
+
+	// Parallelism is the number of goroutines used to decode the elements
+	// of lists, sets, tuples, and maps with at least ParallelThreshold
+	// elements, which defaults to 1000. The goroutines are shared by every
+	// collection in the value, so nested collections don't multiply them.
+	// Elements are decoded in order if it's less than 2. Either way, if
+	// more than one element can't be decoded, the error for the first of
+	// them is returned, ordering the elements of maps by their keys.
+	Parallelism       int
+	ParallelThreshold int
+
+	// ctx is the context of a call to DecodeContext.
+	ctx context.Context
+
+	// workers holds a token for each goroutine decoding elements alongside
+	// the one that called Decode, limiting them to Parallelism-1.
+	workers chan struct{}
+
+	// paths are the steps of the paths passed to DecodePaths, or nil to
+	// decode everything.
+	paths [][]tftypes.AttributePathStep
+}
+
+// Decode decodes `val` into `target`, which must be a non-nil pointer,
+// using a Decoder with the default settings.
+func Decode(val tftypes.Value, target interface{}) error {
+	d := decoderPool.Get().(*Decoder)
+	defer releaseDecoder(d)
+	return d.Decode(val, target)
+}
+
+// DecodeContext decodes `val` into `target`, which must be a non-nil
+// pointer, using a Decoder with the default settings, stopping early if
+// `ctx` is canceled.
+func DecodeContext(ctx context.Context, val tftypes.Value, target interface{}) error {
+	d := decoderPool.Get().(*Decoder)
+	defer releaseDecoder(d)
+	d.ctx = ctx
+	return d.Decode(val, target)
+}
+
+// DecodeContext decodes `val` into `target` like Decode, but stops early if
+// `ctx` is canceled or its deadline passes, so that decoding large values
+// can be interrupted, such as by StopProvider. The error returned then wraps
+// the context's error, and `target` may be partly populated.
+func (d *Decoder) DecodeContext(ctx context.Context, val tftypes.Value, target interface{}) error {
+	dc := copyDecoder(d)
+	defer releaseDecoder(dc)
+	dc.ctx = ctx
+	return dc.Decode(val, target)
+}
+
+// Decode decodes `val` into `target`, which must be a non-nil pointer.
+func (d *Decoder) Decode(val tftypes.Value, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("decode target must be a non-nil pointer")
+	}
+	if d.hasLimits() {
+		if err := d.checkLimits(val); err != nil {
+			return err
+		}
+	}
+	if d.Parallelism >= 2 && d.workers == nil {
+		dw := copyDecoder(d)
+		defer releaseDecoder(dw)
+		dw.workers = make(chan struct{}, d.Parallelism-1)
+		d = dw
+	}
+	return d.decode(tftypes.NewAttributePath(), val, rv.Elem())
+}
+
+func (d *Decoder) decode(path *tftypes.AttributePath, val tftypes.Value, target reflect.Value) error {
+	if d.ctx != nil {
+		if err := d.ctx.Err(); err != nil {
+			return path.NewError(err)
+		}
+	}
+	if target.Type() == valueType {
+		target.Set(reflect.ValueOf(val))
+		return nil
+	}
+	if target.CanAddr() && target.Addr().Type().Implements(valueConverterType) {
+		err := target.Addr().Interface().(tftypes.ValueConverter).FromTerraform5Value(val)
+		if err != nil {
+			return prefixError(path, err)
+		}
+		return nil
+	}
+	if !val.IsKnown() {
+		if d.AllowUnknown {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		return path.NewErrorf("cannot decode unknown value into %s", target.Type())
+	}
+	if target.Kind() == reflect.Ptr {
+		if val.IsNull() || (d.EmptyStringsAsNull && isEmptyString(val)) {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return d.decode(path, val, target.Elem())
+	}
+	if val.IsNull() {
+		if d.NonFinite == NonFiniteNull && isFloat(target.Kind()) && val.Type().Is(tfNumber) {
+			target.SetFloat(math.NaN())
+			return nil
+		}
+		if d.NullCollectionsAsEmpty && isCollection(val.Type()) {
+			switch target.Kind() {
+			case reflect.Slice:
+				target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+				return nil
+			case reflect.Map:
+				target.Set(reflect.MakeMap(target.Type()))
+				return nil
+			}
+		}
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if target.CanAddr() && target.Addr().Type().Implements(orderedMapTargetType) {
+		om := target.Addr().Interface().(orderedMapTarget)
+		m := reflect.New(om.goMap().Type()).Elem()
+		if err := d.decodeMap(path, val, m); err != nil {
+			return err
+		}
+		om.setGoMap(m)
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		if target.NumMethod() != 0 {
+			return path.NewErrorf("cannot decode into non-empty interface type %s", target.Type())
+		}
+		if v, ok, err := primitiveValue(val); ok {
+			if err != nil {
+				return path.NewError(err)
+			}
+			target.Set(reflect.ValueOf(v))
+			return nil
+		}
+		gp := GoPrimitive{Collections: d.Collections}
+		err := gp.FromTerraform5Value(val)
+		if err != nil {
+			return prefixError(path, err)
+		}
+		if gp.Value != nil {
+			target.Set(reflect.ValueOf(gp.Value))
+		}
+		return nil
+	case reflect.String:
+		if !val.Type().Is(tfString) {
+			return path.NewErrorf("cannot decode %s into %s", val.Type(), target.Type())
+		}
+		if target.Type() == stringType && target.CanAddr() {
+			if err := val.As(target.Addr().Interface()); err != nil {
+				return path.NewError(err)
+			}
+			return nil
+		}
+		var s string
+		if err := val.As(&s); err != nil {
+			return path.NewError(err)
+		}
+		target.SetString(s)
+		return nil
+	case reflect.Bool:
+		if !val.Type().Is(tfBool) {
+			return path.NewErrorf("cannot decode %s into %s", val.Type(), target.Type())
+		}
+		if target.Type() == boolType && target.CanAddr() {
+			if err := val.As(target.Addr().Interface()); err != nil {
+				return path.NewError(err)
+			}
+			return nil
+		}
+		var b bool
+		if err := val.As(&b); err != nil {
+			return path.NewError(err)
+		}
+		target.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		if d.NonFinite == NonFiniteString && isFloat(target.Kind()) && val.Type().Is(tfString) {
+			var s string
+			if err := val.As(&s); err != nil {
+				return path.NewError(err)
+			}
+			f, ok := nonFiniteStrings[s]
+			if !ok {
+				return path.NewErrorf("cannot decode %q into %s, expected NaN, +Inf, or -Inf", s, target.Type())
+			}
+			target.SetFloat(f)
+			return nil
+		}
+		return d.decodeNumber(path, val, target)
+	case reflect.Struct:
+		if target.Type() == bigFloatType {
+			f, err := numberValue(path, val)
+			if err != nil {
+				return err
+			}
+			target.Set(reflect.ValueOf(*f))
+			return nil
+		}
+		if target.Type() == bigIntType {
+			f, err := numberValue(path, val)
+			if err != nil {
+				return err
+			}
+			if !f.IsInt() {
+				return path.NewErrorf("cannot decode %s into %s, it is not an integer", f.Text('g', -1), target.Type())
+			}
+			i, _ := f.Int(nil)
+			target.Set(reflect.ValueOf(*i))
+			return nil
+		}
+		return d.decodeStruct(path, val, target)
+	case reflect.Map:
+		if isSetType(target.Type()) {
+			return d.decodeSet(path, val, target)
+		}
+		return d.decodeMap(path, val, target)
+	case reflect.Slice, reflect.Array:
+		return d.decodeSlice(path, val, target)
+	}
+	return path.NewErrorf("cannot decode %s into unsupported type %s", val.Type(), target.Type())
+}
+
+func numberValue(path *tftypes.AttributePath, val tftypes.Value) (*big.Float, error) {
+	if !val.Type().Is(tfNumber) {
+		return nil, path.NewErrorf("cannot decode %s into a number", val.Type())
+	}
+	f := new(big.Float)
+	if err := val.As(&f); err != nil {
+		return nil, path.NewError(err)
+	}
+	return f, nil
+}
+
+// floatPool holds the big.Floats numbers are decoded into on their way to
+// Go's integer and float types, so decoding them doesn't allocate.
+var floatPool = sync.Pool{New: func() interface{} { return new(big.Float) }}
+
+func (d *Decoder) decodeNumber(path *tftypes.AttributePath, val tftypes.Value, target reflect.Value) error {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := decodeInt(path, val, target.Type())
+		if err != nil {
+			return err
+		}
+		target.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := decodeUint(path, val, target.Type())
+		if err != nil {
+			return err
+		}
+		target.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		fl, err := decodeFloat(path, val, target.Type(), d.ExactFloats)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(fl)
+	}
+	return nil
+}
+
+// pooledNumber returns the number `val` as a big.Float from floatPool,
+// which the caller must put back.
+func pooledNumber(path *tftypes.AttributePath, val tftypes.Value) (*big.Float, error) {
+	if !val.Type().Is(tfNumber) {
+		return nil, path.NewErrorf("cannot decode %s into a number", val.Type())
+	}
+	f := floatPool.Get().(*big.Float)
+	if err := val.As(f); err != nil {
+		floatPool.Put(f)
+		return nil, path.NewError(err)
+	}
+	return f, nil
+}
+
+// decodeInt decodes the number `val` into an int64 that fits in the signed
+// integer type `typ`.
+func decodeInt(path *tftypes.AttributePath, val tftypes.Value, typ reflect.Type) (int64, error) {
+	f, err := pooledNumber(path, val)
+	if err != nil {
+		return 0, err
+	}
+	defer floatPool.Put(f)
+	if !f.IsInt() {
+		return 0, path.NewErrorf("cannot decode %s into %s, it is not an integer", f.Text('g', -1), typ)
+	}
+	i, acc := f.Int64()
+	shift := 64 - uint(typ.Bits())
+	if acc != big.Exact || (i<<shift)>>shift != i {
+		return 0, path.NewErrorf("cannot decode %s into %s, it overflows", f.Text('g', -1), typ)
+	}
+	return i, nil
+}
+
+// decodeUint decodes the number `val` into a uint64 that fits in the
+// unsigned integer type `typ`.
+func decodeUint(path *tftypes.AttributePath, val tftypes.Value, typ reflect.Type) (uint64, error) {
+	f, err := pooledNumber(path, val)
+	if err != nil {
+		return 0, err
+	}
+	defer floatPool.Put(f)
+	if !f.IsInt() {
+		return 0, path.NewErrorf("cannot decode %s into %s, it is not an integer", f.Text('g', -1), typ)
+	}
+	if f.Sign() < 0 {
+		return 0, path.NewErrorf("cannot decode %s into %s, it is negative", f.Text('g', -1), typ)
+	}
+	u, acc := f.Uint64()
+	shift := 64 - uint(typ.Bits())
+	if acc != big.Exact || (u<<shift)>>shift != u {
+		return 0, path.NewErrorf("cannot decode %s into %s, it overflows", f.Text('g', -1), typ)
+	}
+	return u, nil
+}
+
+// decodeFloat decodes the number `val` into a float64 that the float type
+// `typ` can hold, rounding it unless `exact` is set.
+func decodeFloat(path *tftypes.AttributePath, val tftypes.Value, typ reflect.Type, exact bool) (float64, error) {
+	f, err := pooledNumber(path, val)
+	if err != nil {
+		return 0, err
+	}
+	defer floatPool.Put(f)
+	fl, acc := f.Float64()
+	if typ.Kind() == reflect.Float32 {
+		var fl32 float32
+		fl32, acc = f.Float32()
+		fl = float64(fl32)
+	}
+	if !f.IsInf() && math.IsInf(fl, 0) {
+		return 0, path.NewErrorf("cannot decode %s into %s, it overflows", f.Text('g', -1), typ)
+	}
+	if exact && acc != big.Exact {
+		return 0, path.NewErrorf("cannot decode %s into %s exactly", f.Text('g', -1), typ)
+	}
+	return fl, nil
+}
+
+func (d *Decoder) decodeStruct(path *tftypes.AttributePath, val tftypes.Value, target reflect.Value) error {
+	if !val.Type().Is(tfObject) {
+		return path.NewErrorf("cannot decode %s into %s, expected an object", val.Type(), target.Type())
+	}
+	info, err := getStructInfo(target.Type())
+	if err != nil {
+		return path.NewError(err)
+	}
+	var attrs map[string]tftypes.Value
+	if err := val.As(&attrs); err != nil {
+		return path.NewError(err)
+	}
+	if d.ErrorUnused && info.remain == nil {
+		if err := d.unused(path, info, attrs, target.Type()); err != nil {
+			return err
+		}
+	}
+	for _, f := range info.fields {
+		attr, ok := attrs[f.name]
+		if !ok && !f.required {
+			continue
+		}
+		attrPath := path.WithAttributeName(f.name)
+		if d.skip(attrPath) {
+			continue
+		}
+		if !ok {
+			return attrPath.NewErrorf("required attribute is missing")
+		}
+		if f.required && attr.IsNull() {
+			return attrPath.NewErrorf("required attribute is null")
+		}
+		var err error
+		if f.emptyAsNull && !d.EmptyStringsAsNull {
+			fd := copyDecoder(d)
+			fd.EmptyStringsAsNull = true
+			err = fd.decode(attrPath, attr, target.FieldByIndex(f.index))
+			releaseDecoder(fd)
+		} else {
+			err = d.decode(attrPath, attr, target.FieldByIndex(f.index))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if info.remain != nil {
+		return d.decodeRemain(path, info, attrs, target.FieldByIndex(info.remain))
+	}
+	return nil
+}
+
+// unused returns an error for each attribute in `attrs` that no field of
+// the struct type `typ`, described by `info`, maps to, in order of their
+// names.
+func (d *Decoder) unused(path *tftypes.AttributePath, info *structInfo, attrs map[string]tftypes.Value, typ reflect.Type) error {
+	var names []string
+	for name := range attrs {
+		if _, ok := info.byName[name]; !ok && !d.skip(path.WithAttributeName(name)) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	errs := make([]error, 0, len(names))
+	for _, name := range names {
+		errs = append(errs, path.WithAttributeName(name).NewErrorf("unused attribute, %s has no field tagged %q", typ, name))
+	}
+	return errors.Join(errs...)
+}
+
+// decodeRemain decodes the attributes in `attrs` that no field of the
+// struct described by `info` maps to into its remain field, `target`.
+func (d *Decoder) decodeRemain(path *tftypes.AttributePath, info *structInfo, attrs map[string]tftypes.Value, target reflect.Value) error {
+	rest := map[string]tftypes.Value{}
+	for name, attr := range attrs {
+		if _, ok := info.byName[name]; ok || d.skip(path.WithAttributeName(name)) {
+			continue
+		}
+		rest[name] = attr
+	}
+	if target.Type() == remainMapType {
+		target.Set(reflect.ValueOf(rest))
+		return nil
+	}
+	types := make(map[string]tftypes.Type, len(rest))
+	for name, attr := range rest {
+		types[name] = attr.Type()
+	}
+	gp := GoPrimitive{Collections: d.Collections}
+	if err := gp.FromTerraform5Value(tftypes.NewValue(tftypes.Object{AttributeTypes: types}, rest)); err != nil {
+		return prefixError(path, err)
+	}
+	target.Set(reflect.ValueOf(gp))
+	return nil
+}
+
+func (d *Decoder) decodeMap(path *tftypes.AttributePath, val tftypes.Value, target reflect.Value) error {
+	isObject := val.Type().Is(tfObject)
+	if !isObject && !val.Type().Is(tfMap) {
+		return path.NewErrorf("cannot decode %s into %s, expected a map or object", val.Type(), target.Type())
+	}
+	if target.Type().Key().Kind() != reflect.String {
+		return path.NewErrorf("cannot decode into %s, map keys must be strings", target.Type())
+	}
+	var elems map[string]tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return path.NewError(err)
+	}
+	sc := getScratch(len(elems))
+	defer releaseScratch(sc)
+	for k := range elems {
+		sc.keys = append(sc.keys, k)
+	}
+	sort.Strings(sc.keys)
+	keys, decoded := sc.keys, sc.values
+	err := d.each(len(keys), func(i int) error {
+		elemPath := path.WithElementKeyString(keys[i])
+		if isObject {
+			elemPath = path.WithAttributeName(keys[i])
+		}
+		if d.skip(elemPath) {
+			return nil
+		}
+		elem := reflect.New(target.Type().Elem()).Elem()
+		if err := d.decode(elemPath, elems[keys[i]], elem); err != nil {
+			return err
+		}
+		decoded[i] = elem
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	res := reflect.MakeMapWithSize(target.Type(), len(elems))
+	for i, k := range keys {
+		if decoded[i].IsValid() {
+			res.SetMapIndex(reflect.ValueOf(k).Convert(target.Type().Key()), decoded[i])
+		}
+	}
+	target.Set(res)
+	return nil
+}
+
+func (d *Decoder) decodeSlice(path *tftypes.AttributePath, val tftypes.Value, target reflect.Value) error {
+	isSet := val.Type().Is(tfSet)
+	if !isSet && !val.Type().Is(tfList) && !val.Type().Is(tfTuple) {
+		return path.NewErrorf("cannot decode %s into %s, expected a list, set, or tuple", val.Type(), target.Type())
+	}
+	var elems []tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return path.NewError(err)
+	}
+	var res reflect.Value
+	if target.Kind() == reflect.Array {
+		if len(elems) != target.Len() {
+			return path.NewErrorf("cannot decode %d elements into %s", len(elems), target.Type())
+		}
+		res = reflect.New(target.Type()).Elem()
+	} else {
+		res = reflect.MakeSlice(target.Type(), len(elems), len(elems))
+	}
+	err := d.each(len(elems), func(i int) error {
+		elemPath := path.WithElementKeyInt(i)
+		if isSet {
+			elemPath = path.WithElementKeyValue(elems[i])
+		}
+		if d.skip(elemPath) {
+			return nil
+		}
+		return d.decode(elemPath, elems[i], res.Index(i))
+	})
+	if err != nil {
+		return err
+	}
+	target.Set(res)
+	return nil
+}
+
+func (d *Decoder) decodeSet(path *tftypes.AttributePath, val tftypes.Value, target reflect.Value) error {
+	if !val.Type().Is(tfSet) {
+		return path.NewErrorf("cannot decode %s into %s, expected a set", val.Type(), target.Type())
+	}
+	var elems []tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return path.NewError(err)
+	}
+	sc := getScratch(len(elems))
+	defer releaseScratch(sc)
+	decoded := sc.values
+	err := d.each(len(elems), func(i int) error {
+		elemPath := path.WithElementKeyValue(elems[i])
+		if d.skip(elemPath) {
+			return nil
+		}
+		elem := reflect.New(target.Type().Key()).Elem()
+		if err := d.decode(elemPath, elems[i], elem); err != nil {
+			return err
+		}
+		decoded[i] = elem
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	res := reflect.MakeMapWithSize(target.Type(), len(elems))
+	for _, elem := range decoded {
+		if elem.IsValid() {
+			res.SetMapIndex(elem, reflect.ValueOf(struct{}{}))
+		}
+	}
+	target.Set(res)
+	return nil
+}
+
+// isCollection returns true if `typ` is a list, set, or map type.
+func isCollection(typ tftypes.Type) bool {
+	return typ.Is(tfList) || typ.Is(tfSet) || typ.Is(tfMap)
+}
+
+// isEmptyString returns true if `val` is a known, empty string.
+func isEmptyString(val tftypes.Value) bool {
+	if !val.Type().Is(tfString) || !val.IsKnown() || val.IsNull() {
+		return false
+	}
+	var s string
+	return val.As(&s) == nil && s == ""
+}
+
+// defaultParallelThreshold is the default Decoder.ParallelThreshold.
+const defaultParallelThreshold = 1000
+
+// each calls `f` with each index up to `n`, concurrently if the Decoder's
+// Parallelism allows, returning the error for the lowest index that `f`
+// returns one for. No more calls are started once `f` returns an error.
+//
+// The calling goroutine calls `f` itself, joined by as many more as there
+// are free workers, so a collection nested in one being decoded
+// concurrently is decoded by the goroutine decoding its parent if every
+// worker is busy.
+func (d *Decoder) each(n int, f func(i int) error) error {
+	threshold := d.ParallelThreshold
+	if threshold <= 0 {
+		threshold = defaultParallelThreshold
+	}
+	if d.Parallelism < 2 || n < threshold {
+		for i := 0; i < n; i++ {
+			if err := f(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// indexes are handed out in order, so when one fails, every lower
+	// index has already been handed out, and the lowest failure is found
+	// by waiting for them
+	var next atomic.Int64
+	var failed atomic.Bool
+	errs := make([]error, n)
+	work := func() {
+		for !failed.Load() {
+			i := int(next.Add(1) - 1)
+			if i >= n {
+				return
+			}
+			if err := f(i); err != nil {
+				errs[i] = err
+				failed.Store(true)
+			}
+		}
+	}
+	var wg sync.WaitGroup
+spawn:
+	for w := 1; w < d.Parallelism && w < n; w++ {
+		select {
+		case d.workers <- struct{}{}:
+		default:
+			break spawn
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-d.workers
+				wg.Done()
+			}()
+			work()
+		}()
+	}
+	work()
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,911 @@
+package asgotypes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type testServer struct {
+	ID       string            `tfsdk:"id"`
+	Name     *string           `tfsdk:"name"`
+	Port     int               `tfsdk:"port"`
+	Tags     map[string]string `tfsdk:"tags"`
+	Aliases  []string          `tfsdk:"aliases"`
+	Disks    []testDisk        `tfsdk:"disk"`
+	Internal string            `tfsdk:"-"`
+	Ignored  string
+}
+
+type testDisk struct {
+	Size float64 `tfsdk:"size"`
+	Boot bool    `tfsdk:"boot"`
+}
+
+var testDiskType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"size": tftypes.Number,
+	"boot": tftypes.Bool,
+}}
+
+var testServerType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"id":      tftypes.String,
+	"name":    tftypes.String,
+	"port":    tftypes.Number,
+	"tags":    tftypes.Map{ElementType: tftypes.String},
+	"aliases": tftypes.Set{ElementType: tftypes.String},
+	"disk":    tftypes.List{ElementType: testDiskType},
+}}
+
+func testServerValue(port tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(testServerType, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "abc"),
+		"name": tftypes.NewValue(tftypes.String, nil),
+		"port": port,
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"env": tftypes.NewValue(tftypes.String, "prod"),
+		}),
+		"aliases": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "www"),
+		}),
+		"disk": tftypes.NewValue(tftypes.List{ElementType: testDiskType}, []tftypes.Value{
+			tftypes.NewValue(testDiskType, map[string]tftypes.Value{
+				"size": tftypes.NewValue(tftypes.Number, big.NewFloat(10.5)),
+				"boot": tftypes.NewValue(tftypes.Bool, true),
+			}),
+		}),
+	})
+}
+
+func TestDecodeStruct(t *testing.T) {
+	var got testServer
+	err := Decode(testServerValue(tftypes.NewValue(tftypes.Number, big.NewFloat(8080))), &got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := testServer{
+		ID:      "abc",
+		Port:    8080,
+		Tags:    map[string]string{"env": "prod"},
+		Aliases: []string{"www"},
+		Disks:   []testDisk{{Size: 10.5, Boot: true}},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	type testCase struct {
+		val          tftypes.Value
+		expectedPath *tftypes.AttributePath
+		decoder      Decoder
+	}
+	cases := map[string]testCase{
+		"not-an-integer": {
+			val:          testServerValue(tftypes.NewValue(tftypes.Number, big.NewFloat(1.5))),
+			expectedPath: tftypes.NewAttributePath().WithAttributeName("port"),
+		},
+		"unknown": {
+			val:          testServerValue(tftypes.NewValue(tftypes.Number, tftypes.UnknownValue)),
+			expectedPath: tftypes.NewAttributePath().WithAttributeName("port"),
+		},
+		"wrong-type": {
+			val:          tftypes.NewValue(tftypes.String, "hello"),
+			expectedPath: tftypes.NewAttributePath(),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var got testServer
+			err := test.decoder.Decode(test.val, &got)
+			if err == nil {
+				t.Fatal("expected error, got none")
+			}
+			pathErr, ok := err.(tftypes.AttributePathError)
+			if !ok {
+				t.Fatalf("expected tftypes.AttributePathError, got %T: %s", err, err)
+			}
+			if !pathErr.Path.Equal(test.expectedPath) {
+				t.Errorf("expected error at %s, got %s", test.expectedPath, pathErr.Path)
+			}
+		})
+	}
+}
+
+func TestDecodeAllowUnknown(t *testing.T) {
+	d := Decoder{AllowUnknown: true}
+	var got testServer
+	err := d.Decode(testServerValue(tftypes.NewValue(tftypes.Number, tftypes.UnknownValue)), &got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.Port != 0 || got.ID != "abc" {
+		t.Errorf("unexpected result %+v", got)
+	}
+}
+
+func TestDecodeTargets(t *testing.T) {
+	type testCase struct {
+		val      tftypes.Value
+		target   func() interface{}
+		expected interface{}
+	}
+	str := "hello"
+	cases := map[string]testCase{
+		"string-pointer": {
+			val:      tftypes.NewValue(tftypes.String, "hello"),
+			target:   func() interface{} { return new(*string) },
+			expected: &str,
+		},
+		"null-string-pointer": {
+			val:      tftypes.NewValue(tftypes.String, nil),
+			target:   func() interface{} { return new(*string) },
+			expected: (*string)(nil),
+		},
+		"uint8": {
+			val:      tftypes.NewValue(tftypes.Number, big.NewFloat(255)),
+			target:   func() interface{} { return new(uint8) },
+			expected: uint8(255),
+		},
+		"big-float": {
+			val:      tftypes.NewValue(tftypes.Number, big.NewFloat(1.25)),
+			target:   func() interface{} { return new(*big.Float) },
+			expected: big.NewFloat(1.25),
+		},
+		"big-int": {
+			val:      tftypes.NewValue(tftypes.Number, bigNumber("18446744073709551617")),
+			target:   func() interface{} { return new(*big.Int) },
+			expected: bigInt("18446744073709551617"),
+		},
+		"int64": {
+			val:      tftypes.NewValue(tftypes.Number, bigNumber("9223372036854775807")),
+			target:   func() interface{} { return new(int64) },
+			expected: int64(9223372036854775807),
+		},
+		"interface": {
+			val: tftypes.NewValue(tftypes.List{ElementType: tftypes.Bool}, []tftypes.Value{
+				tftypes.NewValue(tftypes.Bool, true),
+			}),
+			target:   func() interface{} { return new(interface{}) },
+			expected: []bool{true},
+		},
+		"value": {
+			val:      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			target:   func() interface{} { return new(tftypes.Value) },
+			expected: tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		},
+		"object-to-map": {
+			val: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"a": tftypes.String,
+			}}, map[string]tftypes.Value{
+				"a": tftypes.NewValue(tftypes.String, "b"),
+			}),
+			target:   func() interface{} { return new(map[string]string) },
+			expected: map[string]string{"a": "b"},
+		},
+		"tuple-to-array": {
+			val: tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Bool}}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "a"),
+				tftypes.NewValue(tftypes.Bool, true),
+			}),
+			target:   func() interface{} { return new([2]interface{}) },
+			expected: [2]interface{}{"a", true},
+		},
+		"list-to-array": {
+			val: tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "a"),
+				tftypes.NewValue(tftypes.String, "b"),
+			}),
+			target:   func() interface{} { return new([2]string) },
+			expected: [2]string{"a", "b"},
+		},
+		"null-array": {
+			val:      tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String}}, nil),
+			target:   func() interface{} { return new([1]string) },
+			expected: [1]string{},
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			target := test.target()
+			if err := Decode(test.val, target); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := reflectElem(target)
+			if diff := cmp.Diff(test.expected, got, cmpOpts...); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeArrayLength(t *testing.T) {
+	val := tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.String}}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "a"),
+		tftypes.NewValue(tftypes.String, "b"),
+	})
+	var got [3]string
+	err := Decode(val, &got)
+	expectedErr := "cannot decode 2 elements into [3]string"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func bigNumber(s string) *big.Float {
+	f, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func bigInt(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid integer " + s)
+	}
+	return i
+}
+
+func TestDecodeBigIntErrors(t *testing.T) {
+	type testCase struct {
+		val         tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"fraction": {
+			val:         tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+			expectedErr: "cannot decode 1.5 into big.Int, it is not an integer",
+		},
+		"string": {
+			val:         tftypes.NewValue(tftypes.String, "1"),
+			expectedErr: "cannot decode tftypes.String into a number",
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var got big.Int
+			err := Decode(test.val, &got)
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("expected error %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestUint64RoundTrip(t *testing.T) {
+	type resource struct {
+		ID uint64 `tfsdk:"id"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"id": tftypes.Number}}
+	for _, id := range []uint64{0, 1<<53 + 1, math.MaxInt64 + 1, math.MaxUint64} {
+		val, err := Encode(typ, resource{ID: id})
+		if err != nil {
+			t.Fatalf("%d: unexpected error encoding: %s", id, err)
+		}
+		dv, err := tfprotov5.NewDynamicValue(typ, val)
+		if err != nil {
+			t.Fatalf("%d: unexpected error marshaling: %s", id, err)
+		}
+		val, err = dv.Unmarshal(typ)
+		if err != nil {
+			t.Fatalf("%d: unexpected error unmarshaling: %s", id, err)
+		}
+		var got resource
+		if err := Decode(val, &got); err != nil {
+			t.Fatalf("%d: unexpected error decoding: %s", id, err)
+		}
+		if got.ID != id {
+			t.Errorf("expected %d, got %d", id, got.ID)
+		}
+	}
+}
+
+func TestUint64Errors(t *testing.T) {
+	type testCase struct {
+		val         tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"overflow": {
+			val:         tftypes.NewValue(tftypes.Number, bigNumber("18446744073709551616")),
+			expectedErr: "cannot decode 1.8446744073709551616e+19 into uint64, it overflows",
+		},
+		"negative": {
+			val:         tftypes.NewValue(tftypes.Number, big.NewFloat(-1)),
+			expectedErr: "cannot decode -1 into uint64, it is negative",
+		},
+		"fraction": {
+			val:         tftypes.NewValue(tftypes.Number, big.NewFloat(0.5)),
+			expectedErr: "cannot decode 0.5 into uint64, it is not an integer",
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var got uint64
+			err := Decode(test.val, &got)
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("expected error %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDecodeNullCollectionsAsEmpty(t *testing.T) {
+	type resource struct {
+		Tags     map[string]string `tfsdk:"tags"`
+		Aliases  []string          `tfsdk:"aliases"`
+		Ports    Set[int]          `tfsdk:"ports"`
+		Optional *[]string         `tfsdk:"optional"`
+		Name     string            `tfsdk:"name"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"tags":     tftypes.Map{ElementType: tftypes.String},
+		"aliases":  tftypes.List{ElementType: tftypes.String},
+		"ports":    tftypes.Set{ElementType: tftypes.Number},
+		"optional": tftypes.List{ElementType: tftypes.String},
+		"name":     tftypes.String,
+	}}
+	val := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"tags":     tftypes.NewValue(typ.AttributeTypes["tags"], nil),
+		"aliases":  tftypes.NewValue(typ.AttributeTypes["aliases"], nil),
+		"ports":    tftypes.NewValue(typ.AttributeTypes["ports"], nil),
+		"optional": tftypes.NewValue(typ.AttributeTypes["optional"], nil),
+		"name":     tftypes.NewValue(tftypes.String, nil),
+	})
+	d := Decoder{NullCollectionsAsEmpty: true}
+	var got resource
+	if err := d.Decode(val, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := resource{
+		Tags:    map[string]string{},
+		Aliases: []string{},
+		Ports:   Set[int]{},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if got.Tags == nil || got.Aliases == nil || got.Ports == nil {
+		t.Errorf("expected empty collections, got %#v", got)
+	}
+}
+
+func TestEmptyStringsAsNull(t *testing.T) {
+	type resource struct {
+		Name        *string `tfsdk:"name"`
+		Description *string `tfsdk:"description,emptyasnull"`
+		Notes       string  `tfsdk:"notes"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":        tftypes.String,
+		"description": tftypes.String,
+		"notes":       tftypes.String,
+	}}
+	empty := tftypes.NewValue(tftypes.String, "")
+	null := tftypes.NewValue(tftypes.String, nil)
+	val := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"name":        empty,
+		"description": empty,
+		"notes":       empty,
+	})
+	str := ""
+
+	type testCase struct {
+		emptyAsNull bool
+		decoded     resource
+		encoded     tftypes.Value
+	}
+	cases := map[string]testCase{
+		"tag": {
+			decoded: resource{Name: &str},
+			encoded: tftypes.NewValue(typ, map[string]tftypes.Value{
+				"name":        empty,
+				"description": null,
+				"notes":       empty,
+			}),
+		},
+		"all": {
+			emptyAsNull: true,
+			decoded:     resource{},
+			encoded: tftypes.NewValue(typ, map[string]tftypes.Value{
+				"name":        null,
+				"description": null,
+				"notes":       null,
+			}),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			d := Decoder{EmptyStringsAsNull: test.emptyAsNull}
+			var got resource
+			if err := d.Decode(val, &got); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.decoded, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+
+			e := Encoder{EmptyStringsAsNull: test.emptyAsNull}
+			encoded, err := e.Encode(typ, resource{Name: &str, Description: &str})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.encoded, encoded); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRemain(t *testing.T) {
+	type valueResource struct {
+		ID   string                   `tfsdk:"id"`
+		Rest map[string]tftypes.Value `tfsdk:",remain"`
+	}
+	type primitiveResource struct {
+		ID   string      `tfsdk:"id"`
+		Rest GoPrimitive `tfsdk:",remain"`
+	}
+	restType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"size":  tftypes.Number,
+		"names": tftypes.List{ElementType: tftypes.String},
+	}}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":    tftypes.String,
+		"size":  tftypes.Number,
+		"names": tftypes.List{ElementType: tftypes.String},
+	}}
+	rest := map[string]tftypes.Value{
+		"size": tftypes.NewValue(tftypes.Number, big.NewFloat(3)),
+		"names": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+		}),
+	}
+	val := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id":    tftypes.NewValue(tftypes.String, "abc"),
+		"size":  rest["size"],
+		"names": rest["names"],
+	})
+
+	type testCase struct {
+		target   interface{}
+		expected interface{}
+	}
+	cases := map[string]testCase{
+		"values": {
+			target:   &valueResource{},
+			expected: &valueResource{ID: "abc", Rest: rest},
+		},
+		"primitive": {
+			target: &primitiveResource{},
+			expected: &primitiveResource{ID: "abc", Rest: GoPrimitive{
+				Value: map[string]interface{}{"size": big.NewFloat(3), "names": []string{"a"}},
+				Type:  restType,
+			}},
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if err := Decode(val, test.target); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, test.target, cmp.Comparer(func(a, b *big.Float) bool {
+				return a.Cmp(b) == 0
+			})); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+
+			encoded, err := Encode(typ, test.target)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(val, encoded); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRemainErrors(t *testing.T) {
+	type twoRemain struct {
+		A map[string]tftypes.Value `tfsdk:",remain"`
+		B map[string]tftypes.Value `tfsdk:",remain"`
+	}
+	type wrongType struct {
+		Rest map[string]string `tfsdk:",remain"`
+	}
+	type valueResource struct {
+		ID   string                   `tfsdk:"id"`
+		Rest map[string]tftypes.Value `tfsdk:",remain"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id": tftypes.String,
+	}}
+	val := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "abc"),
+	})
+
+	type testCase struct {
+		decode      interface{}
+		encode      interface{}
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"two-remain": {
+			decode:      &twoRemain{},
+			expectedErr: "asgotypes.twoRemain has more than one remain field",
+		},
+		"wrong-type": {
+			decode:      &wrongType{},
+			expectedErr: "remain field Rest of asgotypes.wrongType must be a map[string]tftypes.Value or GoPrimitive, not map[string]string",
+		},
+		"unknown-attribute": {
+			encode: valueResource{ID: "abc", Rest: map[string]tftypes.Value{
+				"size": tftypes.NewValue(tftypes.Number, big.NewFloat(3)),
+			}},
+			expectedErr: `"size" is not an attribute of the object`,
+		},
+		"field-attribute": {
+			encode: valueResource{ID: "abc", Rest: map[string]tftypes.Value{
+				"id": tftypes.NewValue(tftypes.String, "def"),
+			}},
+			expectedErr: `"id" is not an attribute of the object`,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var err error
+			if test.decode != nil {
+				err = Decode(val, test.decode)
+			} else {
+				_, err = Encode(typ, test.encode)
+			}
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("expected error %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDecodeErrorUnused(t *testing.T) {
+	type disk struct {
+		Size float64 `tfsdk:"size"`
+	}
+	type server struct {
+		ID    string `tfsdk:"id"`
+		Disks []disk `tfsdk:"disk"`
+	}
+	type remainServer struct {
+		ID   string                   `tfsdk:"id"`
+		Rest map[string]tftypes.Value `tfsdk:",remain"`
+	}
+	val := testServerValue(tftypes.NewValue(tftypes.Number, big.NewFloat(8080)))
+
+	type testCase struct {
+		target      interface{}
+		paths       []*tftypes.AttributePath
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"unused": {
+			target: &server{},
+			expectedErr: `AttributeName("aliases"): unused attribute, asgotypes.server has no field tagged "aliases"
+AttributeName("name"): unused attribute, asgotypes.server has no field tagged "name"
+AttributeName("port"): unused attribute, asgotypes.server has no field tagged "port"
+AttributeName("tags"): unused attribute, asgotypes.server has no field tagged "tags"`,
+		},
+		"nested": {
+			target: &struct {
+				ID    string            `tfsdk:"id"`
+				Name  *string           `tfsdk:"name"`
+				Port  int               `tfsdk:"port"`
+				Tags  map[string]string `tfsdk:"tags"`
+				Alias []string          `tfsdk:"aliases"`
+				Disks []disk            `tfsdk:"disk"`
+			}{},
+			expectedErr: `AttributeName("disk").ElementKeyInt(0).AttributeName("boot"): unused attribute, asgotypes.disk has no field tagged "boot"`,
+		},
+		"paths": {
+			target: &server{},
+			paths:  []*tftypes.AttributePath{tftypes.NewAttributePath().WithAttributeName("id")},
+		},
+		"remain": {
+			target: &remainServer{},
+		},
+		"all-used": {
+			target: &testServer{},
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			d := Decoder{ErrorUnused: true}
+			var err error
+			if test.paths != nil {
+				err = d.DecodePaths(val, test.target, test.paths...)
+			} else {
+				err = d.Decode(val, test.target)
+			}
+			if test.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expectedErr {
+				t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+			}
+			var pathErr tftypes.AttributePathError
+			if !errors.As(err, &pathErr) {
+				t.Errorf("expected a tftypes.AttributePathError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestDecodeRequired(t *testing.T) {
+	type disk struct {
+		Size float64 `tfsdk:"size,required"`
+	}
+	type server struct {
+		ID    string `tfsdk:"id,required"`
+		Name  string `tfsdk:"name,required"`
+		Disks []disk `tfsdk:"disk"`
+	}
+	diskType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"size": tftypes.Number,
+	}}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":   tftypes.String,
+		"name": tftypes.String,
+		"disk": tftypes.List{ElementType: diskType},
+	}}
+	value := func(name, size tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(typ, map[string]tftypes.Value{
+			"id":   tftypes.NewValue(tftypes.String, "abc"),
+			"name": name,
+			"disk": tftypes.NewValue(tftypes.List{ElementType: diskType}, []tftypes.Value{
+				tftypes.NewValue(diskType, map[string]tftypes.Value{"size": size}),
+			}),
+		})
+	}
+	ten := tftypes.NewValue(tftypes.Number, big.NewFloat(10))
+
+	type testCase struct {
+		val         tftypes.Value
+		paths       []*tftypes.AttributePath
+		expected    server
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"set": {
+			val:      value(tftypes.NewValue(tftypes.String, "web"), ten),
+			expected: server{ID: "abc", Name: "web", Disks: []disk{{Size: 10}}},
+		},
+		"empty": {
+			val:      value(tftypes.NewValue(tftypes.String, ""), ten),
+			expected: server{ID: "abc", Disks: []disk{{Size: 10}}},
+		},
+		"null": {
+			val:         value(tftypes.NewValue(tftypes.String, nil), ten),
+			expectedErr: `AttributeName("name"): required attribute is null`,
+		},
+		"nested-null": {
+			val:         value(tftypes.NewValue(tftypes.String, "web"), tftypes.NewValue(tftypes.Number, nil)),
+			expectedErr: `AttributeName("disk").ElementKeyInt(0).AttributeName("size"): required attribute is null`,
+		},
+		"missing": {
+			val: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"id": tftypes.String,
+			}}, map[string]tftypes.Value{
+				"id": tftypes.NewValue(tftypes.String, "abc"),
+			}),
+			expectedErr: `AttributeName("name"): required attribute is missing`,
+		},
+		"skipped": {
+			val:      value(tftypes.NewValue(tftypes.String, nil), ten),
+			paths:    []*tftypes.AttributePath{tftypes.NewAttributePath().WithAttributeName("id")},
+			expected: server{ID: "abc"},
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var d Decoder
+			var got server
+			var err error
+			if test.paths != nil {
+				err = d.DecodePaths(test.val, &got, test.paths...)
+			} else {
+				err = d.Decode(test.val, &got)
+			}
+			if test.expectedErr != "" {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+				}
+				if _, ok := err.(tftypes.AttributePathError); !ok {
+					t.Errorf("expected tftypes.AttributePathError, got %T", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestUnknownTagOption(t *testing.T) {
+	type server struct {
+		Name string `tfsdk:"name,requird"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name": tftypes.String,
+	}}
+	expectedErr := `field Name of asgotypes.server has unknown tfsdk tag option "requird"`
+
+	var got server
+	err := Decode(tftypes.NewValue(typ, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "web"),
+	}), &got)
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+	_, err = Encode(typ, server{Name: "web"})
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestDecodeParallel(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	var elems []tftypes.Value
+	attrs := map[string]tftypes.Value{}
+	var expectedList []string
+	expectedMap := map[string]string{}
+	expectedSet := map[string]struct{}{}
+	for i := 0; i < 100; i++ {
+		s := fmt.Sprintf("%03d", i)
+		elems = append(elems, str(s))
+		attrs[s] = str(s)
+		expectedList = append(expectedList, s)
+		expectedMap[s] = s
+		expectedSet[s] = struct{}{}
+	}
+	d := Decoder{Parallelism: 4, ParallelThreshold: 10}
+
+	var list []string
+	if err := d.Decode(tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elems), &list); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expectedList, list); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	var m map[string]string
+	if err := d.Decode(tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, attrs), &m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expectedMap, m); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	var set map[string]struct{}
+	if err := d.Decode(tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elems), &set); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expectedSet, set); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestDecodeParallelErrors(t *testing.T) {
+	var elems []tftypes.Value
+	attrs := map[string]tftypes.Value{}
+	for i := 0; i < 100; i++ {
+		val := tftypes.NewValue(tftypes.Number, i)
+		if i%10 == 7 {
+			val = tftypes.NewValue(tftypes.Number, tftypes.UnknownValue)
+		}
+		elems = append(elems, val)
+		attrs[fmt.Sprintf("%03d", i)] = val
+	}
+	d := Decoder{Parallelism: 8, ParallelThreshold: 10}
+
+	// the error is always for the first element that can't be decoded
+	for i := 0; i < 20; i++ {
+		var list []int
+		err := d.Decode(tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, elems), &list)
+		expectedErr := "ElementKeyInt(7): cannot decode unknown value into int"
+		if err == nil || err.Error() != expectedErr {
+			t.Fatalf("expected error %q, got %v", expectedErr, err)
+		}
+
+		var m map[string]int
+		err = d.Decode(tftypes.NewValue(tftypes.Map{ElementType: tftypes.Number}, attrs), &m)
+		expectedErr = `ElementKeyString("007"): cannot decode unknown value into int`
+		if err == nil || err.Error() != expectedErr {
+			t.Fatalf("expected error %q, got %v", expectedErr, err)
+		}
+	}
+}
+
+// busyElem is decoded slowly, counting how many are being decoded at once.
+type busyElem struct{}
+
+var busyCount, busyMax atomic.Int64
+
+func (*busyElem) FromTerraform5Value(tftypes.Value) error {
+	n := busyCount.Add(1)
+	defer busyCount.Add(-1)
+	for {
+		max := busyMax.Load()
+		if n <= max || busyMax.CompareAndSwap(max, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func TestDecodeParallelNested(t *testing.T) {
+	typ := tftypes.List{ElementType: tftypes.List{ElementType: tftypes.String}}
+	var outer []tftypes.Value
+	for i := 0; i < 10; i++ {
+		var inner []tftypes.Value
+		for j := 0; j < 10; j++ {
+			inner = append(inner, tftypes.NewValue(tftypes.String, "a"))
+		}
+		outer = append(outer, tftypes.NewValue(typ.ElementType, inner))
+	}
+	d := Decoder{Parallelism: 3, ParallelThreshold: 2}
+
+	var target [][]busyElem
+	if err := d.Decode(tftypes.NewValue(typ, outer), &target); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(target) != 10 || len(target[9]) != 10 {
+		t.Errorf("expected 10 lists of 10 elements, got %v", target)
+	}
+	if max := busyMax.Load(); max > 3 {
+		t.Errorf("expected at most 3 elements to be decoded at once, got %d", max)
+	}
+}
+
+func TestDecodeNumberPrecision(t *testing.T) {
+	// the big.Floats numbers are decoded into are reused, and mustn't keep
+	// the precision of the numbers decoded before
+	for i := 0; i < 10; i++ {
+		var f float64
+		if err := Decode(tftypes.NewValue(tftypes.Number, 0.5), &f); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var n int64
+		if err := Decode(tftypes.NewValue(tftypes.Number, bigNumber("4611686018427387905")), &n); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if n != 4611686018427387905 {
+			t.Fatalf("expected 4611686018427387905, got %d", n)
+		}
+	}
+}
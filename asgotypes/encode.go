@@ -0,0 +1,410 @@
+package asgotypes
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	valueCreatorType = reflect.TypeOf((*tftypes.ValueCreator)(nil)).Elem()
+	goPrimitiveType  = reflect.TypeOf(GoPrimitive{})
+	lazyValueType    = reflect.TypeOf((*LazyValue)(nil))
+)
+
+// Encoder converts Go values into tftypes.Values of a given type. It is the
+// inverse of Decoder, and uses the same `tfsdk` struct tags to map struct
+// fields to object attributes.
+//
+// Because Go's type system can't express everything Terraform's can, the
+// type of the resulting value must always be supplied. Nil pointers, slices,
+// maps, and interfaces are encoded as null values of the appropriate type,
+// and object attributes without a corresponding struct field are set to
+// null, unless the struct's ",remain" field has a value for them. A struct
+// field whose tag names an attribute the object type doesn't have is an
+// error, as is a remain field with such an attribute, or one that another
+// field maps to.
+//
+// Values of type tftypes.Value are used as-is, so long as their type
+// matches, except for the zero tftypes.Value, which is encoded as null.
+// Values implementing tftypes.ValueCreator are asked for their builtin
+// representation, and LazyValues are encoded as the value they wrap.
+type Encoder struct {
+	// NonFinite controls how NaN and infinite floats are encoded. By
+	// default, they're an error.
+	NonFinite NonFinitePolicy
+
+	// EmptyCollectionsAsNull encodes empty slices and maps as null lists,
+	// sets, and maps, for APIs that return empty collections for attributes
+	// that aren't set.
+	EmptyCollectionsAsNull bool
+
+	// EmptyStringsAsNull encodes empty strings as null. It can be set for
+	// a single field with the "emptyasnull" tag option, like
+	// Decoder.EmptyStringsAsNull.
+	EmptyStringsAsNull bool
+
+	// ctx is the context of a call to EncodeContext.
+	ctx context.Context
+}
+
+// Encode encodes `src` as a tftypes.Value of type `typ` using an Encoder
+// with the default settings.
+func Encode(typ tftypes.Type, src interface{}) (tftypes.Value, error) {
+	var e Encoder
+	return e.Encode(typ, src)
+}
+
+// EncodeContext encodes `src` as a tftypes.Value of type `typ` using an
+// Encoder with the default settings, stopping early if `ctx` is canceled.
+func EncodeContext(ctx context.Context, typ tftypes.Type, src interface{}) (tftypes.Value, error) {
+	var e Encoder
+	return e.EncodeContext(ctx, typ, src)
+}
+
+// EncodeContext encodes `src` as a tftypes.Value of type `typ` like Encode,
+// but stops early if `ctx` is canceled or its deadline passes. The error
+// returned then wraps the context's error.
+func (e *Encoder) EncodeContext(ctx context.Context, typ tftypes.Type, src interface{}) (tftypes.Value, error) {
+	ec := *e
+	ec.ctx = ctx
+	return ec.Encode(typ, src)
+}
+
+// Encode encodes `src` as a tftypes.Value of type `typ`.
+func (e *Encoder) Encode(typ tftypes.Type, src interface{}) (tftypes.Value, error) {
+	if typ == nil {
+		return tftypes.Value{}, errors.New("cannot encode without a type")
+	}
+	return e.encode(tftypes.NewAttributePath(), typ, reflect.ValueOf(src))
+}
+
+func (e *Encoder) encode(path *tftypes.AttributePath, typ tftypes.Type, src reflect.Value) (tftypes.Value, error) {
+	if e.ctx != nil {
+		if err := e.ctx.Err(); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+	}
+	if !src.IsValid() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if src.Type() == valueType {
+		val := src.Interface().(tftypes.Value)
+		if val.Type() == nil {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		if !typ.Is(tfDynamic) && !val.Type().UsableAs(typ) {
+			return tftypes.Value{}, path.NewErrorf("cannot use value of type %s as %s", val.Type(), typ)
+		}
+		return val, nil
+	}
+	if src.Type() == lazyValueType {
+		if src.IsNil() {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		return e.encode(path, typ, reflect.ValueOf(src.Interface().(*LazyValue).Value()))
+	}
+	if src.Type() == goPrimitiveType {
+		return e.encode(path, typ, reflect.ValueOf(src.Interface().(GoPrimitive).Value))
+	}
+	if src.Type().Implements(valueCreatorType) {
+		if src.Kind() == reflect.Ptr && src.IsNil() {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		raw, err := src.Interface().(tftypes.ValueCreator).ToTerraform5Value()
+		if err != nil {
+			return tftypes.Value{}, prefixError(path, err)
+		}
+		if err := tftypes.ValidateValue(typ, raw); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(typ, raw), nil
+	}
+	if src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		return e.encode(path, typ, src.Elem())
+	}
+
+	if src.Type().Implements(orderedMapType) && (typ.Is(tfMap) || typ.Is(tfObject)) {
+		src = src.Interface().(orderedMap).goMap()
+	}
+
+	if val, ok := encodeNonFinite(e.NonFinite, typ, src); ok {
+		return val, nil
+	}
+
+	if typ.Is(tfDynamic) {
+		inferred, ok := inferPrimitiveType(src)
+		if !ok {
+			return tftypes.Value{}, path.NewErrorf("cannot infer a type for %s, a concrete type is required", src.Type())
+		}
+		typ = inferred
+	}
+
+	if e.EmptyCollectionsAsNull && isCollection(typ) && (src.Kind() == reflect.Slice || src.Kind() == reflect.Map) && src.Len() == 0 {
+		return tftypes.NewValue(typ, nil), nil
+	}
+
+	switch {
+	case typ.Is(tfString):
+		if src.Kind() != reflect.String {
+			return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", src.Type(), typ)
+		}
+		if e.EmptyStringsAsNull && src.Len() == 0 {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		return tftypes.NewValue(typ, src.String()), nil
+	case typ.Is(tfBool):
+		if src.Kind() != reflect.Bool {
+			return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", src.Type(), typ)
+		}
+		return tftypes.NewValue(typ, src.Bool()), nil
+	case typ.Is(tfNumber):
+		f, err := e.encodeNumber(path, src)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(typ, f), nil
+	case typ.Is(tfList):
+		return e.encodeElements(path, typ, typ.(tftypes.List).ElementType, src)
+	case typ.Is(tfSet):
+		return e.encodeElements(path, typ, typ.(tftypes.Set).ElementType, src)
+	case typ.Is(tfTuple):
+		return e.encodeTuple(path, typ.(tftypes.Tuple), src)
+	case typ.Is(tfMap):
+		return e.encodeMap(path, typ.(tftypes.Map), src)
+	case typ.Is(tfObject):
+		return e.encodeObject(path, typ.(tftypes.Object), src)
+	}
+	return tftypes.Value{}, path.NewErrorf("cannot encode %s as unsupported type %s", src.Type(), typ)
+}
+
+func inferPrimitiveType(src reflect.Value) (tftypes.Type, bool) {
+	switch src.Kind() {
+	case reflect.String:
+		return tftypes.String, true
+	case reflect.Bool:
+		return tftypes.Bool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return tftypes.Number, true
+	case reflect.Struct:
+		if src.Type() == bigFloatType || src.Type() == bigIntType {
+			return tftypes.Number, true
+		}
+	}
+	return nil, false
+}
+
+func (e *Encoder) encodeNumber(path *tftypes.AttributePath, src reflect.Value) (*big.Float, error) {
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(src.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(src.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := src.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, path.NewErrorf("cannot encode %v as a number", f)
+		}
+		return big.NewFloat(f), nil
+	case reflect.Struct:
+		if src.Type() == bigFloatType {
+			f := src.Interface().(big.Float)
+			return new(big.Float).Copy(&f), nil
+		}
+		if src.Type() == bigIntType {
+			i := src.Interface().(big.Int)
+			return new(big.Float).SetInt(&i), nil
+		}
+	}
+	return nil, path.NewErrorf("cannot encode %s as %s", src.Type(), tftypes.Number)
+}
+
+func (e *Encoder) encodeElements(path *tftypes.AttributePath, typ, elemType tftypes.Type, src reflect.Value) (tftypes.Value, error) {
+	if typ.Is(tfSet) && isSetType(src.Type()) {
+		return e.encodeSet(path, typ, elemType, src)
+	}
+	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+		return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", src.Type(), typ)
+	}
+	if src.Kind() == reflect.Slice && src.IsNil() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	isSet := typ.Is(tfSet)
+	elems := make([]tftypes.Value, 0, src.Len())
+	for i := 0; i < src.Len(); i++ {
+		elemPath := path.WithElementKeyInt(i)
+		if isSet {
+			// set elements are addressed by value, which we don't have
+			// yet, so errors are reported against the set itself
+			elemPath = path
+		}
+		elem, err := e.encode(elemPath, elemType, src.Index(i))
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, elem)
+	}
+	return newValue(path, typ, elems)
+}
+
+func (e *Encoder) encodeSet(path *tftypes.AttributePath, typ, elemType tftypes.Type, src reflect.Value) (tftypes.Value, error) {
+	if src.IsNil() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	elems := make([]tftypes.Value, 0, src.Len())
+	for _, k := range setKeys(src) {
+		// see encodeElements
+		elem, err := e.encode(path, elemType, k)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, elem)
+	}
+	return newValue(path, typ, elems)
+}
+
+func (e *Encoder) encodeTuple(path *tftypes.AttributePath, typ tftypes.Tuple, src reflect.Value) (tftypes.Value, error) {
+	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+		return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", src.Type(), typ)
+	}
+	if src.Kind() == reflect.Slice && src.IsNil() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if src.Len() != len(typ.ElementTypes) {
+		return tftypes.Value{}, path.NewErrorf("cannot encode %d elements as a tuple with %d elements", src.Len(), len(typ.ElementTypes))
+	}
+	elems := make([]tftypes.Value, 0, src.Len())
+	for i, elemType := range typ.ElementTypes {
+		elem, err := e.encode(path.WithElementKeyInt(i), elemType, src.Index(i))
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, elem)
+	}
+	return newValue(path, typ, elems)
+}
+
+func (e *Encoder) encodeMap(path *tftypes.AttributePath, typ tftypes.Map, src reflect.Value) (tftypes.Value, error) {
+	if src.Kind() != reflect.Map || src.Type().Key().Kind() != reflect.String {
+		return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", src.Type(), typ)
+	}
+	if src.IsNil() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	elems := make(map[string]tftypes.Value, src.Len())
+	iter := src.MapRange()
+	for iter.Next() {
+		k := iter.Key().String()
+		elem, err := e.encode(path.WithElementKeyString(k), typ.ElementType, iter.Value())
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems[k] = elem
+	}
+	return newValue(path, typ, elems)
+}
+
+func (e *Encoder) encodeObject(path *tftypes.AttributePath, typ tftypes.Object, src reflect.Value) (tftypes.Value, error) {
+	attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	switch {
+	case src.Kind() == reflect.Struct:
+		info, err := getStructInfo(src.Type())
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		for _, f := range info.fields {
+			attrType, ok := typ.AttributeTypes[f.name]
+			if !ok {
+				return tftypes.Value{}, path.NewErrorf("%s has a field tagged %q, which is not an attribute of the object", src.Type(), f.name)
+			}
+			fe := e
+			if f.emptyAsNull && !e.EmptyStringsAsNull {
+				fe = &Encoder{}
+				*fe = *e
+				fe.EmptyStringsAsNull = true
+			}
+			attr, err := fe.encode(path.WithAttributeName(f.name), attrType, src.FieldByIndex(f.index))
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[f.name] = attr
+		}
+		if info.remain != nil {
+			if err := e.encodeRemain(path, typ, info, src.FieldByIndex(info.remain), attrs); err != nil {
+				return tftypes.Value{}, err
+			}
+		}
+	case src.Kind() == reflect.Map && src.Type().Key().Kind() == reflect.String:
+		if src.IsNil() {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			attrType, ok := typ.AttributeTypes[k]
+			if !ok {
+				return tftypes.Value{}, path.NewErrorf("%q is not an attribute of the object", k)
+			}
+			attr, err := e.encode(path.WithAttributeName(k), attrType, iter.Value())
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[k] = attr
+		}
+	default:
+		return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", src.Type(), typ)
+	}
+	for k, attrType := range typ.AttributeTypes {
+		if _, ok := attrs[k]; !ok {
+			attrs[k] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	return newValue(path, typ, attrs)
+}
+
+// encodeRemain encodes the remain field `src` of the struct described by
+// `info` as the attributes of `typ` that none of its other fields map to,
+// adding them to `attrs`.
+func (e *Encoder) encodeRemain(path *tftypes.AttributePath, typ tftypes.Object, info *structInfo, src reflect.Value, attrs map[string]tftypes.Value) error {
+	types := map[string]tftypes.Type{}
+	for name, attrType := range typ.AttributeTypes {
+		if _, ok := info.byName[name]; !ok {
+			types[name] = attrType
+		}
+	}
+	rest, err := e.encode(path, tftypes.Object{AttributeTypes: types}, src)
+	if err != nil {
+		return err
+	}
+	if rest.IsNull() {
+		return nil
+	}
+	var restAttrs map[string]tftypes.Value
+	if err := rest.As(&restAttrs); err != nil {
+		return path.NewError(err)
+	}
+	for name, attr := range restAttrs {
+		attrs[name] = attr
+	}
+	return nil
+}
+
+// newValue returns a new value of the collection or structural type `typ`
+// at `path`, returning an error instead of panicking if `val` isn't valid
+// for `typ`, as when a Go value of mixed types is encoded as a list of
+// tftypes.DynamicPseudoType.
+func newValue(path *tftypes.AttributePath, typ tftypes.Type, val interface{}) (tftypes.Value, error) {
+	if err := tftypes.ValidateValue(typ, val); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	return tftypes.NewValue(typ, val), nil
+}
@@ -0,0 +1,223 @@
+package asgotypes
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func reflectElem(v interface{}) interface{} {
+	return reflect.ValueOf(v).Elem().Interface()
+}
+
+func TestEncodeStructRoundTrip(t *testing.T) {
+	expected := testServerValue(tftypes.NewValue(tftypes.Number, big.NewFloat(8080)))
+	var decoded testServer
+	if err := Decode(expected, &decoded); err != nil {
+		t.Fatalf("unexpected error decoding: %s", err)
+	}
+	got, err := Encode(testServerType, decoded)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %s", err)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	type testCase struct {
+		typ         tftypes.Type
+		src         interface{}
+		expected    tftypes.Value
+		expectedErr bool
+	}
+	cases := map[string]testCase{
+		"zero-value": {
+			typ:      tftypes.String,
+			src:      tftypes.Value{},
+			expected: tftypes.NewValue(tftypes.String, nil),
+		},
+		"nil-pointer": {
+			typ:      tftypes.String,
+			src:      (*string)(nil),
+			expected: tftypes.NewValue(tftypes.String, nil),
+		},
+		"nil-slice": {
+			typ:      tftypes.List{ElementType: tftypes.String},
+			src:      []string(nil),
+			expected: tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		},
+		"empty-slice": {
+			typ:      tftypes.List{ElementType: tftypes.String},
+			src:      []string{},
+			expected: tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{}),
+		},
+		"tuple": {
+			typ: tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Number}},
+			src: []interface{}{"a", 1},
+			expected: tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Number}}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "a"),
+				tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+			}),
+		},
+		"array-to-tuple": {
+			typ: tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Bool}},
+			src: [2]interface{}{"a", true},
+			expected: tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Bool}}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "a"),
+				tftypes.NewValue(tftypes.Bool, true),
+			}),
+		},
+		"array-wrong-length": {
+			typ:         tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.String}},
+			src:         [3]string{"a", "b", "c"},
+			expectedErr: true,
+		},
+		"big-int": {
+			typ:      tftypes.Number,
+			src:      bigInt("18446744073709551617"),
+			expected: tftypes.NewValue(tftypes.Number, bigNumber("18446744073709551617")),
+		},
+		"dynamic-big-int": {
+			typ:      tftypes.DynamicPseudoType,
+			src:      *big.NewInt(-5),
+			expected: tftypes.NewValue(tftypes.Number, big.NewFloat(-5)),
+		},
+		"go-primitive": {
+			typ: tftypes.Map{ElementType: tftypes.Bool},
+			src: GoPrimitive{Value: map[string]bool{"a": true}},
+			expected: tftypes.NewValue(tftypes.Map{ElementType: tftypes.Bool}, map[string]tftypes.Value{
+				"a": tftypes.NewValue(tftypes.Bool, true),
+			}),
+		},
+		"dynamic": {
+			typ:      tftypes.DynamicPseudoType,
+			src:      "hello",
+			expected: tftypes.NewValue(tftypes.String, "hello"),
+		},
+		"value-passthrough": {
+			typ:      tftypes.String,
+			src:      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			expected: tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		},
+		"value-wrong-type": {
+			typ:         tftypes.Number,
+			src:         tftypes.NewValue(tftypes.String, "a"),
+			expectedErr: true,
+		},
+		"nan": {
+			typ:         tftypes.Number,
+			src:         math.NaN(),
+			expectedErr: true,
+		},
+		"wrong-kind": {
+			typ:         tftypes.Bool,
+			src:         "true",
+			expectedErr: true,
+		},
+		"mixed-dynamic-list": {
+			typ:         tftypes.List{ElementType: tftypes.DynamicPseudoType},
+			src:         []interface{}{"a", 1},
+			expectedErr: true,
+		},
+		"mixed-dynamic-map": {
+			typ:         tftypes.Map{ElementType: tftypes.DynamicPseudoType},
+			src:         map[string]interface{}{"a": "a", "b": true},
+			expectedErr: true,
+		},
+		"unknown-field": {
+			typ:         tftypes.Object{AttributeTypes: map[string]tftypes.Type{"size": tftypes.Number}},
+			src:         testDisk{},
+			expectedErr: true,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			got, err := Encode(test.typ, test.src)
+			if err != nil {
+				if !test.expectedErr {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if test.expectedErr {
+				t.Fatalf("expected error, got %s", got)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeMixedDynamicListPath(t *testing.T) {
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"tags": tftypes.List{ElementType: tftypes.DynamicPseudoType},
+	}}
+	_, err := Encode(typ, map[string]interface{}{"tags": []interface{}{"a", 1}})
+	var pathErr tftypes.AttributePathError
+	if !errors.As(err, &pathErr) {
+		t.Fatalf("expected an AttributePathError, got %v", err)
+	}
+	if expected := tftypes.NewAttributePath().WithAttributeName("tags"); !pathErr.Path.Equal(expected) {
+		t.Errorf("expected error at %s, got %s", expected, pathErr.Path)
+	}
+}
+
+func TestEncodeEmptyCollectionsAsNull(t *testing.T) {
+	type testCase struct {
+		typ      tftypes.Type
+		src      interface{}
+		expected tftypes.Value
+	}
+	listType := tftypes.List{ElementType: tftypes.String}
+	mapType := tftypes.Map{ElementType: tftypes.String}
+	setType := tftypes.Set{ElementType: tftypes.String}
+	cases := map[string]testCase{
+		"list": {
+			typ:      listType,
+			src:      []string{},
+			expected: tftypes.NewValue(listType, nil),
+		},
+		"map": {
+			typ:      mapType,
+			src:      map[string]string{},
+			expected: tftypes.NewValue(mapType, nil),
+		},
+		"set": {
+			typ:      setType,
+			src:      Set[string]{},
+			expected: tftypes.NewValue(setType, nil),
+		},
+		"non-empty": {
+			typ:      listType,
+			src:      []string{"a"},
+			expected: tftypes.NewValue(listType, []tftypes.Value{tftypes.NewValue(tftypes.String, "a")}),
+		},
+		"object": {
+			typ:      tftypes.Object{AttributeTypes: map[string]tftypes.Type{}},
+			src:      map[string]string{},
+			expected: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{}}, map[string]tftypes.Value{}),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			e := Encoder{EmptyCollectionsAsNull: true}
+			got, err := e.Encode(test.typ, test.src)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
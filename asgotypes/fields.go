@@ -0,0 +1,90 @@
+package asgotypes
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/structtag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// field describes a struct field that maps to an object attribute.
+type field struct {
+	name  string
+	index []int
+
+	// emptyAsNull is set by the "emptyasnull" tag option.
+	emptyAsNull bool
+
+	// required is set by the "required" tag option.
+	required bool
+}
+
+// structInfo describes how a struct type maps to an object.
+type structInfo struct {
+	fields []field
+	byName map[string]int
+
+	// remain is the index of the field tagged with the "remain" option,
+	// which holds the attributes that no other field maps to, or nil.
+	remain []int
+}
+
+var structInfoCache sync.Map // map[reflect.Type]*structInfo
+
+var remainMapType = reflect.TypeOf(map[string]tftypes.Value{})
+
+// getStructInfo returns the attribute mapping for the struct type `t`. Only
+// fields with a tfsdk tag take part in the mapping; a tag of "-" explicitly
+// excludes a field. Options follow the name, separated by commas. A tag of
+// ",remain" marks the field that holds the attributes no other field maps
+// to, which must be a map[string]tftypes.Value or a GoPrimitive. Other
+// options are "emptyasnull" and "required"; any other option is an error,
+// so misspelled options aren't silently ignored.
+func getStructInfo(t reflect.Type) (*structInfo, error) {
+	if cached, ok := structInfoCache.Load(t); ok {
+		return cached.(*structInfo), nil
+	}
+	info := &structInfo{
+		byName: map[string]int{},
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok, err := structtag.Lookup(t, f)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if tag.Remain {
+			if info.remain != nil {
+				return nil, fmt.Errorf("%s has more than one remain field", t)
+			}
+			if f.PkgPath != "" {
+				return nil, fmt.Errorf("field %s of %s is unexported but has a %s tag", f.Name, t, structtag.Key)
+			}
+			if f.Type != remainMapType && f.Type != goPrimitiveType {
+				return nil, fmt.Errorf("remain field %s of %s must be a map[string]tftypes.Value or GoPrimitive, not %s", f.Name, t, f.Type)
+			}
+			info.remain = f.Index
+			continue
+		}
+		if f.PkgPath != "" {
+			return nil, fmt.Errorf("field %s of %s is unexported but has a %s tag", f.Name, t, structtag.Key)
+		}
+		if _, ok := info.byName[tag.Name]; ok {
+			return nil, fmt.Errorf("%s has more than one field tagged %q", t, tag.Name)
+		}
+		info.byName[tag.Name] = len(info.fields)
+		info.fields = append(info.fields, field{
+			name:        tag.Name,
+			index:       f.Index,
+			emptyAsNull: tag.EmptyAsNull,
+			required:    tag.Required,
+		})
+	}
+	structInfoCache.Store(t, info)
+	return info, nil
+}
@@ -0,0 +1,87 @@
+package asgotypes_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tftest"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type fuzzServer struct {
+	ID      string             `tfsdk:"id"`
+	Name    *string            `tfsdk:"name"`
+	Port    *int64             `tfsdk:"port"`
+	Weight  *big.Float         `tfsdk:"weight"`
+	Enabled *bool              `tfsdk:"enabled"`
+	Tags    map[string]*string `tfsdk:"tags"`
+	Aliases []string           `tfsdk:"aliases"`
+	Disks   []fuzzDisk         `tfsdk:"disk"`
+	Extra   tftypes.Value      `tfsdk:"extra"`
+}
+
+type fuzzDisk struct {
+	Size  *int64  `tfsdk:"size"`
+	Label *string `tfsdk:"label"`
+}
+
+var fuzzServerType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"id":      tftypes.String,
+	"name":    tftypes.String,
+	"port":    tftypes.Number,
+	"weight":  tftypes.Number,
+	"enabled": tftypes.Bool,
+	"tags":    tftypes.Map{ElementType: tftypes.String},
+	"aliases": tftypes.Set{ElementType: tftypes.String},
+	"disk": tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"size":  tftypes.Number,
+		"label": tftypes.String,
+	}}},
+	"extra": tftypes.DynamicPseudoType,
+}}
+
+// FuzzDecode checks that decoding state never panics, and that anything
+// decoded can be encoded, and that decoding and encoding the result again
+// doesn't change it.
+func FuzzDecode(f *testing.F) {
+	tftest.FuzzDynamicValue(f, fuzzServerType, func(t *testing.T, val tftypes.Value) {
+		dec := asgotypes.Decoder{AllowUnknown: true}
+		var s fuzzServer
+		if err := dec.Decode(val, &s); err != nil {
+			return
+		}
+		if !val.IsFullyKnown() {
+			return
+		}
+		encoded, err := asgotypes.Encode(fuzzServerType, s)
+		if err != nil {
+			t.Fatalf("error encoding decoded value %s: %s", val, err)
+		}
+		var again fuzzServer
+		if err := asgotypes.Decode(encoded, &again); err != nil {
+			t.Fatalf("error decoding encoded value %s: %s", encoded, err)
+		}
+		reencoded, err := asgotypes.Encode(fuzzServerType, again)
+		if err != nil {
+			t.Fatalf("error encoding %s again: %s", encoded, err)
+		}
+		if !reencoded.Equal(encoded) {
+			t.Errorf("expected %s, got %s", encoded, reencoded)
+		}
+	})
+}
+
+// FuzzGoPrimitive checks that GoPrimitive never panics, whatever it's asked
+// to decode.
+func FuzzGoPrimitive(f *testing.F) {
+	typ := tftypes.Tuple{ElementTypes: []tftypes.Type{
+		fuzzServerType,
+		tftypes.List{ElementType: tftypes.Map{ElementType: tftypes.Number}},
+		tftypes.Set{ElementType: tftypes.Bool},
+	}}
+	tftest.FuzzValue(f, typ, func(t *testing.T, val tftypes.Value) {
+		var gp asgotypes.GoPrimitive
+		_ = gp.FromTerraform5Value(val)
+	})
+}
@@ -0,0 +1,493 @@
+package asgotypes
+
+import (
+	"cmp"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The functions in this file are used by the FromTerraform5Value and
+// ToTerraform5Value methods that cmd/asgotypes-gen generates for tagged
+// structs. Each of them converts values the way a Decoder or Encoder with
+// the default settings would, without walking Go values with reflection.
+
+// DecodeFunc decodes `val` into a T, like a Decoder with the default
+// settings would. Its errors are tftypes.AttributePathErrors, relative to
+// `path`.
+type DecodeFunc[T any] func(path *tftypes.AttributePath, val tftypes.Value) (T, error)
+
+// EncodeFunc encodes `src` as a tftypes.Value of type `typ`, like an
+// Encoder with the default settings would. Its errors are
+// tftypes.AttributePathErrors, relative to `path`.
+type EncodeFunc[T any] func(path *tftypes.AttributePath, typ tftypes.Type, src T) (tftypes.Value, error)
+
+// typeOf returns the Go type T, for error messages.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func unknownError[T any](path *tftypes.AttributePath) error {
+	return path.NewErrorf("cannot decode unknown value into %s", typeOf[T]())
+}
+
+// DecodeString decodes a string.
+func DecodeString[T ~string](path *tftypes.AttributePath, val tftypes.Value) (T, error) {
+	switch {
+	case !val.IsKnown():
+		return "", unknownError[T](path)
+	case val.IsNull():
+		return "", nil
+	case !val.Type().Is(tfString):
+		return "", path.NewErrorf("cannot decode %s into %s", val.Type(), typeOf[T]())
+	}
+	var s string
+	if err := val.As(&s); err != nil {
+		return "", path.NewError(err)
+	}
+	return T(s), nil
+}
+
+// DecodeBool decodes a bool.
+func DecodeBool[T ~bool](path *tftypes.AttributePath, val tftypes.Value) (T, error) {
+	switch {
+	case !val.IsKnown():
+		return false, unknownError[T](path)
+	case val.IsNull():
+		return false, nil
+	case !val.Type().Is(tfBool):
+		return false, path.NewErrorf("cannot decode %s into %s", val.Type(), typeOf[T]())
+	}
+	var b bool
+	if err := val.As(&b); err != nil {
+		return false, path.NewError(err)
+	}
+	return T(b), nil
+}
+
+// DecodeInt decodes a number into a signed integer, if it fits.
+func DecodeInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](path *tftypes.AttributePath, val tftypes.Value) (T, error) {
+	switch {
+	case !val.IsKnown():
+		return 0, unknownError[T](path)
+	case val.IsNull():
+		return 0, nil
+	}
+	i, err := decodeInt(path, val, typeOf[T]())
+	return T(i), err
+}
+
+// DecodeUint decodes a number into an unsigned integer, if it fits.
+func DecodeUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](path *tftypes.AttributePath, val tftypes.Value) (T, error) {
+	switch {
+	case !val.IsKnown():
+		return 0, unknownError[T](path)
+	case val.IsNull():
+		return 0, nil
+	}
+	u, err := decodeUint(path, val, typeOf[T]())
+	return T(u), err
+}
+
+// DecodeFloat decodes a number into a float, rounding it to the nearest
+// one.
+func DecodeFloat[T ~float32 | ~float64](path *tftypes.AttributePath, val tftypes.Value) (T, error) {
+	switch {
+	case !val.IsKnown():
+		return 0, unknownError[T](path)
+	case val.IsNull():
+		return 0, nil
+	}
+	f, err := decodeFloat(path, val, typeOf[T](), false)
+	return T(f), err
+}
+
+// DecodeBigFloat decodes a number into a big.Float.
+func DecodeBigFloat(path *tftypes.AttributePath, val tftypes.Value) (big.Float, error) {
+	switch {
+	case !val.IsKnown():
+		return big.Float{}, unknownError[big.Float](path)
+	case val.IsNull():
+		return big.Float{}, nil
+	}
+	f, err := numberValue(path, val)
+	if err != nil {
+		return big.Float{}, err
+	}
+	return *f, nil
+}
+
+// DecodeBigInt decodes a number into a big.Int, if it's an integer.
+func DecodeBigInt(path *tftypes.AttributePath, val tftypes.Value) (big.Int, error) {
+	switch {
+	case !val.IsKnown():
+		return big.Int{}, unknownError[big.Int](path)
+	case val.IsNull():
+		return big.Int{}, nil
+	}
+	f, err := numberValue(path, val)
+	if err != nil {
+		return big.Int{}, err
+	}
+	if !f.IsInt() {
+		return big.Int{}, path.NewErrorf("cannot decode %s into %s, it is not an integer", f.Text('g', -1), bigIntType)
+	}
+	i, _ := f.Int(nil)
+	return *i, nil
+}
+
+// DecodeValue returns `val` unaltered, for tftypes.Value targets.
+func DecodeValue(_ *tftypes.AttributePath, val tftypes.Value) (tftypes.Value, error) {
+	return val, nil
+}
+
+// ConverterDecoder returns a DecodeFunc that has a T decode values itself
+// with its FromTerraform5Value method.
+func ConverterDecoder[T any, PT interface {
+	*T
+	tftypes.ValueConverter
+}]() DecodeFunc[T] {
+	return func(path *tftypes.AttributePath, val tftypes.Value) (T, error) {
+		var v T
+		if err := PT(&v).FromTerraform5Value(val); err != nil {
+			return v, prefixError(path, err)
+		}
+		return v, nil
+	}
+}
+
+// PointerDecoder returns a DecodeFunc that decodes null values as nil, and
+// other values with `elem`.
+func PointerDecoder[T any](elem DecodeFunc[T]) DecodeFunc[*T] {
+	return func(path *tftypes.AttributePath, val tftypes.Value) (*T, error) {
+		switch {
+		case !val.IsKnown():
+			return nil, unknownError[*T](path)
+		case val.IsNull():
+			return nil, nil
+		}
+		v, err := elem(path, val)
+		if err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+}
+
+// SliceDecoder returns a DecodeFunc that decodes lists, sets, and tuples
+// into slices, decoding their elements with `elem`.
+func SliceDecoder[T any](elem DecodeFunc[T]) DecodeFunc[[]T] {
+	return func(path *tftypes.AttributePath, val tftypes.Value) ([]T, error) {
+		switch {
+		case !val.IsKnown():
+			return nil, unknownError[[]T](path)
+		case val.IsNull():
+			return nil, nil
+		}
+		isSet := val.Type().Is(tfSet)
+		if !isSet && !val.Type().Is(tfList) && !val.Type().Is(tfTuple) {
+			return nil, path.NewErrorf("cannot decode %s into %s, expected a list, set, or tuple", val.Type(), typeOf[[]T]())
+		}
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		res := make([]T, len(elems))
+		for i, v := range elems {
+			elemPath := path.WithElementKeyInt(i)
+			if isSet {
+				elemPath = path.WithElementKeyValue(v)
+			}
+			var err error
+			if res[i], err = elem(elemPath, v); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+}
+
+// MapDecoder returns a DecodeFunc that decodes maps and objects into maps,
+// decoding their elements with `elem`.
+func MapDecoder[T any](elem DecodeFunc[T]) DecodeFunc[map[string]T] {
+	return func(path *tftypes.AttributePath, val tftypes.Value) (map[string]T, error) {
+		switch {
+		case !val.IsKnown():
+			return nil, unknownError[map[string]T](path)
+		case val.IsNull():
+			return nil, nil
+		}
+		isObject := val.Type().Is(tfObject)
+		if !isObject && !val.Type().Is(tfMap) {
+			return nil, path.NewErrorf("cannot decode %s into %s, expected a map or object", val.Type(), typeOf[map[string]T]())
+		}
+		var elems map[string]tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		keys := make([]string, 0, len(elems))
+		for k := range elems {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		res := make(map[string]T, len(elems))
+		for _, k := range keys {
+			elemPath := path.WithElementKeyString(k)
+			if isObject {
+				elemPath = path.WithAttributeName(k)
+			}
+			v, err := elem(elemPath, elems[k])
+			if err != nil {
+				return nil, err
+			}
+			res[k] = v
+		}
+		return res, nil
+	}
+}
+
+// SetDecoder returns a DecodeFunc that decodes sets into Sets, decoding
+// their elements with `elem`.
+func SetDecoder[T comparable](elem DecodeFunc[T]) DecodeFunc[Set[T]] {
+	return func(path *tftypes.AttributePath, val tftypes.Value) (Set[T], error) {
+		switch {
+		case !val.IsKnown():
+			return nil, unknownError[Set[T]](path)
+		case val.IsNull():
+			return nil, nil
+		case !val.Type().Is(tfSet):
+			return nil, path.NewErrorf("cannot decode %s into %s, expected a set", val.Type(), typeOf[Set[T]]())
+		}
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		res := make(Set[T], len(elems))
+		for _, v := range elems {
+			e, err := elem(path.WithElementKeyValue(v), v)
+			if err != nil {
+				return nil, err
+			}
+			res[e] = struct{}{}
+		}
+		return res, nil
+	}
+}
+
+// DecodeObject returns the attributes of the object `val`, for decoding
+// into the struct type T, or nil if `val` is null.
+func DecodeObject[T any](path *tftypes.AttributePath, val tftypes.Value) (map[string]tftypes.Value, error) {
+	switch {
+	case !val.IsKnown():
+		return nil, unknownError[T](path)
+	case val.IsNull():
+		return nil, nil
+	case !val.Type().Is(tfObject):
+		return nil, path.NewErrorf("cannot decode %s into %s, expected an object", val.Type(), typeOf[T]())
+	}
+	var attrs map[string]tftypes.Value
+	if err := val.As(&attrs); err != nil {
+		return nil, path.NewError(err)
+	}
+	if attrs == nil {
+		attrs = map[string]tftypes.Value{}
+	}
+	return attrs, nil
+}
+
+// checkType returns an error if a T can't be encoded as `typ`, which must
+// be `want`.
+func checkType[T any](path *tftypes.AttributePath, typ, want tftypes.Type) error {
+	if !typ.Is(want) {
+		return path.NewErrorf("cannot encode %s as %s", typeOf[T](), typ)
+	}
+	return nil
+}
+
+// EncodeString encodes a string.
+func EncodeString[T ~string](path *tftypes.AttributePath, typ tftypes.Type, src T) (tftypes.Value, error) {
+	if err := checkType[T](path, typ, tfString); err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.NewValue(typ, string(src)), nil
+}
+
+// EncodeBool encodes a bool.
+func EncodeBool[T ~bool](path *tftypes.AttributePath, typ tftypes.Type, src T) (tftypes.Value, error) {
+	if err := checkType[T](path, typ, tfBool); err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.NewValue(typ, bool(src)), nil
+}
+
+// EncodeInt encodes a signed integer.
+func EncodeInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](path *tftypes.AttributePath, typ tftypes.Type, src T) (tftypes.Value, error) {
+	if err := checkType[T](path, typ, tfNumber); err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.NewValue(typ, new(big.Float).SetInt64(int64(src))), nil
+}
+
+// EncodeUint encodes an unsigned integer.
+func EncodeUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](path *tftypes.AttributePath, typ tftypes.Type, src T) (tftypes.Value, error) {
+	if err := checkType[T](path, typ, tfNumber); err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.NewValue(typ, new(big.Float).SetUint64(uint64(src))), nil
+}
+
+// EncodeFloat encodes a float. NaN and infinite floats are an error.
+func EncodeFloat[T ~float32 | ~float64](path *tftypes.AttributePath, typ tftypes.Type, src T) (tftypes.Value, error) {
+	if err := checkType[T](path, typ, tfNumber); err != nil {
+		return tftypes.Value{}, err
+	}
+	f := float64(src)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return tftypes.Value{}, path.NewErrorf("cannot encode %v as a number", f)
+	}
+	return tftypes.NewValue(typ, big.NewFloat(f)), nil
+}
+
+// EncodeBigFloat encodes a big.Float.
+func EncodeBigFloat(path *tftypes.AttributePath, typ tftypes.Type, src big.Float) (tftypes.Value, error) {
+	if err := checkType[big.Float](path, typ, tfNumber); err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.NewValue(typ, new(big.Float).Copy(&src)), nil
+}
+
+// EncodeBigInt encodes a big.Int.
+func EncodeBigInt(path *tftypes.AttributePath, typ tftypes.Type, src big.Int) (tftypes.Value, error) {
+	if err := checkType[big.Int](path, typ, tfNumber); err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.NewValue(typ, new(big.Float).SetInt(&src)), nil
+}
+
+// EncodeValue returns `src` if its type can be used as `typ`, encoding the
+// zero tftypes.Value as null.
+func EncodeValue(path *tftypes.AttributePath, typ tftypes.Type, src tftypes.Value) (tftypes.Value, error) {
+	if src.Type() == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if !typ.Is(tfDynamic) && !src.Type().UsableAs(typ) {
+		return tftypes.Value{}, path.NewErrorf("cannot use value of type %s as %s", src.Type(), typ)
+	}
+	return src, nil
+}
+
+// ConverterEncoder returns an EncodeFunc that has a T encode itself with
+// its ToTerraform5Value method.
+func ConverterEncoder[T tftypes.ValueCreator]() EncodeFunc[T] {
+	return func(path *tftypes.AttributePath, typ tftypes.Type, src T) (tftypes.Value, error) {
+		raw, err := src.ToTerraform5Value()
+		if err != nil {
+			return tftypes.Value{}, prefixError(path, err)
+		}
+		if err := tftypes.ValidateValue(typ, raw); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(typ, raw), nil
+	}
+}
+
+// PointerEncoder returns an EncodeFunc that encodes nil as null, and other
+// pointers by encoding what they point to with `elem`.
+func PointerEncoder[T any](elem EncodeFunc[T]) EncodeFunc[*T] {
+	return func(path *tftypes.AttributePath, typ tftypes.Type, src *T) (tftypes.Value, error) {
+		if src == nil {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		return elem(path, typ, *src)
+	}
+}
+
+// SliceEncoder returns an EncodeFunc that encodes slices as lists or sets,
+// encoding their elements with `elem`. Nil slices are encoded as null.
+func SliceEncoder[T any](elem EncodeFunc[T]) EncodeFunc[[]T] {
+	return func(path *tftypes.AttributePath, typ tftypes.Type, src []T) (tftypes.Value, error) {
+		var elemType tftypes.Type
+		isSet := false
+		switch t := typ.(type) {
+		case tftypes.List:
+			elemType = t.ElementType
+		case tftypes.Set:
+			elemType, isSet = t.ElementType, true
+		default:
+			return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", typeOf[[]T](), typ)
+		}
+		if src == nil {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		elems := make([]tftypes.Value, 0, len(src))
+		for i, v := range src {
+			elemPath := path.WithElementKeyInt(i)
+			if isSet {
+				// see Encoder.encodeElements
+				elemPath = path
+			}
+			e, err := elem(elemPath, elemType, v)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, e)
+		}
+		return newValue(path, typ, elems)
+	}
+}
+
+// MapEncoder returns an EncodeFunc that encodes maps as maps, encoding
+// their elements with `elem`. Nil maps are encoded as null.
+func MapEncoder[T any](elem EncodeFunc[T]) EncodeFunc[map[string]T] {
+	return func(path *tftypes.AttributePath, typ tftypes.Type, src map[string]T) (tftypes.Value, error) {
+		m, ok := typ.(tftypes.Map)
+		if !ok {
+			return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", typeOf[map[string]T](), typ)
+		}
+		if src == nil {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		elems := make(map[string]tftypes.Value, len(src))
+		for k, v := range src {
+			e, err := elem(path.WithElementKeyString(k), m.ElementType, v)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = e
+		}
+		return newValue(path, typ, elems)
+	}
+}
+
+// SetEncoder returns an EncodeFunc that encodes Sets as sets, encoding
+// their elements in order with `elem`. Nil Sets are encoded as null.
+func SetEncoder[T cmp.Ordered](elem EncodeFunc[T]) EncodeFunc[Set[T]] {
+	return func(path *tftypes.AttributePath, typ tftypes.Type, src Set[T]) (tftypes.Value, error) {
+		s, ok := typ.(tftypes.Set)
+		if !ok {
+			return tftypes.Value{}, path.NewErrorf("cannot encode %s as %s", typeOf[Set[T]](), typ)
+		}
+		if src == nil {
+			return tftypes.NewValue(typ, nil), nil
+		}
+		keys := make([]T, 0, len(src))
+		for k := range src {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		elems := make([]tftypes.Value, 0, len(keys))
+		for _, k := range keys {
+			// see Encoder.encodeElements
+			e, err := elem(path, s.ElementType, k)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, e)
+		}
+		return newValue(path, typ, elems)
+	}
+}
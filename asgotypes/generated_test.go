@@ -0,0 +1,29 @@
+package asgotypes
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSliceEncoderMixedDynamic(t *testing.T) {
+	encode := SliceEncoder(EncodeValue)
+	_, err := encode(tftypes.NewAttributePath(), tftypes.List{ElementType: tftypes.DynamicPseudoType}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "a"),
+		tftypes.NewValue(tftypes.Number, 1),
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMapEncoderMixedDynamic(t *testing.T) {
+	encode := MapEncoder(EncodeValue)
+	_, err := encode(tftypes.NewAttributePath(), tftypes.Map{ElementType: tftypes.DynamicPseudoType}, map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.String, "a"),
+		"b": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,316 @@
+// Code generated by asgotypes-gen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ServerType is the object type the tagged fields of Server map to.
+var ServerType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"id":      tftypes.String,
+	"name":    tftypes.String,
+	"enabled": tftypes.Bool,
+	"port":    tftypes.Number,
+	"tags":    tftypes.Map{ElementType: tftypes.String},
+	"aliases": tftypes.Set{ElementType: tftypes.String},
+	"disk":    tftypes.List{ElementType: DiskType},
+	"boot":    DiskType,
+	"extra":   tftypes.DynamicPseudoType,
+	"matrix":  tftypes.List{ElementType: tftypes.List{ElementType: tftypes.Number}},
+	"labels":  tftypes.Map{ElementType: tftypes.String},
+	"numbers": NumbersType,
+}}
+
+var (
+	_Server_Aliases_decode = asgotypes.SetDecoder(asgotypes.DecodeString[string])
+	_Server_Aliases_encode = asgotypes.SetEncoder(asgotypes.EncodeString[string])
+	_Server_Boot_decode    = asgotypes.PointerDecoder(asgotypes.ConverterDecoder[Disk]())
+	_Server_Boot_encode    = asgotypes.PointerEncoder(asgotypes.ConverterEncoder[Disk]())
+	_Server_Disks_decode   = asgotypes.SliceDecoder(asgotypes.ConverterDecoder[Disk]())
+	_Server_Disks_encode   = asgotypes.SliceEncoder(asgotypes.ConverterEncoder[Disk]())
+	_Server_Labels_decode  = asgotypes.MapDecoder(asgotypes.PointerDecoder(asgotypes.DecodeString[string]))
+	_Server_Labels_encode  = asgotypes.MapEncoder(asgotypes.PointerEncoder(asgotypes.EncodeString[string]))
+	_Server_Matrix_decode  = asgotypes.SliceDecoder(asgotypes.SliceDecoder(asgotypes.DecodeInt[int]))
+	_Server_Matrix_encode  = asgotypes.SliceEncoder(asgotypes.SliceEncoder(asgotypes.EncodeInt[int]))
+	_Server_Name_decode    = asgotypes.PointerDecoder(asgotypes.DecodeString[string])
+	_Server_Name_encode    = asgotypes.PointerEncoder(asgotypes.EncodeString[string])
+	_Server_Numbers_decode = asgotypes.ConverterDecoder[Numbers]()
+	_Server_Numbers_encode = asgotypes.ConverterEncoder[Numbers]()
+	_Server_Tags_decode    = asgotypes.MapDecoder(asgotypes.DecodeString[string])
+	_Server_Tags_encode    = asgotypes.MapEncoder(asgotypes.EncodeString[string])
+)
+
+// FromTerraform5Value decodes `val` into the Server like an
+// asgotypes.Decoder with the default settings would.
+func (v *Server) FromTerraform5Value(val tftypes.Value) error {
+	path := tftypes.NewAttributePath()
+	attrs, err := asgotypes.DecodeObject[Server](path, val)
+	if err != nil {
+		return err
+	}
+	if attrs == nil {
+		*v = Server{}
+		return nil
+	}
+	if attr, ok := attrs["id"]; ok {
+		if v.ID, err = asgotypes.DecodeString[string](path.WithAttributeName("id"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["name"]; ok {
+		if v.Name, err = _Server_Name_decode(path.WithAttributeName("name"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["enabled"]; ok {
+		if v.Enabled, err = asgotypes.DecodeBool[bool](path.WithAttributeName("enabled"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["port"]; ok {
+		if v.Port, err = asgotypes.DecodeInt[int](path.WithAttributeName("port"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["tags"]; ok {
+		if v.Tags, err = _Server_Tags_decode(path.WithAttributeName("tags"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["aliases"]; ok {
+		if v.Aliases, err = _Server_Aliases_decode(path.WithAttributeName("aliases"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["disk"]; ok {
+		if v.Disks, err = _Server_Disks_decode(path.WithAttributeName("disk"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["boot"]; ok {
+		if v.Boot, err = _Server_Boot_decode(path.WithAttributeName("boot"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["extra"]; ok {
+		if v.Extra, err = asgotypes.DecodeValue(path.WithAttributeName("extra"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["matrix"]; ok {
+		if v.Matrix, err = _Server_Matrix_decode(path.WithAttributeName("matrix"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["labels"]; ok {
+		if v.Labels, err = _Server_Labels_decode(path.WithAttributeName("labels"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["numbers"]; ok {
+		if v.Numbers, err = _Server_Numbers_decode(path.WithAttributeName("numbers"), attr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToTerraform5Value encodes the Server as an object like an
+// asgotypes.Encoder with the default settings would.
+func (v Server) ToTerraform5Value() (interface{}, error) {
+	path := tftypes.NewAttributePath()
+	attrs := make(map[string]tftypes.Value, 12)
+	var err error
+	if attrs["id"], err = asgotypes.EncodeString[string](path.WithAttributeName("id"), ServerType.AttributeTypes["id"], v.ID); err != nil {
+		return nil, err
+	}
+	if attrs["name"], err = _Server_Name_encode(path.WithAttributeName("name"), ServerType.AttributeTypes["name"], v.Name); err != nil {
+		return nil, err
+	}
+	if attrs["enabled"], err = asgotypes.EncodeBool[bool](path.WithAttributeName("enabled"), ServerType.AttributeTypes["enabled"], v.Enabled); err != nil {
+		return nil, err
+	}
+	if attrs["port"], err = asgotypes.EncodeInt[int](path.WithAttributeName("port"), ServerType.AttributeTypes["port"], v.Port); err != nil {
+		return nil, err
+	}
+	if attrs["tags"], err = _Server_Tags_encode(path.WithAttributeName("tags"), ServerType.AttributeTypes["tags"], v.Tags); err != nil {
+		return nil, err
+	}
+	if attrs["aliases"], err = _Server_Aliases_encode(path.WithAttributeName("aliases"), ServerType.AttributeTypes["aliases"], v.Aliases); err != nil {
+		return nil, err
+	}
+	if attrs["disk"], err = _Server_Disks_encode(path.WithAttributeName("disk"), ServerType.AttributeTypes["disk"], v.Disks); err != nil {
+		return nil, err
+	}
+	if attrs["boot"], err = _Server_Boot_encode(path.WithAttributeName("boot"), ServerType.AttributeTypes["boot"], v.Boot); err != nil {
+		return nil, err
+	}
+	if attrs["extra"], err = asgotypes.EncodeValue(path.WithAttributeName("extra"), ServerType.AttributeTypes["extra"], v.Extra); err != nil {
+		return nil, err
+	}
+	if attrs["matrix"], err = _Server_Matrix_encode(path.WithAttributeName("matrix"), ServerType.AttributeTypes["matrix"], v.Matrix); err != nil {
+		return nil, err
+	}
+	if attrs["labels"], err = _Server_Labels_encode(path.WithAttributeName("labels"), ServerType.AttributeTypes["labels"], v.Labels); err != nil {
+		return nil, err
+	}
+	if attrs["numbers"], err = _Server_Numbers_encode(path.WithAttributeName("numbers"), ServerType.AttributeTypes["numbers"], v.Numbers); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+// DiskType is the object type the tagged fields of Disk map to.
+var DiskType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"size": tftypes.Number,
+	"boot": tftypes.Bool,
+}}
+
+// FromTerraform5Value decodes `val` into the Disk like an
+// asgotypes.Decoder with the default settings would.
+func (v *Disk) FromTerraform5Value(val tftypes.Value) error {
+	path := tftypes.NewAttributePath()
+	attrs, err := asgotypes.DecodeObject[Disk](path, val)
+	if err != nil {
+		return err
+	}
+	if attrs == nil {
+		*v = Disk{}
+		return nil
+	}
+	if attr, ok := attrs["size"]; ok {
+		if v.Size, err = asgotypes.DecodeFloat[float64](path.WithAttributeName("size"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["boot"]; ok {
+		if v.Boot, err = asgotypes.DecodeBool[bool](path.WithAttributeName("boot"), attr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToTerraform5Value encodes the Disk as an object like an
+// asgotypes.Encoder with the default settings would.
+func (v Disk) ToTerraform5Value() (interface{}, error) {
+	path := tftypes.NewAttributePath()
+	attrs := make(map[string]tftypes.Value, 2)
+	var err error
+	if attrs["size"], err = asgotypes.EncodeFloat[float64](path.WithAttributeName("size"), DiskType.AttributeTypes["size"], v.Size); err != nil {
+		return nil, err
+	}
+	if attrs["boot"], err = asgotypes.EncodeBool[bool](path.WithAttributeName("boot"), DiskType.AttributeTypes["boot"], v.Boot); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+// NumbersType is the object type the tagged fields of Numbers map to.
+var NumbersType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"int8":    tftypes.Number,
+	"int64":   tftypes.Number,
+	"uint16":  tftypes.Number,
+	"uint64":  tftypes.Number,
+	"float32": tftypes.Number,
+	"big":     tftypes.Number,
+	"big_int": tftypes.Number,
+	"ports":   tftypes.Set{ElementType: tftypes.Number},
+}}
+
+var (
+	_Numbers_BigInt_decode = asgotypes.PointerDecoder(asgotypes.DecodeBigInt)
+	_Numbers_BigInt_encode = asgotypes.PointerEncoder(asgotypes.EncodeBigInt)
+	_Numbers_Ports_decode  = asgotypes.SetDecoder(asgotypes.DecodeInt[int])
+	_Numbers_Ports_encode  = asgotypes.SetEncoder(asgotypes.EncodeInt[int])
+)
+
+// FromTerraform5Value decodes `val` into the Numbers like an
+// asgotypes.Decoder with the default settings would.
+func (v *Numbers) FromTerraform5Value(val tftypes.Value) error {
+	path := tftypes.NewAttributePath()
+	attrs, err := asgotypes.DecodeObject[Numbers](path, val)
+	if err != nil {
+		return err
+	}
+	if attrs == nil {
+		*v = Numbers{}
+		return nil
+	}
+	if attr, ok := attrs["int8"]; ok {
+		if v.Int8, err = asgotypes.DecodeInt[int8](path.WithAttributeName("int8"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["int64"]; ok {
+		if v.Int64, err = asgotypes.DecodeInt[int64](path.WithAttributeName("int64"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["uint16"]; ok {
+		if v.Uint16, err = asgotypes.DecodeUint[uint16](path.WithAttributeName("uint16"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["uint64"]; ok {
+		if v.Uint64, err = asgotypes.DecodeUint[uint64](path.WithAttributeName("uint64"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["float32"]; ok {
+		if v.Float32, err = asgotypes.DecodeFloat[float32](path.WithAttributeName("float32"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["big"]; ok {
+		if v.Big, err = asgotypes.DecodeBigFloat(path.WithAttributeName("big"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["big_int"]; ok {
+		if v.BigInt, err = _Numbers_BigInt_decode(path.WithAttributeName("big_int"), attr); err != nil {
+			return err
+		}
+	}
+	if attr, ok := attrs["ports"]; ok {
+		if v.Ports, err = _Numbers_Ports_decode(path.WithAttributeName("ports"), attr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToTerraform5Value encodes the Numbers as an object like an
+// asgotypes.Encoder with the default settings would.
+func (v Numbers) ToTerraform5Value() (interface{}, error) {
+	path := tftypes.NewAttributePath()
+	attrs := make(map[string]tftypes.Value, 8)
+	var err error
+	if attrs["int8"], err = asgotypes.EncodeInt[int8](path.WithAttributeName("int8"), NumbersType.AttributeTypes["int8"], v.Int8); err != nil {
+		return nil, err
+	}
+	if attrs["int64"], err = asgotypes.EncodeInt[int64](path.WithAttributeName("int64"), NumbersType.AttributeTypes["int64"], v.Int64); err != nil {
+		return nil, err
+	}
+	if attrs["uint16"], err = asgotypes.EncodeUint[uint16](path.WithAttributeName("uint16"), NumbersType.AttributeTypes["uint16"], v.Uint16); err != nil {
+		return nil, err
+	}
+	if attrs["uint64"], err = asgotypes.EncodeUint[uint64](path.WithAttributeName("uint64"), NumbersType.AttributeTypes["uint64"], v.Uint64); err != nil {
+		return nil, err
+	}
+	if attrs["float32"], err = asgotypes.EncodeFloat[float32](path.WithAttributeName("float32"), NumbersType.AttributeTypes["float32"], v.Float32); err != nil {
+		return nil, err
+	}
+	if attrs["big"], err = asgotypes.EncodeBigFloat(path.WithAttributeName("big"), NumbersType.AttributeTypes["big"], v.Big); err != nil {
+		return nil, err
+	}
+	if attrs["big_int"], err = _Numbers_BigInt_encode(path.WithAttributeName("big_int"), NumbersType.AttributeTypes["big_int"], v.BigInt); err != nil {
+		return nil, err
+	}
+	if attrs["ports"], err = _Numbers_Ports_encode(path.WithAttributeName("ports"), NumbersType.AttributeTypes["ports"], v.Ports); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
@@ -0,0 +1,273 @@
+package gentest
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// reflectedServer has the same fields as Server, but not its generated
+// methods, so it's decoded and encoded with reflection. Its Disks and
+// Numbers still use theirs, and are checked on their own.
+type reflectedServer Server
+
+type reflectedDisk Disk
+
+type reflectedNumbers Numbers
+
+var comparers = []cmp.Option{
+	cmp.Comparer(func(a, b big.Float) bool { return a.Cmp(&b) == 0 }),
+	cmp.Comparer(func(a, b big.Int) bool { return a.Cmp(&b) == 0 }),
+	cmp.Comparer(func(a, b tftypes.Value) bool { return a.Equal(b) }),
+}
+
+// errString returns the message of `err`, with the names of the reflected
+// types replaced by the generated ones.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return strings.ReplaceAll(err.Error(), "gentest.reflected", "gentest.")
+}
+
+func str(s interface{}) tftypes.Value {
+	return tftypes.NewValue(tftypes.String, s)
+}
+
+func num(n interface{}) tftypes.Value {
+	return tftypes.NewValue(tftypes.Number, n)
+}
+
+func disk(size interface{}) tftypes.Value {
+	return tftypes.NewValue(DiskType, map[string]tftypes.Value{
+		"size": num(size),
+		"boot": tftypes.NewValue(tftypes.Bool, true),
+	})
+}
+
+func numbers(attrs map[string]tftypes.Value) tftypes.Value {
+	vals := map[string]tftypes.Value{
+		"int8":    num(-8),
+		"int64":   num(64),
+		"uint16":  num(16),
+		"uint64":  num(new(big.Float).SetUint64(math.MaxUint64)),
+		"float32": num(1.5),
+		"big":     num(1e100),
+		"big_int": num(nil),
+		"ports": tftypes.NewValue(tftypes.Set{ElementType: tftypes.Number}, []tftypes.Value{
+			num(443), num(80),
+		}),
+	}
+	for k, v := range attrs {
+		vals[k] = v
+	}
+	return tftypes.NewValue(NumbersType, vals)
+}
+
+// server returns a Server value, with `attrs` replacing the defaults and
+// their types replacing those of ServerType.
+func server(attrs map[string]tftypes.Value) tftypes.Value {
+	stringMap := tftypes.Map{ElementType: tftypes.String}
+	vals := map[string]tftypes.Value{
+		"id":      str("abc"),
+		"name":    str(nil),
+		"enabled": tftypes.NewValue(tftypes.Bool, true),
+		"port":    num(80),
+		"tags":    tftypes.NewValue(stringMap, map[string]tftypes.Value{"env": str("prod")}),
+		"aliases": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{str("www"), str("api")}),
+		"disk":    tftypes.NewValue(tftypes.List{ElementType: DiskType}, []tftypes.Value{disk(10.5), disk(20)}),
+		"boot":    disk(1),
+		"extra":   tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{str(tftypes.UnknownValue)}),
+		"matrix": tftypes.NewValue(tftypes.List{ElementType: tftypes.List{ElementType: tftypes.Number}}, []tftypes.Value{
+			tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, []tftypes.Value{num(1), num(2)}),
+			tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, nil),
+		}),
+		"labels":  tftypes.NewValue(stringMap, map[string]tftypes.Value{"a": str("x"), "b": str(nil)}),
+		"numbers": numbers(nil),
+	}
+	for k, v := range attrs {
+		vals[k] = v
+	}
+	types := map[string]tftypes.Type{}
+	for k, v := range vals {
+		types[k] = v.Type()
+	}
+	if _, ok := attrs["extra"]; !ok {
+		types["extra"] = tftypes.DynamicPseudoType
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, vals)
+}
+
+func TestDecodeConformance(t *testing.T) {
+	cases := map[string]tftypes.Value{
+		"full":              server(nil),
+		"null":              tftypes.NewValue(ServerType, nil),
+		"unknown":           tftypes.NewValue(ServerType, tftypes.UnknownValue),
+		"wrong-type":        str("x"),
+		"unknown-string":    server(map[string]tftypes.Value{"id": str(tftypes.UnknownValue)}),
+		"unknown-pointer":   server(map[string]tftypes.Value{"name": str(tftypes.UnknownValue)}),
+		"pointer":           server(map[string]tftypes.Value{"name": str("web")}),
+		"null-object":       server(map[string]tftypes.Value{"boot": tftypes.NewValue(DiskType, nil)}),
+		"unknown-object":    server(map[string]tftypes.Value{"boot": tftypes.NewValue(DiskType, tftypes.UnknownValue)}),
+		"nested-unknown":    server(map[string]tftypes.Value{"disk": tftypes.NewValue(tftypes.List{ElementType: DiskType}, []tftypes.Value{disk(1), disk(tftypes.UnknownValue)})}),
+		"string-as-number":  server(map[string]tftypes.Value{"port": str("80")}),
+		"number-as-string":  server(map[string]tftypes.Value{"id": num(1)}),
+		"not-integer":       server(map[string]tftypes.Value{"port": num(1.5)}),
+		"list-as-map":       server(map[string]tftypes.Value{"tags": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{str("x")})}),
+		"object-as-map":     server(map[string]tftypes.Value{"tags": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"b": tftypes.String, "a": tftypes.Number}}, map[string]tftypes.Value{"b": str("x"), "a": num(1)})}),
+		"set-as-slice":      server(map[string]tftypes.Value{"disk": tftypes.NewValue(tftypes.Set{ElementType: DiskType}, []tftypes.Value{disk(1)})}),
+		"list-as-set":       server(map[string]tftypes.Value{"aliases": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{str("x")})}),
+		"unknown-set-elem":  server(map[string]tftypes.Value{"aliases": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{str(tftypes.UnknownValue)})}),
+		"unknown-map-elem":  server(map[string]tftypes.Value{"labels": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{"b": str(tftypes.UnknownValue), "a": str(tftypes.UnknownValue)})}),
+		"null-collections":  server(map[string]tftypes.Value{"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil), "matrix": tftypes.NewValue(tftypes.List{ElementType: tftypes.List{ElementType: tftypes.Number}}, nil)}),
+		"missing-attribute": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"id": tftypes.String}}, map[string]tftypes.Value{"id": str("abc")}),
+		"unknown-value":     server(map[string]tftypes.Value{"extra": str(tftypes.UnknownValue)}),
+		"int8-overflow":     server(map[string]tftypes.Value{"numbers": numbers(map[string]tftypes.Value{"int8": num(128)})}),
+		"uint16-negative":   server(map[string]tftypes.Value{"numbers": numbers(map[string]tftypes.Value{"uint16": num(-1)})}),
+		"uint64-overflow":   server(map[string]tftypes.Value{"numbers": numbers(map[string]tftypes.Value{"uint64": num(1e20)})}),
+		"float32-overflow":  server(map[string]tftypes.Value{"numbers": numbers(map[string]tftypes.Value{"float32": num(1e300)})}),
+		"big-int":           server(map[string]tftypes.Value{"numbers": numbers(map[string]tftypes.Value{"big_int": num(1e30)})}),
+		"big-int-fraction":  server(map[string]tftypes.Value{"numbers": numbers(map[string]tftypes.Value{"big_int": num(0.5)})}),
+		"unknown-big":       server(map[string]tftypes.Value{"numbers": numbers(map[string]tftypes.Value{"big": num(tftypes.UnknownValue)})}),
+		"infinity":          server(map[string]tftypes.Value{"disk": tftypes.NewValue(tftypes.List{ElementType: DiskType}, []tftypes.Value{disk(math.Inf(1))})}),
+	}
+	for name, val := range cases {
+		name, val := name, val
+		t.Run(name, func(t *testing.T) {
+			var generated Server
+			generatedErr := asgotypes.Decode(val, &generated)
+			var reflected reflectedServer
+			reflectedErr := asgotypes.Decode(val, &reflected)
+			if diff := cmp.Diff(errString(reflectedErr), errString(generatedErr)); diff != "" {
+				t.Errorf("unexpected error diff (-reflected, +generated): %s", diff)
+			}
+			if reflectedErr != nil {
+				return
+			}
+			if diff := cmp.Diff(Server(reflected), generated, comparers...); diff != "" {
+				t.Errorf("unexpected diff (-reflected, +generated): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeConformanceNested(t *testing.T) {
+	cases := map[string]tftypes.Value{
+		"disk":          disk(2),
+		"disk-unknown":  disk(tftypes.UnknownValue),
+		"disk-overflow": disk(new(big.Float).SetMantExp(big.NewFloat(1), 2000)),
+		"numbers":       numbers(nil),
+		"numbers-null":  tftypes.NewValue(NumbersType, nil),
+		"numbers-ports": numbers(map[string]tftypes.Value{"ports": tftypes.NewValue(tftypes.Set{ElementType: tftypes.Number}, []tftypes.Value{num(1.5)})}),
+	}
+	for name, val := range cases {
+		name, val := name, val
+		t.Run(name, func(t *testing.T) {
+			if val.Type().Equal(DiskType) {
+				var generated Disk
+				generatedErr := asgotypes.Decode(val, &generated)
+				var reflected reflectedDisk
+				reflectedErr := asgotypes.Decode(val, &reflected)
+				if diff := cmp.Diff(errString(reflectedErr), errString(generatedErr)); diff != "" {
+					t.Errorf("unexpected error diff (-reflected, +generated): %s", diff)
+				}
+				if diff := cmp.Diff(Disk(reflected), generated); diff != "" {
+					t.Errorf("unexpected diff (-reflected, +generated): %s", diff)
+				}
+				return
+			}
+			var generated Numbers
+			generatedErr := asgotypes.Decode(val, &generated)
+			var reflected reflectedNumbers
+			reflectedErr := asgotypes.Decode(val, &reflected)
+			if diff := cmp.Diff(errString(reflectedErr), errString(generatedErr)); diff != "" {
+				t.Errorf("unexpected error diff (-reflected, +generated): %s", diff)
+			}
+			if diff := cmp.Diff(Numbers(reflected), generated, comparers...); diff != "" {
+				t.Errorf("unexpected diff (-reflected, +generated): %s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeConformance(t *testing.T) {
+	name := "web"
+	full := Server{
+		ID:      "abc",
+		Name:    &name,
+		Enabled: true,
+		Port:    80,
+		Tags:    map[string]string{"env": "prod"},
+		Aliases: asgotypes.NewSet("www", "api"),
+		Disks:   []Disk{{Size: 10.5}, {Size: 20, Boot: true}},
+		Boot:    &Disk{Size: 1},
+		Extra:   str("x"),
+		Matrix:  [][]int{{1, 2}, nil},
+		Labels:  map[string]*string{"a": &name, "b": nil},
+		Numbers: Numbers{
+			Int8:    -8,
+			Uint64:  math.MaxUint64,
+			Float32: 1.5,
+			Big:     *big.NewFloat(1e100),
+			BigInt:  big.NewInt(7),
+			Ports:   asgotypes.NewSet(443, 80),
+		},
+	}
+	nan := full
+	nan.Disks = []Disk{{Size: 1}, {Size: math.NaN()}}
+	inf := full
+	inf.Numbers.Float32 = float32(math.Inf(-1))
+	wrongExtra := full
+	wrongExtra.Extra = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil)
+
+	cases := map[string]struct {
+		typ tftypes.Type
+		src Server
+	}{
+		"full":        {typ: ServerType, src: full},
+		"zero":        {typ: ServerType, src: Server{}},
+		"nan":         {typ: ServerType, src: nan},
+		"inf":         {typ: ServerType, src: inf},
+		"wrong-extra": {typ: ServerType, src: wrongExtra},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			generated, generatedErr := asgotypes.Encode(tc.typ, tc.src)
+			reflected, reflectedErr := asgotypes.Encode(tc.typ, reflectedServer(tc.src))
+			if diff := cmp.Diff(errString(reflectedErr), errString(generatedErr)); diff != "" {
+				t.Errorf("unexpected error diff (-reflected, +generated): %s", diff)
+			}
+			if !reflected.Equal(generated) {
+				t.Errorf("expected %s, got %s", reflected, generated)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	val := server(nil)
+	var s Server
+	if err := asgotypes.Decode(val, &s); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the extra attribute's unknown element can't round trip through a
+	// dynamic attribute's type
+	s.Extra = str("x")
+	got, err := asgotypes.Encode(ServerType, s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var again Server
+	if err := asgotypes.Decode(got, &again); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(s, again, comparers...); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,46 @@
+// Package gentest holds structs with methods generated by asgotypes-gen, so
+// the generated code can be checked against the asgotypes Decoder and
+// Encoder.
+package gentest
+
+import (
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+//go:generate go run ../../../cmd/asgotypes-gen -type Server,Disk,Numbers
+
+type Server struct {
+	ID       string                `tfsdk:"id"`
+	Name     *string               `tfsdk:"name"`
+	Enabled  bool                  `tfsdk:"enabled"`
+	Port     int                   `tfsdk:"port"`
+	Tags     map[string]string     `tfsdk:"tags"`
+	Aliases  asgotypes.Set[string] `tfsdk:"aliases"`
+	Disks    []Disk                `tfsdk:"disk"`
+	Boot     *Disk                 `tfsdk:"boot"`
+	Extra    tftypes.Value         `tfsdk:"extra"`
+	Matrix   [][]int               `tfsdk:"matrix"`
+	Labels   map[string]*string    `tfsdk:"labels"`
+	Internal string                `tfsdk:"-"`
+	Ignored  string
+	Numbers  Numbers `tfsdk:"numbers"`
+}
+
+type Disk struct {
+	Size float64 `tfsdk:"size"`
+	Boot bool    `tfsdk:"boot"`
+}
+
+type Numbers struct {
+	Int8    int8               `tfsdk:"int8"`
+	Int64   int64              `tfsdk:"int64"`
+	Uint16  uint16             `tfsdk:"uint16"`
+	Uint64  uint64             `tfsdk:"uint64"`
+	Float32 float32            `tfsdk:"float32"`
+	Big     big.Float          `tfsdk:"big"`
+	BigInt  *big.Int           `tfsdk:"big_int"`
+	Ports   asgotypes.Set[int] `tfsdk:"ports"`
+}
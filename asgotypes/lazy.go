@@ -0,0 +1,204 @@
+package asgotypes
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// LazyValue wraps a tftypes.Value so its attributes and elements can be
+// reached and decoded one at a time, on demand, instead of decoding the
+// whole value up front. The attributes and elements of each value are only
+// unpacked the first time they're needed, and are remembered, so inspecting
+// a few attributes of a large object is cheap no matter how often it's done:
+//
+//	lazy := asgotypes.NewLazyValue(plan)
+//	var size int
+//	err := lazy.DecodePath(tftypes.NewAttributePath().WithAttributeName("disk").WithElementKeyInt(0).WithAttributeName("size"), &size)
+//
+// LazyValue implements tftypes.ValueConverter, so it can also be used as the
+// type of a struct field, to defer decoding that attribute. It is safe for
+// concurrent use, and must not be copied after first use.
+type LazyValue struct {
+	val tftypes.Value
+
+	mu        sync.Mutex
+	unpacked  bool
+	attrs     map[string]*LazyValue
+	elems     []*LazyValue
+	primitive *GoPrimitive
+}
+
+// NewLazyValue returns a LazyValue wrapping `val`.
+func NewLazyValue(val tftypes.Value) *LazyValue {
+	return &LazyValue{val: val}
+}
+
+// Value returns the wrapped tftypes.Value.
+func (l *LazyValue) Value() tftypes.Value {
+	return l.val
+}
+
+// Get returns a LazyValue for the value at `path`. Errors are
+// tftypes.AttributePathErrors identifying the value that couldn't be
+// traversed.
+func (l *LazyValue) Get(path *tftypes.AttributePath) (*LazyValue, error) {
+	cur := l
+	steps := path.Steps()
+	for i, step := range steps {
+		next, err := cur.child(step)
+		if err != nil {
+			return nil, tftypes.NewAttributePathWithSteps(steps[:i]).NewError(err)
+		}
+		cur = next
+	}
+	return cur, nil
+}
+
+// Decode decodes the wrapped value into `target`, like Decode.
+func (l *LazyValue) Decode(target interface{}) error {
+	return Decode(l.val, target)
+}
+
+// DecodePath decodes the value at `path` into `target`, like Decode.
+func (l *LazyValue) DecodePath(path *tftypes.AttributePath, target interface{}) error {
+	v, err := l.Get(path)
+	if err != nil {
+		return err
+	}
+	if err := v.Decode(target); err != nil {
+		return prefixError(path, err)
+	}
+	return nil
+}
+
+// Primitive returns the wrapped value decoded as a GoPrimitive. The result
+// is remembered, so it must not be modified.
+func (l *LazyValue) Primitive() (interface{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.primitive == nil {
+		var gp GoPrimitive
+		if err := gp.FromTerraform5Value(l.val); err != nil {
+			return nil, err
+		}
+		l.primitive = &gp
+	}
+	return l.primitive.Value, nil
+}
+
+// FromTerraform5Value replaces the wrapped value with `val`.
+func (l *LazyValue) FromTerraform5Value(val tftypes.Value) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.val = val
+	l.unpacked = false
+	l.attrs = nil
+	l.elems = nil
+	l.primitive = nil
+	return nil
+}
+
+// String returns the wrapped value's string representation.
+func (l *LazyValue) String() string {
+	return l.val.String()
+}
+
+// child returns the attribute or element of the wrapped value identified
+// by `step`.
+func (l *LazyValue) child(step tftypes.AttributePathStep) (*LazyValue, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.val.IsKnown() {
+		return nil, errors.New("cannot traverse an unknown value")
+	}
+	if l.val.IsNull() {
+		return nil, errors.New("cannot traverse a null value")
+	}
+	if err := l.unpack(); err != nil {
+		return nil, err
+	}
+	typ := l.val.Type()
+	switch s := step.(type) {
+	case tftypes.AttributeName:
+		if typ.Is(tfObject) {
+			if v, ok := l.attrs[string(s)]; ok {
+				return v, nil
+			}
+			return nil, fmt.Errorf("attribute %q not found", string(s))
+		}
+	case tftypes.ElementKeyString:
+		if typ.Is(tfMap) {
+			if v, ok := l.attrs[string(s)]; ok {
+				return v, nil
+			}
+			return nil, fmt.Errorf("element %q not found", string(s))
+		}
+	case tftypes.ElementKeyInt:
+		if typ.Is(tfList) || typ.Is(tfTuple) {
+			if s >= 0 && int64(s) < int64(len(l.elems)) {
+				return l.elems[s], nil
+			}
+			return nil, fmt.Errorf("element %d not found, there are %d elements", int64(s), len(l.elems))
+		}
+	case tftypes.ElementKeyValue:
+		if typ.Is(tfSet) {
+			for _, v := range l.elems {
+				if v.val.Equal(tftypes.Value(s)) {
+					return v, nil
+				}
+			}
+			return nil, fmt.Errorf("element %s not found", tftypes.Value(s))
+		}
+	}
+	return nil, stepError(step, typ)
+}
+
+// stepError returns the error for `step` not applying to a value of type
+// `typ`.
+func stepError(step tftypes.AttributePathStep, typ tftypes.Type) error {
+	switch s := step.(type) {
+	case tftypes.AttributeName:
+		return fmt.Errorf("cannot get attribute %q of %s", string(s), typ)
+	case tftypes.ElementKeyInt:
+		return fmt.Errorf("cannot get element %d of %s", int64(s), typ)
+	case tftypes.ElementKeyString:
+		return fmt.Errorf("cannot get element %q of %s", string(s), typ)
+	case tftypes.ElementKeyValue:
+		return fmt.Errorf("cannot get element %s of %s", tftypes.Value(s), typ)
+	}
+	return fmt.Errorf("unsupported attribute path step %T", step)
+}
+
+// unpack wraps the attributes or elements of the wrapped value in
+// LazyValues, if it hasn't already.
+func (l *LazyValue) unpack() error {
+	if l.unpacked {
+		return nil
+	}
+	typ := l.val.Type()
+	switch {
+	case typ.Is(tfObject), typ.Is(tfMap):
+		attrs := map[string]tftypes.Value{}
+		if err := l.val.As(&attrs); err != nil {
+			return err
+		}
+		l.attrs = make(map[string]*LazyValue, len(attrs))
+		for k, v := range attrs {
+			l.attrs[k] = &LazyValue{val: v}
+		}
+	case typ.Is(tfList), typ.Is(tfSet), typ.Is(tfTuple):
+		var elems []tftypes.Value
+		if err := l.val.As(&elems); err != nil {
+			return err
+		}
+		l.elems = make([]*LazyValue, 0, len(elems))
+		for _, v := range elems {
+			l.elems = append(l.elems, &LazyValue{val: v})
+		}
+	}
+	l.unpacked = true
+	return nil
+}
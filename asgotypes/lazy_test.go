@@ -0,0 +1,130 @@
+package asgotypes
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestLazyValue(t *testing.T) {
+	lazy := NewLazyValue(testServerValue(tftypes.NewValue(tftypes.Number, tftypes.UnknownValue)))
+	root := tftypes.NewAttributePath()
+
+	var size float64
+	if err := lazy.DecodePath(root.WithAttributeName("disk").WithElementKeyInt(0).WithAttributeName("size"), &size); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if size != 10.5 {
+		t.Errorf("expected 10.5, got %v", size)
+	}
+
+	first, err := lazy.Get(root.WithAttributeName("tags"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := lazy.Get(root.WithAttributeName("tags"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if first != second {
+		t.Errorf("expected the same LazyValue for the same path")
+	}
+	tags, err := first.Primitive()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(map[string]string{"env": "prod"}, tags); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	alias, err := lazy.Get(root.WithAttributeName("aliases").WithElementKeyValue(tftypes.NewValue(tftypes.String, "www")))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := tftypes.NewValue(tftypes.String, "www"); !alias.Value().Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, alias)
+	}
+}
+
+func TestLazyValueErrors(t *testing.T) {
+	lazy := NewLazyValue(testServerValue(tftypes.NewValue(tftypes.Number, tftypes.UnknownValue)))
+	root := tftypes.NewAttributePath()
+	type testCase struct {
+		path        *tftypes.AttributePath
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"missing-attribute": {
+			path:        root.WithAttributeName("address"),
+			expectedErr: `attribute "address" not found`,
+		},
+		"out-of-range": {
+			path:        root.WithAttributeName("disk").WithElementKeyInt(1),
+			expectedErr: `AttributeName("disk"): element 1 not found, there are 1 elements`,
+		},
+		"unknown": {
+			path:        root.WithAttributeName("port").WithAttributeName("x"),
+			expectedErr: `AttributeName("port"): cannot traverse an unknown value`,
+		},
+		"null": {
+			path:        root.WithAttributeName("name").WithAttributeName("x"),
+			expectedErr: `AttributeName("name"): cannot traverse a null value`,
+		},
+		"wrong-step": {
+			path:        root.WithAttributeName("tags").WithAttributeName("env"),
+			expectedErr: `AttributeName("tags"): cannot get attribute "env" of tftypes.Map[tftypes.String]`,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			_, err := lazy.Get(test.path)
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("expected error %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+
+	var port int
+	err := lazy.DecodePath(root.WithAttributeName("port"), &port)
+	expectedErr := `AttributeName("port"): cannot decode unknown value into int`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestLazyValueField(t *testing.T) {
+	type resource struct {
+		Port int        `tfsdk:"port"`
+		Disk *LazyValue `tfsdk:"disk"`
+	}
+	var got resource
+	if err := Decode(testServerValue(tftypes.NewValue(tftypes.Number, big.NewFloat(80))), &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var boot bool
+	if err := got.Disk.DecodePath(tftypes.NewAttributePath().WithElementKeyInt(0).WithAttributeName("boot"), &boot); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.Port != 80 || !boot {
+		t.Errorf("unexpected result %d, %t", got.Port, boot)
+	}
+
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"port": tftypes.Number,
+		"disk": testServerType.AttributeTypes["disk"],
+	}}
+	encoded, err := Encode(typ, got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	disk, err := NewLazyValue(encoded).Get(tftypes.NewAttributePath().WithAttributeName("disk"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !disk.Value().Equal(got.Disk.Value()) {
+		t.Errorf("expected %s, got %s", got.Disk, disk)
+	}
+}
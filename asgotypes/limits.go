@@ -0,0 +1,83 @@
+package asgotypes
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// LimitError is wrapped by the errors a Decoder returns when a value exceeds
+// one of its limits, so they can be told apart from other errors with
+// errors.As.
+type LimitError struct {
+	// Limit is the name of the Decoder field that was exceeded, like
+	// "MaxDepth".
+	Limit string
+
+	// Max is the value of the limit.
+	Max int
+
+	// Actual is the depth or length of the value.
+	Actual int
+}
+
+func (e *LimitError) Error() string {
+	what := "length"
+	if e.Limit == "MaxDepth" {
+		what = "depth"
+	}
+	return fmt.Sprintf("%s %d exceeds %s of %d", what, e.Actual, e.Limit, e.Max)
+}
+
+// hasLimits returns true if any of the Decoder's limits are set.
+func (d *Decoder) hasLimits() bool {
+	return d.MaxDepth > 0 || d.MaxElements > 0 || d.MaxStringLength > 0
+}
+
+// checkLimits returns an error if `val`, or any value in it, exceeds the
+// Decoder's limits.
+func (d *Decoder) checkLimits(val tftypes.Value) error {
+	return tftypes.Walk(val, func(path *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+		if d.ctx != nil {
+			if err := d.ctx.Err(); err != nil {
+				return false, err
+			}
+		}
+		if d.MaxDepth > 0 {
+			if depth := len(path.Steps()); depth > d.MaxDepth {
+				return false, &LimitError{Limit: "MaxDepth", Max: d.MaxDepth, Actual: depth}
+			}
+		}
+		if !v.IsKnown() || v.IsNull() {
+			return false, nil
+		}
+		typ := v.Type()
+		switch {
+		case d.MaxStringLength > 0 && typ.Is(tfString):
+			var s string
+			if err := v.As(&s); err != nil {
+				return false, err
+			}
+			if len(s) > d.MaxStringLength {
+				return false, &LimitError{Limit: "MaxStringLength", Max: d.MaxStringLength, Actual: len(s)}
+			}
+		case d.MaxElements > 0 && (typ.Is(tfList) || typ.Is(tfSet) || typ.Is(tfTuple)):
+			var elems []tftypes.Value
+			if err := v.As(&elems); err != nil {
+				return false, err
+			}
+			if len(elems) > d.MaxElements {
+				return false, &LimitError{Limit: "MaxElements", Max: d.MaxElements, Actual: len(elems)}
+			}
+		case d.MaxElements > 0 && typ.Is(tfMap):
+			elems := map[string]tftypes.Value{}
+			if err := v.As(&elems); err != nil {
+				return false, err
+			}
+			if len(elems) > d.MaxElements {
+				return false, &LimitError{Limit: "MaxElements", Max: d.MaxElements, Actual: len(elems)}
+			}
+		}
+		return true, nil
+	})
+}
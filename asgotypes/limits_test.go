@@ -0,0 +1,92 @@
+package asgotypes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDecodeLimits(t *testing.T) {
+	strList := tftypes.List{ElementType: tftypes.String}
+	nestedType := tftypes.List{ElementType: strList}
+	val := tftypes.NewValue(nestedType, []tftypes.Value{
+		tftypes.NewValue(strList, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+			tftypes.NewValue(tftypes.String, strings.Repeat("b", 10)),
+		}),
+		tftypes.NewValue(strList, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "c"),
+			tftypes.NewValue(tftypes.String, "d"),
+			tftypes.NewValue(tftypes.String, "e"),
+		}),
+	})
+	type testCase struct {
+		decoder     Decoder
+		expectedErr string
+		limitErr    *LimitError
+	}
+	cases := map[string]testCase{
+		"within-limits": {
+			decoder: Decoder{MaxDepth: 2, MaxElements: 3, MaxStringLength: 10},
+		},
+		"depth": {
+			decoder:     Decoder{MaxDepth: 1},
+			expectedErr: "ElementKeyInt(0).ElementKeyInt(0): depth 2 exceeds MaxDepth of 1",
+			limitErr:    &LimitError{Limit: "MaxDepth", Max: 1, Actual: 2},
+		},
+		"elements": {
+			decoder:     Decoder{MaxElements: 2},
+			expectedErr: "ElementKeyInt(1): length 3 exceeds MaxElements of 2",
+			limitErr:    &LimitError{Limit: "MaxElements", Max: 2, Actual: 3},
+		},
+		"string-length": {
+			decoder:     Decoder{MaxStringLength: 9},
+			expectedErr: "ElementKeyInt(0).ElementKeyInt(1): length 10 exceeds MaxStringLength of 9",
+			limitErr:    &LimitError{Limit: "MaxStringLength", Max: 9, Actual: 10},
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			// limits apply even to values decoded as they are
+			var got tftypes.Value
+			err := test.decoder.Decode(val, &got)
+			if test.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expectedErr {
+				t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+			}
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected a *LimitError, got %T", err)
+			}
+			if diff := cmp.Diff(test.limitErr, limitErr); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeLimitsMap(t *testing.T) {
+	val := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.String, "1"),
+		"b": tftypes.NewValue(tftypes.String, "2"),
+	})
+	d := Decoder{MaxElements: 1}
+	var got map[string]string
+	err := d.Decode(val, &got)
+	expectedErr := "length 2 exceeds MaxElements of 1"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+	if got != nil {
+		t.Errorf("expected nothing to be decoded, got %v", got)
+	}
+}
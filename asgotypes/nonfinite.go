@@ -0,0 +1,71 @@
+package asgotypes
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// NonFinitePolicy controls how NaN and infinite float32 and float64 values,
+// which Terraform's numbers can't hold, are encoded and decoded.
+type NonFinitePolicy int
+
+const (
+	// NonFiniteError makes encoding NaN or infinite floats an error.
+	NonFiniteError NonFinitePolicy = iota
+
+	// NonFiniteNull encodes NaN and infinite floats as null, and decodes
+	// null numbers into float targets as NaN.
+	NonFiniteNull
+
+	// NonFiniteString encodes NaN and infinite floats as the strings
+	// "NaN", "+Inf", and "-Inf", for attributes of type String or
+	// DynamicPseudoType, and decodes those strings into float targets.
+	// Encoding them as numbers is still an error.
+	NonFiniteString
+)
+
+// nonFiniteStrings are the strings NaN and infinite floats are encoded as
+// by NonFiniteString.
+var nonFiniteStrings = map[string]float64{
+	"NaN":  math.NaN(),
+	"+Inf": math.Inf(1),
+	"-Inf": math.Inf(-1),
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// encodeNonFinite encodes the NaN or infinite float `src` according to
+// `policy`. It returns false if `src` isn't NaN or infinite, or the policy
+// doesn't apply to `typ`.
+func encodeNonFinite(policy NonFinitePolicy, typ tftypes.Type, src reflect.Value) (tftypes.Value, bool) {
+	if !isFloat(src.Kind()) {
+		return tftypes.Value{}, false
+	}
+	f := src.Float()
+	if !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return tftypes.Value{}, false
+	}
+	switch policy {
+	case NonFiniteNull:
+		if typ.Is(tfDynamic) {
+			typ = tftypes.Number
+		}
+		return tftypes.NewValue(typ, nil), true
+	case NonFiniteString:
+		if !typ.Is(tfString) && !typ.Is(tfDynamic) {
+			return tftypes.Value{}, false
+		}
+		s := "NaN"
+		if math.IsInf(f, 1) {
+			s = "+Inf"
+		} else if math.IsInf(f, -1) {
+			s = "-Inf"
+		}
+		return tftypes.NewValue(tftypes.String, s), true
+	}
+	return tftypes.Value{}, false
+}
@@ -0,0 +1,164 @@
+package asgotypes
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestEncodeNonFinite(t *testing.T) {
+	type testCase struct {
+		policy      NonFinitePolicy
+		typ         tftypes.Type
+		src         float64
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"error": {
+			typ:         tftypes.Number,
+			src:         math.NaN(),
+			expectedErr: "cannot encode NaN as a number",
+		},
+		"null": {
+			policy:   NonFiniteNull,
+			typ:      tftypes.Number,
+			src:      math.Inf(1),
+			expected: tftypes.NewValue(tftypes.Number, nil),
+		},
+		"null-dynamic": {
+			policy:   NonFiniteNull,
+			typ:      tftypes.DynamicPseudoType,
+			src:      math.NaN(),
+			expected: tftypes.NewValue(tftypes.Number, nil),
+		},
+		"string": {
+			policy:   NonFiniteString,
+			typ:      tftypes.String,
+			src:      math.Inf(-1),
+			expected: tftypes.NewValue(tftypes.String, "-Inf"),
+		},
+		"string-dynamic": {
+			policy:   NonFiniteString,
+			typ:      tftypes.DynamicPseudoType,
+			src:      math.NaN(),
+			expected: tftypes.NewValue(tftypes.String, "NaN"),
+		},
+		"string-number": {
+			policy:      NonFiniteString,
+			typ:         tftypes.Number,
+			src:         math.Inf(1),
+			expectedErr: "cannot encode +Inf as a number",
+		},
+		"finite": {
+			policy:   NonFiniteNull,
+			typ:      tftypes.Number,
+			src:      1.5,
+			expected: tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			e := Encoder{NonFinite: test.policy}
+			got, err := e.Encode(test.typ, test.src)
+			if test.expectedErr != "" {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Errorf("expected error %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeNonFinite(t *testing.T) {
+	type testCase struct {
+		decoder     Decoder
+		val         tftypes.Value
+		expected    float64
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"null": {
+			val:      tftypes.NewValue(tftypes.Number, nil),
+			expected: 0,
+		},
+		"null-nan": {
+			decoder:  Decoder{NonFinite: NonFiniteNull},
+			val:      tftypes.NewValue(tftypes.Number, nil),
+			expected: math.NaN(),
+		},
+		"string": {
+			decoder:  Decoder{NonFinite: NonFiniteString},
+			val:      tftypes.NewValue(tftypes.String, "+Inf"),
+			expected: math.Inf(1),
+		},
+		"invalid-string": {
+			decoder:     Decoder{NonFinite: NonFiniteString},
+			val:         tftypes.NewValue(tftypes.String, "1.5"),
+			expectedErr: `cannot decode "1.5" into float64, expected NaN, +Inf, or -Inf`,
+		},
+		"infinity": {
+			val:      tftypes.NewValue(tftypes.Number, new(big.Float).SetInf(true)),
+			expected: math.Inf(-1),
+		},
+		"overflow": {
+			val:         tftypes.NewValue(tftypes.Number, bigNumber("1e400")),
+			expectedErr: "cannot decode 1e+400 into float64, it overflows",
+		},
+		"rounded": {
+			val:      tftypes.NewValue(tftypes.Number, bigNumber("0.1")),
+			expected: 0.1,
+		},
+		"exact": {
+			decoder:     Decoder{ExactFloats: true},
+			val:         tftypes.NewValue(tftypes.Number, bigNumber("0.1")),
+			expectedErr: "cannot decode 0.1 into float64 exactly",
+		},
+		"exact-representable": {
+			decoder:  Decoder{ExactFloats: true},
+			val:      tftypes.NewValue(tftypes.Number, bigNumber("0.5")),
+			expected: 0.5,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var got float64
+			err := test.decoder.Decode(test.val, &got)
+			if test.expectedErr != "" {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Errorf("expected error %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got, cmpopts.EquateNaNs()); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeExactFloat32(t *testing.T) {
+	d := Decoder{ExactFloats: true}
+	var got float32
+	err := d.Decode(tftypes.NewValue(tftypes.Number, big.NewFloat(16777217)), &got)
+	expectedErr := "cannot decode 1.6777217e+07 into float32 exactly"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
@@ -0,0 +1,145 @@
+package asgotypes
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// OrderedMap is a map with string keys that remembers the order its keys
+// were added in, for when objects or maps are displayed or serialized and
+// their order matters, like in generated documentation or JSON that's
+// compared byte for byte. The zero value is an empty OrderedMap ready to use.
+//
+// Objects and maps can be decoded into OrderedMaps and encoded from them like
+// other maps. Terraform doesn't give their attributes or elements an order, so
+// keys already in the OrderedMap keep their place when it's decoded into,
+// and new keys are added after them in order. An OrderedMap can be given an
+// order, like that of the attributes in a schema, by setting its keys before
+// decoding:
+//
+//	var attrs asgotypes.OrderedMap[interface{}]
+//	for _, name := range []string{"id", "name", "tags"} {
+//		attrs.Set(name, nil)
+//	}
+//	err := asgotypes.Decode(val, &attrs)
+//
+// Keys that aren't in the value are removed.
+type OrderedMap[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+// Get returns the value for `key`, and whether it's in the map.
+func (m *OrderedMap[V]) Get(key string) (V, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set sets the value for `key`, adding it after the other keys if it isn't
+// already in the map.
+func (m *OrderedMap[V]) Set(key string, value V) {
+	if m.values == nil {
+		m.values = map[string]V{}
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Delete removes `key` from the map.
+func (m *OrderedMap[V]) Delete(key string) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the keys of the map, in order.
+func (m *OrderedMap[V]) Keys() []string {
+	return append([]string(nil), m.keys...)
+}
+
+// Len returns the number of keys in the map.
+func (m *OrderedMap[V]) Len() int {
+	return len(m.keys)
+}
+
+// MarshalJSON encodes the map as a JSON object with its keys in order.
+func (m OrderedMap[V]) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// orderedMap is implemented by OrderedMaps, so the Decoder and Encoder can
+// convert them to and from Go maps without knowing their type parameter.
+type orderedMap interface {
+	goMap() reflect.Value
+}
+
+// orderedMapTarget is implemented by pointers to OrderedMaps.
+type orderedMapTarget interface {
+	orderedMap
+	setGoMap(m reflect.Value)
+}
+
+var (
+	orderedMapType       = reflect.TypeOf((*orderedMap)(nil)).Elem()
+	orderedMapTargetType = reflect.TypeOf((*orderedMapTarget)(nil)).Elem()
+)
+
+// goMap returns the contents of the map as a map[string]V.
+func (m OrderedMap[V]) goMap() reflect.Value {
+	res := make(map[string]V, len(m.values))
+	for k, v := range m.values {
+		res[k] = v
+	}
+	return reflect.ValueOf(res)
+}
+
+// setGoMap replaces the contents of the map with those of the
+// map[string]V `gm`, keeping the order of the keys already in the map.
+func (m *OrderedMap[V]) setGoMap(gm reflect.Value) {
+	values := gm.Interface().(map[string]V)
+	var keys []string
+	for _, k := range m.keys {
+		if _, ok := values[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	var added []string
+	for k := range values {
+		if _, ok := m.values[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	m.keys = append(keys, added...)
+	m.values = values
+}
@@ -0,0 +1,115 @@
+package asgotypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[int]
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("c", 3)
+	m.Set("b", 4)
+	m.Delete("a")
+	m.Delete("z")
+	if diff := cmp.Diff([]string{"b", "c"}, m.Keys()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if v, ok := m.Get("b"); !ok || v != 4 {
+		t.Errorf("expected 4, got %d, %t", v, ok)
+	}
+	if _, ok := m.Get("a"); ok {
+		t.Errorf("expected a to be deleted")
+	}
+	if m.Len() != 2 {
+		t.Errorf("expected 2 keys, got %d", m.Len())
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := `{"b":4,"c":3}`; string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+}
+
+func TestOrderedMapRoundTrip(t *testing.T) {
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":   tftypes.String,
+		"name": tftypes.String,
+		"port": tftypes.Number,
+		"tags": tftypes.Map{ElementType: tftypes.String},
+	}}
+	val := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "abc"),
+		"name": tftypes.NewValue(tftypes.String, "web"),
+		"port": tftypes.NewValue(tftypes.Number, 80),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+	})
+
+	var got OrderedMap[interface{}]
+	got.Set("port", nil)
+	got.Set("address", nil)
+	got.Set("id", nil)
+	if err := Decode(val, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"port", "id", "name", "tags"}, got.Keys()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := `{"port":"80","id":"abc","name":"web","tags":null}`; string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+
+	encoded, err := Encode(typ, got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !encoded.Equal(val) {
+		t.Errorf("expected %s, got %s", val, encoded)
+	}
+}
+
+func TestOrderedMapField(t *testing.T) {
+	type resource struct {
+		Tags *OrderedMap[string] `tfsdk:"tags"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"tags": tftypes.Map{ElementType: tftypes.String},
+	}}
+	val := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"b": tftypes.NewValue(tftypes.String, "2"),
+			"a": tftypes.NewValue(tftypes.String, "1"),
+		}),
+	})
+	var got resource
+	if err := Decode(val, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, got.Tags.Keys()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	encoded, err := Encode(typ, got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !encoded.Equal(val) {
+		t.Errorf("expected %s, got %s", val, encoded)
+	}
+
+	var wrong OrderedMap[string]
+	err = Decode(tftypes.NewValue(tftypes.String, "a"), &wrong)
+	expectedErr := "cannot decode tftypes.String into map[string]string, expected a map or object"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
@@ -0,0 +1,64 @@
+package asgotypes
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// DecodePaths decodes only the values at `paths` in `val` into `target`,
+// using a Decoder with the default settings.
+func DecodePaths(val tftypes.Value, target interface{}, paths ...*tftypes.AttributePath) error {
+	var d Decoder
+	return d.DecodePaths(val, target, paths...)
+}
+
+// DecodePaths decodes only the values at `paths` in `val` into `target`,
+// which must be a non-nil pointer, skipping the rest of `val`, for when
+// only a few attributes of a large value are needed:
+//
+//	var state Server
+//	err := d.DecodePaths(val, &state,
+//		tftypes.NewAttributePath().WithAttributeName("id"),
+//		tftypes.NewAttributePath().WithAttributeName("region"),
+//	)
+//
+// Struct fields for other attributes are left as they are, elements of
+// slices and arrays that aren't decoded are left as zero values, and
+// elements of maps and Sets that aren't decoded are omitted. Paths that
+// aren't in `val` are ignored.
+func (d *Decoder) DecodePaths(val tftypes.Value, target interface{}, paths ...*tftypes.AttributePath) error {
+	dp := copyDecoder(d)
+	defer releaseDecoder(dp)
+	dp.paths = make([][]tftypes.AttributePathStep, 0, len(paths))
+	for _, p := range paths {
+		dp.paths = append(dp.paths, p.Steps())
+	}
+	return dp.Decode(val, target)
+}
+
+// skip returns true if the value at `path` isn't at, in, or on the way to
+// any of the paths passed to DecodePaths.
+func (d *Decoder) skip(path *tftypes.AttributePath) bool {
+	if d.paths == nil {
+		return false
+	}
+	steps := path.Steps()
+	for _, p := range d.paths {
+		n := len(steps)
+		if len(p) < n {
+			n = len(p)
+		}
+		if stepsEqual(steps[:n], p[:n]) {
+			return false
+		}
+	}
+	return true
+}
+
+func stepsEqual(a, b []tftypes.AttributePathStep) bool {
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package asgotypes
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDecodePaths(t *testing.T) {
+	// the port is unknown, which would be an error if it were decoded
+	val := testServerValue(tftypes.NewValue(tftypes.Number, tftypes.UnknownValue))
+	root := tftypes.NewAttributePath()
+	type testCase struct {
+		paths    []*tftypes.AttributePath
+		expected testServer
+	}
+	cases := map[string]testCase{
+		"attributes": {
+			paths: []*tftypes.AttributePath{
+				root.WithAttributeName("id"),
+				root.WithAttributeName("tags"),
+			},
+			expected: testServer{ID: "abc", Port: 1, Tags: map[string]string{"env": "prod"}},
+		},
+		"nested": {
+			paths: []*tftypes.AttributePath{
+				root.WithAttributeName("disk").WithElementKeyInt(0).WithAttributeName("size"),
+			},
+			expected: testServer{Port: 1, Disks: []testDisk{{Size: 10.5}}},
+		},
+		"set-element": {
+			paths: []*tftypes.AttributePath{
+				root.WithAttributeName("aliases").WithElementKeyValue(tftypes.NewValue(tftypes.String, "www")),
+			},
+			expected: testServer{Port: 1, Aliases: []string{"www"}},
+		},
+		"missing": {
+			paths: []*tftypes.AttributePath{
+				root.WithAttributeName("tags").WithElementKeyString("team"),
+				root.WithAttributeName("address"),
+			},
+			expected: testServer{Port: 1, Tags: map[string]string{}},
+		},
+		"none": {
+			expected: testServer{Port: 1},
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			got := testServer{Port: 1}
+			if err := DecodePaths(val, &got, test.paths...); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodePathsMap(t *testing.T) {
+	val := testServerValue(tftypes.NewValue(tftypes.Number, big.NewFloat(80)))
+	var got map[string]interface{}
+	err := DecodePaths(val, &got,
+		tftypes.NewAttributePath().WithAttributeName("id"),
+		tftypes.NewAttributePath().WithAttributeName("disk").WithElementKeyInt(0),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]interface{}{
+		"id": "abc",
+		"disk": []map[string]interface{}{
+			{"size": big.NewFloat(10.5), "boot": true},
+		},
+	}
+	if diff := cmp.Diff(expected, got, cmpOpts...); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,78 @@
+package asgotypes
+
+import (
+	"reflect"
+	"sync"
+)
+
+// maxScratchLen is the length above which scratch slices aren't returned to
+// scratchPool, so that decoding one huge collection doesn't keep its
+// buffers alive for every decode after it.
+const maxScratchLen = 4096
+
+// decoderPool holds the Decoders that Decode, DecodeContext, and
+// DecodePaths copy their settings into, so each call doesn't allocate one.
+// The Decoders in it are always reset.
+var decoderPool = sync.Pool{New: func() interface{} { return new(Decoder) }}
+
+// scratchPool holds the *scratch buffers used while decoding maps and sets.
+var scratchPool = sync.Pool{New: func() interface{} { return new(scratch) }}
+
+// Reset sets the Decoder back to its zero value, clearing all its settings,
+// so it can be reused as if it were new. Providers that configure Decoders
+// per RPC can keep them in a sync.Pool, resetting them before putting them
+// back:
+//
+//	var decoders = sync.Pool{New: func() any { return new(asgotypes.Decoder) }}
+//
+//	d := decoders.Get().(*asgotypes.Decoder)
+//	defer func() { d.Reset(); decoders.Put(d) }()
+//	d.AllowUnknown = true
+//	err := d.DecodeContext(ctx, val, &plan)
+func (d *Decoder) Reset() {
+	*d = Decoder{}
+}
+
+// copyDecoder returns a Decoder from decoderPool with the same settings as
+// `d`, which must be returned with releaseDecoder once it's done with.
+func copyDecoder(d *Decoder) *Decoder {
+	dc := decoderPool.Get().(*Decoder)
+	*dc = *d
+	return dc
+}
+
+func releaseDecoder(d *Decoder) {
+	d.Reset()
+	decoderPool.Put(d)
+}
+
+// scratch holds the keys of a map and the values decoded from its or a
+// set's elements, before they're put in the target map.
+type scratch struct {
+	keys   []string
+	values []reflect.Value
+}
+
+// getScratch returns a scratch from scratchPool with room for `n` keys and
+// values, which must be returned with releaseScratch once it's done with.
+func getScratch(n int) *scratch {
+	s := scratchPool.Get().(*scratch)
+	if cap(s.keys) < n {
+		s.keys = make([]string, 0, n)
+	}
+	if cap(s.values) < n {
+		s.values = make([]reflect.Value, n)
+	}
+	s.keys = s.keys[:0]
+	s.values = s.values[:n]
+	return s
+}
+
+func releaseScratch(s *scratch) {
+	if cap(s.keys) > maxScratchLen || cap(s.values) > maxScratchLen {
+		return
+	}
+	// don't keep the decoded values reachable
+	clear(s.values)
+	scratchPool.Put(s)
+}
@@ -0,0 +1,64 @@
+package asgotypes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDecoderReset(t *testing.T) {
+	d := Decoder{AllowUnknown: true, MaxDepth: 1, Parallelism: 4}
+	d.Reset()
+	if diff := cmp.Diff(Decoder{}, d, cmp.AllowUnexported(Decoder{})); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	var s string
+	err := d.Decode(tftypes.NewValue(tftypes.String, tftypes.UnknownValue), &s)
+	expectedErr := "cannot decode unknown value into string"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestDecodePooledDecoders(t *testing.T) {
+	// the Decoders DecodeContext copies settings into are reused, and
+	// mustn't keep them
+	unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	d := Decoder{AllowUnknown: true}
+	for i := 0; i < 10; i++ {
+		var s string
+		if err := d.DecodeContext(context.Background(), unknown, &s); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		expectedErr := "cannot decode unknown value into string"
+		if err := Decode(unknown, &s); err == nil || err.Error() != expectedErr {
+			t.Fatalf("expected error %q, got %v", expectedErr, err)
+		}
+	}
+}
+
+func TestDecodePooledScratch(t *testing.T) {
+	// the slices maps are decoded through are reused, and mustn't keep
+	// the elements of maps decoded before
+	mapType := tftypes.Map{ElementType: tftypes.String}
+	val := tftypes.NewValue(mapType, map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.String, "1"),
+		"b": tftypes.NewValue(tftypes.String, "2"),
+		"c": tftypes.NewValue(tftypes.String, "3"),
+	})
+	for i := 0; i < 10; i++ {
+		var all map[string]string
+		if err := Decode(val, &all); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var some map[string]string
+		if err := DecodePaths(val, &some, tftypes.NewAttributePath().WithElementKeyString("b")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := cmp.Diff(map[string]string{"b": "2"}, some); diff != "" {
+			t.Fatalf("unexpected diff (-wanted, +got): %s", diff)
+		}
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"errors"
 	"math/big"
 	"reflect"
+	"sort"
 
-	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tftypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
 // GoPrimitive is a way to get at the contents of a tftypes.Value without
 // asserting anything about the tftypes.Value except that it is fully known. It
 // is the equivalent of unmarshalling JSON to an interface{}.
@@ -32,6 +35,59 @@ import (
 // appropriate.
 type GoPrimitive struct {
 	Value interface{}
+
+	// Type is the type of the tftypes.Value the GoPrimitive was populated
+	// from, which Value alone can't always reproduce, as lists, sets, and
+	// tuples are all decoded as slices.
+	Type tftypes.Type
+
+	// Collections controls the Go types lists, sets, and maps are
+	// decoded as.
+	Collections CollectionMode
+}
+
+// CollectionMode controls the Go types a GoPrimitive decodes lists, sets, and
+// maps as.
+type CollectionMode int
+
+const (
+	// CollectionsTyped decodes lists and sets as slices, and maps as maps,
+	// of the Go type their elements decode as, like []string, or
+	// interface{} if their elements decode as different Go types, or any
+	// of them are null.
+	CollectionsTyped CollectionMode = iota
+
+	// CollectionsStrict decodes collections like CollectionsTyped, but
+	// returns an error identifying the first element that doesn't decode
+	// as the same Go type as the ones before it, or is null, rather than
+	// falling back to interface{}.
+	CollectionsStrict
+
+	// CollectionsLenient always decodes lists and sets as []interface{},
+	// and maps as map[string]interface{}, so the Go types of collections
+	// don't depend on their contents.
+	CollectionsLenient
+)
+
+// primitiveValue returns the known, non-null string, number, or bool
+// `value` as a GoPrimitive's Value, and false if `value` is of another
+// type.
+func primitiveValue(value tftypes.Value) (interface{}, bool, error) {
+	switch {
+	case value.Type().Is(tfString):
+		var str string
+		err := value.As(&str)
+		return str, true, err
+	case value.Type().Is(tfNumber):
+		num := new(big.Float)
+		err := value.As(num)
+		return num, true, err
+	case value.Type().Is(tfBool):
+		var b bool
+		err := value.As(&b)
+		return b, true, err
+	}
+	return nil, false, nil
 }
 
 // FromTerraform5Value controls how the GoPrimitive will be populated by a
@@ -40,36 +96,20 @@ func (dt *GoPrimitive) FromTerraform5Value(value tftypes.Value) error {
 	if !value.IsKnown() {
 		return errors.New("cannot decode unknown values to Go types")
 	}
+	dt.Type = value.Type()
 	if value.IsNull() {
 		dt.Value = nil
 		return nil
 	}
-	switch {
-	case value.Is(tftypes.String):
-		var str string
-		err := value.As(&str)
-		if err != nil {
-			return err
-		}
-		dt.Value = str
-		return nil
-	case value.Is(tftypes.Number):
-		num := big.NewFloat(-42)
-		err := value.As(&num)
+	if v, ok, err := primitiveValue(value); ok {
 		if err != nil {
 			return err
 		}
-		dt.Value = num
+		dt.Value = v
 		return nil
-	case value.Is(tftypes.Bool):
-		var b bool
-		err := value.As(&b)
-		if err != nil {
-			return err
-		}
-		dt.Value = b
-		return nil
-	case value.Is(tftypes.Object{}):
+	}
+	switch {
+	case value.Type().Is(tfObject):
 		msv := map[string]tftypes.Value{}
 		err := value.As(&msv)
 		if err != nil {
@@ -77,33 +117,30 @@ func (dt *GoPrimitive) FromTerraform5Value(value tftypes.Value) error {
 		}
 		res := map[string]interface{}{}
 		for k, v := range msv {
-			var vgp GoPrimitive
-			err = v.As(&vgp)
+			res[k], err = dt.child(tftypes.AttributeName(k), v)
 			if err != nil {
 				return err
 			}
-			res[k] = vgp.Value
 		}
 		dt.Value = res
 		return nil
-	case value.Is(tftypes.Tuple{}):
+	case value.Type().Is(tfTuple):
 		vals := []tftypes.Value{}
 		err := value.As(&vals)
 		if err != nil {
 			return err
 		}
 		res := []interface{}{}
-		for _, v := range vals {
-			var vgp GoPrimitive
-			err = v.As(&vgp)
+		for i, v := range vals {
+			elem, err := dt.child(tftypes.ElementKeyInt(i), v)
 			if err != nil {
 				return err
 			}
-			res = append(res, vgp.Value)
+			res = append(res, elem)
 		}
 		dt.Value = res
 		return nil
-	case value.Is(tftypes.List{}) || value.Is(tftypes.Set{}):
+	case value.Type().Is(tfList) || value.Type().Is(tfSet):
 		vals := []tftypes.Value{}
 		err := value.As(&vals)
 		if err != nil {
@@ -114,23 +151,32 @@ func (dt *GoPrimitive) FromTerraform5Value(value tftypes.Value) error {
 			dt.Value = tmp
 			return nil
 		}
-		for _, v := range vals {
-			var vgp GoPrimitive
-			err = v.As(&vgp)
+		isSet := value.Type().Is(tfSet)
+		steps := make([]tftypes.AttributePathStep, 0, len(vals))
+		for i, v := range vals {
+			var step tftypes.AttributePathStep = tftypes.ElementKeyInt(i)
+			if isSet {
+				step = tftypes.ElementKeyValue(v)
+			}
+			elem, err := dt.child(step, v)
 			if err != nil {
 				return err
 			}
-			tmp = append(tmp, vgp.Value)
+			tmp = append(tmp, elem)
+			steps = append(steps, step)
+		}
+		typ, err := dt.elementType(steps, tmp)
+		if err != nil {
+			return err
 		}
-		typ := reflect.TypeOf(tmp[0])
 		sliceTyp := reflect.SliceOf(typ)
 		res := reflect.MakeSlice(sliceTyp, 0, len(tmp))
 		for _, v := range tmp {
-			res = reflect.Append(res, reflect.ValueOf(v))
+			res = reflect.Append(res, elementValue(typ, v))
 		}
 		dt.Value = res.Interface()
 		return nil
-	case value.Is(tftypes.Map{}):
+	case value.Type().Is(tfMap):
 		msv := map[string]tftypes.Value{}
 		err := value.As(&msv)
 		if err != nil {
@@ -141,25 +187,106 @@ func (dt *GoPrimitive) FromTerraform5Value(value tftypes.Value) error {
 			dt.Value = tmp
 			return nil
 		}
-		var typ reflect.Type
-		for k, v := range msv {
-			var vgp GoPrimitive
-			err = v.As(&vgp)
+		keys := make([]string, 0, len(msv))
+		for k := range msv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		elems := make([]interface{}, 0, len(msv))
+		steps := make([]tftypes.AttributePathStep, 0, len(msv))
+		for _, k := range keys {
+			elem, err := dt.child(tftypes.ElementKeyString(k), msv[k])
 			if err != nil {
 				return err
 			}
-			if typ == nil {
-				typ = reflect.TypeOf(vgp.Value)
-			}
-			tmp[k] = vgp.Value
+			tmp[k] = elem
+			elems = append(elems, elem)
+			steps = append(steps, tftypes.ElementKeyString(k))
+		}
+		typ, err := dt.elementType(steps, elems)
+		if err != nil {
+			return err
 		}
 		mapTyp := reflect.MapOf(reflect.TypeOf(""), typ)
 		res := reflect.MakeMapWithSize(mapTyp, len(tmp))
 		for k, v := range tmp {
-			res.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v))
+			res.SetMapIndex(reflect.ValueOf(k), elementValue(typ, v))
 		}
 		dt.Value = res.Interface()
 		return nil
 	}
 	return errors.New("unknown type")
 }
+
+// child decodes the attribute or element `v` of the value being decoded,
+// identified by `step`, with the same settings.
+func (dt *GoPrimitive) child(step tftypes.AttributePathStep, v tftypes.Value) (interface{}, error) {
+	gp := GoPrimitive{Collections: dt.Collections}
+	if err := gp.FromTerraform5Value(v); err != nil {
+		return nil, prefixError(tftypes.NewAttributePathWithSteps([]tftypes.AttributePathStep{step}), err)
+	}
+	return gp.Value, nil
+}
+
+// elementType returns the Go type to decode a collection with the elements
+// `elems` as, according to the CollectionMode. `steps` identify the
+// elements, for errors.
+func (dt *GoPrimitive) elementType(steps []tftypes.AttributePathStep, elems []interface{}) (reflect.Type, error) {
+	switch dt.Collections {
+	case CollectionsLenient:
+		return interfaceType, nil
+	case CollectionsStrict:
+		var typ reflect.Type
+		for i, e := range elems {
+			path := tftypes.NewAttributePathWithSteps(steps[i : i+1])
+			t := reflect.TypeOf(e)
+			switch {
+			case t == nil:
+				return nil, path.NewErrorf("cannot decode null elements into a typed collection")
+			case typ != nil && t != typ:
+				return nil, path.NewErrorf("element decodes as %s, but earlier elements decode as %s", t, typ)
+			}
+			typ = t
+		}
+		return typ, nil
+	}
+	return elementType(elems), nil
+}
+
+// elementType returns the Go type shared by `elems`, or interface{} if they
+// don't all have the same type, like when some of them are null.
+func elementType(elems []interface{}) reflect.Type {
+	var typ reflect.Type
+	for _, e := range elems {
+		t := reflect.TypeOf(e)
+		if t == nil || (typ != nil && t != typ) {
+			return interfaceType
+		}
+		typ = t
+	}
+	if typ == nil {
+		return interfaceType
+	}
+	return typ
+}
+
+// prefixError returns `err` as a tftypes.AttributePathError at `path`. If
+// it's already a tftypes.AttributePathError, its path is appended to
+// `path`, rather than the error being wrapped again.
+func prefixError(path *tftypes.AttributePath, err error) error {
+	var pathErr tftypes.AttributePathError
+	if errors.As(err, &pathErr) {
+		steps := append(path.Steps(), pathErr.Path.Steps()...)
+		return tftypes.NewAttributePathWithSteps(steps).NewError(pathErr.Unwrap())
+	}
+	return path.NewError(err)
+}
+
+// elementValue returns `v` as a reflect.Value that can be stored in a slice
+// or map of `typ`.
+func elementValue(typ reflect.Type, v interface{}) reflect.Value {
+	if v == nil {
+		return reflect.Zero(typ)
+	}
+	return reflect.ValueOf(v)
+}
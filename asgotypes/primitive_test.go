@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tftypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestGoPrimitive(t *testing.T) {
@@ -82,7 +82,7 @@ func TestGoPrimitive(t *testing.T) {
 		},
 		"map-string": {
 			tfval: tftypes.NewValue(tftypes.Map{
-				AttributeType: tftypes.String,
+				ElementType: tftypes.String,
 			}, map[string]tftypes.Value{
 				"a": tftypes.NewValue(tftypes.String, "foo"),
 				"b": tftypes.NewValue(tftypes.String, "bar"),
@@ -94,10 +94,31 @@ func TestGoPrimitive(t *testing.T) {
 				"c": "baz",
 			},
 		},
+		"list-string-null": {
+			tfval: tftypes.NewValue(tftypes.List{
+				ElementType: tftypes.String,
+			}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, nil),
+				tftypes.NewValue(tftypes.String, "foo"),
+			}),
+			expected: []interface{}{nil, "foo"},
+		},
+		"map-string-null": {
+			tfval: tftypes.NewValue(tftypes.Map{
+				ElementType: tftypes.String,
+			}, map[string]tftypes.Value{
+				"a": tftypes.NewValue(tftypes.String, "foo"),
+				"b": tftypes.NewValue(tftypes.String, nil),
+			}),
+			expected: map[string]interface{}{
+				"a": "foo",
+				"b": nil,
+			},
+		},
 		"list-map-set-object-string-string-bool": {
 			tfval: tftypes.NewValue(tftypes.List{
 				ElementType: tftypes.Map{
-					AttributeType: tftypes.Set{
+					ElementType: tftypes.Set{
 						ElementType: tftypes.Object{
 							AttributeTypes: map[string]tftypes.Type{
 								"a": tftypes.String,
@@ -109,7 +130,7 @@ func TestGoPrimitive(t *testing.T) {
 				},
 			}, []tftypes.Value{
 				tftypes.NewValue(tftypes.Map{
-					AttributeType: tftypes.Set{
+					ElementType: tftypes.Set{
 						ElementType: tftypes.Object{
 							AttributeTypes: map[string]tftypes.Type{
 								"a": tftypes.String,
@@ -185,7 +206,7 @@ func TestGoPrimitive(t *testing.T) {
 					}),
 				}),
 				tftypes.NewValue(tftypes.Map{
-					AttributeType: tftypes.Set{
+					ElementType: tftypes.Set{
 						ElementType: tftypes.Object{
 							AttributeTypes: map[string]tftypes.Type{
 								"a": tftypes.String,
@@ -352,7 +373,7 @@ func TestGoPrimitive(t *testing.T) {
 		},
 		"map-list-string": {
 			tfval: tftypes.NewValue(tftypes.Map{
-				AttributeType: tftypes.List{
+				ElementType: tftypes.List{
 					ElementType: tftypes.String,
 				},
 			}, map[string]tftypes.Value{
@@ -393,3 +414,109 @@ func TestGoPrimitive(t *testing.T) {
 		})
 	}
 }
+
+func TestGoPrimitiveCollections(t *testing.T) {
+	// the empty list decodes as []interface{}, and the other as []string
+	nested := tftypes.NewValue(tftypes.List{ElementType: tftypes.List{ElementType: tftypes.String}}, []tftypes.Value{
+		tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+		}),
+		tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{}),
+	})
+	strList := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "a"),
+		tftypes.NewValue(tftypes.String, nil),
+	})
+	strMap := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.String, "b"),
+	})
+	type testCase struct {
+		mode        CollectionMode
+		tfval       tftypes.Value
+		expected    interface{}
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"typed-mixed": {
+			mode:     CollectionsTyped,
+			tfval:    nested,
+			expected: []interface{}{[]string{"a"}, []interface{}(nil)},
+		},
+		"typed-map": {
+			mode:     CollectionsTyped,
+			tfval:    strMap,
+			expected: map[string]string{"a": "b"},
+		},
+		"strict-mixed": {
+			mode:        CollectionsStrict,
+			tfval:       nested,
+			expectedErr: "ElementKeyInt(1): element decodes as []interface {}, but earlier elements decode as []string",
+		},
+		"strict-null": {
+			mode:        CollectionsStrict,
+			tfval:       strList,
+			expectedErr: "ElementKeyInt(1): cannot decode null elements into a typed collection",
+		},
+		"strict-nested": {
+			mode: CollectionsStrict,
+			tfval: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"list": strList.Type(),
+			}}, map[string]tftypes.Value{"list": strList}),
+			expectedErr: `AttributeName("list").ElementKeyInt(1): cannot decode null elements into a typed collection`,
+		},
+		"strict-map": {
+			mode:     CollectionsStrict,
+			tfval:    strMap,
+			expected: map[string]string{"a": "b"},
+		},
+		"lenient-list": {
+			mode:     CollectionsLenient,
+			tfval:    strList,
+			expected: []interface{}{"a", nil},
+		},
+		"lenient-map": {
+			mode:     CollectionsLenient,
+			tfval:    strMap,
+			expected: map[string]interface{}{"a": "b"},
+		},
+	}
+	for name, testCase := range cases {
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			res := GoPrimitive{Collections: testCase.mode}
+			err := res.FromTerraform5Value(testCase.tfval)
+			if testCase.expectedErr != "" {
+				if err == nil || err.Error() != testCase.expectedErr {
+					t.Errorf("expected error %q, got %v", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.expected, res.Value, cmpOpts...); diff != "" {
+				t.Errorf("Unexpected value (- wanted, + got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeCollections(t *testing.T) {
+	val := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"tags": tftypes.Map{ElementType: tftypes.String},
+	}}, map[string]tftypes.Value{
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"a": tftypes.NewValue(tftypes.String, nil),
+		}),
+	})
+	var got struct {
+		Tags interface{} `tfsdk:"tags"`
+	}
+	d := Decoder{Collections: CollectionsStrict}
+	err := d.Decode(val, &got)
+	expectedErr := `AttributeName("tags").ElementKeyString("a"): cannot decode null elements into a typed collection`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
@@ -0,0 +1,103 @@
+package asgotypes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+var emptyStructType = reflect.TypeOf(struct{}{})
+
+// Set is a set of Go values, which tftypes.Set values can be decoded into
+// and encoded from, so that their elements don't appear to have an order:
+//
+//	type Server struct {
+//		Aliases asgotypes.Set[string] `tfsdk:"aliases"`
+//	}
+//
+// Any map with struct{} values, like map[string]struct{}, can be used the
+// same way. Like other maps, a nil Set is decoded from and encoded as a null
+// set.
+type Set[T comparable] map[T]struct{}
+
+// NewSet returns a Set of `elems`.
+func NewSet[T comparable](elems ...T) Set[T] {
+	s := make(Set[T], len(elems))
+	for _, e := range elems {
+		s[e] = struct{}{}
+	}
+	return s
+}
+
+// Add adds `elem` to the set, which must not be nil.
+func (s Set[T]) Add(elem T) {
+	s[elem] = struct{}{}
+}
+
+// Remove removes `elem` from the set, if it's in it.
+func (s Set[T]) Remove(elem T) {
+	delete(s, elem)
+}
+
+// Has reports whether `elem` is in the set.
+func (s Set[T]) Has(elem T) bool {
+	_, ok := s[elem]
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Elements returns the elements of the set in a stable order, so they can be
+// iterated over or compared in tests. Strings, numbers, and bools are
+// ordered by value, and other types by their formatting with %v.
+func (s Set[T]) Elements() []T {
+	elems := make([]T, 0, len(s))
+	for e := range s {
+		elems = append(elems, e)
+	}
+	sort.Slice(elems, func(i, j int) bool {
+		return lessValue(reflect.ValueOf(elems[i]), reflect.ValueOf(elems[j]))
+	})
+	return elems
+}
+
+// setKeys returns the keys of the map `m`, sorted by lessValue.
+func setKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessValue(keys[i], keys[j])
+	})
+	return keys
+}
+
+// lessValue orders Go values of the same type, for iterating over sets in
+// a stable order.
+func lessValue(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return !a.IsValid() && b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return a.Type().String() < b.Type().String()
+	}
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	}
+	return fmt.Sprintf("%v", a.Interface()) < fmt.Sprintf("%v", b.Interface())
+}
+
+// isSetType returns true if `t` is a map type that can hold a set.
+func isSetType(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Elem() == emptyStructType
+}
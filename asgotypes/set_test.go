@@ -0,0 +1,99 @@
+package asgotypes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet("b", "a")
+	s.Add("c")
+	s.Remove("b")
+	if !s.Has("a") || s.Has("b") || s.Len() != 2 {
+		t.Errorf("unexpected set %v", s)
+	}
+	if diff := cmp.Diff([]string{"a", "c"}, s.Elements()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]int{2, 10}, NewSet(10, 2).Elements()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]interface{}{1, "a"}, NewSet[interface{}]("a", 1).Elements()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestSetRoundTrip(t *testing.T) {
+	typ := tftypes.Set{ElementType: tftypes.Number}
+	val := tftypes.NewValue(typ, []tftypes.Value{
+		tftypes.NewValue(tftypes.Number, 3),
+		tftypes.NewValue(tftypes.Number, 1),
+		tftypes.NewValue(tftypes.Number, 2),
+	})
+	var got Set[int]
+	if err := Decode(val, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(NewSet(1, 2, 3), got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	encoded, err := Encode(typ, got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := tftypes.NewValue(typ, []tftypes.Value{
+		tftypes.NewValue(tftypes.Number, 1),
+		tftypes.NewValue(tftypes.Number, 2),
+		tftypes.NewValue(tftypes.Number, 3),
+	})
+	if diff := cmp.Diff(expected, encoded); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	var plain map[string]struct{}
+	if err := Decode(tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, nil), &plain); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if plain != nil {
+		t.Errorf("expected nil map, got %v", plain)
+	}
+	encoded, err = Encode(typ, Set[int](nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !encoded.IsNull() {
+		t.Errorf("expected null, got %s", encoded)
+	}
+}
+
+func TestSetDecodeErrors(t *testing.T) {
+	type testCase struct {
+		val         tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"list": {
+			val:         tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{}),
+			expectedErr: "cannot decode tftypes.List[tftypes.String] into asgotypes.Set[string], expected a set",
+		},
+		"unknown-element": {
+			val: tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			}),
+			expectedErr: "ElementKeyValue(tftypes.String<unknown>): cannot decode unknown value into string",
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var got Set[string]
+			err := Decode(test.val, &got)
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("expected error %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,459 @@
+// Command asgotypes-gen generates FromTerraform5Value and ToTerraform5Value
+// methods for structs tagged for the asgotypes package, so they're decoded
+// and encoded without reflection. It's meant to be run by go generate:
+//
+//	//go:generate go run github.com/hashicorp/terraform-plugin-go-contrib/cmd/asgotypes-gen -type Server,Disk
+//
+// The structs named by -type are read from the package in the current
+// directory, and the methods are written to asgotypes_gen.go, or the file
+// named by -output.
+//
+// The object type each struct maps to is written as a package-level
+// variable, named by formatting the struct's name with -typevar, so
+// ServerType by default, for building schemas and values that stay in sync
+// with the structs. With -methods=false, only the variables are written,
+// for structs that are decoded and encoded with reflection.
+//
+// The generated methods decode and encode values like an asgotypes.Decoder
+// and asgotypes.Encoder with the default settings, and since they implement
+// tftypes.ValueConverter and tftypes.ValueCreator, Decoders and Encoders
+// use them too, ignoring their own settings for the generated types.
+//
+// Because tftypes.ValueCreator isn't told the type to encode a value as,
+// ToTerraform5Value encodes structs as the object type their fields map to:
+// strings, bools, and numbers as their primitive types, slices as lists,
+// asgotypes.Sets as sets, maps as maps, tftypes.Values as dynamic values,
+// and the other structs named by -type as their own object types. Those are
+// the only field types supported; fields of other types, and fields with
+// tag options, are an error.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	asgotypesPath = "github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	tftypesPath   = "github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// genType is a struct the methods are generated for.
+type genType struct {
+	Name    string
+	TypeVar string
+	Fields  []genField
+	Vars    []genVar
+}
+
+// genField is a field of a genType, mapped to an object attribute.
+type genField struct {
+	GoName string
+	Attr   string
+	Type   string
+	Decode string
+	Encode string
+}
+
+// genVar is a package-level variable holding a DecodeFunc or EncodeFunc,
+// so they're only built once.
+type genVar struct {
+	Name  string
+	Value string
+}
+
+// codec is how a Go type is decoded and encoded: the expressions of its
+// DecodeFunc and EncodeFunc, and of its tftypes.Type.
+type codec struct {
+	decode, encode, typ string
+}
+
+// generator resolves the Go types of fields in a package.
+type generator struct {
+	// types are the names of the structs being generated.
+	types map[string]bool
+
+	// typeVar is the format of the names of the variables holding their
+	// object types.
+	typeVar string
+
+	// imports maps the names packages are imported as in the file being
+	// read to their paths.
+	imports map[string]string
+}
+
+var tmpl = template.Must(template.New("").Parse(`// Code generated by asgotypes-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+{{- if .Methods }}
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+{{- end }}
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+{{ $methods := .Methods }}{{ range .Types }}
+// {{ .TypeVar }} is the object type the tagged fields of {{ .Name }} map to.
+var {{ .TypeVar }} = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+{{- range .Fields }}
+	{{ printf "%q" .Attr }}: {{ .Type }},
+{{- end }}
+}}
+{{ if $methods }}{{ if .Vars }}
+var (
+{{- range .Vars }}
+	{{ .Name }} = {{ .Value }}
+{{- end }}
+)
+{{ end }}
+// FromTerraform5Value decodes ` + "`val`" + ` into the {{ .Name }} like an
+// asgotypes.Decoder with the default settings would.
+func (v *{{ .Name }}) FromTerraform5Value(val tftypes.Value) error {
+	path := tftypes.NewAttributePath()
+	attrs, err := asgotypes.DecodeObject[{{ .Name }}](path, val)
+	if err != nil {
+		return err
+	}
+	if attrs == nil {
+		*v = {{ .Name }}{}
+		return nil
+	}
+{{- range .Fields }}
+	if attr, ok := attrs[{{ printf "%q" .Attr }}]; ok {
+		if v.{{ .GoName }}, err = {{ .Decode }}(path.WithAttributeName({{ printf "%q" .Attr }}), attr); err != nil {
+			return err
+		}
+	}
+{{- end }}
+	return nil
+}
+
+// ToTerraform5Value encodes the {{ .Name }} as an object like an
+// asgotypes.Encoder with the default settings would.
+func (v {{ .Name }}) ToTerraform5Value() (interface{}, error) {
+	path := tftypes.NewAttributePath()
+	attrs := make(map[string]tftypes.Value, {{ len .Fields }})
+	var err error
+{{- $typeVar := .TypeVar }}
+{{- range .Fields }}
+	if attrs[{{ printf "%q" .Attr }}], err = {{ .Encode }}(path.WithAttributeName({{ printf "%q" .Attr }}), {{ $typeVar }}.AttributeTypes[{{ printf "%q" .Attr }}], v.{{ .GoName }}); err != nil {
+		return nil, err
+	}
+{{- end }}
+	return attrs, nil
+}
+{{ end }}{{ end }}`))
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("asgotypes-gen: ")
+	typeNames := flag.String("type", "", "comma-separated list of struct type names; required")
+	output := flag.String("output", "asgotypes_gen.go", "output file name")
+	typeVar := flag.String("typevar", "%sType", "format of the names of the object type variables")
+	methods := flag.Bool("methods", true, "generate FromTerraform5Value and ToTerraform5Value methods")
+	flag.Parse()
+	if *typeNames == "" || strings.Count(*typeVar, "%s") != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	src, err := generate(".", strings.Split(*typeNames, ","), options{
+		output:  *output,
+		typeVar: *typeVar,
+		methods: *methods,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// options are the settings of a call to generate.
+type options struct {
+	// output is the name of the file being generated, which is ignored
+	// when reading the package.
+	output string
+
+	// typeVar is the format of the names of the object type variables.
+	typeVar string
+
+	// methods, if true, generates methods as well as variables.
+	methods bool
+}
+
+// generate returns the generated source for the structs named `types` in the
+// package in `dir`.
+func generate(dir string, types []string, opts options) ([]byte, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{types: map[string]bool{}, typeVar: opts.typeVar}
+	for _, t := range types {
+		g.types[t] = true
+	}
+	fset := token.NewFileSet()
+	var pkg string
+	found := map[string]genType{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || filepath.Base(file) == opts.output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		pkg = f.Name.Name
+		g.imports = map[string]string{}
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := path[strings.LastIndex(path, "/")+1:]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			g.imports[name] = path
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if !g.types[ts.Name.Name] {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || ts.TypeParams != nil {
+					return nil, fmt.Errorf("%s: %s is not a struct type", fset.Position(ts.Pos()), ts.Name.Name)
+				}
+				gt, err := g.genType(ts.Name.Name, st)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", fset.Position(ts.Pos()), err)
+				}
+				found[gt.Name] = gt
+			}
+		}
+	}
+	data := struct {
+		Package string
+		Methods bool
+		Types   []genType
+	}{Package: pkg, Methods: opts.methods}
+	for _, t := range types {
+		gt, ok := found[t]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in %s", t, dir)
+		}
+		data.Types = append(data.Types, gt)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// genType returns the fields of the struct `name` that map to attributes,
+// following the same rules as asgotypes.
+func (g *generator) genType(name string, st *ast.StructType) (genType, error) {
+	gt := genType{Name: name, TypeVar: fmt.Sprintf(g.typeVar, name)}
+	attrs := map[string]bool{}
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		tag, _ := strconv.Unquote(f.Tag.Value)
+		attr, ok := reflect.StructTag(tag).Lookup("tfsdk")
+		if !ok || attr == "-" {
+			continue
+		}
+		names := f.Names
+		if len(names) == 0 {
+			// embedded fields are named after their type
+			names = []*ast.Ident{ast.NewIdent(embeddedName(f.Type))}
+		}
+		if len(names) > 1 {
+			return gt, fmt.Errorf("fields %s of %s share a tfsdk tag", identList(names), name)
+		}
+		goName := names[0].Name
+		switch {
+		case attr == "":
+			return gt, fmt.Errorf("field %s of %s has an empty tfsdk tag", goName, name)
+		case strings.Contains(attr, ","):
+			return gt, fmt.Errorf("field %s of %s has tag options, which aren't supported", goName, name)
+		case !ast.IsExported(goName):
+			return gt, fmt.Errorf("field %s of %s is unexported but has a tfsdk tag", goName, name)
+		case attrs[attr]:
+			return gt, fmt.Errorf("%s has more than one field tagged %q", name, attr)
+		}
+		attrs[attr] = true
+		c, err := g.codec(f.Type)
+		if err != nil {
+			return gt, fmt.Errorf("field %s of %s: %w", goName, name, err)
+		}
+		field := genField{GoName: goName, Attr: attr, Type: c.typ, Decode: c.decode, Encode: c.encode}
+		if strings.Contains(c.decode, "(") {
+			// build DecodeFuncs and EncodeFuncs that take arguments once
+			field.Decode = fmt.Sprintf("_%s_%s_decode", name, goName)
+			field.Encode = fmt.Sprintf("_%s_%s_encode", name, goName)
+			gt.Vars = append(gt.Vars, genVar{Name: field.Decode, Value: c.decode}, genVar{Name: field.Encode, Value: c.encode})
+		}
+		gt.Fields = append(gt.Fields, field)
+	}
+	sort.Slice(gt.Vars, func(i, j int) bool { return gt.Vars[i].Name < gt.Vars[j].Name })
+	return gt, nil
+}
+
+// primitives are the codecs of Go's primitive types.
+var primitives = map[string]codec{
+	"string": {"asgotypes.DecodeString[string]", "asgotypes.EncodeString[string]", "tftypes.String"},
+	"bool":   {"asgotypes.DecodeBool[bool]", "asgotypes.EncodeBool[bool]", "tftypes.Bool"},
+}
+
+func init() {
+	for _, t := range []string{"int", "int8", "int16", "int32", "int64"} {
+		primitives[t] = codec{"asgotypes.DecodeInt[" + t + "]", "asgotypes.EncodeInt[" + t + "]", "tftypes.Number"}
+	}
+	for _, t := range []string{"uint", "uint8", "uint16", "uint32", "uint64", "uintptr"} {
+		primitives[t] = codec{"asgotypes.DecodeUint[" + t + "]", "asgotypes.EncodeUint[" + t + "]", "tftypes.Number"}
+	}
+	for _, t := range []string{"float32", "float64"} {
+		primitives[t] = codec{"asgotypes.DecodeFloat[" + t + "]", "asgotypes.EncodeFloat[" + t + "]", "tftypes.Number"}
+	}
+}
+
+// codec returns the codec of the Go type `expr`.
+func (g *generator) codec(expr ast.Expr) (codec, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if c, ok := primitives[e.Name]; ok {
+			return c, nil
+		}
+		if g.types[e.Name] {
+			return codec{
+				decode: "asgotypes.ConverterDecoder[" + e.Name + "]()",
+				encode: "asgotypes.ConverterEncoder[" + e.Name + "]()",
+				typ:    fmt.Sprintf(g.typeVar, e.Name),
+			}, nil
+		}
+	case *ast.StarExpr:
+		elem, err := g.codec(e.X)
+		if err != nil {
+			return codec{}, err
+		}
+		return codec{
+			decode: "asgotypes.PointerDecoder(" + elem.decode + ")",
+			encode: "asgotypes.PointerEncoder(" + elem.encode + ")",
+			typ:    elem.typ,
+		}, nil
+	case *ast.ArrayType:
+		if e.Len != nil {
+			break
+		}
+		elem, err := g.codec(e.Elt)
+		if err != nil {
+			return codec{}, err
+		}
+		return codec{
+			decode: "asgotypes.SliceDecoder(" + elem.decode + ")",
+			encode: "asgotypes.SliceEncoder(" + elem.encode + ")",
+			typ:    "tftypes.List{ElementType: " + elem.typ + "}",
+		}, nil
+	case *ast.MapType:
+		if key, ok := e.Key.(*ast.Ident); !ok || key.Name != "string" {
+			break
+		}
+		elem, err := g.codec(e.Value)
+		if err != nil {
+			return codec{}, err
+		}
+		return codec{
+			decode: "asgotypes.MapDecoder(" + elem.decode + ")",
+			encode: "asgotypes.MapEncoder(" + elem.encode + ")",
+			typ:    "tftypes.Map{ElementType: " + elem.typ + "}",
+		}, nil
+	case *ast.SelectorExpr:
+		switch g.qualifiedName(e) {
+		case "math/big.Float":
+			return codec{"asgotypes.DecodeBigFloat", "asgotypes.EncodeBigFloat", "tftypes.Number"}, nil
+		case "math/big.Int":
+			return codec{"asgotypes.DecodeBigInt", "asgotypes.EncodeBigInt", "tftypes.Number"}, nil
+		case tftypesPath + ".Value":
+			return codec{"asgotypes.DecodeValue", "asgotypes.EncodeValue", "tftypes.DynamicPseudoType"}, nil
+		}
+	case *ast.IndexExpr:
+		sel, ok := e.X.(*ast.SelectorExpr)
+		if !ok || g.qualifiedName(sel) != asgotypesPath+".Set" {
+			break
+		}
+		// sets are encoded in order, so their elements must be ordered
+		if elem, ok := e.Index.(*ast.Ident); ok && elem.Name != "bool" {
+			if c, ok := primitives[elem.Name]; ok {
+				return codec{
+					decode: "asgotypes.SetDecoder(" + c.decode + ")",
+					encode: "asgotypes.SetEncoder(" + c.encode + ")",
+					typ:    "tftypes.Set{ElementType: " + c.typ + "}",
+				}, nil
+			}
+		}
+	}
+	return codec{}, fmt.Errorf("unsupported type %s", exprString(expr))
+}
+
+// qualifiedName returns the name of the type `sel` refers to, qualified by
+// the path of its package.
+func (g *generator) qualifiedName(sel *ast.SelectorExpr) string {
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return g.imports[x.Name] + "." + sel.Sel.Name
+}
+
+// embeddedName returns the name of an embedded field of type `expr`.
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+func identList(idents []*ast.Ident) string {
+	names := make([]string, 0, len(idents))
+	for _, id := range idents {
+		names = append(names, id.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), expr); err != nil {
+		return fmt.Sprintf("%T", expr)
+	}
+	return buf.String()
+}
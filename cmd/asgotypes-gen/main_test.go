@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var defaultOptions = options{output: "asgotypes_gen.go", typeVar: "%sType", methods: true}
+
+func TestGenerateUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..", "asgotypes", "internal", "gentest")
+	got, err := generate(dir, []string{"Server", "Disk", "Numbers"}, defaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected, err := os.ReadFile(filepath.Join(dir, "asgotypes_gen.go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != string(expected) {
+		t.Errorf("%s/asgotypes_gen.go is out of date, run go generate", dir)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	type testCase struct {
+		src         string
+		types       []string
+		opts        options
+		expected    []string
+		unexpected  []string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"embedded": {
+			src: `type Base struct {
+	ID string ` + "`tfsdk:\"id\"`" + `
+}
+
+type Server struct {
+	Base ` + "`tfsdk:\"base\"`" + `
+	Name string ` + "`tfsdk:\"name\"`" + `
+	Skip string ` + "`tfsdk:\"-\"`" + `
+	Untagged string
+}`,
+			types: []string{"Server", "Base"},
+			opts:  defaultOptions,
+			expected: []string{
+				`var BaseType = tftypes.Object{`,
+				`"base": BaseType,`,
+				`_Server_Base_decode = asgotypes.ConverterDecoder[Base]()`,
+				`if v.Name, err = asgotypes.DecodeString[string](path.WithAttributeName("name"), attr); err != nil {`,
+			},
+		},
+		"typevar": {
+			src:   "type Disk struct {\n\tSize int `tfsdk:\"size\"`\n}\n\ntype Server struct {\n\tDisk *Disk `tfsdk:\"disk\"`\n}",
+			types: []string{"Server", "Disk"},
+			opts:  options{output: "asgotypes_gen.go", typeVar: "tf%sType", methods: true},
+			expected: []string{
+				`var tfServerType = tftypes.Object{`,
+				`"disk": tfDiskType,`,
+				`tfServerType.AttributeTypes["disk"]`,
+			},
+		},
+		"types-only": {
+			src:   "type Server struct {\n\tName string `tfsdk:\"name\"`\n}",
+			types: []string{"Server"},
+			opts:  options{output: "asgotypes_gen.go", typeVar: "%sType"},
+			expected: []string{
+				`var ServerType = tftypes.Object{`,
+				`"name": tftypes.String,`,
+			},
+			unexpected: []string{
+				"terraform-plugin-go-contrib/asgotypes",
+				"FromTerraform5Value",
+				"ToTerraform5Value",
+			},
+		},
+		"unsupported-type": {
+			src:         "type Server struct {\n\tPorts [2]int `tfsdk:\"ports\"`\n}",
+			types:       []string{"Server"},
+			opts:        defaultOptions,
+			expectedErr: "field Ports of Server: unsupported type [2]int",
+		},
+		"ungenerated-struct": {
+			src:         "type Disk struct{}\n\ntype Server struct {\n\tDisk *Disk `tfsdk:\"disk\"`\n}",
+			types:       []string{"Server"},
+			opts:        defaultOptions,
+			expectedErr: "field Disk of Server: unsupported type Disk",
+		},
+		"unordered-set": {
+			src:         "type Server struct {\n\tFlags asgotypes.Set[bool] `tfsdk:\"flags\"`\n}",
+			types:       []string{"Server"},
+			opts:        defaultOptions,
+			expectedErr: "field Flags of Server: unsupported type asgotypes.Set[bool]",
+		},
+		"tag-options": {
+			src:         "type Server struct {\n\tName *string `tfsdk:\"name,emptyasnull\"`\n}",
+			types:       []string{"Server"},
+			opts:        defaultOptions,
+			expectedErr: "field Name of Server has tag options, which aren't supported",
+		},
+		"unexported": {
+			src:         "type Server struct {\n\tname string `tfsdk:\"name\"`\n}",
+			types:       []string{"Server"},
+			opts:        defaultOptions,
+			expectedErr: "field name of Server is unexported but has a tfsdk tag",
+		},
+		"duplicate": {
+			src:         "type Server struct {\n\tA string `tfsdk:\"name\"`\n\tB string `tfsdk:\"name\"`\n}",
+			types:       []string{"Server"},
+			opts:        defaultOptions,
+			expectedErr: `Server has more than one field tagged "name"`,
+		},
+		"not-struct": {
+			src:         "type Server string",
+			types:       []string{"Server"},
+			opts:        defaultOptions,
+			expectedErr: "Server is not a struct type",
+		},
+		"missing": {
+			src:         "type Server struct{}",
+			types:       []string{"Disk"},
+			opts:        defaultOptions,
+			expectedErr: "type Disk not found in",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := "package example\n\nimport \"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes\"\n\nvar _ asgotypes.Set[int]\n\n" + tc.src + "\n"
+			if err := os.WriteFile(filepath.Join(dir, "types.go"), []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := generate(dir, tc.types, tc.opts)
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Errorf("expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for _, s := range tc.expected {
+				if !strings.Contains(string(got), s) {
+					t.Errorf("expected generated code to contain %q, got:\n%s", s, got)
+				}
+			}
+			for _, s := range tc.unexpected {
+				if strings.Contains(string(got), s) {
+					t.Errorf("expected generated code not to contain %q, got:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,890 @@
+// Command sdkv2-convert converts the schemas of a provider built with
+// terraform-plugin-sdk/v2 into tfprotov5.Schemas, and into skeletons of
+// structs tagged for the asgotypes and tfplan packages, as a starting point
+// for moving the provider onto terraform-plugin-go:
+//
+//	sdkv2-convert -package provider -output schemas.go ./internal/provider
+//
+// The package in the directory given is read without being compiled, so the
+// SDK doesn't need to be a dependency of the module it's run in. Schemas
+// are found in functions returning a *schema.Resource or *schema.Provider
+// literal. Resources and data sources are named after their type names in
+// the provider's ResourcesMap and DataSourcesMap, or after the functions
+// returning them if there's no provider.
+//
+// Only the parts of a schema.Schema that affect the schema are converted:
+// Type, Elem, Required, Optional, Computed, Sensitive, Description,
+// Deprecated, MinItems, MaxItems, and ConfigMode. ForceNew and Default are
+// converted to tfplan struct tags. Everything else, like ValidateFunc and
+// ConflictsWith, and schemas that aren't literals, are listed in the doc
+// comment of each converted schema, to be ported by hand.
+//
+// The output is meant to be edited, and isn't regenerated.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+)
+
+const schemaPath = "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sdkv2-convert: ")
+	pkg := flag.String("package", "", "package name of the output; defaults to that of the input")
+	output := flag.String("output", "", "output file name; defaults to standard output")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: sdkv2-convert [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	dir := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	src, err := convert(dir, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*output, src, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// kind is what a converted schema is the schema of.
+type kind int
+
+const (
+	kindResource kind = iota
+	kindDataSource
+	kindProvider
+)
+
+// resource is a schema.Resource or schema.Provider literal returned by a
+// function.
+type resource struct {
+	funcName string
+	lit      *ast.CompositeLit
+	isProv   bool
+}
+
+// converted is a converted schema, and the Go name its schema variable and
+// struct are named after.
+type converted struct {
+	goName   string
+	source   string
+	typeName string
+	version  string
+	block    *block
+	notes    []string
+}
+
+// block is a converted schema.Resource, as a tfprotov5.SchemaBlock.
+type block struct {
+	description string
+	deprecated  bool
+	attrs       []*attribute
+	blocks      []*nestedBlock
+}
+
+// attribute is a converted schema.Schema that's an attribute.
+type attribute struct {
+	name        string
+	typ         string
+	goType      string
+	required    bool
+	optional    bool
+	computed    bool
+	sensitive   bool
+	deprecated  bool
+	description string
+	plan        []string
+}
+
+// nestedBlock is a converted schema.Schema that's a nested block.
+type nestedBlock struct {
+	name     string
+	nesting  string
+	minItems string
+	maxItems string
+	block    *block
+	goType   string
+	plan     []string
+}
+
+// converter converts the schemas of one package.
+type converter struct {
+	fset *token.FileSet
+
+	// schemaName is the name the SDK's schema package is imported as in
+	// the file being read.
+	schemaName string
+
+	// structs are the struct types to declare, in order.
+	structs []goStruct
+
+	// usesSet records whether any struct has an asgotypes.Set field.
+	usesSet bool
+
+	// notes are the parts of the schema being converted that weren't.
+	notes []string
+}
+
+// goStruct is a struct type to declare.
+type goStruct struct {
+	name   string
+	doc    string
+	fields []goField
+}
+
+type goField struct {
+	name string
+	typ  string
+	tag  string
+}
+
+// convert returns the converted schemas of the package in `dir`, as the
+// source of a file in the package `pkg`.
+func convert(dir, pkg string) ([]byte, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	c := &converter{fset: token.NewFileSet()}
+	var (
+		resources []resource
+		srcPkg    string
+		schemaFor = map[*ast.CompositeLit]string{}
+	)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(c.fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		srcPkg = f.Name.Name
+		name := importName(f, schemaPath)
+		if name == "" {
+			continue
+		}
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Body == nil {
+				continue
+			}
+			lit, typeName := returnedLiteral(fd.Body, name)
+			if lit == nil {
+				continue
+			}
+			resources = append(resources, resource{funcName: fd.Name.Name, lit: lit, isProv: typeName == "Provider"})
+			schemaFor[lit] = name
+		}
+	}
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("no schema.Resource or schema.Provider literals found in %s", dir)
+	}
+	if pkg == "" {
+		pkg = srcPkg
+	}
+
+	// name resources and data sources after their type names, if there's
+	// a provider to find them in
+	typeNames := map[string]string{}
+	kinds := map[string]kind{}
+	for _, r := range resources {
+		if !r.isProv {
+			continue
+		}
+		for _, kv := range r.lit.Elts {
+			kv, ok := kv.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			var k kind
+			switch keyName(kv.Key) {
+			case "ResourcesMap":
+				k = kindResource
+			case "DataSourcesMap":
+				k = kindDataSource
+			default:
+				continue
+			}
+			m, ok := unparen(kv.Value).(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			for _, elt := range m.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				typeName, ok := stringLit(kv.Key)
+				call, isCall := unparen(kv.Value).(*ast.CallExpr)
+				if !ok || !isCall {
+					continue
+				}
+				if fn, ok := call.Fun.(*ast.Ident); ok {
+					typeNames[fn.Name] = typeName
+					kinds[fn.Name] = k
+				}
+			}
+		}
+	}
+
+	var out []converted
+	goNames := map[string]string{}
+	for _, r := range resources {
+		c.schemaName = schemaFor[r.lit]
+		c.notes = nil
+		k, ok := kinds[r.funcName]
+		switch {
+		case r.isProv:
+			k = kindProvider
+		case !ok && strings.HasPrefix(r.funcName, "data"):
+			k = kindDataSource
+		}
+		goName := goNameFor(r.funcName, typeNames[r.funcName], k)
+		if prev, ok := goNames[goName]; ok {
+			return nil, fmt.Errorf("%s and %s would both be converted to %s", prev, r.funcName, goName)
+		}
+		goNames[goName] = r.funcName
+		conv := converted{goName: goName, source: r.funcName, typeName: typeNames[r.funcName]}
+		conv.block, conv.version = c.resource(goName, r.lit, k)
+		conv.notes = c.notes
+		out = append(out, conv)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].goName < out[j].goName })
+	sort.SliceStable(c.structs, func(i, j int) bool { return c.structs[i].name < c.structs[j].name })
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Converted from terraform-plugin-sdk/v2 schemas by sdkv2-convert.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n", pkg)
+	if c.usesSet {
+		fmt.Fprintf(&buf, "\t\"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes\"\n")
+	}
+	fmt.Fprintf(&buf, "\t\"github.com/hashicorp/terraform-plugin-go/tfprotov5\"\n\t\"github.com/hashicorp/terraform-plugin-go/tftypes\"\n)\n")
+	for _, conv := range out {
+		writeSchema(&buf, conv)
+	}
+	for _, s := range c.structs {
+		writeStruct(&buf, s)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting converted code: %w", err)
+	}
+	return src, nil
+}
+
+// returnedLiteral returns the schema.Resource or schema.Provider literal
+// returned by the function with body `body`, and the name of its type.
+// `name` is the name the schema package is imported as.
+func returnedLiteral(body *ast.BlockStmt, name string) (*ast.CompositeLit, string) {
+	var (
+		lit      *ast.CompositeLit
+		typeName string
+	)
+	for _, stmt := range body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		expr := unparen(ret.Results[0])
+		if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+			expr = unparen(u.X)
+		}
+		cl, ok := expr.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		switch t := selectorName(cl.Type, name); t {
+		case "Resource", "Provider":
+			lit, typeName = cl, t
+		}
+	}
+	return lit, typeName
+}
+
+// resource converts the schema.Resource or schema.Provider literal `lit`,
+// returning its block and schema version.
+func (c *converter) resource(goName string, lit *ast.CompositeLit, k kind) (*block, string) {
+	b := &block{}
+	version := "0"
+	var schemaMap ast.Expr
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		switch key := keyName(kv.Key); key {
+		case "Schema":
+			schemaMap = kv.Value
+		case "SchemaVersion":
+			if v, ok := intLit(kv.Value); ok {
+				version = v
+			} else {
+				c.note("SchemaVersion")
+			}
+		case "Description":
+			if s, ok := stringLit(kv.Value); ok {
+				b.description = s
+			} else {
+				c.note("Description")
+			}
+		case "DeprecationMessage":
+			b.deprecated = true
+		case "Timeouts", "StateUpgraders", "CustomizeDiff", "Importer":
+			c.note(key)
+		}
+	}
+	c.block(goName, nil, b, schemaMap)
+	if k != kindProvider && !b.has("id") {
+		// the SDK adds an id attribute to every resource and data source
+		b.attrs = append(b.attrs, &attribute{name: "id", typ: "tftypes.String", goType: "*string", optional: true, computed: true})
+	}
+	b.sort()
+	s := goStruct{name: goName, doc: fmt.Sprintf("%s holds values of %sSchema.", goName, goName)}
+	for _, a := range b.attrs {
+		s.fields = append(s.fields, goField{name: fieldName(a.name), typ: a.goType, tag: tag(a.name, a.plan)})
+	}
+	for _, nb := range b.blocks {
+		s.fields = append(s.fields, goField{name: fieldName(nb.name), typ: nb.goType, tag: tag(nb.name, nb.plan)})
+	}
+	c.structs = append(c.structs, s)
+	return b, version
+}
+
+// block converts the map of schema.Schemas `schemaMap` into the attributes
+// and nested blocks of `b`. `path` is the path to the block, for notes.
+func (c *converter) block(goName string, path []string, b *block, schemaMap ast.Expr) {
+	if schemaMap == nil {
+		return
+	}
+	m, ok := unparen(schemaMap).(*ast.CompositeLit)
+	if !ok {
+		c.note(strings.Join(append(path, "Schema"), "."))
+		return
+	}
+	for _, elt := range m.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		name, ok := stringLit(kv.Key)
+		if !ok {
+			c.note(strings.Join(append(path, exprString(kv.Key)), "."))
+			continue
+		}
+		attrPath := append(append([]string(nil), path...), name)
+		s := schemaLiteral(kv.Value, c.schemaName, "Schema")
+		if s == nil {
+			c.note(strings.Join(attrPath, "."))
+			continue
+		}
+		c.schema(goName, attrPath, b, s)
+	}
+}
+
+// schema converts the schema.Schema literal `s`, at `path`, adding it to
+// `b` as an attribute or nested block.
+func (c *converter) schema(goName string, path []string, b *block, s *ast.CompositeLit) {
+	name := path[len(path)-1]
+	fields := map[string]ast.Expr{}
+	for _, elt := range s.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		fields[keyName(kv.Key)] = kv.Value
+	}
+	a := &attribute{name: name}
+	var (
+		minItems, maxItems = "0", "0"
+		attrMode           bool
+	)
+	for _, key := range sorted.Keys(fields) {
+		v := fields[key]
+		ok := true
+		switch key {
+		case "Type", "Elem":
+		case "Required":
+			a.required, ok = boolLit(v)
+		case "Optional":
+			a.optional, ok = boolLit(v)
+		case "Computed":
+			a.computed, ok = boolLit(v)
+		case "Sensitive":
+			a.sensitive, ok = boolLit(v)
+		case "Description":
+			a.description, ok = stringLit(v)
+		case "Deprecated":
+			var msg string
+			msg, ok = stringLit(v)
+			a.deprecated = msg != ""
+		case "MinItems":
+			minItems, ok = intLit(v)
+		case "MaxItems":
+			maxItems, ok = intLit(v)
+		case "ForceNew":
+			var forceNew bool
+			if forceNew, ok = boolLit(v); forceNew {
+				a.plan = append(a.plan, "requires_replace")
+			}
+		case "Default":
+			var def string
+			if def, ok = defaultLit(v); ok {
+				// Terraform only accepts planned defaults for computed
+				// attributes
+				a.plan = append(a.plan, "default="+def)
+				a.computed = true
+			}
+		case "ConfigMode":
+			attrMode = selectorName(v, c.schemaName) == "SchemaConfigModeAttr"
+			ok = attrMode || selectorName(v, c.schemaName) != ""
+		default:
+			ok = false
+		}
+		if !ok {
+			c.note(key + " of " + strings.Join(path, "."))
+		}
+	}
+
+	typ := selectorName(fields["Type"], c.schemaName)
+	elem := unparen(fields["Elem"])
+	if u, ok := elem.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		elem = unparen(u.X)
+	}
+	elemLit, _ := elem.(*ast.CompositeLit)
+	elemType := ""
+	if elemLit != nil {
+		elemType = selectorName(elemLit.Type, c.schemaName)
+	}
+
+	nestedName := goName + fieldName(name)
+	if (typ == "TypeList" || typ == "TypeSet") && elemType == "Resource" {
+		nb := &block{}
+		for _, elt := range elemLit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok && keyName(kv.Key) == "Schema" {
+				c.block(nestedName, path, nb, kv.Value)
+			}
+		}
+		nb.sort()
+		s := goStruct{name: nestedName}
+		for _, na := range nb.attrs {
+			s.fields = append(s.fields, goField{name: fieldName(na.name), typ: na.goType, tag: tag(na.name, na.plan)})
+		}
+		for _, nnb := range nb.blocks {
+			s.fields = append(s.fields, goField{name: fieldName(nnb.name), typ: nnb.goType, tag: tag(nnb.name, nnb.plan)})
+		}
+		c.structs = append(c.structs, s)
+		if attrMode || (a.computed && !a.optional) {
+			// the SDK converts computed-only nested resources, and
+			// those in attribute mode, to attributes
+			a.typ = collectionType(typ, objectType(nb))
+			a.goType = "[]" + nestedName
+			b.attrs = append(b.attrs, a)
+			return
+		}
+		nesting := "tfprotov5.SchemaNestedBlockNestingModeList"
+		if typ == "TypeSet" {
+			nesting = "tfprotov5.SchemaNestedBlockNestingModeSet"
+		}
+		if a.required && minItems == "0" {
+			minItems = "1"
+		}
+		nb.description = a.description
+		nb.deprecated = a.deprecated
+		b.blocks = append(b.blocks, &nestedBlock{
+			name:     name,
+			nesting:  nesting,
+			minItems: minItems,
+			maxItems: maxItems,
+			block:    nb,
+			goType:   "[]" + nestedName,
+			plan:     a.plan,
+		})
+		return
+	}
+
+	var ok bool
+	a.typ, a.goType, ok = c.attributeType(typ, elemLit, elemType)
+	if !ok {
+		c.note("Type of " + strings.Join(path, "."))
+		a.typ, a.goType = "tftypes.DynamicPseudoType", "tftypes.Value"
+	}
+	if !a.required && !strings.HasPrefix(a.goType, "[]") && !strings.HasPrefix(a.goType, "map[") && !strings.HasPrefix(a.goType, "asgotypes.") && a.goType != "tftypes.Value" {
+		a.goType = "*" + a.goType
+	}
+	b.attrs = append(b.attrs, a)
+}
+
+// primitiveTypes maps the SDK's primitive types to tftypes and Go types.
+var primitiveTypes = map[string][2]string{
+	"TypeString": {"tftypes.String", "string"},
+	"TypeInt":    {"tftypes.Number", "int64"},
+	"TypeFloat":  {"tftypes.Number", "float64"},
+	"TypeBool":   {"tftypes.Bool", "bool"},
+}
+
+// attributeType returns the tftypes and Go types of an attribute of the
+// SDK type `typ`, with the element `elemLit`, a schema.Schema or
+// schema.Resource literal named `elemType`, if it's a collection.
+func (c *converter) attributeType(typ string, elemLit *ast.CompositeLit, elemType string) (string, string, bool) {
+	if t, ok := primitiveTypes[typ]; ok {
+		return t[0], t[1], true
+	}
+	elem := primitiveTypes["TypeString"]
+	switch elemType {
+	case "Schema":
+		var elemTyp string
+		for _, e := range elemLit.Elts {
+			if kv, ok := e.(*ast.KeyValueExpr); ok && keyName(kv.Key) == "Type" {
+				elemTyp = selectorName(kv.Value, c.schemaName)
+			}
+		}
+		var ok bool
+		if elem, ok = primitiveTypes[elemTyp]; !ok {
+			return "", "", false
+		}
+	case "Resource":
+		// maps of resources are maps of strings in the SDK
+		if typ != "TypeMap" {
+			return "", "", false
+		}
+	case "":
+		if elemLit != nil || typ != "TypeMap" {
+			return "", "", false
+		}
+	default:
+		return "", "", false
+	}
+	switch typ {
+	case "TypeList":
+		return collectionType(typ, elem[0]), "[]" + elem[1], true
+	case "TypeSet":
+		if elem[1] == "bool" {
+			return collectionType(typ, elem[0]), "[]bool", true
+		}
+		c.usesSet = true
+		return collectionType(typ, elem[0]), "asgotypes.Set[" + elem[1] + "]", true
+	case "TypeMap":
+		return collectionType(typ, elem[0]), "map[string]" + elem[1], true
+	}
+	return "", "", false
+}
+
+func collectionType(typ, elem string) string {
+	switch typ {
+	case "TypeSet":
+		return "tftypes.Set{ElementType: " + elem + "}"
+	case "TypeMap":
+		return "tftypes.Map{ElementType: " + elem + "}"
+	}
+	return "tftypes.List{ElementType: " + elem + "}"
+}
+
+// objectType returns the tftypes.Object of the values of `b`.
+func objectType(b *block) string {
+	var s strings.Builder
+	s.WriteString("tftypes.Object{AttributeTypes: map[string]tftypes.Type{")
+	for _, a := range b.attrs {
+		fmt.Fprintf(&s, "%q: %s, ", a.name, a.typ)
+	}
+	for _, nb := range b.blocks {
+		typ := "TypeList"
+		if nb.nesting == "tfprotov5.SchemaNestedBlockNestingModeSet" {
+			typ = "TypeSet"
+		}
+		fmt.Fprintf(&s, "%q: %s, ", nb.name, collectionType(typ, objectType(nb.block)))
+	}
+	s.WriteString("}}")
+	return s.String()
+}
+
+func (c *converter) note(s string) {
+	c.notes = append(c.notes, s)
+}
+
+func (b *block) has(name string) bool {
+	for _, a := range b.attrs {
+		if a.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *block) sort() {
+	sort.Slice(b.attrs, func(i, j int) bool { return b.attrs[i].name < b.attrs[j].name })
+	sort.Slice(b.blocks, func(i, j int) bool { return b.blocks[i].name < b.blocks[j].name })
+}
+
+func writeSchema(buf *bytes.Buffer, conv converted) {
+	source := conv.source
+	if conv.typeName != "" {
+		source = fmt.Sprintf("%s, the schema of %s", source, conv.typeName)
+	}
+	fmt.Fprintf(buf, "\n// %sSchema is converted from %s.\n", conv.goName, source)
+	if len(conv.notes) > 0 {
+		fmt.Fprintf(buf, "//\n// Not converted:\n//\n")
+		for _, n := range conv.notes {
+			fmt.Fprintf(buf, "//   - %s\n", n)
+		}
+	}
+	fmt.Fprintf(buf, "var %sSchema = &tfprotov5.Schema{\n", conv.goName)
+	if conv.version != "0" {
+		fmt.Fprintf(buf, "Version: %s,\n", conv.version)
+	}
+	fmt.Fprintf(buf, "Block: ")
+	writeBlock(buf, conv.block)
+	fmt.Fprintf(buf, ",\n}\n")
+}
+
+func writeBlock(buf *bytes.Buffer, b *block) {
+	fmt.Fprintf(buf, "&tfprotov5.SchemaBlock{\n")
+	if b.description != "" {
+		fmt.Fprintf(buf, "Description: %s,\n", strconv.Quote(b.description))
+	}
+	if b.deprecated {
+		fmt.Fprintf(buf, "Deprecated: true,\n")
+	}
+	if len(b.attrs) > 0 {
+		fmt.Fprintf(buf, "Attributes: []*tfprotov5.SchemaAttribute{\n")
+		for _, a := range b.attrs {
+			fmt.Fprintf(buf, "{\nName: %q,\nType: %s,\n", a.name, a.typ)
+			for _, f := range []struct {
+				name string
+				set  bool
+			}{
+				{"Required", a.required},
+				{"Optional", a.optional},
+				{"Computed", a.computed},
+				{"Sensitive", a.sensitive},
+				{"Deprecated", a.deprecated},
+			} {
+				if f.set {
+					fmt.Fprintf(buf, "%s: true,\n", f.name)
+				}
+			}
+			if a.description != "" {
+				fmt.Fprintf(buf, "Description: %s,\n", strconv.Quote(a.description))
+			}
+			fmt.Fprintf(buf, "},\n")
+		}
+		fmt.Fprintf(buf, "},\n")
+	}
+	if len(b.blocks) > 0 {
+		fmt.Fprintf(buf, "BlockTypes: []*tfprotov5.SchemaNestedBlock{\n")
+		for _, nb := range b.blocks {
+			fmt.Fprintf(buf, "{\nTypeName: %q,\nNesting: %s,\n", nb.name, nb.nesting)
+			if nb.minItems != "0" {
+				fmt.Fprintf(buf, "MinItems: %s,\n", nb.minItems)
+			}
+			if nb.maxItems != "0" {
+				fmt.Fprintf(buf, "MaxItems: %s,\n", nb.maxItems)
+			}
+			fmt.Fprintf(buf, "Block: ")
+			writeBlock(buf, nb.block)
+			fmt.Fprintf(buf, ",\n},\n")
+		}
+		fmt.Fprintf(buf, "},\n")
+	}
+	fmt.Fprintf(buf, "}")
+}
+
+func writeStruct(buf *bytes.Buffer, s goStruct) {
+	fmt.Fprintf(buf, "\n")
+	if s.doc != "" {
+		fmt.Fprintf(buf, "// %s\n", s.doc)
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", s.name)
+	for _, f := range s.fields {
+		fmt.Fprintf(buf, "%s %s `%s`\n", f.name, f.typ, f.tag)
+	}
+	fmt.Fprintf(buf, "}\n")
+}
+
+// tag returns the struct tag of the field for the attribute `name`.
+func tag(name string, plan []string) string {
+	t := fmt.Sprintf("tfsdk:%q", name)
+	if len(plan) > 0 {
+		t += fmt.Sprintf(" tfplan:%q", strings.Join(plan, ","))
+	}
+	return t
+}
+
+// initialisms are the words that are all caps in Go names.
+var initialisms = map[string]bool{
+	"api": true, "arn": true, "cpu": true, "dns": true, "http": true, "https": true,
+	"id": true, "ip": true, "json": true, "ssh": true, "tls": true, "ttl": true,
+	"uri": true, "url": true, "uuid": true,
+}
+
+// fieldName returns the Go name for the attribute `name`.
+func fieldName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		if initialisms[word] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// goNameFor returns the name of the converted schema returned by the
+// function `funcName`, for the type `typeName`, if known.
+func goNameFor(funcName, typeName string, k kind) string {
+	if k == kindProvider {
+		return "ProviderConfig"
+	}
+	name := typeName
+	if name == "" {
+		name = funcName
+		for _, prefix := range []string{"resource", "dataSource", "data"} {
+			if rest := strings.TrimPrefix(name, prefix); rest != name && rest != "" {
+				name = rest
+				break
+			}
+		}
+	}
+	name = fieldName(name)
+	if k == kindDataSource {
+		name += "DataSource"
+	}
+	return name
+}
+
+// schemaLiteral returns `expr` as a literal of the type `typeName` of the
+// schema package, imported as `name`, which may be elided in map values.
+func schemaLiteral(expr ast.Expr, name, typeName string) *ast.CompositeLit {
+	expr = unparen(expr)
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		expr = unparen(u.X)
+	}
+	cl, ok := expr.(*ast.CompositeLit)
+	if !ok || (cl.Type != nil && selectorName(cl.Type, name) != typeName) {
+		return nil
+	}
+	return cl
+}
+
+func importName(f *ast.File, path string) string {
+	for _, imp := range f.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		if p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "schema"
+	}
+	return ""
+}
+
+// selectorName returns the name selected from the package `pkg` by `expr`,
+// or "" if it doesn't select from it.
+func selectorName(expr ast.Expr, pkg string) string {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if x, ok := sel.X.(*ast.Ident); !ok || x.Name != pkg {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+func keyName(expr ast.Expr) string {
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		p, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.X
+	}
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := unparen(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+func intLit(expr ast.Expr) (string, bool) {
+	lit, ok := unparen(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return "", false
+	}
+	return lit.Value, true
+}
+
+func boolLit(expr ast.Expr) (bool, bool) {
+	id, ok := unparen(expr).(*ast.Ident)
+	if !ok || (id.Name != "true" && id.Name != "false") {
+		return false, false
+	}
+	return id.Name == "true", true
+}
+
+// defaultLit returns the literal `expr` as the value of a tfplan "default"
+// tag, which can't contain commas.
+func defaultLit(expr ast.Expr) (string, bool) {
+	if b, ok := boolLit(expr); ok {
+		return strconv.FormatBool(b), true
+	}
+	if s, ok := stringLit(expr); ok {
+		return s, !strings.Contains(s, ",")
+	}
+	lit, ok := unparen(expr).(*ast.BasicLit)
+	if !ok || (lit.Kind != token.INT && lit.Kind != token.FLOAT) {
+		return "", false
+	}
+	return lit.Value, true
+}
+
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), expr); err != nil {
+		return fmt.Sprintf("%T", expr)
+	}
+	return buf.String()
+}
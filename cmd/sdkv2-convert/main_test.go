@@ -0,0 +1,199 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const providerSrc = `package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"token": {Type: schema.TypeString, Optional: true, Sensitive: true},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"example_server": resourceServer(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"example_server": dataSourceServer(),
+		},
+	}
+}
+
+func resourceServer() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manages a server.",
+		SchemaVersion: 2,
+		Timeouts:      &schema.ResourceTimeout{},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 63),
+			},
+			"port":    {Type: schema.TypeInt, Optional: true, Default: 80},
+			"tags":    {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"aliases": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"disk": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 4,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"size_gb": {Type: schema.TypeFloat, Required: true},
+					},
+				},
+			},
+			"endpoint": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+			"common": commonSchema(),
+		},
+	}
+}
+
+func dataSourceServer() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id":   {Type: schema.TypeString, Required: true},
+		},
+	}
+}
+`
+
+func TestConvert(t *testing.T) {
+	type testCase struct {
+		src         string
+		pkg         string
+		expected    []string
+		unexpected  []string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"provider": {
+			src: providerSrc,
+			pkg: "converted",
+			expected: []string{
+				"package converted",
+				"// ExampleServerSchema is converted from resourceServer, the schema of example_server.",
+				"//   - Timeouts\n//   - ValidateFunc of name\n//   - common\n",
+				"\tVersion: 2,\n",
+				`Type:     tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{"host": tftypes.String}}},`,
+				"TypeName: \"disk\",\n\t\t\t\tNesting:  tfprotov5.SchemaNestedBlockNestingModeList,\n\t\t\t\tMinItems: 1,\n\t\t\t\tMaxItems: 4,",
+				"var ExampleServerDataSourceSchema = &tfprotov5.Schema{",
+				"var ProviderConfigSchema = &tfprotov5.Schema{",
+				"Aliases  asgotypes.Set[string]   `tfsdk:\"aliases\"`",
+				"Endpoint []ExampleServerEndpoint `tfsdk:\"endpoint\"`",
+				"Name     string                  `tfsdk:\"name\" tfplan:\"requires_replace\"`",
+				"Port     *int64                  `tfsdk:\"port\" tfplan:\"default=80\"`",
+				"Disk     []ExampleServerDisk     `tfsdk:\"disk\"`",
+				"SizeGb float64 `tfsdk:\"size_gb\"`",
+				"type ExampleServerDataSource struct {\n\tID string `tfsdk:\"id\"`\n}",
+				"Token *string `tfsdk:\"token\"`",
+			},
+		},
+		"function-names": {
+			src: `package provider
+
+import sdk "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func resourceExampleNetwork() *sdk.Resource {
+	return &sdk.Resource{
+		Schema: map[string]*sdk.Schema{
+			"cidrs":   {Type: sdk.TypeList, Optional: true, Elem: &sdk.Schema{Type: sdk.TypeString}, Deprecated: "use cidr"},
+			"flags":   {Type: sdk.TypeSet, Optional: true, Elem: &sdk.Schema{Type: sdk.TypeBool}},
+			"mystery": {Type: sdk.TypeList, Optional: true},
+			"rule": {
+				Type:       sdk.TypeSet,
+				Optional:   true,
+				ConfigMode: sdk.SchemaConfigModeAttr,
+				Elem: &sdk.Resource{Schema: map[string]*sdk.Schema{
+					"port": {Type: sdk.TypeInt, Required: true},
+				}},
+			},
+		},
+	}
+}
+
+func dataExampleNetwork() *sdk.Resource {
+	return &sdk.Resource{}
+}
+`,
+			expected: []string{
+				"package provider",
+				"var ExampleNetworkSchema = &tfprotov5.Schema{",
+				"var ExampleNetworkDataSourceSchema = &tfprotov5.Schema{",
+				"//   - Type of mystery\n",
+				"Cidrs   []string             `tfsdk:\"cidrs\"`",
+				"Flags   []bool               `tfsdk:\"flags\"`",
+				"Mystery tftypes.Value        `tfsdk:\"mystery\"`",
+				"Rule    []ExampleNetworkRule `tfsdk:\"rule\"`",
+				`Type:     tftypes.Set{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{"port": tftypes.Number}}},`,
+				"Deprecated: true,",
+			},
+			unexpected: []string{
+				"asgotypes",
+				"BlockTypes",
+			},
+		},
+		"duplicate-names": {
+			src: `package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func resourceServer() *schema.Resource { return &schema.Resource{} }
+
+func resource_server() *schema.Resource { return &schema.Resource{} }
+`,
+			expectedErr: "resourceServer and resource_server would both be converted to Server",
+		},
+		"no-schemas": {
+			src:         "package provider\n\nfunc Provider() {}\n",
+			expectedErr: "no schema.Resource or schema.Provider literals found in",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "provider.go"), []byte(tc.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := convert(dir, tc.pkg)
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Errorf("expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for _, s := range tc.expected {
+				if !strings.Contains(string(got), s) {
+					t.Errorf("expected converted code to contain %q, got:\n%s", s, got)
+				}
+			}
+			for _, s := range tc.unexpected {
+				if strings.Contains(string(got), s) {
+					t.Errorf("expected converted code not to contain %q, got:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,461 @@
+// Package docsgen renders the schemas of a provider as Markdown pages laid
+// out the way the Terraform Registry expects, with a page for the provider
+// at index.md and one for each resource and data source under resources/
+// and data-sources/.
+//
+// Pages start with an example configuration, generated by Example unless
+// one is given, then list each attribute and nested block with its type and
+// description, grouped into required, optional, and read-only ones, and
+// document nested blocks in sections of their own. The descriptions of the tfvalidate
+// Validators and tfplan Modifiers registered for an attribute, like
+// "Must be between 1 and 10." or "Defaults to `80`.", are added to its
+// description; see tfvalidate.Describe and tfplan.Describe.
+//
+// Pages are usually built from a tfrouter.Router with FromRouter, and
+// written from a program run by go generate:
+//
+//	//go:generate go run ./internal/docs
+//
+//	func main() {
+//		pages := docsgen.FromRouter(provider.New())
+//		if err := docsgen.Write("docs", "example", pages); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+package docsgen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfplan"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfrouter"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Kind is the kind of thing a Page documents.
+type Kind int
+
+const (
+	// Provider is the provider's own configuration.
+	Provider Kind = iota
+
+	// Resource is a resource type.
+	Resource
+
+	// DataSource is a data source type.
+	DataSource
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Provider:
+		return "Provider"
+	case Resource:
+		return "Resource"
+	case DataSource:
+		return "Data Source"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Page is the documentation of the provider, or of one resource or data
+// source type.
+type Page struct {
+	Kind Kind
+
+	// Name is the resource or data source type name, like
+	// "example_server". It's ignored for the provider's page.
+	Name string
+
+	// Subcategory groups the page in the Registry's navigation.
+	Subcategory string
+
+	// Schema is the schema being documented. The description of its
+	// block is used as the page's description.
+	Schema *tfprotov5.Schema
+
+	// Validators and Modifiers are registered for the schema's
+	// attributes, and add their descriptions to those of the attributes.
+	// Either may be nil.
+	Validators *tfvalidate.Registry
+	Modifiers  *tfplan.Registry
+
+	// Example is the configuration shown under the page's Example Usage
+	// heading. If empty, the configuration returned by Example is shown.
+	Example string
+}
+
+// Path returns the path of the page's file, relative to the docs directory,
+// for the provider named `provider`.
+func (p Page) Path(provider string) string {
+	name := strings.TrimPrefix(p.Name, provider+"_")
+	switch p.Kind {
+	case Resource:
+		return filepath.Join("resources", name+".md")
+	case DataSource:
+		return filepath.Join("data-sources", name+".md")
+	}
+	return "index.md"
+}
+
+// FromRouter returns the pages of the provider, resources, and data sources
+// of `r`, sorted by name. The Validators of handlers implementing
+// tfvalidate.Validated and the Modifiers of handlers implementing
+// tfplan.Modified are documented.
+func FromRouter(r *tfrouter.Router) []Page {
+	var pages []Page
+	if r.ProviderSchema != nil {
+		page := Page{Kind: Provider, Schema: r.ProviderSchema}
+		if v, ok := r.Provider.(tfvalidate.Validated); ok {
+			page.Validators = v.Validators()
+		}
+		pages = append(pages, page)
+	}
+	for _, name := range sorted.Keys(r.Resources) {
+		pages = append(pages, handlerPage(Resource, name, r.Resources[name], r.Resources[name].Schema()))
+	}
+	for _, name := range sorted.Keys(r.DataSources) {
+		pages = append(pages, handlerPage(DataSource, name, r.DataSources[name], r.DataSources[name].Schema()))
+	}
+	return pages
+}
+
+func handlerPage(kind Kind, name string, handler interface{}, schema *tfprotov5.Schema) Page {
+	page := Page{Kind: kind, Name: name, Schema: schema}
+	if v, ok := handler.(tfvalidate.Validated); ok {
+		page.Validators = v.Validators()
+	}
+	if m, ok := handler.(tfplan.Modified); ok {
+		page.Modifiers = m.Modifiers()
+	}
+	return page
+}
+
+// Write renders `pages` for the provider named `provider` into the
+// directory `dir`, creating it and its subdirectories if necessary and
+// replacing any existing pages.
+func Write(dir, provider string, pages []Page) error {
+	for _, page := range pages {
+		path := filepath.Join(dir, page.Path(provider))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		err = Render(f, provider, page)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Render writes `page` for the provider named `provider` to `w` as
+// Markdown.
+func Render(w io.Writer, provider string, page Page) error {
+	if page.Schema == nil || page.Schema.Block == nil {
+		return fmt.Errorf("%s %q has no schema", page.Kind, page.Name)
+	}
+	title := page.Name
+	if page.Kind == Provider {
+		title = provider
+	}
+	block := page.Schema.Block
+	r := &renderer{w: bufio.NewWriter(w), page: page}
+	r.printf("---\n")
+	r.printf("page_title: %q\n", fmt.Sprintf("%s %s - terraform-provider-%s", title, page.Kind, provider))
+	r.printf("subcategory: %q\n", page.Subcategory)
+	r.printf("description: |-\n")
+	for _, line := range strings.Split(block.Description, "\n") {
+		r.printf("%s\n", strings.TrimRight("  "+line, " "))
+	}
+	r.printf("---\n\n")
+	r.printf("# %s (%s)\n\n", title, page.Kind)
+	if block.Deprecated {
+		r.deprecation(block)
+	}
+	if block.Description != "" {
+		r.printf("%s\n\n", block.Description)
+	}
+	example := page.Example
+	if example == "" {
+		example = Example(page.Kind, title, page.Schema)
+	}
+	r.printf("## Example Usage\n\n```terraform\n%s\n```\n\n", strings.TrimRight(example, "\n"))
+	r.printf("## Schema\n")
+	r.block(tftypes.NewAttributePath(), block, "###")
+	r.nestedSections()
+	return r.w.Flush()
+}
+
+// renderer renders one Page. The sections of nested blocks and object
+// attributes are queued as they're referenced, and rendered after the
+// top-level schema.
+type renderer struct {
+	w      *bufio.Writer
+	page   Page
+	queued []nested
+}
+
+// nested is a nested block, or the block of an object attribute, waiting to
+// be documented.
+type nested struct {
+	anchor string
+	path   *tftypes.AttributePath
+	block  *tfprotov5.SchemaBlock
+}
+
+// item is an attribute or nested block of a block, and its section, if it
+// has one.
+type item struct {
+	name    string
+	line    string
+	section *nested
+}
+
+func (r *renderer) printf(format string, args ...interface{}) {
+	fmt.Fprintf(r.w, format, args...)
+}
+
+func (r *renderer) deprecation(block *tfprotov5.SchemaBlock) {
+	msg := block.DeprecationMessage
+	if msg == "" {
+		msg = "This is deprecated."
+	}
+	r.printf("~> **Deprecated** %s\n\n", msg)
+}
+
+// block documents the attributes and nested blocks of `block`, at `path`,
+// in groups with headings of level `heading`, or in labelled lists if
+// `heading` is empty.
+func (r *renderer) block(path *tftypes.AttributePath, block *tfprotov5.SchemaBlock, heading string) {
+	var required, optional, readOnly []item
+	for _, attr := range block.Attributes {
+		attrPath := path.WithAttributeName(attr.Name)
+		line, section := r.attribute(attrPath, attr)
+		it := item{name: attr.Name, line: line, section: section}
+		switch {
+		case attr.Required:
+			required = append(required, it)
+		case attr.Optional:
+			optional = append(optional, it)
+		default:
+			readOnly = append(readOnly, it)
+		}
+	}
+	for _, nb := range block.BlockTypes {
+		line, section := r.nestedBlock(path.WithAttributeName(nb.TypeName), nb)
+		it := item{name: nb.TypeName, line: line, section: section}
+		if nb.MinItems > 0 {
+			required = append(required, it)
+		} else {
+			optional = append(optional, it)
+		}
+	}
+	r.group("Required", required, heading)
+	r.group("Optional", optional, heading)
+	r.group("Read-Only", readOnly, heading)
+}
+
+func (r *renderer) group(title string, items []item, heading string) {
+	if len(items) == 0 {
+		return
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].name < items[j].name })
+	if heading != "" {
+		r.printf("\n%s %s\n\n", heading, title)
+	} else {
+		r.printf("\n%s:\n\n", title)
+	}
+	for _, it := range items {
+		r.printf("- %s\n", it.line)
+		if it.section != nil {
+			r.queued = append(r.queued, *it.section)
+		}
+	}
+}
+
+// attribute returns the line documenting `attr`, at `path`, and the section
+// documenting its attributes if it's an object.
+func (r *renderer) attribute(path *tftypes.AttributePath, attr *tfprotov5.SchemaAttribute) (string, *nested) {
+	kind := typeName(attr.Type)
+	if attr.Sensitive {
+		kind += ", Sensitive"
+	}
+	if attr.WriteOnly {
+		kind += ", Write-only"
+	}
+	if attr.Deprecated {
+		kind += ", Deprecated"
+	}
+	desc := r.description(path, attr.Description)
+	obj, ok := objectType(attr.Type)
+	if !ok {
+		return line(attr.Name, kind, desc), nil
+	}
+	section := &nested{anchor: anchorFor("nestedatt", path), path: path, block: objectBlock(obj, attr)}
+	desc = joinSentences(desc, fmt.Sprintf("(see [below for nested schema](#%s))", section.anchor))
+	return line(attr.Name, kind, desc), section
+}
+
+// objectBlock returns a block with the attributes of `obj`, which are
+// required, optional, or computed like `attr`, the attribute of that type.
+func objectBlock(obj tftypes.Object, attr *tfprotov5.SchemaAttribute) *tfprotov5.SchemaBlock {
+	block := &tfprotov5.SchemaBlock{}
+	for _, name := range sorted.Keys(obj.AttributeTypes) {
+		block.Attributes = append(block.Attributes, &tfprotov5.SchemaAttribute{
+			Name:     name,
+			Type:     obj.AttributeTypes[name],
+			Required: attr.Required,
+			Optional: attr.Optional,
+			Computed: attr.Computed,
+		})
+	}
+	return block
+}
+
+// nestedBlock returns the line documenting `nb`, at `path`, and the section
+// documenting its block.
+func (r *renderer) nestedBlock(path *tftypes.AttributePath, nb *tfprotov5.SchemaNestedBlock) (string, *nested) {
+	kind := "Block"
+	switch nb.Nesting {
+	case tfprotov5.SchemaNestedBlockNestingModeList:
+		kind = "Block List"
+	case tfprotov5.SchemaNestedBlockNestingModeSet:
+		kind = "Block Set"
+	case tfprotov5.SchemaNestedBlockNestingModeMap:
+		kind = "Block Map"
+	}
+	if nb.MinItems > 0 {
+		kind += fmt.Sprintf(", Min: %d", nb.MinItems)
+	}
+	if nb.MaxItems > 0 {
+		kind += fmt.Sprintf(", Max: %d", nb.MaxItems)
+	}
+	if nb.Block != nil && nb.Block.Deprecated {
+		kind += ", Deprecated"
+	}
+	var desc string
+	if nb.Block != nil {
+		desc = nb.Block.Description
+	}
+	desc = r.description(path, desc)
+	if nb.Block == nil {
+		return line(nb.TypeName, kind, desc), nil
+	}
+	section := &nested{anchor: anchorFor("nestedblock", path), path: path, block: nb.Block}
+	desc = joinSentences(desc, fmt.Sprintf("(see [below for nested schema](#%s))", section.anchor))
+	return line(nb.TypeName, kind, desc), section
+}
+
+// description returns `desc`, followed by the descriptions of the
+// Modifiers and Validators registered for `path`.
+func (r *renderer) description(path *tftypes.AttributePath, desc string) string {
+	desc = joinSentences(desc, tfplan.Description(r.page.Modifiers.Modifiers(path)...))
+	return joinSentences(desc, tfvalidate.Description(r.page.Validators.Validators(path)...))
+}
+
+// nestedSections renders the sections of the queued nested blocks and
+// object attributes, in the order they're referenced, including those
+// queued while rendering them.
+func (r *renderer) nestedSections() {
+	for len(r.queued) > 0 {
+		n := r.queued[0]
+		r.queued = r.queued[1:]
+		r.printf("\n<a id=%q></a>\n", n.anchor)
+		r.printf("### Nested Schema for `%s`\n", strings.Join(attrpath.Names(n.path), "."))
+		if n.block.Deprecated {
+			r.printf("\n")
+			r.deprecation(n.block)
+		}
+		r.block(n.path, n.block, "")
+	}
+}
+
+// objectType returns the object type of `typ`, or of its elements, if it's
+// an object or a collection of objects.
+func objectType(typ tftypes.Type) (tftypes.Object, bool) {
+	switch t := typ.(type) {
+	case tftypes.Object:
+		return t, true
+	case tftypes.List:
+		return objectType(t.ElementType)
+	case tftypes.Set:
+		return objectType(t.ElementType)
+	case tftypes.Map:
+		return objectType(t.ElementType)
+	}
+	return tftypes.Object{}, false
+}
+
+// typeName returns the name of `typ` used in the Registry's documentation.
+func typeName(typ tftypes.Type) string {
+	switch t := typ.(type) {
+	case tftypes.List:
+		return "List of " + typeName(t.ElementType)
+	case tftypes.Set:
+		return "Set of " + typeName(t.ElementType)
+	case tftypes.Map:
+		return "Map of " + typeName(t.ElementType)
+	case tftypes.Object:
+		return "Object"
+	case tftypes.Tuple:
+		return "Tuple"
+	}
+	switch {
+	case typ.Is(tftypes.String):
+		return "String"
+	case typ.Is(tftypes.Number):
+		return "Number"
+	case typ.Is(tftypes.Bool):
+		return "Boolean"
+	case typ.Is(tftypes.DynamicPseudoType):
+		return "Dynamic"
+	}
+	return typ.String()
+}
+
+func line(name, kind, desc string) string {
+	if desc == "" {
+		return fmt.Sprintf("`%s` (%s)", name, kind)
+	}
+	return fmt.Sprintf("`%s` (%s) %s", name, kind, desc)
+}
+
+// anchorFor returns the anchor of the section documenting the nested block
+// or object attribute at `path`.
+func anchorFor(prefix string, path *tftypes.AttributePath) string {
+	return prefix + "--" + strings.Join(attrpath.Names(path), "--")
+}
+
+// joinSentences joins `a` and `b` with a space, leaving out either if it's
+// empty, and ending `a` with a full stop if it doesn't end with
+// punctuation.
+func joinSentences(a, b string) string {
+	a = strings.TrimSpace(a)
+	if b == "" {
+		return a
+	}
+	if a == "" {
+		return b
+	}
+	if !strings.ContainsAny(a[len(a)-1:], ".!?)") {
+		a += "."
+	}
+	return a + " " + b
+}
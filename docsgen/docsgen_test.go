@@ -0,0 +1,220 @@
+package docsgen
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfplan"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfrouter"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var serverSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Description: "Manages a server.",
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Computed: true},
+			{Name: "name", Type: tftypes.String, Required: true, Description: "The name of the server"},
+			{Name: "port", Type: tftypes.Number, Optional: true, Computed: true},
+			{Name: "password", Type: tftypes.String, Optional: true, Sensitive: true},
+			{Name: "tags", Type: tftypes.Map{ElementType: tftypes.String}, Optional: true},
+			{Name: "endpoint", Type: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"host": tftypes.String,
+				"port": tftypes.Number,
+			}}, Computed: true},
+		},
+		BlockTypes: []*tfprotov5.SchemaNestedBlock{
+			{
+				TypeName: "disk",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				MinItems: 1,
+				MaxItems: 4,
+				Block: &tfprotov5.SchemaBlock{
+					Description: "A disk attached to the server.",
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "size", Type: tftypes.Number, Required: true},
+						{Name: "type", Type: tftypes.String, Optional: true, Deprecated: true},
+					},
+				},
+			},
+		},
+	},
+}
+
+func serverValidators() *tfvalidate.Registry {
+	r := &tfvalidate.Registry{}
+	r.Add(tftypes.NewAttributePath().WithAttributeName("name"), tfvalidate.StringLength(1, 63))
+	r.Add(tftypes.NewAttributePath().WithAttributeName("disk").WithElementKeyInt(0).WithAttributeName("size"), tfvalidate.IntBetween(1, 1024))
+	return r
+}
+
+func serverModifiers() *tfplan.Registry {
+	r := &tfplan.Registry{}
+	r.Add(tftypes.NewAttributePath().WithAttributeName("port"), tfplan.Default(tftypes.NewValue(tftypes.Number, big.NewFloat(80))))
+	r.Add(tftypes.NewAttributePath().WithAttributeName("name"), tfplan.RequiresReplace())
+	return r
+}
+
+const serverPage = `---
+page_title: "example_server Resource - terraform-provider-example"
+subcategory: "Compute"
+description: |-
+  Manages a server.
+---
+
+# example_server (Resource)
+
+Manages a server.
+
+## Example Usage
+
+` + "```terraform" + `
+resource "example_server" "example" {
+  name = "name"
+
+  # password = "password"
+  # port     = 0
+  # tags     = { key = "tags" }
+
+  disk {
+    size = 0
+
+    # type = "type"
+  }
+}
+` + "```" + `
+
+## Schema
+
+### Required
+
+- ` + "`disk`" + ` (Block List, Min: 1, Max: 4) A disk attached to the server. (see [below for nested schema](#nestedblock--disk))
+- ` + "`name`" + ` (String) The name of the server. Changing this forces the resource to be replaced. Must be between 1 and 63 characters long.
+
+### Optional
+
+- ` + "`password`" + ` (String, Sensitive)
+- ` + "`port`" + ` (Number) Defaults to ` + "`80`" + `.
+- ` + "`tags`" + ` (Map of String)
+
+### Read-Only
+
+- ` + "`endpoint`" + ` (Object) (see [below for nested schema](#nestedatt--endpoint))
+- ` + "`id`" + ` (String)
+
+<a id="nestedblock--disk"></a>
+### Nested Schema for ` + "`disk`" + `
+
+Required:
+
+- ` + "`size`" + ` (Number) Must be a whole number between 1 and 1024.
+
+Optional:
+
+- ` + "`type`" + ` (String, Deprecated)
+
+<a id="nestedatt--endpoint"></a>
+### Nested Schema for ` + "`endpoint`" + `
+
+Read-Only:
+
+- ` + "`host`" + ` (String)
+- ` + "`port`" + ` (Number)
+`
+
+func TestRender(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(&buf, "example", Page{
+		Kind:        Resource,
+		Name:        "example_server",
+		Subcategory: "Compute",
+		Schema:      serverSchema,
+		Validators:  serverValidators(),
+		Modifiers:   serverModifiers(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(serverPage, buf.String()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestRenderNoSchema(t *testing.T) {
+	err := Render(&bytes.Buffer{}, "example", Page{Kind: DataSource, Name: "example_image"})
+	expected := `Data Source "example_image" has no schema`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+type testResource struct {
+	tfprotov5.ResourceServer
+}
+
+func (testResource) Schema() *tfprotov5.Schema        { return serverSchema }
+func (testResource) Validators() *tfvalidate.Registry { return serverValidators() }
+func (testResource) Modifiers() *tfplan.Registry      { return serverModifiers() }
+
+type testDataSource struct {
+	tfprotov5.DataSourceServer
+}
+
+func (testDataSource) Schema() *tfprotov5.Schema {
+	return &tfprotov5.Schema{Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{{Name: "id", Type: tftypes.String, Required: true}},
+	}}
+}
+
+func TestWrite(t *testing.T) {
+	router := &tfrouter.Router{
+		ProviderSchema: &tfprotov5.Schema{Block: &tfprotov5.SchemaBlock{
+			Description: "The example provider.",
+			Attributes:  []*tfprotov5.SchemaAttribute{{Name: "token", Type: tftypes.String, Optional: true, Sensitive: true}},
+		}},
+		Resources:   map[string]tfrouter.Resource{"example_server": testResource{}},
+		DataSources: map[string]tfrouter.DataSource{"example_image": testDataSource{}},
+	}
+	dir := t.TempDir()
+	if err := Write(dir, "example", FromRouter(router)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sort.Strings(files)
+	expected := []string{"data-sources/image.md", "index.md", "resources/server.md"}
+	if diff := cmp.Diff(expected, files); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	server, err := os.ReadFile(filepath.Join(dir, "resources", "server.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(strings.Replace(serverPage, `"Compute"`, `""`, 1), string(server)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	index, err := os.ReadFile(filepath.Join(dir, "index.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(index), "# example (Provider)\n\nThe example provider.\n") || !strings.Contains(string(index), "- `token` (String, Sensitive)\n") {
+		t.Errorf("unexpected provider page:\n%s", index)
+	}
+}
@@ -0,0 +1,196 @@
+package docsgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/hcl"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Example returns an example configuration of the provider, resource, or
+// data source named `name` described by `schema`, for documentation or as
+// the starting point of an acceptance test's configuration:
+//
+//	resource "example_server" "example" {
+//	  name = "name"
+//
+//	  # port = 0
+//
+//	  disk {
+//	    size = 0
+//	  }
+//	}
+//
+// Required attributes and nested blocks are set to placeholders, optional
+// ones are included but commented out, and computed ones are left out.
+// Strings are set to the attribute's name, numbers to 0, and bools to false.
+func Example(kind Kind, name string, schema *tfprotov5.Schema) string {
+	var header string
+	switch kind {
+	case Provider:
+		header = fmt.Sprintf("provider %s", hcl.String(name))
+	case Resource:
+		header = fmt.Sprintf("resource %s \"example\"", hcl.String(name))
+	case DataSource:
+		header = fmt.Sprintf("data %s \"example\"", hcl.String(name))
+	}
+	w := &exampleWriter{}
+	w.line(0, -1, header+" {")
+	if schema != nil && schema.Block != nil {
+		w.block(1, -1, schema.Block)
+	}
+	w.line(0, -1, "}")
+	return w.b.String()
+}
+
+// exampleWriter builds an example configuration a line at a time.
+type exampleWriter struct {
+	b strings.Builder
+
+	// blank records that a blank line should be written before the next
+	// line, to separate it from the one before.
+	blank bool
+}
+
+// line writes `s` indented to `depth`, commented out at `comment` if it's
+// not negative.
+func (w *exampleWriter) line(depth, comment int, s string) {
+	if w.blank {
+		w.b.WriteByte('\n')
+		w.blank = false
+	}
+	if comment >= 0 {
+		w.b.WriteString(strings.Repeat("  ", comment))
+		w.b.WriteString("# ")
+		depth -= comment
+	}
+	w.b.WriteString(strings.Repeat("  ", depth))
+	w.b.WriteString(s)
+	w.b.WriteByte('\n')
+}
+
+// separate requests a blank line before the next line, unless nothing's
+// been written inside the current block yet.
+func (w *exampleWriter) separate() {
+	s := w.b.String()
+	if !strings.HasSuffix(s, "{\n") {
+		w.blank = true
+	}
+}
+
+// block writes the body of `block` at `depth`, commented out at `comment`
+// if it's not negative.
+func (w *exampleWriter) block(depth, comment int, block *tfprotov5.SchemaBlock) {
+	var required, optional []*tfprotov5.SchemaAttribute
+	for _, attr := range block.Attributes {
+		switch {
+		case attr.Required:
+			required = append(required, attr)
+		case attr.Optional:
+			optional = append(optional, attr)
+		}
+	}
+	w.attributes(depth, comment, required)
+	optionalComment := comment
+	if optionalComment < 0 {
+		optionalComment = depth
+	}
+	w.attributes(depth, optionalComment, optional)
+
+	blocks := append([]*tfprotov5.SchemaNestedBlock(nil), block.BlockTypes...)
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if (blocks[i].MinItems > 0) != (blocks[j].MinItems > 0) {
+			return blocks[i].MinItems > 0
+		}
+		return blocks[i].TypeName < blocks[j].TypeName
+	})
+	for _, nb := range blocks {
+		if nb.Block == nil {
+			continue
+		}
+		nbComment := comment
+		if nbComment < 0 && nb.MinItems == 0 {
+			nbComment = depth
+		}
+		label := ""
+		if nb.Nesting == tfprotov5.SchemaNestedBlockNestingModeMap {
+			label = ` "key"`
+		}
+		n := nb.MinItems
+		if n == 0 {
+			n = 1
+		}
+		for i := int64(0); i < n; i++ {
+			w.separate()
+			w.line(depth, nbComment, nb.TypeName+label+" {")
+			w.block(depth+1, nbComment, nb.Block)
+			w.line(depth, nbComment, "}")
+		}
+	}
+}
+
+// attributes writes `attrs`, sorted by name and with their equals signs
+// aligned, as terraform fmt would.
+func (w *exampleWriter) attributes(depth, comment int, attrs []*tfprotov5.SchemaAttribute) {
+	if len(attrs) == 0 {
+		return
+	}
+	attrs = append([]*tfprotov5.SchemaAttribute(nil), attrs...)
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Name < attrs[j].Name })
+	width := 0
+	for _, attr := range attrs {
+		if n := len(attributeName(attr.Name)); n > width {
+			width = n
+		}
+	}
+	w.separate()
+	for _, attr := range attrs {
+		w.line(depth, comment, fmt.Sprintf("%-*s = %s", width, attributeName(attr.Name), placeholder(attr.Name, attr.Type)))
+	}
+}
+
+func attributeName(name string) string {
+	if hcl.Identifier(name) {
+		return name
+	}
+	return hcl.String(name)
+}
+
+// placeholder returns an HCL expression of type `typ` to use as the value
+// of the attribute `name`.
+func placeholder(name string, typ tftypes.Type) string {
+	switch t := typ.(type) {
+	case tftypes.List:
+		return "[" + placeholder(name, t.ElementType) + "]"
+	case tftypes.Set:
+		return "[" + placeholder(name, t.ElementType) + "]"
+	case tftypes.Tuple:
+		elems := make([]string, 0, len(t.ElementTypes))
+		for _, et := range t.ElementTypes {
+			elems = append(elems, placeholder(name, et))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case tftypes.Map:
+		return "{ key = " + placeholder(name, t.ElementType) + " }"
+	case tftypes.Object:
+		if len(t.AttributeTypes) == 0 {
+			return "{}"
+		}
+		attrs := make([]string, 0, len(t.AttributeTypes))
+		for _, k := range sorted.Keys(t.AttributeTypes) {
+			attrs = append(attrs, attributeName(k)+" = "+placeholder(k, t.AttributeTypes[k]))
+		}
+		return "{ " + strings.Join(attrs, ", ") + " }"
+	}
+	switch {
+	case typ.Is(tftypes.Number):
+		return "0"
+	case typ.Is(tftypes.Bool):
+		return "false"
+	}
+	return hcl.String(name)
+}
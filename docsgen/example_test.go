@@ -0,0 +1,109 @@
+package docsgen
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestExample(t *testing.T) {
+	type testCase struct {
+		kind     Kind
+		name     string
+		schema   *tfprotov5.Schema
+		expected string
+	}
+	cases := map[string]testCase{
+		"provider": {
+			kind: Provider,
+			name: "example",
+			schema: &tfprotov5.Schema{Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{Name: "token", Type: tftypes.String, Optional: true, Sensitive: true},
+					{Name: "endpoint", Type: tftypes.String, Optional: true},
+				},
+			}},
+			expected: `provider "example" {
+  # endpoint = "endpoint"
+  # token    = "token"
+}
+`,
+		},
+		"data-source": {
+			kind: DataSource,
+			name: "example_image",
+			schema: &tfprotov5.Schema{Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{Name: "id", Type: tftypes.String, Computed: true},
+					{Name: "filter", Type: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+						"name":   tftypes.String,
+						"values": tftypes.Set{ElementType: tftypes.String},
+					}}, Required: true},
+					{Name: "most-recent", Type: tftypes.Bool, Required: true},
+				},
+			}},
+			expected: `data "example_image" "example" {
+  filter      = { name = "name", values = ["values"] }
+  most-recent = false
+}
+`,
+		},
+		"blocks": {
+			kind: Resource,
+			name: "example_network",
+			schema: &tfprotov5.Schema{Block: &tfprotov5.SchemaBlock{
+				BlockTypes: []*tfprotov5.SchemaNestedBlock{
+					{
+						TypeName: "subnet",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeMap,
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{Name: "cidr", Type: tftypes.String, Required: true},
+							},
+						},
+					},
+					{
+						TypeName: "route",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+						MinItems: 2,
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{Name: "ports", Type: tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.Number}}, Required: true},
+							},
+						},
+					},
+				},
+			}},
+			expected: `resource "example_network" "example" {
+  route {
+    ports = [0, 0]
+  }
+
+  route {
+    ports = [0, 0]
+  }
+
+  # subnet "key" {
+  #   cidr = "cidr"
+  # }
+}
+`,
+		},
+		"no-schema": {
+			kind:     Resource,
+			name:     "example_empty",
+			expected: "resource \"example_empty\" \"example\" {\n}\n",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got := Example(tc.kind, tc.name, tc.schema)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+// Package graceful provides a tfprotov5.ProviderServer wrapper that
+// implements StopProvider by cancelling the contexts of every in-flight RPC
+// and waiting for them to drain.
+//
+// Terraform calls StopProvider when the user interrupts an operation, and
+// expects the provider to abandon any long-running work as quickly as it
+// safely can. Wiring that up by hand means threading a shared cancellation
+// signal through every handler, which is tedious enough that most providers
+// built directly on terraform-plugin-go skip it entirely. Wrapping a provider
+// with NewServer gets the behavior for free: every handler receives a context
+// that is cancelled when StopProvider is called, and StopProvider itself
+// doesn't return until those handlers have finished or the drain timeout
+// elapses.
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// DefaultDrainTimeout is the amount of time StopProvider will wait for
+// in-flight requests to return if no other timeout is configured.
+const DefaultDrainTimeout = 30 * time.Second
+
+var (
+	_ tfprotov5.ProviderServerWithListResource = &Server{}
+	_ tfprotov5.ProviderServerWithActions      = &Server{}
+)
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithDrainTimeout sets how long StopProvider waits for in-flight requests to
+// return after their contexts have been cancelled. A timeout of zero or less
+// means StopProvider will not wait at all.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.drainTimeout = d
+	}
+}
+
+// Server is a tfprotov5.ProviderServer that wraps another
+// tfprotov5.ProviderServer, handing each RPC a context that is cancelled when
+// StopProvider is called. The list resource and action RPCs are passed on
+// if the wrapped server implements tfprotov5.ListResourceServer or
+// tfprotov5.ActionServer, like tfrouter.Router.
+type Server struct {
+	srv          tfprotov5.ProviderServer
+	drainTimeout time.Duration
+
+	stopCtx    context.Context
+	stopCancel context.CancelFunc
+
+	mu      sync.Mutex
+	count   int
+	waiters []chan struct{}
+}
+
+// NewServer returns a Server wrapping `srv`.
+func NewServer(srv tfprotov5.ProviderServer, opts ...Option) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		srv:          srv,
+		drainTimeout: DefaultDrainTimeout,
+		stopCtx:      ctx,
+		stopCancel:   cancel,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// StopContext returns a context that will be cancelled when StopProvider is
+// called. It can be used by work that isn't tied to a single RPC, like
+// background refreshes of credentials.
+func (s *Server) StopContext() context.Context {
+	return s.stopCtx
+}
+
+// Stopped returns true if StopProvider has been called.
+func (s *Server) Stopped() bool {
+	return s.stopCtx.Err() != nil
+}
+
+// InFlight returns the number of RPCs currently being handled, not counting
+// StopProvider itself.
+func (s *Server) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// track returns a context derived from `ctx` that is also cancelled when the
+// Server is stopped, and a function that must be called when the RPC is
+// complete.
+func (s *Server) track(ctx context.Context) (context.Context, func()) {
+	s.mu.Lock()
+	s.count++
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	if s.Stopped() {
+		cancel()
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-s.stopCtx.Done():
+			cancel()
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		close(done)
+		cancel()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.count--
+		if s.count == 0 {
+			for _, ch := range s.waiters {
+				close(ch)
+			}
+			s.waiters = nil
+		}
+	}
+}
+
+// drained returns a channel that is closed once there are no in-flight RPCs.
+func (s *Server) drained() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan struct{})
+	if s.count == 0 {
+		close(ch)
+		return ch
+	}
+	s.waiters = append(s.waiters, ch)
+	return ch
+}
+
+// StopProvider cancels the context of every in-flight RPC, passes the request
+// on to the wrapped server, and then waits up to the drain timeout for the
+// in-flight RPCs to return. If they don't return in time, the response's
+// Error will say so.
+func (s *Server) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	s.stopCancel()
+
+	resp, err := s.srv.StopProvider(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if resp == nil {
+		resp = &tfprotov5.StopProviderResponse{}
+	}
+
+	drained := s.drained()
+	if s.drainTimeout <= 0 {
+		select {
+		case <-drained:
+		default:
+			resp.Error = appendError(resp.Error, fmt.Sprintf("%d requests were still in flight", s.InFlight()))
+		}
+		return resp, nil
+	}
+
+	timer := time.NewTimer(s.drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		resp.Error = appendError(resp.Error, fmt.Sprintf("stopped waiting for %d in-flight requests: %s", s.InFlight(), ctx.Err()))
+	case <-timer.C:
+		resp.Error = appendError(resp.Error, fmt.Sprintf("timed out waiting for %d in-flight requests to finish", s.InFlight()))
+	}
+	return resp, nil
+}
+
+func appendError(existing, msg string) string {
+	if existing == "" {
+		return msg
+	}
+	return existing + "; " + msg
+}
+
+func (s *Server) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.GetMetadata(ctx, req)
+}
+
+func (s *Server) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.GetProviderSchema(ctx, req)
+}
+
+func (s *Server) GetResourceIdentitySchemas(ctx context.Context, req *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.GetResourceIdentitySchemas(ctx, req)
+}
+
+func (s *Server) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.PrepareProviderConfig(ctx, req)
+}
+
+func (s *Server) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.ConfigureProvider(ctx, req)
+}
+
+func (s *Server) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.ValidateResourceTypeConfig(ctx, req)
+}
+
+func (s *Server) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.UpgradeResourceState(ctx, req)
+}
+
+func (s *Server) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.ReadResource(ctx, req)
+}
+
+func (s *Server) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.PlanResourceChange(ctx, req)
+}
+
+func (s *Server) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.ApplyResourceChange(ctx, req)
+}
+
+func (s *Server) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.ImportResourceState(ctx, req)
+}
+
+func (s *Server) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.MoveResourceState(ctx, req)
+}
+
+func (s *Server) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.UpgradeResourceIdentity(ctx, req)
+}
+
+func (s *Server) GenerateResourceConfig(ctx context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.GenerateResourceConfig(ctx, req)
+}
+
+func (s *Server) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.ValidateDataSourceConfig(ctx, req)
+}
+
+func (s *Server) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.ReadDataSource(ctx, req)
+}
+
+func (s *Server) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.CallFunction(ctx, req)
+}
+
+func (s *Server) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.GetFunctions(ctx, req)
+}
+
+func (s *Server) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.ValidateEphemeralResourceConfig(ctx, req)
+}
+
+func (s *Server) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.OpenEphemeralResource(ctx, req)
+}
+
+func (s *Server) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.RenewEphemeralResource(ctx, req)
+}
+
+func (s *Server) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	ctx, done := s.track(ctx)
+	defer done()
+	return s.srv.CloseEphemeralResource(ctx, req)
+}
+
+// listUnsupported returns the diagnostic returned by the list resource RPCs
+// when the wrapped server doesn't implement them.
+func listUnsupported() *tfprotov5.Diagnostic {
+	return diag.Errorf("List resources not supported", "The provider does not support list resources.")
+}
+
+func (s *Server) ValidateListResourceConfig(ctx context.Context, req *tfprotov5.ValidateListResourceConfigRequest) (*tfprotov5.ValidateListResourceConfigResponse, error) {
+	ls, ok := s.srv.(tfprotov5.ListResourceServer)
+	if !ok {
+		return &tfprotov5.ValidateListResourceConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{listUnsupported()}}, nil
+	}
+	ctx, done := s.track(ctx)
+	defer done()
+	return ls.ValidateListResourceConfig(ctx, req)
+}
+
+// ListResource passes the request on to the wrapped server if it implements
+// tfprotov5.ListResourceServer. The RPC counts as in flight until its
+// results have been streamed, as that's when the listing happens.
+func (s *Server) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	ls, ok := s.srv.(tfprotov5.ListResourceServer)
+	if !ok {
+		return &tfprotov5.ListResourceServerStream{
+			Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+				yield(tfprotov5.ListResourceResult{Diagnostics: []*tfprotov5.Diagnostic{listUnsupported()}})
+			},
+		}, nil
+	}
+	ctx, track := s.track(ctx)
+	var once sync.Once
+	done := func() { once.Do(track) }
+	stream, err := ls.ListResource(ctx, req)
+	if err != nil || stream == nil || stream.Results == nil {
+		done()
+		return stream, err
+	}
+	results := stream.Results
+	stream.Results = func(yield func(tfprotov5.ListResourceResult) bool) {
+		defer done()
+		results(yield)
+	}
+	return stream, nil
+}
+
+// actionsUnsupported returns the diagnostic returned by the action RPCs when
+// the wrapped server doesn't implement them.
+func actionsUnsupported() *tfprotov5.Diagnostic {
+	return diag.Errorf("Actions not supported", "The provider does not support actions.")
+}
+
+func (s *Server) ValidateActionConfig(ctx context.Context, req *tfprotov5.ValidateActionConfigRequest) (*tfprotov5.ValidateActionConfigResponse, error) {
+	as, ok := s.srv.(tfprotov5.ActionServer)
+	if !ok {
+		return &tfprotov5.ValidateActionConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{actionsUnsupported()}}, nil
+	}
+	ctx, done := s.track(ctx)
+	defer done()
+	return as.ValidateActionConfig(ctx, req)
+}
+
+func (s *Server) PlanAction(ctx context.Context, req *tfprotov5.PlanActionRequest) (*tfprotov5.PlanActionResponse, error) {
+	as, ok := s.srv.(tfprotov5.ActionServer)
+	if !ok {
+		return &tfprotov5.PlanActionResponse{Diagnostics: []*tfprotov5.Diagnostic{actionsUnsupported()}}, nil
+	}
+	ctx, done := s.track(ctx)
+	defer done()
+	return as.PlanAction(ctx, req)
+}
+
+// InvokeAction passes the request on to the wrapped server if it implements
+// tfprotov5.ActionServer. The RPC counts as in flight until its events have
+// been streamed, as that's when the action runs.
+func (s *Server) InvokeAction(ctx context.Context, req *tfprotov5.InvokeActionRequest) (*tfprotov5.InvokeActionServerStream, error) {
+	as, ok := s.srv.(tfprotov5.ActionServer)
+	if !ok {
+		return &tfprotov5.InvokeActionServerStream{
+			Events: func(yield func(tfprotov5.InvokeActionEvent) bool) {
+				yield(tfprotov5.InvokeActionEvent{Type: tfprotov5.CompletedInvokeActionEventType{
+					Diagnostics: []*tfprotov5.Diagnostic{actionsUnsupported()},
+				}})
+			},
+		}, nil
+	}
+	ctx, track := s.track(ctx)
+	var once sync.Once
+	done := func() { once.Do(track) }
+	stream, err := as.InvokeAction(ctx, req)
+	if err != nil || stream == nil || stream.Events == nil {
+		done()
+		return stream, err
+	}
+	events := stream.Events
+	stream.Events = func(yield func(tfprotov5.InvokeActionEvent) bool) {
+		defer done()
+		events(yield)
+	}
+	return stream, nil
+}
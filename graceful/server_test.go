@@ -0,0 +1,260 @@
+package graceful
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+type blockingServer struct {
+	tfprotov5.ProviderServer
+
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	close(b.started)
+	select {
+	case <-ctx.Done():
+	case <-b.release:
+	}
+	return &tfprotov5.ReadResourceResponse{}, ctx.Err()
+}
+
+func (b *blockingServer) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	return &tfprotov5.StopProviderResponse{}, nil
+}
+
+func TestStopProviderCancelsInFlight(t *testing.T) {
+	inner := &blockingServer{started: make(chan struct{}), release: make(chan struct{})}
+	srv := NewServer(inner, WithDrainTimeout(5*time.Second))
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := srv.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{})
+		errCh <- err
+	}()
+	<-inner.started
+
+	resp, err := srv.StopProvider(context.Background(), &tfprotov5.StopProviderRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp.Error != "" {
+		t.Errorf("unexpected response error: %s", resp.Error)
+	}
+	if got := <-errCh; got != context.Canceled {
+		t.Errorf("expected handler to see context.Canceled, got %v", got)
+	}
+	if !srv.Stopped() {
+		t.Error("expected server to report being stopped")
+	}
+	if srv.InFlight() != 0 {
+		t.Errorf("expected no in-flight requests, got %d", srv.InFlight())
+	}
+}
+
+type stubbornServer struct {
+	blockingServer
+}
+
+func (s *stubbornServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	close(s.started)
+	<-s.release
+	return &tfprotov5.ReadResourceResponse{}, nil
+}
+
+func TestStopProviderDrainTimeout(t *testing.T) {
+	inner := &stubbornServer{blockingServer{started: make(chan struct{}), release: make(chan struct{})}}
+	srv := NewServer(inner, WithDrainTimeout(10*time.Millisecond))
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = srv.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{})
+		close(done)
+	}()
+	<-inner.started
+
+	resp, err := srv.StopProvider(context.Background(), &tfprotov5.StopProviderRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(resp.Error, "timed out waiting for 1 in-flight requests") {
+		t.Errorf("unexpected response error: %q", resp.Error)
+	}
+	close(inner.release)
+	<-done
+}
+
+func TestRequestAfterStopIsCancelled(t *testing.T) {
+	inner := &blockingServer{started: make(chan struct{}), release: make(chan struct{})}
+	srv := NewServer(inner)
+	if _, err := srv.StopProvider(context.Background(), &tfprotov5.StopProviderRequest{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err := srv.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{})
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+type listServer struct {
+	blockingServer
+	srv *Server
+}
+
+func (l *listServer) ValidateListResourceConfig(ctx context.Context, req *tfprotov5.ValidateListResourceConfigRequest) (*tfprotov5.ValidateListResourceConfigResponse, error) {
+	return &tfprotov5.ValidateListResourceConfigResponse{}, nil
+}
+
+func (l *listServer) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	return &tfprotov5.ListResourceServerStream{
+		Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+			for i := 0; i < 2; i++ {
+				if l.srv.InFlight() != 1 {
+					yield(tfprotov5.ListResourceResult{DisplayName: "not in flight"})
+					return
+				}
+				if !yield(tfprotov5.ListResourceResult{DisplayName: "found"}) {
+					return
+				}
+			}
+		},
+	}, nil
+}
+
+func TestListResource(t *testing.T) {
+	inner := &listServer{}
+	srv := NewServer(inner)
+	inner.srv = srv
+
+	stream, err := srv.ListResource(context.Background(), &tfprotov5.ListResourceRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var names []string
+	for result := range stream.Results {
+		names = append(names, result.DisplayName)
+	}
+	if len(names) != 2 || names[0] != "found" || names[1] != "found" {
+		t.Errorf("expected the results to be streamed while in flight, got %v", names)
+	}
+	if srv.InFlight() != 0 {
+		t.Errorf("expected no in-flight requests, got %d", srv.InFlight())
+	}
+}
+
+func TestListResourceUnsupported(t *testing.T) {
+	srv := NewServer(&blockingServer{})
+
+	validate, err := srv.ValidateListResourceConfig(context.Background(), &tfprotov5.ValidateListResourceConfigRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(validate.Diagnostics) != 1 || validate.Diagnostics[0].Summary != "List resources not supported" {
+		t.Errorf("unexpected diagnostics: %+v", validate.Diagnostics)
+	}
+	stream, err := srv.ListResource(context.Background(), &tfprotov5.ListResourceRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for result := range stream.Results {
+		if len(result.Diagnostics) != 1 || result.Diagnostics[0].Summary != "List resources not supported" {
+			t.Errorf("unexpected diagnostics: %+v", result.Diagnostics)
+		}
+	}
+	if srv.InFlight() != 0 {
+		t.Errorf("expected no in-flight requests, got %d", srv.InFlight())
+	}
+}
+
+type actionServer struct {
+	blockingServer
+	srv *Server
+}
+
+func (a *actionServer) ValidateActionConfig(ctx context.Context, req *tfprotov5.ValidateActionConfigRequest) (*tfprotov5.ValidateActionConfigResponse, error) {
+	return &tfprotov5.ValidateActionConfigResponse{}, nil
+}
+
+func (a *actionServer) PlanAction(ctx context.Context, req *tfprotov5.PlanActionRequest) (*tfprotov5.PlanActionResponse, error) {
+	if a.srv.InFlight() != 1 {
+		return nil, context.Canceled
+	}
+	return &tfprotov5.PlanActionResponse{}, ctx.Err()
+}
+
+func (a *actionServer) InvokeAction(ctx context.Context, req *tfprotov5.InvokeActionRequest) (*tfprotov5.InvokeActionServerStream, error) {
+	return &tfprotov5.InvokeActionServerStream{
+		Events: func(yield func(tfprotov5.InvokeActionEvent) bool) {
+			message := "running"
+			if a.srv.InFlight() != 1 {
+				message = "not in flight"
+			}
+			if !yield(tfprotov5.InvokeActionEvent{Type: tfprotov5.ProgressInvokeActionEventType{Message: message}}) {
+				return
+			}
+			yield(tfprotov5.InvokeActionEvent{Type: tfprotov5.CompletedInvokeActionEventType{}})
+		},
+	}, nil
+}
+
+func TestInvokeAction(t *testing.T) {
+	inner := &actionServer{}
+	srv := NewServer(inner)
+	inner.srv = srv
+
+	if _, err := srv.PlanAction(context.Background(), &tfprotov5.PlanActionRequest{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	stream, err := srv.InvokeAction(context.Background(), &tfprotov5.InvokeActionRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var events []tfprotov5.InvokeActionEvent
+	for event := range stream.Events {
+		events = append(events, event)
+	}
+	if len(events) != 2 || events[0].Type != (tfprotov5.ProgressInvokeActionEventType{Message: "running"}) {
+		t.Errorf("expected the events to be streamed while in flight, got %+v", events)
+	}
+	if srv.InFlight() != 0 {
+		t.Errorf("expected no in-flight requests, got %d", srv.InFlight())
+	}
+}
+
+func TestActionsUnsupported(t *testing.T) {
+	srv := NewServer(&blockingServer{})
+
+	validate, err := srv.ValidateActionConfig(context.Background(), &tfprotov5.ValidateActionConfigRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(validate.Diagnostics) != 1 || validate.Diagnostics[0].Summary != "Actions not supported" {
+		t.Errorf("unexpected diagnostics: %+v", validate.Diagnostics)
+	}
+	plan, err := srv.PlanAction(context.Background(), &tfprotov5.PlanActionRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(plan.Diagnostics) != 1 || plan.Diagnostics[0].Summary != "Actions not supported" {
+		t.Errorf("unexpected diagnostics: %+v", plan.Diagnostics)
+	}
+	stream, err := srv.InvokeAction(context.Background(), &tfprotov5.InvokeActionRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for event := range stream.Events {
+		completed, ok := event.Type.(tfprotov5.CompletedInvokeActionEventType)
+		if !ok || len(completed.Diagnostics) != 1 || completed.Diagnostics[0].Summary != "Actions not supported" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	}
+	if srv.InFlight() != 0 {
+		t.Errorf("expected no in-flight requests, got %d", srv.InFlight())
+	}
+}
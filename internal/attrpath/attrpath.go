@@ -0,0 +1,87 @@
+// Package attrpath matches tftypes.AttributePaths by their attribute names,
+// ignoring element keys, for the packages of this module that associate
+// values with the attributes of nested blocks.
+package attrpath
+
+import (
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Names returns the attribute names in `path`, leaving out element keys.
+func Names(path *tftypes.AttributePath) []string {
+	var names []string
+	for _, step := range path.Steps() {
+		if name, ok := step.(tftypes.AttributeName); ok {
+			names = append(names, string(name))
+		}
+	}
+	return names
+}
+
+// HasPrefix returns true if `names` starts with `prefix`.
+func HasPrefix(names, prefix []string) bool {
+	return len(prefix) <= len(names) && slices.Equal(names[:len(prefix)], prefix)
+}
+
+// Registry associates values with attribute paths. The zero value is an
+// empty Registry ready to use, and a nil *Registry has no values.
+//
+// Element keys in paths are ignored when matching, so values registered for
+// an attribute of a nested block apply to that attribute in every element
+// of the block.
+type Registry[V any] struct {
+	entries []entry[V]
+}
+
+type entry[V any] struct {
+	names []string
+	vals  []V
+}
+
+// Add registers `vals` for the attribute at `path`, after any already
+// registered for it.
+func (r *Registry[V]) Add(path *tftypes.AttributePath, vals ...V) {
+	if i := r.index(Names(path)); i >= 0 {
+		r.entries[i].vals = append(r.entries[i].vals, vals...)
+		return
+	}
+	r.entries = append(r.entries, entry[V]{names: Names(path), vals: vals})
+}
+
+// Set registers `vals` for the attribute at `path`, replacing any already
+// registered for it.
+func (r *Registry[V]) Set(path *tftypes.AttributePath, vals ...V) {
+	if i := r.index(Names(path)); i >= 0 {
+		r.entries[i].vals = vals
+		return
+	}
+	r.entries = append(r.entries, entry[V]{names: Names(path), vals: vals})
+}
+
+// Get returns the values registered for the attribute at `path`, in the
+// order they were added.
+func (r *Registry[V]) Get(path *tftypes.AttributePath) []V {
+	if r == nil {
+		return nil
+	}
+	if i := r.index(Names(path)); i >= 0 {
+		return r.entries[i].vals
+	}
+	return nil
+}
+
+// Len returns the number of attributes with values registered.
+func (r *Registry[V]) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.entries)
+}
+
+func (r *Registry[V]) index(names []string) int {
+	return slices.IndexFunc(r.entries, func(e entry[V]) bool {
+		return slices.Equal(e.names, names)
+	})
+}
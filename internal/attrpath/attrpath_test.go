@@ -0,0 +1,65 @@
+package attrpath
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestNames(t *testing.T) {
+	path := tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(0).WithAttributeName("port")
+	if diff := cmp.Diff([]string{"rule", "port"}, Names(path)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	type testCase struct {
+		names, prefix []string
+		expected      bool
+	}
+	cases := map[string]testCase{
+		"equal":   {names: []string{"a", "b"}, prefix: []string{"a", "b"}, expected: true},
+		"prefix":  {names: []string{"a", "b"}, prefix: []string{"a"}, expected: true},
+		"empty":   {names: []string{"a"}, expected: true},
+		"longer":  {names: []string{"a"}, prefix: []string{"a", "b"}, expected: false},
+		"differs": {names: []string{"a", "b"}, prefix: []string{"b"}, expected: false},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if got := HasPrefix(tc.names, tc.prefix); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	var r Registry[string]
+	port := tftypes.NewAttributePath().WithAttributeName("rule").WithAttributeName("port")
+	r.Add(port, "a")
+	r.Add(port, "b")
+	r.Add(tftypes.NewAttributePath().WithAttributeName("name"), "c")
+
+	elem := tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(1).WithAttributeName("port")
+	if diff := cmp.Diff([]string{"a", "b"}, r.Get(elem)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	r.Set(port, "d")
+	if diff := cmp.Diff([]string{"d"}, r.Get(port)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if got := r.Get(tftypes.NewAttributePath().WithAttributeName("rule")); got != nil {
+		t.Errorf("expected no values, got %v", got)
+	}
+	if r.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", r.Len())
+	}
+
+	var nilRegistry *Registry[string]
+	if nilRegistry.Get(port) != nil || nilRegistry.Len() != 0 {
+		t.Error("expected a nil registry to be empty")
+	}
+}
@@ -0,0 +1,23 @@
+// Package describe joins the descriptions of validators and plan modifiers,
+// for the documentation docsgen renders.
+package describe
+
+import "strings"
+
+// Describer is implemented by values that can describe themselves, for
+// documentation.
+type Describer interface {
+	Description() string
+}
+
+// Join returns the descriptions of the `items` that implement Describer,
+// joined by spaces, or "" if none of them do.
+func Join[T any](items ...T) string {
+	var descs []string
+	for _, item := range items {
+		if d, ok := any(item).(Describer); ok && d.Description() != "" {
+			descs = append(descs, d.Description())
+		}
+	}
+	return strings.Join(descs, " ")
+}
@@ -0,0 +1,36 @@
+// Package diag contains helpers for building tfprotov5.Diagnostics that are
+// shared between the packages of this module.
+package diag
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Error returns an error diagnostic for `err`, attaching the attribute path
+// if `err` is or wraps a tftypes.AttributePathError.
+func Error(summary string, err error) *tfprotov5.Diagnostic {
+	d := &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  summary,
+		Detail:   err.Error(),
+	}
+	var pathErr tftypes.AttributePathError
+	if errors.As(err, &pathErr) && pathErr.Path != nil && len(pathErr.Path.Steps()) > 0 {
+		d.Attribute = pathErr.Path
+	}
+	return d
+}
+
+// Errorf returns an error diagnostic with the given summary, and a detail
+// formatted from `format` and `args` as with fmt.Sprintf.
+func Errorf(summary, format string, args ...interface{}) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  summary,
+		Detail:   fmt.Sprintf(format, args...),
+	}
+}
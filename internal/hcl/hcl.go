@@ -0,0 +1,110 @@
+// Package hcl formats tftypes.Values as HCL expressions, for the
+// documentation and examples generated by this module's packages.
+package hcl
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Value returns `val` as an HCL expression on a single line. Null values are
+// formatted as null, and unknown values, which have no literal syntax, as a
+// placeholder comment.
+func Value(val tftypes.Value) string {
+	var b strings.Builder
+	writeValue(&b, val)
+	return b.String()
+}
+
+// String returns `s` as a quoted HCL string, escaping template sequences.
+func String(s string) string {
+	q := strconv.Quote(s)
+	q = strings.ReplaceAll(q, "${", "$${")
+	return strings.ReplaceAll(q, "%{", "%%{")
+}
+
+// Identifier reports whether `s` can be used as an attribute name without
+// quoting.
+func Identifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '-' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func writeValue(b *strings.Builder, val tftypes.Value) {
+	if !val.IsKnown() {
+		b.WriteString("/* unknown */")
+		return
+	}
+	if val.IsNull() {
+		b.WriteString("null")
+		return
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.String):
+		var s string
+		_ = val.As(&s)
+		b.WriteString(String(s))
+	case typ.Is(tftypes.Number):
+		var f big.Float
+		_ = val.As(&f)
+		b.WriteString(f.Text('f', -1))
+	case typ.Is(tftypes.Bool):
+		var v bool
+		_ = val.As(&v)
+		b.WriteString(strconv.FormatBool(v))
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		var elems []tftypes.Value
+		_ = val.As(&elems)
+		b.WriteByte('[')
+		for i, elem := range elems {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeValue(b, elem)
+		}
+		b.WriteByte(']')
+	case typ.Is(tftypes.Map{}), typ.Is(tftypes.Object{}):
+		var elems map[string]tftypes.Value
+		_ = val.As(&elems)
+		keys := make([]string, 0, len(elems))
+		for k := range elems {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		if len(keys) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		b.WriteString("{ ")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if Identifier(k) {
+				b.WriteString(k)
+			} else {
+				b.WriteString(String(k))
+			}
+			b.WriteString(" = ")
+			writeValue(b, elems[k])
+		}
+		b.WriteString(" }")
+	default:
+		b.WriteString(val.String())
+	}
+}
@@ -0,0 +1,49 @@
+package hcl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestValue(t *testing.T) {
+	type testCase struct {
+		val      tftypes.Value
+		expected string
+	}
+	obj := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":  tftypes.String,
+		"a-b.c": tftypes.Bool,
+	}}
+	cases := map[string]testCase{
+		"string":  {val: tftypes.NewValue(tftypes.String, "say \"hi\" ${x}"), expected: `"say \"hi\" $${x}"`},
+		"number":  {val: tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)), expected: "1.5"},
+		"bool":    {val: tftypes.NewValue(tftypes.Bool, true), expected: "true"},
+		"null":    {val: tftypes.NewValue(tftypes.String, nil), expected: "null"},
+		"unknown": {val: tftypes.NewValue(tftypes.String, tftypes.UnknownValue), expected: "/* unknown */"},
+		"empty":   {val: tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{}), expected: "{}"},
+		"list": {
+			val: tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, []tftypes.Value{
+				tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+				tftypes.NewValue(tftypes.Number, big.NewFloat(2)),
+			}),
+			expected: "[1, 2]",
+		},
+		"object": {
+			val: tftypes.NewValue(obj, map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "web"),
+				"a-b.c": tftypes.NewValue(tftypes.Bool, false),
+			}),
+			expected: `{ "a-b.c" = false, name = "web" }`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if got := Value(tc.val); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Package parse converts strings from outside of Terraform, like
+// environment variables and import IDs, into tftypes.Values.
+package parse
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Primitive parses `s` as a value of the primitive type `typ`.
+func Primitive(typ tftypes.Type, s string) (tftypes.Value, error) {
+	switch {
+	case typ.Is(tftypes.String):
+		return tftypes.NewValue(typ, s), nil
+	case typ.Is(tftypes.Bool):
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("can't parse %q as a bool", s)
+		}
+		return tftypes.NewValue(typ, b), nil
+	case typ.Is(tftypes.Number):
+		f, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("can't parse %q as a number", s)
+		}
+		return tftypes.NewValue(typ, f), nil
+	}
+	return tftypes.Value{}, fmt.Errorf("can't parse a value of type %s", typ)
+}
+
+// Env returns the value of the first of the environment variables `names`
+// that is set and not empty, parsed as `typ`. It returns false if none of
+// them are set.
+func Env(typ tftypes.Type, names []string) (tftypes.Value, bool, error) {
+	for _, name := range names {
+		if s := os.Getenv(name); s != "" {
+			val, err := Primitive(typ, s)
+			if err != nil {
+				return tftypes.Value{}, false, fmt.Errorf("environment variable %s: %w", name, err)
+			}
+			return val, true, nil
+		}
+	}
+	return tftypes.Value{}, false, nil
+}
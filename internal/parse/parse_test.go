@@ -0,0 +1,50 @@
+package parse
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPrimitive(t *testing.T) {
+	type testCase struct {
+		typ         tftypes.Type
+		s           string
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"string": {typ: tftypes.String, s: "foo", expected: tftypes.NewValue(tftypes.String, "foo")},
+		"bool":   {typ: tftypes.Bool, s: "true", expected: tftypes.NewValue(tftypes.Bool, true)},
+		"number": {typ: tftypes.Number, s: "1.5", expected: tftypes.NewValue(tftypes.Number, big.NewFloat(1.5))},
+		"bad-bool": {
+			typ:         tftypes.Bool,
+			s:           "yes please",
+			expectedErr: `can't parse "yes please" as a bool`,
+		},
+		"list": {
+			typ:         tftypes.List{ElementType: tftypes.String},
+			s:           "foo",
+			expectedErr: "can't parse a value of type tftypes.List[tftypes.String]",
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			got, err := Primitive(test.typ, test.s)
+			if test.expectedErr != "" {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equal(test.expected) {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+// Package sorted iterates over maps in a stable order, so output built from
+// them, like diagnostics and generated code, is always the same.
+package sorted
+
+import "sort"
+
+// Keys returns the keys of `m`, sorted. It's never nil, even if `m` is.
+func Keys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
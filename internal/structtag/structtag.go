@@ -0,0 +1,60 @@
+// Package structtag parses the tfsdk struct tags that map the fields of Go
+// structs to object attributes, for the packages of this module that read
+// them, so they accept the same options and report the same errors.
+package structtag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Key is the key of the struct tag parsed by Lookup.
+const Key = "tfsdk"
+
+// Tag is a parsed tfsdk struct tag.
+type Tag struct {
+	// Name is the name of the attribute the field maps to. It's empty for
+	// remain fields.
+	Name string
+
+	// Remain is set by a tag of ",remain", marking the field that holds
+	// the attributes no other field maps to.
+	Remain bool
+
+	// EmptyAsNull is set by the "emptyasnull" option.
+	EmptyAsNull bool
+
+	// Required is set by the "required" option.
+	Required bool
+}
+
+// Lookup returns the tfsdk tag of the field `f` of the struct type `t`, and
+// whether it has one; a tag of "-" counts as not having one. Options follow
+// the name, separated by commas, and unknown options are an error, so
+// misspelled options aren't silently ignored.
+func Lookup(t reflect.Type, f reflect.StructField) (Tag, bool, error) {
+	tag, ok := f.Tag.Lookup(Key)
+	if !ok || tag == "-" {
+		return Tag{}, false, nil
+	}
+	opts := strings.Split(tag, ",")
+	if opts[0] == "" && len(opts) == 2 && opts[1] == "remain" {
+		return Tag{Remain: true}, true, nil
+	}
+	if opts[0] == "" {
+		return Tag{}, false, fmt.Errorf("field %s of %s has an empty %s tag", f.Name, t, Key)
+	}
+	parsed := Tag{Name: opts[0]}
+	for _, opt := range opts[1:] {
+		switch opt {
+		case "emptyasnull":
+			parsed.EmptyAsNull = true
+		case "required":
+			parsed.Required = true
+		default:
+			return Tag{}, false, fmt.Errorf("field %s of %s has unknown %s tag option %q", f.Name, t, Key, opt)
+		}
+	}
+	return parsed, true, nil
+}
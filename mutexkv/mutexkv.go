@@ -0,0 +1,150 @@
+// Package mutexkv provides a mutex keyed by string, for serializing
+// operations on shared upstream objects.
+//
+// Many APIs reject concurrent changes to the same object, like two rules
+// being added to one security group at the same time, even though Terraform
+// happily applies the resources that make those changes in parallel.
+// Locking a MutexKV with the ID of the shared object around each change
+// serializes them without limiting parallelism for unrelated objects, and
+// Resource does the same for a whole resource type.
+package mutexkv
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MutexKV is a set of mutexes, each identified by a key. The zero value is
+// ready to use, and a MutexKV must not be copied after first use.
+type MutexKV struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+	stats Stats
+}
+
+// keyLock is the mutex for a single key. It's removed from the MutexKV once
+// nothing holds or is waiting for it.
+type keyLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// Stats describes the contention on a MutexKV.
+type Stats struct {
+	// Acquired is the number of times a lock has been acquired.
+	Acquired uint64
+
+	// Contended is the number of times Lock had to wait for a lock held
+	// by someone else.
+	Contended uint64
+
+	// Cancelled is the number of times Lock gave up because its context
+	// was done before the lock was acquired.
+	Cancelled uint64
+
+	// Wait is the total time Lock has spent waiting for locks.
+	Wait time.Duration
+}
+
+// ref returns the lock for `key`, creating it if necessary, and records that
+// it's in use.
+func (m *MutexKV) ref(key string) *keyLock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks == nil {
+		m.locks = map[string]*keyLock{}
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyLock{ch: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	return l
+}
+
+// unref records that the lock for `key` is no longer in use, removing it if
+// nothing else is using it either.
+func (m *MutexKV) unref(key string, l *keyLock) {
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
+
+// Lock locks the mutex for `key`, waiting until it's available or `ctx` is
+// done. It returns the context's error if the lock wasn't acquired.
+func (m *MutexKV) Lock(ctx context.Context, key string) error {
+	l := m.ref(key)
+	select {
+	case l.ch <- struct{}{}:
+		m.mu.Lock()
+		m.stats.Acquired++
+		m.mu.Unlock()
+		return nil
+	default:
+	}
+
+	start := time.Now()
+	select {
+	case l.ch <- struct{}{}:
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.stats.Acquired++
+		m.stats.Contended++
+		m.stats.Wait += time.Since(start)
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.stats.Contended++
+		m.stats.Cancelled++
+		m.stats.Wait += time.Since(start)
+		m.unref(key, l)
+		return ctx.Err()
+	}
+}
+
+// TryLock locks the mutex for `key` if it's available, and reports whether
+// it did.
+func (m *MutexKV) TryLock(key string) bool {
+	l := m.ref(key)
+	select {
+	case l.ch <- struct{}{}:
+		m.mu.Lock()
+		m.stats.Acquired++
+		m.mu.Unlock()
+		return true
+	default:
+		m.mu.Lock()
+		m.unref(key, l)
+		m.mu.Unlock()
+		return false
+	}
+}
+
+// Unlock unlocks the mutex for `key`. Like sync.Mutex, it panics if the
+// mutex isn't locked.
+func (m *MutexKV) Unlock(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.locks[key]
+	if !ok {
+		panic(fmt.Sprintf("mutexkv: unlock of unlocked key %q", key))
+	}
+	select {
+	case <-l.ch:
+	default:
+		panic(fmt.Sprintf("mutexkv: unlock of unlocked key %q", key))
+	}
+	m.unref(key, l)
+}
+
+// Stats returns the contention on `m` since it was created.
+func (m *MutexKV) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
@@ -0,0 +1,104 @@
+package mutexkv
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMutexKVSerializes(t *testing.T) {
+	var (
+		m       MutexKV
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		running int
+		maxSeen int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.Lock(context.Background(), "sg-1"); err != nil {
+				t.Error(err)
+				return
+			}
+			defer m.Unlock("sg-1")
+			mu.Lock()
+			running++
+			if running > maxSeen {
+				maxSeen = running
+			}
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if maxSeen != 1 {
+		t.Errorf("expected at most 1 holder at a time, saw %d", maxSeen)
+	}
+	if got := m.Stats().Acquired; got != 10 {
+		t.Errorf("expected 10 acquisitions, got %d", got)
+	}
+	if len(m.locks) != 0 {
+		t.Errorf("expected unused locks to be removed, got %d", len(m.locks))
+	}
+}
+
+func TestMutexKVIndependentKeys(t *testing.T) {
+	var m MutexKV
+	if !m.TryLock("a") {
+		t.Fatal("expected to lock a")
+	}
+	if !m.TryLock("b") {
+		t.Fatal("expected to lock b while a is held")
+	}
+	if m.TryLock("a") {
+		t.Fatal("expected not to lock a twice")
+	}
+	m.Unlock("a")
+	m.Unlock("b")
+	if !m.TryLock("a") {
+		t.Fatal("expected to lock a after unlocking it")
+	}
+	m.Unlock("a")
+}
+
+func TestMutexKVLockCancelled(t *testing.T) {
+	var m MutexKV
+	if err := m.Lock(context.Background(), "a"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Lock(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	m.Unlock("a")
+
+	stats := m.Stats()
+	stats.Wait = 0
+	expected := Stats{Acquired: 1, Contended: 1, Cancelled: 1}
+	if diff := cmp.Diff(expected, stats); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if len(m.locks) != 0 {
+		t.Errorf("expected unused locks to be removed, got %d", len(m.locks))
+	}
+}
+
+func TestMutexKVUnlockUnlocked(t *testing.T) {
+	defer func() {
+		if r := recover(); r != `mutexkv: unlock of unlocked key "a"` {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	var m MutexKV
+	m.Unlock("a")
+}
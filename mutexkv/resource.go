@@ -0,0 +1,140 @@
+package mutexkv
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfidentity"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfplan"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfrouter"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// KeyFunc returns the key to lock while applying a change to a resource,
+// given its prior and planned states. Either may be null, when the resource
+// is being created or destroyed. If the key is empty, no lock is taken.
+type KeyFunc func(ctx context.Context, prior, planned tftypes.Value) (string, error)
+
+// Attribute returns a KeyFunc that uses the string attribute at `path`, read
+// from the planned state, or from the prior state when the resource is being
+// destroyed. It's typically the ID of the shared object the resource
+// changes.
+func Attribute(path *tftypes.AttributePath) KeyFunc {
+	return func(ctx context.Context, prior, planned tftypes.Value) (string, error) {
+		val := planned
+		if val.IsNull() {
+			val = prior
+		}
+		attr, _, err := tftypes.WalkAttributePath(val, path)
+		if err != nil {
+			return "", err
+		}
+		v, ok := attr.(tftypes.Value)
+		if !ok {
+			return "", path.NewErrorf("can't use %T as a lock key", attr)
+		}
+		if !v.IsKnown() || v.IsNull() {
+			return "", nil
+		}
+		var key string
+		if err := v.As(&key); err != nil {
+			return "", path.NewError(err)
+		}
+		return key, nil
+	}
+}
+
+// Resource returns a tfrouter.Resource that wraps `res`, holding the lock in
+// `m` for the key returned by `key` while ApplyResourceChange runs. The
+// other RPCs aren't locked.
+func Resource(res tfrouter.Resource, m *MutexKV, key KeyFunc) tfrouter.Resource {
+	return &resource{Resource: res, m: m, key: key}
+}
+
+type resource struct {
+	tfrouter.Resource
+	m   *MutexKV
+	key KeyFunc
+}
+
+// Validators forwards to the wrapped resource, so that its Validators are
+// still run by tfrouter.Router.
+func (r *resource) Validators() *tfvalidate.Registry {
+	if v, ok := r.Resource.(tfvalidate.Validated); ok {
+		return v.Validators()
+	}
+	return nil
+}
+
+// IdentitySchema forwards to the wrapped resource, so that tfrouter.Router
+// still advertises its identity schema.
+func (r *resource) IdentitySchema() *tfprotov5.ResourceIdentitySchema {
+	if i, ok := r.Resource.(tfidentity.Identified); ok {
+		return i.IdentitySchema()
+	}
+	return nil
+}
+
+// PlansDestroy forwards to the wrapped resource, so that tfrouter.Router
+// only plans its destroys if it doesn't.
+func (r *resource) PlansDestroy() bool {
+	p, ok := r.Resource.(tfrouter.DestroyPlanner)
+	return ok && p.PlansDestroy()
+}
+
+// MovesState forwards to the wrapped resource, so that tfrouter.Router
+// still enables the MoveResourceState capability for it.
+func (r *resource) MovesState() bool {
+	m, ok := r.Resource.(tfrouter.StateMover)
+	return ok && m.MovesState()
+}
+
+// Modifiers forwards to the wrapped resource, so that its Modifiers are
+// still documented.
+func (r *resource) Modifiers() *tfplan.Registry {
+	if m, ok := r.Resource.(tfplan.Modified); ok {
+		return m.Modifiers()
+	}
+	return nil
+}
+
+func (r *resource) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	key, err := r.lockKey(ctx, req)
+	if err != nil {
+		return &tfprotov5.ApplyResourceChangeResponse{
+			NewState:    req.PriorState,
+			Diagnostics: []*tfprotov5.Diagnostic{diag.Error("Error locking resource", err)},
+		}, nil
+	}
+	if key != "" {
+		if err := r.m.Lock(ctx, key); err != nil {
+			return &tfprotov5.ApplyResourceChangeResponse{
+				NewState: req.PriorState,
+				Diagnostics: []*tfprotov5.Diagnostic{
+					diag.Errorf("Error locking resource", "Gave up waiting for the lock on %q: %s", key, err),
+				},
+			}, nil
+		}
+		defer r.m.Unlock(key)
+	}
+	return r.Resource.ApplyResourceChange(ctx, req)
+}
+
+func (r *resource) lockKey(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (string, error) {
+	typ := r.Schema().ValueType()
+	prior, planned := tftypes.NewValue(typ, nil), tftypes.NewValue(typ, nil)
+	var err error
+	if req.PriorState != nil {
+		if prior, err = req.PriorState.Unmarshal(typ); err != nil {
+			return "", err
+		}
+	}
+	if req.PlannedState != nil {
+		if planned, err = req.PlannedState.Unmarshal(typ); err != nil {
+			return "", err
+		}
+	}
+	return r.key(ctx, prior, planned)
+}
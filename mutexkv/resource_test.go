@@ -0,0 +1,206 @@
+package mutexkv
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfresource"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfrouter"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var ruleSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "group_id", Type: tftypes.String, Required: true},
+		},
+	},
+}
+
+type ruleResource struct {
+	tfprotov5.ResourceServer
+
+	mu      sync.Mutex
+	running map[string]int
+	overlap bool
+}
+
+func (r *ruleResource) Schema() *tfprotov5.Schema {
+	return ruleSchema
+}
+
+func (r *ruleResource) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	val, err := req.PlannedState.Unmarshal(ruleSchema.ValueType())
+	if err != nil {
+		return nil, err
+	}
+	var attrs map[string]tftypes.Value
+	if err := val.As(&attrs); err != nil {
+		return nil, err
+	}
+	var group string
+	if err := attrs["group_id"].As(&group); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.running[group]++
+	if r.running[group] > 1 {
+		r.overlap = true
+	}
+	r.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	r.mu.Lock()
+	r.running[group]--
+	r.mu.Unlock()
+	return &tfprotov5.ApplyResourceChangeResponse{NewState: req.PlannedState}, nil
+}
+
+func ruleValue(t *testing.T, group interface{}) *tfprotov5.DynamicValue {
+	t.Helper()
+	typ := ruleSchema.ValueType()
+	dv, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, map[string]tftypes.Value{
+		"group_id": tftypes.NewValue(tftypes.String, group),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+func TestResource(t *testing.T) {
+	inner := &ruleResource{running: map[string]int{}}
+	m := &MutexKV{}
+	res := Resource(inner, m, Attribute(tftypes.NewAttributePath().WithAttributeName("group_id")))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		group := "sg-1"
+		if i%2 == 1 {
+			group = "sg-2"
+		}
+		planned := ruleValue(t, group)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := res.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+				PlannedState: planned,
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, d := range resp.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+			}
+		}()
+	}
+	wg.Wait()
+	if inner.overlap {
+		t.Error("expected changes to the same group to be serialized")
+	}
+	if got := m.Stats().Acquired; got != 10 {
+		t.Errorf("expected 10 acquisitions, got %d", got)
+	}
+}
+
+func TestResourceLockCancelled(t *testing.T) {
+	inner := &ruleResource{running: map[string]int{}}
+	m := &MutexKV{}
+	if !m.TryLock("sg-1") {
+		t.Fatal("expected to lock sg-1")
+	}
+	defer m.Unlock("sg-1")
+	res := Resource(inner, m, Attribute(tftypes.NewAttributePath().WithAttributeName("group_id")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, err := res.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		PlannedState: ruleValue(t, "sg-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Detail != `Gave up waiting for the lock on "sg-1": context canceled` {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+}
+
+type rule struct {
+	GroupID string `tfsdk:"group_id"`
+}
+
+type identifiedRuleResource struct{}
+
+func (identifiedRuleResource) Create(ctx context.Context, planned rule) (rule, error) {
+	return planned, nil
+}
+
+func (identifiedRuleResource) Read(ctx context.Context, current rule) (rule, error) {
+	return current, nil
+}
+
+func (identifiedRuleResource) Update(ctx context.Context, prior, planned rule) (rule, error) {
+	return planned, nil
+}
+
+func (identifiedRuleResource) Delete(ctx context.Context, current rule) error {
+	return nil
+}
+
+func (identifiedRuleResource) IdentitySchema() *tfprotov5.ResourceIdentitySchema {
+	return &tfprotov5.ResourceIdentitySchema{
+		IdentityAttributes: []*tfprotov5.ResourceIdentitySchemaAttribute{
+			{Name: "group_id", Type: tftypes.String, RequiredForImport: true},
+		},
+	}
+}
+
+func TestResourceRouted(t *testing.T) {
+	ctx := context.Background()
+	inner := tfresource.NewServer[rule](ruleSchema, identifiedRuleResource{})
+	router := &tfrouter.Router{
+		Resources: map[string]tfrouter.Resource{
+			"example_rule": Resource(inner, &MutexKV{}, Attribute(tftypes.NewAttributePath().WithAttributeName("group_id"))),
+		},
+	}
+
+	identities, err := router.GetResourceIdentitySchemas(ctx, &tfprotov5.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(inner.IdentitySchema(), identities.IdentitySchemas["example_rule"]); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	caps := router.ServerCapabilities()
+	if !caps.PlanDestroy {
+		t.Error("expected the PlanDestroy capability to be enabled")
+	}
+	if caps.MoveResourceState {
+		t.Error("expected the MoveResourceState capability to be disabled")
+	}
+
+	typ := ruleSchema.ValueType()
+	null, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apply, err := router.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:     "example_rule",
+		PriorState:   &null,
+		PlannedState: ruleValue(t, "sg-1"),
+		Config:       ruleValue(t, "sg-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range apply.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+	if apply.NewIdentity == nil {
+		t.Error("expected a new identity")
+	}
+}
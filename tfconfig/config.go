@@ -0,0 +1,251 @@
+// Package tfconfig decodes provider configuration, as sent in the
+// ConfigureProvider RPC, into Go structs.
+//
+// Most providers allow their configuration to be set using environment
+// variables as well as in the provider block, need to reject configuration
+// that won't be known until apply, and report problems as diagnostics
+// pointing at the attribute responsible. Decoder handles all three:
+//
+//	type config struct {
+//		Endpoint *string `tfsdk:"endpoint" env:"MYAPI_ENDPOINT"`
+//		Token    string  `tfsdk:"token,required" env:"MYAPI_TOKEN,MYAPI_API_TOKEN"`
+//	}
+//
+//	var c config
+//	resp.Diagnostics = tfconfig.Decode(ctx, schema, req.Config, &c)
+package tfconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/parse"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/structtag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Decoder decodes provider configuration into a struct using `tfsdk` tags,
+// as with asgotypes.Decode, along with an `env:"NAME,..."` tag listing
+// environment variables that are used, in order, when the attribute isn't
+// set in the configuration. The first one set to a non-empty value is parsed
+// according to the attribute's type, which must be a string, number, or
+// bool.
+//
+// The tfsdk tag's "required" option reports an error diagnostic when the
+// attribute isn't set in the configuration or by any of its environment
+// variables, naming the variables.
+//
+// Fields holding structs, or pointers to them, are treated as nested blocks
+// and their tags are read too.
+type Decoder struct {
+	// AllowUnknown allows the configuration to contain values that won't
+	// be known until apply, which happens when the provider is configured
+	// using attributes of resources that haven't been created yet. They
+	// are decoded as their zero value. If false, each one is reported as
+	// an error.
+	AllowUnknown bool
+
+	// Validators, if set, are run against the configuration after
+	// environment variables have been applied.
+	Validators *tfvalidate.Registry
+}
+
+// Decode decodes `config` into `target` using a Decoder with the default
+// settings.
+func Decode(ctx context.Context, schema *tfprotov5.Schema, config *tfprotov5.DynamicValue, target interface{}) []*tfprotov5.Diagnostic {
+	var d Decoder
+	return d.Decode(ctx, schema, config, target)
+}
+
+// Decode decodes `config`, the configuration of a provider described by
+// `schema`, into `target`, which must be a non-nil pointer to a struct. Any
+// problems are returned as diagnostics, and `target` should only be used if
+// none of them are errors.
+func (d *Decoder) Decode(ctx context.Context, schema *tfprotov5.Schema, config *tfprotov5.DynamicValue, target interface{}) []*tfprotov5.Diagnostic {
+	typ := schema.ValueType()
+	val := tftypes.NewValue(typ, nil)
+	if config != nil {
+		var err error
+		val, err = config.Unmarshal(typ)
+		if err != nil {
+			return []*tfprotov5.Diagnostic{diag.Error("Error reading provider configuration", err)}
+		}
+	}
+	fields, err := structFields(target)
+	if err != nil {
+		return []*tfprotov5.Diagnostic{diag.Error("Error reading provider configuration", err)}
+	}
+
+	var diags []*tfprotov5.Diagnostic
+	if !d.AllowUnknown {
+		diags = append(diags, unknowns(val)...)
+		if len(diags) > 0 {
+			return diags
+		}
+	}
+
+	val, err = tftypes.Transform(val, func(path *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		f, ok := fields[pathKey(path)]
+		if !ok || !v.IsNull() || len(f.envs) == 0 {
+			return v, nil
+		}
+		env, ok, err := parse.Env(v.Type(), f.envs)
+		if err != nil || !ok {
+			return v, err
+		}
+		return env, nil
+	})
+	if err != nil {
+		return append(diags, diag.Error("Invalid provider configuration", err))
+	}
+
+	diags = append(diags, required(val, fields)...)
+	diags = append(diags, d.Validators.Validate(ctx, val)...)
+	for _, diagnostic := range diags {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError {
+			return diags
+		}
+	}
+
+	dec := asgotypes.Decoder{AllowUnknown: d.AllowUnknown}
+	if err := dec.DecodeContext(ctx, val, target); err != nil {
+		diags = append(diags, diag.Error("Invalid provider configuration", err))
+	}
+	return diags
+}
+
+// unknowns returns an error diagnostic for each unknown value in `val`,
+// without descending into them.
+func unknowns(val tftypes.Value) []*tfprotov5.Diagnostic {
+	var diags []*tfprotov5.Diagnostic
+	_ = tftypes.Walk(val, func(path *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+		if v.IsKnown() {
+			return true, nil
+		}
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Unknown provider configuration",
+			Detail:    "The provider can't be configured because this value won't be known until apply. Use a value that is known during planning instead.",
+			Attribute: path,
+		})
+		return false, nil
+	})
+	return diags
+}
+
+// required returns an error diagnostic for each field tagged as required
+// that is null in `val`.
+func required(val tftypes.Value, fields map[string]field) []*tfprotov5.Diagnostic {
+	var diags []*tfprotov5.Diagnostic
+	for _, key := range sorted.Keys(fields) {
+		f := fields[key]
+		if !f.required {
+			continue
+		}
+		v, err := valueAt(val, f.path)
+		if err != nil || !v.IsNull() {
+			continue
+		}
+		detail := "The attribute must be set in the provider configuration."
+		if len(f.envs) > 0 {
+			detail = fmt.Sprintf("The attribute must be set in the provider configuration, or using the %s environment variable.", strings.Join(f.envs, " or "))
+		}
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Missing provider configuration",
+			Detail:    detail,
+			Attribute: f.path,
+		})
+	}
+	return diags
+}
+
+// valueAt returns the value at `path` in `val`. Attributes inside null
+// blocks are treated as null.
+func valueAt(val tftypes.Value, path *tftypes.AttributePath) (tftypes.Value, error) {
+	for _, step := range path.Steps() {
+		if val.IsNull() {
+			return val, nil
+		}
+		var attrs map[string]tftypes.Value
+		if err := val.As(&attrs); err != nil {
+			return tftypes.Value{}, err
+		}
+		val = attrs[string(step.(tftypes.AttributeName))]
+	}
+	return val, nil
+}
+
+// field holds the tags of a struct field.
+type field struct {
+	path     *tftypes.AttributePath
+	envs     []string
+	required bool
+}
+
+// structFields returns the tagged fields of the struct `target` points to,
+// keyed by pathKey.
+func structFields(target interface{}) (map[string]field, error) {
+	typ := reflect.TypeOf(target)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot decode provider configuration into %T, a pointer to a struct is required", target)
+	}
+	fields := map[string]field{}
+	if err := addFields(fields, tftypes.NewAttributePath(), typ.Elem()); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func addFields(fields map[string]field, path *tftypes.AttributePath, typ reflect.Type) error {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag, ok, err := structtag.Lookup(typ, sf)
+		if err != nil {
+			return err
+		}
+		if !ok || tag.Remain {
+			continue
+		}
+		f := field{path: path.WithAttributeName(tag.Name), required: tag.Required}
+		if env := sf.Tag.Get("env"); env != "" {
+			for _, e := range strings.Split(env, ",") {
+				f.envs = append(f.envs, strings.TrimSpace(e))
+			}
+		}
+		if len(f.envs) > 0 || f.required {
+			fields[pathKey(f.path)] = f
+		}
+		nested := sf.Type
+		if nested.Kind() == reflect.Ptr {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested != reflect.TypeOf(tftypes.Value{}) && nested.PkgPath() != "math/big" {
+			if err := addFields(fields, f.path, nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pathKey returns a key identifying `path`, or "" if it contains steps other
+// than attribute names.
+func pathKey(path *tftypes.AttributePath) string {
+	names := make([]string, 0, len(path.Steps()))
+	for _, step := range path.Steps() {
+		name, ok := step.(tftypes.AttributeName)
+		if !ok {
+			return ""
+		}
+		names = append(names, string(name))
+	}
+	return strings.Join(names, ".")
+}
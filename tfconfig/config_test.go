@@ -0,0 +1,200 @@
+package tfconfig
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "endpoint", Type: tftypes.String, Optional: true},
+			{Name: "token", Type: tftypes.String, Optional: true, Sensitive: true},
+			{Name: "retries", Type: tftypes.Number, Optional: true},
+		},
+		BlockTypes: []*tfprotov5.SchemaNestedBlock{
+			{
+				TypeName: "proxy",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeSingle,
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "url", Type: tftypes.String, Optional: true},
+					},
+				},
+			},
+		},
+	},
+}
+
+var testType = testSchema.ValueType().(tftypes.Object)
+
+type testProxy struct {
+	URL string `tfsdk:"url" env:"TFCONFIG_TEST_PROXY"`
+}
+
+type testConfig struct {
+	Endpoint *string    `tfsdk:"endpoint" env:"TFCONFIG_TEST_ENDPOINT"`
+	Token    string     `tfsdk:"token,required" env:"TFCONFIG_TEST_TOKEN,TFCONFIG_TEST_API_TOKEN"`
+	Retries  int        `tfsdk:"retries" env:"TFCONFIG_TEST_RETRIES"`
+	Proxy    *testProxy `tfsdk:"proxy"`
+}
+
+func testValue(t *testing.T, endpoint, token, retries interface{}, proxy map[string]tftypes.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+	proxyType := testType.AttributeTypes["proxy"]
+	var proxyVal interface{}
+	if proxy != nil {
+		proxyVal = proxy
+	}
+	dv, err := tfprotov5.NewDynamicValue(testType, tftypes.NewValue(testType, map[string]tftypes.Value{
+		"endpoint": tftypes.NewValue(tftypes.String, endpoint),
+		"token":    tftypes.NewValue(tftypes.String, token),
+		"retries":  tftypes.NewValue(tftypes.Number, retries),
+		"proxy":    tftypes.NewValue(proxyType, proxyVal),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestDecode(t *testing.T) {
+	type testCase struct {
+		decoder       Decoder
+		config        *tfprotov5.DynamicValue
+		env           map[string]string
+		expected      testConfig
+		expectedDiags []*tfprotov5.Diagnostic
+	}
+	cases := map[string]testCase{
+		"config": {
+			config: testValue(t, "https://example.com", "secret", 3, nil),
+			env: map[string]string{
+				"TFCONFIG_TEST_ENDPOINT": "https://env.example.com",
+				"TFCONFIG_TEST_TOKEN":    "env-secret",
+			},
+			expected: testConfig{Endpoint: strPtr("https://example.com"), Token: "secret", Retries: 3},
+		},
+		"env": {
+			config: testValue(t, nil, nil, nil, map[string]tftypes.Value{
+				"url": tftypes.NewValue(tftypes.String, nil),
+			}),
+			env: map[string]string{
+				"TFCONFIG_TEST_ENDPOINT":  "https://env.example.com",
+				"TFCONFIG_TEST_API_TOKEN": "env-secret",
+				"TFCONFIG_TEST_RETRIES":   "5",
+				"TFCONFIG_TEST_PROXY":     "http://proxy",
+			},
+			expected: testConfig{
+				Endpoint: strPtr("https://env.example.com"),
+				Token:    "env-secret",
+				Retries:  5,
+				Proxy:    &testProxy{URL: "http://proxy"},
+			},
+		},
+		"env-order": {
+			config: testValue(t, nil, nil, nil, nil),
+			env: map[string]string{
+				"TFCONFIG_TEST_TOKEN":     "first",
+				"TFCONFIG_TEST_API_TOKEN": "second",
+			},
+			expected: testConfig{Token: "first"},
+		},
+		"bad-env": {
+			config: testValue(t, nil, "secret", nil, nil),
+			env: map[string]string{
+				"TFCONFIG_TEST_RETRIES": "lots",
+			},
+			expectedDiags: []*tfprotov5.Diagnostic{
+				{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Invalid provider configuration",
+					Detail:    `AttributeName("retries"): environment variable TFCONFIG_TEST_RETRIES: can't parse "lots" as a number`,
+					Attribute: tftypes.NewAttributePath().WithAttributeName("retries"),
+				},
+			},
+		},
+		"missing": {
+			config: testValue(t, nil, nil, nil, nil),
+			expectedDiags: []*tfprotov5.Diagnostic{
+				{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Missing provider configuration",
+					Detail:    "The attribute must be set in the provider configuration, or using the TFCONFIG_TEST_TOKEN or TFCONFIG_TEST_API_TOKEN environment variable.",
+					Attribute: tftypes.NewAttributePath().WithAttributeName("token"),
+				},
+			},
+		},
+		"unknown": {
+			config: testValue(t, tftypes.UnknownValue, "secret", nil, nil),
+			expectedDiags: []*tfprotov5.Diagnostic{
+				{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Unknown provider configuration",
+					Detail:    "The provider can't be configured because this value won't be known until apply. Use a value that is known during planning instead.",
+					Attribute: tftypes.NewAttributePath().WithAttributeName("endpoint"),
+				},
+			},
+		},
+		"allow-unknown": {
+			decoder:  Decoder{AllowUnknown: true},
+			config:   testValue(t, tftypes.UnknownValue, "secret", nil, nil),
+			expected: testConfig{Token: "secret"},
+		},
+		"validators": {
+			decoder: Decoder{Validators: func() *tfvalidate.Registry {
+				r := &tfvalidate.Registry{}
+				r.Add(tftypes.NewAttributePath().WithAttributeName("token"), tfvalidate.StringMatches(regexp.MustCompile(`^tok-`), "Value must start with tok-"))
+				return r
+			}()},
+			config: testValue(t, nil, nil, nil, nil),
+			env: map[string]string{
+				"TFCONFIG_TEST_TOKEN": "secret",
+			},
+			expectedDiags: []*tfprotov5.Diagnostic{
+				{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Invalid attribute value",
+					Detail:    "Value must start with tok-, got \"secret\".",
+					Attribute: tftypes.NewAttributePath().WithAttributeName("token"),
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			for _, k := range []string{"TFCONFIG_TEST_ENDPOINT", "TFCONFIG_TEST_TOKEN", "TFCONFIG_TEST_API_TOKEN", "TFCONFIG_TEST_RETRIES", "TFCONFIG_TEST_PROXY"} {
+				t.Setenv(k, tc.env[k])
+			}
+			var got testConfig
+			diags := tc.decoder.Decode(context.Background(), testSchema, tc.config, &got)
+			if diff := cmp.Diff(tc.expectedDiags, diags); diff != "" {
+				t.Fatalf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalidTag(t *testing.T) {
+	var target struct {
+		Token string `tfsdk:"token,mandatory"`
+	}
+	diags := Decode(context.Background(), testSchema, testValue(t, nil, nil, nil, nil), &target)
+	if len(diags) != 1 || diags[0].Detail != `field Token of struct { Token string "tfsdk:\"token,mandatory\"" } has unknown tfsdk tag option "mandatory"` {
+		t.Errorf("unexpected diagnostics: %+v", diags)
+	}
+}
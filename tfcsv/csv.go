@@ -0,0 +1,347 @@
+// Package tfcsv converts lists and sets of objects to and from CSV and TSV,
+// for data sources that surface tabular API results, or read them from
+// files.
+//
+// Each object is a row, with a column for each attribute, and the first row
+// is a header naming the columns. Strings are written as they are, numbers
+// in full, without exponents, and bools as true or false. Lists, sets,
+// tuples, maps, and objects nested in the rows are written as JSON.
+package tfcsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	tfString tftypes.Type = tftypes.String
+	tfNumber tftypes.Type = tftypes.Number
+	tfBool   tftypes.Type = tftypes.Bool
+	tfList   tftypes.Type = tftypes.List{}
+	tfSet    tftypes.Type = tftypes.Set{}
+	tfObject tftypes.Type = tftypes.Object{}
+	tfMap    tftypes.Type = tftypes.Map{}
+)
+
+// Converter converts values to and from CSV.
+type Converter struct {
+	// Comma is the field delimiter, ',' by default. Set it to '\t' for
+	// TSV.
+	Comma rune
+
+	// Columns are the attributes written, in order. By default, every
+	// attribute is written, sorted by name, so the output doesn't depend
+	// on map iteration order.
+	Columns []string
+
+	// Null is written for null values, and fields equal to it are read as
+	// null. By default, it's the empty string, so null and empty strings
+	// are written the same way, and empty fields are read as null.
+	Null string
+}
+
+// Marshal returns `val` as CSV written by a Converter with the default
+// settings.
+func Marshal(val tftypes.Value) ([]byte, error) {
+	var c Converter
+	var b bytes.Buffer
+	if err := c.Write(&b, val); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Unmarshal reads the CSV `data` as a list of objects of type `typ` with a
+// Converter with the default settings.
+func Unmarshal(data []byte, typ tftypes.Object) (tftypes.Value, error) {
+	var c Converter
+	return c.Read(bytes.NewReader(data), typ)
+}
+
+func (c Converter) comma() rune {
+	if c.Comma == 0 {
+		return ','
+	}
+	return c.Comma
+}
+
+// Write writes `val`, a known list or set of objects, to `w` as CSV. The
+// rows of sets are sorted, so their output is deterministic too. A null
+// `val` is written as just the header.
+func (c Converter) Write(w io.Writer, val tftypes.Value) error {
+	typ := val.Type()
+	if !typ.Is(tfList) && !typ.Is(tfSet) {
+		return fmt.Errorf("cannot write %s as CSV, only lists and sets of objects", typ)
+	}
+	var elemType tftypes.Type
+	if l, ok := typ.(tftypes.List); ok {
+		elemType = l.ElementType
+	} else {
+		elemType = typ.(tftypes.Set).ElementType
+	}
+	obj, ok := elemType.(tftypes.Object)
+	if !ok {
+		return fmt.Errorf("cannot write %s as CSV, only lists and sets of objects", typ)
+	}
+	cols := c.Columns
+	if cols == nil {
+		for name := range obj.AttributeTypes {
+			cols = append(cols, name)
+		}
+		sort.Strings(cols)
+	}
+	for _, col := range cols {
+		if _, ok := obj.AttributeTypes[col]; !ok {
+			return fmt.Errorf("column %q is not an attribute of %s", col, elemType)
+		}
+	}
+	if !val.IsKnown() {
+		return fmt.Errorf("cannot write an unknown value as CSV")
+	}
+
+	var rows [][]string
+	if !val.IsNull() {
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return err
+		}
+		for i, elem := range elems {
+			path := tftypes.NewAttributePath().WithElementKeyInt(i)
+			if typ.Is(tfSet) {
+				path = tftypes.NewAttributePath().WithElementKeyValue(elem)
+			}
+			row, err := c.row(path, elem, cols)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, row)
+		}
+	}
+	if typ.Is(tfSet) {
+		sort.Slice(rows, func(i, j int) bool {
+			for k := range rows[i] {
+				if rows[i][k] != rows[j][k] {
+					return rows[i][k] < rows[j][k]
+				}
+			}
+			return false
+		})
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = c.comma()
+	if err := cw.Write(cols); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// row returns the fields of the object `elem` in the columns `cols`.
+func (c Converter) row(path *tftypes.AttributePath, elem tftypes.Value, cols []string) ([]string, error) {
+	if !elem.IsKnown() {
+		return nil, path.NewErrorf("cannot write an unknown value as CSV")
+	}
+	if elem.IsNull() {
+		return nil, path.NewErrorf("cannot write a null row")
+	}
+	var attrs map[string]tftypes.Value
+	if err := elem.As(&attrs); err != nil {
+		return nil, path.NewError(err)
+	}
+	row := make([]string, 0, len(cols))
+	for _, col := range cols {
+		field, err := c.field(path.WithAttributeName(col), attrs[col])
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, field)
+	}
+	return row, nil
+}
+
+// field returns `val` formatted as a field.
+func (c Converter) field(path *tftypes.AttributePath, val tftypes.Value) (string, error) {
+	if !val.IsFullyKnown() {
+		return "", path.NewErrorf("cannot write an unknown value as CSV")
+	}
+	if val.IsNull() {
+		return c.Null, nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tfString):
+		var s string
+		err := val.As(&s)
+		return s, err
+	case typ.Is(tfNumber):
+		f := new(big.Float)
+		err := val.As(&f)
+		return f.Text('f', -1), err
+	case typ.Is(tfBool):
+		var b bool
+		err := val.As(&b)
+		return strconv.FormatBool(b), err
+	}
+	v, err := jsonValue(path, val)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", path.NewError(err)
+	}
+	return string(b), nil
+}
+
+// jsonValue returns the known value `val` as a value encoding/json encodes
+// like Terraform would, with numbers in full.
+func jsonValue(path *tftypes.AttributePath, val tftypes.Value) (interface{}, error) {
+	if val.IsNull() {
+		return nil, nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tfString):
+		var s string
+		err := val.As(&s)
+		return s, err
+	case typ.Is(tfNumber):
+		f := new(big.Float)
+		err := val.As(&f)
+		return json.Number(f.Text('f', -1)), err
+	case typ.Is(tfBool):
+		var b bool
+		err := val.As(&b)
+		return b, err
+	case typ.Is(tfObject), typ.Is(tfMap):
+		var attrs map[string]tftypes.Value
+		if err := val.As(&attrs); err != nil {
+			return nil, path.NewError(err)
+		}
+		res := make(map[string]interface{}, len(attrs))
+		for k, attr := range attrs {
+			next := path.WithAttributeName(k)
+			if typ.Is(tfMap) {
+				next = path.WithElementKeyString(k)
+			}
+			v, err := jsonValue(next, attr)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = v
+		}
+		return res, nil
+	}
+	var elems []tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return nil, path.NewError(err)
+	}
+	res := make([]interface{}, 0, len(elems))
+	for i, elem := range elems {
+		next := path.WithElementKeyInt(i)
+		if typ.Is(tfSet) {
+			next = path.WithElementKeyValue(elem)
+		}
+		v, err := jsonValue(next, elem)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+// Read reads CSV from `r` as a list of objects of type `typ`, the inverse
+// of Write. The header row must name attributes of `typ`, and attributes
+// without a column are null.
+func (c Converter) Read(r io.Reader, typ tftypes.Object) (tftypes.Value, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = c.comma()
+	header, err := cr.Read()
+	if err == io.EOF {
+		return tftypes.Value{}, fmt.Errorf("no header row")
+	}
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	seen := make(map[string]bool, len(header))
+	for _, col := range header {
+		if _, ok := typ.AttributeTypes[col]; !ok {
+			return tftypes.Value{}, fmt.Errorf("column %q is not an attribute of %s", col, typ)
+		}
+		if seen[col] {
+			return tftypes.Value{}, fmt.Errorf("column %q appears more than once", col)
+		}
+		seen[col] = true
+	}
+
+	rows := []tftypes.Value{}
+	for i := 0; ; i++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		path := tftypes.NewAttributePath().WithElementKeyInt(i)
+		attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+		for name, attrType := range typ.AttributeTypes {
+			attrs[name] = tftypes.NewValue(attrType, nil)
+		}
+		for j, col := range header {
+			attr, err := c.parse(path.WithAttributeName(col), typ.AttributeTypes[col], record[j])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[col] = attr
+		}
+		if err := tftypes.ValidateValue(typ, attrs); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		rows = append(rows, tftypes.NewValue(typ, attrs))
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: typ}, rows), nil
+}
+
+// parse returns the field `s` as a value of type `typ`.
+func (c Converter) parse(path *tftypes.AttributePath, typ tftypes.Type, s string) (tftypes.Value, error) {
+	if s == c.Null {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	switch {
+	case typ.Is(tfString):
+		return tftypes.NewValue(typ, s), nil
+	case typ.Is(tfNumber):
+		f, _, err := big.ParseFloat(strings.TrimSpace(s), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return tftypes.Value{}, path.NewErrorf("cannot parse %q as a number", s)
+		}
+		return tftypes.NewValue(typ, f), nil
+	case typ.Is(tfBool):
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return tftypes.Value{}, path.NewErrorf("cannot parse %q as a bool", s)
+		}
+		return tftypes.NewValue(typ, b), nil
+	case typ.Is(tftypes.DynamicPseudoType):
+		return tftypes.Value{}, path.NewErrorf("cannot read attributes of type %s from CSV", typ)
+	}
+	val, err := tftypes.ValueFromJSON([]byte(s), typ)
+	if err != nil {
+		return tftypes.Value{}, path.NewErrorf("cannot parse %q as JSON: %s", s, err)
+	}
+	return val, nil
+}
@@ -0,0 +1,191 @@
+package tfcsv
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var rowType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"name":  tftypes.String,
+	"size":  tftypes.Number,
+	"ready": tftypes.Bool,
+	"tags":  tftypes.Map{ElementType: tftypes.String},
+}}
+
+func row(name, size, ready interface{}, tags map[string]tftypes.Value) tftypes.Value {
+	var tagsVal interface{}
+	if tags != nil {
+		tagsVal = tags
+	}
+	return tftypes.NewValue(rowType, map[string]tftypes.Value{
+		"name":  tftypes.NewValue(tftypes.String, name),
+		"size":  tftypes.NewValue(tftypes.Number, size),
+		"ready": tftypes.NewValue(tftypes.Bool, ready),
+		"tags":  tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tagsVal),
+	})
+}
+
+func testRows() []tftypes.Value {
+	huge, _, _ := big.ParseFloat("12345678901234567890.5", 10, 512, big.ToNearestEven)
+	return []tftypes.Value{
+		row("web, primary", huge, true, map[string]tftypes.Value{
+			"env": tftypes.NewValue(tftypes.String, "prod"),
+		}),
+		row("db", big.NewFloat(0.25), false, nil),
+		row(nil, nil, nil, nil),
+	}
+}
+
+func TestWrite(t *testing.T) {
+	type testCase struct {
+		converter Converter
+		val       tftypes.Value
+		expected  string
+	}
+	cases := map[string]testCase{
+		"list": {
+			val: tftypes.NewValue(tftypes.List{ElementType: rowType}, testRows()),
+			expected: `name,ready,size,tags
+"web, primary",true,12345678901234567890.5,"{""env"":""prod""}"
+db,false,0.25,
+,,,
+`,
+		},
+		"set": {
+			val: tftypes.NewValue(tftypes.Set{ElementType: rowType}, testRows()),
+			expected: `name,ready,size,tags
+,,,
+db,false,0.25,
+"web, primary",true,12345678901234567890.5,"{""env"":""prod""}"
+`,
+		},
+		"tsv-columns-null": {
+			converter: Converter{Comma: '\t', Columns: []string{"size", "name"}, Null: "NULL"},
+			val:       tftypes.NewValue(tftypes.List{ElementType: rowType}, testRows()[1:]),
+			expected:  "size\tname\n0.25\tdb\nNULL\tNULL\n",
+		},
+		"null": {
+			val:      tftypes.NewValue(tftypes.List{ElementType: rowType}, nil),
+			expected: "name,ready,size,tags\n",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := tc.converter.Write(&b, tc.val); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	type testCase struct {
+		converter   Converter
+		val         tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"not-objects": {
+			val:         tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+			expectedErr: "cannot write tftypes.List[tftypes.String] as CSV, only lists and sets of objects",
+		},
+		"unknown-column": {
+			converter:   Converter{Columns: []string{"nope"}},
+			val:         tftypes.NewValue(tftypes.List{ElementType: rowType}, nil),
+			expectedErr: `column "nope" is not an attribute of tftypes.Object["name":tftypes.String, "ready":tftypes.Bool, "size":tftypes.Number, "tags":tftypes.Map[tftypes.String]]`,
+		},
+		"unknown-field": {
+			val: tftypes.NewValue(tftypes.List{ElementType: rowType}, []tftypes.Value{
+				row(tftypes.UnknownValue, nil, nil, nil),
+			}),
+			expectedErr: `ElementKeyInt(0).AttributeName("name"): cannot write an unknown value as CSV`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			err := tc.converter.Write(&bytes.Buffer{}, tc.val)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	val := tftypes.NewValue(tftypes.List{ElementType: rowType}, testRows())
+	b, err := Marshal(val)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := Unmarshal(b, rowType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(val, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestRead(t *testing.T) {
+	c := Converter{Comma: '\t'}
+	got, err := c.Read(strings.NewReader("ready\tname\nTRUE\tweb\n0\t\n"), rowType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := tftypes.NewValue(tftypes.List{ElementType: rowType}, []tftypes.Value{
+		row("web", nil, true, nil),
+		row(nil, nil, false, nil),
+	})
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	type testCase struct {
+		data        string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"empty": {
+			data:        "",
+			expectedErr: "no header row",
+		},
+		"unknown-column": {
+			data:        "name,nope\n",
+			expectedErr: `column "nope" is not an attribute of tftypes.Object["name":tftypes.String, "ready":tftypes.Bool, "size":tftypes.Number, "tags":tftypes.Map[tftypes.String]]`,
+		},
+		"duplicate-column": {
+			data:        "name,name\n",
+			expectedErr: `column "name" appears more than once`,
+		},
+		"number": {
+			data:        "name,size\nweb,big\n",
+			expectedErr: `ElementKeyInt(0).AttributeName("size"): cannot parse "big" as a number`,
+		},
+		"json": {
+			data:        "tags\n[1]\n",
+			expectedErr: `ElementKeyInt(0).AttributeName("tags"): cannot parse "[1]" as JSON: invalid JSON, expected "{", got "["`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(tc.data), rowType)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Package tfdatasource provides a typed abstraction over the data source
+// RPCs of tfprotov5.DataSourceServer.
+//
+// Providers supply a DataSource, often just a ReadFunc, that accepts the
+// data source's configuration decoded into a struct T and returns a result
+// struct R. NewServer takes care of decoding the configuration, skipping the
+// read while the configuration still contains unknown values, and encoding
+// the result as the data source's state. Both T and R use `tfsdk` struct tags
+// as understood by the asgotypes package, and may be the same type.
+package tfdatasource
+
+import (
+	"context"
+)
+
+// DataSource reads a data source.
+type DataSource[T, R any] interface {
+	// Read returns the data identified by `config`. Any attribute of the
+	// data source that the result leaves null will be populated from
+	// `config`, so R only needs to include the computed attributes.
+	Read(ctx context.Context, config T) (R, error)
+}
+
+// ReadFunc is a function that implements DataSource.
+type ReadFunc[T, R any] func(ctx context.Context, config T) (R, error)
+
+// Read calls `f`.
+func (f ReadFunc[T, R]) Read(ctx context.Context, config T) (R, error) {
+	return f(ctx, config)
+}
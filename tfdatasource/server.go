@@ -0,0 +1,162 @@
+package tfdatasource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ tfprotov5.DataSourceServer = &Server[struct{}, struct{}]{}
+
+// Server is a tfprotov5.DataSourceServer for a single data source type,
+// backed by a DataSource.
+type Server[T, R any] struct {
+	schema     *tfprotov5.Schema
+	typ        tftypes.Type
+	dataSource DataSource[T, R]
+}
+
+// NewServer returns a Server for the data source described by `schema`,
+// backed by `ds`.
+func NewServer[T, R any](schema *tfprotov5.Schema, ds DataSource[T, R]) *Server[T, R] {
+	return &Server[T, R]{
+		schema:     schema,
+		typ:        schema.ValueType(),
+		dataSource: ds,
+	}
+}
+
+// Schema returns the data source's schema.
+func (s *Server[T, R]) Schema() *tfprotov5.Schema {
+	return s.schema
+}
+
+// Validators returns the DataSource's Validators, if it implements
+// tfvalidate.Validated.
+func (s *Server[T, R]) Validators() *tfvalidate.Registry {
+	if v, ok := s.dataSource.(tfvalidate.Validated); ok {
+		return v.Validators()
+	}
+	return nil
+}
+
+// ValidateDataSourceConfig checks that the config can be decoded into T.
+func (s *Server[T, R]) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	resp := &tfprotov5.ValidateDataSourceConfigResponse{}
+	config, err := s.unmarshal(req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading configuration", err))
+		return resp, nil
+	}
+	var decoded T
+	d := asgotypes.Decoder{AllowUnknown: true}
+	if err := d.DecodeContext(ctx, config, &decoded); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Invalid configuration", err))
+	}
+	return resp, nil
+}
+
+// ReadDataSource decodes the config and calls DataSource.Read, encoding the
+// result as the new state. If the config isn't wholly known yet, Read isn't
+// called, and the computed attributes the config doesn't set are returned as
+// unknown.
+func (s *Server[T, R]) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	resp := &tfprotov5.ReadDataSourceResponse{}
+	config, err := s.unmarshal(req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading configuration", err))
+		return resp, nil
+	}
+
+	var state tftypes.Value
+	if !config.IsFullyKnown() {
+		state, err = s.withComputedUnknown(config)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading data source", err))
+			return resp, nil
+		}
+	} else {
+		var decoded T
+		if err := asgotypes.DecodeContext(ctx, config, &decoded); err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Invalid configuration", err))
+			return resp, nil
+		}
+		result, err := s.dataSource.Read(ctx, decoded)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading data source", err))
+			return resp, nil
+		}
+		state, err = asgotypes.EncodeContext(ctx, s.typ, result)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading data source", err))
+			return resp, nil
+		}
+		state, err = mergeConfig(s.typ, state, config)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading data source", err))
+			return resp, nil
+		}
+	}
+
+	dv, err := tfprotov5.NewDynamicValue(s.typ, state)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading data source", err))
+		return resp, nil
+	}
+	resp.State = &dv
+	return resp, nil
+}
+
+func (s *Server[T, R]) unmarshal(dv *tfprotov5.DynamicValue) (tftypes.Value, error) {
+	if dv == nil {
+		return tftypes.NewValue(s.typ, nil), nil
+	}
+	return dv.Unmarshal(s.typ)
+}
+
+// withComputedUnknown returns `config` with every computed attribute that
+// config leaves null set to unknown.
+func (s *Server[T, R]) withComputedUnknown(config tftypes.Value) (tftypes.Value, error) {
+	if s.schema.Block == nil || config.IsNull() {
+		return config, nil
+	}
+	attrs := map[string]tftypes.Value{}
+	if err := config.As(&attrs); err != nil {
+		return tftypes.Value{}, err
+	}
+	for _, attr := range s.schema.Block.Attributes {
+		if v, ok := attrs[attr.Name]; ok && attr.Computed && v.IsNull() {
+			attrs[attr.Name] = tftypes.NewValue(attr.ValueType(), tftypes.UnknownValue)
+		}
+	}
+	return tftypes.NewValue(s.typ, attrs), nil
+}
+
+// mergeConfig returns `state` with any top-level attributes it leaves null
+// populated from `config`.
+func mergeConfig(typ tftypes.Type, state, config tftypes.Value) (tftypes.Value, error) {
+	if config.IsNull() {
+		return state, nil
+	}
+	if state.IsNull() {
+		return config, nil
+	}
+	stateAttrs := map[string]tftypes.Value{}
+	if err := state.As(&stateAttrs); err != nil {
+		return tftypes.Value{}, err
+	}
+	configAttrs := map[string]tftypes.Value{}
+	if err := config.As(&configAttrs); err != nil {
+		return tftypes.Value{}, err
+	}
+	for k, v := range configAttrs {
+		if existing, ok := stateAttrs[k]; !ok || existing.IsNull() {
+			stateAttrs[k] = v
+		}
+	}
+	return tftypes.NewValue(typ, stateAttrs), nil
+}
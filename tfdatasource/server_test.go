@@ -0,0 +1,89 @@
+package tfdatasource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type lookupConfig struct {
+	Name string `tfsdk:"name"`
+}
+
+type lookupResult struct {
+	Address *string `tfsdk:"address"`
+}
+
+var lookupSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "name", Type: tftypes.String, Required: true},
+			{Name: "address", Type: tftypes.String, Computed: true},
+		},
+	},
+}
+
+var lookupType = lookupSchema.ValueType()
+
+func lookupValue(name, address interface{}) tftypes.Value {
+	return tftypes.NewValue(lookupType, map[string]tftypes.Value{
+		"name":    tftypes.NewValue(tftypes.String, name),
+		"address": tftypes.NewValue(tftypes.String, address),
+	})
+}
+
+func TestReadDataSource(t *testing.T) {
+	type testCase struct {
+		config   tftypes.Value
+		expected tftypes.Value
+		called   bool
+	}
+	cases := map[string]testCase{
+		"known": {
+			config:   lookupValue("example.com", nil),
+			expected: lookupValue("example.com", "192.0.2.1"),
+			called:   true,
+		},
+		"unknown": {
+			config:   lookupValue(tftypes.UnknownValue, nil),
+			expected: lookupValue(tftypes.UnknownValue, tftypes.UnknownValue),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var called bool
+			srv := NewServer[lookupConfig, lookupResult](lookupSchema, ReadFunc[lookupConfig, lookupResult](func(ctx context.Context, config lookupConfig) (lookupResult, error) {
+				called = true
+				addr := "192.0.2.1"
+				return lookupResult{Address: &addr}, nil
+			}))
+			config, err := tfprotov5.NewDynamicValue(lookupType, test.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := srv.ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{
+				Config: &config,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range resp.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+			}
+			got, err := resp.State.Unmarshal(lookupType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			if called != test.called {
+				t.Errorf("expected Read to be called to be %v, was %v", test.called, called)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package tfdiags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Template is a reusable diagnostic identified by a code, like "WIDGET001",
+// whose summary and detail are text/template templates filled in with
+// parameters when the diagnostic is created. Defining each diagnostic once
+// keeps their wording consistent across a provider, and the codes give users
+// something to search the provider's documentation for.
+//
+// The code is included at the start of the summary, like
+// "[WIDGET001] Invalid port", and can be read back using Code.
+type Template struct {
+	code     string
+	severity tfprotov5.DiagnosticSeverity
+	summary  *template.Template
+	detail   *template.Template
+
+	rawSummary, rawDetail string
+}
+
+// Define returns a Template with the given code, severity, summary, and
+// detail. It panics if the summary or detail aren't valid templates, so it's
+// intended for initialising package-level variables:
+//
+//	var errInvalidPort = tfdiags.Define("WIDGET001", tfprotov5.DiagnosticSeverityError,
+//		"Invalid port", "Port {{.Port}} is outside the allowed range {{.Min}}-{{.Max}}.")
+func Define(code string, severity tfprotov5.DiagnosticSeverity, summary, detail string) *Template {
+	return &Template{
+		code:       code,
+		severity:   severity,
+		summary:    template.Must(template.New(code).Option("missingkey=error").Parse(summary)),
+		detail:     template.Must(template.New(code).Option("missingkey=error").Parse(detail)),
+		rawSummary: summary,
+		rawDetail:  detail,
+	}
+}
+
+// Code returns the Template's code.
+func (t *Template) Code() string {
+	return t.code
+}
+
+// Severity returns the severity of the Template's diagnostics.
+func (t *Template) Severity() tfprotov5.DiagnosticSeverity {
+	return t.severity
+}
+
+// Summary returns the Template's summary, before parameters are substituted.
+func (t *Template) Summary() string {
+	return t.rawSummary
+}
+
+// Detail returns the Template's detail, before parameters are substituted.
+func (t *Template) Detail() string {
+	return t.rawDetail
+}
+
+// New returns a diagnostic attached to `path`, which may be nil, with
+// `params` substituted into the summary and detail. If a parameter the
+// templates refer to is missing, the template is included unsubstituted,
+// along with the error, rather than losing the diagnostic.
+func (t *Template) New(path *tftypes.AttributePath, params interface{}) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity:  t.severity,
+		Summary:   fmt.Sprintf("[%s] %s", t.code, execute(t.summary, t.rawSummary, params)),
+		Detail:    execute(t.detail, t.rawDetail, params),
+		Attribute: path,
+	}
+}
+
+// Error returns a *DiagnosticError holding the diagnostic returned by New.
+func (t *Template) Error(path *tftypes.AttributePath, params interface{}) error {
+	return &DiagnosticError{Diagnostic: t.New(path, params)}
+}
+
+func execute(tmpl *template.Template, raw string, params interface{}) string {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, params); err != nil {
+		return fmt.Sprintf("%s (%s)", raw, err)
+	}
+	return b.String()
+}
+
+// Code returns the code of a diagnostic created from a Template, or "" if it
+// wasn't created from one.
+func Code(d *tfprotov5.Diagnostic) string {
+	if d == nil || !strings.HasPrefix(d.Summary, "[") {
+		return ""
+	}
+	end := strings.Index(d.Summary, "] ")
+	if end < 0 {
+		return ""
+	}
+	return d.Summary[1:end]
+}
+
+// Catalog is a set of Templates with unique codes, for providers that want
+// to list every diagnostic they can produce, like in their documentation.
+// The zero value is an empty Catalog ready to use.
+type Catalog struct {
+	mu        sync.Mutex
+	templates map[string]*Template
+}
+
+// Define defines a Template as with the Define function, and adds it to the
+// Catalog. It panics if the Catalog already has a Template with that code.
+func (c *Catalog) Define(code string, severity tfprotov5.DiagnosticSeverity, summary, detail string) *Template {
+	t := Define(code, severity, summary, detail)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.templates[code]; ok {
+		panic(fmt.Sprintf("tfdiags: diagnostic code %q is already defined", code))
+	}
+	if c.templates == nil {
+		c.templates = map[string]*Template{}
+	}
+	c.templates[code] = t
+	return t
+}
+
+// Lookup returns the Template with `code`, or nil if there isn't one.
+func (c *Catalog) Lookup(code string) *Template {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.templates[code]
+}
+
+// Templates returns the Catalog's Templates, sorted by code.
+func (c *Catalog) Templates() []*Template {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	templates := make([]*Template, 0, len(c.templates))
+	for _, t := range c.templates {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].code < templates[j].code
+	})
+	return templates
+}
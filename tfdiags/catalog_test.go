@@ -0,0 +1,113 @@
+package tfdiags
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestTemplateNew(t *testing.T) {
+	type testCase struct {
+		template *Template
+		params   interface{}
+		expected *tfprotov5.Diagnostic
+	}
+	port := tftypes.NewAttributePath().WithAttributeName("port")
+	invalidPort := Define("WIDGET001", tfprotov5.DiagnosticSeverityError, "Invalid port", "Port {{.Port}} is outside the allowed range {{.Min}}-{{.Max}}.")
+	cases := map[string]testCase{
+		"map": {
+			template: invalidPort,
+			params:   map[string]int{"Port": 70000, "Min": 1, "Max": 65535},
+			expected: &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "[WIDGET001] Invalid port",
+				Detail:    "Port 70000 is outside the allowed range 1-65535.",
+				Attribute: port,
+			},
+		},
+		"struct": {
+			template: invalidPort,
+			params:   struct{ Port, Min, Max int }{0, 1, 65535},
+			expected: &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "[WIDGET001] Invalid port",
+				Detail:    "Port 0 is outside the allowed range 1-65535.",
+				Attribute: port,
+			},
+		},
+		"missing-param": {
+			template: invalidPort,
+			params:   map[string]int{"Port": 0},
+			expected: &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "[WIDGET001] Invalid port",
+				Detail:    `Port {{.Port}} is outside the allowed range {{.Min}}-{{.Max}}. (template: WIDGET001:1:46: executing "WIDGET001" at <.Min>: map has no entry for key "Min")`,
+				Attribute: port,
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got := tc.template.New(port, tc.params)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			if code := Code(got); code != "WIDGET001" {
+				t.Errorf("expected code WIDGET001, got %q", code)
+			}
+		})
+	}
+}
+
+func TestTemplateError(t *testing.T) {
+	tmpl := Define("WIDGET002", tfprotov5.DiagnosticSeverityWarning, "Slow {{.}}", "")
+	err := tmpl.Error(nil, "widget")
+	if err.Error() != "[WIDGET002] Slow widget" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	var diagErr *DiagnosticError
+	if !errors.As(err, &diagErr) || diagErr.Diagnostic.Severity != tfprotov5.DiagnosticSeverityWarning {
+		t.Errorf("expected a warning DiagnosticError, got %#v", err)
+	}
+}
+
+func TestCode(t *testing.T) {
+	cases := map[string]string{
+		"[WIDGET001] Invalid port": "WIDGET001",
+		"Invalid port":             "",
+		"[not a code":              "",
+	}
+	for summary, expected := range cases {
+		if got := Code(&tfprotov5.Diagnostic{Summary: summary}); got != expected {
+			t.Errorf("expected code %q for %q, got %q", expected, summary, got)
+		}
+	}
+	if got := Code(nil); got != "" {
+		t.Errorf("expected no code for nil diagnostic, got %q", got)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	var c Catalog
+	b := c.Define("B001", tfprotov5.DiagnosticSeverityWarning, "Second", "")
+	a := c.Define("A001", tfprotov5.DiagnosticSeverityError, "First", "Detail {{.}}")
+	if diff := cmp.Diff([]string{"A001", "B001"}, []string{c.Templates()[0].Code(), c.Templates()[1].Code()}); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if c.Lookup("A001") != a || c.Lookup("B001") != b || c.Lookup("C001") != nil {
+		t.Error("unexpected lookup results")
+	}
+	if a.Summary() != "First" || a.Detail() != "Detail {{.}}" || a.Severity() != tfprotov5.DiagnosticSeverityError {
+		t.Errorf("unexpected template fields: %q, %q, %s", a.Summary(), a.Detail(), a.Severity())
+	}
+	defer func() {
+		if r := recover(); r != `tfdiags: diagnostic code "A001" is already defined` {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	c.Define("A001", tfprotov5.DiagnosticSeverityError, "Again", "")
+}
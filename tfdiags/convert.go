@@ -0,0 +1,134 @@
+// Package tfdiags provides helpers for building, combining, and adjusting
+// tfprotov5.Diagnostics.
+//
+// Provider code deep below the RPC handlers usually reports problems as Go
+// errors, while Terraform expects diagnostics with a summary, a detail, and
+// ideally the path of the attribute responsible. The helpers in this package
+// convert between the two and take care of the details, like splitting
+// joined errors into one diagnostic each.
+package tfdiags
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// DefaultSummary is the summary of diagnostics converted from errors that no
+// Rule matches, when Converter.Summary is empty.
+const DefaultSummary = "Error"
+
+// Rule maps errors of a known kind to a diagnostic summary and detail. It
+// returns false if `err` isn't of the kind it handles.
+type Rule func(err error) (summary, detail string, ok bool)
+
+// Is returns a Rule that uses `summary` for errors that are, or wrap,
+// `target`, as reported by errors.Is. The error's message is used as the
+// detail.
+func Is(target error, summary string) Rule {
+	return func(err error) (string, string, bool) {
+		if !errors.Is(err, target) {
+			return "", "", false
+		}
+		return summary, err.Error(), true
+	}
+}
+
+// As returns a Rule that uses `summary` for errors that are, or wrap, an
+// error of type E, as reported by errors.As. The error's message is used as
+// the detail.
+func As[E error](summary string) Rule {
+	return func(err error) (string, string, bool) {
+		var target E
+		if !errors.As(err, &target) {
+			return "", "", false
+		}
+		return summary, err.Error(), true
+	}
+}
+
+// DefaultRules are the Rules used by FromError, and by Converters with no
+// Rules of their own.
+var DefaultRules = []Rule{
+	Is(context.DeadlineExceeded, "Operation timed out"),
+	Is(context.Canceled, "Operation cancelled"),
+}
+
+// Converter converts Go errors into error diagnostics.
+type Converter struct {
+	// Summary is the summary of diagnostics for errors that no Rule
+	// matches. It defaults to DefaultSummary.
+	Summary string
+
+	// Rules are tried in order, and the first to match an error decides
+	// the summary and detail of its diagnostic. If nil, DefaultRules are
+	// used.
+	Rules []Rule
+}
+
+// FromError converts `err` into diagnostics using a Converter with the
+// default settings.
+func FromError(err error) []*tfprotov5.Diagnostic {
+	var c Converter
+	return c.Convert(err)
+}
+
+// Convert converts `err` into diagnostics. A nil error produces no
+// diagnostics.
+//
+// Errors implementing Unwrap() []error, like those returned by errors.Join,
+// are split into one diagnostic for each of the errors they wrap. Errors
+// that are, or wrap, a *DiagnosticError or *DiagnosticsError are converted
+// back into the diagnostics they hold, without any context added by wrapping them. Any
+// other error becomes a single diagnostic, and if it is or wraps a
+// tftypes.AttributePathError, like the errors returned by asgotypes, the
+// diagnostic is attached to the error's path.
+func (c *Converter) Convert(err error) []*tfprotov5.Diagnostic {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var diags []*tfprotov5.Diagnostic
+		for _, e := range joined.Unwrap() {
+			diags = append(diags, c.Convert(e)...)
+		}
+		return diags
+	}
+	var diagsErr *DiagnosticsError
+	if errors.As(err, &diagsErr) {
+		var diags []*tfprotov5.Diagnostic
+		for _, d := range diagsErr.Diagnostics {
+			if d != nil {
+				diags = append(diags, d)
+			}
+		}
+		return diags
+	}
+	return []*tfprotov5.Diagnostic{c.convert(err)}
+}
+
+func (c *Converter) convert(err error) *tfprotov5.Diagnostic {
+	var diagErr *DiagnosticError
+	if errors.As(err, &diagErr) && diagErr.Diagnostic != nil {
+		return diagErr.Diagnostic
+	}
+	rules := c.Rules
+	if rules == nil {
+		rules = DefaultRules
+	}
+	summary, detail := c.Summary, err.Error()
+	if summary == "" {
+		summary = DefaultSummary
+	}
+	for _, rule := range rules {
+		if s, d, ok := rule(err); ok {
+			summary, detail = s, d
+			break
+		}
+	}
+	d := diag.Error(summary, err)
+	d.Detail = detail
+	return d
+}
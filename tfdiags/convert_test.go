@@ -0,0 +1,113 @@
+package tfdiags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestConvert(t *testing.T) {
+	type testCase struct {
+		converter Converter
+		err       error
+		expected  []*tfprotov5.Diagnostic
+	}
+	path := tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(1)
+	cases := map[string]testCase{
+		"nil": {},
+		"plain": {
+			err: errors.New("boom"),
+			expected: []*tfprotov5.Diagnostic{
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Error", Detail: "boom"},
+			},
+		},
+		"summary": {
+			converter: Converter{Summary: "Error creating widget"},
+			err:       errors.New("boom"),
+			expected: []*tfprotov5.Diagnostic{
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Error creating widget", Detail: "boom"},
+			},
+		},
+		"path": {
+			err: fmt.Errorf("decoding: %w", path.NewErrorf("not a number")),
+			expected: []*tfprotov5.Diagnostic{
+				{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Error",
+					Detail:    `decoding: AttributeName("rule").ElementKeyInt(1): not a number`,
+					Attribute: path,
+				},
+			},
+		},
+		"joined": {
+			err: errors.Join(errors.New("first"), nil, errors.Join(errors.New("second"), path.NewErrorf("third"))),
+			expected: []*tfprotov5.Diagnostic{
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Error", Detail: "first"},
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Error", Detail: "second"},
+				{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Error",
+					Detail:    `AttributeName("rule").ElementKeyInt(1): third`,
+					Attribute: path,
+				},
+			},
+		},
+		"wrapped-joined": {
+			err: fmt.Errorf("creating: %w", errors.Join(errors.New("first"), errors.New("second"))),
+			expected: []*tfprotov5.Diagnostic{
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Error", Detail: "creating: first\nsecond"},
+			},
+		},
+		"default-rules": {
+			err: fmt.Errorf("waiting for widget: %w", context.DeadlineExceeded),
+			expected: []*tfprotov5.Diagnostic{
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Operation timed out", Detail: "waiting for widget: context deadline exceeded"},
+			},
+		},
+		"custom-rules": {
+			converter: Converter{
+				Rules: []Rule{
+					As[*fs.PathError]("Error reading file"),
+					func(err error) (string, string, bool) {
+						return "Custom", "custom detail", errors.Is(err, fs.ErrPermission)
+					},
+				},
+			},
+			err: errors.Join(
+				&fs.PathError{Op: "open", Path: "key.pem", Err: fs.ErrNotExist},
+				fmt.Errorf("writing: %w", fs.ErrPermission),
+				context.Canceled,
+			),
+			expected: []*tfprotov5.Diagnostic{
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Error reading file", Detail: "open key.pem: file does not exist"},
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Custom", Detail: "custom detail"},
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Error", Detail: "context canceled"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got := tc.converter.Convert(tc.err)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	got := FromError(context.Canceled)
+	expected := []*tfprotov5.Diagnostic{
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Operation cancelled", Detail: "context canceled"},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
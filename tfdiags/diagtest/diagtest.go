@@ -0,0 +1,52 @@
+// Package diagtest provides helpers for asserting on the diagnostics
+// returned by a provider in tests.
+package diagtest
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfdiags"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// HasCode fails the test unless `diags` contains a diagnostic created from
+// the tfdiags.Template with `code`, and returns the first such diagnostic.
+func HasCode(t testing.TB, diags []*tfprotov5.Diagnostic, code string) *tfprotov5.Diagnostic {
+	t.Helper()
+	for _, d := range diags {
+		if tfdiags.Code(d) == code {
+			return d
+		}
+	}
+	t.Errorf("expected a diagnostic with code %s, got %s", code, describe(diags))
+	return nil
+}
+
+// NoCode fails the test if `diags` contains a diagnostic created from the
+// tfdiags.Template with `code`.
+func NoCode(t testing.TB, diags []*tfprotov5.Diagnostic, code string) {
+	t.Helper()
+	for _, d := range diags {
+		if tfdiags.Code(d) == code {
+			t.Errorf("expected no diagnostic with code %s, got %q: %q", code, d.Summary, d.Detail)
+		}
+	}
+}
+
+func describe(diags []*tfprotov5.Diagnostic) string {
+	if len(diags) == 0 {
+		return "no diagnostics"
+	}
+	var s string
+	for i, d := range diags {
+		if i > 0 {
+			s += ", "
+		}
+		if d == nil {
+			s += "nil"
+			continue
+		}
+		s += "\"" + d.Summary + "\""
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package diagtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfdiags"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+var errInvalidPort = tfdiags.Define("TEST001", tfprotov5.DiagnosticSeverityError, "Invalid port", "Port {{.}} is invalid.")
+
+func TestHasCode(t *testing.T) {
+	diags := []*tfprotov5.Diagnostic{
+		{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Deprecated"},
+		errInvalidPort.New(nil, 0),
+	}
+
+	r := &recorder{}
+	if d := HasCode(r, diags, "TEST001"); d != diags[1] {
+		t.Errorf("expected the matching diagnostic, got %+v", d)
+	}
+	NoCode(r, diags, "TEST002")
+	if len(r.errors) != 0 {
+		t.Errorf("unexpected failures: %q", r.errors)
+	}
+
+	r = &recorder{}
+	HasCode(r, diags, "TEST002")
+	NoCode(r, diags, "TEST001")
+	expected := []string{
+		`expected a diagnostic with code TEST002, got "Deprecated", "[TEST001] Invalid port"`,
+		`expected no diagnostic with code TEST001, got "[TEST001] Invalid port": "Port 0 is invalid."`,
+	}
+	if fmt.Sprint(r.errors) != fmt.Sprint(expected) {
+		t.Errorf("expected failures %q, got %q", expected, r.errors)
+	}
+}
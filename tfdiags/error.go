@@ -0,0 +1,70 @@
+package tfdiags
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// DiagnosticError is an error holding a single diagnostic. Converter.Convert
+// converts it back into the diagnostic it holds, so a diagnostic can be
+// passed up through code that deals in errors without losing its severity,
+// summary, or path.
+type DiagnosticError struct {
+	Diagnostic *tfprotov5.Diagnostic
+}
+
+// Error returns the diagnostic's summary and detail.
+func (e *DiagnosticError) Error() string {
+	if e.Diagnostic.Detail == "" {
+		return e.Diagnostic.Summary
+	}
+	return e.Diagnostic.Summary + ": " + e.Diagnostic.Detail
+}
+
+// DiagnosticsError is an error holding a set of diagnostics. It wraps a
+// *DiagnosticError for each of them.
+type DiagnosticsError struct {
+	Diagnostics []*tfprotov5.Diagnostic
+}
+
+// Error returns the summary and detail of each diagnostic, one per line.
+func (e *DiagnosticsError) Error() string {
+	msgs := make([]string, 0, len(e.Diagnostics))
+	for _, err := range e.Unwrap() {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns a *DiagnosticError for each diagnostic.
+func (e *DiagnosticsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Diagnostics))
+	for _, d := range e.Diagnostics {
+		if d != nil {
+			errs = append(errs, &DiagnosticError{Diagnostic: d})
+		}
+	}
+	return errs
+}
+
+// ToError returns `diags` as a *DiagnosticsError, or nil if there are no
+// diagnostics. Warnings are included, so that converting the error back
+// using FromError returns the same diagnostics; use HasError to check for
+// errors first if warnings shouldn't be treated as a failure.
+func ToError(diags []*tfprotov5.Diagnostic) error {
+	if len(diags) == 0 {
+		return nil
+	}
+	return &DiagnosticsError{Diagnostics: diags}
+}
+
+// HasError returns true if any of `diags` has error severity.
+func HasError(diags []*tfprotov5.Diagnostic) bool {
+	for _, d := range diags {
+		if d != nil && d.Severity == tfprotov5.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package tfdiags
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestErrorRoundTrip(t *testing.T) {
+	type testCase struct {
+		diags       []*tfprotov5.Diagnostic
+		wrap        func(error) error
+		expectedMsg string
+		expected    []*tfprotov5.Diagnostic
+	}
+	diags := []*tfprotov5.Diagnostic{
+		warning("Deprecated", "use title", tftypes.NewAttributePath().WithAttributeName("name")),
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Invalid port"},
+	}
+	cases := map[string]testCase{
+		"empty": {},
+		"diagnostics": {
+			diags:       diags,
+			expectedMsg: "Deprecated: use title\nInvalid port",
+			expected:    diags,
+		},
+		"wrapped": {
+			diags: diags,
+			wrap: func(err error) error {
+				return fmt.Errorf("creating widget: %w", err)
+			},
+			expectedMsg: "creating widget: Deprecated: use title\nInvalid port",
+			expected:    diags,
+		},
+		"joined": {
+			diags: diags[1:],
+			wrap: func(err error) error {
+				return errors.Join(errors.New("boom"), err)
+			},
+			expectedMsg: "boom\nInvalid port",
+			expected: []*tfprotov5.Diagnostic{
+				{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Error", Detail: "boom"},
+				diags[1],
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			err := ToError(tc.diags)
+			if err == nil {
+				if tc.diags != nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if tc.wrap != nil {
+				err = tc.wrap(err)
+			}
+			if err.Error() != tc.expectedMsg {
+				t.Errorf("expected message %q, got %q", tc.expectedMsg, err.Error())
+			}
+			if diff := cmp.Diff(tc.expected, FromError(err)); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestHasError(t *testing.T) {
+	if HasError([]*tfprotov5.Diagnostic{warning("Deprecated", "", nil), nil}) {
+		t.Error("expected warnings not to be errors")
+	}
+	if !HasError([]*tfprotov5.Diagnostic{warning("Deprecated", "", nil), {Severity: tfprotov5.DiagnosticSeverityError}}) {
+		t.Error("expected an error")
+	}
+}
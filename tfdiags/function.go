@@ -0,0 +1,140 @@
+package tfdiags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ArgumentError is an error caused by the value of one of the arguments of
+// a provider-defined function. FunctionErrorFromError uses it to attach the
+// resulting FunctionError to the argument.
+type ArgumentError struct {
+	// Index is the position of the argument, starting from zero. Variadic
+	// arguments all share the index after the last fixed argument.
+	Index int64
+	Err   error
+}
+
+func (e *ArgumentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// FunctionError returns a FunctionError with the text `format`, formatted as
+// with fmt.Sprintf.
+func FunctionError(format string, args ...interface{}) *tfprotov5.FunctionError {
+	return &tfprotov5.FunctionError{Text: fmt.Sprintf(format, args...)}
+}
+
+// FunctionArgumentError returns a FunctionError for the argument at
+// `index`, with the text `format`, formatted as with fmt.Sprintf.
+func FunctionArgumentError(index int64, format string, args ...interface{}) *tfprotov5.FunctionError {
+	return &tfprotov5.FunctionError{
+		Text:             fmt.Sprintf(format, args...),
+		FunctionArgument: &index,
+	}
+}
+
+// FunctionErrorFromError converts `err` into a FunctionError, or returns nil
+// if `err` is nil. Errors implementing Unwrap() []error, like those returned
+// by errors.Join, have their messages combined, one per line, as a function
+// can only return one error. If every error is an ArgumentError for the same
+// argument, the FunctionError is attached to that argument.
+func FunctionErrorFromError(err error) *tfprotov5.FunctionError {
+	if err == nil {
+		return nil
+	}
+	var (
+		texts []string
+		args  []*int64
+	)
+	var collect func(error)
+	collect = func(err error) {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				collect(e)
+			}
+			return
+		}
+		texts = append(texts, err.Error())
+		var argErr *ArgumentError
+		if errors.As(err, &argErr) {
+			index := argErr.Index
+			args = append(args, &index)
+		} else {
+			args = append(args, nil)
+		}
+	}
+	collect(err)
+	return &tfprotov5.FunctionError{
+		Text:             strings.Join(texts, "\n"),
+		FunctionArgument: sameArgument(args),
+	}
+}
+
+// FunctionErrorFromDiagnostics converts the error diagnostics in `diags`
+// into a FunctionError attached to the argument at `index`, or returns nil
+// if there are none. A negative `index` leaves the FunctionError
+// unattached. Each diagnostic's summary and detail are combined, one
+// diagnostic per line, and warnings are dropped, as functions can't return
+// them.
+func FunctionErrorFromDiagnostics(diags []*tfprotov5.Diagnostic, index int64) *tfprotov5.FunctionError {
+	var texts []string
+	for _, d := range diags {
+		if d == nil || d.Severity != tfprotov5.DiagnosticSeverityError {
+			continue
+		}
+		texts = append(texts, (&DiagnosticError{Diagnostic: d}).Error())
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+	fe := &tfprotov5.FunctionError{Text: strings.Join(texts, "\n")}
+	if index >= 0 {
+		fe.FunctionArgument = &index
+	}
+	return fe
+}
+
+// ValidateArgument runs `validators` against `val`, the value of the
+// function argument at `index`, and returns a FunctionError for that
+// argument if any of them report errors.
+func ValidateArgument(ctx context.Context, index int64, val tftypes.Value, validators ...tfvalidate.Validator) *tfprotov5.FunctionError {
+	diags := tfvalidate.All(validators...).ValidateValue(ctx, tftypes.NewAttributePath(), val)
+	return FunctionErrorFromDiagnostics(diags, index)
+}
+
+// FunctionErrorV6 converts `fe` for use with tfprotov6.
+func FunctionErrorV6(fe *tfprotov5.FunctionError) *tfprotov6.FunctionError {
+	if fe == nil {
+		return nil
+	}
+	return &tfprotov6.FunctionError{
+		Text:             fe.Text,
+		FunctionArgument: fe.FunctionArgument,
+	}
+}
+
+// sameArgument returns the argument all of `args` refer to, or nil if they
+// don't all refer to the same one.
+func sameArgument(args []*int64) *int64 {
+	if len(args) == 0 || args[0] == nil {
+		return nil
+	}
+	for _, a := range args[1:] {
+		if a == nil || *a != *args[0] {
+			return nil
+		}
+	}
+	return args[0]
+}
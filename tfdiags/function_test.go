@@ -0,0 +1,116 @@
+package tfdiags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func argIndex(i int64) *int64 {
+	return &i
+}
+
+func TestFunctionErrorFromError(t *testing.T) {
+	type testCase struct {
+		err      error
+		expected *tfprotov5.FunctionError
+	}
+	cases := map[string]testCase{
+		"nil": {},
+		"plain": {
+			err:      errors.New("boom"),
+			expected: &tfprotov5.FunctionError{Text: "boom"},
+		},
+		"argument": {
+			err:      fmt.Errorf("parsing: %w", &ArgumentError{Index: 1, Err: errors.New("not a CIDR")}),
+			expected: &tfprotov5.FunctionError{Text: "parsing: not a CIDR", FunctionArgument: argIndex(1)},
+		},
+		"joined-same-argument": {
+			err: errors.Join(
+				&ArgumentError{Index: 0, Err: errors.New("too short")},
+				&ArgumentError{Index: 0, Err: errors.New("not lowercase")},
+			),
+			expected: &tfprotov5.FunctionError{Text: "too short\nnot lowercase", FunctionArgument: argIndex(0)},
+		},
+		"joined-different-arguments": {
+			err: errors.Join(
+				&ArgumentError{Index: 0, Err: errors.New("too short")},
+				&ArgumentError{Index: 1, Err: errors.New("too long")},
+			),
+			expected: &tfprotov5.FunctionError{Text: "too short\ntoo long"},
+		},
+		"joined-mixed": {
+			err: errors.Join(
+				&ArgumentError{Index: 0, Err: errors.New("too short")},
+				errors.New("boom"),
+			),
+			expected: &tfprotov5.FunctionError{Text: "too short\nboom"},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got := FunctionErrorFromError(tc.err)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFunctionErrorFromDiagnostics(t *testing.T) {
+	diags := []*tfprotov5.Diagnostic{
+		warning("Deprecated", "", nil),
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Invalid CIDR", Detail: "bad prefix"},
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Invalid address"},
+	}
+	got := FunctionErrorFromDiagnostics(diags, 2)
+	expected := &tfprotov5.FunctionError{Text: "Invalid CIDR: bad prefix\nInvalid address", FunctionArgument: argIndex(2)}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if got := FunctionErrorFromDiagnostics(diags[:1], 0); got != nil {
+		t.Errorf("expected no error for warnings, got %+v", got)
+	}
+	if got := FunctionErrorFromDiagnostics(diags[1:2], -1); got.FunctionArgument != nil {
+		t.Errorf("expected an unattached error, got %+v", got)
+	}
+}
+
+func TestValidateArgument(t *testing.T) {
+	ctx := context.Background()
+	got := ValidateArgument(ctx, 1, tftypes.NewValue(tftypes.String, "ab"), tfvalidate.StringLength(3, 10))
+	expected := &tfprotov5.FunctionError{
+		Text:             "Invalid attribute value: Must be at least 3 characters long, got 2.",
+		FunctionArgument: argIndex(1),
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if got := ValidateArgument(ctx, 1, tftypes.NewValue(tftypes.String, "abcd"), tfvalidate.StringLength(3, 10)); got != nil {
+		t.Errorf("expected no error, got %+v", got)
+	}
+}
+
+func TestFunctionErrorBuilders(t *testing.T) {
+	if diff := cmp.Diff(&tfprotov5.FunctionError{Text: "bad input 3"}, FunctionError("bad input %d", 3)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	fe := FunctionArgumentError(0, "must not be %s", "empty")
+	if diff := cmp.Diff(&tfprotov5.FunctionError{Text: "must not be empty", FunctionArgument: argIndex(0)}, fe); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if diff := cmp.Diff(&tfprotov6.FunctionError{Text: "must not be empty", FunctionArgument: argIndex(0)}, FunctionErrorV6(fe)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if FunctionErrorV6(nil) != nil {
+		t.Error("expected nil to convert to nil")
+	}
+}
@@ -0,0 +1,127 @@
+package tfdiags
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Fields added to the log entries written by Logger.
+const (
+	LogKeySeverity  = "diagnostic_severity"
+	LogKeySummary   = "diagnostic_summary"
+	LogKeyDetail    = "diagnostic_detail"
+	LogKeyAttribute = "diagnostic_attribute"
+)
+
+// Redacted replaces the detail of diagnostics attached to sensitive
+// attributes in log entries written by Logger.
+const Redacted = "[REDACTED]"
+
+// Logger writes diagnostics to the provider's logs using tflog, so that
+// operators can find the context around an error shown by Terraform in the
+// provider's debug logs. Error diagnostics are logged at the error level,
+// warnings at the warn level, and any others at the info level.
+type Logger struct {
+	// Subsystem, if set, is the tflog subsystem diagnostics are logged
+	// to. It's created using tflog.NewSubsystem with default options if
+	// the context doesn't already hold it.
+	Subsystem string
+
+	// Sensitive are the paths of attributes whose values may appear in
+	// the detail of diagnostics attached to them, which is replaced with
+	// Redacted when logging. The diagnostics of attributes nested within
+	// them are redacted too. As with the other registries in this module,
+	// element keys are ignored when matching paths. SensitivePaths
+	// returns the paths of a schema's sensitive attributes.
+	Sensitive []*tftypes.AttributePath
+}
+
+// Log writes each of `diags` to the log.
+func (l *Logger) Log(ctx context.Context, diags []*tfprotov5.Diagnostic) {
+	if l.Subsystem != "" {
+		ctx = tflog.NewSubsystem(ctx, l.Subsystem)
+	}
+	for _, d := range diags {
+		if d == nil {
+			continue
+		}
+		fields := map[string]interface{}{
+			LogKeySeverity: d.Severity.String(),
+			LogKeySummary:  d.Summary,
+			LogKeyDetail:   d.Detail,
+		}
+		if d.Attribute != nil && len(d.Attribute.Steps()) > 0 {
+			fields[LogKeyAttribute] = d.Attribute.String()
+			if l.sensitive(d.Attribute) {
+				fields[LogKeyDetail] = Redacted
+			}
+		}
+		l.write(ctx, d.Severity, fields)
+	}
+}
+
+func (l *Logger) write(ctx context.Context, severity tfprotov5.DiagnosticSeverity, fields map[string]interface{}) {
+	switch severity {
+	case tfprotov5.DiagnosticSeverityError:
+		if l.Subsystem != "" {
+			tflog.SubsystemError(ctx, l.Subsystem, "Response contains error diagnostic", fields)
+		} else {
+			tflog.Error(ctx, "Response contains error diagnostic", fields)
+		}
+	case tfprotov5.DiagnosticSeverityWarning:
+		if l.Subsystem != "" {
+			tflog.SubsystemWarn(ctx, l.Subsystem, "Response contains warning diagnostic", fields)
+		} else {
+			tflog.Warn(ctx, "Response contains warning diagnostic", fields)
+		}
+	default:
+		if l.Subsystem != "" {
+			tflog.SubsystemInfo(ctx, l.Subsystem, "Response contains diagnostic", fields)
+		} else {
+			tflog.Info(ctx, "Response contains diagnostic", fields)
+		}
+	}
+}
+
+// sensitive returns true if `path` is, or is nested within, one of the
+// Sensitive paths.
+func (l *Logger) sensitive(path *tftypes.AttributePath) bool {
+	names := attrpath.Names(path)
+	for _, s := range l.Sensitive {
+		if attrpath.HasPrefix(names, attrpath.Names(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SensitivePaths returns the paths of the attributes marked as sensitive in
+// `schemas`, including those in nested blocks.
+func SensitivePaths(schemas ...*tfprotov5.Schema) []*tftypes.AttributePath {
+	var paths []*tftypes.AttributePath
+	for _, schema := range schemas {
+		if schema != nil {
+			paths = sensitiveBlockPaths(paths, tftypes.NewAttributePath(), schema.Block)
+		}
+	}
+	return paths
+}
+
+func sensitiveBlockPaths(paths []*tftypes.AttributePath, path *tftypes.AttributePath, block *tfprotov5.SchemaBlock) []*tftypes.AttributePath {
+	if block == nil {
+		return paths
+	}
+	for _, attr := range block.Attributes {
+		if attr.Sensitive {
+			paths = append(paths, path.WithAttributeName(attr.Name))
+		}
+	}
+	for _, nested := range block.BlockTypes {
+		paths = sensitiveBlockPaths(paths, path.WithAttributeName(nested.TypeName), nested.Block)
+	}
+	return paths
+}
@@ -0,0 +1,120 @@
+package tfdiags
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+var testSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "name", Type: tftypes.String, Required: true},
+			{Name: "password", Type: tftypes.String, Optional: true, Sensitive: true},
+		},
+		BlockTypes: []*tfprotov5.SchemaNestedBlock{
+			{
+				TypeName: "credentials",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "token", Type: tftypes.String, Optional: true, Sensitive: true},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestSensitivePaths(t *testing.T) {
+	got := SensitivePaths(testSchema, nil)
+	expected := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("password"),
+		tftypes.NewAttributePath().WithAttributeName("credentials").WithAttributeName("token"),
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestLoggerLog(t *testing.T) {
+	type testCase struct {
+		logger   Logger
+		expected []map[string]interface{}
+	}
+	diags := []*tfprotov5.Diagnostic{
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Invalid name", Detail: `"x" is too short`, Attribute: tftypes.NewAttributePath().WithAttributeName("name")},
+		{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Weak token", Detail: `"hunter2" is weak`, Attribute: tftypes.NewAttributePath().WithAttributeName("credentials").WithElementKeyInt(0).WithAttributeName("token")},
+		nil,
+	}
+	cases := map[string]testCase{
+		"root": {
+			expected: []map[string]interface{}{
+				{
+					"@level":        "error",
+					"@message":      "Response contains error diagnostic",
+					"@module":       "provider",
+					LogKeySeverity:  "ERROR",
+					LogKeySummary:   "Invalid name",
+					LogKeyDetail:    `"x" is too short`,
+					LogKeyAttribute: `AttributeName("name")`,
+				},
+				{
+					"@level":        "warn",
+					"@message":      "Response contains warning diagnostic",
+					"@module":       "provider",
+					LogKeySeverity:  "WARNING",
+					LogKeySummary:   "Weak token",
+					LogKeyDetail:    `"hunter2" is weak`,
+					LogKeyAttribute: `AttributeName("credentials").ElementKeyInt(0).AttributeName("token")`,
+				},
+			},
+		},
+		"subsystem-redacted": {
+			logger: Logger{
+				Subsystem: "diagnostics",
+				Sensitive: SensitivePaths(testSchema),
+			},
+			expected: []map[string]interface{}{
+				{
+					"@level":        "error",
+					"@message":      "Response contains error diagnostic",
+					"@module":       "provider.diagnostics",
+					LogKeySeverity:  "ERROR",
+					LogKeySummary:   "Invalid name",
+					LogKeyDetail:    `"x" is too short`,
+					LogKeyAttribute: `AttributeName("name")`,
+				},
+				{
+					"@level":        "warn",
+					"@message":      "Response contains warning diagnostic",
+					"@module":       "provider.diagnostics",
+					LogKeySeverity:  "WARNING",
+					LogKeySummary:   "Weak token",
+					LogKeyDetail:    Redacted,
+					LogKeyAttribute: `AttributeName("credentials").ElementKeyInt(0).AttributeName("token")`,
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &buf)
+			tc.logger.Log(ctx, diags)
+			got, err := tflogtest.MultilineJSONDecode(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package tfdiags
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// key identifies diagnostics that are duplicates of each other.
+type key struct {
+	severity tfprotov5.DiagnosticSeverity
+	summary  string
+	detail   string
+	path     string
+}
+
+func keyOf(d *tfprotov5.Diagnostic) key {
+	k := key{
+		severity: d.Severity,
+		summary:  d.Summary,
+		detail:   d.Detail,
+	}
+	if d.Attribute != nil {
+		k.path = d.Attribute.String()
+	}
+	return k
+}
+
+// Merge returns the diagnostics in `sets`, in order, with duplicates removed
+// as by Dedupe.
+func Merge(sets ...[]*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+	var all []*tfprotov5.Diagnostic
+	for _, set := range sets {
+		all = append(all, set...)
+	}
+	return Dedupe(all)
+}
+
+// Dedupe returns `diags` with nil diagnostics and duplicates removed, keeping
+// the first of each. Diagnostics are duplicates if they have the same
+// severity, summary, detail, and attribute path.
+func Dedupe(diags []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+	seen := make(map[key]bool, len(diags))
+	var result []*tfprotov5.Diagnostic
+	for _, d := range diags {
+		if d == nil {
+			continue
+		}
+		k := keyOf(d)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		result = append(result, d)
+	}
+	return result
+}
+
+// Limit returns `diags` with at most `max` diagnostics of each severity and
+// summary, for when the same problem is found in every element of a large
+// collection. The diagnostics beyond `max` are replaced with a single
+// diagnostic, with the same severity and summary, saying how many more there
+// were. A `max` of zero or less doesn't limit the diagnostics.
+func Limit(diags []*tfprotov5.Diagnostic, max int) []*tfprotov5.Diagnostic {
+	if max <= 0 {
+		return diags
+	}
+	type group struct {
+		severity tfprotov5.DiagnosticSeverity
+		summary  string
+	}
+	counts := map[group]int{}
+	for _, d := range diags {
+		if d != nil {
+			counts[group{d.Severity, d.Summary}]++
+		}
+	}
+	seen := map[group]int{}
+	var result []*tfprotov5.Diagnostic
+	for _, d := range diags {
+		if d == nil {
+			continue
+		}
+		g := group{d.Severity, d.Summary}
+		seen[g]++
+		switch {
+		case seen[g] <= max:
+			result = append(result, d)
+		case seen[g] == max+1:
+			more := counts[g] - max
+			detail := fmt.Sprintf("And %d more similar problems.", more)
+			if more == 1 {
+				detail = "And 1 more similar problem."
+			}
+			result = append(result, &tfprotov5.Diagnostic{
+				Severity: d.Severity,
+				Summary:  d.Summary,
+				Detail:   detail,
+			})
+		}
+	}
+	return result
+}
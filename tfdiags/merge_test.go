@@ -0,0 +1,83 @@
+package tfdiags
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func warning(summary, detail string, path *tftypes.AttributePath) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity:  tfprotov5.DiagnosticSeverityWarning,
+		Summary:   summary,
+		Detail:    detail,
+		Attribute: path,
+	}
+}
+
+func TestMerge(t *testing.T) {
+	name := tftypes.NewAttributePath().WithAttributeName("name")
+	a := warning("Deprecated", "use title", name)
+	b := warning("Deprecated", "use title", tftypes.NewAttributePath().WithAttributeName("name"))
+	c := warning("Deprecated", "use title", nil)
+	d := &tfprotov5.Diagnostic{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Deprecated", Detail: "use title", Attribute: name}
+
+	got := Merge([]*tfprotov5.Diagnostic{a, nil, c}, nil, []*tfprotov5.Diagnostic{b, d, c})
+	expected := []*tfprotov5.Diagnostic{a, c, d}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	type testCase struct {
+		diags    []*tfprotov5.Diagnostic
+		max      int
+		expected []*tfprotov5.Diagnostic
+	}
+	elem := func(i int) *tftypes.AttributePath {
+		return tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(i)
+	}
+	cases := map[string]testCase{
+		"under": {
+			diags:    []*tfprotov5.Diagnostic{warning("Deprecated", "a", elem(0)), warning("Deprecated", "b", elem(1))},
+			max:      2,
+			expected: []*tfprotov5.Diagnostic{warning("Deprecated", "a", elem(0)), warning("Deprecated", "b", elem(1))},
+		},
+		"over": {
+			diags: []*tfprotov5.Diagnostic{
+				warning("Deprecated", "a", elem(0)),
+				warning("Other", "x", nil),
+				warning("Deprecated", "b", elem(1)),
+				warning("Deprecated", "c", elem(2)),
+				warning("Deprecated", "d", elem(3)),
+			},
+			max: 1,
+			expected: []*tfprotov5.Diagnostic{
+				warning("Deprecated", "a", elem(0)),
+				warning("Other", "x", nil),
+				warning("Deprecated", "And 3 more similar problems.", nil),
+			},
+		},
+		"one-more": {
+			diags:    []*tfprotov5.Diagnostic{warning("Deprecated", "a", elem(0)), warning("Deprecated", "b", elem(1))},
+			max:      1,
+			expected: []*tfprotov5.Diagnostic{warning("Deprecated", "a", elem(0)), warning("Deprecated", "And 1 more similar problem.", nil)},
+		},
+		"unlimited": {
+			diags:    []*tfprotov5.Diagnostic{warning("Deprecated", "a", elem(0)), warning("Deprecated", "b", elem(1))},
+			expected: []*tfprotov5.Diagnostic{warning("Deprecated", "a", elem(0)), warning("Deprecated", "b", elem(1))},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got := Limit(tc.diags, tc.max)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package tfdiags
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// WithPath returns `diags` with the diagnostics that aren't attached to an
+// attribute attached to `path`. The diagnostics are copied rather than
+// modified.
+func WithPath(diags []*tfprotov5.Diagnostic, path *tftypes.AttributePath) []*tfprotov5.Diagnostic {
+	return Rewrite(diags, func(p *tftypes.AttributePath) *tftypes.AttributePath {
+		if p == nil || len(p.Steps()) == 0 {
+			return path
+		}
+		return p
+	})
+}
+
+// Prefix returns `diags` with `prefix` prepended to their attribute paths,
+// for bubbling diagnostics up from code that validates a nested block or
+// collection element on its own. Diagnostics that aren't attached to an
+// attribute are attached to `prefix`. `prefix` may contain any kind of step,
+// so diagnostics can be attached to elements of lists, sets, and maps:
+//
+//	tfdiags.Prefix(diags, tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(i))
+//
+// The diagnostics are copied rather than modified.
+func Prefix(diags []*tfprotov5.Diagnostic, prefix *tftypes.AttributePath) []*tfprotov5.Diagnostic {
+	return Rewrite(diags, func(p *tftypes.AttributePath) *tftypes.AttributePath {
+		return Join(prefix, p)
+	})
+}
+
+// Rewrite returns `diags` with their attribute paths replaced by the result
+// of `f`, which is called with nil for diagnostics that aren't attached to an
+// attribute. The diagnostics are copied rather than modified.
+func Rewrite(diags []*tfprotov5.Diagnostic, f func(*tftypes.AttributePath) *tftypes.AttributePath) []*tfprotov5.Diagnostic {
+	if diags == nil {
+		return nil
+	}
+	result := make([]*tfprotov5.Diagnostic, 0, len(diags))
+	for _, d := range diags {
+		if d == nil {
+			continue
+		}
+		c := *d
+		c.Attribute = f(d.Attribute)
+		result = append(result, &c)
+	}
+	return result
+}
+
+// Join returns a path made of the steps of each of `paths` in turn. Nil
+// paths are skipped.
+func Join(paths ...*tftypes.AttributePath) *tftypes.AttributePath {
+	var steps []tftypes.AttributePathStep
+	for _, p := range paths {
+		if p != nil {
+			steps = append(steps, p.Steps()...)
+		}
+	}
+	return tftypes.NewAttributePathWithSteps(steps)
+}
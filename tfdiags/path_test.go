@@ -0,0 +1,82 @@
+package tfdiags
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPathHelpers(t *testing.T) {
+	type testCase struct {
+		f        func([]*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic
+		expected []*tfprotov5.Diagnostic
+	}
+	port := tftypes.NewAttributePath().WithAttributeName("port")
+	rule := tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyValue(
+		tftypes.NewValue(tftypes.String, "ssh"),
+	)
+	diags := func() []*tfprotov5.Diagnostic {
+		return []*tfprotov5.Diagnostic{
+			warning("Attached", "", port),
+			warning("Unattached", "", nil),
+		}
+	}
+	cases := map[string]testCase{
+		"with-path": {
+			f: func(d []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+				return WithPath(d, rule)
+			},
+			expected: []*tfprotov5.Diagnostic{
+				warning("Attached", "", port),
+				warning("Unattached", "", rule),
+			},
+		},
+		"prefix": {
+			f: func(d []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+				return Prefix(d, rule)
+			},
+			expected: []*tfprotov5.Diagnostic{
+				warning("Attached", "", rule.WithAttributeName("port")),
+				warning("Unattached", "", rule),
+			},
+		},
+		"prefix-element": {
+			f: func(d []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+				return Prefix(d, tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyString("env"))
+			},
+			expected: []*tfprotov5.Diagnostic{
+				warning("Attached", "", tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyString("env").WithAttributeName("port")),
+				warning("Unattached", "", tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyString("env")),
+			},
+		},
+		"rewrite": {
+			f: func(d []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+				return Rewrite(d, func(p *tftypes.AttributePath) *tftypes.AttributePath {
+					if p.Equal(port) {
+						return tftypes.NewAttributePath().WithAttributeName("from_port")
+					}
+					return p
+				})
+			},
+			expected: []*tfprotov5.Diagnostic{
+				warning("Attached", "", tftypes.NewAttributePath().WithAttributeName("from_port")),
+				warning("Unattached", "", nil),
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			in := diags()
+			got := tc.f(in)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			if diff := cmp.Diff(diags(), in); diff != "" {
+				t.Errorf("expected input to be unchanged (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
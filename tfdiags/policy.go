@@ -0,0 +1,90 @@
+package tfdiags
+
+import (
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Match selects diagnostics. Each field that is set must match for a
+// diagnostic to be selected, so the zero Match selects every diagnostic.
+type Match struct {
+	// Code matches diagnostics created from the Template with this code.
+	Code string
+
+	// Summary matches diagnostics with exactly this summary.
+	Summary string
+
+	// Path matches diagnostics attached to this attribute, or to
+	// attributes nested within it. Element keys are ignored, as with the
+	// other registries in this module.
+	Path *tftypes.AttributePath
+}
+
+func (m Match) matches(d *tfprotov5.Diagnostic) bool {
+	if m.Code != "" && Code(d) != m.Code {
+		return false
+	}
+	if m.Summary != "" && d.Summary != m.Summary {
+		return false
+	}
+	if m.Path != nil {
+		if d.Attribute == nil {
+			return false
+		}
+		if !attrpath.HasPrefix(attrpath.Names(d.Attribute), attrpath.Names(m.Path)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Policy escalates or suppresses warnings, for providers, or organisations
+// building them, that want stricter or quieter behavior than the code
+// producing the warnings chose. Error diagnostics are never changed.
+type Policy struct {
+	// Suppress selects warnings that are removed entirely, like known
+	// noisy deprecation warnings.
+	Suppress []Match
+
+	// Escalate selects warnings that are turned into errors.
+	Escalate []Match
+
+	// Strict turns every warning that isn't suppressed into an error.
+	Strict bool
+}
+
+// Apply returns `diags` with the Policy applied. Suppression takes
+// precedence over escalation. Escalated diagnostics are copied rather than
+// modified.
+func (p *Policy) Apply(diags []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+	if p == nil || diags == nil {
+		return diags
+	}
+	result := make([]*tfprotov5.Diagnostic, 0, len(diags))
+	for _, d := range diags {
+		if d == nil || d.Severity != tfprotov5.DiagnosticSeverityWarning {
+			result = append(result, d)
+			continue
+		}
+		if anyMatch(p.Suppress, d) {
+			continue
+		}
+		if p.Strict || anyMatch(p.Escalate, d) {
+			c := *d
+			c.Severity = tfprotov5.DiagnosticSeverityError
+			d = &c
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+func anyMatch(matches []Match, d *tfprotov5.Diagnostic) bool {
+	for _, m := range matches {
+		if m.matches(d) {
+			return true
+		}
+	}
+	return false
+}
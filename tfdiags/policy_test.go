@@ -0,0 +1,66 @@
+package tfdiags
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPolicyApply(t *testing.T) {
+	type testCase struct {
+		policy   *Policy
+		expected []*tfprotov5.Diagnostic
+	}
+	deprecated := Define("WIDGET100", tfprotov5.DiagnosticSeverityWarning, "Deprecated attribute", "")
+	rule := tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(0).WithAttributeName("port")
+	diags := []*tfprotov5.Diagnostic{
+		deprecated.New(nil, nil),
+		warning("Slow", "", rule),
+		warning("Other", "", nil),
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Broken"},
+	}
+	escalated := func(d *tfprotov5.Diagnostic) *tfprotov5.Diagnostic {
+		c := *d
+		c.Severity = tfprotov5.DiagnosticSeverityError
+		return &c
+	}
+	cases := map[string]testCase{
+		"nil": {
+			expected: diags,
+		},
+		"suppress-code": {
+			policy:   &Policy{Suppress: []Match{{Code: "WIDGET100"}}},
+			expected: diags[1:],
+		},
+		"escalate-path": {
+			policy: &Policy{Escalate: []Match{{Path: tftypes.NewAttributePath().WithAttributeName("rule")}}},
+			expected: []*tfprotov5.Diagnostic{
+				diags[0], escalated(diags[1]), diags[2], diags[3],
+			},
+		},
+		"escalate-summary-and-path": {
+			policy:   &Policy{Escalate: []Match{{Summary: "Slow", Path: tftypes.NewAttributePath().WithAttributeName("name")}}},
+			expected: diags,
+		},
+		"strict": {
+			policy: &Policy{Strict: true, Suppress: []Match{{Summary: "Other"}}},
+			expected: []*tfprotov5.Diagnostic{
+				escalated(diags[0]), escalated(diags[1]), diags[3],
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got := tc.policy.Apply(diags)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			if diags[0].Severity != tfprotov5.DiagnosticSeverityWarning {
+				t.Error("expected input to be unchanged")
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package tfdiags
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+var (
+	_ tfprotov5.ProviderServerWithListResource = &Server{}
+	_ tfprotov5.ProviderServerWithActions      = &Server{}
+)
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithLogger causes the Server to write the diagnostics of every response
+// to the provider's logs using `l`.
+func WithLogger(l *Logger) Option {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+// WithPolicy causes the Server to apply `p` to the diagnostics of every
+// response. The Policy is applied before the diagnostics are logged.
+func WithPolicy(p *Policy) Option {
+	return func(s *Server) {
+		s.policy = p
+	}
+}
+
+// Server is a tfprotov5.ProviderServer that wraps another
+// tfprotov5.ProviderServer, processing the diagnostics of every response as
+// configured by its Options. The list resource and action RPCs are passed on
+// if the wrapped server implements tfprotov5.ListResourceServer or
+// tfprotov5.ActionServer, like tfrouter.Router.
+type Server struct {
+	srv    tfprotov5.ProviderServer
+	logger *Logger
+	policy *Policy
+}
+
+// NewServer returns a Server wrapping `srv`.
+func NewServer(srv tfprotov5.ProviderServer, opts ...Option) *Server {
+	s := &Server{srv: srv}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// process returns the diagnostics of a response after applying the Server's
+// Options to them.
+func (s *Server) process(ctx context.Context, diags []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+	diags = s.policy.Apply(diags)
+	if s.logger != nil {
+		s.logger.Log(ctx, diags)
+	}
+	return diags
+}
+
+func (s *Server) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	return s.srv.StopProvider(ctx, req)
+}
+
+func (s *Server) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	return s.srv.CallFunction(ctx, req)
+}
+
+func (s *Server) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.srv.GetMetadata(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.srv.GetProviderSchema(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) GetResourceIdentitySchemas(ctx context.Context, req *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	resp, err := s.srv.GetResourceIdentitySchemas(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	resp, err := s.srv.PrepareProviderConfig(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	resp, err := s.srv.ConfigureProvider(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	resp, err := s.srv.ValidateResourceTypeConfig(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	resp, err := s.srv.UpgradeResourceState(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	resp, err := s.srv.ReadResource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp, err := s.srv.PlanResourceChange(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	resp, err := s.srv.ApplyResourceChange(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	resp, err := s.srv.ImportResourceState(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	resp, err := s.srv.MoveResourceState(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	resp, err := s.srv.UpgradeResourceIdentity(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) GenerateResourceConfig(ctx context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	resp, err := s.srv.GenerateResourceConfig(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	resp, err := s.srv.ValidateDataSourceConfig(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	resp, err := s.srv.ReadDataSource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	resp, err := s.srv.GetFunctions(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	resp, err := s.srv.ValidateEphemeralResourceConfig(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	resp, err := s.srv.OpenEphemeralResource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	resp, err := s.srv.RenewEphemeralResource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	resp, err := s.srv.CloseEphemeralResource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+// listUnsupported returns the diagnostic returned by the list resource RPCs
+// when the wrapped server doesn't implement them.
+func listUnsupported() *tfprotov5.Diagnostic {
+	return diag.Errorf("List resources not supported", "The provider does not support list resources.")
+}
+
+func (s *Server) ValidateListResourceConfig(ctx context.Context, req *tfprotov5.ValidateListResourceConfigRequest) (*tfprotov5.ValidateListResourceConfigResponse, error) {
+	ls, ok := s.srv.(tfprotov5.ListResourceServer)
+	if !ok {
+		return &tfprotov5.ValidateListResourceConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{listUnsupported()}}, nil
+	}
+	resp, err := ls.ValidateListResourceConfig(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+// ListResource passes the request on to the wrapped server if it implements
+// tfprotov5.ListResourceServer, processing the diagnostics of each result as
+// it's streamed.
+func (s *Server) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	ls, ok := s.srv.(tfprotov5.ListResourceServer)
+	if !ok {
+		return &tfprotov5.ListResourceServerStream{
+			Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+				yield(tfprotov5.ListResourceResult{Diagnostics: []*tfprotov5.Diagnostic{listUnsupported()}})
+			},
+		}, nil
+	}
+	stream, err := ls.ListResource(ctx, req)
+	if err != nil || stream == nil || stream.Results == nil {
+		return stream, err
+	}
+	results := stream.Results
+	stream.Results = func(yield func(tfprotov5.ListResourceResult) bool) {
+		results(func(result tfprotov5.ListResourceResult) bool {
+			result.Diagnostics = s.process(ctx, result.Diagnostics)
+			return yield(result)
+		})
+	}
+	return stream, nil
+}
+
+// actionsUnsupported returns the diagnostic returned by the action RPCs when
+// the wrapped server doesn't implement them.
+func actionsUnsupported() *tfprotov5.Diagnostic {
+	return diag.Errorf("Actions not supported", "The provider does not support actions.")
+}
+
+func (s *Server) ValidateActionConfig(ctx context.Context, req *tfprotov5.ValidateActionConfigRequest) (*tfprotov5.ValidateActionConfigResponse, error) {
+	as, ok := s.srv.(tfprotov5.ActionServer)
+	if !ok {
+		return &tfprotov5.ValidateActionConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{actionsUnsupported()}}, nil
+	}
+	resp, err := as.ValidateActionConfig(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s *Server) PlanAction(ctx context.Context, req *tfprotov5.PlanActionRequest) (*tfprotov5.PlanActionResponse, error) {
+	as, ok := s.srv.(tfprotov5.ActionServer)
+	if !ok {
+		return &tfprotov5.PlanActionResponse{Diagnostics: []*tfprotov5.Diagnostic{actionsUnsupported()}}, nil
+	}
+	resp, err := as.PlanAction(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = s.process(ctx, resp.Diagnostics)
+	}
+	return resp, err
+}
+
+// InvokeAction passes the request on to the wrapped server if it implements
+// tfprotov5.ActionServer, processing the diagnostics of the completed event
+// as it's streamed.
+func (s *Server) InvokeAction(ctx context.Context, req *tfprotov5.InvokeActionRequest) (*tfprotov5.InvokeActionServerStream, error) {
+	as, ok := s.srv.(tfprotov5.ActionServer)
+	if !ok {
+		return &tfprotov5.InvokeActionServerStream{
+			Events: func(yield func(tfprotov5.InvokeActionEvent) bool) {
+				yield(tfprotov5.InvokeActionEvent{Type: tfprotov5.CompletedInvokeActionEventType{
+					Diagnostics: []*tfprotov5.Diagnostic{actionsUnsupported()},
+				}})
+			},
+		}, nil
+	}
+	stream, err := as.InvokeAction(ctx, req)
+	if err != nil || stream == nil || stream.Events == nil {
+		return stream, err
+	}
+	events := stream.Events
+	stream.Events = func(yield func(tfprotov5.InvokeActionEvent) bool) {
+		events(func(event tfprotov5.InvokeActionEvent) bool {
+			if completed, ok := event.Type.(tfprotov5.CompletedInvokeActionEventType); ok {
+				completed.Diagnostics = s.process(ctx, completed.Diagnostics)
+				event.Type = completed
+			}
+			return yield(event)
+		})
+	}
+	return stream, nil
+}
@@ -0,0 +1,173 @@
+package tfdiags
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+type diagnosticServer struct {
+	tfprotov5.ProviderServer
+
+	diags []*tfprotov5.Diagnostic
+}
+
+func (d *diagnosticServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	return &tfprotov5.ReadResourceResponse{Diagnostics: d.diags}, nil
+}
+
+func (d *diagnosticServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	return nil, nil
+}
+
+func TestServerLogger(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &buf)
+	inner := &diagnosticServer{diags: []*tfprotov5.Diagnostic{
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Boom"},
+	}}
+	srv := NewServer(inner, WithLogger(&Logger{}))
+
+	resp, err := srv.ReadResource(ctx, &tfprotov5.ReadResourceRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Errorf("expected diagnostics to be passed through, got %+v", resp.Diagnostics)
+	}
+	if _, err := srv.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0][LogKeySummary] != "Boom" {
+		t.Errorf("unexpected log entries: %v", entries)
+	}
+}
+
+func TestServerPolicy(t *testing.T) {
+	inner := &diagnosticServer{diags: []*tfprotov5.Diagnostic{
+		{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Noisy"},
+		{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Important"},
+	}}
+	srv := NewServer(inner, WithPolicy(&Policy{
+		Suppress: []Match{{Summary: "Noisy"}},
+		Strict:   true,
+	}))
+	resp, err := srv.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Important" || resp.Diagnostics[0].Severity != tfprotov5.DiagnosticSeverityError {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+}
+
+func (d *diagnosticServer) ValidateListResourceConfig(ctx context.Context, req *tfprotov5.ValidateListResourceConfigRequest) (*tfprotov5.ValidateListResourceConfigResponse, error) {
+	return &tfprotov5.ValidateListResourceConfigResponse{Diagnostics: d.diags}, nil
+}
+
+func (d *diagnosticServer) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	return &tfprotov5.ListResourceServerStream{
+		Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+			yield(tfprotov5.ListResourceResult{DisplayName: "found", Diagnostics: d.diags})
+		},
+	}, nil
+}
+
+func TestServerListResource(t *testing.T) {
+	newInner := func() *diagnosticServer {
+		return &diagnosticServer{diags: []*tfprotov5.Diagnostic{
+			{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Noisy"},
+			{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Important"},
+		}}
+	}
+	policy := &Policy{Suppress: []Match{{Summary: "Noisy"}}}
+
+	validate, err := NewServer(newInner(), WithPolicy(policy)).ValidateListResourceConfig(context.Background(), &tfprotov5.ValidateListResourceConfigRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validate.Diagnostics) != 1 || validate.Diagnostics[0].Summary != "Important" {
+		t.Errorf("unexpected diagnostics: %+v", validate.Diagnostics)
+	}
+
+	stream, err := NewServer(newInner(), WithPolicy(policy)).ListResource(context.Background(), &tfprotov5.ListResourceRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []tfprotov5.ListResourceResult
+	for result := range stream.Results {
+		results = append(results, result)
+	}
+	if len(results) != 1 || len(results[0].Diagnostics) != 1 || results[0].Diagnostics[0].Summary != "Important" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func (d *diagnosticServer) ValidateActionConfig(ctx context.Context, req *tfprotov5.ValidateActionConfigRequest) (*tfprotov5.ValidateActionConfigResponse, error) {
+	return &tfprotov5.ValidateActionConfigResponse{Diagnostics: d.diags}, nil
+}
+
+func (d *diagnosticServer) PlanAction(ctx context.Context, req *tfprotov5.PlanActionRequest) (*tfprotov5.PlanActionResponse, error) {
+	return &tfprotov5.PlanActionResponse{Diagnostics: d.diags}, nil
+}
+
+func (d *diagnosticServer) InvokeAction(ctx context.Context, req *tfprotov5.InvokeActionRequest) (*tfprotov5.InvokeActionServerStream, error) {
+	return &tfprotov5.InvokeActionServerStream{
+		Events: func(yield func(tfprotov5.InvokeActionEvent) bool) {
+			if !yield(tfprotov5.InvokeActionEvent{Type: tfprotov5.ProgressInvokeActionEventType{Message: "running"}}) {
+				return
+			}
+			yield(tfprotov5.InvokeActionEvent{Type: tfprotov5.CompletedInvokeActionEventType{Diagnostics: d.diags}})
+		},
+	}, nil
+}
+
+func TestServerActions(t *testing.T) {
+	newInner := func() *diagnosticServer {
+		return &diagnosticServer{diags: []*tfprotov5.Diagnostic{
+			{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Noisy"},
+			{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Important"},
+		}}
+	}
+	policy := &Policy{Suppress: []Match{{Summary: "Noisy"}}}
+
+	validate, err := NewServer(newInner(), WithPolicy(policy)).ValidateActionConfig(context.Background(), &tfprotov5.ValidateActionConfigRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validate.Diagnostics) != 1 || validate.Diagnostics[0].Summary != "Important" {
+		t.Errorf("unexpected diagnostics: %+v", validate.Diagnostics)
+	}
+
+	plan, err := NewServer(newInner(), WithPolicy(policy)).PlanAction(context.Background(), &tfprotov5.PlanActionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Diagnostics) != 1 || plan.Diagnostics[0].Summary != "Important" {
+		t.Errorf("unexpected diagnostics: %+v", plan.Diagnostics)
+	}
+
+	stream, err := NewServer(newInner(), WithPolicy(policy)).InvokeAction(context.Background(), &tfprotov5.InvokeActionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []tfprotov5.InvokeActionEvent
+	for event := range stream.Events {
+		events = append(events, event)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	completed, ok := events[1].Type.(tfprotov5.CompletedInvokeActionEventType)
+	if !ok || len(completed.Diagnostics) != 1 || completed.Diagnostics[0].Summary != "Important" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
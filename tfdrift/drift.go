@@ -0,0 +1,321 @@
+// Package tfdrift reports the differences between the prior state of a
+// resource and the value freshly read from its API, so that providers can
+// tell practitioners what changed outside of Terraform.
+//
+// Differences that a tfequal.Registry considers semantically equal, like
+// reordered JSON keys, aren't drift and aren't reported.
+package tfdrift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalue"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Fields added to the log entries written by Report.Log.
+const (
+	LogKeyAttribute = "drift_attribute"
+	LogKeySeverity  = "drift_severity"
+	LogKeyOld       = "drift_old"
+	LogKeyNew       = "drift_new"
+)
+
+// Severity describes how significant a Change is.
+type Severity int
+
+const (
+	// SeverityInfo is for changes that are expected, like counters or
+	// timestamps maintained by the API, and are only worth logging.
+	SeverityInfo Severity = iota
+
+	// SeverityWarning is for changes practitioners should know about.
+	// It's the severity of changes when Detector.Severity isn't set.
+	SeverityWarning
+
+	// SeverityCritical is for changes that may affect the security or
+	// availability of the resource.
+	SeverityCritical
+)
+
+// String returns the name of the Severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Change is a value that differs between the prior state and the remote
+// value. Attributes and map elements that were added or removed have a null
+// Old or New value.
+type Change struct {
+	Path     *tftypes.AttributePath
+	Old      tftypes.Value
+	New      tftypes.Value
+	Severity Severity
+
+	// Sensitive is true if the value is, contains, or is nested within a
+	// sensitive attribute, in which case Old and New are never written
+	// to logs or diagnostics.
+	Sensitive bool
+}
+
+// Report lists the Changes found by a Detector, in the order of their
+// paths, with attribute names and map keys sorted.
+type Report struct {
+	Changes []Change
+}
+
+// HasDrift returns true if the Report has any Changes.
+func (r Report) HasDrift() bool {
+	return len(r.Changes) > 0
+}
+
+// Max returns the highest Severity of the Report's Changes, or SeverityInfo
+// if there aren't any.
+func (r Report) Max() Severity {
+	max := SeverityInfo
+	for _, c := range r.Changes {
+		if c.Severity > max {
+			max = c.Severity
+		}
+	}
+	return max
+}
+
+// Diagnostics returns a warning diagnostic attached to the path of each
+// Change with a Severity of at least `min`, for ReadResource responses.
+func (r Report) Diagnostics(min Severity) []*tfprotov5.Diagnostic {
+	var diags []*tfprotov5.Diagnostic
+	for _, c := range r.Changes {
+		if c.Severity < min {
+			continue
+		}
+		summary := "Value changed outside of Terraform"
+		if c.Severity == SeverityCritical {
+			summary = "Critical value changed outside of Terraform"
+		}
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityWarning,
+			Summary:   summary,
+			Detail:    fmt.Sprintf("%s changed from %s to %s.", describe(c.Path), c.old(), c.new()),
+			Attribute: c.Path,
+		})
+	}
+	return diags
+}
+
+// Log writes each Change to the log using tflog, at the info level for
+// SeverityInfo and at the warn level for the others.
+func (r Report) Log(ctx context.Context) {
+	for _, c := range r.Changes {
+		fields := map[string]interface{}{
+			LogKeyAttribute: tfpath.String(c.Path),
+			LogKeySeverity:  c.Severity.String(),
+			LogKeyOld:       c.old(),
+			LogKeyNew:       c.new(),
+		}
+		if c.Severity == SeverityInfo {
+			tflog.Info(ctx, "Value changed outside of Terraform", fields)
+		} else {
+			tflog.Warn(ctx, "Value changed outside of Terraform", fields)
+		}
+	}
+}
+
+// String returns the Report as text, with one line for each Change.
+func (r Report) String() string {
+	var b strings.Builder
+	for _, c := range r.Changes {
+		fmt.Fprintf(&b, "%s (%s): %s -> %s\n", describe(c.Path), c.Severity, c.old(), c.new())
+	}
+	return b.String()
+}
+
+func (c Change) old() string {
+	return c.format(c.Old)
+}
+
+func (c Change) new() string {
+	return c.format(c.New)
+}
+
+func (c Change) format(val tftypes.Value) string {
+	if c.Sensitive {
+		return "(sensitive value)"
+	}
+	return tfvalue.Printer{}.Format(val)
+}
+
+func describe(path *tftypes.AttributePath) string {
+	if len(path.Steps()) == 0 {
+		return "The resource"
+	}
+	return tfpath.String(path)
+}
+
+// Detector compares prior state with remote values. The zero value is ready
+// to use, and reports every difference as a SeverityWarning Change.
+type Detector struct {
+	// Equality, if set, is used to ignore differences between values
+	// that are semantically equal.
+	Equality *tfequal.Registry
+
+	// Sensitive are the paths of attributes whose values are redacted
+	// in Reports. Attributes nested within them are redacted too, and
+	// element keys are ignored when matching paths.
+	// tfdiags.SensitivePaths returns the paths of a schema's sensitive
+	// attributes.
+	Sensitive []*tftypes.AttributePath
+
+	// Severity, if set, returns the Severity of a Change to the value at
+	// `path`.
+	Severity func(path *tftypes.AttributePath) Severity
+}
+
+// Detect returns a Report of the differences between `prior` and `remote`.
+//
+// Objects, maps, and lists and tuples of the same length are compared
+// element by element, so the Report points at the attributes that changed.
+// Sets, and lists and tuples whose lengths differ, are reported as a whole.
+// If either value is null, as when the resource has just been imported or
+// has been deleted, there's no drift to report.
+func (d Detector) Detect(prior, remote tftypes.Value) (Report, error) {
+	var r Report
+	if prior.IsNull() || remote.IsNull() {
+		return r, nil
+	}
+	if err := d.compare(&r, tftypes.NewAttributePath(), prior, remote); err != nil {
+		return Report{}, err
+	}
+	return r, nil
+}
+
+// Detect returns a Report of the differences between `prior` and `remote`
+// found by a Detector using `equality`.
+func Detect(equality *tfequal.Registry, prior, remote tftypes.Value) (Report, error) {
+	return Detector{Equality: equality}.Detect(prior, remote)
+}
+
+func (d Detector) compare(r *Report, path *tftypes.AttributePath, prior, remote tftypes.Value) error {
+	equal, err := d.Equality.Equal(path, prior, remote)
+	if err != nil {
+		return path.NewError(err)
+	}
+	if equal {
+		return nil
+	}
+	if prior.IsNull() || !prior.IsKnown() || remote.IsNull() || !remote.IsKnown() || !prior.Type().Equal(remote.Type()) {
+		d.add(r, path, prior, remote)
+		return nil
+	}
+	typ := prior.Type()
+	switch {
+	case typ.Is(tftypes.Object{}):
+		var p, v map[string]tftypes.Value
+		if err := prior.As(&p); err != nil {
+			return err
+		}
+		if err := remote.As(&v); err != nil {
+			return err
+		}
+		for _, name := range sorted.Keys(p) {
+			if err := d.compare(r, path.WithAttributeName(name), p[name], v[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case typ.Is(tftypes.Map{}):
+		var p, v map[string]tftypes.Value
+		if err := prior.As(&p); err != nil {
+			return err
+		}
+		if err := remote.As(&v); err != nil {
+			return err
+		}
+		null := tftypes.NewValue(typ.(tftypes.Map).ElementType, nil)
+		keys := sorted.Keys(p)
+		for k := range v {
+			if _, ok := p[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			pv, ok := p[k]
+			if !ok {
+				pv = null
+			}
+			rv, ok := v[k]
+			if !ok {
+				rv = null
+			}
+			if err := d.compare(r, path.WithElementKeyString(k), pv, rv); err != nil {
+				return err
+			}
+		}
+		return nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Tuple{}):
+		var p, v []tftypes.Value
+		if err := prior.As(&p); err != nil {
+			return err
+		}
+		if err := remote.As(&v); err != nil {
+			return err
+		}
+		if len(p) != len(v) {
+			d.add(r, path, prior, remote)
+			return nil
+		}
+		for i := range p {
+			if err := d.compare(r, path.WithElementKeyInt(i), p[i], v[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	d.add(r, path, prior, remote)
+	return nil
+}
+
+func (d Detector) add(r *Report, path *tftypes.AttributePath, prior, remote tftypes.Value) {
+	severity := SeverityWarning
+	if d.Severity != nil {
+		severity = d.Severity(path)
+	}
+	r.Changes = append(r.Changes, Change{
+		Path:      path,
+		Old:       prior,
+		New:       remote,
+		Severity:  severity,
+		Sensitive: d.sensitive(path),
+	})
+}
+
+// sensitive returns true if `path` is, contains, or is nested within one of
+// the Sensitive paths.
+func (d Detector) sensitive(path *tftypes.AttributePath) bool {
+	names := attrpath.Names(path)
+	for _, s := range d.Sensitive {
+		sensitive := attrpath.Names(s)
+		if attrpath.HasPrefix(names, sensitive) || attrpath.HasPrefix(sensitive, names) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package tfdrift
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"policy":   tftypes.String,
+	"password": tftypes.String,
+	"ports":    tftypes.List{ElementType: tftypes.Number},
+	"tags":     tftypes.Map{ElementType: tftypes.String},
+	"updated":  tftypes.String,
+}}
+
+func testValue(policy, password, updated string, ports []tftypes.Value, tags map[string]tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(testType, map[string]tftypes.Value{
+		"policy":   tftypes.NewValue(tftypes.String, policy),
+		"password": tftypes.NewValue(tftypes.String, password),
+		"ports":    tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, ports),
+		"tags":     tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tags),
+		"updated":  tftypes.NewValue(tftypes.String, updated),
+	})
+}
+
+func ports(ns ...int64) []tftypes.Value {
+	vals := []tftypes.Value{}
+	for _, n := range ns {
+		vals = append(vals, tftypes.NewValue(tftypes.Number, n))
+	}
+	return vals
+}
+
+func tags(kv ...string) map[string]tftypes.Value {
+	vals := map[string]tftypes.Value{}
+	for i := 0; i < len(kv); i += 2 {
+		vals[kv[i]] = tftypes.NewValue(tftypes.String, kv[i+1])
+	}
+	return vals
+}
+
+func testDetector() Detector {
+	equality := &tfequal.Registry{}
+	equality.AddPath(tftypes.NewAttributePath().WithAttributeName("policy"), tfequal.JSON())
+	return Detector{
+		Equality:  equality,
+		Sensitive: []*tftypes.AttributePath{tftypes.NewAttributePath().WithAttributeName("password")},
+		Severity: func(path *tftypes.AttributePath) Severity {
+			switch {
+			case path.Equal(tftypes.NewAttributePath().WithAttributeName("updated")):
+				return SeverityInfo
+			case path.Equal(tftypes.NewAttributePath().WithAttributeName("password")):
+				return SeverityCritical
+			}
+			return SeverityWarning
+		},
+	}
+}
+
+func TestDetect(t *testing.T) {
+	type testCase struct {
+		prior    tftypes.Value
+		remote   tftypes.Value
+		expected string
+	}
+	cases := map[string]testCase{
+		"semantically-equal": {
+			prior:  testValue(`{"a": 1, "b": 2}`, "x", "t1", ports(80), tags("env", "prod")),
+			remote: testValue(`{"b":2,"a":1}`, "x", "t1", ports(80), tags("env", "prod")),
+		},
+		"changed": {
+			prior:  testValue(`{"a": 1}`, "x", "t1", ports(80, 443), tags("env", "prod", "team", "infra")),
+			remote: testValue(`{"a":2}`, "y", "t2", ports(80, 8443), tags("env", "dev", "owner", "me")),
+			expected: `password (critical): (sensitive value) -> (sensitive value)
+policy (warning): "{\"a\": 1}" -> "{\"a\":2}"
+ports[1] (warning): 443 -> 8443
+tags["env"] (warning): "prod" -> "dev"
+tags["owner"] (warning): null -> "me"
+tags["team"] (warning): "infra" -> null
+updated (info): "t1" -> "t2"
+`,
+		},
+		"list-length": {
+			prior:    testValue("{}", "x", "t1", ports(80), nil),
+			remote:   testValue("{}", "x", "t1", ports(80, 443), nil),
+			expected: "ports (warning): [\n  80,\n] -> [\n  80,\n  443,\n]\n",
+		},
+		"null-prior": {
+			prior:  tftypes.NewValue(testType, nil),
+			remote: testValue("{}", "x", "t1", nil, nil),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			report, err := testDetector().Detect(tc.prior, tc.remote)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, report.String()); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			if report.HasDrift() != (tc.expected != "") {
+				t.Errorf("expected HasDrift to be %t", tc.expected != "")
+			}
+		})
+	}
+}
+
+func TestReportDiagnostics(t *testing.T) {
+	report, err := testDetector().Detect(
+		testValue("{}", "x", "t1", nil, nil),
+		testValue("{}", "y", "t2", nil, tags("env", "dev")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if report.Max() != SeverityCritical {
+		t.Errorf("expected max severity critical, got %s", report.Max())
+	}
+	expected := []*tfprotov5.Diagnostic{
+		{
+			Severity:  tfprotov5.DiagnosticSeverityWarning,
+			Summary:   "Critical value changed outside of Terraform",
+			Detail:    "password changed from (sensitive value) to (sensitive value).",
+			Attribute: tftypes.NewAttributePath().WithAttributeName("password"),
+		},
+		{
+			Severity:  tfprotov5.DiagnosticSeverityWarning,
+			Summary:   "Value changed outside of Terraform",
+			Detail:    `tags["env"] changed from null to "dev".`,
+			Attribute: tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyString("env"),
+		},
+	}
+	if diff := cmp.Diff(expected, report.Diagnostics(SeverityWarning)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
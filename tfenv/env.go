@@ -0,0 +1,419 @@
+// Package tfenv converts values to and from environment variable style maps,
+// like APP_DATABASE_HOSTS_0_PORT=5432, for providers that render nested
+// configuration into key/value stores that only hold strings, like
+// environment variables, CI variables, or parameter stores.
+//
+// Each variable's name is the path of a string, number, or bool, with its
+// steps joined by a separator: attribute names and map keys, in the
+// Converter's case, and the indexes of list, set, and tuple elements.
+// Nulls aren't written, so they, and empty collections, are read back as
+// null. Unknown values can't be flattened.
+package tfenv
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	tfString tftypes.Type = tftypes.String
+	tfNumber tftypes.Type = tftypes.Number
+	tfBool   tftypes.Type = tftypes.Bool
+	tfList   tftypes.Type = tftypes.List{}
+	tfSet    tftypes.Type = tftypes.Set{}
+	tfTuple  tftypes.Type = tftypes.Tuple{}
+	tfMap    tftypes.Type = tftypes.Map{}
+	tfObject tftypes.Type = tftypes.Object{}
+)
+
+// Case is how attribute names and map keys are written in variable names.
+type Case int
+
+const (
+	// CaseUpper writes names in upper case, like environment variables
+	// conventionally are.
+	CaseUpper Case = iota
+
+	// CaseLower writes names in lower case.
+	CaseLower
+
+	// CasePreserve writes names as they are.
+	CasePreserve
+)
+
+func (c Case) apply(s string) string {
+	switch c {
+	case CaseUpper:
+		return strings.ToUpper(s)
+	case CaseLower:
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// Converter converts values to and from variables.
+type Converter struct {
+	// Prefix is the first step of every variable's name, like APP. When
+	// expanding, variables that don't start with it are ignored.
+	Prefix string
+
+	// Separator joins the steps of variables' names, "_" by default.
+	Separator string
+
+	// Case is how attribute names and map keys are written. Map keys are
+	// read back as they're written, so they only survive the round trip
+	// unchanged with CasePreserve, or if they're already in the Case.
+	Case Case
+}
+
+// Flatten returns `val` as variables, with a Converter with the default
+// settings.
+func Flatten(val tftypes.Value) (map[string]string, error) {
+	var c Converter
+	return c.Flatten(val)
+}
+
+// Expand returns the value of type `typ` the variables `env` hold, with a
+// Converter with the default settings.
+func Expand(env map[string]string, typ tftypes.Type) (tftypes.Value, error) {
+	var c Converter
+	return c.Expand(env, typ)
+}
+
+func (c Converter) separator() string {
+	if c.Separator == "" {
+		return "_"
+	}
+	return c.Separator
+}
+
+func (c Converter) join(name, step string) string {
+	if name == "" {
+		return step
+	}
+	return name + c.separator() + step
+}
+
+// Flatten returns `val` as variables. The elements of sets are numbered in
+// the order of their variables' values, so the result is deterministic.
+func (c Converter) Flatten(val tftypes.Value) (map[string]string, error) {
+	env := map[string]string{}
+	if err := c.flatten(env, tftypes.NewAttributePath(), c.Prefix, val); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func (c Converter) flatten(env map[string]string, path *tftypes.AttributePath, name string, val tftypes.Value) error {
+	if !val.IsKnown() {
+		return path.NewErrorf("cannot flatten an unknown value")
+	}
+	if val.IsNull() {
+		return nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tfString):
+		var s string
+		if err := val.As(&s); err != nil {
+			return path.NewError(err)
+		}
+		return c.set(env, path, name, s)
+	case typ.Is(tfNumber):
+		f := new(big.Float)
+		if err := val.As(&f); err != nil {
+			return path.NewError(err)
+		}
+		return c.set(env, path, name, f.Text('f', -1))
+	case typ.Is(tfBool):
+		var b bool
+		if err := val.As(&b); err != nil {
+			return path.NewError(err)
+		}
+		return c.set(env, path, name, strconv.FormatBool(b))
+	case typ.Is(tfMap), typ.Is(tfObject):
+		var attrs map[string]tftypes.Value
+		if err := val.As(&attrs); err != nil {
+			return path.NewError(err)
+		}
+		for k, attr := range attrs {
+			next := path.WithAttributeName(k)
+			if typ.Is(tfMap) {
+				next = path.WithElementKeyString(k)
+			}
+			if err := c.flatten(env, next, c.join(name, c.Case.apply(k)), attr); err != nil {
+				return err
+			}
+		}
+		return nil
+	case typ.Is(tfSet):
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return path.NewError(err)
+		}
+		// Number the elements in the order of their variables, as
+		// they'd be written if they were the only element.
+		keys := make(map[int]string, len(elems))
+		for i, elem := range elems {
+			elemEnv := map[string]string{}
+			if err := c.flatten(elemEnv, path.WithElementKeyValue(elem), "0", elem); err != nil {
+				return err
+			}
+			keys[i] = envString(elemEnv)
+		}
+		order := make([]int, len(elems))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return keys[order[i]] < keys[order[j]]
+		})
+		for i, j := range order {
+			if err := c.flatten(env, path.WithElementKeyValue(elems[j]), c.join(name, strconv.Itoa(i)), elems[j]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case typ.Is(tfList), typ.Is(tfTuple):
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return path.NewError(err)
+		}
+		for i, elem := range elems {
+			if err := c.flatten(env, path.WithElementKeyInt(i), c.join(name, strconv.Itoa(i)), elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return path.NewErrorf("cannot flatten values of type %s", typ)
+}
+
+// set sets the variable `name`, unless two values would have the same
+// name, like attributes whose names only differ by case.
+func (c Converter) set(env map[string]string, path *tftypes.AttributePath, name, value string) error {
+	if name == "" {
+		return path.NewErrorf("cannot flatten a string, number, or bool without a Prefix to name it")
+	}
+	if _, ok := env[name]; ok {
+		return path.NewErrorf("more than one value would be written to %s", name)
+	}
+	env[name] = value
+	return nil
+}
+
+// envString returns `env` as a string, for sorting.
+func envString(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + "=" + env[k] + "\n")
+	}
+	return b.String()
+}
+
+// Expand returns the value of type `typ` the variables `env` hold, the
+// inverse of Flatten. The type resolves the ambiguity of names with
+// separators in them: APP_DB_HOST is the db_host attribute of an object
+// with one, and the host attribute of the db attribute otherwise. Every
+// variable that starts with the Prefix must be part of the value.
+func (c Converter) Expand(env map[string]string, typ tftypes.Type) (tftypes.Value, error) {
+	vars := map[string]variable{}
+	for name, value := range env {
+		rest := name
+		if c.Prefix != "" {
+			if name == c.Prefix {
+				rest = ""
+			} else if strings.HasPrefix(name, c.Prefix+c.separator()) {
+				rest = strings.TrimPrefix(name, c.Prefix+c.separator())
+			} else {
+				continue
+			}
+		}
+		vars[rest] = variable{name: name, value: value}
+	}
+	return c.expand(tftypes.NewAttributePath(), vars, typ)
+}
+
+// variable is a variable being expanded.
+type variable struct {
+	name, value string
+}
+
+// expand returns the value of type `typ` at `path`, from `vars`, keyed by
+// the rest of their names after the path.
+func (c Converter) expand(path *tftypes.AttributePath, vars map[string]variable, typ tftypes.Type) (tftypes.Value, error) {
+	if len(vars) == 0 {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	switch {
+	case typ.Is(tfString), typ.Is(tfNumber), typ.Is(tfBool):
+		for rest, v := range vars {
+			if rest != "" {
+				return tftypes.Value{}, path.NewErrorf("unexpected variable %s", v.name)
+			}
+		}
+		return parse(path, vars[""], typ)
+	case typ.Is(tfObject):
+		obj := typ.(tftypes.Object)
+		groups := map[string]map[string]variable{}
+		for rest, v := range vars {
+			attr, sub, ok := c.attribute(obj, rest)
+			if !ok {
+				return tftypes.Value{}, path.NewErrorf("unexpected variable %s", v.name)
+			}
+			if groups[attr] == nil {
+				groups[attr] = map[string]variable{}
+			}
+			groups[attr][sub] = v
+		}
+		attrs := make(map[string]tftypes.Value, len(obj.AttributeTypes))
+		for name, attrType := range obj.AttributeTypes {
+			attr, err := c.expand(path.WithAttributeName(name), groups[name], attrType)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[name] = attr
+		}
+		return tftypes.NewValue(typ, attrs), nil
+	case typ.Is(tfMap):
+		elemType := typ.(tftypes.Map).ElementType
+		groups := map[string]map[string]variable{}
+		for rest, v := range vars {
+			key, sub := rest, ""
+			// Keys of maps of strings, numbers, and bools can have
+			// separators in them, as they're the last step.
+			if !isPrimitive(elemType) {
+				key, sub = c.split(rest)
+			}
+			if key == "" {
+				return tftypes.Value{}, path.NewErrorf("unexpected variable %s", v.name)
+			}
+			if groups[key] == nil {
+				groups[key] = map[string]variable{}
+			}
+			groups[key][sub] = v
+		}
+		elems := make(map[string]tftypes.Value, len(groups))
+		for key, group := range groups {
+			elem, err := c.expand(path.WithElementKeyString(key), group, elemType)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[key] = elem
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case typ.Is(tfList), typ.Is(tfSet), typ.Is(tfTuple):
+		groups := map[int]map[string]variable{}
+		for rest, v := range vars {
+			step, sub := c.split(rest)
+			i, err := strconv.Atoi(step)
+			if err != nil || i < 0 || strconv.Itoa(i) != step {
+				return tftypes.Value{}, path.NewErrorf("unexpected variable %s, expected an index after %s", v.name, strings.TrimSuffix(v.name, rest))
+			}
+			if groups[i] == nil {
+				groups[i] = map[string]variable{}
+			}
+			groups[i][sub] = v
+		}
+		n := 0
+		for i := range groups {
+			if i >= n {
+				n = i + 1
+			}
+		}
+		elemTypes := make([]tftypes.Type, n)
+		switch t := typ.(type) {
+		case tftypes.List:
+			for i := range elemTypes {
+				elemTypes[i] = t.ElementType
+			}
+		case tftypes.Set:
+			for i := range elemTypes {
+				elemTypes[i] = t.ElementType
+			}
+		case tftypes.Tuple:
+			if len(t.ElementTypes) != n {
+				return tftypes.Value{}, path.NewErrorf("expected %d elements, got %d", len(t.ElementTypes), n)
+			}
+			elemTypes = t.ElementTypes
+		}
+		elems := make([]tftypes.Value, 0, n)
+		for i := range elemTypes {
+			group, ok := groups[i]
+			if !ok {
+				return tftypes.Value{}, path.WithElementKeyInt(i).NewErrorf("missing element, the indexes of elements must be consecutive")
+			}
+			elem, err := c.expand(path.WithElementKeyInt(i), group, elemTypes[i])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		if err := tftypes.ValidateValue(typ, elems); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	}
+	return tftypes.Value{}, path.NewErrorf("cannot expand values of type %s", typ)
+}
+
+// attribute returns the attribute of `obj` the rest of a variable's name,
+// `rest`, starts with, preferring the longest, and the rest of the name
+// after it.
+func (c Converter) attribute(obj tftypes.Object, rest string) (string, string, bool) {
+	var attr, sub string
+	found := false
+	for name := range obj.AttributeTypes {
+		step := c.Case.apply(name)
+		if found && len(name) <= len(attr) {
+			continue
+		}
+		switch {
+		case rest == step:
+			attr, sub, found = name, "", true
+		case strings.HasPrefix(rest, step+c.separator()):
+			attr, sub, found = name, strings.TrimPrefix(rest, step+c.separator()), true
+		}
+	}
+	return attr, sub, found
+}
+
+// split returns the first step of `rest` and the rest after it.
+func (c Converter) split(rest string) (string, string) {
+	if i := strings.Index(rest, c.separator()); i >= 0 {
+		return rest[:i], rest[i+len(c.separator()):]
+	}
+	return rest, ""
+}
+
+func isPrimitive(typ tftypes.Type) bool {
+	return typ.Is(tfString) || typ.Is(tfNumber) || typ.Is(tfBool)
+}
+
+// parse returns the value of `v` as a value of the primitive type `typ`.
+func parse(path *tftypes.AttributePath, v variable, typ tftypes.Type) (tftypes.Value, error) {
+	switch {
+	case typ.Is(tfNumber):
+		f, _, err := big.ParseFloat(v.value, 10, 512, big.ToNearestEven)
+		if err != nil {
+			return tftypes.Value{}, path.NewErrorf("cannot parse %s=%q as a number", v.name, v.value)
+		}
+		return tftypes.NewValue(typ, f), nil
+	case typ.Is(tfBool):
+		b, err := strconv.ParseBool(v.value)
+		if err != nil {
+			return tftypes.Value{}, path.NewErrorf("cannot parse %s=%q as a bool", v.name, v.value)
+		}
+		return tftypes.NewValue(typ, b), nil
+	}
+	return tftypes.NewValue(typ, v.value), nil
+}
@@ -0,0 +1,169 @@
+package tfenv
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	hostType   = tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String, "port": tftypes.Number}}
+	configType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"db":      tftypes.Object{AttributeTypes: map[string]tftypes.Type{"hosts": tftypes.List{ElementType: hostType}}},
+		"db_user": tftypes.String,
+		"debug":   tftypes.Bool,
+		"labels":  tftypes.Map{ElementType: tftypes.String},
+		"zones":   tftypes.Set{ElementType: tftypes.String},
+		"extra":   tftypes.String,
+	}}
+)
+
+func host(name string, port float64) tftypes.Value {
+	return tftypes.NewValue(hostType, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, name),
+		"port": tftypes.NewValue(tftypes.Number, big.NewFloat(port)),
+	})
+}
+
+func testConfig() tftypes.Value {
+	return tftypes.NewValue(configType, map[string]tftypes.Value{
+		"db": tftypes.NewValue(configType.AttributeTypes["db"], map[string]tftypes.Value{
+			"hosts": tftypes.NewValue(tftypes.List{ElementType: hostType}, []tftypes.Value{
+				host("a", 5432),
+				host("b", 5433),
+			}),
+		}),
+		"db_user": tftypes.NewValue(tftypes.String, "admin"),
+		"debug":   tftypes.NewValue(tftypes.Bool, true),
+		"labels": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"TEAM_NAME": tftypes.NewValue(tftypes.String, "infra"),
+		}),
+		"zones": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "us-east-1b"),
+			tftypes.NewValue(tftypes.String, "us-east-1a"),
+		}),
+		"extra": tftypes.NewValue(tftypes.String, nil),
+	})
+}
+
+func TestFlatten(t *testing.T) {
+	type testCase struct {
+		converter Converter
+		expected  map[string]string
+	}
+	cases := map[string]testCase{
+		"default": {
+			expected: map[string]string{
+				"DB_HOSTS_0_NAME":  "a",
+				"DB_HOSTS_0_PORT":  "5432",
+				"DB_HOSTS_1_NAME":  "b",
+				"DB_HOSTS_1_PORT":  "5433",
+				"DB_USER":          "admin",
+				"DEBUG":            "true",
+				"LABELS_TEAM_NAME": "infra",
+				"ZONES_0":          "us-east-1a",
+				"ZONES_1":          "us-east-1b",
+			},
+		},
+		"prefix-separator-case": {
+			converter: Converter{Prefix: "app", Separator: ".", Case: CaseLower},
+			expected: map[string]string{
+				"app.db.hosts.0.name":  "a",
+				"app.db.hosts.0.port":  "5432",
+				"app.db.hosts.1.name":  "b",
+				"app.db.hosts.1.port":  "5433",
+				"app.db_user":          "admin",
+				"app.debug":            "true",
+				"app.labels.team_name": "infra",
+				"app.zones.0":          "us-east-1a",
+				"app.zones.1":          "us-east-1b",
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.converter.Flatten(testConfig())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFlattenErrors(t *testing.T) {
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"a": tftypes.String, "A": tftypes.String}}
+	_, err := Flatten(tftypes.NewValue(typ, map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.String, "x"),
+		"A": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	}))
+	expectedErr := `AttributeName("A"): cannot flatten an unknown value`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+
+	_, err = Flatten(tftypes.NewValue(tftypes.String, "x"))
+	expectedErr = "cannot flatten a string, number, or bool without a Prefix to name it"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	c := Converter{Prefix: "APP"}
+	env, err := c.Flatten(testConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	env["PATH"] = "/usr/bin"
+	got, err := c.Expand(env, configType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(testConfig(), got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	type testCase struct {
+		env         map[string]string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"unexpected": {
+			env:         map[string]string{"NOPE": "x"},
+			expectedErr: "unexpected variable NOPE",
+		},
+		"too-deep": {
+			env:         map[string]string{"DEBUG_X": "true"},
+			expectedErr: `AttributeName("debug"): unexpected variable DEBUG_X`,
+		},
+		"bad-index": {
+			env:         map[string]string{"DB_HOSTS_FIRST_NAME": "a"},
+			expectedErr: `AttributeName("db").AttributeName("hosts"): unexpected variable DB_HOSTS_FIRST_NAME, expected an index after DB_HOSTS_`,
+		},
+		"missing-index": {
+			env:         map[string]string{"DB_HOSTS_1_NAME": "a"},
+			expectedErr: `AttributeName("db").AttributeName("hosts").ElementKeyInt(0): missing element, the indexes of elements must be consecutive`,
+		},
+		"bad-bool": {
+			env:         map[string]string{"DEBUG": "maybe"},
+			expectedErr: `AttributeName("debug"): cannot parse DEBUG="maybe" as a bool`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := Expand(tc.env, configType)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
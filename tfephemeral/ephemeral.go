@@ -0,0 +1,82 @@
+// Package tfephemeral provides a typed abstraction over the ephemeral
+// resource RPCs of tfprotov5.EphemeralResourceServer.
+//
+// Ephemeral resources describe short-lived remote objects, like credentials
+// or tunnels, whose values are never stored in plan or state. Providers
+// implement EphemeralResource for a config struct T and a result struct R,
+// and optionally Renewer and Closer. NewServer takes care of decoding the
+// configuration, encoding the result, and carrying a value of type P between
+// Open, Renew, and Close in the ephemeral resource's private data, using a
+// tfprivate.Codec. T, R, and P may be the same type, and T and R use `tfsdk`
+// struct tags as understood by the asgotypes package.
+package tfephemeral
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfprivate"
+)
+
+// EphemeralResource opens a single kind of ephemeral resource.
+type EphemeralResource[T, R, P any] interface {
+	// Open opens the ephemeral resource described by `config`. Any
+	// attribute that the result leaves null will be populated from
+	// `config`, so R only needs to include the computed attributes.
+	Open(ctx context.Context, config T) (Opened[R, P], error)
+}
+
+// Opened is the result of opening an ephemeral resource.
+type Opened[R, P any] struct {
+	// Result is the ephemeral resource's value.
+	Result R
+
+	// Private is passed to Renew and Close. It's encoded with the
+	// Server's tfprivate.Codec, so it must be encodable as JSON.
+	Private P
+
+	// RenewAt is when Terraform should call Renew, if the ephemeral
+	// resource is still in use. Renew is never called if it's zero.
+	RenewAt time.Time
+}
+
+// OpenFunc is a function that implements EphemeralResource.
+type OpenFunc[T, R, P any] func(ctx context.Context, config T) (Opened[R, P], error)
+
+// Open calls `f`.
+func (f OpenFunc[T, R, P]) Open(ctx context.Context, config T) (Opened[R, P], error) {
+	return f(ctx, config)
+}
+
+// Renewer is an optional interface for EphemeralResources that need to be
+// renewed periodically, like leases.
+type Renewer[P any] interface {
+	// Renew renews the ephemeral resource described by `private`, and
+	// returns the private data and renewal time to use from now on.
+	Renew(ctx context.Context, private P) (Renewed[P], error)
+}
+
+// Renewed is the result of renewing an ephemeral resource.
+type Renewed[P any] struct {
+	// Private replaces the private data passed to later calls to Renew
+	// and Close.
+	Private P
+
+	// RenewAt is when Terraform should call Renew again. Renew isn't
+	// called again if it's zero.
+	RenewAt time.Time
+}
+
+// Closer is an optional interface for EphemeralResources that need to be
+// cleaned up once Terraform is done with them, like tunnels.
+type Closer[P any] interface {
+	// Close closes the ephemeral resource described by `private`.
+	Close(ctx context.Context, private P) error
+}
+
+// PrivateCodec is an optional interface for EphemeralResources whose private
+// data needs a tfprivate.Codec other than the zero value, for example to
+// encrypt it.
+type PrivateCodec[P any] interface {
+	PrivateCodec() tfprivate.Codec[P]
+}
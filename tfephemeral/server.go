@@ -0,0 +1,222 @@
+package tfephemeral
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfprivate"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ tfprotov5.EphemeralResourceServer = &Server[struct{}, struct{}, struct{}]{}
+
+// Server is a tfprotov5.EphemeralResourceServer for a single ephemeral
+// resource type, backed by an EphemeralResource.
+type Server[T, R, P any] struct {
+	schema   *tfprotov5.Schema
+	typ      tftypes.Type
+	resource EphemeralResource[T, R, P]
+	codec    tfprivate.Codec[P]
+}
+
+// NewServer returns a Server for the ephemeral resource described by
+// `schema`, backed by `resource`.
+func NewServer[T, R, P any](schema *tfprotov5.Schema, resource EphemeralResource[T, R, P]) *Server[T, R, P] {
+	var codec tfprivate.Codec[P]
+	if c, ok := resource.(PrivateCodec[P]); ok {
+		codec = c.PrivateCodec()
+	}
+	return &Server[T, R, P]{
+		schema:   schema,
+		typ:      schema.ValueType(),
+		resource: resource,
+		codec:    codec,
+	}
+}
+
+// Schema returns the ephemeral resource's schema.
+func (s *Server[T, R, P]) Schema() *tfprotov5.Schema {
+	return s.schema
+}
+
+// Validators returns the EphemeralResource's Validators, if it implements
+// tfvalidate.Validated.
+func (s *Server[T, R, P]) Validators() *tfvalidate.Registry {
+	if v, ok := s.resource.(tfvalidate.Validated); ok {
+		return v.Validators()
+	}
+	return nil
+}
+
+// ValidateEphemeralResourceConfig checks that the config can be decoded
+// into T.
+func (s *Server[T, R, P]) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	resp := &tfprotov5.ValidateEphemeralResourceConfigResponse{}
+	config, err := s.unmarshal(req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading configuration", err))
+		return resp, nil
+	}
+	var decoded T
+	d := asgotypes.Decoder{AllowUnknown: true}
+	if err := d.DecodeContext(ctx, config, &decoded); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Invalid configuration", err))
+	}
+	return resp, nil
+}
+
+// OpenEphemeralResource decodes the config and calls EphemeralResource.Open,
+// encoding the result and the private data. If the config isn't wholly
+// known yet, Open isn't called, and the computed attributes the config
+// doesn't set are returned as unknown.
+func (s *Server[T, R, P]) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	resp := &tfprotov5.OpenEphemeralResourceResponse{}
+	config, err := s.unmarshal(req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading configuration", err))
+		return resp, nil
+	}
+	var result tftypes.Value
+	if !config.IsFullyKnown() {
+		result, err = s.withComputedUnknown(config)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error opening ephemeral resource", err))
+			return resp, nil
+		}
+	} else {
+		var decoded T
+		if err := asgotypes.DecodeContext(ctx, config, &decoded); err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Invalid configuration", err))
+			return resp, nil
+		}
+		opened, err := s.resource.Open(ctx, decoded)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error opening ephemeral resource", err))
+			return resp, nil
+		}
+		result, err = asgotypes.EncodeContext(ctx, s.typ, opened.Result)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error opening ephemeral resource", err))
+			return resp, nil
+		}
+		result, err = mergeConfig(s.typ, result, config)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error opening ephemeral resource", err))
+			return resp, nil
+		}
+		resp.Private, err = s.codec.Encode(opened.Private)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error opening ephemeral resource", err))
+			return resp, nil
+		}
+		resp.RenewAt = opened.RenewAt
+	}
+	dv, err := tfprotov5.NewDynamicValue(s.typ, result)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error opening ephemeral resource", err))
+		return resp, nil
+	}
+	resp.Result = &dv
+	return resp, nil
+}
+
+// RenewEphemeralResource decodes the private data and calls Renewer.Renew,
+// if the EphemeralResource implements Renewer. Otherwise it does nothing.
+func (s *Server[T, R, P]) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	resp := &tfprotov5.RenewEphemeralResourceResponse{
+		Private: req.Private,
+	}
+	renewer, ok := s.resource.(Renewer[P])
+	if !ok {
+		return resp, nil
+	}
+	private, err := s.codec.Decode(req.Private)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading private data", err))
+		return resp, nil
+	}
+	renewed, err := renewer.Renew(ctx, private)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error renewing ephemeral resource", err))
+		return resp, nil
+	}
+	resp.Private, err = s.codec.Encode(renewed.Private)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error renewing ephemeral resource", err))
+		return resp, nil
+	}
+	resp.RenewAt = renewed.RenewAt
+	return resp, nil
+}
+
+// CloseEphemeralResource decodes the private data and calls Closer.Close, if
+// the EphemeralResource implements Closer. Otherwise it does nothing.
+func (s *Server[T, R, P]) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	resp := &tfprotov5.CloseEphemeralResourceResponse{}
+	closer, ok := s.resource.(Closer[P])
+	if !ok {
+		return resp, nil
+	}
+	private, err := s.codec.Decode(req.Private)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading private data", err))
+		return resp, nil
+	}
+	if err := closer.Close(ctx, private); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error closing ephemeral resource", err))
+	}
+	return resp, nil
+}
+
+func (s *Server[T, R, P]) unmarshal(dv *tfprotov5.DynamicValue) (tftypes.Value, error) {
+	if dv == nil {
+		return tftypes.NewValue(s.typ, nil), nil
+	}
+	return dv.Unmarshal(s.typ)
+}
+
+// withComputedUnknown returns `config` with every computed attribute that
+// config leaves null set to unknown.
+func (s *Server[T, R, P]) withComputedUnknown(config tftypes.Value) (tftypes.Value, error) {
+	if s.schema.Block == nil || config.IsNull() {
+		return config, nil
+	}
+	attrs := map[string]tftypes.Value{}
+	if err := config.As(&attrs); err != nil {
+		return tftypes.Value{}, err
+	}
+	for _, attr := range s.schema.Block.Attributes {
+		if v, ok := attrs[attr.Name]; ok && attr.Computed && v.IsNull() {
+			attrs[attr.Name] = tftypes.NewValue(attr.ValueType(), tftypes.UnknownValue)
+		}
+	}
+	return tftypes.NewValue(s.typ, attrs), nil
+}
+
+// mergeConfig returns `result` with any null attributes replaced by their
+// values in `config`.
+func mergeConfig(typ tftypes.Type, result, config tftypes.Value) (tftypes.Value, error) {
+	if config.IsNull() {
+		return result, nil
+	}
+	if result.IsNull() {
+		return config, nil
+	}
+	resultAttrs := map[string]tftypes.Value{}
+	if err := result.As(&resultAttrs); err != nil {
+		return tftypes.Value{}, err
+	}
+	configAttrs := map[string]tftypes.Value{}
+	if err := config.As(&configAttrs); err != nil {
+		return tftypes.Value{}, err
+	}
+	for k, v := range configAttrs {
+		if existing, ok := resultAttrs[k]; !ok || existing.IsNull() {
+			resultAttrs[k] = v
+		}
+	}
+	return tftypes.NewValue(typ, resultAttrs), nil
+}
@@ -0,0 +1,191 @@
+package tfephemeral
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type tokenConfig struct {
+	Role string `tfsdk:"role"`
+}
+
+type tokenResult struct {
+	Token *string `tfsdk:"token"`
+}
+
+type lease struct {
+	ID      string `json:"id"`
+	Renewed int    `json:"renewed"`
+}
+
+var tokenSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "role", Type: tftypes.String, Required: true},
+			{Name: "token", Type: tftypes.String, Computed: true, Sensitive: true},
+		},
+	},
+}
+
+var tokenType = tokenSchema.ValueType()
+
+func tokenValue(t *testing.T, role, token interface{}) *tfprotov5.DynamicValue {
+	t.Helper()
+	dv, err := tfprotov5.NewDynamicValue(tokenType, tftypes.NewValue(tokenType, map[string]tftypes.Value{
+		"role":  tftypes.NewValue(tftypes.String, role),
+		"token": tftypes.NewValue(tftypes.String, token),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+var renewAt = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type tokenResource struct {
+	opened  int
+	renewed []lease
+	closed  []lease
+}
+
+func (r *tokenResource) Open(ctx context.Context, config tokenConfig) (Opened[tokenResult, lease], error) {
+	if config.Role == "" {
+		return Opened[tokenResult, lease]{}, errors.New("role cannot be empty")
+	}
+	r.opened++
+	token := "token-for-" + config.Role
+	return Opened[tokenResult, lease]{
+		Result:  tokenResult{Token: &token},
+		Private: lease{ID: "lease-1"},
+		RenewAt: renewAt,
+	}, nil
+}
+
+func (r *tokenResource) Renew(ctx context.Context, private lease) (Renewed[lease], error) {
+	r.renewed = append(r.renewed, private)
+	private.Renewed++
+	return Renewed[lease]{Private: private, RenewAt: renewAt.Add(time.Hour)}, nil
+}
+
+func (r *tokenResource) Close(ctx context.Context, private lease) error {
+	r.closed = append(r.closed, private)
+	return nil
+}
+
+func TestServerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	res := &tokenResource{}
+	srv := NewServer[tokenConfig, tokenResult, lease](tokenSchema, res)
+
+	opened, err := srv.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		Config: tokenValue(t, "admin", nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", opened.Diagnostics)
+	}
+	if diff := cmp.Diff(tokenValue(t, "admin", "token-for-admin"), opened.Result); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if !opened.RenewAt.Equal(renewAt) {
+		t.Errorf("expected renewal at %s, got %s", renewAt, opened.RenewAt)
+	}
+
+	renewed, err := srv.RenewEphemeralResource(ctx, &tfprotov5.RenewEphemeralResourceRequest{
+		Private: opened.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(renewed.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", renewed.Diagnostics)
+	}
+	if !renewed.RenewAt.Equal(renewAt.Add(time.Hour)) {
+		t.Errorf("expected renewal at %s, got %s", renewAt.Add(time.Hour), renewed.RenewAt)
+	}
+
+	closed, err := srv.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{
+		Private: renewed.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(closed.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", closed.Diagnostics)
+	}
+	if diff := cmp.Diff([]lease{{ID: "lease-1"}}, res.renewed); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]lease{{ID: "lease-1", Renewed: 1}}, res.closed); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestServerOpenUnknown(t *testing.T) {
+	res := &tokenResource{}
+	srv := NewServer[tokenConfig, tokenResult, lease](tokenSchema, res)
+	opened, err := srv.OpenEphemeralResource(context.Background(), &tfprotov5.OpenEphemeralResourceRequest{
+		Config: tokenValue(t, tftypes.UnknownValue, nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", opened.Diagnostics)
+	}
+	if diff := cmp.Diff(tokenValue(t, tftypes.UnknownValue, tftypes.UnknownValue), opened.Result); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if res.opened != 0 {
+		t.Errorf("expected Open not to be called, got %d calls", res.opened)
+	}
+}
+
+func TestServerOpenError(t *testing.T) {
+	srv := NewServer[tokenConfig, tokenResult, lease](tokenSchema, &tokenResource{})
+	opened, err := srv.OpenEphemeralResource(context.Background(), &tfprotov5.OpenEphemeralResourceRequest{
+		Config: tokenValue(t, "", nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*tfprotov5.Diagnostic{{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  "Error opening ephemeral resource",
+		Detail:   "role cannot be empty",
+	}}
+	if diff := cmp.Diff(expected, opened.Diagnostics); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestServerWithoutRenewOrClose(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer[tokenConfig, tokenResult, struct{}](tokenSchema, OpenFunc[tokenConfig, tokenResult, struct{}](func(ctx context.Context, config tokenConfig) (Opened[tokenResult, struct{}], error) {
+		token := "static"
+		return Opened[tokenResult, struct{}]{Result: tokenResult{Token: &token}}, nil
+	}))
+	renewed, err := srv.RenewEphemeralResource(ctx, &tfprotov5.RenewEphemeralResourceRequest{Private: []byte("private")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(renewed.Diagnostics) != 0 || string(renewed.Private) != "private" {
+		t.Errorf("expected renewal to keep the private data, got %q and diagnostics %+v", renewed.Private, renewed.Diagnostics)
+	}
+	closed, err := srv.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{Private: []byte("private")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(closed.Diagnostics) != 0 {
+		t.Errorf("unexpected diagnostics: %+v", closed.Diagnostics)
+	}
+}
@@ -0,0 +1,137 @@
+// Package tfequal provides a registry of semantic equality functions, used to
+// stop cosmetic differences between values from producing perpetual diffs.
+//
+// Many APIs return values in a canonical form that differs from the one
+// practitioners write in their configuration: JSON documents with their keys
+// reordered, IDs in a different case, or CIDR blocks with their host bits
+// cleared. If a provider stores the API's form in state, Terraform sees a
+// difference from the configuration on every plan. Registry.Normalize keeps
+// the prior form of any value that's semantically equal to the new one.
+package tfequal
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Func reports whether two values of the same type are semantically equal.
+// It is only called with known, non-null values.
+type Func func(a, b tftypes.Value) (bool, error)
+
+// JSON is a Func for strings holding JSON documents, which are equal if they
+// decode to the same value. Numbers are compared exactly, so 1.0 and 1 are
+// equal, but integers too large for a float64 aren't rounded. Strings that
+// aren't valid JSON are never equal.
+func JSON() Func {
+	return stringFunc(func(a, b string) bool {
+		av, err := decodeJSON(a)
+		if err != nil {
+			return false
+		}
+		bv, err := decodeJSON(b)
+		if err != nil {
+			return false
+		}
+		return jsonEqual(av, bv)
+	})
+}
+
+// decodeJSON decodes the JSON document `s`, with numbers as json.Numbers.
+func decodeJSON(s string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after JSON document")
+	}
+	return v, nil
+}
+
+// jsonEqual reports whether the values `a` and `b` returned by decodeJSON are
+// equal.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ar, aok := new(big.Rat).SetString(a.String())
+		br, bok := new(big.Rat).SetString(b.String())
+		return aok && bok && ar.Cmp(br) == 0
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !jsonEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// CaseInsensitive is a Func for strings that are equal regardless of case.
+func CaseInsensitive() Func {
+	return stringFunc(strings.EqualFold)
+}
+
+// CIDR is a Func for strings holding IPv4 or IPv6 CIDR blocks, which are
+// equal if they describe the same network, so "10.0.0.1/8" is equal to
+// "10.0.0.0/8". Strings that aren't valid CIDR blocks are never equal.
+func CIDR() Func {
+	return stringFunc(func(a, b string) bool {
+		ap, err := netip.ParsePrefix(a)
+		if err != nil {
+			return false
+		}
+		bp, err := netip.ParsePrefix(b)
+		if err != nil {
+			return false
+		}
+		return ap.Masked() == bp.Masked()
+	})
+}
+
+func stringFunc(f func(a, b string) bool) Func {
+	return func(a, b tftypes.Value) (bool, error) {
+		if !a.Type().Is(tftypes.String) || !b.Type().Is(tftypes.String) {
+			return false, nil
+		}
+		if !a.IsKnown() || a.IsNull() || !b.IsKnown() || b.IsNull() {
+			return false, nil
+		}
+		var as, bs string
+		if err := a.As(&as); err != nil {
+			return false, err
+		}
+		if err := b.As(&bs); err != nil {
+			return false, err
+		}
+		return f(as, bs), nil
+	}
+}
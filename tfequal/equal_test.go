@@ -0,0 +1,48 @@
+package tfequal
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestFuncs(t *testing.T) {
+	type testCase struct {
+		f        Func
+		a        tftypes.Value
+		b        tftypes.Value
+		expected bool
+	}
+	str := func(s string) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, s)
+	}
+	cases := map[string]testCase{
+		"json-equal":            {f: JSON(), a: str(`{"a": 1, "b": [true]}`), b: str(`{"b":[true],"a":1}`), expected: true},
+		"json-different":        {f: JSON(), a: str(`{"a": 1}`), b: str(`{"a": 2}`), expected: false},
+		"json-invalid":          {f: JSON(), a: str(`{"a": 1`), b: str(`{"a": 1`), expected: false},
+		"json-large-integers":   {f: JSON(), a: str(`{"id":9007199254740993}`), b: str(`{"id":9007199254740992}`), expected: false},
+		"json-number-forms":     {f: JSON(), a: str(`[1, 1.5, 100]`), b: str(`[1.0, 15e-1, 1E2]`), expected: true},
+		"json-trailing-data":    {f: JSON(), a: str(`{"a": 1} {}`), b: str(`{"a": 1} {}`), expected: false},
+		"case-equal":            {f: CaseInsensitive(), a: str("Foo-Bar"), b: str("foo-bar"), expected: true},
+		"case-different":        {f: CaseInsensitive(), a: str("foo"), b: str("bar"), expected: false},
+		"cidr-host-bits":        {f: CIDR(), a: str("10.0.0.1/8"), b: str("10.0.0.0/8"), expected: true},
+		"cidr-ipv6":             {f: CIDR(), a: str("2001:0db8:0000::/32"), b: str("2001:db8::/32"), expected: true},
+		"cidr-different-prefix": {f: CIDR(), a: str("10.0.0.0/8"), b: str("10.0.0.0/16"), expected: false},
+		"cidr-invalid":          {f: CIDR(), a: str("10.0.0.0"), b: str("10.0.0.0"), expected: false},
+		"not-string":            {f: CaseInsensitive(), a: tftypes.NewValue(tftypes.Number, big.NewFloat(1)), b: tftypes.NewValue(tftypes.Number, big.NewFloat(1)), expected: false},
+		"null":                  {f: CaseInsensitive(), a: tftypes.NewValue(tftypes.String, nil), b: str("foo"), expected: false},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			got, err := test.f(test.a, test.b)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
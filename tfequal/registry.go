@@ -0,0 +1,121 @@
+package tfequal
+
+import (
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Registry associates semantic equality Funcs with attribute paths and
+// value types. The zero value is an empty Registry ready to use.
+//
+// Element keys in paths are ignored when matching, so a Func registered for
+// an attribute of a nested block applies to that attribute in every element
+// of the block. Funcs registered for a path take precedence over those
+// registered for a type.
+type Registry struct {
+	paths attrpath.Registry[Func]
+	types []typeEntry
+}
+
+type typeEntry struct {
+	typ tftypes.Type
+	f   Func
+}
+
+// AddPath registers `f` for the attribute at `path`, replacing any Func
+// already registered for it.
+func (r *Registry) AddPath(path *tftypes.AttributePath, f Func) {
+	r.paths.Set(path, f)
+}
+
+// AddType registers `f` for all values of type `typ`, replacing any Func
+// already registered for it.
+func (r *Registry) AddType(typ tftypes.Type, f Func) {
+	for i, entry := range r.types {
+		if entry.typ.Equal(typ) {
+			r.types[i].f = f
+			return
+		}
+	}
+	r.types = append(r.types, typeEntry{typ: typ, f: f})
+}
+
+// Func returns the Func for the value of type `typ` at `path`, or nil if
+// there isn't one.
+func (r *Registry) Func(path *tftypes.AttributePath, typ tftypes.Type) Func {
+	if r == nil {
+		return nil
+	}
+	steps := path.Steps()
+	if len(steps) > 0 {
+		if _, ok := steps[len(steps)-1].(tftypes.AttributeName); ok {
+			if fs := r.paths.Get(path); len(fs) > 0 {
+				return fs[0]
+			}
+		}
+	}
+	for _, entry := range r.types {
+		if entry.typ.Equal(typ) {
+			return entry.f
+		}
+	}
+	return nil
+}
+
+// Equal reports whether `a` and `b`, the values at `path`, are equal or
+// semantically equal.
+func (r *Registry) Equal(path *tftypes.AttributePath, a, b tftypes.Value) (bool, error) {
+	if a.Equal(b) {
+		return true, nil
+	}
+	if !a.IsKnown() || a.IsNull() || !b.IsKnown() || b.IsNull() {
+		return false, nil
+	}
+	if !a.Type().Equal(b.Type()) {
+		return false, nil
+	}
+	f := r.Func(path, a.Type())
+	if f == nil {
+		return false, nil
+	}
+	return f(a, b)
+}
+
+// Normalize returns `val` with every value that is semantically equal to the
+// value at the same path in `prior` replaced with the prior value.
+//
+// Providers should call it with the prior state and the state returned from
+// their API when reading resources, so that the form of each value stored in
+// state stays the same as the one in the configuration.
+func (r *Registry) Normalize(prior, val tftypes.Value) (tftypes.Value, error) {
+	if r == nil || prior.IsNull() || !prior.IsKnown() {
+		return val, nil
+	}
+	return tftypes.Transform(val, func(path *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if r.Func(path, v.Type()) == nil {
+			return v, nil
+		}
+		p, ok := valueAt(prior, path)
+		if !ok {
+			return v, nil
+		}
+		equal, err := r.Equal(path, p, v)
+		if err != nil {
+			return v, err
+		}
+		if equal {
+			return p, nil
+		}
+		return v, nil
+	})
+}
+
+// valueAt returns the value at `path` in `root`, if there is one.
+func valueAt(root tftypes.Value, path *tftypes.AttributePath) (tftypes.Value, bool) {
+	v, _, err := tftypes.WalkAttributePath(root, path)
+	if err != nil {
+		return tftypes.Value{}, false
+	}
+	val, ok := v.(tftypes.Value)
+	return val, ok
+}
@@ -0,0 +1,100 @@
+package tfequal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	testRuleType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"cidr": tftypes.String,
+	}}
+	testType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":     tftypes.String,
+		"policy": tftypes.String,
+		"rule":   tftypes.List{ElementType: testRuleType},
+	}}
+)
+
+func testValue(id, policy interface{}, cidrs ...string) tftypes.Value {
+	var rules []tftypes.Value
+	for _, cidr := range cidrs {
+		rules = append(rules, tftypes.NewValue(testRuleType, map[string]tftypes.Value{
+			"cidr": tftypes.NewValue(tftypes.String, cidr),
+		}))
+	}
+	return tftypes.NewValue(testType, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, id),
+		"policy": tftypes.NewValue(tftypes.String, policy),
+		"rule":   tftypes.NewValue(tftypes.List{ElementType: testRuleType}, rules),
+	})
+}
+
+func testRegistry() *Registry {
+	r := &Registry{}
+	r.AddType(tftypes.String, CaseInsensitive())
+	r.AddPath(tftypes.NewAttributePath().WithAttributeName("policy"), JSON())
+	r.AddPath(tftypes.NewAttributePath().WithAttributeName("rule").WithAttributeName("cidr"), CIDR())
+	return r
+}
+
+func TestRegistryNormalize(t *testing.T) {
+	type testCase struct {
+		prior    tftypes.Value
+		val      tftypes.Value
+		expected tftypes.Value
+	}
+	cases := map[string]testCase{
+		"equal": {
+			prior:    testValue("ABC", `{"a": 1, "b": 2}`, "10.0.0.0/8"),
+			val:      testValue("abc", `{"b":2,"a":1}`, "10.0.0.1/8"),
+			expected: testValue("ABC", `{"a": 1, "b": 2}`, "10.0.0.0/8"),
+		},
+		"different": {
+			prior:    testValue("ABC", `{"a": 1}`, "10.0.0.0/8"),
+			val:      testValue("abd", `{"a":2}`, "10.0.0.0/16"),
+			expected: testValue("abd", `{"a":2}`, "10.0.0.0/16"),
+		},
+		"path-takes-precedence": {
+			prior:    testValue("ABC", `{"A": 1}`),
+			val:      testValue("ABC", `{"a": 1}`),
+			expected: testValue("ABC", `{"a": 1}`),
+		},
+		"new-element": {
+			prior:    testValue("ABC", nil, "10.0.0.0/8"),
+			val:      testValue("ABC", nil, "10.0.0.1/8", "192.168.0.1/24"),
+			expected: testValue("ABC", nil, "10.0.0.0/8", "192.168.0.1/24"),
+		},
+		"null-prior": {
+			prior:    tftypes.NewValue(testType, nil),
+			val:      testValue("abc", nil),
+			expected: testValue("abc", nil),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			got, err := testRegistry().Normalize(test.prior, test.val)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRegistryNil(t *testing.T) {
+	var r *Registry
+	val := testValue("abc", nil)
+	got, err := r.Normalize(testValue("ABC", nil), val)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(val, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
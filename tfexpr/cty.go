@@ -0,0 +1,266 @@
+package tfexpr
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ToCtyType returns the cty.Type equivalent to `typ`.
+func ToCtyType(typ tftypes.Type) (cty.Type, error) {
+	switch {
+	case typ == nil:
+		return cty.NilType, fmt.Errorf("type is nil")
+	case typ.Is(tftypes.DynamicPseudoType):
+		return cty.DynamicPseudoType, nil
+	case typ.Is(tftypes.String):
+		return cty.String, nil
+	case typ.Is(tftypes.Number):
+		return cty.Number, nil
+	case typ.Is(tftypes.Bool):
+		return cty.Bool, nil
+	}
+	switch t := typ.(type) {
+	case tftypes.List:
+		elem, err := ToCtyType(t.ElementType)
+		return cty.List(elem), err
+	case tftypes.Set:
+		elem, err := ToCtyType(t.ElementType)
+		return cty.Set(elem), err
+	case tftypes.Map:
+		elem, err := ToCtyType(t.ElementType)
+		return cty.Map(elem), err
+	case tftypes.Tuple:
+		elems := make([]cty.Type, 0, len(t.ElementTypes))
+		for _, e := range t.ElementTypes {
+			elem, err := ToCtyType(e)
+			if err != nil {
+				return cty.NilType, err
+			}
+			elems = append(elems, elem)
+		}
+		return cty.Tuple(elems), nil
+	case tftypes.Object:
+		attrs := make(map[string]cty.Type, len(t.AttributeTypes))
+		for name, a := range t.AttributeTypes {
+			attr, err := ToCtyType(a)
+			if err != nil {
+				return cty.NilType, err
+			}
+			attrs[name] = attr
+		}
+		if len(t.OptionalAttributes) > 0 {
+			optional := make([]string, 0, len(t.OptionalAttributes))
+			for name := range t.OptionalAttributes {
+				optional = append(optional, name)
+			}
+			sort.Strings(optional)
+			return cty.ObjectWithOptionalAttrs(attrs, optional), nil
+		}
+		return cty.Object(attrs), nil
+	}
+	return cty.NilType, fmt.Errorf("unsupported type %s", typ)
+}
+
+// FromCtyType returns the tftypes.Type equivalent to `typ`.
+func FromCtyType(typ cty.Type) (tftypes.Type, error) {
+	switch {
+	case typ == cty.DynamicPseudoType:
+		return tftypes.DynamicPseudoType, nil
+	case typ == cty.String:
+		return tftypes.String, nil
+	case typ == cty.Number:
+		return tftypes.Number, nil
+	case typ == cty.Bool:
+		return tftypes.Bool, nil
+	case typ.IsListType():
+		elem, err := FromCtyType(typ.ElementType())
+		return tftypes.List{ElementType: elem}, err
+	case typ.IsSetType():
+		elem, err := FromCtyType(typ.ElementType())
+		return tftypes.Set{ElementType: elem}, err
+	case typ.IsMapType():
+		elem, err := FromCtyType(typ.ElementType())
+		return tftypes.Map{ElementType: elem}, err
+	case typ.IsTupleType():
+		elems := make([]tftypes.Type, 0, typ.Length())
+		for _, e := range typ.TupleElementTypes() {
+			elem, err := FromCtyType(e)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return tftypes.Tuple{ElementTypes: elems}, nil
+	case typ.IsObjectType():
+		obj := tftypes.Object{AttributeTypes: make(map[string]tftypes.Type, len(typ.AttributeTypes()))}
+		for name, a := range typ.AttributeTypes() {
+			attr, err := FromCtyType(a)
+			if err != nil {
+				return nil, err
+			}
+			obj.AttributeTypes[name] = attr
+			if typ.AttributeOptional(name) {
+				if obj.OptionalAttributes == nil {
+					obj.OptionalAttributes = map[string]struct{}{}
+				}
+				obj.OptionalAttributes[name] = struct{}{}
+			}
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ.FriendlyName())
+}
+
+// ToCty returns the cty.Value equivalent to `val`. Object values lose the
+// optional attributes of their types, as cty only has optional attributes
+// in type constraints.
+func ToCty(val tftypes.Value) (cty.Value, error) {
+	return toCty(tftypes.NewAttributePath(), val)
+}
+
+func toCty(path *tftypes.AttributePath, val tftypes.Value) (cty.Value, error) {
+	typ, err := ToCtyType(val.Type())
+	if err != nil {
+		return cty.NilVal, path.NewError(err)
+	}
+	if !val.IsKnown() {
+		return cty.UnknownVal(typ), nil
+	}
+	if val.IsNull() {
+		return cty.NullVal(typ), nil
+	}
+	switch {
+	case typ == cty.String:
+		var s string
+		if err := val.As(&s); err != nil {
+			return cty.NilVal, path.NewError(err)
+		}
+		return cty.StringVal(s), nil
+	case typ == cty.Number:
+		f := new(big.Float)
+		if err := val.As(&f); err != nil {
+			return cty.NilVal, path.NewError(err)
+		}
+		return cty.NumberVal(f), nil
+	case typ == cty.Bool:
+		var b bool
+		if err := val.As(&b); err != nil {
+			return cty.NilVal, path.NewError(err)
+		}
+		return cty.BoolVal(b), nil
+	case typ.IsListType(), typ.IsSetType(), typ.IsTupleType():
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return cty.NilVal, path.NewError(err)
+		}
+		vals := make([]cty.Value, 0, len(elems))
+		for i, elem := range elems {
+			elemPath := path.WithElementKeyInt(i)
+			if typ.IsSetType() {
+				elemPath = path.WithElementKeyValue(elem)
+			}
+			v, err := toCty(elemPath, elem)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals = append(vals, v)
+		}
+		switch {
+		case typ.IsTupleType():
+			return cty.TupleVal(vals), nil
+		case len(vals) == 0 && typ.IsListType():
+			return cty.ListValEmpty(typ.ElementType()), nil
+		case len(vals) == 0:
+			return cty.SetValEmpty(typ.ElementType()), nil
+		}
+		if typ.IsListType() {
+			return cty.ListVal(vals), nil
+		}
+		return cty.SetVal(vals), nil
+	case typ.IsMapType(), typ.IsObjectType():
+		var elems map[string]tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return cty.NilVal, path.NewError(err)
+		}
+		vals := make(map[string]cty.Value, len(elems))
+		for k, elem := range elems {
+			elemPath := path.WithElementKeyString(k)
+			if typ.IsObjectType() {
+				elemPath = path.WithAttributeName(k)
+			}
+			v, err := toCty(elemPath, elem)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals[k] = v
+		}
+		if typ.IsObjectType() {
+			return cty.ObjectVal(vals), nil
+		}
+		if len(vals) == 0 {
+			return cty.MapValEmpty(typ.ElementType()), nil
+		}
+		return cty.MapVal(vals), nil
+	}
+	return cty.NilVal, path.NewErrorf("unsupported type %s", val.Type())
+}
+
+// FromCty returns the tftypes.Value equivalent to `val`. Marks are
+// discarded.
+func FromCty(val cty.Value) (tftypes.Value, error) {
+	val, _ = val.UnmarkDeep()
+	return fromCty(tftypes.NewAttributePath(), val)
+}
+
+func fromCty(path *tftypes.AttributePath, val cty.Value) (tftypes.Value, error) {
+	ty := val.Type()
+	typ, err := FromCtyType(ty)
+	if err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	if !val.IsKnown() {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	if val.IsNull() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	switch {
+	case ty == cty.String:
+		return tftypes.NewValue(typ, val.AsString()), nil
+	case ty == cty.Number:
+		return tftypes.NewValue(typ, val.AsBigFloat()), nil
+	case ty == cty.Bool:
+		return tftypes.NewValue(typ, val.True()), nil
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		elems := make([]tftypes.Value, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			elem, err := fromCty(path.WithElementKeyInt(len(elems)), v)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case ty.IsMapType(), ty.IsObjectType():
+		elems := make(map[string]tftypes.Value, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			elemPath := path.WithElementKeyString(k.AsString())
+			if ty.IsObjectType() {
+				elemPath = path.WithAttributeName(k.AsString())
+			}
+			elem, err := fromCty(elemPath, v)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k.AsString()] = elem
+		}
+		return tftypes.NewValue(typ, elems), nil
+	}
+	return tftypes.Value{}, path.NewErrorf("unsupported type %s", ty.FriendlyName())
+}
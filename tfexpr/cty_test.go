@@ -0,0 +1,120 @@
+package tfexpr
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCtyRoundTrip(t *testing.T) {
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name": tftypes.String,
+		"port": tftypes.Number,
+	}}
+	type testCase struct {
+		val      tftypes.Value
+		expected cty.Value
+	}
+	cases := map[string]testCase{
+		"string": {
+			val:      str("a"),
+			expected: cty.StringVal("a"),
+		},
+		"number": {
+			val:      tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+			expected: cty.NumberFloatVal(1.5),
+		},
+		"null": {
+			val:      tftypes.NewValue(tftypes.Bool, nil),
+			expected: cty.NullVal(cty.Bool),
+		},
+		"unknown": {
+			val:      tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+			expected: cty.UnknownVal(cty.List(cty.String)),
+		},
+		"dynamic-null": {
+			val:      tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+			expected: cty.NullVal(cty.DynamicPseudoType),
+		},
+		"empty-set": {
+			val:      tftypes.NewValue(tftypes.Set{ElementType: tftypes.Number}, []tftypes.Value{}),
+			expected: cty.SetValEmpty(cty.Number),
+		},
+		"map": {
+			val: tftypes.NewValue(tftypes.Map{ElementType: tftypes.Bool}, map[string]tftypes.Value{
+				"a": boolean(true),
+				"b": tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue),
+			}),
+			expected: cty.MapVal(map[string]cty.Value{
+				"a": cty.True,
+				"b": cty.UnknownVal(cty.Bool),
+			}),
+		},
+		"object": {
+			val: tftypes.NewValue(objType, map[string]tftypes.Value{
+				"name": str("a"),
+				"port": tftypes.NewValue(tftypes.Number, nil),
+			}),
+			expected: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("a"),
+				"port": cty.NullVal(cty.Number),
+			}),
+		},
+		"tuple": {
+			val:      strs("a", "b"),
+			expected: cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := ToCty(tc.val)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, got)
+			}
+			back, err := FromCty(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.val, back); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCtyTypeRoundTrip(t *testing.T) {
+	typ := tftypes.List{ElementType: tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"name": tftypes.String,
+			"tags": tftypes.Map{ElementType: tftypes.DynamicPseudoType},
+			"port": tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.Bool}},
+		},
+		OptionalAttributes: map[string]struct{}{"port": {}},
+	}}
+	ctyType, err := ToCtyType(typ)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name": cty.String,
+		"tags": cty.Map(cty.DynamicPseudoType),
+		"port": cty.Tuple([]cty.Type{cty.Number, cty.Bool}),
+	}, []string{"port"}))
+	if !ctyType.Equals(expected) {
+		t.Errorf("expected %#v, got %#v", expected, ctyType)
+	}
+	back, err := FromCtyType(ctyType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !back.Equal(typ) {
+		t.Errorf("expected %s, got %s", typ, back)
+	}
+}
@@ -0,0 +1,284 @@
+// Package tfexpr evaluates HCL native syntax expressions, like
+// `length(tags) > 0 && tags.env == "prod"`, against tftypes.Values, so data
+// sources can offer practitioners configurable filters and transformations.
+//
+// Expressions are parsed and evaluated by github.com/hashicorp/hcl/v2, with
+// values converted to and from github.com/zclconf/go-cty, so they behave
+// like expressions in Terraform configuration. ToCty and FromCty convert
+// between the two representations of values, for callers that want to use
+// HCL or cty directly.
+//
+// Unknown values propagate like they do in Terraform: an operation with an
+// unknown operand has an unknown result.
+package tfexpr
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+var (
+	tfString  tftypes.Type = tftypes.String
+	tfNumber  tftypes.Type = tftypes.Number
+	tfBool    tftypes.Type = tftypes.Bool
+	tfDynamic tftypes.Type = tftypes.DynamicPseudoType
+)
+
+// Function is a function expressions can call. Functions are only called
+// with known arguments; a call with any unknown arguments has an unknown
+// result.
+type Function func(args []tftypes.Value) (tftypes.Value, error)
+
+// Scope holds the variables and functions available to an expression.
+type Scope struct {
+	Variables map[string]tftypes.Value
+
+	// Functions are available in addition to Builtins, and override
+	// builtin functions with the same name.
+	Functions map[string]Function
+}
+
+// NewScope returns a Scope with the variables `vars`, converted from
+// GoPrimitives. A GoPrimitive's Type is used if it's set, and otherwise its
+// type is inferred from its Value: slices become tuples, and maps objects.
+func NewScope(vars map[string]asgotypes.GoPrimitive) (*Scope, error) {
+	scope := &Scope{Variables: make(map[string]tftypes.Value, len(vars))}
+	for name, p := range vars {
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		var val tftypes.Value
+		var err error
+		if p.Type != nil {
+			val, err = asgotypes.Encode(p.Type, p)
+		} else {
+			val, err = inferValue(path, p.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		scope.Variables[name] = val
+	}
+	return scope, nil
+}
+
+// inferValue converts `v`, a GoPrimitive's Value, into a tftypes.Value,
+// inferring its type.
+func inferValue(path *tftypes.AttributePath, v interface{}) (tftypes.Value, error) {
+	switch x := v.(type) {
+	case nil:
+		return tftypes.NewValue(tfDynamic, nil), nil
+	case string:
+		return tftypes.NewValue(tfString, x), nil
+	case bool:
+		return tftypes.NewValue(tfBool, x), nil
+	case *big.Float:
+		if x == nil {
+			return tftypes.NewValue(tfNumber, nil), nil
+		}
+		return tftypes.NewValue(tfNumber, x), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice:
+		elems := make([]tftypes.Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			elem, err := inferValue(path.WithElementKeyInt(i), rv.Index(i).Interface())
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		return newTuple(elems), nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		attrs := make(map[string]tftypes.Value, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			attr, err := inferValue(path.WithAttributeName(k), iter.Value().Interface())
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[k] = attr
+		}
+		return newObject(attrs), nil
+	}
+	return tftypes.Value{}, path.NewErrorf("unsupported %T in GoPrimitive", v)
+}
+
+// Expression is a parsed expression, which can be evaluated any number of
+// times.
+type Expression struct {
+	src  string
+	expr hclsyntax.Expression
+}
+
+// Parse parses the expression `src` in HCL's native syntax.
+func Parse(src string) (*Expression, error) {
+	expr, diags := hclsyntax.ParseExpression([]byte(src), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diagsError(diags)
+	}
+	return &Expression{src: src, expr: expr}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.src
+}
+
+// Evaluate evaluates the expression with the variables and functions in
+// `scope`, which may be nil.
+func (e *Expression) Evaluate(scope *Scope) (tftypes.Value, error) {
+	val, err := e.evaluate(scope)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return FromCty(val)
+}
+
+// EvaluateBool evaluates the expression, like Evaluate, and returns its
+// result, which must be a known, non-null bool, or convertible to one. It's
+// meant for filters.
+func (e *Expression) EvaluateBool(scope *Scope) (bool, error) {
+	val, err := e.evaluate(scope)
+	if err != nil {
+		return false, err
+	}
+	if !val.IsWhollyKnown() {
+		return false, fmt.Errorf("expression %q is unknown", e.src)
+	}
+	if val.IsNull() {
+		return false, fmt.Errorf("expression %q must be a bool: value is null", e.src)
+	}
+	val, err = convert.Convert(val, cty.Bool)
+	if err != nil {
+		return false, fmt.Errorf("expression %q must be a bool: %w", e.src, err)
+	}
+	return val.True(), nil
+}
+
+func (e *Expression) evaluate(scope *Scope) (cty.Value, error) {
+	ctx, err := scope.evalContext()
+	if err != nil {
+		return cty.NilVal, err
+	}
+	val, diags := e.expr.Value(ctx)
+	if diags.HasErrors() {
+		return cty.NilVal, diagsError(diags)
+	}
+	return val, nil
+}
+
+// Evaluate parses and evaluates the expression `src` with the variables and
+// functions in `scope`.
+func Evaluate(src string, scope *Scope) (tftypes.Value, error) {
+	expr, err := Parse(src)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return expr.Evaluate(scope)
+}
+
+// evalContext returns the hcl.EvalContext for the variables and functions
+// in `s`, which may be nil.
+func (s *Scope) evalContext() (*hcl.EvalContext, error) {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{},
+		Functions: make(map[string]function.Function, len(Builtins)),
+	}
+	for name, f := range Builtins {
+		ctx.Functions[name] = f
+	}
+	if s == nil {
+		return ctx, nil
+	}
+	for name, v := range s.Variables {
+		val, err := toCty(tftypes.NewAttributePath().WithAttributeName(name), v)
+		if err != nil {
+			return nil, err
+		}
+		ctx.Variables[name] = val
+	}
+	for name, f := range s.Functions {
+		ctx.Functions[name] = f.ctyFunction()
+	}
+	return ctx, nil
+}
+
+// ctyFunction returns `f` as a cty function that takes any number of
+// arguments of any type, including null, and has an unknown result if any
+// of them are unknown.
+func (f Function) ctyFunction() function.Function {
+	return function.New(&function.Spec{
+		VarParam: &function.Parameter{
+			Name:             "args",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowDynamicType: true,
+		},
+		Type: function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			vals := make([]tftypes.Value, 0, len(args))
+			for i, arg := range args {
+				val, err := FromCty(arg)
+				if err != nil {
+					return cty.NilVal, function.NewArgError(i, err)
+				}
+				vals = append(vals, val)
+			}
+			res, err := f(vals)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			return ToCty(res)
+		},
+	})
+}
+
+// diagsError returns the errors in `diags` as an error, identifying their
+// position in the expression by line and column.
+func diagsError(diags hcl.Diagnostics) error {
+	var errs []error
+	for _, d := range diags {
+		if d.Severity != hcl.DiagError {
+			continue
+		}
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += "; " + d.Detail
+		}
+		if d.Subject != nil {
+			msg = fmt.Sprintf("%d:%d: %s", d.Subject.Start.Line, d.Subject.Start.Column, msg)
+		}
+		errs = append(errs, errors.New(msg))
+	}
+	return errors.Join(errs...)
+}
+
+func newTuple(elems []tftypes.Value) tftypes.Value {
+	types := make([]tftypes.Type, 0, len(elems))
+	for _, e := range elems {
+		types = append(types, e.Type())
+	}
+	return tftypes.NewValue(tftypes.Tuple{ElementTypes: types}, elems)
+}
+
+func newObject(attrs map[string]tftypes.Value) tftypes.Value {
+	types := make(map[string]tftypes.Type, len(attrs))
+	for k, v := range attrs {
+		types[k] = v.Type()
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, attrs)
+}
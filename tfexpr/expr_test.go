@@ -0,0 +1,286 @@
+package tfexpr
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testScope(t *testing.T) *Scope {
+	t.Helper()
+	scope, err := NewScope(map[string]asgotypes.GoPrimitive{
+		"name": {Value: "web"},
+		"size": {Value: big.NewFloat(3)},
+		"tags": {
+			Value: map[string]string{"env": "prod", "team": "infra"},
+			Type:  tftypes.Map{ElementType: tftypes.String},
+		},
+		"servers": {Value: []interface{}{
+			map[string]interface{}{"name": "a", "port": big.NewFloat(80)},
+			map[string]interface{}{"name": "b", "port": big.NewFloat(443)},
+		}},
+		"missing": {Value: nil},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	scope.Variables["pending"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	return scope
+}
+
+func str(s string) tftypes.Value {
+	return tftypes.NewValue(tftypes.String, s)
+}
+
+func num(f float64) tftypes.Value {
+	return tftypes.NewValue(tftypes.Number, big.NewFloat(f))
+}
+
+func boolean(b bool) tftypes.Value {
+	return tftypes.NewValue(tftypes.Bool, b)
+}
+
+func strs(ss ...string) tftypes.Value {
+	elems := make([]tftypes.Value, 0, len(ss))
+	for _, s := range ss {
+		elems = append(elems, str(s))
+	}
+	return newTuple(elems)
+}
+
+func TestEvaluate(t *testing.T) {
+	type testCase struct {
+		expr     string
+		expected tftypes.Value
+	}
+	cases := map[string]testCase{
+		"literal": {
+			expr:     `1.5`,
+			expected: num(1.5),
+		},
+		"variable": {
+			expr:     `name`,
+			expected: str("web"),
+		},
+		"null": {
+			expr:     `null`,
+			expected: tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+		},
+		"template": {
+			expr:     `"${name}-${size}: \"${tags.env}\" $${x}"`,
+			expected: str(`web-3: "prod" ${x}`),
+		},
+		"interpolation-only": {
+			expr:     `"${size}"`,
+			expected: num(3),
+		},
+		"arithmetic": {
+			expr:     `-size * 2 + 10 / 4 - 7 % 4`,
+			expected: num(-6.5),
+		},
+		"precedence": {
+			expr:     `size > 2 && name == "web" || false`,
+			expected: boolean(true),
+		},
+		"not": {
+			expr:     `!(size >= 3)`,
+			expected: boolean(false),
+		},
+		"conditional": {
+			expr:     `tags.env == "prod" ? "p" : "np"`,
+			expected: str("p"),
+		},
+		"index": {
+			expr:     `servers[1]["name"]`,
+			expected: str("b"),
+		},
+		"legacy-index": {
+			expr:     `servers.0.port`,
+			expected: num(80),
+		},
+		"map-index": {
+			expr:     `tags["team"]`,
+			expected: str("infra"),
+		},
+		"splat": {
+			expr:     `servers[*].name`,
+			expected: strs("a", "b"),
+		},
+		"splat-of-null": {
+			expr:     `missing[*].name`,
+			expected: newTuple([]tftypes.Value{}),
+		},
+		"tuple": {
+			expr:     `[name, "x"]`,
+			expected: strs("web", "x"),
+		},
+		"object": {
+			expr: `{name = name, "size": size}`,
+			expected: newObject(map[string]tftypes.Value{
+				"name": str("web"),
+				"size": num(3),
+			}),
+		},
+		"for-tuple": {
+			expr:     `[for s in servers : upper(s.name) if s.port > 100]`,
+			expected: strs("B"),
+		},
+		"for-object": {
+			expr: `{for k, v in tags : v => k}`,
+			expected: newObject(map[string]tftypes.Value{
+				"prod":  str("env"),
+				"infra": str("team"),
+			}),
+		},
+		"for-index": {
+			expr:     `[for i, s in servers : "${i}:${s.name}"]`,
+			expected: strs("0:a", "1:b"),
+		},
+		"equality": {
+			expr:     `servers[*].name == ["a", "b"] && missing == null`,
+			expected: boolean(true),
+		},
+		"functions": {
+			expr:     `join(",", keys(tags)) == "env,team" && length(servers) == 2 && contains(split("-", "a-b"), "b")`,
+			expected: boolean(true),
+		},
+		"coalesce": {
+			expr:     `coalesce(missing, "fallback")`,
+			expected: str("fallback"),
+		},
+		"unknown-propagates": {
+			expr:     `pending == "x"`,
+			expected: tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue),
+		},
+		"unknown-template": {
+			expr:     `"${name}-${pending}"`,
+			expected: tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		},
+		"unknown-call": {
+			expr:     `upper(pending)`,
+			expected: tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		},
+		"unknown-custom-call": {
+			expr:     `isweb(pending)`,
+			expected: tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue),
+		},
+	}
+	scope := testScope(t)
+	scope.Functions = map[string]Function{
+		"isweb": func(args []tftypes.Value) (tftypes.Value, error) {
+			return boolean(args[0].Equal(str("web"))), nil
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := Evaluate(tc.expr, scope)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	type testCase struct {
+		expr        string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"syntax": {
+			expr:        `size +`,
+			expectedErr: `1:7: Missing expression; Expected the start of an expression, but found the end of the file.`,
+		},
+		"trailing": {
+			expr:        `size size`,
+			expectedErr: `1:6: Extra characters after expression; An expression was successfully parsed, but extra characters were found after it.`,
+		},
+		"unterminated-string": {
+			expr:        `"abc`,
+			expectedErr: `1:5: Unterminated template string; No closing marker was found for the string.`,
+		},
+		"unknown-variable": {
+			expr:        `nope`,
+			expectedErr: `1:1: Unknown variable; There is no variable named "nope". Did you mean "name"?`,
+		},
+		"missing-attribute": {
+			expr:        `servers[0].nope`,
+			expectedErr: `1:11: Unsupported attribute; This object does not have an attribute named "nope".`,
+		},
+		"out-of-range": {
+			expr:        `servers[2]`,
+			expectedErr: `1:8: Invalid index; The given key does not identify an element in this collection value: the given index is greater than or equal to the length of the collection.`,
+		},
+		"type-mismatch": {
+			expr:        `name * 2`,
+			expectedErr: `1:1: Invalid operand; Unsuitable value for left operand: a number is required.`,
+		},
+		"argument-type": {
+			expr:        `upper(servers)`,
+			expectedErr: `1:7: Invalid function argument; Invalid value for "str" parameter: string required, but have tuple.`,
+		},
+		"null-in-template": {
+			expr:        `"a${missing}"`,
+			expectedErr: `1:5: Invalid template interpolation value; The expression result is null. Cannot include a null value in a string template.`,
+		},
+		"unknown-function": {
+			expr:        `nope(1)`,
+			expectedErr: `1:1: Call to unknown function; There is no function named "nope".`,
+		},
+		"function-error": {
+			expr:        `upper(name, name)`,
+			expectedErr: `1:13: Too many function arguments; Function "upper" expects only 1 argument(s).`,
+		},
+		"multiline": {
+			expr:        "size +\n  nope",
+			expectedErr: `2:3: Unknown variable; There is no variable named "nope". Did you mean "name"?`,
+		},
+	}
+	scope := testScope(t)
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := Evaluate(tc.expr, scope)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestEvaluateBool(t *testing.T) {
+	scope := testScope(t)
+	scope.Functions = map[string]Function{
+		"isweb": func(args []tftypes.Value) (tftypes.Value, error) {
+			return boolean(args[0].Equal(str("web"))), nil
+		},
+	}
+	expr, err := Parse(`isweb(name) && startswith(tags.env, "pr")`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ok, err := expr.EvaluateBool(scope)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ok {
+		t.Error("expected expression to be true")
+	}
+
+	expr, err = Parse(`pending == "x"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = expr.EvaluateBool(scope)
+	expectedErr := `expression "pending == \"x\"" is unknown`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
@@ -0,0 +1,46 @@
+package tfexpr
+
+import (
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// Builtins are the functions available to every expression. They're a
+// subset of Terraform's functions, with the same names, implemented by
+// cty's standard library; Terraform adds some behavior of its own, so
+// coalesce, for example, doesn't skip empty strings here.
+var Builtins = map[string]function.Function{
+	"length":     stdlib.LengthFunc,
+	"lower":      stdlib.LowerFunc,
+	"upper":      stdlib.UpperFunc,
+	"trimspace":  stdlib.TrimSpaceFunc,
+	"startswith": affixFunc(strings.HasPrefix, "prefix"),
+	"endswith":   affixFunc(strings.HasSuffix, "suffix"),
+	"contains":   stdlib.ContainsFunc,
+	"keys":       stdlib.KeysFunc,
+	"values":     stdlib.ValuesFunc,
+	"join":       stdlib.JoinFunc,
+	"split":      stdlib.SplitFunc,
+	"tostring":   stdlib.MakeToFunc(cty.String),
+	"tonumber":   stdlib.MakeToFunc(cty.Number),
+	"tobool":     stdlib.MakeToFunc(cty.Bool),
+	"coalesce":   stdlib.CoalesceFunc,
+}
+
+// affixFunc returns Terraform's startswith or endswith function, which
+// aren't in cty's standard library, using `f` to test the string.
+func affixFunc(f func(s, affix string) bool, affix string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: affix, Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.Bool),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.BoolVal(f(args[0].AsString(), args[1].AsString())), nil
+		},
+	})
+}
@@ -0,0 +1,183 @@
+// Package tffunction provides a typed abstraction over the RPCs for
+// provider-defined functions, GetFunctions and CallFunction.
+//
+// Providers supply a Function, often just a CallFunc, that accepts the
+// function's arguments decoded into a struct A and returns a result R.
+// NewServer takes care of decoding the arguments, attaching errors to the
+// argument that caused them, and encoding the result as the function's
+// return type. A uses `tfsdk` struct tags as understood by the asgotypes
+// package, naming the function's parameters:
+//
+//	type JoinArgs struct {
+//		Separator string   `tfsdk:"separator"`
+//		Parts     []string `tfsdk:"parts"`
+//	}
+//
+// The arguments for a variadic parameter are decoded into a slice field
+// tagged with its name. Registry combines Servers, or any other Handler, into
+// the function RPCs of a provider.
+package tffunction
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfdiags"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Function is a provider-defined function.
+type Function[A, R any] interface {
+	// Call returns the result of the function for `args`. Errors caused
+	// by an argument should be wrapped in a *tfdiags.ArgumentError, so
+	// that Terraform can point at it.
+	Call(ctx context.Context, args A) (R, error)
+}
+
+// CallFunc is a function that implements Function.
+type CallFunc[A, R any] func(ctx context.Context, args A) (R, error)
+
+// Call calls `f`.
+func (f CallFunc[A, R]) Call(ctx context.Context, args A) (R, error) {
+	return f(ctx, args)
+}
+
+var _ Handler = &Server[struct{}, struct{}]{}
+
+// Server is a Handler for a single function, backed by a Function.
+type Server[A, R any] struct {
+	definition *tfprotov5.Function
+	function   Function[A, R]
+}
+
+// NewServer returns a Server for the function described by `definition`,
+// backed by `f`. Every parameter in `definition`, including the variadic
+// one, must have a name.
+func NewServer[A, R any](definition *tfprotov5.Function, f Function[A, R]) *Server[A, R] {
+	return &Server[A, R]{
+		definition: definition,
+		function:   f,
+	}
+}
+
+// Definition returns the function's definition.
+func (s *Server[A, R]) Definition() *tfprotov5.Function {
+	return s.definition
+}
+
+// CallFunction decodes the arguments into A, calls Function.Call, and
+// encodes the result. Errors are returned as the response's FunctionError.
+func (s *Server[A, R]) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	resp := &tfprotov5.CallFunctionResponse{}
+	args, fe := Arguments(s.definition, req.Arguments)
+	if fe != nil {
+		resp.Error = fe
+		return resp, nil
+	}
+	var decoded A
+	if err := asgotypes.DecodeContext(ctx, args, &decoded); err != nil {
+		resp.Error = argumentError(s.definition, err)
+		return resp, nil
+	}
+	result, err := s.function.Call(ctx, decoded)
+	if err != nil {
+		resp.Error = tfdiags.FunctionErrorFromError(err)
+		return resp, nil
+	}
+	resp.Result, resp.Error = Result(ctx, s.definition, result)
+	return resp, nil
+}
+
+// Arguments unmarshals the arguments of a call to the function described by
+// `definition` into an object with an attribute for each parameter, named
+// after it. The arguments for the variadic parameter are combined into a
+// tuple.
+func Arguments(definition *tfprotov5.Function, args []*tfprotov5.DynamicValue) (tftypes.Value, *tfprotov5.FunctionError) {
+	params := definition.Parameters
+	if len(args) < len(params) || (definition.VariadicParameter == nil && len(args) > len(params)) {
+		return tftypes.Value{}, tfdiags.FunctionError("expected %d arguments, got %d", len(params), len(args))
+	}
+	attrTypes := make(map[string]tftypes.Type, len(params)+1)
+	attrs := make(map[string]tftypes.Value, len(params)+1)
+	for i, param := range params {
+		val, err := unmarshal(param.Type, args[i])
+		if err != nil {
+			return tftypes.Value{}, tfdiags.FunctionArgumentError(int64(i), "cannot read argument %q: %s", param.Name, err)
+		}
+		attrTypes[param.Name] = param.Type
+		attrs[param.Name] = val
+	}
+	if variadic := definition.VariadicParameter; variadic != nil {
+		var (
+			elemTypes []tftypes.Type
+			elems     []tftypes.Value
+		)
+		for _, arg := range args[len(params):] {
+			val, err := unmarshal(variadic.Type, arg)
+			if err != nil {
+				return tftypes.Value{}, tfdiags.FunctionArgumentError(int64(len(params)), "cannot read argument %q: %s", variadic.Name, err)
+			}
+			elemTypes = append(elemTypes, val.Type())
+			elems = append(elems, val)
+		}
+		typ := tftypes.Tuple{ElementTypes: elemTypes}
+		attrTypes[variadic.Name] = typ
+		attrs[variadic.Name] = tftypes.NewValue(typ, elems)
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, attrs), nil
+}
+
+// Result encodes `result` as the return type of the function described by
+// `definition`.
+func Result(ctx context.Context, definition *tfprotov5.Function, result interface{}) (*tfprotov5.DynamicValue, *tfprotov5.FunctionError) {
+	if definition.Return == nil {
+		return nil, tfdiags.FunctionError("function has no return type")
+	}
+	typ := definition.Return.Type
+	val, err := asgotypes.EncodeContext(ctx, typ, result)
+	if err != nil {
+		return nil, tfdiags.FunctionError("cannot encode result: %s", err)
+	}
+	dv, err := tfprotov5.NewDynamicValue(typ, val)
+	if err != nil {
+		return nil, tfdiags.FunctionError("cannot encode result: %s", err)
+	}
+	return &dv, nil
+}
+
+func unmarshal(typ tftypes.Type, dv *tfprotov5.DynamicValue) (tftypes.Value, error) {
+	if dv == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	return dv.Unmarshal(typ)
+}
+
+// argumentError returns a FunctionError for an error decoding the arguments,
+// attached to the argument whose parameter is named by the first step of its
+// path.
+func argumentError(definition *tfprotov5.Function, err error) *tfprotov5.FunctionError {
+	fe := tfdiags.FunctionError("cannot decode arguments: %s", err)
+	var pathErr tftypes.AttributePathError
+	if !errors.As(err, &pathErr) {
+		return fe
+	}
+	steps := pathErr.Path.Steps()
+	if len(steps) == 0 {
+		return fe
+	}
+	name, ok := steps[0].(tftypes.AttributeName)
+	if !ok {
+		return fe
+	}
+	for i, param := range definition.Parameters {
+		if param.Name == string(name) {
+			return tfdiags.FunctionArgumentError(int64(i), "invalid argument: %s", err)
+		}
+	}
+	if v := definition.VariadicParameter; v != nil && v.Name == string(name) {
+		return tfdiags.FunctionArgumentError(int64(len(definition.Parameters)), "invalid argument: %s", err)
+	}
+	return fe
+}
@@ -0,0 +1,142 @@
+package tffunction
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfdiags"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var joinDefinition = &tfprotov5.Function{
+	Parameters: []*tfprotov5.FunctionParameter{
+		{Name: "separator", Type: tftypes.String},
+	},
+	VariadicParameter: &tfprotov5.FunctionParameter{Name: "parts", Type: tftypes.String},
+	Return:            &tfprotov5.FunctionReturn{Type: tftypes.String},
+}
+
+type joinArgs struct {
+	Separator string   `tfsdk:"separator"`
+	Parts     []string `tfsdk:"parts"`
+}
+
+func join(ctx context.Context, args joinArgs) (string, error) {
+	for _, part := range args.Parts {
+		if part == "" {
+			return "", &tfdiags.ArgumentError{Index: 1, Err: errors.New("parts cannot be empty")}
+		}
+	}
+	return strings.Join(args.Parts, args.Separator), nil
+}
+
+func dynamicValues(t *testing.T, vals ...tftypes.Value) []*tfprotov5.DynamicValue {
+	t.Helper()
+	var dvs []*tfprotov5.DynamicValue
+	for _, val := range vals {
+		dv, err := tfprotov5.NewDynamicValue(val.Type(), val)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		dvs = append(dvs, &dv)
+	}
+	return dvs
+}
+
+func TestServerCallFunction(t *testing.T) {
+	s := NewServer[joinArgs, string](joinDefinition, CallFunc[joinArgs, string](join))
+	index := func(i int64) *int64 { return &i }
+	type testCase struct {
+		args     []tftypes.Value
+		expected tftypes.Value
+		err      *tfprotov5.FunctionError
+	}
+	cases := map[string]testCase{
+		"variadic": {
+			args: []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "-"),
+				tftypes.NewValue(tftypes.String, "a"),
+				tftypes.NewValue(tftypes.String, "b"),
+			},
+			expected: tftypes.NewValue(tftypes.String, "a-b"),
+		},
+		"no-variadic-arguments": {
+			args:     []tftypes.Value{tftypes.NewValue(tftypes.String, "-")},
+			expected: tftypes.NewValue(tftypes.String, ""),
+		},
+		"too-few-arguments": {
+			err: &tfprotov5.FunctionError{Text: "expected 1 arguments, got 0"},
+		},
+		"unreadable-argument": {
+			args: []tftypes.Value{tftypes.NewValue(tftypes.Bool, true)},
+			err: &tfprotov5.FunctionError{
+				Text:             `cannot read argument "separator": error decoding string: msgpack: invalid code=c3 decoding string/bytes length`,
+				FunctionArgument: index(0),
+			},
+		},
+		"argument-error": {
+			args: []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "-"),
+				tftypes.NewValue(tftypes.String, ""),
+			},
+			err: &tfprotov5.FunctionError{Text: "parts cannot be empty", FunctionArgument: index(1)},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			resp, err := s.CallFunction(context.Background(), &tfprotov5.CallFunctionRequest{
+				Name:      "join",
+				Arguments: dynamicValues(t, tc.args...),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.err, resp.Error); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			if tc.err != nil {
+				return
+			}
+			got, err := resp.Result.Unmarshal(tftypes.String)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestServerDecodeError(t *testing.T) {
+	type args struct {
+		Separator string `tfsdk:"separator"`
+		Parts     []bool `tfsdk:"parts"`
+	}
+	s := NewServer[args, string](joinDefinition, CallFunc[args, string](func(ctx context.Context, args args) (string, error) {
+		return "", nil
+	}))
+	resp, err := s.CallFunction(context.Background(), &tfprotov5.CallFunctionRequest{
+		Name: "join",
+		Arguments: dynamicValues(t,
+			tftypes.NewValue(tftypes.String, "-"),
+			tftypes.NewValue(tftypes.String, "a"),
+		),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	index := int64(1)
+	expected := &tfprotov5.FunctionError{
+		Text:             `invalid argument: AttributeName("parts").ElementKeyInt(0): cannot decode tftypes.String into bool`,
+		FunctionArgument: &index,
+	}
+	if diff := cmp.Diff(expected, resp.Error); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,81 @@
+package tffunction
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// Handler is a handler for a single provider-defined function.
+type Handler interface {
+	// Definition returns the function's definition.
+	Definition() *tfprotov5.Function
+
+	CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error)
+}
+
+// Registry associates Handlers with function names, and implements the
+// function RPCs of tfprotov5.FunctionServer by dispatching to them. The zero
+// value is an empty Registry ready to use.
+type Registry struct {
+	handlers map[string]Handler
+}
+
+// Add registers `h` as the handler for the function called `name`,
+// replacing any Handler already registered for it.
+func (r *Registry) Add(name string, h Handler) {
+	if r.handlers == nil {
+		r.handlers = map[string]Handler{}
+	}
+	r.handlers[name] = h
+}
+
+// Names returns the names of the registered functions, sorted.
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.handlers))
+	for name := range r.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Definitions returns the definitions of the registered functions, by
+// name, for GetProviderSchema and GetFunctions responses.
+func (r *Registry) Definitions() map[string]*tfprotov5.Function {
+	defs := map[string]*tfprotov5.Function{}
+	if r == nil {
+		return defs
+	}
+	for name, h := range r.handlers {
+		defs[name] = h.Definition()
+	}
+	return defs
+}
+
+// GetFunctions returns the definitions of the registered functions.
+func (r *Registry) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	return &tfprotov5.GetFunctionsResponse{Functions: r.Definitions()}, nil
+}
+
+// CallFunction calls the Handler registered for the function named in the
+// request, or returns a FunctionError if there isn't one.
+func (r *Registry) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	var h Handler
+	if r != nil {
+		h = r.handlers[req.Name]
+	}
+	if h == nil {
+		return &tfprotov5.CallFunctionResponse{
+			Error: &tfprotov5.FunctionError{
+				Text: fmt.Sprintf("The provider does not support the function %q.", req.Name),
+			},
+		}, nil
+	}
+	return h.CallFunction(ctx, req)
+}
@@ -0,0 +1,34 @@
+package tffunction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+func TestRegistry(t *testing.T) {
+	r := &Registry{}
+	r.Add("join", NewServer[joinArgs, string](joinDefinition, CallFunc[joinArgs, string](join)))
+
+	resp, err := r.GetFunctions(context.Background(), &tfprotov5.GetFunctionsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp.Functions["join"] != joinDefinition {
+		t.Errorf("expected join definition, got %+v", resp.Functions)
+	}
+	if diff := cmp.Diff([]string{"join"}, r.Names()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	call, err := r.CallFunction(context.Background(), &tfprotov5.CallFunctionRequest{Name: "split"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := &tfprotov5.FunctionError{Text: `The provider does not support the function "split".`}
+	if diff := cmp.Diff(expected, call.Error); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,139 @@
+// Package tfidentity provides helpers for the resource identity features of
+// the protocol, which let practitioners import resources and address them
+// by a set of identifying attributes, like a region and a name, rather than
+// a single opaque ID.
+//
+// Identity data is an object described by a ResourceIdentitySchema. The
+// helpers here convert it to and from tftypes.Values and structs tagged for
+// use with the asgotypes codec, derive it from a resource's state, and
+// upgrade it from earlier identity schema versions.
+package tfidentity
+
+import (
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Identified is implemented by resource handlers that support resource
+// identity, like tfresource.Server, so tfrouter can return their identity
+// schemas. Handlers that only sometimes support it return nil.
+type Identified interface {
+	IdentitySchema() *tfprotov5.ResourceIdentitySchema
+}
+
+// Unmarshal returns the value of `data`, which is described by `schema`.
+// Missing data is returned as a null value.
+func Unmarshal(schema *tfprotov5.ResourceIdentitySchema, data *tfprotov5.ResourceIdentityData) (tftypes.Value, error) {
+	typ := schema.ValueType()
+	if data == nil || data.IdentityData == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	return data.IdentityData.Unmarshal(typ)
+}
+
+// Marshal returns `val`, which is described by `schema`, as identity data.
+// Null values are returned as nil, as Terraform expects when a resource has
+// no identity.
+func Marshal(schema *tfprotov5.ResourceIdentitySchema, val tftypes.Value) (*tfprotov5.ResourceIdentityData, error) {
+	if val.IsNull() {
+		return nil, nil
+	}
+	if !val.IsFullyKnown() {
+		return nil, errors.New("identity data cannot contain unknown values")
+	}
+	dv, err := tfprotov5.NewDynamicValue(schema.ValueType(), val)
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov5.ResourceIdentityData{IdentityData: &dv}, nil
+}
+
+// Decode decodes `data`, which is described by `schema`, into `target`
+// using asgotypes.Decode. `target` is usually a pointer to a tagged struct.
+func Decode(schema *tfprotov5.ResourceIdentitySchema, data *tfprotov5.ResourceIdentityData, target interface{}) error {
+	val, err := Unmarshal(schema, data)
+	if err != nil {
+		return err
+	}
+	return asgotypes.Decode(val, target)
+}
+
+// Encode encodes `src` as identity data described by `schema` using
+// asgotypes.Encode. `src` is usually a tagged struct.
+func Encode(schema *tfprotov5.ResourceIdentitySchema, src interface{}) (*tfprotov5.ResourceIdentityData, error) {
+	val, err := asgotypes.Encode(schema.ValueType(), src)
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(schema, val)
+}
+
+// FromState returns the identity described by `schema` of the resource
+// whose state is `state`, taking the value of each identity attribute from
+// the state attribute with the same name. Null state has null identity.
+func FromState(schema *tfprotov5.ResourceIdentitySchema, state tftypes.Value) (tftypes.Value, error) {
+	typ := schema.ValueType().(tftypes.Object)
+	if state.IsNull() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if !state.Type().Is(tftypes.Object{}) {
+		return tftypes.Value{}, errors.New("state must be an object")
+	}
+	var attrs map[string]tftypes.Value
+	if err := state.As(&attrs); err != nil {
+		return tftypes.Value{}, err
+	}
+	identity := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for _, name := range sorted.Keys(typ.AttributeTypes) {
+		attrType := typ.AttributeTypes[name]
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		attr, ok := attrs[name]
+		if !ok {
+			return tftypes.Value{}, path.NewErrorf("identity attribute is not an attribute of the resource")
+		}
+		if !attr.Type().Equal(attrType) {
+			return tftypes.Value{}, path.NewErrorf("identity attribute is a %s, but the resource's attribute is a %s", attrType, attr.Type())
+		}
+		identity[name] = attr
+	}
+	return tftypes.NewValue(typ, identity), nil
+}
+
+// ToState returns a value of type `stateType`, which must be an object type,
+// with the identity attributes of `identity` set and every other attribute
+// null. It is the inverse of FromState, and is useful for importing a
+// resource by its identity.
+func ToState(stateType tftypes.Type, identity tftypes.Value) (tftypes.Value, error) {
+	typ, ok := stateType.(tftypes.Object)
+	if !ok {
+		return tftypes.Value{}, errors.New("state must be an object")
+	}
+	if identity.IsNull() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	var attrs map[string]tftypes.Value
+	if err := identity.As(&attrs); err != nil {
+		return tftypes.Value{}, err
+	}
+	state := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, attrType := range typ.AttributeTypes {
+		state[name] = tftypes.NewValue(attrType, nil)
+	}
+	for _, name := range sorted.Keys(attrs) {
+		attr := attrs[name]
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		attrType, ok := typ.AttributeTypes[name]
+		if !ok {
+			return tftypes.Value{}, path.NewErrorf("identity attribute is not an attribute of the resource")
+		}
+		if !attr.Type().Equal(attrType) {
+			return tftypes.Value{}, path.NewErrorf("identity attribute is a %s, but the resource's attribute is a %s", attr.Type(), attrType)
+		}
+		state[name] = attr
+	}
+	return tftypes.NewValue(typ, state), nil
+}
@@ -0,0 +1,168 @@
+package tfidentity
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testSchema = &tfprotov5.ResourceIdentitySchema{
+	Version: 1,
+	IdentityAttributes: []*tfprotov5.ResourceIdentitySchemaAttribute{
+		{Name: "region", Type: tftypes.String, RequiredForImport: true},
+		{Name: "name", Type: tftypes.String, RequiredForImport: true},
+	},
+}
+
+var (
+	testIdentityType = testSchema.ValueType()
+	testStateType    = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":     tftypes.String,
+		"region": tftypes.String,
+		"name":   tftypes.String,
+	}}
+)
+
+type testIdentity struct {
+	Region string `tfsdk:"region"`
+	Name   string `tfsdk:"name"`
+}
+
+func testIdentityValue(region, name interface{}) tftypes.Value {
+	return tftypes.NewValue(testIdentityType, map[string]tftypes.Value{
+		"region": tftypes.NewValue(tftypes.String, region),
+		"name":   tftypes.NewValue(tftypes.String, name),
+	})
+}
+
+func testStateValue(id, region, name interface{}) tftypes.Value {
+	return tftypes.NewValue(testStateType, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, id),
+		"region": tftypes.NewValue(tftypes.String, region),
+		"name":   tftypes.NewValue(tftypes.String, name),
+	})
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	data, err := Encode(testSchema, testIdentity{Region: "eu-west-1", Name: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	val, err := Unmarshal(testSchema, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(testIdentityValue("eu-west-1", "foo"), val); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	var got testIdentity
+	if err := Decode(testSchema, data, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(testIdentity{Region: "eu-west-1", Name: "foo"}, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestMarshalNull(t *testing.T) {
+	data, err := Marshal(testSchema, tftypes.NewValue(testIdentityType, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if data != nil {
+		t.Errorf("expected nil identity data, got %+v", data)
+	}
+	val, err := Unmarshal(testSchema, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !val.IsNull() {
+		t.Errorf("expected null identity, got %s", val)
+	}
+}
+
+func TestMarshalUnknown(t *testing.T) {
+	_, err := Marshal(testSchema, testIdentityValue("eu-west-1", tftypes.UnknownValue))
+	expected := "identity data cannot contain unknown values"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestFromState(t *testing.T) {
+	type testCase struct {
+		state    tftypes.Value
+		expected tftypes.Value
+		err      string
+	}
+	cases := map[string]testCase{
+		"known": {
+			state:    testStateValue("i-1", "eu-west-1", "foo"),
+			expected: testIdentityValue("eu-west-1", "foo"),
+		},
+		"unknown": {
+			state:    testStateValue(tftypes.UnknownValue, "eu-west-1", tftypes.UnknownValue),
+			expected: testIdentityValue("eu-west-1", tftypes.UnknownValue),
+		},
+		"null": {
+			state:    tftypes.NewValue(testStateType, nil),
+			expected: tftypes.NewValue(testIdentityType, nil),
+		},
+		"missing-attribute": {
+			state: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"region": tftypes.String,
+			}}, map[string]tftypes.Value{
+				"region": tftypes.NewValue(tftypes.String, "eu-west-1"),
+			}),
+			err: `AttributeName("name"): identity attribute is not an attribute of the resource`,
+		},
+		"wrong-type": {
+			state: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"region": tftypes.String,
+				"name":   tftypes.Number,
+			}}, map[string]tftypes.Value{
+				"region": tftypes.NewValue(tftypes.String, "eu-west-1"),
+				"name":   tftypes.NewValue(tftypes.Number, 1),
+			}),
+			err: `AttributeName("name"): identity attribute is a tftypes.String, but the resource's attribute is a tftypes.Number`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := FromState(testSchema, tc.state)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Errorf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestToState(t *testing.T) {
+	got, err := ToState(testStateType, testIdentityValue("eu-west-1", "foo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(testStateValue(nil, "eu-west-1", "foo"), got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	_, err = ToState(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id": tftypes.String,
+	}}, testIdentityValue("eu-west-1", "foo"))
+	expected := `AttributeName("name"): identity attribute is not an attribute of the resource`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
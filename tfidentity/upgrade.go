@@ -0,0 +1,84 @@
+package tfidentity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfstate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Chain upgrades identity data written with any earlier identity schema
+// version to the current one by running tfstate.Upgraders in sequence, like
+// tfstate.Chain does for state.
+type Chain struct {
+	// Schema is the current identity schema of the resource.
+	Schema *tfprotov5.ResourceIdentitySchema
+
+	// Upgraders holds the Upgraders for each earlier version, keyed by
+	// the version they upgrade from. There must be one for every version
+	// from the oldest supported version up to Schema.Version-1.
+	Upgraders map[int64]tfstate.Upgrader
+}
+
+// Upgrade decodes `raw`, which was written with identity schema version
+// `version`, and upgrades it to the current version.
+func (c *Chain) Upgrade(ctx context.Context, version int64, raw *tfprotov5.RawState) (tftypes.Value, error) {
+	if version > c.Schema.Version {
+		return tftypes.Value{}, fmt.Errorf("identity was written with identity schema version %d, which is newer than the current version %d", version, c.Schema.Version)
+	}
+	typ, err := c.typeAt(version)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	identity, err := tfstate.UnmarshalRawState(raw, typ)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	for v := version; v < c.Schema.Version; v++ {
+		upgraded, err := c.Upgraders[v].Upgrade(ctx, identity)
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("upgrading identity from version %d to %d: %w", v, v+1, err)
+		}
+		next, err := c.typeAt(v + 1)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		identity, err = asgotypes.Encode(next, upgraded)
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("upgrading identity from version %d to %d: %w", v, v+1, err)
+		}
+	}
+	return identity, nil
+}
+
+// typeAt returns the type of the identity at `version`.
+func (c *Chain) typeAt(version int64) (tftypes.Type, error) {
+	if version == c.Schema.Version {
+		return c.Schema.ValueType(), nil
+	}
+	u, ok := c.Upgraders[version]
+	if !ok || u == nil {
+		return nil, fmt.Errorf("upgrading identity from identity schema version %d is not supported", version)
+	}
+	return u.Type(), nil
+}
+
+// UpgradeResourceIdentity implements the UpgradeResourceIdentity RPC using
+// Upgrade. Any error is returned as a diagnostic on the response.
+func (c *Chain) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) *tfprotov5.UpgradeResourceIdentityResponse {
+	resp := &tfprotov5.UpgradeResourceIdentityResponse{}
+	identity, err := c.Upgrade(ctx, req.Version, req.RawIdentity)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error upgrading identity", err))
+		return resp
+	}
+	resp.UpgradedIdentity, err = Marshal(c.Schema, identity)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error upgrading identity", err))
+	}
+	return resp
+}
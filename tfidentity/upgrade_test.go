@@ -0,0 +1,80 @@
+package tfidentity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfstate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testChain() *Chain {
+	type identityV0 struct {
+		Name string `tfsdk:"name"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name": tftypes.String,
+	}}
+	return &Chain{
+		Schema: testSchema,
+		Upgraders: map[int64]tfstate.Upgrader{
+			0: tfstate.UpgradeFunc(typ, func(ctx context.Context, prior identityV0) (testIdentity, error) {
+				return testIdentity{Region: "us-east-1", Name: prior.Name}, nil
+			}),
+		},
+	}
+}
+
+func TestChainUpgradeResourceIdentity(t *testing.T) {
+	type testCase struct {
+		version       int64
+		json          string
+		expected      tftypes.Value
+		expectedDiags []*tfprotov5.Diagnostic
+	}
+	cases := map[string]testCase{
+		"current": {
+			version:  1,
+			json:     `{"region": "eu-west-1", "name": "foo"}`,
+			expected: testIdentityValue("eu-west-1", "foo"),
+		},
+		"upgraded": {
+			version:  0,
+			json:     `{"name": "foo"}`,
+			expected: testIdentityValue("us-east-1", "foo"),
+		},
+		"newer": {
+			version: 2,
+			json:    `{}`,
+			expectedDiags: []*tfprotov5.Diagnostic{{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Error upgrading identity",
+				Detail:   "identity was written with identity schema version 2, which is newer than the current version 1",
+			}},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			resp := testChain().UpgradeResourceIdentity(context.Background(), &tfprotov5.UpgradeResourceIdentityRequest{
+				Version:     tc.version,
+				RawIdentity: &tfprotov5.RawState{JSON: []byte(tc.json)},
+			})
+			if diff := cmp.Diff(tc.expectedDiags, resp.Diagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics diff (-wanted, +got): %s", diff)
+			}
+			if tc.expectedDiags != nil {
+				return
+			}
+			got, err := Unmarshal(testSchema, resp.UpgradedIdentity)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
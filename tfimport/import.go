@@ -0,0 +1,173 @@
+// Package tfimport provides helpers for implementing ImportResourceState.
+//
+// Most resources are imported using an ID that either is one of the
+// resource's attributes, or is made up of several of them joined with a
+// separator, like "org/project/name". ID describes both cases, and builds
+// the state that Terraform will pass to ReadResource to finish the import.
+package tfimport
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/parse"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// DefaultSeparator is the separator used between the parts of an ID when
+// ID.Separator is empty.
+const DefaultSeparator = "/"
+
+// ID describes the format of the IDs a resource can be imported with.
+type ID struct {
+	// Paths are the attributes each part of the ID is stored in, in
+	// order. The paths may only contain attribute names, and each
+	// attribute must be a string, number, or bool.
+	Paths []*tftypes.AttributePath
+
+	// Separator separates the parts of the ID. It defaults to
+	// DefaultSeparator. When there's only one path the whole ID is
+	// stored in it, and Separator is ignored.
+	Separator string
+}
+
+// Passthrough returns an ID that stores the whole import ID in the attribute
+// at `path`.
+func Passthrough(path *tftypes.AttributePath) ID {
+	return ID{Paths: []*tftypes.AttributePath{path}}
+}
+
+// MultiPart returns an ID made up of one part for each of `paths`, separated
+// by DefaultSeparator.
+func MultiPart(paths ...*tftypes.AttributePath) ID {
+	return ID{Paths: paths}
+}
+
+func (i ID) separator() string {
+	if i.Separator == "" {
+		return DefaultSeparator
+	}
+	return i.Separator
+}
+
+// Format returns a description of the format of the ID, like
+// "org/project/name", built from the last attribute name of each path.
+func (i ID) Format() string {
+	names := make([]string, 0, len(i.Paths))
+	for _, path := range i.Paths {
+		name := "id"
+		if step, ok := path.LastStep().(tftypes.AttributeName); ok {
+			name = string(step)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, i.separator())
+}
+
+// Parse splits `id` into its parts. It returns an error if `id` doesn't have
+// exactly one non-empty part for each path.
+func (i ID) Parse(id string) ([]string, error) {
+	if len(i.Paths) == 0 {
+		return nil, errors.New("no attributes were configured for the import ID")
+	}
+	parts := []string{id}
+	if len(i.Paths) > 1 {
+		parts = strings.Split(id, i.separator())
+	}
+	if len(parts) != len(i.Paths) {
+		return nil, fmt.Errorf("expected an import ID of the form %q, got %q", i.Format(), id)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("expected an import ID of the form %q, got %q", i.Format(), id)
+		}
+	}
+	return parts, nil
+}
+
+// State parses `id` and returns a value of type `typ` with each part of the
+// ID stored at its path, and all other attributes null.
+func (i ID) State(typ tftypes.Type, id string) (tftypes.Value, error) {
+	parts, err := i.Parse(id)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	state := tftypes.NewValue(typ, nil)
+	for n, path := range i.Paths {
+		state, err = setAt(tftypes.NewAttributePath(), state, path.Steps(), parts[n])
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+	}
+	return state, nil
+}
+
+// ImportResourceState implements the ImportResourceState RPC for a
+// resource described by `schema`, returning a single resource whose state is
+// built using State. A malformed ID is returned as a diagnostic on the
+// response.
+func (i ID) ImportResourceState(schema *tfprotov5.Schema, req *tfprotov5.ImportResourceStateRequest) *tfprotov5.ImportResourceStateResponse {
+	resp := &tfprotov5.ImportResourceStateResponse{}
+	typ := schema.ValueType()
+	state, err := i.State(typ, req.ID)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Invalid import ID", err))
+		return resp
+	}
+	dv, err := tfprotov5.NewDynamicValue(typ, state)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error importing resource", err))
+		return resp
+	}
+	resp.ImportedResources = []*tfprotov5.ImportedResource{
+		{
+			TypeName: req.TypeName,
+			State:    &dv,
+		},
+	}
+	return resp
+}
+
+// setAt returns `val` with the attribute reached by `steps` set to `s`,
+// creating any null objects along the way.
+func setAt(path *tftypes.AttributePath, val tftypes.Value, steps []tftypes.AttributePathStep, s string) (tftypes.Value, error) {
+	if len(steps) == 0 {
+		v, err := parse.Primitive(val.Type(), s)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return v, nil
+	}
+	name, ok := steps[0].(tftypes.AttributeName)
+	if !ok {
+		return tftypes.Value{}, path.NewErrorf("import IDs can only be stored in attributes of objects")
+	}
+	typ, ok := val.Type().(tftypes.Object)
+	if !ok {
+		return tftypes.Value{}, path.NewErrorf("import IDs can only be stored in attributes of objects")
+	}
+	if _, ok := typ.AttributeTypes[string(name)]; !ok {
+		return tftypes.Value{}, path.NewErrorf("%q is not an attribute of the object", name)
+	}
+	attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	if !val.IsNull() {
+		if err := val.As(&attrs); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+	}
+	for k, t := range typ.AttributeTypes {
+		if _, ok := attrs[k]; !ok {
+			attrs[k] = tftypes.NewValue(t, nil)
+		}
+	}
+	attrPath := path.WithAttributeName(string(name))
+	attr, err := setAt(attrPath, attrs[string(name)], steps[1:], s)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	attrs[string(name)] = attr
+	return tftypes.NewValue(typ, attrs), nil
+}
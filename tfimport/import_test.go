@@ -0,0 +1,146 @@
+package tfimport
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Computed: true},
+			{Name: "org", Type: tftypes.String, Required: true},
+			{Name: "project", Type: tftypes.Number, Required: true},
+			{Name: "name", Type: tftypes.String, Required: true},
+		},
+	},
+}
+
+var testType = testSchema.ValueType()
+
+func testValue(id, org, project, name interface{}) tftypes.Value {
+	return tftypes.NewValue(testType, map[string]tftypes.Value{
+		"id":      tftypes.NewValue(tftypes.String, id),
+		"org":     tftypes.NewValue(tftypes.String, org),
+		"project": tftypes.NewValue(tftypes.Number, project),
+		"name":    tftypes.NewValue(tftypes.String, name),
+	})
+}
+
+func TestIDState(t *testing.T) {
+	type testCase struct {
+		format      ID
+		id          string
+		expected    tftypes.Value
+		expectedErr string
+	}
+	multi := MultiPart(
+		tftypes.NewAttributePath().WithAttributeName("org"),
+		tftypes.NewAttributePath().WithAttributeName("project"),
+		tftypes.NewAttributePath().WithAttributeName("name"),
+	)
+	cases := map[string]testCase{
+		"passthrough": {
+			format:   Passthrough(tftypes.NewAttributePath().WithAttributeName("id")),
+			id:       "abc/def",
+			expected: testValue("abc/def", nil, nil, nil),
+		},
+		"multi-part": {
+			format:   multi,
+			id:       "acme/42/web",
+			expected: testValue(nil, "acme", big.NewFloat(42), "web"),
+		},
+		"separator": {
+			format: ID{
+				Paths:     multi.Paths,
+				Separator: ":",
+			},
+			id:       "acme:42:web",
+			expected: testValue(nil, "acme", big.NewFloat(42), "web"),
+		},
+		"too-few-parts": {
+			format:      multi,
+			id:          "acme/42",
+			expectedErr: `expected an import ID of the form "org/project/name", got "acme/42"`,
+		},
+		"empty-part": {
+			format:      multi,
+			id:          "acme//web",
+			expectedErr: `expected an import ID of the form "org/project/name", got "acme//web"`,
+		},
+		"invalid-number": {
+			format:      multi,
+			id:          "acme/forty-two/web",
+			expectedErr: `AttributeName("project"): can't parse "forty-two" as a number`,
+		},
+		"unknown-attribute": {
+			format:      Passthrough(tftypes.NewAttributePath().WithAttributeName("uuid")),
+			id:          "abc",
+			expectedErr: `"uuid" is not an attribute of the object`,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			got, err := test.format.State(testType, test.id)
+			if test.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", test.expectedErr)
+				}
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestIDImportResourceState(t *testing.T) {
+	format := Passthrough(tftypes.NewAttributePath().WithAttributeName("id"))
+	resp := format.ImportResourceState(testSchema, &tfprotov5.ImportResourceStateRequest{
+		TypeName: "example_thing",
+		ID:       "abc",
+	})
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+	if len(resp.ImportedResources) != 1 || resp.ImportedResources[0].TypeName != "example_thing" {
+		t.Fatalf("unexpected imported resources: %+v", resp.ImportedResources)
+	}
+	got, err := resp.ImportedResources[0].State.Unmarshal(testType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(testValue("abc", nil, nil, nil), got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	resp = MultiPart(
+		tftypes.NewAttributePath().WithAttributeName("org"),
+		tftypes.NewAttributePath().WithAttributeName("name"),
+	).ImportResourceState(testSchema, &tfprotov5.ImportResourceStateRequest{
+		TypeName: "example_thing",
+		ID:       "abc",
+	})
+	expected := []*tfprotov5.Diagnostic{
+		{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Invalid import ID",
+			Detail:   `expected an import ID of the form "org/name", got "abc"`,
+		},
+	}
+	if diff := cmp.Diff(expected, resp.Diagnostics); diff != "" {
+		t.Errorf("unexpected diagnostics diff (-wanted, +got): %s", diff)
+	}
+}
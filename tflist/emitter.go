@@ -0,0 +1,106 @@
+package tflist
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfidentity"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfstate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// Emitter streams the objects found by a Lister back to Terraform.
+type Emitter[T any] struct {
+	ctx      context.Context
+	schema   *tfprotov5.Schema
+	identity *tfprotov5.ResourceIdentitySchema
+	include  bool
+	limit    int64
+	yield    func(tfprotov5.ListResourceResult) bool
+
+	emitted int64
+	stopped bool
+}
+
+// Emit emits an object with the state `resource`, shown to practitioners as
+// `displayName`. Its identity is derived from `resource` using
+// tfidentity.FromState, and the state itself is only sent if Terraform asked
+// for it.
+//
+// Emit returns false once Terraform doesn't want any more results, because
+// the list's limit has been reached or it has stopped reading them, and the
+// Lister should stop listing and return.
+func (e *Emitter[T]) Emit(displayName string, resource T) bool {
+	if e.stopped {
+		return false
+	}
+	result, err := e.result(displayName, resource)
+	if err != nil {
+		return e.send(tfprotov5.ListResourceResult{
+			DisplayName: displayName,
+			Diagnostics: []*tfprotov5.Diagnostic{diag.Error("Error listing resource", err)},
+		})
+	}
+	e.emitted++
+	if !e.send(result) {
+		return false
+	}
+	if e.limit > 0 && e.emitted >= e.limit {
+		e.stopped = true
+	}
+	return !e.stopped
+}
+
+// Error emits a result with an error diagnostic, for objects that were found
+// but couldn't be read. It returns false under the same conditions as Emit.
+func (e *Emitter[T]) Error(err error) bool {
+	if e.stopped {
+		return false
+	}
+	return e.send(tfprotov5.ListResourceResult{
+		Diagnostics: []*tfprotov5.Diagnostic{diag.Error("Error listing resource", err)},
+	})
+}
+
+// Emitted returns the number of objects emitted so far.
+func (e *Emitter[T]) Emitted() int64 {
+	return e.emitted
+}
+
+func (e *Emitter[T]) send(result tfprotov5.ListResourceResult) bool {
+	if !e.yield(result) {
+		e.stopped = true
+	}
+	return !e.stopped
+}
+
+func (e *Emitter[T]) result(displayName string, resource T) (tfprotov5.ListResourceResult, error) {
+	typ := e.schema.ValueType()
+	state, err := asgotypes.EncodeContext(e.ctx, typ, resource)
+	if err != nil {
+		return tfprotov5.ListResourceResult{}, err
+	}
+	identity, err := tfidentity.FromState(e.identity, state)
+	if err != nil {
+		return tfprotov5.ListResourceResult{}, err
+	}
+	result := tfprotov5.ListResourceResult{DisplayName: displayName}
+	result.Identity, err = tfidentity.Marshal(e.identity, identity)
+	if err != nil {
+		return tfprotov5.ListResourceResult{}, err
+	}
+	if !e.include {
+		return result, nil
+	}
+	state, err = tfstate.NullWriteOnly(e.schema, state)
+	if err != nil {
+		return tfprotov5.ListResourceResult{}, err
+	}
+	dv, err := tfprotov5.NewDynamicValue(typ, state)
+	if err != nil {
+		return tfprotov5.ListResourceResult{}, err
+	}
+	result.Resource = &dv
+	return result, nil
+}
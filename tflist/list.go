@@ -0,0 +1,45 @@
+// Package tflist provides a typed abstraction over the list resource RPCs
+// of tfprotov5.ListResourceServer, which Terraform uses to find existing
+// remote objects to import, for example with `terraform query`.
+//
+// Providers implement Lister for a filter struct F, decoded from the list
+// block's configuration, and the managed resource's state struct T. Found
+// objects are passed to an Emitter, which encodes each one's identity, and
+// its state if Terraform asked for it, as a result streamed back to
+// Terraform. Both F and T use `tfsdk` struct tags as understood by the
+// asgotypes package.
+package tflist
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfidentity"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// Lister lists the remote objects of a managed resource type.
+type Lister[F, T any] interface {
+	// List finds the remote objects matching `filter` and emits each of
+	// them with `emit`, returning once Emit returns false. Returning an
+	// error emits it as a final result with an error diagnostic.
+	List(ctx context.Context, filter F, emit *Emitter[T]) error
+}
+
+// ListFunc is a function that implements Lister.
+type ListFunc[F, T any] func(ctx context.Context, filter F, emit *Emitter[T]) error
+
+// List calls `f`.
+func (f ListFunc[F, T]) List(ctx context.Context, filter F, emit *Emitter[T]) error {
+	return f(ctx, filter, emit)
+}
+
+// Resource describes the managed resource type being listed. It is
+// implemented by tfresource.Server for resources that implement
+// tfidentity.Identified. Every listed object needs an identity, so the
+// identity schema must not be nil.
+type Resource interface {
+	tfidentity.Identified
+
+	// Schema returns the managed resource type's schema.
+	Schema() *tfprotov5.Schema
+}
@@ -0,0 +1,116 @@
+package tflist
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ tfprotov5.ListResourceServer = &Server[struct{}, struct{}]{}
+
+// Server is a tfprotov5.ListResourceServer for a single managed resource
+// type, backed by a Lister.
+type Server[F, T any] struct {
+	schema   *tfprotov5.Schema
+	typ      tftypes.Type
+	resource Resource
+	lister   Lister[F, T]
+}
+
+// NewServer returns a Server listing objects of the managed resource type
+// described by `resource`, backed by `lister`. `schema` is the schema of the
+// list block's configuration, which is decoded into F.
+func NewServer[F, T any](schema *tfprotov5.Schema, resource Resource, lister Lister[F, T]) *Server[F, T] {
+	return &Server[F, T]{
+		schema:   schema,
+		typ:      schema.ValueType(),
+		resource: resource,
+		lister:   lister,
+	}
+}
+
+// Schema returns the schema of the list block's configuration.
+func (s *Server[F, T]) Schema() *tfprotov5.Schema {
+	return s.schema
+}
+
+// Validators returns the Lister's Validators, if it implements
+// tfvalidate.Validated.
+func (s *Server[F, T]) Validators() *tfvalidate.Registry {
+	if v, ok := s.lister.(tfvalidate.Validated); ok {
+		return v.Validators()
+	}
+	return nil
+}
+
+// ValidateListResourceConfig checks that the config can be decoded into F.
+func (s *Server[F, T]) ValidateListResourceConfig(ctx context.Context, req *tfprotov5.ValidateListResourceConfigRequest) (*tfprotov5.ValidateListResourceConfigResponse, error) {
+	resp := &tfprotov5.ValidateListResourceConfigResponse{}
+	config, err := s.unmarshal(req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading configuration", err))
+		return resp, nil
+	}
+	var decoded F
+	d := asgotypes.Decoder{AllowUnknown: true}
+	if err := d.DecodeContext(ctx, config, &decoded); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Invalid configuration", err))
+	}
+	return resp, nil
+}
+
+// ListResource decodes the config into F and calls Lister.List, streaming
+// the objects it emits back to Terraform. Errors are returned as results
+// with error diagnostics.
+func (s *Server[F, T]) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	identity := s.resource.IdentitySchema()
+	if identity == nil {
+		return errorStream(diag.Error("Error listing resources", errors.New("the resource does not support resource identity"))), nil
+	}
+	config, err := s.unmarshal(req.Config)
+	if err != nil {
+		return errorStream(diag.Error("Error reading configuration", err)), nil
+	}
+	var filter F
+	if err := asgotypes.DecodeContext(ctx, config, &filter); err != nil {
+		return errorStream(diag.Error("Invalid configuration", err)), nil
+	}
+	return &tfprotov5.ListResourceServerStream{
+		Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+			e := &Emitter[T]{
+				ctx:      ctx,
+				schema:   s.resource.Schema(),
+				identity: identity,
+				include:  req.IncludeResource,
+				limit:    req.Limit,
+				yield:    yield,
+			}
+			if err := s.lister.List(ctx, filter, e); err != nil && !e.stopped {
+				yield(tfprotov5.ListResourceResult{
+					Diagnostics: []*tfprotov5.Diagnostic{diag.Error("Error listing resources", err)},
+				})
+			}
+		},
+	}, nil
+}
+
+func (s *Server[F, T]) unmarshal(dv *tfprotov5.DynamicValue) (tftypes.Value, error) {
+	if dv == nil {
+		return tftypes.NewValue(s.typ, nil), nil
+	}
+	return dv.Unmarshal(s.typ)
+}
+
+// errorStream returns a stream with a single result reporting `d`.
+func errorStream(d *tfprotov5.Diagnostic) *tfprotov5.ListResourceServerStream {
+	return &tfprotov5.ListResourceServerStream{
+		Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+			yield(tfprotov5.ListResourceResult{Diagnostics: []*tfprotov5.Diagnostic{d}})
+		},
+	}
+}
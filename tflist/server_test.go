@@ -0,0 +1,194 @@
+package tflist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type widget struct {
+	ID   string `tfsdk:"id"`
+	Name string `tfsdk:"name"`
+}
+
+type widgetFilter struct {
+	Prefix string `tfsdk:"prefix"`
+}
+
+type testResource struct {
+	identity *tfprotov5.ResourceIdentitySchema
+}
+
+func (r testResource) Schema() *tfprotov5.Schema {
+	return widgetSchema
+}
+
+func (r testResource) IdentitySchema() *tfprotov5.ResourceIdentitySchema {
+	return r.identity
+}
+
+var (
+	widgetSchema = &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "id", Type: tftypes.String, Computed: true},
+				{Name: "name", Type: tftypes.String, Required: true},
+			},
+		},
+	}
+	widgetIdentitySchema = &tfprotov5.ResourceIdentitySchema{
+		IdentityAttributes: []*tfprotov5.ResourceIdentitySchemaAttribute{
+			{Name: "id", Type: tftypes.String, RequiredForImport: true},
+		},
+	}
+	filterSchema = &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "prefix", Type: tftypes.String, Optional: true},
+			},
+		},
+	}
+)
+
+func listWidgets(ctx context.Context, filter widgetFilter, emit *Emitter[widget]) error {
+	for i := 1; i <= 3; i++ {
+		if !emit.Emit(fmt.Sprintf("%s widget %d", filter.Prefix, i), widget{ID: fmt.Sprintf("w-%d", i), Name: filter.Prefix}) {
+			return nil
+		}
+	}
+	return errors.New("page 2 is unavailable")
+}
+
+func filterValue(t *testing.T, prefix string) *tfprotov5.DynamicValue {
+	t.Helper()
+	typ := filterSchema.ValueType()
+	dv, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, map[string]tftypes.Value{
+		"prefix": tftypes.NewValue(tftypes.String, prefix),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+func widgetIdentity(t *testing.T, id string) *tfprotov5.ResourceIdentityData {
+	t.Helper()
+	typ := widgetIdentitySchema.ValueType()
+	dv, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, id),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tfprotov5.ResourceIdentityData{IdentityData: &dv}
+}
+
+func widgetState(t *testing.T, id, name string) *tfprotov5.DynamicValue {
+	t.Helper()
+	typ := widgetSchema.ValueType()
+	dv, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, id),
+		"name": tftypes.NewValue(tftypes.String, name),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+func collect(t *testing.T, stream *tfprotov5.ListResourceServerStream) []tfprotov5.ListResourceResult {
+	t.Helper()
+	var results []tfprotov5.ListResourceResult
+	for result := range stream.Results {
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestServerListResource(t *testing.T) {
+	listErr := tfprotov5.ListResourceResult{
+		Diagnostics: []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Error listing resources",
+			Detail:   "page 2 is unavailable",
+		}},
+	}
+	type testCase struct {
+		req      *tfprotov5.ListResourceRequest
+		expected func(t *testing.T) []tfprotov5.ListResourceResult
+	}
+	cases := map[string]testCase{
+		"identities": {
+			req: &tfprotov5.ListResourceRequest{Config: filterValue(t, "blue")},
+			expected: func(t *testing.T) []tfprotov5.ListResourceResult {
+				return []tfprotov5.ListResourceResult{
+					{DisplayName: "blue widget 1", Identity: widgetIdentity(t, "w-1")},
+					{DisplayName: "blue widget 2", Identity: widgetIdentity(t, "w-2")},
+					{DisplayName: "blue widget 3", Identity: widgetIdentity(t, "w-3")},
+					listErr,
+				}
+			},
+		},
+		"include-resource-with-limit": {
+			req: &tfprotov5.ListResourceRequest{Config: filterValue(t, "blue"), IncludeResource: true, Limit: 2},
+			expected: func(t *testing.T) []tfprotov5.ListResourceResult {
+				return []tfprotov5.ListResourceResult{
+					{DisplayName: "blue widget 1", Identity: widgetIdentity(t, "w-1"), Resource: widgetState(t, "w-1", "blue")},
+					{DisplayName: "blue widget 2", Identity: widgetIdentity(t, "w-2"), Resource: widgetState(t, "w-2", "blue")},
+				}
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			srv := NewServer[widgetFilter, widget](filterSchema, testResource{identity: widgetIdentitySchema}, ListFunc[widgetFilter, widget](listWidgets))
+			stream, err := srv.ListResource(context.Background(), tc.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected(t), collect(t, stream)); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestServerListResourceStopped(t *testing.T) {
+	srv := NewServer[widgetFilter, widget](filterSchema, testResource{identity: widgetIdentitySchema}, ListFunc[widgetFilter, widget](listWidgets))
+	stream, err := srv.ListResource(context.Background(), &tfprotov5.ListResourceRequest{Config: filterValue(t, "blue")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var names []string
+	for result := range stream.Results {
+		names = append(names, result.DisplayName)
+		break
+	}
+	if diff := cmp.Diff([]string{"blue widget 1"}, names); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestServerListResourceWithoutIdentity(t *testing.T) {
+	srv := NewServer[widgetFilter, widget](filterSchema, testResource{}, ListFunc[widgetFilter, widget](listWidgets))
+	stream, err := srv.ListResource(context.Background(), &tfprotov5.ListResourceRequest{Config: filterValue(t, "blue")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []tfprotov5.ListResourceResult{{
+		Diagnostics: []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Error listing resources",
+			Detail:   "the resource does not support resource identity",
+		}},
+	}}
+	if diff := cmp.Diff(expected, collect(t, stream)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,330 @@
+// Package tfmerge builds the new state of a resource from its configuration,
+// its prior state, and the values returned by its API.
+//
+// Every provider built directly on terraform-plugin-go ends up writing this
+// logic for ApplyResourceChange and ReadResource, usually slightly
+// differently for each resource: configured values must be kept as they were
+// written, computed values come from the API, and values the API doesn't
+// return, like passwords, have to be carried over from the prior state.
+// Merger implements those rules for any schema, with hooks for the
+// attributes that need something else.
+package tfmerge
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Values holds the configuration, prior state, and remote value of a
+// resource or of one of its attributes.
+type Values struct {
+	Config tftypes.Value
+	Prior  tftypes.Value
+	Remote tftypes.Value
+}
+
+// Func overrides the merged value of an attribute or nested block. It's
+// called with the attribute's values and the value the default rules
+// produced, and returns the value to use instead.
+type Func func(ctx context.Context, path *tftypes.AttributePath, vals Values, merged tftypes.Value) (tftypes.Value, error)
+
+// PreferPrior returns a Func that keeps the prior value of the attribute
+// whenever there is one, for values like generated passwords that the API
+// only returns when they're created.
+func PreferPrior() Func {
+	return func(ctx context.Context, path *tftypes.AttributePath, vals Values, merged tftypes.Value) (tftypes.Value, error) {
+		if vals.Prior.IsNull() {
+			return merged, nil
+		}
+		return vals.Prior, nil
+	}
+}
+
+// PreferRemote returns a Func that uses the remote value of the attribute
+// whenever there is one, even if it's configured, for values the API is the
+// source of truth for.
+func PreferRemote() Func {
+	return func(ctx context.Context, path *tftypes.AttributePath, vals Values, merged tftypes.Value) (tftypes.Value, error) {
+		if vals.Remote.IsNull() || !vals.Remote.IsKnown() {
+			return merged, nil
+		}
+		return vals.Remote, nil
+	}
+}
+
+// Merge returns the new state of a resource described by `schema` using a
+// Merger with no overrides.
+func Merge(schema *tfprotov5.Schema, config, prior, remote tftypes.Value) (tftypes.Value, error) {
+	m := Merger{Schema: schema}
+	return m.Merge(context.Background(), config, prior, remote)
+}
+
+// Merger merges configuration, prior state, and remote values. Its Schema
+// must be set.
+type Merger struct {
+	Schema *tfprotov5.Schema
+
+	// Equality, if set, is used to keep the prior value of attributes
+	// whose remote value is semantically equal to it.
+	Equality *tfequal.Registry
+
+	overrides attrpath.Registry[Func]
+}
+
+// Override registers `f` for the attribute or nested block at `path`,
+// replacing any Func already registered for it. Element keys in `path` are
+// ignored, so a Func registered for an attribute of a nested block applies
+// to that attribute in every element of the block.
+func (m *Merger) Override(path *tftypes.AttributePath, f Func) {
+	m.overrides.Set(path, f)
+}
+
+// Merge returns the new state of the resource. `config` is null when there's
+// no configuration, as in ReadResource and ImportResourceState, and `remote`
+// is the resource's state as read from its API after any change was applied.
+// If `remote` is null, the resource doesn't exist and null is returned.
+//
+// Each attribute takes:
+//
+//   - its configured value, if it's set in the configuration;
+//
+//   - null, if it isn't set in the configuration and isn't computed, as
+//     Terraform requires;
+//
+//   - otherwise, its remote value, or its prior value if that's
+//     semantically equal to the remote one;
+//
+//   - or, if the remote value is null, its prior value.
+//
+// Nested blocks are merged recursively. Elements of list blocks are matched
+// by index and elements of map blocks by key, and their elements come from
+// the configuration if there is one, or the remote value otherwise. Set
+// blocks have no identity other than their value, so they're taken whole
+// from the configuration, remote value, or prior state, in that order.
+func (m *Merger) Merge(ctx context.Context, config, prior, remote tftypes.Value) (tftypes.Value, error) {
+	if m.Schema == nil || m.Schema.Block == nil {
+		return tftypes.Value{}, errors.New("cannot merge without a schema")
+	}
+	if remote.IsNull() {
+		return remote, nil
+	}
+	return m.block(ctx, tftypes.NewAttributePath(), m.Schema.Block, Values{Config: config, Prior: prior, Remote: remote})
+}
+
+func (m *Merger) block(ctx context.Context, path *tftypes.AttributePath, block *tfprotov5.SchemaBlock, vals Values) (tftypes.Value, error) {
+	configAttrs, err := attrs(vals.Config)
+	if err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	priorAttrs, err := attrs(vals.Prior)
+	if err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	remoteAttrs, err := attrs(vals.Remote)
+	if err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	merged := make(map[string]tftypes.Value, len(block.Attributes)+len(block.BlockTypes))
+	for _, attr := range block.Attributes {
+		attrPath := path.WithAttributeName(attr.Name)
+		attrVals := Values{
+			Config: valueOr(configAttrs, attr.Name, attr.ValueType()),
+			Prior:  valueOr(priorAttrs, attr.Name, attr.ValueType()),
+			Remote: valueOr(remoteAttrs, attr.Name, attr.ValueType()),
+		}
+		val, err := m.attribute(attrPath, attr, !vals.Config.IsNull(), attrVals)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		merged[attr.Name], err = m.override(ctx, attrPath, attrVals, val)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+	}
+	for _, nested := range block.BlockTypes {
+		nestedPath := path.WithAttributeName(nested.TypeName)
+		nestedVals := Values{
+			Config: valueOr(configAttrs, nested.TypeName, nested.ValueType()),
+			Prior:  valueOr(priorAttrs, nested.TypeName, nested.ValueType()),
+			Remote: valueOr(remoteAttrs, nested.TypeName, nested.ValueType()),
+		}
+		val, err := m.nestedBlock(ctx, nestedPath, nested, !vals.Config.IsNull(), nestedVals)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		merged[nested.TypeName], err = m.override(ctx, nestedPath, nestedVals, val)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+	}
+	return tftypes.NewValue(block.ValueType(), merged), nil
+}
+
+func (m *Merger) attribute(path *tftypes.AttributePath, attr *tfprotov5.SchemaAttribute, configured bool, vals Values) (tftypes.Value, error) {
+	switch {
+	case !vals.Config.IsNull():
+		return vals.Config, nil
+	case configured && !attr.Computed:
+		return vals.Config, nil
+	case vals.Remote.IsNull():
+		return vals.Prior, nil
+	}
+	equal, err := m.Equality.Equal(path, vals.Prior, vals.Remote)
+	if err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	if equal {
+		return vals.Prior, nil
+	}
+	return vals.Remote, nil
+}
+
+func (m *Merger) nestedBlock(ctx context.Context, path *tftypes.AttributePath, nested *tfprotov5.SchemaNestedBlock, configured bool, vals Values) (tftypes.Value, error) {
+	// Elements come from the configuration if there is one, since blocks
+	// can't be computed, and from the remote value otherwise.
+	source := vals.Remote
+	if configured {
+		source = vals.Config
+	}
+	elemType := nested.Block.ValueType()
+	switch nested.Nesting {
+	case tfprotov5.SchemaNestedBlockNestingModeSingle, tfprotov5.SchemaNestedBlockNestingModeGroup:
+		if source.IsNull() {
+			return source, nil
+		}
+		return m.block(ctx, path, nested.Block, vals)
+	case tfprotov5.SchemaNestedBlockNestingModeList:
+		if source.IsNull() {
+			return source, nil
+		}
+		sourceElems, err := elems(source)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		configElems, err := elems(vals.Config)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		priorElems, err := elems(vals.Prior)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		remoteElems, err := elems(vals.Remote)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		merged := make([]tftypes.Value, 0, len(sourceElems))
+		for i := range sourceElems {
+			elem, err := m.block(ctx, path.WithElementKeyInt(i), nested.Block, Values{
+				Config: index(configElems, i, elemType),
+				Prior:  index(priorElems, i, elemType),
+				Remote: index(remoteElems, i, elemType),
+			})
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			merged = append(merged, elem)
+		}
+		return tftypes.NewValue(nested.ValueType(), merged), nil
+	case tfprotov5.SchemaNestedBlockNestingModeMap:
+		if source.IsNull() {
+			return source, nil
+		}
+		sourceElems, err := attrs(source)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		configElems, err := attrs(vals.Config)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		priorElems, err := attrs(vals.Prior)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		remoteElems, err := attrs(vals.Remote)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		merged := make(map[string]tftypes.Value, len(sourceElems))
+		for k := range sourceElems {
+			merged[k], err = m.block(ctx, path.WithElementKeyString(k), nested.Block, Values{
+				Config: valueOr(configElems, k, elemType),
+				Prior:  valueOr(priorElems, k, elemType),
+				Remote: valueOr(remoteElems, k, elemType),
+			})
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+		}
+		return tftypes.NewValue(nested.ValueType(), merged), nil
+	case tfprotov5.SchemaNestedBlockNestingModeSet:
+		switch {
+		case configured:
+			return vals.Config, nil
+		case !vals.Remote.IsNull():
+			return vals.Remote, nil
+		}
+		return vals.Prior, nil
+	}
+	return tftypes.Value{}, path.NewErrorf("unsupported nesting mode %s", nested.Nesting)
+}
+
+func (m *Merger) override(ctx context.Context, path *tftypes.AttributePath, vals Values, merged tftypes.Value) (tftypes.Value, error) {
+	for _, f := range m.overrides.Get(path) {
+		val, err := f(ctx, path, vals, merged)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		if !val.Type().Equal(merged.Type()) {
+			return tftypes.Value{}, path.NewErrorf("override returned a value of type %s, expected %s", val.Type(), merged.Type())
+		}
+		return val, nil
+	}
+	return merged, nil
+}
+
+// attrs returns the attributes of an object value or the elements of a map
+// value, or an empty map if the value is null or unknown.
+func attrs(val tftypes.Value) (map[string]tftypes.Value, error) {
+	res := map[string]tftypes.Value{}
+	if val.IsNull() || !val.IsKnown() {
+		return res, nil
+	}
+	if err := val.As(&res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// elems returns the elements of a list value, or nil if the value is null
+// or unknown.
+func elems(val tftypes.Value) ([]tftypes.Value, error) {
+	if val.IsNull() || !val.IsKnown() {
+		return nil, nil
+	}
+	var res []tftypes.Value
+	if err := val.As(&res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func valueOr(vals map[string]tftypes.Value, k string, typ tftypes.Type) tftypes.Value {
+	if val, ok := vals[k]; ok {
+		return val
+	}
+	return tftypes.NewValue(typ, nil)
+}
+
+func index(vals []tftypes.Value, i int, typ tftypes.Type) tftypes.Value {
+	if i < len(vals) {
+		return vals[i]
+	}
+	return tftypes.NewValue(typ, nil)
+}
@@ -0,0 +1,136 @@
+package tfmerge
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Computed: true},
+			{Name: "name", Type: tftypes.String, Required: true},
+			{Name: "description", Type: tftypes.String, Optional: true},
+			{Name: "password", Type: tftypes.String, Optional: true, Sensitive: true},
+			{Name: "policy", Type: tftypes.String, Optional: true, Computed: true},
+		},
+		BlockTypes: []*tfprotov5.SchemaNestedBlock{
+			{
+				TypeName: "rule",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "port", Type: tftypes.Number, Required: true},
+						{Name: "rule_id", Type: tftypes.String, Computed: true},
+					},
+				},
+			},
+		},
+	},
+}
+
+var (
+	testType     = testSchema.ValueType()
+	testRuleType = testSchema.Block.BlockTypes[0].Block.ValueType()
+)
+
+func testValue(id, name, description, password, policy interface{}, rules ...tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(testType, map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, id),
+		"name":        tftypes.NewValue(tftypes.String, name),
+		"description": tftypes.NewValue(tftypes.String, description),
+		"password":    tftypes.NewValue(tftypes.String, password),
+		"policy":      tftypes.NewValue(tftypes.String, policy),
+		"rule":        tftypes.NewValue(tftypes.List{ElementType: testRuleType}, rules),
+	})
+}
+
+func testRule(port int64, ruleID interface{}) tftypes.Value {
+	return tftypes.NewValue(testRuleType, map[string]tftypes.Value{
+		"port":    tftypes.NewValue(tftypes.Number, big.NewFloat(float64(port))),
+		"rule_id": tftypes.NewValue(tftypes.String, ruleID),
+	})
+}
+
+func TestMerge(t *testing.T) {
+	type testCase struct {
+		config   tftypes.Value
+		prior    tftypes.Value
+		remote   tftypes.Value
+		expected tftypes.Value
+	}
+	cases := map[string]testCase{
+		"create": {
+			config:   testValue(nil, "web", nil, "hunter2", `{"a": 1}`, testRule(80, nil)),
+			prior:    tftypes.NewValue(testType, nil),
+			remote:   testValue("i-1", "WEB", "managed", nil, `{"a":1}`, testRule(80, "r-1")),
+			expected: testValue("i-1", "web", nil, "hunter2", `{"a": 1}`, testRule(80, "r-1")),
+		},
+		"update-computed": {
+			config:   testValue(nil, "web", "new", nil, nil, testRule(80, nil), testRule(443, nil)),
+			prior:    testValue("i-1", "web", "old", nil, `{"a": 1}`, testRule(80, "r-1")),
+			remote:   testValue("i-1", "web", "new", nil, `{"b":2}`, testRule(80, "r-1"), testRule(443, "r-2")),
+			expected: testValue("i-1", "web", "new", nil, `{"b":2}`, testRule(80, "r-1"), testRule(443, "r-2")),
+		},
+		"read": {
+			config:   tftypes.NewValue(testType, nil),
+			prior:    testValue("i-1", "web", "old", "hunter2", `{"a": 1}`, testRule(80, "r-1")),
+			remote:   testValue("i-1", "web", "changed", nil, `{"a":1}`),
+			expected: testValue("i-1", "web", "changed", "hunter2", `{"a": 1}`),
+		},
+		"deleted": {
+			config:   tftypes.NewValue(testType, nil),
+			prior:    testValue("i-1", "web", nil, nil, nil),
+			remote:   tftypes.NewValue(testType, nil),
+			expected: tftypes.NewValue(testType, nil),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			equality := &tfequal.Registry{}
+			equality.AddPath(tftypes.NewAttributePath().WithAttributeName("policy"), tfequal.JSON())
+			m := Merger{Schema: testSchema, Equality: equality}
+			got, err := m.Merge(context.Background(), tc.config, tc.prior, tc.remote)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestMergerOverride(t *testing.T) {
+	m := Merger{Schema: testSchema}
+	m.Override(tftypes.NewAttributePath().WithAttributeName("name"), PreferRemote())
+	m.Override(tftypes.NewAttributePath().WithAttributeName("rule").WithAttributeName("rule_id"), PreferPrior())
+	got, err := m.Merge(context.Background(),
+		testValue(nil, "web", nil, nil, nil, testRule(80, nil)),
+		testValue("i-1", "web", nil, nil, nil, testRule(80, "r-1")),
+		testValue("i-1", "WEB", nil, nil, nil, testRule(80, "r-2")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := testValue("i-1", "WEB", nil, nil, nil, testRule(80, "r-1"))
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	m.Override(tftypes.NewAttributePath().WithAttributeName("name"), func(ctx context.Context, path *tftypes.AttributePath, vals Values, merged tftypes.Value) (tftypes.Value, error) {
+		return tftypes.NewValue(tftypes.Bool, true), nil
+	})
+	_, err = m.Merge(context.Background(), tftypes.NewValue(testType, nil), tftypes.NewValue(testType, nil), testValue(nil, "web", nil, nil, nil))
+	expectedErr := `AttributeName("name"): override returned a value of type tftypes.Bool, expected tftypes.String`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
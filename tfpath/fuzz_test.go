@@ -0,0 +1,52 @@
+package tfpath
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// FuzzParse checks that Parse never panics, and that anything it parses is
+// written back as a string that parses to the same path.
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"", "name", `rule[3].ports["http"]`, `"dns name"[-1]`, `a["\xff\n"].b`} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		path, err := Parse(s)
+		if err != nil {
+			return
+		}
+		again, err := Parse(String(path))
+		if err != nil {
+			t.Fatalf("error parsing %q, written from %q: %s", String(path), s, err)
+		}
+		if !again.Equal(path) {
+			t.Errorf("expected %s, got %s", path, again)
+		}
+	})
+}
+
+// FuzzString checks that paths built from arbitrary names and keys are
+// written as strings that parse back to the same path.
+func FuzzString(f *testing.F) {
+	f.Add("rule", "http", int64(3))
+	f.Add("dns name", "", int64(-1))
+	f.Add("2fa", "\"\\", int64(0))
+	f.Fuzz(func(t *testing.T, name, key string, i int64) {
+		path := tftypes.NewAttributePath().
+			WithAttributeName(name).
+			WithElementKeyInt(int(i)).
+			WithAttributeName(key).
+			WithElementKeyString(key).
+			WithElementKeyString(name)
+		s := String(path)
+		parsed, err := Parse(s)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", s, err)
+		}
+		if !parsed.Equal(path) {
+			t.Errorf("expected %s, got %s", path, parsed)
+		}
+	})
+}
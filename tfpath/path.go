@@ -0,0 +1,221 @@
+// Package tfpath converts between *tftypes.AttributePaths and strings like
+// `rule[3].ports["http"]`, the way paths are written in Terraform's
+// configuration language, so they can be written in provider configuration,
+// messages, and code more readably than by chaining tftypes builder methods.
+//
+// A path is written as its steps in order:
+//
+//   - Attribute names are written after a dot, like `.ports`, except for a
+//     leading attribute name, which is written without one. Names that
+//     aren't identifiers, made of letters, digits, underscores, and hyphens
+//     and not starting with a digit or hyphen, are written as quoted
+//     strings, like `."dns name"`.
+//   - Element keys of lists and tuples are written as integers in brackets,
+//     like `[3]`.
+//   - Element keys of maps are written as quoted strings in brackets, like
+//     `["http"]`.
+//   - Elements of sets, which are identified by their value, are written
+//     as `[...]`, following Terraform, and can't be parsed.
+//
+// Quoted strings use Go's syntax, so `"` and `\` are escaped with `\`, and
+// strings that aren't valid UTF-8 can be represented. The empty path, which
+// refers to the value as a whole, is written as an empty string.
+package tfpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Parse returns the path written as `s`.
+func Parse(s string) (*tftypes.AttributePath, error) {
+	p := &parser{s: s}
+	path := tftypes.NewAttributePath()
+	for p.pos < len(s) {
+		var err error
+		path, err = p.step(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", s, err)
+		}
+	}
+	return path, nil
+}
+
+// MustParse is like Parse, but panics if `s` can't be parsed, for paths
+// known at compile time.
+func MustParse(s string) *tftypes.AttributePath {
+	path, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// String returns `path` written as a string. Nil paths are written as empty
+// strings.
+func String(path *tftypes.AttributePath) string {
+	if path == nil {
+		return ""
+	}
+	var b strings.Builder
+	for i, step := range path.Steps() {
+		switch s := step.(type) {
+		case tftypes.AttributeName:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			if isIdentifier(string(s)) {
+				b.WriteString(string(s))
+			} else {
+				b.WriteString(strconv.Quote(string(s)))
+			}
+		case tftypes.ElementKeyString:
+			b.WriteString("[" + strconv.Quote(string(s)) + "]")
+		case tftypes.ElementKeyInt:
+			b.WriteString("[" + strconv.FormatInt(int64(s), 10) + "]")
+		case tftypes.ElementKeyValue:
+			b.WriteString("[...]")
+		}
+	}
+	return b.String()
+}
+
+// isIdentifier returns true if `s` can be written without quotes.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case (r == '-' || unicode.IsDigit(r)) && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+// step parses the next step of the path, and returns `path` with it
+// appended.
+func (p *parser) step(path *tftypes.AttributePath) (*tftypes.AttributePath, error) {
+	switch {
+	case p.s[p.pos] == '[':
+		return p.elementKey(path)
+	case p.s[p.pos] == '.':
+		if p.pos == 0 {
+			return nil, p.errorf("path can't start with %q", '.')
+		}
+		p.pos++
+		return p.attributeName(path)
+	case p.pos == 0:
+		return p.attributeName(path)
+	}
+	return nil, p.errorf("expected %q or %q, got %s", '.', '[', p.next())
+}
+
+func (p *parser) attributeName(path *tftypes.AttributePath) (*tftypes.AttributePath, error) {
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		name, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return path.WithAttributeName(name), nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if r != '_' && !unicode.IsLetter(r) && (p.pos == start || (r != '-' && !unicode.IsDigit(r))) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected attribute name, got %s", p.next())
+	}
+	return path.WithAttributeName(p.s[start:p.pos]), nil
+}
+
+func (p *parser) elementKey(path *tftypes.AttributePath) (*tftypes.AttributePath, error) {
+	p.pos++
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		key, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.closeBracket(); err != nil {
+			return nil, err
+		}
+		return path.WithElementKeyString(key), nil
+	}
+	if strings.HasPrefix(p.s[p.pos:], "...]") {
+		return nil, p.errorf("set elements can't be parsed")
+	}
+	start := p.pos
+	if p.pos < len(p.s) && p.s[p.pos] == '-' {
+		p.pos++
+	}
+	digits := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == digits {
+		p.pos = start
+		return nil, p.errorf("expected element key, got %s", p.next())
+	}
+	text := p.s[start:p.pos]
+	key, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("element key %s is out of range", text)
+	}
+	if err := p.closeBracket(); err != nil {
+		return nil, err
+	}
+	return path.WithElementKeyInt(int(key)), nil
+}
+
+func (p *parser) closeBracket() error {
+	if p.pos == len(p.s) || p.s[p.pos] != ']' {
+		return p.errorf("expected %q, got %s", ']', p.next())
+	}
+	p.pos++
+	return nil
+}
+
+// quoted parses a quoted string.
+func (p *parser) quoted() (string, error) {
+	quoted, err := strconv.QuotedPrefix(p.s[p.pos:])
+	if err != nil {
+		return "", p.errorf("invalid quoted string")
+	}
+	s, err := strconv.Unquote(quoted)
+	if err != nil {
+		return "", p.errorf("invalid quoted string")
+	}
+	p.pos += len(quoted)
+	return s, nil
+}
+
+// next describes the rest of the string, for errors.
+func (p *parser) next() string {
+	if p.pos == len(p.s) {
+		return "end of path"
+	}
+	r, _ := utf8.DecodeRuneInString(p.s[p.pos:])
+	return strconv.QuoteRune(r)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,138 @@
+package tfpath
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestParseAndString(t *testing.T) {
+	root := tftypes.NewAttributePath()
+
+	type testCase struct {
+		s    string
+		path *tftypes.AttributePath
+	}
+	cases := map[string]testCase{
+		"empty": {
+			s:    "",
+			path: root,
+		},
+		"attribute": {
+			s:    "name",
+			path: root.WithAttributeName("name"),
+		},
+		"nested": {
+			s:    `rule[3].ports["http"]`,
+			path: root.WithAttributeName("rule").WithElementKeyInt(3).WithAttributeName("ports").WithElementKeyString("http"),
+		},
+		"identifiers": {
+			s:    "_private.dns-name.v2.ÿ",
+			path: root.WithAttributeName("_private").WithAttributeName("dns-name").WithAttributeName("v2").WithAttributeName("ÿ"),
+		},
+		"quoted-names": {
+			s:    `"dns name"."2fa"."-"."".x`,
+			path: root.WithAttributeName("dns name").WithAttributeName("2fa").WithAttributeName("-").WithAttributeName("").WithAttributeName("x"),
+		},
+		"element-keys": {
+			s:    `[0][-1]["a\"b\\c"][""]["\xff"]`,
+			path: root.WithElementKeyInt(0).WithElementKeyInt(-1).WithElementKeyString(`a"b\c`).WithElementKeyString("").WithElementKeyString("\xff"),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			path, err := Parse(tc.s)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !path.Equal(tc.path) {
+				t.Errorf("expected %s, got %s", tc.path, path)
+			}
+			if s := String(tc.path); s != tc.s {
+				t.Errorf("expected %q, got %q", tc.s, s)
+			}
+		})
+	}
+}
+
+func TestStringSetElement(t *testing.T) {
+	path := tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyValue(tftypes.NewValue(tftypes.String, "a"))
+	if s := String(path); s != "tags[...]" {
+		t.Errorf("expected %q, got %q", "tags[...]", s)
+	}
+	if s := String(nil); s != "" {
+		t.Errorf("expected empty string, got %q", s)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	type testCase struct {
+		s           string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"leading-dot": {
+			s:           ".name",
+			expectedErr: `invalid path ".name": at offset 0: path can't start with '.'`,
+		},
+		"trailing-dot": {
+			s:           "name.",
+			expectedErr: `invalid path "name.": at offset 5: expected attribute name, got end of path`,
+		},
+		"leading-digit": {
+			s:           "rule.2fa",
+			expectedErr: `invalid path "rule.2fa": at offset 5: expected attribute name, got '2'`,
+		},
+		"missing-separator": {
+			s:           "rule[0]name",
+			expectedErr: `invalid path "rule[0]name": at offset 7: expected '.' or '[', got 'n'`,
+		},
+		"space": {
+			s:           "dns name",
+			expectedErr: `invalid path "dns name": at offset 3: expected '.' or '[', got ' '`,
+		},
+		"unclosed-bracket": {
+			s:           "rule[0",
+			expectedErr: `invalid path "rule[0": at offset 6: expected ']', got end of path`,
+		},
+		"empty-key": {
+			s:           "rule[]",
+			expectedErr: `invalid path "rule[]": at offset 5: expected element key, got ']'`,
+		},
+		"sign-only": {
+			s:           "rule[-]",
+			expectedErr: `invalid path "rule[-]": at offset 5: expected element key, got '-'`,
+		},
+		"out-of-range": {
+			s:           "rule[9223372036854775808]",
+			expectedErr: `invalid path "rule[9223372036854775808]": at offset 5: element key 9223372036854775808 is out of range`,
+		},
+		"unterminated-quote": {
+			s:           `tags["a]`,
+			expectedErr: `invalid path "tags[\"a]": at offset 5: invalid quoted string`,
+		},
+		"set-element": {
+			s:           "tags[...]",
+			expectedErr: `invalid path "tags[...]": at offset 5: set elements can't be parsed`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tc.s)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic, got none")
+		}
+	}()
+	MustParse("rule[")
+}
@@ -0,0 +1,310 @@
+package tfplan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalue"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Action describes what applying a plan does to a resource or to one of its
+// values.
+type Action int
+
+const (
+	ActionNoOp Action = iota
+	ActionCreate
+	ActionUpdate
+	ActionDelete
+	ActionReplace
+)
+
+// String returns the name of the Action.
+func (a Action) String() string {
+	switch a {
+	case ActionNoOp:
+		return "no-op"
+	case ActionCreate:
+		return "create"
+	case ActionUpdate:
+		return "update"
+	case ActionDelete:
+		return "delete"
+	case ActionReplace:
+		return "replace"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// ReplaceFunc reports whether the change to the value at `path` from
+// `prior` to `planned` requires the resource to be replaced.
+type ReplaceFunc func(path *tftypes.AttributePath, prior, planned tftypes.Value) bool
+
+// ReplacePaths returns a ReplaceFunc for changes to the values at, or
+// nested within, `paths`, like those returned by Planner.Plan. Element keys
+// in `paths` are ignored.
+func ReplacePaths(paths ...*tftypes.AttributePath) ReplaceFunc {
+	prefixes := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		prefixes = append(prefixes, attrpath.Names(path))
+	}
+	return func(path *tftypes.AttributePath, prior, planned tftypes.Value) bool {
+		names := attrpath.Names(path)
+		for _, prefix := range prefixes {
+			if attrpath.HasPrefix(names, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Change is a value that differs between the prior and planned state.
+type Change struct {
+	Path    *tftypes.AttributePath
+	Action  Action
+	Prior   tftypes.Value
+	Planned tftypes.Value
+}
+
+// ChangeSet describes the changes a plan makes to a resource.
+type ChangeSet struct {
+	// Action is what applying the plan does to the resource as a whole.
+	Action Action
+
+	// Changes are the values that differ between the prior and planned
+	// state, in the order of their paths, with attribute names and map
+	// keys sorted. Objects, maps, and lists and tuples of the same length
+	// are compared element by element, so each Change is for the most
+	// deeply nested value that changed. Sets, and lists and tuples whose
+	// lengths differ, change as a whole.
+	Changes []Change
+}
+
+// NewChangeSet returns the ChangeSet for a plan from `prior` to `planned`.
+// When the resource is being updated, Changes are classified as
+// ActionReplace if any of `replace` reports that they require replacement,
+// and the resource is replaced if any of its Changes is.
+func NewChangeSet(prior, planned tftypes.Value, replace ...ReplaceFunc) (*ChangeSet, error) {
+	cs := &ChangeSet{}
+	switch {
+	case prior.IsNull() && planned.IsNull():
+		return cs, nil
+	case prior.IsNull():
+		cs.Action = ActionCreate
+	case planned.IsNull():
+		cs.Action = ActionDelete
+	}
+	d := differ{}
+	if cs.Action == ActionNoOp {
+		d.replace = replace
+	}
+	if err := d.diff(tftypes.NewAttributePath(), prior, planned); err != nil {
+		return nil, err
+	}
+	cs.Changes = d.changes
+	if cs.Action != ActionNoOp {
+		return cs, nil
+	}
+	for _, c := range cs.Changes {
+		if c.Action == ActionReplace {
+			cs.Action = ActionReplace
+			return cs, nil
+		}
+		cs.Action = ActionUpdate
+	}
+	return cs, nil
+}
+
+// ActionAt returns the Action for the value at `path`: the Action of the
+// Change for it or for the value it's nested within, ActionUpdate if values
+// nested within it change, or ActionNoOp.
+func (cs *ChangeSet) ActionAt(path *tftypes.AttributePath) Action {
+	action := ActionNoOp
+	steps := path.Steps()
+	for _, c := range cs.Changes {
+		changeSteps := c.Path.Steps()
+		switch {
+		case stepsHavePrefix(steps, changeSteps):
+			return c.Action
+		case stepsHavePrefix(changeSteps, steps):
+			if c.Action == ActionReplace {
+				return ActionReplace
+			}
+			action = ActionUpdate
+		}
+	}
+	return action
+}
+
+// HasChange returns true if the value at `path`, or any value nested within
+// it, changes.
+func (cs *ChangeSet) HasChange(path *tftypes.AttributePath) bool {
+	return cs.ActionAt(path) != ActionNoOp
+}
+
+// RequiresReplace returns the paths of the Changes that require the
+// resource to be replaced, for PlanResourceChange responses.
+func (cs *ChangeSet) RequiresReplace() []*tftypes.AttributePath {
+	var paths []*tftypes.AttributePath
+	for _, c := range cs.Changes {
+		if c.Action == ActionReplace {
+			paths = append(paths, c.Path)
+		}
+	}
+	return paths
+}
+
+// String returns the ChangeSet as text for logging, with sensitive values
+// redacted as described by Render.
+func (cs *ChangeSet) String() string {
+	return cs.Render(nil)
+}
+
+// Render returns the ChangeSet as text, with the resource's Action on the
+// first line followed by a line for each Change. The values of Changes at,
+// containing, or nested within `sensitive` paths are written as
+// "(sensitive value)"; element keys in `sensitive` are ignored.
+func (cs *ChangeSet) Render(sensitive []*tftypes.AttributePath) string {
+	var b strings.Builder
+	b.WriteString(cs.Action.String())
+	b.WriteString("\n")
+	for _, c := range cs.Changes {
+		redact := false
+		names := attrpath.Names(c.Path)
+		for _, s := range sensitive {
+			if attrpath.HasPrefix(names, attrpath.Names(s)) || attrpath.HasPrefix(attrpath.Names(s), names) {
+				redact = true
+			}
+		}
+		path := tfpath.String(c.Path)
+		if path == "" {
+			path = "(resource)"
+		}
+		fmt.Fprintf(&b, "  %s %s: %s -> %s\n", c.Action, path, render(c.Prior, redact), render(c.Planned, redact))
+	}
+	return b.String()
+}
+
+func render(val tftypes.Value, redact bool) string {
+	if redact {
+		return "(sensitive value)"
+	}
+	return strings.ReplaceAll(tfvalue.Format(val), "\n", "\n  ")
+}
+
+type differ struct {
+	replace []ReplaceFunc
+	changes []Change
+}
+
+func (d *differ) diff(path *tftypes.AttributePath, prior, planned tftypes.Value) error {
+	if prior.Equal(planned) {
+		return nil
+	}
+	if prior.IsNull() || !prior.IsKnown() || planned.IsNull() || !planned.IsKnown() || !prior.Type().Equal(planned.Type()) {
+		d.add(path, prior, planned)
+		return nil
+	}
+	typ := prior.Type()
+	switch {
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		var p, v map[string]tftypes.Value
+		if err := prior.As(&p); err != nil {
+			return path.NewError(err)
+		}
+		if err := planned.As(&v); err != nil {
+			return path.NewError(err)
+		}
+		var null tftypes.Value
+		if m, ok := typ.(tftypes.Map); ok {
+			null = tftypes.NewValue(m.ElementType, nil)
+		}
+		keys := make([]string, 0, len(p))
+		for k := range p {
+			keys = append(keys, k)
+		}
+		for k := range v {
+			if _, ok := p[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			next := path.WithAttributeName(k)
+			if typ.Is(tftypes.Map{}) {
+				next = path.WithElementKeyString(k)
+			}
+			pv, ok := p[k]
+			if !ok {
+				pv = null
+			}
+			vv, ok := v[k]
+			if !ok {
+				vv = null
+			}
+			if err := d.diff(next, pv, vv); err != nil {
+				return err
+			}
+		}
+		return nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Tuple{}):
+		var p, v []tftypes.Value
+		if err := prior.As(&p); err != nil {
+			return path.NewError(err)
+		}
+		if err := planned.As(&v); err != nil {
+			return path.NewError(err)
+		}
+		if len(p) != len(v) {
+			d.add(path, prior, planned)
+			return nil
+		}
+		for i := range p {
+			if err := d.diff(path.WithElementKeyInt(i), p[i], v[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	d.add(path, prior, planned)
+	return nil
+}
+
+func (d *differ) add(path *tftypes.AttributePath, prior, planned tftypes.Value) {
+	action := ActionUpdate
+	switch {
+	case prior.IsNull():
+		action = ActionCreate
+	case planned.IsNull():
+		action = ActionDelete
+	}
+	for _, f := range d.replace {
+		if f(path, prior, planned) {
+			action = ActionReplace
+			break
+		}
+	}
+	d.changes = append(d.changes, Change{
+		Path:    path,
+		Action:  action,
+		Prior:   prior,
+		Planned: planned,
+	})
+}
+
+func stepsHavePrefix(steps, prefix []tftypes.AttributePathStep) bool {
+	if len(prefix) > len(steps) {
+		return false
+	}
+	for i := range prefix {
+		if !prefix[i].Equal(steps[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package tfplan
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestNewChangeSet(t *testing.T) {
+	type testCase struct {
+		prior    tftypes.Value
+		planned  tftypes.Value
+		replace  []ReplaceFunc
+		expected string
+	}
+	cases := map[string]testCase{
+		"no-op": {
+			prior:    testValue("i-1", "web", nil, "e1", testRule(80, "r-1")),
+			planned:  testValue("i-1", "web", nil, "e1", testRule(80, "r-1")),
+			expected: "no-op\n",
+		},
+		"create": {
+			prior:    tftypes.NewValue(testType, nil),
+			planned:  testValue(tftypes.UnknownValue, "web", nil, nil),
+			expected: "create\n  create (resource): null -> {\n    description = null\n    etag        = null\n    id          = (known after apply)\n    name        = \"web\"\n    rule        = []\n  }\n",
+		},
+		"delete": {
+			prior:    testValue("i-1", "web", nil, nil),
+			planned:  tftypes.NewValue(testType, nil),
+			replace:  []ReplaceFunc{ReplacePaths(tftypes.NewAttributePath())},
+			expected: "delete\n  delete (resource): {\n    description = null\n    etag        = null\n    id          = \"i-1\"\n    name        = \"web\"\n    rule        = []\n  } -> null\n",
+		},
+		"update": {
+			prior:   testValue("i-1", "web", nil, "e1", testRule(80, "r-1")),
+			planned: testValue("i-1", "web", "new", tftypes.UnknownValue, testRule(443, "r-1")),
+			expected: `update
+  create description: null -> "new"
+  update etag: "e1" -> (known after apply)
+  update rule[0].port: 80 -> 443
+`,
+		},
+		"replace": {
+			prior:   testValue("i-1", "web", nil, "e1", testRule(80, "r-1")),
+			planned: testValue("i-1", "app", nil, "e1", testRule(443, "r-1")),
+			replace: []ReplaceFunc{ReplacePaths(tftypes.NewAttributePath().WithAttributeName("rule"))},
+			expected: `replace
+  update name: "web" -> "app"
+  replace rule[0].port: 80 -> 443
+`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			cs, err := NewChangeSet(tc.prior, tc.planned, tc.replace...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, cs.String()); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestChangeSetActionAt(t *testing.T) {
+	cs, err := NewChangeSet(
+		testValue("i-1", "web", nil, "e1", testRule(80, "r-1")),
+		testValue("i-1", "web", "new", "e1", testRule(443, "r-1")),
+		ReplacePaths(tftypes.NewAttributePath().WithAttributeName("rule").WithAttributeName("port")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	type testCase struct {
+		path     *tftypes.AttributePath
+		expected Action
+	}
+	cases := map[string]testCase{
+		"unchanged":  {tftypes.NewAttributePath().WithAttributeName("name"), ActionNoOp},
+		"created":    {tftypes.NewAttributePath().WithAttributeName("description"), ActionCreate},
+		"containing": {tftypes.NewAttributePath().WithAttributeName("rule"), ActionReplace},
+		"changed":    {tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(0).WithAttributeName("port"), ActionReplace},
+		"sibling":    {tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(0).WithAttributeName("rule_id"), ActionNoOp},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if got := cs.ActionAt(tc.path); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+	if diff := cmp.Diff([]string{`AttributeName("rule").ElementKeyInt(0).AttributeName("port")`}, pathStrings(cs.RequiresReplace())); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if cs.HasChange(tftypes.NewAttributePath().WithAttributeName("id")) {
+		t.Error("expected no change to id")
+	}
+}
+
+func TestChangeSetRender(t *testing.T) {
+	cs, err := NewChangeSet(
+		testValue("i-1", "web", "old", "e1"),
+		testValue("i-1", "web", "new", "e1"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got := cs.Render([]*tftypes.AttributePath{tftypes.NewAttributePath().WithAttributeName("description")})
+	expected := "update\n  update description: (sensitive value) -> (sensitive value)\n"
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func pathStrings(paths []*tftypes.AttributePath) []string {
+	var res []string
+	for _, path := range paths {
+		res = append(res, path.String())
+	}
+	return res
+}
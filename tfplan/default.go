@@ -0,0 +1,96 @@
+package tfplan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/hcl"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/parse"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Default returns a Modifier that plans `val` for the attribute when it's
+// not set in the configuration. The attribute must be computed, or
+// Terraform will reject the plan.
+func Default(val tftypes.Value) Modifier {
+	return Describe(DefaultFunc(func(ctx context.Context, req ModifyRequest) (tftypes.Value, error) {
+		return val, nil
+	}), defaultDescription(nil, hcl.Value(val)))
+}
+
+// DefaultFunc returns a Modifier that plans the value returned by `f` for
+// the attribute when it's not set in the configuration. `f` can use
+// req.Resource to compute the default from other attributes.
+//
+// If `f` returns a tftypes.Value without a type, the plan is left
+// unchanged.
+func DefaultFunc(f func(ctx context.Context, req ModifyRequest) (tftypes.Value, error)) Modifier {
+	return ModifierFunc(func(ctx context.Context, req ModifyRequest, resp *ModifyResponse) error {
+		if !req.Attribute.Config.IsNull() {
+			return nil
+		}
+		val, err := f(ctx, req)
+		if err != nil {
+			return err
+		}
+		if val.Type() == nil {
+			return nil
+		}
+		if !val.Type().UsableAs(req.Attribute.Planned.Type()) {
+			return fmt.Errorf("default value of type %s can't be used for an attribute of type %s", val.Type(), req.Attribute.Planned.Type())
+		}
+		resp.Planned = val
+		return nil
+	})
+}
+
+// EnvDefault returns a Modifier that plans the value of the first of the
+// environment variables `names` that is set and not empty for the
+// attribute, when it's not set in the configuration. If none of them are
+// set, `fallback` is planned instead, unless it's the zero value.
+//
+// The environment variable is parsed according to the type of the
+// attribute, which must be a string, number, or bool.
+func EnvDefault(fallback tftypes.Value, names ...string) Modifier {
+	var fallbackDesc string
+	if fallback.Type() != nil {
+		fallbackDesc = hcl.Value(fallback)
+	}
+	return Describe(DefaultFunc(func(ctx context.Context, req ModifyRequest) (tftypes.Value, error) {
+		val, ok, err := parse.Env(req.Attribute.Planned.Type(), names)
+		if err != nil || ok {
+			return val, err
+		}
+		return fallback, nil
+	}), defaultDescription(names, fallbackDesc))
+}
+
+// DefaultFrom returns a Modifier that plans the planned value of the
+// attribute `name` for the attribute, when it's not set in the
+// configuration. `name` is resolved relative to the attribute being
+// modified, so within nested blocks it refers to an attribute of the same
+// element.
+func DefaultFrom(name string) Modifier {
+	return Describe(DefaultFunc(func(ctx context.Context, req ModifyRequest) (tftypes.Value, error) {
+		path := req.Path.WithoutLastStep().WithAttributeName(name)
+		return valueAt(req.Resource.Planned, path, req.Attribute.Planned.Type()), nil
+	}), fmt.Sprintf("Defaults to the value of `%s`.", name))
+}
+
+// defaultDescription describes a default taken from the first of the
+// environment variables `envs` that's set, or `fallback`, an HCL
+// expression, if it isn't empty.
+func defaultDescription(envs []string, fallback string) string {
+	if len(envs) == 0 {
+		return fmt.Sprintf("Defaults to `%s`.", fallback)
+	}
+	desc := "Defaults to the value of the `" + strings.Join(envs, "`, `") + "` environment variable"
+	if len(envs) > 1 {
+		desc = "Defaults to the value of the first of the `" + strings.Join(envs, "`, `") + "` environment variables set"
+	}
+	if fallback == "" {
+		return desc + "."
+	}
+	return fmt.Sprintf("%s, or `%s` otherwise.", desc, fallback)
+}
@@ -0,0 +1,161 @@
+package tfplan
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDefaults(t *testing.T) {
+	type testCase struct {
+		path        *tftypes.AttributePath
+		modifier    Modifier
+		env         map[string]string
+		config      tftypes.Value
+		expected    tftypes.Value
+		expectedErr string
+	}
+	etag := tftypes.NewAttributePath().WithAttributeName("etag")
+	ruleID := tftypes.NewAttributePath().WithAttributeName("rule").WithAttributeName("rule_id")
+	cases := map[string]testCase{
+		"static": {
+			path:     etag,
+			modifier: Default(tftypes.NewValue(tftypes.String, "none")),
+			config:   testValue(nil, "foo", nil, nil),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, "none"),
+		},
+		"static-configured": {
+			path:     etag,
+			modifier: Default(tftypes.NewValue(tftypes.String, "none")),
+			config:   testValue(nil, "foo", nil, "e2"),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, "e2"),
+		},
+		"static-wrong-type": {
+			path:        etag,
+			modifier:    Default(tftypes.NewValue(tftypes.Number, big.NewFloat(1))),
+			config:      testValue(nil, "foo", nil, nil),
+			expectedErr: `AttributeName("etag"): default value of type tftypes.Number can't be used for an attribute of type tftypes.String`,
+		},
+		"env": {
+			path:     etag,
+			modifier: EnvDefault(tftypes.NewValue(tftypes.String, "none"), "TFPLAN_TEST_A", "TFPLAN_TEST_B"),
+			env:      map[string]string{"TFPLAN_TEST_B": "from-env"},
+			config:   testValue(nil, "foo", nil, nil),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, "from-env"),
+		},
+		"env-fallback": {
+			path:     etag,
+			modifier: EnvDefault(tftypes.NewValue(tftypes.String, "none"), "TFPLAN_TEST_A"),
+			config:   testValue(nil, "foo", nil, nil),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, "none"),
+		},
+		"env-no-fallback": {
+			path:     etag,
+			modifier: EnvDefault(tftypes.Value{}, "TFPLAN_TEST_A"),
+			config:   testValue(nil, "foo", nil, nil),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, tftypes.UnknownValue),
+		},
+		"env-nested": {
+			path:     ruleID,
+			modifier: EnvDefault(tftypes.Value{}, "TFPLAN_TEST_A"),
+			env:      map[string]string{"TFPLAN_TEST_A": "r0"},
+			config:   testValue(nil, "foo", nil, nil, testRule(80, nil), testRule(443, nil)),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, tftypes.UnknownValue, testRule(80, "r0"), testRule(443, "r0")),
+		},
+		"from": {
+			path:     etag,
+			modifier: DefaultFrom("name"),
+			config:   testValue(nil, "foo", nil, nil),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, "foo"),
+		},
+		"from-wrong-type": {
+			path:        ruleID,
+			modifier:    DefaultFrom("port"),
+			config:      testValue(nil, "foo", nil, nil, testRule(80, nil)),
+			expectedErr: `AttributeName("rule").ElementKeyInt(0).AttributeName("rule_id"): default value of type tftypes.Number can't be used for an attribute of type tftypes.String`,
+		},
+		"func": {
+			path: etag,
+			modifier: DefaultFunc(func(ctx context.Context, req ModifyRequest) (tftypes.Value, error) {
+				var name string
+				if err := valueAt(req.Resource.Config, tftypes.NewAttributePath().WithAttributeName("name"), tftypes.String).As(&name); err != nil {
+					return tftypes.Value{}, err
+				}
+				return tftypes.NewValue(tftypes.String, name+"-etag"), nil
+			}),
+			config:   testValue(nil, "foo", nil, nil),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, "foo-etag"),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+			r := &Registry{}
+			r.Add(test.path, test.modifier)
+			p := Planner{Schema: testSchema, Modifiers: r}
+			got, _, err := p.Plan(context.Background(), tftypes.NewValue(testType, nil), test.config, test.config)
+			if test.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", test.expectedErr)
+				}
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDescription(t *testing.T) {
+	type testCase struct {
+		modifiers []Modifier
+		expected  string
+	}
+	cases := map[string]testCase{
+		"default": {
+			modifiers: []Modifier{Default(tftypes.NewValue(tftypes.Number, big.NewFloat(80)))},
+			expected:  "Defaults to `80`.",
+		},
+		"env-default": {
+			modifiers: []Modifier{EnvDefault(tftypes.NewValue(tftypes.String, "us-east-1"), "EXAMPLE_REGION")},
+			expected:  "Defaults to the value of the `EXAMPLE_REGION` environment variable, or `\"us-east-1\"` otherwise.",
+		},
+		"env-no-fallback": {
+			modifiers: []Modifier{EnvDefault(tftypes.Value{}, "A", "B")},
+			expected:  "Defaults to the value of the first of the `A`, `B` environment variables set.",
+		},
+		"default-from": {
+			modifiers: []Modifier{DefaultFrom("name")},
+			expected:  "Defaults to the value of `name`.",
+		},
+		"mixed": {
+			modifiers: []Modifier{UseStateForUnknown(), RequiresReplace(), Describe(UseStateForUnknown(), "Set by the server.")},
+			expected:  "Changing this forces the resource to be replaced. Set by the server.",
+		},
+		"tags": {
+			modifiers: []Modifier{tagDefault([]string{"EXAMPLE_REGION"}, "us-east-1", true)},
+			expected:  "Defaults to the value of the `EXAMPLE_REGION` environment variable, or `us-east-1` otherwise.",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if got := Description(tc.modifiers...); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
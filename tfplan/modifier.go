@@ -0,0 +1,117 @@
+package tfplan
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/describe"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Values holds the prior state, configuration, and planned state of a
+// resource or of one of its attributes.
+type Values struct {
+	Prior   tftypes.Value
+	Config  tftypes.Value
+	Planned tftypes.Value
+}
+
+// ModifyRequest is passed to a Modifier for each attribute it applies to.
+type ModifyRequest struct {
+	// Path is the path to the attribute being modified.
+	Path *tftypes.AttributePath
+
+	// Attribute holds the values of the attribute being modified.
+	// Attribute.Planned reflects any modifications made by Modifiers that
+	// ran earlier.
+	Attribute Values
+
+	// Resource holds the values of the whole resource, as they were
+	// before any Modifiers ran.
+	Resource Values
+}
+
+// ModifyResponse is populated by a Modifier to describe its changes.
+type ModifyResponse struct {
+	// Planned is the planned value of the attribute. It is initialised
+	// to the incoming planned value, so Modifiers that don't want to
+	// change the plan can leave it alone.
+	Planned tftypes.Value
+
+	// RequiresReplace should be set to true if the change to the
+	// attribute can't be applied in-place.
+	RequiresReplace bool
+}
+
+// Modifier adjusts the planned value of an attribute after the default
+// planning rules of Plan have been applied.
+type Modifier interface {
+	Modify(ctx context.Context, req ModifyRequest, resp *ModifyResponse) error
+}
+
+// ModifierFunc is a function that implements Modifier.
+type ModifierFunc func(ctx context.Context, req ModifyRequest, resp *ModifyResponse) error
+
+// Modify calls `f`.
+func (f ModifierFunc) Modify(ctx context.Context, req ModifyRequest, resp *ModifyResponse) error {
+	return f(ctx, req, resp)
+}
+
+// Describer is implemented by Modifiers that can describe their effect, for
+// documentation. Modifiers returned by this package implement it where
+// their effect is worth documenting, and other Modifiers can be given a
+// description with Describe.
+type Describer = describe.Describer
+
+// described is a Modifier with a description.
+type described struct {
+	Modifier
+	description string
+}
+
+func (d described) Description() string {
+	return d.description
+}
+
+// Describe returns `modifier` with `description`, so it's documented by
+// docsgen.
+func Describe(modifier Modifier, description string) Modifier {
+	return described{Modifier: modifier, description: description}
+}
+
+// Description returns the description of `modifiers`, joined by spaces, or
+// "" if none of them implement Describer.
+func Description(modifiers ...Modifier) string {
+	return describe.Join(modifiers...)
+}
+
+// RequiresReplace returns a Modifier that marks the resource as requiring
+// replacement when the attribute's planned value differs from its prior
+// value. It has no effect when the resource is being created.
+func RequiresReplace() Modifier {
+	return Describe(ModifierFunc(func(ctx context.Context, req ModifyRequest, resp *ModifyResponse) error {
+		if req.Resource.Prior.IsNull() {
+			return nil
+		}
+		if !resp.Planned.Equal(req.Attribute.Prior) {
+			resp.RequiresReplace = true
+		}
+		return nil
+	}), "Changing this forces the resource to be replaced.")
+}
+
+// UseStateForUnknown returns a Modifier that replaces an unknown planned
+// value with the attribute's prior value. It's intended for computed
+// attributes, like IDs, that are set when the resource is created and never
+// change afterwards.
+func UseStateForUnknown() Modifier {
+	return ModifierFunc(func(ctx context.Context, req ModifyRequest, resp *ModifyResponse) error {
+		if resp.Planned.IsKnown() || req.Resource.Prior.IsNull() || req.Attribute.Prior.IsNull() {
+			return nil
+		}
+		if !req.Attribute.Config.IsKnown() {
+			return nil
+		}
+		resp.Planned = req.Attribute.Prior
+		return nil
+	})
+}
@@ -0,0 +1,88 @@
+package tfplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestModifiers(t *testing.T) {
+	type testCase struct {
+		path            *tftypes.AttributePath
+		modifier        Modifier
+		prior           tftypes.Value
+		config          tftypes.Value
+		expected        tftypes.Value
+		requiresReplace bool
+	}
+	prior := testValue("abc", "foo", "desc", "e1", testRule(80, "r1"))
+	cases := map[string]testCase{
+		"requires-replace-changed": {
+			path:            tftypes.NewAttributePath().WithAttributeName("name"),
+			modifier:        RequiresReplace(),
+			prior:           prior,
+			config:          testValue(nil, "bar", "desc", nil, testRule(80, nil)),
+			expected:        testValue(tftypes.UnknownValue, "bar", "desc", tftypes.UnknownValue, testRule(80, tftypes.UnknownValue)),
+			requiresReplace: true,
+		},
+		"requires-replace-unchanged": {
+			path:     tftypes.NewAttributePath().WithAttributeName("name"),
+			modifier: RequiresReplace(),
+			prior:    prior,
+			config:   testValue(nil, "foo", nil, nil, testRule(80, nil)),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, tftypes.UnknownValue, testRule(80, tftypes.UnknownValue)),
+		},
+		"requires-replace-create": {
+			path:     tftypes.NewAttributePath().WithAttributeName("name"),
+			modifier: RequiresReplace(),
+			prior:    tftypes.NewValue(testType, nil),
+			config:   testValue(nil, "foo", nil, nil),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, tftypes.UnknownValue),
+		},
+		"use-state-for-unknown": {
+			path:     tftypes.NewAttributePath().WithAttributeName("id"),
+			modifier: UseStateForUnknown(),
+			prior:    prior,
+			config:   testValue(nil, "bar", "desc", nil, testRule(80, nil)),
+			expected: testValue("abc", "bar", "desc", tftypes.UnknownValue, testRule(80, tftypes.UnknownValue)),
+		},
+		"use-state-for-unknown-create": {
+			path:     tftypes.NewAttributePath().WithAttributeName("id"),
+			modifier: UseStateForUnknown(),
+			prior:    tftypes.NewValue(testType, nil),
+			config:   testValue(nil, "foo", nil, nil),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, tftypes.UnknownValue),
+		},
+		"use-state-for-unknown-nested": {
+			path:     tftypes.NewAttributePath().WithAttributeName("rule").WithAttributeName("rule_id"),
+			modifier: UseStateForUnknown(),
+			prior:    prior,
+			config:   testValue(nil, "bar", "desc", nil, testRule(80, nil), testRule(443, nil)),
+			expected: testValue(tftypes.UnknownValue, "bar", "desc", tftypes.UnknownValue, testRule(80, "r1"), testRule(443, tftypes.UnknownValue)),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			r := &Registry{}
+			r.Add(test.path, test.modifier)
+			p := Planner{Schema: testSchema, Modifiers: r}
+			got, requiresReplace, err := p.Plan(context.Background(), test.prior, test.config, test.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			var expectedReplace []*tftypes.AttributePath
+			if test.requiresReplace {
+				expectedReplace = []*tftypes.AttributePath{test.path}
+			}
+			if diff := cmp.Diff(expectedReplace, requiresReplace); diff != "" {
+				t.Errorf("unexpected requires replace diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+// Package tfplan provides helpers for implementing PlanResourceChange.
+//
+// The most common source of "Provider produced invalid plan" and "Provider
+// produced inconsistent result after apply" errors in providers built
+// directly on terraform-plugin-go is a planning implementation that doesn't
+// follow the rules Terraform expects: attributes set in configuration must be
+// planned as configured, attributes that are neither configured nor computed
+// must be planned as null, and computed attributes that the provider can't
+// predict must be planned as unknown. Plan implements those rules for any
+// schema, so providers only need to layer their own adjustments on top.
+package tfplan
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Plan returns the planned new state for a resource described by `schema`,
+// given its prior state, the proposed new state Terraform sent, and its
+// configuration.
+//
+// If the proposed new state is null, the resource is being destroyed and the
+// null value is returned unchanged. Otherwise, the planned state is built
+// from the configuration:
+//
+//   - write-only attributes are null, as Terraform never persists them;
+//
+//   - attributes set in the configuration take their configured value;
+//
+//   - computed attributes not set in the configuration take their value from
+//     the prior state, unless the resource is being created or any configured
+//     value differs from the prior state, in which case they're marked as
+//     unknown;
+//
+//   - all other attributes are null, so removing an attribute from the
+//     configuration removes it from the resource.
+//
+// Nested blocks are planned recursively. Elements of list blocks are matched
+// with the prior state by index, elements of map blocks by key, and elements
+// of set blocks by looking for a prior element that the configured element
+// wouldn't change.
+func Plan(schema *tfprotov5.Schema, prior, proposed, config tftypes.Value) (tftypes.Value, error) {
+	if schema == nil || schema.Block == nil {
+		return tftypes.Value{}, errors.New("cannot plan without a schema")
+	}
+	if proposed.IsNull() {
+		return proposed, nil
+	}
+	planned, err := planBlock(tftypes.NewAttributePath(), schema.Block, prior, config, false)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if prior.IsNull() || !planned.Equal(prior) {
+		planned, err = planBlock(tftypes.NewAttributePath(), schema.Block, prior, config, true)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+	}
+	return planned, nil
+}
+
+// PlanResourceChange implements the PlanResourceChange RPC using Plan,
+// handling the unmarshaling and marshaling of the request and response.
+// Any error is returned as a diagnostic on the response.
+func PlanResourceChange(schema *tfprotov5.Schema, req *tfprotov5.PlanResourceChangeRequest) *tfprotov5.PlanResourceChangeResponse {
+	p := Planner{Schema: schema}
+	return p.PlanResourceChange(context.Background(), req)
+}
+
+// Planner plans resource changes using Plan, then adjusts the result with
+// the Modifiers registered for each attribute.
+type Planner struct {
+	Schema    *tfprotov5.Schema
+	Modifiers *Registry
+
+	// Equality is used to keep the prior value of attributes that aren't
+	// set in the configuration when their planned value is semantically
+	// equal to it. Configured values are always planned as configured, as
+	// Terraform requires.
+	Equality *tfequal.Registry
+}
+
+// Plan returns the planned new state of the resource, and the paths of the
+// attributes whose changes require the resource to be replaced. Modifiers
+// aren't run when the resource is being destroyed.
+func (p *Planner) Plan(ctx context.Context, prior, proposed, config tftypes.Value) (tftypes.Value, []*tftypes.AttributePath, error) {
+	planned, err := Plan(p.Schema, prior, proposed, config)
+	if err != nil {
+		return tftypes.Value{}, nil, err
+	}
+	if planned.IsNull() {
+		return planned, nil, nil
+	}
+	planned, requiresReplace, err := p.modify(ctx, prior, config, planned)
+	if err != nil {
+		return tftypes.Value{}, nil, err
+	}
+	planned, err = p.suppressDiffs(prior, config, planned)
+	if err != nil {
+		return tftypes.Value{}, nil, err
+	}
+	return planned, requiresReplace, nil
+}
+
+func (p *Planner) modify(ctx context.Context, prior, config, planned tftypes.Value) (tftypes.Value, []*tftypes.AttributePath, error) {
+	if p.Modifiers.empty() {
+		return planned, nil, nil
+	}
+	resource := Values{
+		Prior:   prior,
+		Config:  config,
+		Planned: planned,
+	}
+	var requiresReplace []*tftypes.AttributePath
+	planned, err := tftypes.Transform(planned, func(path *tftypes.AttributePath, val tftypes.Value) (tftypes.Value, error) {
+		steps := path.Steps()
+		if len(steps) == 0 {
+			return val, nil
+		}
+		if _, ok := steps[len(steps)-1].(tftypes.AttributeName); !ok {
+			return val, nil
+		}
+		modifiers := p.Modifiers.Modifiers(path)
+		if len(modifiers) == 0 {
+			return val, nil
+		}
+		req := ModifyRequest{
+			Path: path,
+			Attribute: Values{
+				Prior:   valueAt(prior, path, val.Type()),
+				Config:  valueAt(config, path, val.Type()),
+				Planned: val,
+			},
+			Resource: resource,
+		}
+		resp := &ModifyResponse{Planned: val}
+		for _, modifier := range modifiers {
+			if err := modifier.Modify(ctx, req, resp); err != nil {
+				return val, err
+			}
+			req.Attribute.Planned = resp.Planned
+		}
+		if resp.RequiresReplace {
+			requiresReplace = append(requiresReplace, path)
+		}
+		return resp.Planned, nil
+	})
+	if err != nil {
+		return tftypes.Value{}, nil, err
+	}
+	return planned, requiresReplace, nil
+}
+
+func (p *Planner) suppressDiffs(prior, config, planned tftypes.Value) (tftypes.Value, error) {
+	if p.Equality == nil || prior.IsNull() {
+		return planned, nil
+	}
+	return tftypes.Transform(planned, func(path *tftypes.AttributePath, val tftypes.Value) (tftypes.Value, error) {
+		if p.Equality.Func(path, val.Type()) == nil {
+			return val, nil
+		}
+		if !valueAt(config, path, val.Type()).IsNull() {
+			return val, nil
+		}
+		pri := valueAt(prior, path, val.Type())
+		equal, err := p.Equality.Equal(path, pri, val)
+		if err != nil {
+			return val, err
+		}
+		if equal {
+			return pri, nil
+		}
+		return val, nil
+	})
+}
+
+// PlanResourceChange implements the PlanResourceChange RPC using
+// Planner.Plan, handling the unmarshaling and marshaling of the request and
+// response. Any error is returned as a diagnostic on the response.
+func (p *Planner) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) *tfprotov5.PlanResourceChangeResponse {
+	resp := &tfprotov5.PlanResourceChangeResponse{
+		PlannedPrivate: req.PriorPrivate,
+	}
+	typ := p.Schema.ValueType()
+	prior, err := unmarshal(typ, req.PriorState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading prior state", err))
+		return resp
+	}
+	proposed, err := unmarshal(typ, req.ProposedNewState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading proposed new state", err))
+		return resp
+	}
+	config, err := unmarshal(typ, req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading configuration", err))
+		return resp
+	}
+	planned, requiresReplace, err := p.Plan(ctx, prior, proposed, config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error planning resource", err))
+		return resp
+	}
+	dv, err := tfprotov5.NewDynamicValue(typ, planned)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error planning resource", err))
+		return resp
+	}
+	resp.PlannedState = &dv
+	resp.RequiresReplace = requiresReplace
+	return resp
+}
+
+func unmarshal(typ tftypes.Type, dv *tfprotov5.DynamicValue) (tftypes.Value, error) {
+	if dv == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	return dv.Unmarshal(typ)
+}
+
+// valueAt returns the value at `path` in `root`, or a null value of type
+// `typ` if there isn't one.
+func valueAt(root tftypes.Value, path *tftypes.AttributePath, typ tftypes.Type) tftypes.Value {
+	v, _, err := tftypes.WalkAttributePath(root, path)
+	if err != nil {
+		return tftypes.NewValue(typ, nil)
+	}
+	val, ok := v.(tftypes.Value)
+	if !ok {
+		return tftypes.NewValue(typ, nil)
+	}
+	return val
+}
+
+// attrs returns the attributes of an object value, or an empty map if the
+// value is null or unknown.
+func attrs(val tftypes.Value) (map[string]tftypes.Value, error) {
+	res := map[string]tftypes.Value{}
+	if val.IsNull() || !val.IsKnown() {
+		return res, nil
+	}
+	if err := val.As(&res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func planBlock(path *tftypes.AttributePath, block *tfprotov5.SchemaBlock, prior, config tftypes.Value, markUnknown bool) (tftypes.Value, error) {
+	typ := block.ValueType()
+	if !config.IsKnown() {
+		return config, nil
+	}
+	priorAttrs, err := attrs(prior)
+	if err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	configAttrs, err := attrs(config)
+	if err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	planned := make(map[string]tftypes.Value, len(block.Attributes)+len(block.BlockTypes))
+	for _, attr := range block.Attributes {
+		cfg, ok := configAttrs[attr.Name]
+		if !ok {
+			cfg = tftypes.NewValue(attr.ValueType(), nil)
+		}
+		pri, ok := priorAttrs[attr.Name]
+		if !ok {
+			pri = tftypes.NewValue(attr.ValueType(), nil)
+		}
+		switch {
+		case attr.WriteOnly:
+			planned[attr.Name] = tftypes.NewValue(attr.ValueType(), nil)
+		case !cfg.IsNull():
+			planned[attr.Name] = cfg
+		case attr.Computed && markUnknown:
+			planned[attr.Name] = tftypes.NewValue(attr.ValueType(), tftypes.UnknownValue)
+		case attr.Computed:
+			planned[attr.Name] = pri
+		default:
+			planned[attr.Name] = cfg
+		}
+	}
+	for _, nested := range block.BlockTypes {
+		nestedPath := path.WithAttributeName(nested.TypeName)
+		cfg, ok := configAttrs[nested.TypeName]
+		if !ok {
+			cfg = tftypes.NewValue(nested.ValueType(), nil)
+		}
+		pri, ok := priorAttrs[nested.TypeName]
+		if !ok {
+			pri = tftypes.NewValue(nested.ValueType(), nil)
+		}
+		planned[nested.TypeName], err = planNestedBlock(nestedPath, nested, pri, cfg, markUnknown)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+	}
+	return tftypes.NewValue(typ, planned), nil
+}
+
+func planNestedBlock(path *tftypes.AttributePath, nested *tfprotov5.SchemaNestedBlock, prior, config tftypes.Value, markUnknown bool) (tftypes.Value, error) {
+	if !config.IsKnown() || config.IsNull() {
+		return config, nil
+	}
+	typ := nested.ValueType()
+	switch nested.Nesting {
+	case tfprotov5.SchemaNestedBlockNestingModeSingle, tfprotov5.SchemaNestedBlockNestingModeGroup:
+		return planBlock(path, nested.Block, prior, config, markUnknown)
+	case tfprotov5.SchemaNestedBlockNestingModeList:
+		var cfgElems, priorElems []tftypes.Value
+		if err := config.As(&cfgElems); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		if !prior.IsNull() && prior.IsKnown() {
+			if err := prior.As(&priorElems); err != nil {
+				return tftypes.Value{}, path.NewError(err)
+			}
+		}
+		elems := make([]tftypes.Value, 0, len(cfgElems))
+		for i, cfg := range cfgElems {
+			pri := tftypes.NewValue(nested.Block.ValueType(), nil)
+			if i < len(priorElems) {
+				pri = priorElems[i]
+			}
+			elem, err := planBlock(path.WithElementKeyInt(i), nested.Block, pri, cfg, markUnknown)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case tfprotov5.SchemaNestedBlockNestingModeSet:
+		var cfgElems, priorElems []tftypes.Value
+		if err := config.As(&cfgElems); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		if !prior.IsNull() && prior.IsKnown() {
+			if err := prior.As(&priorElems); err != nil {
+				return tftypes.Value{}, path.NewError(err)
+			}
+		}
+		elems := make([]tftypes.Value, 0, len(cfgElems))
+		for _, cfg := range cfgElems {
+			elem, err := planSetElement(path.WithElementKeyValue(cfg), nested.Block, priorElems, cfg, markUnknown)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case tfprotov5.SchemaNestedBlockNestingModeMap:
+		cfgElems, err := attrs(config)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		priorElems, err := attrs(prior)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		elems := make(map[string]tftypes.Value, len(cfgElems))
+		for k, cfg := range cfgElems {
+			pri, ok := priorElems[k]
+			if !ok {
+				pri = tftypes.NewValue(nested.Block.ValueType(), nil)
+			}
+			elems[k], err = planBlock(path.WithElementKeyString(k), nested.Block, pri, cfg, markUnknown)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+		}
+		return tftypes.NewValue(typ, elems), nil
+	}
+	return tftypes.Value{}, path.NewErrorf("unsupported nesting mode %s", nested.Nesting)
+}
+
+// planSetElement plans a single element of a set block. Set elements have no
+// identity other than their value, so the prior element used is the first
+// one that planning the configured element against leaves unchanged.
+func planSetElement(path *tftypes.AttributePath, block *tfprotov5.SchemaBlock, priorElems []tftypes.Value, config tftypes.Value, markUnknown bool) (tftypes.Value, error) {
+	for _, pri := range priorElems {
+		elem, err := planBlock(path, block, pri, config, false)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		if elem.Equal(pri) {
+			return elem, nil
+		}
+	}
+	return planBlock(path, block, tftypes.NewValue(block.ValueType(), nil), config, markUnknown)
+}
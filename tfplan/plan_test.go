@@ -0,0 +1,167 @@
+package tfplan
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Computed: true},
+			{Name: "name", Type: tftypes.String, Required: true},
+			{Name: "description", Type: tftypes.String, Optional: true},
+			{Name: "etag", Type: tftypes.String, Optional: true, Computed: true},
+		},
+		BlockTypes: []*tfprotov5.SchemaNestedBlock{
+			{
+				TypeName: "rule",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "port", Type: tftypes.Number, Required: true},
+						{Name: "rule_id", Type: tftypes.String, Computed: true},
+					},
+				},
+			},
+		},
+	},
+}
+
+var (
+	testType     = testSchema.ValueType()
+	testRuleType = testSchema.Block.BlockTypes[0].Block.ValueType()
+)
+
+func testValue(id, name, description, etag interface{}, rules ...tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(testType, map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, id),
+		"name":        tftypes.NewValue(tftypes.String, name),
+		"description": tftypes.NewValue(tftypes.String, description),
+		"etag":        tftypes.NewValue(tftypes.String, etag),
+		"rule":        tftypes.NewValue(tftypes.List{ElementType: testRuleType}, rules),
+	})
+}
+
+func testRule(port int64, ruleID interface{}) tftypes.Value {
+	return tftypes.NewValue(testRuleType, map[string]tftypes.Value{
+		"port":    tftypes.NewValue(tftypes.Number, big.NewFloat(float64(port))),
+		"rule_id": tftypes.NewValue(tftypes.String, ruleID),
+	})
+}
+
+func TestPlan(t *testing.T) {
+	type testCase struct {
+		prior    tftypes.Value
+		proposed tftypes.Value
+		config   tftypes.Value
+		expected tftypes.Value
+	}
+	prior := testValue("abc", "foo", "desc", "e1", testRule(80, "r1"))
+	cases := map[string]testCase{
+		"create": {
+			prior:    tftypes.NewValue(testType, nil),
+			config:   testValue(nil, "foo", nil, nil, testRule(80, nil)),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, tftypes.UnknownValue, testRule(80, tftypes.UnknownValue)),
+		},
+		"no-change": {
+			prior:    prior,
+			config:   testValue(nil, "foo", "desc", nil, testRule(80, nil)),
+			expected: prior,
+		},
+		"update": {
+			prior:    prior,
+			config:   testValue(nil, "bar", "desc", nil, testRule(80, nil)),
+			expected: testValue(tftypes.UnknownValue, "bar", "desc", tftypes.UnknownValue, testRule(80, tftypes.UnknownValue)),
+		},
+		"remove-optional": {
+			prior:    prior,
+			config:   testValue(nil, "foo", nil, "e1", testRule(80, nil)),
+			expected: testValue(tftypes.UnknownValue, "foo", nil, "e1", testRule(80, tftypes.UnknownValue)),
+		},
+		"add-rule": {
+			prior:    prior,
+			config:   testValue(nil, "foo", "desc", nil, testRule(80, nil), testRule(443, nil)),
+			expected: testValue(tftypes.UnknownValue, "foo", "desc", tftypes.UnknownValue, testRule(80, tftypes.UnknownValue), testRule(443, tftypes.UnknownValue)),
+		},
+		"destroy": {
+			prior:    prior,
+			proposed: tftypes.NewValue(testType, nil),
+			config:   tftypes.NewValue(testType, nil),
+			expected: tftypes.NewValue(testType, nil),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			proposed := test.proposed
+			if proposed.Type() == nil {
+				proposed = test.config
+			}
+			got, err := Plan(testSchema, test.prior, proposed, test.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestPlannerEquality(t *testing.T) {
+	equality := &tfequal.Registry{}
+	equality.AddType(tftypes.String, tfequal.CaseInsensitive())
+	modifiers := &Registry{}
+	modifiers.Add(tftypes.NewAttributePath().WithAttributeName("etag"), Default(tftypes.NewValue(tftypes.String, "e1")))
+	p := Planner{
+		Schema:    testSchema,
+		Modifiers: modifiers,
+		Equality:  equality,
+	}
+
+	prior := testValue("abc", "foo", nil, "E1")
+	config := testValue(nil, "FOO", nil, nil)
+	got, _, err := p.Plan(context.Background(), prior, config, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// configured values must be planned as configured, even when they're
+	// semantically equal to the prior state
+	expected := testValue(tftypes.UnknownValue, "FOO", nil, "E1")
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestPlanWriteOnly(t *testing.T) {
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "name", Type: tftypes.String, Required: true},
+				{Name: "password", Type: tftypes.String, Optional: true, WriteOnly: true},
+			},
+		},
+	}
+	typ := schema.ValueType()
+	value := func(name, password interface{}) tftypes.Value {
+		return tftypes.NewValue(typ, map[string]tftypes.Value{
+			"name":     tftypes.NewValue(tftypes.String, name),
+			"password": tftypes.NewValue(tftypes.String, password),
+		})
+	}
+	config := value("foo", "hunter2")
+	got, err := Plan(schema, tftypes.NewValue(typ, nil), config, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(value("foo", nil), got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,166 @@
+package tfplan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/parse"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Modified is implemented by resource handlers that declare Modifiers for
+// their attributes, like tfresource.Server, so docsgen can document them.
+type Modified interface {
+	Modifiers() *Registry
+}
+
+// Registry associates Modifiers with attribute paths. The zero value is an
+// empty Registry ready to use.
+//
+// Element keys in paths are ignored when matching, so Modifiers registered
+// for an attribute of a nested block apply to that attribute in every
+// element of the block.
+type Registry struct {
+	modifiers attrpath.Registry[Modifier]
+}
+
+// Add registers `modifiers` for the attribute at `path`. Modifiers are run
+// in the order they were added.
+func (r *Registry) Add(path *tftypes.AttributePath, modifiers ...Modifier) {
+	r.modifiers.Add(path, modifiers...)
+}
+
+// Modifiers returns the Modifiers registered for the attribute at `path`.
+func (r *Registry) Modifiers(path *tftypes.AttributePath) []Modifier {
+	if r == nil {
+		return nil
+	}
+	return r.modifiers.Get(path)
+}
+
+func (r *Registry) empty() bool {
+	return r == nil || r.modifiers.Len() == 0
+}
+
+// tagModifiers are the Modifiers that can be declared using `tfplan` struct
+// tags.
+var tagModifiers = map[string]func() Modifier{
+	"requires_replace":      RequiresReplace,
+	"use_state_for_unknown": UseStateForUnknown,
+}
+
+// RegistryFromStruct builds a Registry from the `tfplan` struct tags of `v`,
+// which must be a struct or a pointer to one. Fields are mapped to
+// attributes using their `tfsdk` tags, as with asgotypes.Decode, and fields
+// holding structs, or slices, maps, or pointers of structs, are treated as
+// nested blocks.
+//
+// The `tfplan` tag is a comma-separated list of modifiers:
+//
+//	ID     *string `tfsdk:"id" tfplan:"use_state_for_unknown"`
+//	Zone   string  `tfsdk:"zone" tfplan:"requires_replace"`
+//	Region *string `tfsdk:"region" tfplan:"env=EXAMPLE_REGION,default=us-east-1"`
+//
+// The supported modifiers are "requires_replace", "use_state_for_unknown",
+// "env=NAME", and "default=VALUE". "env" may be given more than once, and
+// the first environment variable that is set is used, falling back to
+// "default" if none of them are; see EnvDefault. Default values are parsed
+// according to the attribute's type, and can't contain commas.
+func RegistryFromStruct(v interface{}) (*Registry, error) {
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot build a registry from %T, a struct is required", v)
+	}
+	r := &Registry{}
+	if err := r.addStruct(tftypes.NewAttributePath(), typ); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Registry) addStruct(path *tftypes.AttributePath, typ reflect.Type) error {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("tfsdk"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		attrPath := path.WithAttributeName(name)
+		if tag := f.Tag.Get("tfplan"); tag != "" {
+			var (
+				envs       []string
+				def        string
+				hasDefault bool
+			)
+			for _, mod := range strings.Split(tag, ",") {
+				mod = strings.TrimSpace(mod)
+				if k, v, ok := strings.Cut(mod, "="); ok {
+					switch k {
+					case "env":
+						envs = append(envs, v)
+					case "default":
+						def, hasDefault = v, true
+					default:
+						return fmt.Errorf("%s.%s: unknown plan modifier %q", typ, f.Name, k)
+					}
+					continue
+				}
+				newModifier, ok := tagModifiers[mod]
+				if !ok {
+					return fmt.Errorf("%s.%s: unknown plan modifier %q", typ, f.Name, mod)
+				}
+				r.Add(attrPath, newModifier())
+			}
+			if len(envs) > 0 || hasDefault {
+				r.Add(attrPath, tagDefault(envs, def, hasDefault))
+			}
+		}
+		if nested := nestedStruct(f.Type); nested != nil {
+			if err := r.addStruct(attrPath, nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tagDefault returns the Modifier for the "env" and "default" tags. The
+// default is parsed when the Modifier runs, as the attribute's type isn't
+// known until then.
+func tagDefault(envs []string, def string, hasDefault bool) Modifier {
+	return Describe(DefaultFunc(func(ctx context.Context, req ModifyRequest) (tftypes.Value, error) {
+		typ := req.Attribute.Planned.Type()
+		val, ok, err := parse.Env(typ, envs)
+		if err != nil || ok {
+			return val, err
+		}
+		if !hasDefault {
+			return tftypes.Value{}, nil
+		}
+		return parse.Primitive(typ, def)
+	}), defaultDescription(envs, def))
+}
+
+// nestedStruct returns the struct type held by `typ`, looking through
+// pointers, slices, arrays, and maps, or nil if it doesn't hold one.
+func nestedStruct(typ reflect.Type) reflect.Type {
+	for {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		case reflect.Struct:
+			if typ == reflect.TypeOf(tftypes.Value{}) || typ.PkgPath() == "math/big" {
+				return nil
+			}
+			return typ
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package tfplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type testTaggedRule struct {
+	Port   int     `tfsdk:"port" tfplan:"requires_replace"`
+	RuleID *string `tfsdk:"rule_id" tfplan:"use_state_for_unknown"`
+}
+
+type testTagged struct {
+	ID          *string          `tfsdk:"id" tfplan:"use_state_for_unknown"`
+	Name        string           `tfsdk:"name" tfplan:"requires_replace, use_state_for_unknown"`
+	Description *string          `tfsdk:"description"`
+	Rules       []testTaggedRule `tfsdk:"rule"`
+	internal    string
+}
+
+func TestRegistryFromStruct(t *testing.T) {
+	r, err := RegistryFromStruct(&testTagged{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[*tftypes.AttributePath]int{
+		tftypes.NewAttributePath().WithAttributeName("id"):                                                  1,
+		tftypes.NewAttributePath().WithAttributeName("name"):                                                2,
+		tftypes.NewAttributePath().WithAttributeName("description"):                                         0,
+		tftypes.NewAttributePath().WithAttributeName("rule"):                                                0,
+		tftypes.NewAttributePath().WithAttributeName("rule").WithElementKeyInt(1).WithAttributeName("port"): 1,
+		tftypes.NewAttributePath().WithAttributeName("rule").WithAttributeName("rule_id"):                   1,
+	}
+	for path, n := range expected {
+		if got := len(r.Modifiers(path)); got != n {
+			t.Errorf("expected %d modifiers for %s, got %d", n, path, got)
+		}
+	}
+}
+
+func TestRegistryFromStructErrors(t *testing.T) {
+	type testCase struct {
+		v           interface{}
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"not-struct": {
+			v:           "foo",
+			expectedErr: "cannot build a registry from string, a struct is required",
+		},
+		"unknown-modifier": {
+			v: struct {
+				Name string `tfsdk:"name" tfplan:"sometimes_replace"`
+			}{},
+			expectedErr: `struct { Name string "tfsdk:\"name\" tfplan:\"sometimes_replace\"" }.Name: unknown plan modifier "sometimes_replace"`,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			_, err := RegistryFromStruct(test.v)
+			if err == nil {
+				t.Fatalf("expected error %q, got none", test.expectedErr)
+			}
+			if err.Error() != test.expectedErr {
+				t.Errorf("expected error %q, got %q", test.expectedErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestRegistryFromStructDefaults(t *testing.T) {
+	type tagged struct {
+		Etag *string `tfsdk:"etag" tfplan:"env=TFPLAN_TEST_ETAG,default=none"`
+	}
+	r, err := RegistryFromStruct(tagged{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	p := Planner{Schema: testSchema, Modifiers: r}
+	config := testValue(nil, "foo", nil, nil)
+
+	got, _, err := p.Plan(context.Background(), tftypes.NewValue(testType, nil), config, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(testValue(tftypes.UnknownValue, "foo", nil, "none"), got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	t.Setenv("TFPLAN_TEST_ETAG", "from-env")
+	got, _, err = p.Plan(context.Background(), tftypes.NewValue(testType, nil), config, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(testValue(tftypes.UnknownValue, "foo", nil, "from-env"), got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
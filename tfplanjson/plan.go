@@ -0,0 +1,495 @@
+// Package tfplanjson reads the JSON representation of a plan that
+// `terraform show -json` prints, reconstructing the before and after values
+// of its changes as tftypes.Values.
+//
+// The JSON representation leaves unknown values out of the after values,
+// recording them in a separate after_unknown structure that mirrors the
+// value, and does the same for sensitive values. This package puts the
+// unknown values back, and returns the sensitive ones as
+// tftypes.AttributePaths, so tests and tools can work with plans the same
+// way providers do.
+package tfplanjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Plan is the JSON representation of a plan. Only the parts describing
+// changes are decoded.
+type Plan struct {
+	FormatVersion    string            `json:"format_version"`
+	TerraformVersion string            `json:"terraform_version"`
+	ResourceChanges  []ResourceChange  `json:"resource_changes"`
+	OutputChanges    map[string]Change `json:"output_changes"`
+}
+
+// ResourceChange is a planned change to a resource instance.
+type ResourceChange struct {
+	Address         string `json:"address"`
+	PreviousAddress string `json:"previous_address"`
+	ModuleAddress   string `json:"module_address"`
+
+	// Mode is "managed" for resources and "data" for data sources.
+	Mode string `json:"mode"`
+
+	Type         string      `json:"type"`
+	Name         string      `json:"name"`
+	Index        interface{} `json:"index"`
+	ProviderName string      `json:"provider_name"`
+
+	// Deposed is the key of the deposed object the change is to, and
+	// empty for changes to the current object.
+	Deposed string `json:"deposed"`
+
+	Change Change `json:"change"`
+
+	// ActionReason explains why the change has the actions it does, like
+	// "replace_because_cannot_update", and is often empty.
+	ActionReason string `json:"action_reason"`
+}
+
+// Change is a planned change to a resource instance or output value. The
+// raw JSON of its parts is kept, as they can only be interpreted with the
+// type of the value being changed.
+type Change struct {
+	// Actions are the actions the change takes, like ["update"] or
+	// ["delete", "create"] for a replacement.
+	Actions []string `json:"actions"`
+
+	Before          json.RawMessage `json:"before"`
+	After           json.RawMessage `json:"after"`
+	AfterUnknown    json.RawMessage `json:"after_unknown"`
+	BeforeSensitive json.RawMessage `json:"before_sensitive"`
+	AfterSensitive  json.RawMessage `json:"after_sensitive"`
+	ReplacePaths    json.RawMessage `json:"replace_paths"`
+}
+
+// ReadFile reads and parses the JSON plan at `path`, as written by
+// `terraform show -json plan.out > plan.json`.
+func ReadFile(path string) (*Plan, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse parses a JSON plan from `r`.
+func Parse(r io.Reader) (*Plan, error) {
+	var plan Plan
+	if err := json.NewDecoder(r).Decode(&plan); err != nil {
+		return nil, fmt.Errorf("error parsing plan: %w", err)
+	}
+	if plan.FormatVersion == "" {
+		return nil, fmt.Errorf("error parsing plan: no format_version, is it the output of terraform show -json?")
+	}
+	return &plan, nil
+}
+
+// ResourceChange returns the change to the resource instance at
+// `address`, and false if there isn't one.
+func (p *Plan) ResourceChange(address string) (*ResourceChange, bool) {
+	for i := range p.ResourceChanges {
+		if p.ResourceChanges[i].Address == address && p.ResourceChanges[i].Deposed == "" {
+			return &p.ResourceChanges[i], true
+		}
+	}
+	return nil, false
+}
+
+// Is reports whether the change's actions are exactly `actions`.
+func (c *Change) Is(actions ...string) bool {
+	if len(c.Actions) != len(actions) {
+		return false
+	}
+	for i, a := range actions {
+		if c.Actions[i] != a {
+			return false
+		}
+	}
+	return true
+}
+
+// BeforeValue returns the value before the change as a value of type
+// `typ`, which is usually the type of the resource's schema. It's null for
+// creates.
+//
+// If `typ` is tftypes.DynamicPseudoType, the type is inferred from the
+// JSON: arrays become tuples and objects objects.
+func (c *Change) BeforeValue(typ tftypes.Type) (tftypes.Value, error) {
+	return buildValue(typ, c.Before, nil)
+}
+
+// AfterValue returns the value after the change as a value of type `typ`,
+// with the values after_unknown records as unknown set to unknown. It's
+// null for deletes.
+//
+// If `typ` is tftypes.DynamicPseudoType, the type is inferred from the
+// JSON: arrays become tuples and objects objects, and unknown values are
+// unknown values of tftypes.DynamicPseudoType.
+func (c *Change) AfterValue(typ tftypes.Type) (tftypes.Value, error) {
+	return buildValue(typ, c.After, c.AfterUnknown)
+}
+
+// BeforeSensitivePaths returns the paths of the sensitive parts of the
+// value before the change, which must be of type `typ`.
+func (c *Change) BeforeSensitivePaths(typ tftypes.Type) ([]*tftypes.AttributePath, error) {
+	val, err := c.BeforeValue(typ)
+	if err != nil {
+		return nil, err
+	}
+	return markedPaths(val, c.BeforeSensitive)
+}
+
+// AfterSensitivePaths returns the paths of the sensitive parts of the value
+// after the change, which must be of type `typ`.
+func (c *Change) AfterSensitivePaths(typ tftypes.Type) ([]*tftypes.AttributePath, error) {
+	val, err := c.AfterValue(typ)
+	if err != nil {
+		return nil, err
+	}
+	return markedPaths(val, c.AfterSensitive)
+}
+
+// ReplaceAttributePaths returns the paths of the attributes whose changes
+// force the resource to be replaced, which the JSON only records as lists
+// of attribute names, keys, and indexes. `typ` is used to tell which steps
+// are which; if it's tftypes.DynamicPseudoType, strings are assumed to be
+// attribute names.
+func (c *Change) ReplaceAttributePaths(typ tftypes.Type) ([]*tftypes.AttributePath, error) {
+	if len(c.ReplacePaths) == 0 {
+		return nil, nil
+	}
+	var raw [][]interface{}
+	if err := json.Unmarshal(c.ReplacePaths, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing replace_paths: %w", err)
+	}
+	paths := make([]*tftypes.AttributePath, 0, len(raw))
+	for _, steps := range raw {
+		path := tftypes.NewAttributePath()
+		t := typ
+		for _, step := range steps {
+			switch s := step.(type) {
+			case string:
+				switch tt := t.(type) {
+				case tftypes.Map:
+					path, t = path.WithElementKeyString(s), tt.ElementType
+				case tftypes.Object:
+					path, t = path.WithAttributeName(s), tt.AttributeTypes[s]
+				default:
+					path, t = path.WithAttributeName(s), tftypes.DynamicPseudoType
+				}
+			case float64:
+				path = path.WithElementKeyInt(int(s))
+				switch tt := t.(type) {
+				case tftypes.List:
+					t = tt.ElementType
+				case tftypes.Tuple:
+					if int(s) < len(tt.ElementTypes) {
+						t = tt.ElementTypes[int(s)]
+					}
+				default:
+					t = tftypes.DynamicPseudoType
+				}
+			default:
+				return nil, fmt.Errorf("unexpected step %v in replace_paths", step)
+			}
+			if t == nil {
+				return nil, path.NewErrorf("no attribute in %s", typ)
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// buildValue decodes `raw` as a value of type `typ`, setting the values
+// `unknown` marks as unknown.
+func buildValue(typ tftypes.Type, raw, unknown json.RawMessage) (tftypes.Value, error) {
+	v, err := decodeJSON(raw)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	u, err := decodeJSON(unknown)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return build(tftypes.NewAttributePath(), typ, v, u)
+}
+
+func decodeJSON(raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("error parsing plan value: %w", err)
+	}
+	return v, nil
+}
+
+// build converts `v`, decoded from JSON, into a value of type `typ`.
+// `unknown` is the corresponding part of the after_unknown structure: true
+// if the whole value is unknown, or an array or object holding the same
+// for its elements.
+func build(path *tftypes.AttributePath, typ tftypes.Type, v, unknown interface{}) (tftypes.Value, error) {
+	if unknown == true {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	if v == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	switch t := typ.(type) {
+	case tftypes.List:
+		elems, err := buildElements(path, v, unknown, func(int) tftypes.Type { return t.ElementType })
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return newValue(path, typ, elems)
+	case tftypes.Set:
+		elems, err := buildElements(path, v, unknown, func(int) tftypes.Type { return t.ElementType })
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return newValue(path, typ, elems)
+	case tftypes.Tuple:
+		arr, ok := v.([]interface{})
+		if !ok || len(arr) != len(t.ElementTypes) {
+			return tftypes.Value{}, path.NewErrorf("cannot convert %s to %s", describe(v), typ)
+		}
+		elems, err := buildElements(path, v, unknown, func(i int) tftypes.Type { return t.ElementTypes[i] })
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return newValue(path, typ, elems)
+	case tftypes.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return tftypes.Value{}, path.NewErrorf("cannot convert %s to %s", describe(v), typ)
+		}
+		unknowns, _ := unknown.(map[string]interface{})
+		elems := make(map[string]tftypes.Value, len(obj))
+		for _, k := range union(obj, unknowns) {
+			elem, err := build(path.WithElementKeyString(k), t.ElementType, obj[k], unknowns[k])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = elem
+		}
+		return newValue(path, typ, elems)
+	case tftypes.Object:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return tftypes.Value{}, path.NewErrorf("cannot convert %s to %s", describe(v), typ)
+		}
+		unknowns, _ := unknown.(map[string]interface{})
+		attrs := make(map[string]tftypes.Value, len(t.AttributeTypes))
+		for k, attrType := range t.AttributeTypes {
+			attr, err := build(path.WithAttributeName(k), attrType, obj[k], unknowns[k])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[k] = attr
+		}
+		return newValue(path, typ, attrs)
+	}
+	switch {
+	case typ.Is(tftypes.String):
+		if s, ok := v.(string); ok {
+			return tftypes.NewValue(typ, s), nil
+		}
+	case typ.Is(tftypes.Number):
+		if n, ok := v.(json.Number); ok {
+			f, _, err := big.ParseFloat(string(n), 10, 512, big.ToNearestEven)
+			if err != nil {
+				return tftypes.Value{}, path.NewError(err)
+			}
+			return tftypes.NewValue(typ, f), nil
+		}
+	case typ.Is(tftypes.Bool):
+		if b, ok := v.(bool); ok {
+			return tftypes.NewValue(typ, b), nil
+		}
+	case typ.Is(tftypes.DynamicPseudoType):
+		return buildDynamic(path, v, unknown)
+	}
+	return tftypes.Value{}, path.NewErrorf("cannot convert %s to %s", describe(v), typ)
+}
+
+// buildDynamic converts `v` into a value, inferring its type.
+func buildDynamic(path *tftypes.AttributePath, v, unknown interface{}) (tftypes.Value, error) {
+	switch x := v.(type) {
+	case string:
+		return tftypes.NewValue(tftypes.String, x), nil
+	case bool:
+		return tftypes.NewValue(tftypes.Bool, x), nil
+	case json.Number:
+		return build(path, tftypes.Number, v, unknown)
+	case []interface{}:
+		elems, err := buildElements(path, v, unknown, func(int) tftypes.Type { return tftypes.DynamicPseudoType })
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		types := make([]tftypes.Type, 0, len(elems))
+		for _, elem := range elems {
+			types = append(types, elem.Type())
+		}
+		return tftypes.NewValue(tftypes.Tuple{ElementTypes: types}, elems), nil
+	}
+	obj := v.(map[string]interface{})
+	unknowns, _ := unknown.(map[string]interface{})
+	// Unknown attributes are left out of objects, so take the attributes
+	// from both.
+	attrs := make(map[string]tftypes.Value, len(obj))
+	types := make(map[string]tftypes.Type, len(obj))
+	for _, k := range union(obj, unknowns) {
+		attr, err := build(path.WithAttributeName(k), tftypes.DynamicPseudoType, obj[k], unknowns[k])
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		attrs[k] = attr
+		types[k] = attr.Type()
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, attrs), nil
+}
+
+func buildElements(path *tftypes.AttributePath, v, unknown interface{}, elemType func(int) tftypes.Type) ([]tftypes.Value, error) {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, path.NewErrorf("cannot convert %s to a list", describe(v))
+	}
+	unknowns, _ := unknown.([]interface{})
+	elems := make([]tftypes.Value, 0, len(arr))
+	for i, e := range arr {
+		var u interface{}
+		if i < len(unknowns) {
+			u = unknowns[i]
+		}
+		elem, err := build(path.WithElementKeyInt(i), elemType(i), e, u)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+	}
+	return elems, nil
+}
+
+func newValue(path *tftypes.AttributePath, typ tftypes.Type, val interface{}) (tftypes.Value, error) {
+	if err := tftypes.ValidateValue(typ, val); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	return tftypes.NewValue(typ, val), nil
+}
+
+// markedPaths returns the paths of the parts of `val` that `raw`, a
+// structure like after_sensitive, marks with true.
+func markedPaths(val tftypes.Value, raw json.RawMessage) ([]*tftypes.AttributePath, error) {
+	marks, err := decodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	var paths []*tftypes.AttributePath
+	if err := walkMarks(tftypes.NewAttributePath(), val, marks, &paths); err != nil {
+		return nil, err
+	}
+	sort.Slice(paths, func(i, j int) bool { return paths[i].String() < paths[j].String() })
+	return paths, nil
+}
+
+func walkMarks(path *tftypes.AttributePath, val tftypes.Value, marks interface{}, paths *[]*tftypes.AttributePath) error {
+	switch m := marks.(type) {
+	case bool:
+		if m {
+			*paths = append(*paths, path)
+		}
+		return nil
+	case []interface{}:
+		if !val.IsKnown() || val.IsNull() {
+			return nil
+		}
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return path.NewError(err)
+		}
+		isSet := val.Type().Is(tftypes.Set{})
+		for i, mark := range m {
+			if i >= len(elems) {
+				break
+			}
+			elemPath := path.WithElementKeyInt(i)
+			if isSet {
+				elemPath = path.WithElementKeyValue(elems[i])
+			}
+			if err := walkMarks(elemPath, elems[i], mark, paths); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		if !val.IsKnown() || val.IsNull() {
+			return nil
+		}
+		var elems map[string]tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return path.NewError(err)
+		}
+		isMap := val.Type().Is(tftypes.Map{})
+		for _, k := range union(m, nil) {
+			elem, ok := elems[k]
+			if !ok {
+				continue
+			}
+			elemPath := path.WithAttributeName(k)
+			if isMap {
+				elemPath = path.WithElementKeyString(k)
+			}
+			if err := walkMarks(elemPath, elem, m[k], paths); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// union returns the keys of `a` and `b`, sorted.
+func union(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// describe returns the kind of `v`, decoded from JSON, for errors.
+func describe(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a bool"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return "null"
+}
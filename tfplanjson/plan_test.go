@@ -0,0 +1,179 @@
+package tfplanjson
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	diskType   = tftypes.Object{AttributeTypes: map[string]tftypes.Type{"size": tftypes.Number}}
+	serverType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":       tftypes.String,
+		"name":     tftypes.String,
+		"password": tftypes.String,
+		"ports":    tftypes.List{ElementType: tftypes.Number},
+		"tags":     tftypes.Map{ElementType: tftypes.String},
+		"disks":    tftypes.List{ElementType: diskType},
+	}}
+)
+
+func server(id, name, password, ports, tags interface{}, disks []tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(serverType, map[string]tftypes.Value{
+		"id":       tftypes.NewValue(tftypes.String, id),
+		"name":     tftypes.NewValue(tftypes.String, name),
+		"password": tftypes.NewValue(tftypes.String, password),
+		"ports":    tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, ports),
+		"tags":     tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tags),
+		"disks":    tftypes.NewValue(tftypes.List{ElementType: diskType}, disks),
+	})
+}
+
+func disk(size int64) tftypes.Value {
+	return tftypes.NewValue(diskType, map[string]tftypes.Value{
+		"size": tftypes.NewValue(tftypes.Number, big.NewFloat(float64(size))),
+	})
+}
+
+func readTestPlan(t *testing.T) *Plan {
+	t.Helper()
+	plan, err := ReadFile("testdata/plan.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return plan
+}
+
+func TestChangeValues(t *testing.T) {
+	plan := readTestPlan(t)
+	prod := map[string]tftypes.Value{"env": tftypes.NewValue(tftypes.String, "prod")}
+
+	type testCase struct {
+		address        string
+		expectedBefore tftypes.Value
+		expectedAfter  tftypes.Value
+	}
+	cases := map[string]testCase{
+		"replace": {
+			address: "example_server.web",
+			expectedBefore: server("s-1", "web", "hunter2", []tftypes.Value{
+				tftypes.NewValue(tftypes.Number, big.NewFloat(80)),
+			}, prod, []tftypes.Value{disk(10)}),
+			expectedAfter: server(tftypes.UnknownValue, "web-2", "hunter3", []tftypes.Value{
+				tftypes.NewValue(tftypes.Number, big.NewFloat(80)),
+				tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+			}, prod, []tftypes.Value{disk(20)}),
+		},
+		"create": {
+			address:        "example_server.new",
+			expectedBefore: tftypes.NewValue(serverType, nil),
+			expectedAfter:  server(tftypes.UnknownValue, "new", nil, nil, nil, []tftypes.Value{}),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			rc, ok := plan.ResourceChange(tc.address)
+			if !ok {
+				t.Fatalf("expected a change to %s", tc.address)
+			}
+			before, err := rc.Change.BeforeValue(serverType)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expectedBefore, before); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			after, err := rc.Change.AfterValue(serverType)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expectedAfter, after); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestChangePaths(t *testing.T) {
+	plan := readTestPlan(t)
+	rc, _ := plan.ResourceChange("example_server.web")
+	if !rc.Change.Is("delete", "create") {
+		t.Errorf("expected a replacement, got %v", rc.Change.Actions)
+	}
+
+	root := tftypes.NewAttributePath()
+	sensitive, err := rc.Change.AfterSensitivePaths(serverType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []*tftypes.AttributePath{
+		root.WithAttributeName("disks").WithElementKeyInt(0).WithAttributeName("size"),
+		root.WithAttributeName("password"),
+		root.WithAttributeName("tags").WithElementKeyString("env"),
+	}
+	if diff := cmp.Diff(expected, sensitive); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	sensitive, err = rc.Change.BeforeSensitivePaths(serverType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]*tftypes.AttributePath{root.WithAttributeName("password")}, sensitive); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	replace, err := rc.Change.ReplaceAttributePaths(serverType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected = []*tftypes.AttributePath{
+		root.WithAttributeName("name"),
+		root.WithAttributeName("tags").WithElementKeyString("env"),
+		root.WithAttributeName("ports").WithElementKeyInt(0),
+	}
+	if diff := cmp.Diff(expected, replace); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestChangeDynamic(t *testing.T) {
+	plan := readTestPlan(t)
+	change := plan.OutputChanges["address"]
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"primary":   tftypes.String,
+		"secondary": tftypes.DynamicPseudoType,
+	}}
+	expected := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"primary":   tftypes.NewValue(tftypes.String, "10.0.0.1"),
+		"secondary": tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue),
+	})
+	got, err := change.AfterValue(tftypes.DynamicPseudoType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestChangeErrors(t *testing.T) {
+	plan := readTestPlan(t)
+	rc, _ := plan.ResourceChange("example_server.web")
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.Number}}
+	_, err := rc.Change.AfterValue(typ)
+	expectedErr := `AttributeName("name"): cannot convert a string to tftypes.Number`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+
+	_, err = Parse(strings.NewReader(`{"version": 4}`))
+	expectedErr = "error parsing plan: no format_version, is it the output of terraform show -json?"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
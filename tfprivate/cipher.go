@@ -0,0 +1,86 @@
+package tfprivate
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// Cipher encrypts and decrypts private state. Implementations must
+// authenticate the data they encrypt, along with `additionalData`, so that
+// tampering is detected when decrypting.
+//
+// Decrypt must return an error wrapping ErrCorrupt if `ciphertext` is
+// malformed or can't be authenticated. Other errors, like failing to get the
+// key, must not wrap it, as providers usually treat corrupt private state as
+// empty and would otherwise discard it because of a transient failure.
+type Cipher interface {
+	Encrypt(plaintext, additionalData []byte) ([]byte, error)
+	Decrypt(ciphertext, additionalData []byte) ([]byte, error)
+}
+
+// KeyFunc returns the key used by a Cipher. It's called for every encryption
+// and decryption, so keys fetched from a key management service should be
+// cached by the provider.
+type KeyFunc func() ([]byte, error)
+
+// StaticKey returns a KeyFunc that always returns `key`, which is usually
+// read from the provider's configuration.
+func StaticKey(key []byte) KeyFunc {
+	return func() ([]byte, error) {
+		return key, nil
+	}
+}
+
+// AESGCM returns a Cipher using AES-GCM with the key returned by `key`,
+// which must be 16, 24, or 32 bytes long to select AES-128, AES-192, or
+// AES-256. A random nonce is generated for each encryption and stored with
+// the ciphertext.
+func AESGCM(key KeyFunc) Cipher {
+	return aesGCM{key: key}
+}
+
+type aesGCM struct {
+	key KeyFunc
+}
+
+func (c aesGCM) aead() (cipher.AEAD, error) {
+	key, err := c.key()
+	if err != nil {
+		return nil, fmt.Errorf("error getting private state key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (c aesGCM) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func (c aesGCM) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: ciphertext is too short", ErrCorrupt)
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorrupt, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,133 @@
+package tfprivate
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testKey = bytes.Repeat([]byte{1}, 32)
+
+func TestCodecEncrypted(t *testing.T) {
+	codec := testCodec
+	codec.Cipher = AESGCM(StaticKey(testKey))
+	v := testPrivate{ETag: "abc", Token: "secret"}
+	b, err := codec.Encode(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bytes.Contains(b, []byte("secret")) {
+		t.Errorf("expected token to be encrypted, got %s", b)
+	}
+	got, err := codec.Decode(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(v, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestCodecDecrypt(t *testing.T) {
+	type testCase struct {
+		private     []byte
+		cipher      Cipher
+		expected    testPrivate
+		expectedErr string
+		corrupt     bool
+	}
+	encrypted, err := Codec[testPrivate]{Version: 1, Cipher: AESGCM(StaticKey(testKey))}.Encode(testPrivate{ETag: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cases := map[string]testCase{
+		"matching-key": {
+			cipher:   AESGCM(StaticKey(testKey)),
+			expected: testPrivate{ETag: "abc"},
+		},
+		"wrong-key": {
+			cipher:      AESGCM(StaticKey(bytes.Repeat([]byte{2}, 32))),
+			expectedErr: "error decrypting private state: private state is corrupt: cipher: message authentication failed",
+			corrupt:     true,
+		},
+		"tampered-version": {
+			private:     bytes.Replace(encrypted, []byte(`"version":1`), []byte(`"version":0`), 1),
+			cipher:      AESGCM(StaticKey(testKey)),
+			expectedErr: "error decrypting private state: private state is corrupt: cipher: message authentication failed",
+			corrupt:     true,
+		},
+		"truncated": {
+			private:     []byte(`{"version":1,"encrypted":"AAAA"}`),
+			cipher:      AESGCM(StaticKey(testKey)),
+			expectedErr: "error decrypting private state: private state is corrupt: ciphertext is too short",
+			corrupt:     true,
+		},
+		"no-cipher": {
+			expectedErr: "private state is encrypted, but no cipher was provided to decrypt it",
+		},
+		"invalid-key": {
+			cipher:      AESGCM(StaticKey([]byte("short"))),
+			expectedErr: "error decrypting private state: crypto/aes: invalid key size 5",
+		},
+		"key-error": {
+			cipher: AESGCM(func() ([]byte, error) {
+				return nil, errors.New("kms unavailable")
+			}),
+			expectedErr: "error decrypting private state: error getting private state key: kms unavailable",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			codec := testCodec
+			codec.Cipher = tc.cipher
+			private := encrypted
+			if tc.private != nil {
+				private = tc.private
+			}
+			got, err := codec.Decode(private)
+			if err != nil {
+				if tc.expectedErr == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %q", tc.expectedErr, err.Error())
+				}
+				if errors.Is(err, ErrCorrupt) != tc.corrupt {
+					t.Errorf("expected errors.Is(err, ErrCorrupt) to be %v", tc.corrupt)
+				}
+				return
+			}
+			if tc.expectedErr != "" {
+				t.Fatalf("expected error %q, got none", tc.expectedErr)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCodecDecryptPlaintext(t *testing.T) {
+	b, err := testCodec.Encode(testPrivate{ETag: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	codec := testCodec
+	codec.Cipher = AESGCM(StaticKey(testKey))
+	expectedErr := "private state isn't encrypted, set AllowPlaintext to decode private state written before encryption was enabled"
+	if _, err := codec.Decode(b); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+
+	codec.AllowPlaintext = true
+	got, err := codec.Decode(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(testPrivate{ETag: "abc"}, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,134 @@
+// Package tfprivate provides helpers for the opaque Private bytes that
+// Terraform stores alongside a resource's state and passes back to the
+// provider on every RPC.
+//
+// Providers commonly use private state for things like ETags and tokens
+// that shouldn't be shown to practitioners. Codec stores a Go value there as
+// JSON, alongside a version number so that the format of the value can
+// change over time, and handles empty and corrupt payloads safely. Values can
+// optionally be encrypted, so that secrets aren't stored in plaintext in
+// state files and their backups.
+package tfprivate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrCorrupt is returned, wrapped, by Codec.Decode when the private state
+// can't be decoded, or can't be authenticated when decrypting it. Providers
+// usually want to treat corrupt private state as empty, with
+// errors.Is(err, ErrCorrupt), rather than failing. Other errors, like failing
+// to get the key to decrypt it with, don't wrap ErrCorrupt.
+var ErrCorrupt = errors.New("private state is corrupt")
+
+// MigrateFunc migrates the JSON encoding of a value from one version of a
+// Codec to the next.
+type MigrateFunc func(data json.RawMessage) (json.RawMessage, error)
+
+// envelope is the JSON format private state is stored in.
+type envelope struct {
+	Version   int             `json:"version"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Encrypted []byte          `json:"encrypted,omitempty"`
+}
+
+// Codec encodes and decodes values of type T to and from private state.
+// The zero value is a Codec for version 0 without any migrations.
+type Codec[T any] struct {
+	// Version is the current version of T's encoding. It should be
+	// incremented whenever T changes in a way that existing private state
+	// can't be decoded into.
+	Version int
+
+	// Migrations holds the MigrateFuncs for each earlier version, keyed
+	// by the version they migrate from. Private state written by a
+	// version without a path of migrations to the current version can't
+	// be decoded.
+	Migrations map[int]MigrateFunc
+
+	// Cipher, if set, is used to encrypt values when encoding them, with
+	// the version they're encoded with as additional authenticated data.
+	// Encrypted private state can't be decoded without a Cipher.
+	Cipher Cipher
+
+	// AllowPlaintext allows private state written without encryption to
+	// be decoded when Cipher is set, so that encryption can be enabled
+	// without breaking existing state. Otherwise it's rejected, so that
+	// encrypted private state can't be replaced with plaintext.
+	AllowPlaintext bool
+}
+
+// Encode returns the private state for `v`.
+func (c Codec[T]) Encode(v T) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	env := envelope{Version: c.Version, Data: data}
+	if c.Cipher != nil {
+		env.Encrypted, err = c.Cipher.Encrypt(data, additionalData(c.Version))
+		if err != nil {
+			return nil, fmt.Errorf("error encrypting private state: %w", err)
+		}
+		env.Data = nil
+	}
+	return json.Marshal(env)
+}
+
+// Decode decodes `private` into a value of type T, migrating it from the
+// version it was written with if necessary. Empty private state decodes to
+// the zero value of T without an error.
+func (c Codec[T]) Decode(private []byte) (T, error) {
+	var v T
+	if len(private) == 0 {
+		return v, nil
+	}
+	var env envelope
+	if err := json.Unmarshal(private, &env); err != nil {
+		return v, fmt.Errorf("%w: %s", ErrCorrupt, err)
+	}
+	if env.Encrypted != nil {
+		if c.Cipher == nil {
+			return v, errors.New("private state is encrypted, but no cipher was provided to decrypt it")
+		}
+		data, err := c.Cipher.Decrypt(env.Encrypted, additionalData(env.Version))
+		if err != nil {
+			return v, fmt.Errorf("error decrypting private state: %w", err)
+		}
+		env.Data = data
+	} else if c.Cipher != nil && !c.AllowPlaintext {
+		return v, errors.New("private state isn't encrypted, set AllowPlaintext to decode private state written before encryption was enabled")
+	}
+	if env.Data == nil {
+		return v, fmt.Errorf("%w: no data", ErrCorrupt)
+	}
+	if env.Version > c.Version {
+		return v, fmt.Errorf("private state was written with version %d, which is newer than the current version %d", env.Version, c.Version)
+	}
+	data := env.Data
+	for version := env.Version; version < c.Version; version++ {
+		migrate, ok := c.Migrations[version]
+		if !ok {
+			return v, fmt.Errorf("migrating private state from version %d is not supported", version)
+		}
+		var err error
+		data, err = migrate(data)
+		if err != nil {
+			return v, fmt.Errorf("migrating private state from version %d to %d: %w", version, version+1, err)
+		}
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("%w: %s", ErrCorrupt, err)
+	}
+	return v, nil
+}
+
+// additionalData returns the additional authenticated data that private
+// state written with `version` is encrypted with, so that the version can't
+// be changed without detection.
+func additionalData(version int) []byte {
+	return []byte("tfprivate:version=" + strconv.Itoa(version))
+}
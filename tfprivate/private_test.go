@@ -0,0 +1,118 @@
+package tfprivate
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testPrivateV0 struct {
+	Tag string `json:"tag"`
+}
+
+type testPrivate struct {
+	ETag  string `json:"etag"`
+	Token string `json:"token,omitempty"`
+}
+
+var testCodec = Codec[testPrivate]{
+	Version: 1,
+	Migrations: map[int]MigrateFunc{
+		0: func(data json.RawMessage) (json.RawMessage, error) {
+			var v0 testPrivateV0
+			if err := json.Unmarshal(data, &v0); err != nil {
+				return nil, err
+			}
+			if v0.Tag == "" {
+				return nil, errors.New("no tag")
+			}
+			return json.Marshal(testPrivate{ETag: v0.Tag})
+		},
+	},
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	v := testPrivate{ETag: "abc", Token: "secret"}
+	b, err := testCodec.Encode(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := testCodec.Decode(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(v, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestCodecDecode(t *testing.T) {
+	type testCase struct {
+		private     string
+		expected    testPrivate
+		expectedErr string
+		corrupt     bool
+	}
+	cases := map[string]testCase{
+		"empty": {
+			private: "",
+		},
+		"current": {
+			private:  `{"version": 1, "data": {"etag": "abc"}}`,
+			expected: testPrivate{ETag: "abc"},
+		},
+		"migrated": {
+			private:  `{"version": 0, "data": {"tag": "abc"}}`,
+			expected: testPrivate{ETag: "abc"},
+		},
+		"migration-error": {
+			private:     `{"version": 0, "data": {}}`,
+			expectedErr: "migrating private state from version 0 to 1: no tag",
+		},
+		"newer": {
+			private:     `{"version": 2, "data": {}}`,
+			expectedErr: "private state was written with version 2, which is newer than the current version 1",
+		},
+		"not-json": {
+			private:     `not json`,
+			expectedErr: "private state is corrupt: invalid character 'o' in literal null (expecting 'u')",
+			corrupt:     true,
+		},
+		"no-data": {
+			private:     `{"schema_version": "1"}`,
+			expectedErr: "private state is corrupt: no data",
+			corrupt:     true,
+		},
+		"wrong-type": {
+			private:     `{"version": 1, "data": {"etag": 1}}`,
+			expectedErr: "private state is corrupt: json: cannot unmarshal number into Go struct field testPrivate.etag of type string",
+			corrupt:     true,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			got, err := testCodec.Decode([]byte(test.private))
+			if test.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", test.expectedErr)
+				}
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %q", test.expectedErr, err.Error())
+				}
+				if errors.Is(err, ErrCorrupt) != test.corrupt {
+					t.Errorf("expected errors.Is(err, ErrCorrupt) to be %v", test.corrupt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
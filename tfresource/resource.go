@@ -0,0 +1,135 @@
+// Package tfresource provides a typed, CRUD-style abstraction over the
+// resource RPCs of tfprotov5.ResourceServer.
+//
+// Providers implement Resource for a struct type tagged for use with the
+// asgotypes codec, and NewServer takes care of decoding the protocol's
+// DynamicValues into that struct, deciding which of Create, Update, or Delete
+// an ApplyResourceChange call corresponds to, and encoding the results and any
+// errors back into protocol responses.
+package tfresource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfstate"
+)
+
+// ErrNotFound should be returned, optionally wrapped, from Resource.Read when
+// the remote object no longer exists. The resource will be removed from state
+// rather than an error being reported.
+var ErrNotFound = errors.New("resource not found")
+
+// Resource is implemented by types that manage a single kind of remote
+// object. T is the Go representation of the resource's state, and must be a
+// struct using `tfsdk` tags to map its fields to the resource's attributes.
+//
+// Computed attributes that aren't known yet during Create and Update are
+// decoded as their zero value, so they should usually be pointer types.
+type Resource[T any] interface {
+	// Create creates the remote object described by `planned` and
+	// returns its state.
+	Create(ctx context.Context, planned T) (T, error)
+
+	// Read returns the current state of the remote object described by
+	// `current`. If the remote object no longer exists, Read should
+	// return an error wrapping ErrNotFound.
+	Read(ctx context.Context, current T) (T, error)
+
+	// Update updates the remote object described by `prior` to match
+	// `planned` and returns its new state.
+	Update(ctx context.Context, prior, planned T) (T, error)
+
+	// Delete deletes the remote object described by `current`.
+	Delete(ctx context.Context, current T) error
+}
+
+// Importer is an optional interface for Resources that support importing
+// existing remote objects into Terraform.
+type Importer[T any] interface {
+	// Import returns the state of the remote object identified by `id`.
+	// The result will be refreshed with Read before Terraform stores it.
+	Import(ctx context.Context, id string) (T, error)
+}
+
+// WriteOnlyApplier is an optional interface for Resources with write-only
+// attributes, whose values are only available in the configuration and are
+// never persisted to state. If a Resource implements it, its methods are
+// called instead of Resource.Create and Resource.Update, with the write-only
+// values decoded into `writeOnly` and every other field of it left as its
+// zero value. Write-only fields of `planned` and of the result are ignored.
+type WriteOnlyApplier[T any] interface {
+	CreateWriteOnly(ctx context.Context, planned, writeOnly T) (T, error)
+	UpdateWriteOnly(ctx context.Context, prior, planned, writeOnly T) (T, error)
+}
+
+// IdentityImporter is an optional interface for Resources that implement
+// tfidentity.Identified and support importing existing remote objects by
+// their identity, rather than by an ID.
+type IdentityImporter[T any] interface {
+	// ImportIdentity returns the state of the remote object identified by
+	// `identity`, which has only the resource's identity attributes set.
+	// The result will be refreshed with Read before Terraform stores it.
+	ImportIdentity(ctx context.Context, identity T) (T, error)
+}
+
+// IdentityUpgrader is an optional interface for Resources whose identity
+// schema version has been incremented. The returned Upgraders are keyed by
+// the version they upgrade from; see tfidentity.Chain.
+type IdentityUpgrader interface {
+	IdentityUpgraders() map[int64]tfstate.Upgrader
+}
+
+// SemanticEquality is an optional interface for Resources with attributes
+// whose values can be equivalent without being identical, like JSON
+// documents. The returned Registry is used to keep the prior form of such
+// values when reading and planning the resource.
+type SemanticEquality interface {
+	Equality() *tfequal.Registry
+}
+
+// StateUpgrader is an optional interface for Resources whose schema version
+// has been incremented. The returned Upgraders are keyed by the version they
+// upgrade from; see tfstate.Chain.
+type StateUpgrader interface {
+	StateUpgraders() map[int64]tfstate.Upgrader
+}
+
+// StateMover is an optional interface for Resources that state can be moved
+// to from other resource types, with a `moved` block. See tfstate.Mover.
+type StateMover interface {
+	StateMoves() []tfstate.Move
+}
+
+// PartialStateError is returned, optionally wrapped, from Resource.Create or
+// Resource.Update when the operation failed after changing the remote
+// object. The value returned alongside it should describe the remote object
+// as far as the operation got, and is saved to state using tfstate.Partial
+// so the object isn't lost.
+//
+// Terraform marks objects saved after a failed Create as tainted, so they're
+// destroyed and created again on the next apply. Objects saved after a failed
+// Update aren't tainted, and the next apply updates them again.
+type PartialStateError struct {
+	Err error
+
+	// RolledBack should be set if the changes made to the remote object
+	// were undone before returning, so the returned value is ignored. A
+	// failed Create then leaves nothing in state, rather than a tainted
+	// object, and a failed Update keeps the prior state.
+	RolledBack bool
+}
+
+// PartialState returns a PartialStateError wrapping `err`.
+func PartialState(err error) error {
+	return &PartialStateError{Err: err}
+}
+
+func (e *PartialStateError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PartialStateError) Unwrap() error {
+	return e.Err
+}
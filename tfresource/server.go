@@ -0,0 +1,552 @@
+package tfresource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfidentity"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfplan"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfstate"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ tfprotov5.ResourceServer = &Server[struct{}]{}
+
+// Server is a tfprotov5.ResourceServer for a single resource type, backed by
+// a Resource.
+type Server[T any] struct {
+	schema     *tfprotov5.Schema
+	typ        tftypes.Type
+	resource   Resource[T]
+	planner    *tfplan.Planner
+	plannerErr error
+	equality   *tfequal.Registry
+	upgrader   *tfstate.Chain
+	mover      *tfstate.Mover
+
+	// identity is the Resource's identity schema, or nil if it doesn't
+	// support resource identity.
+	identity         *tfprotov5.ResourceIdentitySchema
+	identityUpgrader *tfidentity.Chain
+}
+
+// NewServer returns a Server for the resource described by `schema`, backed
+// by `resource`. Plan modifiers declared using `tfplan` struct tags on T are
+// applied when planning.
+//
+// If the Resource implements tfidentity.Identified, the identity of the
+// resource is derived from its state using tfidentity.FromState and returned
+// from every RPC that returns state, so every identity attribute must also
+// be an attribute of the resource.
+func NewServer[T any](schema *tfprotov5.Schema, resource Resource[T]) *Server[T] {
+	modifiers, err := tfplan.RegistryFromStruct(new(T))
+	var equality *tfequal.Registry
+	if eq, ok := resource.(SemanticEquality); ok {
+		equality = eq.Equality()
+	}
+	upgrader := &tfstate.Chain{Schema: schema}
+	if u, ok := resource.(StateUpgrader); ok {
+		upgrader.Upgraders = u.StateUpgraders()
+	}
+	mover := &tfstate.Mover{Schema: schema}
+	if m, ok := resource.(StateMover); ok {
+		mover.Moves = m.StateMoves()
+	}
+	var identity *tfprotov5.ResourceIdentitySchema
+	if i, ok := resource.(tfidentity.Identified); ok {
+		identity = i.IdentitySchema()
+	}
+	identityUpgrader := &tfidentity.Chain{Schema: identity}
+	if u, ok := resource.(IdentityUpgrader); ok {
+		identityUpgrader.Upgraders = u.IdentityUpgraders()
+	}
+	return &Server[T]{
+		schema:   schema,
+		typ:      schema.ValueType(),
+		resource: resource,
+		planner: &tfplan.Planner{
+			Schema:    schema,
+			Modifiers: modifiers,
+			Equality:  equality,
+		},
+		plannerErr: err,
+		equality:   equality,
+		upgrader:   upgrader,
+		mover:      mover,
+
+		identity:         identity,
+		identityUpgrader: identityUpgrader,
+	}
+}
+
+// Schema returns the resource's schema.
+func (s *Server[T]) Schema() *tfprotov5.Schema {
+	return s.schema
+}
+
+// IdentitySchema returns the resource's identity schema, or nil if the
+// Resource doesn't implement tfidentity.Identified.
+func (s *Server[T]) IdentitySchema() *tfprotov5.ResourceIdentitySchema {
+	return s.identity
+}
+
+// PlansDestroy returns true, as PlanResourceChange plans destroys, so the
+// PlanDestroy capability can be enabled for the resource.
+func (s *Server[T]) PlansDestroy() bool {
+	return true
+}
+
+// MovesState returns whether the Resource implements StateMover and
+// declares any moves.
+func (s *Server[T]) MovesState() bool {
+	return len(s.mover.Moves) > 0
+}
+
+// Modifiers returns the plan Modifiers declared using `tfplan` struct tags
+// on T.
+func (s *Server[T]) Modifiers() *tfplan.Registry {
+	return s.planner.Modifiers
+}
+
+// Validators returns the Resource's Validators, if it implements
+// tfvalidate.Validated.
+func (s *Server[T]) Validators() *tfvalidate.Registry {
+	if v, ok := s.resource.(tfvalidate.Validated); ok {
+		return v.Validators()
+	}
+	return nil
+}
+
+// ValidateResourceTypeConfig checks that the config can be decoded into T.
+func (s *Server[T]) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	resp := &tfprotov5.ValidateResourceTypeConfigResponse{}
+	config, err := s.unmarshal(req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading configuration", err))
+		return resp, nil
+	}
+	var decoded T
+	d := asgotypes.Decoder{AllowUnknown: true}
+	if err := d.DecodeContext(ctx, config, &decoded); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Invalid configuration", err))
+	}
+	return resp, nil
+}
+
+// UpgradeResourceState upgrades the raw state to the current schema version
+// using the Resource's StateUpgraders, if it implements StateUpgrader. State
+// written by other schema versions is rejected otherwise.
+func (s *Server[T]) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	return s.upgrader.UpgradeResourceState(ctx, req), nil
+}
+
+// ReadResource calls Resource.Read, removing the resource from state if it
+// returns ErrNotFound. If the Resource implements SemanticEquality, values
+// in the result that are semantically equal to the current state are
+// replaced with the current values.
+func (s *Server[T]) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	resp := &tfprotov5.ReadResourceResponse{
+		Private: req.Private,
+	}
+	current, err := s.unmarshal(req.CurrentState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading state", err))
+		return resp, nil
+	}
+	if current.IsNull() {
+		resp.NewState = req.CurrentState
+		return resp, nil
+	}
+	var decoded T
+	if err := asgotypes.DecodeContext(ctx, current, &decoded); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading state", err))
+		return resp, nil
+	}
+	result, err := s.resource.Read(ctx, decoded)
+	if errors.Is(err, ErrNotFound) {
+		resp.NewState, err = s.marshal(tftypes.NewValue(s.typ, nil))
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading resource", err))
+		}
+		return resp, nil
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading resource", err))
+		return resp, nil
+	}
+	newState, err := asgotypes.EncodeContext(ctx, s.typ, result)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading resource", err))
+		return resp, nil
+	}
+	newState, err = s.equality.Normalize(current, newState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading resource", err))
+		return resp, nil
+	}
+	resp.NewState, err = s.marshal(newState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading resource", err))
+		return resp, nil
+	}
+	resp.NewIdentity, err = s.identityOf(newState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading resource identity", err))
+	}
+	return resp, nil
+}
+
+// PlanResourceChange plans the resource using tfplan.Planner. The planned
+// identity is derived from the planned state, or is the prior identity if
+// any identity attribute's planned value is unknown.
+func (s *Server[T]) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	if s.plannerErr != nil {
+		return &tfprotov5.PlanResourceChangeResponse{
+			Diagnostics: []*tfprotov5.Diagnostic{
+				diag.Error("Invalid plan modifiers", s.plannerErr),
+			},
+		}, nil
+	}
+	resp := s.planner.PlanResourceChange(ctx, req)
+	if s.identity == nil || resp.PlannedState == nil {
+		return resp, nil
+	}
+	planned, err := s.unmarshal(resp.PlannedState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error planning resource identity", err))
+		return resp, nil
+	}
+	identity, err := tfidentity.FromState(s.identity, planned)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error planning resource identity", err))
+		return resp, nil
+	}
+	if !identity.IsFullyKnown() {
+		resp.PlannedIdentity = req.PriorIdentity
+		return resp, nil
+	}
+	resp.PlannedIdentity, err = tfidentity.Marshal(s.identity, identity)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error planning resource identity", err))
+	}
+	return resp, nil
+}
+
+// ApplyResourceChange calls Resource.Create, Resource.Update, or
+// Resource.Delete, depending on the prior and planned states. The new state
+// is built from the planned state and the result using tfstate.Build.
+//
+// If Create or Update return an error wrapping a PartialStateError, the new
+// state is built from the result using tfstate.Partial instead, and returned
+// along with the error.
+//
+// If the Resource implements WriteOnlyApplier, its methods are called
+// instead of Create and Update, with the write-only values from the config.
+func (s *Server[T]) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	resp := &tfprotov5.ApplyResourceChangeResponse{
+		Private: req.PlannedPrivate,
+	}
+	prior, err := s.unmarshal(req.PriorState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading prior state", err))
+		return resp, nil
+	}
+	planned, err := s.unmarshal(req.PlannedState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading planned state", err))
+		return resp, nil
+	}
+
+	d := asgotypes.Decoder{AllowUnknown: true}
+	var decodedPrior, decodedPlanned T
+	if err := d.DecodeContext(ctx, prior, &decodedPrior); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading prior state", err))
+		return resp, nil
+	}
+	if err := d.DecodeContext(ctx, planned, &decodedPlanned); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading planned state", err))
+		return resp, nil
+	}
+
+	create, update := s.resource.Create, s.resource.Update
+	if applier, ok := s.resource.(WriteOnlyApplier[T]); ok && !planned.IsNull() {
+		writeOnly, err := s.writeOnly(ctx, req.Config)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading configuration", err))
+			return resp, nil
+		}
+		create = func(ctx context.Context, planned T) (T, error) {
+			return applier.CreateWriteOnly(ctx, planned, writeOnly)
+		}
+		update = func(ctx context.Context, prior, planned T) (T, error) {
+			return applier.UpdateWriteOnly(ctx, prior, planned, writeOnly)
+		}
+	}
+
+	switch {
+	case planned.IsNull():
+		if err := s.resource.Delete(ctx, decodedPrior); err != nil {
+			resp.NewState = req.PriorState
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error deleting resource", err))
+			return resp, nil
+		}
+		resp.NewState = req.PlannedState
+		return resp, nil
+	case prior.IsNull():
+		result, err := create(ctx, decodedPlanned)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error creating resource", err))
+			s.partial(resp, err, result)
+			return resp, nil
+		}
+		resp.NewState, resp.NewIdentity, err = s.build(planned, result)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error creating resource", err))
+		}
+		return resp, nil
+	default:
+		result, err := update(ctx, decodedPrior, decodedPlanned)
+		if err != nil {
+			resp.NewState = req.PriorState
+			resp.NewIdentity = req.PlannedIdentity
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error updating resource", err))
+			s.partial(resp, err, result)
+			return resp, nil
+		}
+		resp.NewState, resp.NewIdentity, err = s.build(planned, result)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error updating resource", err))
+		}
+		return resp, nil
+	}
+}
+
+// ImportResourceState calls Importer.Import if the Resource implements
+// Importer, and returns an error otherwise. Imports by identity, which have
+// no ID, call IdentityImporter.ImportIdentity with the identity decoded into
+// T instead.
+func (s *Server[T]) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	resp := &tfprotov5.ImportResourceStateResponse{}
+	var (
+		result T
+		err    error
+	)
+	if req.ID == "" && req.Identity != nil && s.identity != nil {
+		importer, ok := s.resource.(IdentityImporter[T])
+		if !ok {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Errorf("Resource import not supported", "The %s resource does not support import by identity.", req.TypeName))
+			return resp, nil
+		}
+		var identity T
+		identity, err = s.identityState(ctx, req.Identity)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading resource identity", err))
+			return resp, nil
+		}
+		result, err = importer.ImportIdentity(ctx, identity)
+	} else {
+		importer, ok := s.resource.(Importer[T])
+		if !ok {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Errorf("Resource import not supported", "The %s resource does not support import.", req.TypeName))
+			return resp, nil
+		}
+		result, err = importer.Import(ctx, req.ID)
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error importing resource", err))
+		return resp, nil
+	}
+	val, err := asgotypes.EncodeContext(ctx, s.typ, result)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error importing resource", err))
+		return resp, nil
+	}
+	state, err := s.marshal(val)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error importing resource", err))
+		return resp, nil
+	}
+	identity, err := s.identityOf(val)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error importing resource identity", err))
+		return resp, nil
+	}
+	resp.ImportedResources = []*tfprotov5.ImportedResource{
+		{
+			TypeName: req.TypeName,
+			State:    state,
+			Identity: identity,
+		},
+	}
+	return resp, nil
+}
+
+// MoveResourceState moves state from another resource type using the
+// Resource's StateMoves, if it implements StateMover. Moves from other
+// resource types are rejected otherwise.
+func (s *Server[T]) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	resp := s.mover.MoveResourceState(ctx, req)
+	if resp.TargetState == nil {
+		return resp, nil
+	}
+	state, err := s.unmarshal(resp.TargetState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error moving resource identity", err))
+		return resp, nil
+	}
+	resp.TargetIdentity, err = s.identityOf(state)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error moving resource identity", err))
+	}
+	return resp, nil
+}
+
+// UpgradeResourceIdentity upgrades the raw identity to the current identity
+// schema version using the Resource's IdentityUpgraders, if it implements
+// IdentityUpgrader. Identities written by other versions are rejected
+// otherwise, and an error is returned if the Resource doesn't implement
+// tfidentity.Identified.
+func (s *Server[T]) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	if s.identity != nil {
+		return s.identityUpgrader.UpgradeResourceIdentity(ctx, req), nil
+	}
+	return &tfprotov5.UpgradeResourceIdentityResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{
+			diag.Errorf("Resource identity not supported", "The %s resource does not support resource identity.", req.TypeName),
+		},
+	}, nil
+}
+
+// GenerateResourceConfig returns the state as the generated config, using
+// tfstate.GenerateConfig to null the attributes that can't be configured.
+func (s *Server[T]) GenerateResourceConfig(ctx context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	resp := &tfprotov5.GenerateResourceConfigResponse{}
+	state, err := s.unmarshal(req.State)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error reading state", err))
+		return resp, nil
+	}
+	config, err := tfstate.GenerateConfig(s.schema, state)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error generating resource config", err))
+		return resp, nil
+	}
+	dv, err := tfprotov5.NewDynamicValue(s.typ, config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error generating resource config", err))
+		return resp, nil
+	}
+	resp.Config = &dv
+	return resp, nil
+}
+
+func (s *Server[T]) unmarshal(dv *tfprotov5.DynamicValue) (tftypes.Value, error) {
+	if dv == nil {
+		return tftypes.NewValue(s.typ, nil), nil
+	}
+	return dv.Unmarshal(s.typ)
+}
+
+// marshal returns `val` as state, with any write-only attributes null.
+func (s *Server[T]) marshal(val tftypes.Value) (*tfprotov5.DynamicValue, error) {
+	val, err := tfstate.NullWriteOnly(s.schema, val)
+	if err != nil {
+		return nil, err
+	}
+	dv, err := tfprotov5.NewDynamicValue(s.typ, val)
+	if err != nil {
+		return nil, err
+	}
+	return &dv, nil
+}
+
+// writeOnly decodes the write-only attributes of `config` into T.
+func (s *Server[T]) writeOnly(ctx context.Context, config *tfprotov5.DynamicValue) (T, error) {
+	var decoded T
+	val, err := s.unmarshal(config)
+	if err != nil {
+		return decoded, err
+	}
+	val, err = tfstate.WriteOnly(s.schema, val)
+	if err != nil {
+		return decoded, err
+	}
+	err = asgotypes.DecodeContext(ctx, val, &decoded)
+	return decoded, err
+}
+
+// identityState decodes `data` into T, with every attribute that isn't an
+// identity attribute null.
+func (s *Server[T]) identityState(ctx context.Context, data *tfprotov5.ResourceIdentityData) (T, error) {
+	var decoded T
+	identity, err := tfidentity.Unmarshal(s.identity, data)
+	if err != nil {
+		return decoded, err
+	}
+	state, err := tfidentity.ToState(s.typ, identity)
+	if err != nil {
+		return decoded, err
+	}
+	err = asgotypes.DecodeContext(ctx, state, &decoded)
+	return decoded, err
+}
+
+// identityOf returns the identity data of the resource whose state is
+// `state`, or nil if the Resource doesn't support resource identity.
+func (s *Server[T]) identityOf(state tftypes.Value) (*tfprotov5.ResourceIdentityData, error) {
+	if s.identity == nil {
+		return nil, nil
+	}
+	identity, err := tfidentity.FromState(s.identity, state)
+	if err != nil {
+		return nil, err
+	}
+	return tfidentity.Marshal(s.identity, identity)
+}
+
+// partial sets the new state on `resp` to the partial state in `v` if `err`
+// wraps a PartialStateError that wasn't rolled back.
+func (s *Server[T]) partial(resp *tfprotov5.ApplyResourceChangeResponse, err error, v T) {
+	var partialErr *PartialStateError
+	if !errors.As(err, &partialErr) || partialErr.RolledBack {
+		return
+	}
+	val, err := tfstate.Partial(s.schema, v)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error saving partial state", err))
+		return
+	}
+	dv, err := s.marshal(val)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error saving partial state", err))
+		return
+	}
+	identity, err := s.identityOf(val)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error saving partial state", err))
+		return
+	}
+	resp.NewState = dv
+	resp.NewIdentity = identity
+}
+
+func (s *Server[T]) build(planned tftypes.Value, v T) (*tfprotov5.DynamicValue, *tfprotov5.ResourceIdentityData, error) {
+	val, err := tfstate.Build(s.schema, planned, v)
+	if err != nil {
+		return nil, nil, err
+	}
+	dv, err := s.marshal(val)
+	if err != nil {
+		return nil, nil, err
+	}
+	identity, err := s.identityOf(val)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dv, identity, nil
+}
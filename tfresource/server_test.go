@@ -0,0 +1,701 @@
+package tfresource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfstate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type widget struct {
+	ID   *string `tfsdk:"id" tfplan:"use_state_for_unknown"`
+	Name string  `tfsdk:"name"`
+}
+
+type widgetResource struct {
+	widgets map[string]string
+	nextID  int
+}
+
+func (w *widgetResource) Create(ctx context.Context, planned widget) (widget, error) {
+	w.nextID++
+	id := fmt.Sprintf("w-%d", w.nextID)
+	w.widgets[id] = planned.Name
+	planned.ID = &id
+	return planned, nil
+}
+
+func (w *widgetResource) Read(ctx context.Context, current widget) (widget, error) {
+	name, ok := w.widgets[*current.ID]
+	if !ok {
+		return widget{}, fmt.Errorf("widget %s: %w", *current.ID, ErrNotFound)
+	}
+	current.Name = name
+	return current, nil
+}
+
+func (w *widgetResource) Update(ctx context.Context, prior, planned widget) (widget, error) {
+	w.widgets[*prior.ID] = planned.Name
+	planned.ID = prior.ID
+	return planned, nil
+}
+
+func (w *widgetResource) Delete(ctx context.Context, current widget) error {
+	delete(w.widgets, *current.ID)
+	return nil
+}
+
+var widgetSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Computed: true},
+			{Name: "name", Type: tftypes.String, Required: true},
+		},
+	},
+}
+
+var widgetType = widgetSchema.ValueType()
+
+func widgetValue(t *testing.T, id interface{}, name string) *tfprotov5.DynamicValue {
+	t.Helper()
+	dv, err := tfprotov5.NewDynamicValue(widgetType, tftypes.NewValue(widgetType, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, id),
+		"name": tftypes.NewValue(tftypes.String, name),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+func nullWidget(t *testing.T) *tfprotov5.DynamicValue {
+	t.Helper()
+	dv, err := tfprotov5.NewDynamicValue(widgetType, tftypes.NewValue(widgetType, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+func assertState(t *testing.T, expected, got *tfprotov5.DynamicValue) {
+	t.Helper()
+	expectedVal, err := expected.Unmarshal(widgetType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotVal, err := got.Unmarshal(widgetType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expectedVal, gotVal); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func assertNoDiags(t *testing.T, diags []*tfprotov5.Diagnostic) {
+	t.Helper()
+	for _, diag := range diags {
+		t.Errorf("unexpected diagnostic: %s: %s", diag.Summary, diag.Detail)
+	}
+}
+
+func TestServerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	res := &widgetResource{widgets: map[string]string{}}
+	srv := NewServer[widget](widgetSchema, res)
+
+	plan, err := srv.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		PriorState:       nullWidget(t),
+		ProposedNewState: widgetValue(t, nil, "foo"),
+		Config:           widgetValue(t, nil, "foo"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, plan.Diagnostics)
+	assertState(t, widgetValue(t, tftypes.UnknownValue, "foo"), plan.PlannedState)
+
+	created, err := srv.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		PriorState:   nullWidget(t),
+		PlannedState: plan.PlannedState,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, created.Diagnostics)
+	assertState(t, widgetValue(t, "w-1", "foo"), created.NewState)
+
+	plan, err = srv.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		PriorState:       created.NewState,
+		ProposedNewState: widgetValue(t, "w-1", "bar"),
+		Config:           widgetValue(t, nil, "bar"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, plan.Diagnostics)
+	assertState(t, widgetValue(t, "w-1", "bar"), plan.PlannedState)
+
+	updated, err := srv.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		PriorState:   created.NewState,
+		PlannedState: plan.PlannedState,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, updated.Diagnostics)
+	assertState(t, widgetValue(t, "w-1", "bar"), updated.NewState)
+
+	res.widgets["w-1"] = "drifted"
+	read, err := srv.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		CurrentState: updated.NewState,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, read.Diagnostics)
+	assertState(t, widgetValue(t, "w-1", "drifted"), read.NewState)
+
+	deleted, err := srv.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		PriorState:   read.NewState,
+		PlannedState: nullWidget(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, deleted.Diagnostics)
+	assertState(t, nullWidget(t), deleted.NewState)
+
+	gone, err := srv.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		CurrentState: read.NewState,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, gone.Diagnostics)
+	assertState(t, nullWidget(t), gone.NewState)
+}
+
+func TestServerImportNotSupported(t *testing.T) {
+	srv := NewServer[widget](widgetSchema, &widgetResource{})
+	resp, err := srv.ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{
+		TypeName: "example_widget",
+		ID:       "w-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Resource import not supported" {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+}
+
+type caseInsensitiveWidgetResource struct {
+	*widgetResource
+}
+
+func (caseInsensitiveWidgetResource) Equality() *tfequal.Registry {
+	r := &tfequal.Registry{}
+	r.AddPath(tftypes.NewAttributePath().WithAttributeName("name"), tfequal.CaseInsensitive())
+	return r
+}
+
+func TestServerReadSemanticEquality(t *testing.T) {
+	res := &widgetResource{widgets: map[string]string{"w-1": "FOO"}}
+	srv := NewServer[widget](widgetSchema, caseInsensitiveWidgetResource{res})
+
+	read, err := srv.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+		CurrentState: widgetValue(t, "w-1", "foo"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, read.Diagnostics)
+	assertState(t, widgetValue(t, "w-1", "foo"), read.NewState)
+
+	res.widgets["w-1"] = "bar"
+	read, err = srv.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+		CurrentState: widgetValue(t, "w-1", "foo"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, read.Diagnostics)
+	assertState(t, widgetValue(t, "w-1", "bar"), read.NewState)
+}
+
+type upgradingWidgetResource struct {
+	*widgetResource
+}
+
+func (upgradingWidgetResource) StateUpgraders() map[int64]tfstate.Upgrader {
+	type widgetV0 struct {
+		ID    string `tfsdk:"id"`
+		Title string `tfsdk:"title"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":    tftypes.String,
+		"title": tftypes.String,
+	}}
+	return map[int64]tfstate.Upgrader{
+		0: tfstate.UpgradeFunc(typ, func(ctx context.Context, prior widgetV0) (widget, error) {
+			return widget{ID: &prior.ID, Name: prior.Title}, nil
+		}),
+	}
+}
+
+func TestServerUpgradeResourceState(t *testing.T) {
+	schema := &tfprotov5.Schema{
+		Version: 1,
+		Block:   widgetSchema.Block,
+	}
+	srv := NewServer[widget](schema, upgradingWidgetResource{&widgetResource{}})
+	resp, err := srv.UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: []byte(`{"id": "w-1", "title": "foo"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, resp.Diagnostics)
+	assertState(t, widgetValue(t, "w-1", "foo"), resp.UpgradedState)
+
+	srv = NewServer[widget](schema, &widgetResource{})
+	resp, err = srv.UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: []byte(`{"id": "w-1", "title": "foo"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Detail != "upgrading state from schema version 0 is not supported" {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+}
+
+type failingWidgetResource struct {
+	*widgetResource
+	err error
+}
+
+func (f failingWidgetResource) Create(ctx context.Context, planned widget) (widget, error) {
+	id := "w-1"
+	planned.ID = &id
+	return planned, f.err
+}
+
+func (f failingWidgetResource) Update(ctx context.Context, prior, planned widget) (widget, error) {
+	planned.ID = prior.ID
+	return planned, f.err
+}
+
+func TestServerApplyPartialState(t *testing.T) {
+	type testCase struct {
+		err      error
+		prior    *tfprotov5.DynamicValue
+		planned  *tfprotov5.DynamicValue
+		expected *tfprotov5.DynamicValue
+	}
+	cases := map[string]testCase{
+		"create": {
+			err:      errors.New("boom"),
+			prior:    nullWidget(t),
+			planned:  widgetValue(t, tftypes.UnknownValue, "foo"),
+			expected: nil,
+		},
+		"create-partial": {
+			err:      fmt.Errorf("waiting for widget: %w", PartialState(errors.New("boom"))),
+			prior:    nullWidget(t),
+			planned:  widgetValue(t, tftypes.UnknownValue, "foo"),
+			expected: widgetValue(t, "w-1", "foo"),
+		},
+		"create-rolled-back": {
+			err:      &PartialStateError{Err: errors.New("boom"), RolledBack: true},
+			prior:    nullWidget(t),
+			planned:  widgetValue(t, tftypes.UnknownValue, "foo"),
+			expected: nil,
+		},
+		"update": {
+			err:      errors.New("boom"),
+			prior:    widgetValue(t, "w-1", "foo"),
+			planned:  widgetValue(t, "w-1", "bar"),
+			expected: widgetValue(t, "w-1", "foo"),
+		},
+		"update-partial": {
+			err:      PartialState(errors.New("boom")),
+			prior:    widgetValue(t, "w-1", "foo"),
+			planned:  widgetValue(t, "w-1", "bar"),
+			expected: widgetValue(t, "w-1", "bar"),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			srv := NewServer[widget](widgetSchema, failingWidgetResource{&widgetResource{}, tc.err})
+			resp, err := srv.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+				PriorState:   tc.prior,
+				PlannedState: tc.planned,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Detail != tc.err.Error() {
+				t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+			}
+			if tc.expected == nil {
+				if resp.NewState != nil {
+					t.Errorf("expected no new state, got %+v", resp.NewState)
+				}
+				return
+			}
+			assertState(t, tc.expected, resp.NewState)
+		})
+	}
+}
+
+type identifiedWidgetResource struct {
+	*widgetResource
+}
+
+var widgetIdentitySchema = &tfprotov5.ResourceIdentitySchema{
+	IdentityAttributes: []*tfprotov5.ResourceIdentitySchemaAttribute{
+		{Name: "id", Type: tftypes.String, RequiredForImport: true},
+	},
+}
+
+func (identifiedWidgetResource) IdentitySchema() *tfprotov5.ResourceIdentitySchema {
+	return widgetIdentitySchema
+}
+
+func (i identifiedWidgetResource) ImportIdentity(ctx context.Context, identity widget) (widget, error) {
+	return i.Read(ctx, identity)
+}
+
+func widgetIdentity(t *testing.T, id string) *tfprotov5.ResourceIdentityData {
+	t.Helper()
+	typ := widgetIdentitySchema.ValueType()
+	dv, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, id),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tfprotov5.ResourceIdentityData{IdentityData: &dv}
+}
+
+func TestServerIdentity(t *testing.T) {
+	ctx := context.Background()
+	res := &widgetResource{widgets: map[string]string{}}
+	srv := NewServer[widget](widgetSchema, identifiedWidgetResource{res})
+	if srv.IdentitySchema() != widgetIdentitySchema {
+		t.Errorf("unexpected identity schema: %+v", srv.IdentitySchema())
+	}
+
+	plan, err := srv.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		PriorState:       nullWidget(t),
+		ProposedNewState: widgetValue(t, nil, "foo"),
+		Config:           widgetValue(t, nil, "foo"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, plan.Diagnostics)
+	if plan.PlannedIdentity != nil {
+		t.Errorf("expected no planned identity while the id is unknown, got %+v", plan.PlannedIdentity)
+	}
+
+	apply, err := srv.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		PriorState:   nullWidget(t),
+		PlannedState: plan.PlannedState,
+		Config:       widgetValue(t, nil, "foo"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, apply.Diagnostics)
+	if diff := cmp.Diff(widgetIdentity(t, "w-1"), apply.NewIdentity); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	plan, err = srv.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		PriorState:       apply.NewState,
+		PriorIdentity:    apply.NewIdentity,
+		ProposedNewState: widgetValue(t, "w-1", "bar"),
+		Config:           widgetValue(t, nil, "bar"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, plan.Diagnostics)
+	if diff := cmp.Diff(widgetIdentity(t, "w-1"), plan.PlannedIdentity); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	read, err := srv.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		CurrentState:    apply.NewState,
+		CurrentIdentity: apply.NewIdentity,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, read.Diagnostics)
+	if diff := cmp.Diff(widgetIdentity(t, "w-1"), read.NewIdentity); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	imported, err := srv.ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+		TypeName: "example_widget",
+		Identity: widgetIdentity(t, "w-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, imported.Diagnostics)
+	if len(imported.ImportedResources) != 1 {
+		t.Fatalf("expected 1 imported resource, got %d", len(imported.ImportedResources))
+	}
+	assertState(t, widgetValue(t, "w-1", "foo"), imported.ImportedResources[0].State)
+	if diff := cmp.Diff(widgetIdentity(t, "w-1"), imported.ImportedResources[0].Identity); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	missing, err := srv.ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+		TypeName: "example_widget",
+		Identity: widgetIdentity(t, "w-2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing.Diagnostics) != 1 || missing.Diagnostics[0].Summary != "Error importing resource" {
+		t.Errorf("unexpected diagnostics: %+v", missing.Diagnostics)
+	}
+	if len(missing.ImportedResources) != 0 {
+		t.Errorf("expected no imported resources, got %d", len(missing.ImportedResources))
+	}
+
+	upgraded, err := srv.UpgradeResourceIdentity(ctx, &tfprotov5.UpgradeResourceIdentityRequest{
+		RawIdentity: &tfprotov5.RawState{JSON: []byte(`{"id": "w-1"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, upgraded.Diagnostics)
+	if diff := cmp.Diff(widgetIdentity(t, "w-1"), upgraded.UpgradedIdentity); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+type secret struct {
+	ID       *string `tfsdk:"id"`
+	Name     string  `tfsdk:"name"`
+	Password string  `tfsdk:"password"`
+}
+
+type secretResource struct {
+	passwords map[string]string
+}
+
+func (s *secretResource) Create(ctx context.Context, planned secret) (secret, error) {
+	return secret{}, errors.New("expected CreateWriteOnly to be called")
+}
+
+func (s *secretResource) CreateWriteOnly(ctx context.Context, planned, writeOnly secret) (secret, error) {
+	id := planned.Name
+	s.passwords[id] = writeOnly.Password
+	planned.ID = &id
+	planned.Password = writeOnly.Password
+	return planned, nil
+}
+
+func (s *secretResource) Read(ctx context.Context, current secret) (secret, error) {
+	current.Password = s.passwords[*current.ID]
+	return current, nil
+}
+
+func (s *secretResource) Update(ctx context.Context, prior, planned secret) (secret, error) {
+	return secret{}, errors.New("expected UpdateWriteOnly to be called")
+}
+
+func (s *secretResource) UpdateWriteOnly(ctx context.Context, prior, planned, writeOnly secret) (secret, error) {
+	s.passwords[*prior.ID] = writeOnly.Password
+	planned.ID = prior.ID
+	return planned, nil
+}
+
+func (s *secretResource) Delete(ctx context.Context, current secret) error {
+	delete(s.passwords, *current.ID)
+	return nil
+}
+
+func TestServerWriteOnly(t *testing.T) {
+	ctx := context.Background()
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "id", Type: tftypes.String, Computed: true},
+				{Name: "name", Type: tftypes.String, Required: true},
+				{Name: "password", Type: tftypes.String, Optional: true, WriteOnly: true},
+			},
+		},
+	}
+	typ := schema.ValueType()
+	value := func(id, name, password interface{}) *tfprotov5.DynamicValue {
+		t.Helper()
+		dv, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, map[string]tftypes.Value{
+			"id":       tftypes.NewValue(tftypes.String, id),
+			"name":     tftypes.NewValue(tftypes.String, name),
+			"password": tftypes.NewValue(tftypes.String, password),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &dv
+	}
+	null, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := &secretResource{passwords: map[string]string{}}
+	srv := NewServer[secret](schema, res)
+
+	plan, err := srv.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		PriorState:       &null,
+		ProposedNewState: value(nil, "foo", "hunter2"),
+		Config:           value(nil, "foo", "hunter2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, plan.Diagnostics)
+	if diff := cmp.Diff(value(tftypes.UnknownValue, "foo", nil), plan.PlannedState); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	apply, err := srv.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		PriorState:   &null,
+		PlannedState: plan.PlannedState,
+		Config:       value(nil, "foo", "hunter2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, apply.Diagnostics)
+	if diff := cmp.Diff(value("foo", "foo", nil), apply.NewState); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if res.passwords["foo"] != "hunter2" {
+		t.Errorf("expected password %q, got %q", "hunter2", res.passwords["foo"])
+	}
+
+	apply, err = srv.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		PriorState:   value("foo", "foo", nil),
+		PlannedState: value("foo", "foo", nil),
+		Config:       value(nil, "foo", "correct-horse"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, apply.Diagnostics)
+	if res.passwords["foo"] != "correct-horse" {
+		t.Errorf("expected password %q, got %q", "correct-horse", res.passwords["foo"])
+	}
+
+	read, err := srv.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		CurrentState: value("foo", "foo", nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, read.Diagnostics)
+	if diff := cmp.Diff(value("foo", "foo", nil), read.NewState); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+type movingWidgetResource struct {
+	*widgetResource
+}
+
+func (movingWidgetResource) StateMoves() []tfstate.Move {
+	type gadget struct {
+		ID    string `tfsdk:"id"`
+		Title string `tfsdk:"title"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":    tftypes.String,
+		"title": tftypes.String,
+	}}
+	return []tfstate.Move{
+		{
+			TypeName: "example_gadget",
+			Transformer: tfstate.TransformFunc(typ, func(ctx context.Context, source gadget) (widget, error) {
+				return widget{ID: &source.ID, Name: source.Title}, nil
+			}),
+		},
+	}
+}
+
+func TestServerMoveResourceState(t *testing.T) {
+	srv := NewServer[widget](widgetSchema, movingWidgetResource{&widgetResource{}})
+	resp, err := srv.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "example_gadget",
+		SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id": "g-1", "title": "foo"}`)},
+		TargetTypeName: "example_widget",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, resp.Diagnostics)
+	assertState(t, widgetValue(t, "g-1", "foo"), resp.TargetState)
+
+	srv = NewServer[widget](widgetSchema, &widgetResource{})
+	resp, err = srv.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "example_gadget",
+		SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id": "g-1", "title": "foo"}`)},
+		TargetTypeName: "example_widget",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Resource move not supported" {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+}
+
+func TestServerGenerateResourceConfig(t *testing.T) {
+	srv := NewServer[widget](widgetSchema, &widgetResource{})
+	resp, err := srv.GenerateResourceConfig(context.Background(), &tfprotov5.GenerateResourceConfigRequest{
+		TypeName: "example_widget",
+		State:    widgetValue(t, "w-1", "foo"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiags(t, resp.Diagnostics)
+	assertState(t, widgetValue(t, nil, "foo"), resp.Config)
+}
+
+func TestServerImportIdentityCanceled(t *testing.T) {
+	res := &widgetResource{widgets: map[string]string{"w-1": "foo"}}
+	srv := NewServer[widget](widgetSchema, identifiedWidgetResource{res})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resp, err := srv.ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+		TypeName: "example_widget",
+		Identity: widgetIdentity(t, "w-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Error reading resource identity" || !strings.Contains(resp.Diagnostics[0].Detail, context.Canceled.Error()) {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,82 @@
+// Package tfretry provides helpers for waiting on remote APIs that are
+// eventually consistent, or that perform long-running operations.
+//
+// Retry calls a function until it stops returning retryable errors, and
+// StateChangeConf polls a remote object until it reaches one of a set of
+// target states. Both back off exponentially between attempts, give up when
+// their timeout elapses, and return as soon as their context is cancelled,
+// so they stop promptly when Terraform calls StopProvider.
+package tfretry
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultMinInterval is the delay before the first retry when
+	// Backoff.Min is zero.
+	DefaultMinInterval = 500 * time.Millisecond
+
+	// DefaultMaxInterval is the longest delay between retries when
+	// Backoff.Max is zero.
+	DefaultMaxInterval = 10 * time.Second
+
+	// DefaultMultiplier is the factor the delay grows by after each retry
+	// when Backoff.Multiplier is zero.
+	DefaultMultiplier = 2
+)
+
+// Backoff describes how long to wait between attempts. The zero value uses
+// DefaultMinInterval, DefaultMaxInterval, and DefaultMultiplier.
+type Backoff struct {
+	// Min is the delay before the first retry.
+	Min time.Duration
+
+	// Max is the longest delay between retries.
+	Max time.Duration
+
+	// Multiplier is the factor the delay grows by after each retry. A
+	// Multiplier of 1 retries at a fixed interval.
+	Multiplier float64
+}
+
+func (b Backoff) min() time.Duration {
+	if b.Min <= 0 {
+		return DefaultMinInterval
+	}
+	return b.Min
+}
+
+// next returns the delay to use after `d`.
+func (b Backoff) next(d time.Duration) time.Duration {
+	max := b.Max
+	if max <= 0 {
+		max = DefaultMaxInterval
+	}
+	mult := b.Multiplier
+	if mult <= 0 {
+		mult = DefaultMultiplier
+	}
+	d = time.Duration(float64(d) * mult)
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// sleep waits for `d`, returning early with the context's error if `ctx` is
+// done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
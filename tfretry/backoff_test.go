@@ -0,0 +1,38 @@
+package tfretry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffNext(t *testing.T) {
+	type testCase struct {
+		backoff  Backoff
+		expected []time.Duration
+	}
+	cases := map[string]testCase{
+		"default": {
+			expected: []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second},
+		},
+		"custom": {
+			backoff:  Backoff{Min: time.Second, Max: 5 * time.Second, Multiplier: 3},
+			expected: []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		"fixed": {
+			backoff:  Backoff{Min: time.Second, Multiplier: 1},
+			expected: []time.Duration{time.Second, time.Second, time.Second},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			d := tc.backoff.min()
+			for i, expected := range tc.expected {
+				if d != expected {
+					t.Errorf("expected interval %d to be %s, got %s", i, expected, d)
+				}
+				d = tc.backoff.next(d)
+			}
+		})
+	}
+}
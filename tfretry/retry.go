@@ -0,0 +1,124 @@
+package tfretry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetryableError marks `err` as retryable, so Retry calls the function
+// again rather than returning it.
+func RetryableError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err}
+}
+
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// IsRetryable returns true if `err` is or wraps an error marked using
+// RetryableError.
+func IsRetryable(err error) bool {
+	var r *retryableError
+	return errors.As(err, &r)
+}
+
+// TimeoutError is returned when a Retry or StateChangeConf gives up because
+// its timeout elapsed.
+type TimeoutError struct {
+	// Timeout is the timeout that elapsed.
+	Timeout time.Duration
+
+	// LastError is the last error returned by the function being retried,
+	// if any.
+	LastError error
+
+	// LastState is the last state the object being waited on was in, if
+	// any.
+	LastState string
+
+	// ExpectedState lists the states that were being waited for, if any.
+	ExpectedState []string
+}
+
+func (e *TimeoutError) Error() string {
+	msg := fmt.Sprintf("timeout after %s", e.Timeout)
+	if len(e.ExpectedState) > 0 {
+		msg = fmt.Sprintf("timeout after %s while waiting for state to become %q", e.Timeout, e.ExpectedState)
+	}
+	if e.LastState != "" {
+		msg += fmt.Sprintf(" (last state: %q)", e.LastState)
+	}
+	if e.LastError != nil {
+		msg += ": " + e.LastError.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the last error, if any.
+func (e *TimeoutError) Unwrap() error {
+	return e.LastError
+}
+
+// Retry calls `f` until it returns nil or an error not marked with
+// RetryableError, using the default Backoff. See Backoff.Retry.
+func Retry(ctx context.Context, timeout time.Duration, f func(ctx context.Context) error) error {
+	return Backoff{}.Retry(ctx, timeout, f)
+}
+
+// Retry calls `f` until it returns nil or an error not marked with
+// RetryableError, which is returned with the mark removed, waiting between
+// attempts as described by `b`.
+//
+// If `timeout` is greater than zero, or `ctx` has a deadline, and it elapses
+// first, a *TimeoutError holding the last error is returned. If `ctx` is
+// cancelled first, its error is returned.
+func (b Backoff) Retry(ctx context.Context, timeout time.Duration, f func(ctx context.Context) error) error {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+	interval := b.min()
+	for {
+		err := f(ctx)
+		if err == nil {
+			return nil
+		}
+		var r *retryableError
+		if !errors.As(err, &r) {
+			return err
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return timeoutError(err, &TimeoutError{Timeout: timeout, LastError: r.err})
+		}
+		interval = b.next(interval)
+	}
+}
+
+// withTimeout returns a context that's done after `timeout`, or `ctx`
+// unchanged if `timeout` isn't greater than zero.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timeoutError returns `timeoutErr` if `err` was caused by a deadline
+// elapsing, and `err` otherwise.
+func timeoutError(err error, timeoutErr *TimeoutError) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return timeoutErr
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package tfretry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var testBackoff = Backoff{Min: time.Millisecond, Max: 5 * time.Millisecond}
+
+func TestRetry(t *testing.T) {
+	type testCase struct {
+		errs        []error
+		timeout     time.Duration
+		expectedErr string
+		calls       int
+	}
+	cases := map[string]testCase{
+		"success": {
+			calls: 1,
+		},
+		"retries": {
+			errs:  []error{RetryableError(errors.New("not yet")), RetryableError(errors.New("not yet"))},
+			calls: 3,
+		},
+		"non-retryable": {
+			errs:        []error{RetryableError(errors.New("not yet")), errors.New("boom")},
+			expectedErr: "boom",
+			calls:       2,
+		},
+		"timeout": {
+			errs:        []error{RetryableError(errors.New("not yet"))},
+			timeout:     20 * time.Millisecond,
+			expectedErr: "timeout after 20ms: not yet",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			err := testBackoff.Retry(context.Background(), tc.timeout, func(ctx context.Context) error {
+				calls++
+				if len(tc.errs) == 0 {
+					return nil
+				}
+				if calls > len(tc.errs) {
+					if tc.timeout > 0 {
+						return tc.errs[len(tc.errs)-1]
+					}
+					return nil
+				}
+				return tc.errs[calls-1]
+			})
+			if err != nil {
+				if tc.expectedErr == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %q", tc.expectedErr, err.Error())
+				}
+			} else if tc.expectedErr != "" {
+				t.Fatalf("expected error %q, got none", tc.expectedErr)
+			}
+			if tc.calls > 0 && calls != tc.calls {
+				t.Errorf("expected %d calls, got %d", tc.calls, calls)
+			}
+		})
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := testBackoff.Retry(ctx, 0, func(ctx context.Context) error {
+		cancel()
+		return RetryableError(errors.New("not yet"))
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	if IsRetryable(errors.New("boom")) {
+		t.Error("expected plain error not to be retryable")
+	}
+	if !IsRetryable(RetryableError(errors.New("boom"))) {
+		t.Error("expected error to be retryable")
+	}
+	if RetryableError(nil) != nil {
+		t.Error("expected RetryableError(nil) to be nil")
+	}
+}
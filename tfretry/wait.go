@@ -0,0 +1,136 @@
+package tfretry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RefreshFunc returns the current state of the object being waited on,
+// along with a representation of the object that's returned once it reaches
+// a target state.
+type RefreshFunc[T any] func(ctx context.Context) (result T, state string, err error)
+
+// UnexpectedStateError is returned when the object being waited on reaches a
+// state that's neither pending nor a target.
+type UnexpectedStateError struct {
+	State         string
+	ExpectedState []string
+}
+
+func (e *UnexpectedStateError) Error() string {
+	return fmt.Sprintf("unexpected state %q, wanted target %q", e.State, e.ExpectedState)
+}
+
+// StateChangeConf waits for an object to move from one of its Pending states
+// to one of its Target states, like waiting for a newly created instance to
+// be "running" or a deleted one to be "gone".
+type StateChangeConf[T any] struct {
+	// Pending are the states the object may be in while it's changing.
+	Pending []string
+
+	// Target are the states the object is expected to end up in.
+	Target []string
+
+	// Refresh returns the object and its current state. If it returns an
+	// error, waiting stops and the error is returned, unless the error is
+	// marked with RetryableError.
+	Refresh RefreshFunc[T]
+
+	// Timeout is how long to wait for the object to reach a target state.
+	// If it's zero, Wait waits until its context is done.
+	Timeout time.Duration
+
+	// Delay is how long to wait before refreshing the object for the
+	// first time.
+	Delay time.Duration
+
+	// Backoff describes how long to wait between refreshes.
+	Backoff Backoff
+
+	// ContinuousTargetOccurrence is the number of consecutive refreshes
+	// that must find the object in a target state before Wait returns,
+	// for APIs whose reads may briefly disagree with each other. It
+	// defaults to 1.
+	ContinuousTargetOccurrence int
+}
+
+// Wait refreshes the object until it reaches a target state, and returns it.
+//
+// A *TimeoutError is returned if Timeout, or the deadline of `ctx`, elapses
+// first, and an *UnexpectedStateError if the object reaches a state that's
+// neither pending nor a target. If Refresh returns any other error that
+// isn't retryable, it's returned as is. In each case the last object Refresh
+// returned without an error is returned alongside the error. If `ctx` is
+// cancelled, its error is returned.
+func (c *StateChangeConf[T]) Wait(ctx context.Context) (T, error) {
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	var (
+		result    T
+		lastState string
+		lastErr   error
+		targets   int
+	)
+	timeout := func(err error) error {
+		return timeoutError(err, &TimeoutError{
+			Timeout:       c.Timeout,
+			LastError:     lastErr,
+			LastState:     lastState,
+			ExpectedState: c.Target,
+		})
+	}
+
+	if err := sleep(ctx, c.Delay); err != nil {
+		return result, timeout(err)
+	}
+	occurrences := c.ContinuousTargetOccurrence
+	if occurrences <= 0 {
+		occurrences = 1
+	}
+	interval := c.Backoff.min()
+	for {
+		res, state, err := c.Refresh(ctx)
+		var r *retryableError
+		switch {
+		case err == nil:
+			result, lastState, lastErr = res, state, nil
+		case errors.As(err, &r):
+			lastErr = r.err
+		case errors.Is(err, context.DeadlineExceeded):
+			return result, timeout(err)
+		default:
+			return result, err
+		}
+
+		if err == nil {
+			switch {
+			case contains(c.Target, state):
+				targets++
+				if targets >= occurrences {
+					return result, nil
+				}
+			case contains(c.Pending, state):
+				targets = 0
+			default:
+				return result, &UnexpectedStateError{State: state, ExpectedState: c.Target}
+			}
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return result, timeout(err)
+		}
+		interval = c.Backoff.next(interval)
+	}
+}
+
+func contains(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
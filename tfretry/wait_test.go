@@ -0,0 +1,119 @@
+package tfretry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testObject struct {
+	ID    string
+	State string
+}
+
+func TestStateChangeConfWait(t *testing.T) {
+	type testCase struct {
+		states      []string
+		errs        map[int]error
+		timeout     time.Duration
+		occurrences int
+		expected    testObject
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"target": {
+			states:   []string{"pending", "pending", "ready"},
+			expected: testObject{ID: "a", State: "ready"},
+		},
+		"unexpected": {
+			states:      []string{"pending", "failed"},
+			expected:    testObject{ID: "a", State: "failed"},
+			expectedErr: `unexpected state "failed", wanted target ["ready"]`,
+		},
+		"error": {
+			states:      []string{"pending", "pending"},
+			errs:        map[int]error{1: errors.New("boom")},
+			expected:    testObject{ID: "a", State: "pending"},
+			expectedErr: "boom",
+		},
+		"first-error": {
+			states:      []string{"pending"},
+			errs:        map[int]error{0: errors.New("boom")},
+			expectedErr: "boom",
+		},
+		"retryable-error": {
+			states:   []string{"pending", "pending", "ready"},
+			errs:     map[int]error{1: RetryableError(errors.New("throttled"))},
+			expected: testObject{ID: "a", State: "ready"},
+		},
+		"continuous-target": {
+			states:      []string{"ready", "pending", "ready", "ready"},
+			occurrences: 2,
+			expected:    testObject{ID: "a", State: "ready"},
+		},
+		"timeout": {
+			states:      []string{"pending"},
+			timeout:     20 * time.Millisecond,
+			expected:    testObject{ID: "a", State: "pending"},
+			expectedErr: `timeout after 20ms while waiting for state to become ["ready"] (last state: "pending")`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			conf := &StateChangeConf[testObject]{
+				Pending: []string{"pending"},
+				Target:  []string{"ready"},
+				Refresh: func(ctx context.Context) (testObject, string, error) {
+					n := calls
+					calls++
+					if err, ok := tc.errs[n]; ok {
+						return testObject{}, "", err
+					}
+					if n >= len(tc.states) {
+						n = len(tc.states) - 1
+					}
+					return testObject{ID: "a", State: tc.states[n]}, tc.states[n], nil
+				},
+				Timeout:                    tc.timeout,
+				Backoff:                    testBackoff,
+				ContinuousTargetOccurrence: tc.occurrences,
+			}
+			got, err := conf.Wait(context.Background())
+			if err != nil {
+				if tc.expectedErr == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %q", tc.expectedErr, err.Error())
+				}
+			} else if tc.expectedErr != "" {
+				t.Fatalf("expected error %q, got none", tc.expectedErr)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestStateChangeConfWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conf := &StateChangeConf[testObject]{
+		Pending: []string{"pending"},
+		Target:  []string{"ready"},
+		Refresh: func(ctx context.Context) (testObject, string, error) {
+			cancel()
+			return testObject{}, "pending", nil
+		},
+		Backoff: testBackoff,
+	}
+	_, err := conf.Wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package tfrouter
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// Toggle enables or disables an optional protocol feature, or leaves Router
+// to decide based on the handlers registered with it.
+type Toggle int
+
+const (
+	// Auto lets Router decide whether to enable the feature. It is the
+	// zero value.
+	Auto Toggle = iota
+
+	// Enabled always enables the feature.
+	Enabled
+
+	// Disabled always disables the feature.
+	Disabled
+)
+
+// Resolve returns whether the feature is enabled, using `auto` if the
+// Toggle is Auto.
+func (t Toggle) Resolve(auto bool) bool {
+	switch t {
+	case Enabled:
+		return true
+	case Disabled:
+		return false
+	default:
+		return auto
+	}
+}
+
+// Capabilities are the optional protocol features Router advertises to
+// Terraform in GetMetadata and GetProviderSchema. The zero value leaves each
+// of them to Router.
+type Capabilities struct {
+	// GetProviderSchemaOptional lets Terraform use a cached copy of the
+	// provider's schema rather than calling GetProviderSchema first.
+	// Router doesn't need GetProviderSchema to be called, so Auto enables
+	// it.
+	GetProviderSchemaOptional Toggle
+
+	// PlanDestroy makes Terraform call PlanResourceChange when resources
+	// are being destroyed, with a null ProposedNewState. Auto enables it
+	// if any Resource implements DestroyPlanner and returns true. Router
+	// plans the destroy itself for the Resources that don't.
+	PlanDestroy Toggle
+
+	// MoveResourceState tells Terraform that the provider supports moving
+	// state from other resource types. Auto enables it if any Resource
+	// implements StateMover and returns true. Router rejects moves when
+	// it is disabled, without calling the Resource.
+	MoveResourceState Toggle
+}
+
+// DestroyPlanner is implemented by Resources whose PlanResourceChange
+// handles destroys, like tfresource.Server.
+type DestroyPlanner interface {
+	PlansDestroy() bool
+}
+
+// StateMover is implemented by Resources that can say whether they accept
+// state moved from other resource types, like tfresource.Server.
+type StateMover interface {
+	MovesState() bool
+}
+
+// ServerCapabilities returns the capabilities Router advertises, resolving
+// any that are Auto.
+func (r *Router) ServerCapabilities() *tfprotov5.ServerCapabilities {
+	return &tfprotov5.ServerCapabilities{
+		GetProviderSchemaOptional: r.Capabilities.GetProviderSchemaOptional.Resolve(true),
+		PlanDestroy:               r.Capabilities.PlanDestroy.Resolve(r.anyResource(plansDestroy)),
+		MoveResourceState:         r.Capabilities.MoveResourceState.Resolve(r.anyResource(movesState)),
+	}
+}
+
+func plansDestroy(res Resource) bool {
+	p, ok := res.(DestroyPlanner)
+	return ok && p.PlansDestroy()
+}
+
+func movesState(res Resource) bool {
+	m, ok := res.(StateMover)
+	return ok && m.MovesState()
+}
+
+func (r *Router) anyResource(f func(Resource) bool) bool {
+	for _, res := range r.Resources {
+		if f(res) {
+			return true
+		}
+	}
+	return false
+}
+
+// planDestroy returns the plan for destroying a resource whose handler
+// doesn't implement DestroyPlanner, which is always to remove it.
+func planDestroy(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) *tfprotov5.PlanResourceChangeResponse {
+	return &tfprotov5.PlanResourceChangeResponse{
+		PlannedState:   req.ProposedNewState,
+		PlannedPrivate: req.PriorPrivate,
+	}
+}
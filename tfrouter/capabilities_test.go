@@ -0,0 +1,159 @@
+package tfrouter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type capableResource struct {
+	*testResource
+	plansDestroy bool
+	movesState   bool
+	plans        int
+	moves        int
+}
+
+func (r *capableResource) PlansDestroy() bool {
+	return r.plansDestroy
+}
+
+func (r *capableResource) MovesState() bool {
+	return r.movesState
+}
+
+func (r *capableResource) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	r.plans++
+	return &tfprotov5.PlanResourceChangeResponse{PlannedState: req.ProposedNewState}, nil
+}
+
+func (r *capableResource) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	r.moves++
+	return &tfprotov5.MoveResourceStateResponse{}, nil
+}
+
+func TestRouterServerCapabilities(t *testing.T) {
+	type testCase struct {
+		capabilities Capabilities
+		resource     *capableResource
+		expected     *tfprotov5.ServerCapabilities
+	}
+	cases := map[string]testCase{
+		"auto": {
+			resource: &capableResource{},
+			expected: &tfprotov5.ServerCapabilities{GetProviderSchemaOptional: true},
+		},
+		"auto-supported": {
+			resource: &capableResource{plansDestroy: true, movesState: true},
+			expected: &tfprotov5.ServerCapabilities{
+				GetProviderSchemaOptional: true,
+				PlanDestroy:               true,
+				MoveResourceState:         true,
+			},
+		},
+		"enabled": {
+			capabilities: Capabilities{PlanDestroy: Enabled, MoveResourceState: Enabled},
+			resource:     &capableResource{},
+			expected: &tfprotov5.ServerCapabilities{
+				GetProviderSchemaOptional: true,
+				PlanDestroy:               true,
+				MoveResourceState:         true,
+			},
+		},
+		"disabled": {
+			capabilities: Capabilities{
+				GetProviderSchemaOptional: Disabled,
+				PlanDestroy:               Disabled,
+				MoveResourceState:         Disabled,
+			},
+			resource: &capableResource{plansDestroy: true, movesState: true},
+			expected: &tfprotov5.ServerCapabilities{},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			r := &Router{
+				Resources:    map[string]Resource{"example_widget": tc.resource},
+				Capabilities: tc.capabilities,
+			}
+			if diff := cmp.Diff(tc.expected, r.ServerCapabilities()); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRouterPlanDestroy(t *testing.T) {
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "id", Type: tftypes.String, Computed: true},
+			},
+		},
+	}
+	typ := schema.ValueType()
+	null, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	widget := &capableResource{testResource: &testResource{schema: schema}}
+	gadget := &capableResource{testResource: &testResource{schema: schema}, plansDestroy: true}
+	r := &Router{
+		Resources: map[string]Resource{
+			"example_widget": widget,
+			"example_gadget": gadget,
+		},
+	}
+	for _, name := range []string{"example_widget", "example_gadget"} {
+		resp, err := r.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+			TypeName:         name,
+			ProposedNewState: &null,
+			PriorPrivate:     []byte("private"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) != 0 {
+			t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+		}
+		if resp.PlannedState != &null {
+			t.Errorf("expected the null proposed state to be planned, got %+v", resp.PlannedState)
+		}
+	}
+	if widget.plans != 0 || gadget.plans != 1 {
+		t.Errorf("expected only the gadget to plan its destroy, got %d widget and %d gadget plans", widget.plans, gadget.plans)
+	}
+}
+
+func TestRouterMoveResourceStateDisabled(t *testing.T) {
+	widget := &capableResource{testResource: &testResource{}}
+	r := &Router{
+		Resources: map[string]Resource{"example_widget": widget},
+	}
+	resp, err := r.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "example_gadget",
+		TargetTypeName: "example_widget",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Resource move not supported" {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+
+	r.Capabilities.MoveResourceState = Enabled
+	resp, err = r.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "example_gadget",
+		TargetTypeName: "example_widget",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 0 || widget.moves != 1 {
+		t.Errorf("expected the widget to move the state, got %d moves and diagnostics %+v", widget.moves, resp.Diagnostics)
+	}
+}
@@ -0,0 +1,443 @@
+// Package tfrouter provides a tfprotov5.ProviderServer that routes each RPC
+// to the handler registered for the resource, data source, ephemeral
+// resource, or list resource type or the function it concerns, and assembles
+// the provider's schema from the schemas of those handlers.
+//
+// Handlers are usually built with the tfresource, tfdatasource, tfephemeral,
+// and tflist packages, but any type implementing the Resource, DataSource,
+// EphemeralResource, or ListResource interface can be registered.
+package tfrouter
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tffunction"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfidentity"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+var _ tfprotov5.ProviderServerWithListResource = &Router{}
+
+// Resource is a handler for a single resource type.
+type Resource interface {
+	tfprotov5.ResourceServer
+
+	// Schema returns the resource type's schema.
+	Schema() *tfprotov5.Schema
+}
+
+// DataSource is a handler for a single data source type.
+type DataSource interface {
+	tfprotov5.DataSourceServer
+
+	// Schema returns the data source type's schema.
+	Schema() *tfprotov5.Schema
+}
+
+// EphemeralResource is a handler for a single ephemeral resource type.
+type EphemeralResource interface {
+	tfprotov5.EphemeralResourceServer
+
+	// Schema returns the ephemeral resource type's schema.
+	Schema() *tfprotov5.Schema
+}
+
+// ListResource is a handler for listing a single managed resource type.
+type ListResource interface {
+	tfprotov5.ListResourceServer
+
+	// Schema returns the schema of the list block's configuration.
+	Schema() *tfprotov5.Schema
+}
+
+// ProviderHandler handles the provider-level RPCs that configure the
+// provider itself.
+type ProviderHandler interface {
+	PrepareProviderConfig(context.Context, *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error)
+	ConfigureProvider(context.Context, *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error)
+}
+
+// Router is a tfprotov5.ProviderServer that dispatches RPCs to the Resource
+// or DataSource registered for the type named in the request. RPCs for
+// types that aren't registered return an error diagnostic.
+//
+// If a Resource, DataSource, or the ProviderHandler implements
+// tfvalidate.Validated, its Validators are run against the configuration
+// during the corresponding validation RPC, before the handler is called.
+// Their diagnostics are returned alongside the handler's.
+//
+// Router does nothing when StopProvider is called; wrap it with
+// graceful.NewServer to cancel in-flight requests.
+type Router struct {
+	// ProviderSchema is the schema of the provider's configuration block.
+	ProviderSchema *tfprotov5.Schema
+
+	// ProviderMetaSchema is the schema of the provider_meta block, if the
+	// provider supports one.
+	ProviderMetaSchema *tfprotov5.Schema
+
+	// Provider handles the provider configuration RPCs. If nil, the
+	// config is passed through unchanged and configuring the provider is
+	// a no-op.
+	Provider ProviderHandler
+
+	// Resources maps resource type names to their handlers.
+	Resources map[string]Resource
+
+	// DataSources maps data source type names to their handlers.
+	DataSources map[string]DataSource
+
+	// EphemeralResources maps ephemeral resource type names to their
+	// handlers.
+	EphemeralResources map[string]EphemeralResource
+
+	// ListResources maps managed resource type names to the handlers that
+	// list them.
+	ListResources map[string]ListResource
+
+	// Functions holds the handlers of the provider's functions, if it
+	// has any.
+	Functions *tffunction.Registry
+
+	// Capabilities toggles the optional protocol features advertised in
+	// GetMetadata and GetProviderSchema. See ServerCapabilities.
+	Capabilities Capabilities
+}
+
+func (r *Router) resource(typeName string) (Resource, *tfprotov5.Diagnostic) {
+	res, ok := r.Resources[typeName]
+	if !ok {
+		return nil, diag.Errorf("Unsupported resource type", "The provider does not support the resource type %q.", typeName)
+	}
+	return res, nil
+}
+
+func (r *Router) ephemeralResource(typeName string) (EphemeralResource, *tfprotov5.Diagnostic) {
+	er, ok := r.EphemeralResources[typeName]
+	if !ok {
+		return nil, diag.Errorf("Unsupported ephemeral resource type", "The provider does not support the ephemeral resource type %q.", typeName)
+	}
+	return er, nil
+}
+
+func (r *Router) listResource(typeName string) (ListResource, *tfprotov5.Diagnostic) {
+	lr, ok := r.ListResources[typeName]
+	if !ok {
+		return nil, diag.Errorf("Unsupported list resource type", "The provider does not support listing the resource type %q.", typeName)
+	}
+	return lr, nil
+}
+
+func (r *Router) dataSource(typeName string) (DataSource, *tfprotov5.Diagnostic) {
+	ds, ok := r.DataSources[typeName]
+	if !ok {
+		return nil, diag.Errorf("Unsupported data source type", "The provider does not support the data source type %q.", typeName)
+	}
+	return ds, nil
+}
+
+// validate runs the Validators of `handler` against `config`, if `handler`
+// implements tfvalidate.Validated.
+func validate(ctx context.Context, handler interface{}, schema *tfprotov5.Schema, config *tfprotov5.DynamicValue) []*tfprotov5.Diagnostic {
+	v, ok := handler.(tfvalidate.Validated)
+	if !ok || schema == nil || config == nil {
+		return nil
+	}
+	val, err := config.Unmarshal(schema.ValueType())
+	if err != nil {
+		return []*tfprotov5.Diagnostic{diag.Error("Error reading configuration", err)}
+	}
+	return v.Validators().Validate(ctx, val)
+}
+
+// GetMetadata lists the registered resource types, data source types, and
+// functions, in order, without building any of their schemas, so Terraform
+// can use schemas it has cached instead of calling GetProviderSchema.
+func (r *Router) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp := &tfprotov5.GetMetadataResponse{
+		ServerCapabilities: r.ServerCapabilities(),
+	}
+	for _, name := range sorted.Keys(r.Resources) {
+		resp.Resources = append(resp.Resources, tfprotov5.ResourceMetadata{TypeName: name})
+	}
+	for _, name := range sorted.Keys(r.DataSources) {
+		resp.DataSources = append(resp.DataSources, tfprotov5.DataSourceMetadata{TypeName: name})
+	}
+	for _, name := range sorted.Keys(r.EphemeralResources) {
+		resp.EphemeralResources = append(resp.EphemeralResources, tfprotov5.EphemeralResourceMetadata{TypeName: name})
+	}
+	for _, name := range sorted.Keys(r.ListResources) {
+		resp.ListResources = append(resp.ListResources, tfprotov5.ListResourceMetadata{TypeName: name})
+	}
+	for _, name := range r.Functions.Names() {
+		resp.Functions = append(resp.Functions, tfprotov5.FunctionMetadata{Name: name})
+	}
+	return resp, nil
+}
+
+func (r *Router) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp := &tfprotov5.GetProviderSchemaResponse{
+		Provider:                 r.ProviderSchema,
+		ProviderMeta:             r.ProviderMetaSchema,
+		ResourceSchemas:          make(map[string]*tfprotov5.Schema, len(r.Resources)),
+		DataSourceSchemas:        make(map[string]*tfprotov5.Schema, len(r.DataSources)),
+		EphemeralResourceSchemas: make(map[string]*tfprotov5.Schema, len(r.EphemeralResources)),
+		ListResourceSchemas:      make(map[string]*tfprotov5.Schema, len(r.ListResources)),
+		Functions:                r.Functions.Definitions(),
+		ServerCapabilities:       r.ServerCapabilities(),
+	}
+	if resp.Provider == nil {
+		resp.Provider = &tfprotov5.Schema{Block: &tfprotov5.SchemaBlock{}}
+	}
+	for name, res := range r.Resources {
+		resp.ResourceSchemas[name] = res.Schema()
+	}
+	for name, ds := range r.DataSources {
+		resp.DataSourceSchemas[name] = ds.Schema()
+	}
+	for name, er := range r.EphemeralResources {
+		resp.EphemeralResourceSchemas[name] = er.Schema()
+	}
+	for name, lr := range r.ListResources {
+		resp.ListResourceSchemas[name] = lr.Schema()
+	}
+	return resp, nil
+}
+
+// GetResourceIdentitySchemas returns the identity schemas of the resources
+// whose handlers implement tfidentity.Identified.
+func (r *Router) GetResourceIdentitySchemas(ctx context.Context, req *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	resp := &tfprotov5.GetResourceIdentitySchemasResponse{
+		IdentitySchemas: map[string]*tfprotov5.ResourceIdentitySchema{},
+	}
+	for name, res := range r.Resources {
+		i, ok := res.(tfidentity.Identified)
+		if !ok {
+			continue
+		}
+		if schema := i.IdentitySchema(); schema != nil {
+			resp.IdentitySchemas[name] = schema
+		}
+	}
+	return resp, nil
+}
+
+func (r *Router) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	if r.Provider == nil {
+		return &tfprotov5.PrepareProviderConfigResponse{PreparedConfig: req.Config}, nil
+	}
+	diags := validate(ctx, r.Provider, r.ProviderSchema, req.Config)
+	resp, err := r.Provider.PrepareProviderConfig(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	resp.Diagnostics = append(diags, resp.Diagnostics...)
+	return resp, nil
+}
+
+func (r *Router) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	if r.Provider == nil {
+		return &tfprotov5.ConfigureProviderResponse{}, nil
+	}
+	return r.Provider.ConfigureProvider(ctx, req)
+}
+
+func (r *Router) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	return &tfprotov5.StopProviderResponse{}, nil
+}
+
+func (r *Router) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	res, d := r.resource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ValidateResourceTypeConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	diags := validate(ctx, res, res.Schema(), req.Config)
+	resp, err := res.ValidateResourceTypeConfig(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	resp.Diagnostics = append(diags, resp.Diagnostics...)
+	return resp, nil
+}
+
+func (r *Router) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	res, d := r.resource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.UpgradeResourceStateResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return res.UpgradeResourceState(ctx, req)
+}
+
+func (r *Router) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	res, d := r.resource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ReadResourceResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return res.ReadResource(ctx, req)
+}
+
+// PlanResourceChange plans the change using the Resource, unless the
+// resource is being destroyed and the Resource doesn't implement
+// DestroyPlanner, in which case Router plans the destroy itself.
+func (r *Router) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	res, d := r.resource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.PlanResourceChangeResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	if !plansDestroy(res) && req.ProposedNewState != nil {
+		proposed, err := req.ProposedNewState.Unmarshal(res.Schema().ValueType())
+		if err != nil {
+			return &tfprotov5.PlanResourceChangeResponse{Diagnostics: []*tfprotov5.Diagnostic{diag.Error("Error reading proposed new state", err)}}, nil
+		}
+		if proposed.IsNull() {
+			return planDestroy(ctx, req), nil
+		}
+	}
+	return res.PlanResourceChange(ctx, req)
+}
+
+func (r *Router) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	res, d := r.resource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ApplyResourceChangeResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return res.ApplyResourceChange(ctx, req)
+}
+
+func (r *Router) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	res, d := r.resource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ImportResourceStateResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return res.ImportResourceState(ctx, req)
+}
+
+// MoveResourceState moves the state using the Resource, unless the
+// MoveResourceState capability is disabled.
+func (r *Router) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	res, d := r.resource(req.TargetTypeName)
+	if d != nil {
+		return &tfprotov5.MoveResourceStateResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	if !r.Capabilities.MoveResourceState.Resolve(r.anyResource(movesState)) {
+		return &tfprotov5.MoveResourceStateResponse{
+			Diagnostics: []*tfprotov5.Diagnostic{
+				diag.Errorf("Resource move not supported", "The provider does not support moving state between resource types."),
+			},
+		}, nil
+	}
+	return res.MoveResourceState(ctx, req)
+}
+
+func (r *Router) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	res, d := r.resource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.UpgradeResourceIdentityResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return res.UpgradeResourceIdentity(ctx, req)
+}
+
+func (r *Router) GenerateResourceConfig(ctx context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	res, d := r.resource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.GenerateResourceConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return res.GenerateResourceConfig(ctx, req)
+}
+
+func (r *Router) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	ds, d := r.dataSource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ValidateDataSourceConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	diags := validate(ctx, ds, ds.Schema(), req.Config)
+	resp, err := ds.ValidateDataSourceConfig(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	resp.Diagnostics = append(diags, resp.Diagnostics...)
+	return resp, nil
+}
+
+func (r *Router) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	ds, d := r.dataSource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ReadDataSourceResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return ds.ReadDataSource(ctx, req)
+}
+
+func (r *Router) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	return r.Functions.GetFunctions(ctx, req)
+}
+
+func (r *Router) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	return r.Functions.CallFunction(ctx, req)
+}
+
+func (r *Router) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	er, d := r.ephemeralResource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ValidateEphemeralResourceConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	diags := validate(ctx, er, er.Schema(), req.Config)
+	resp, err := er.ValidateEphemeralResourceConfig(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	resp.Diagnostics = append(diags, resp.Diagnostics...)
+	return resp, nil
+}
+
+func (r *Router) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	er, d := r.ephemeralResource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.OpenEphemeralResourceResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return er.OpenEphemeralResource(ctx, req)
+}
+
+func (r *Router) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	er, d := r.ephemeralResource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.RenewEphemeralResourceResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return er.RenewEphemeralResource(ctx, req)
+}
+
+func (r *Router) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	er, d := r.ephemeralResource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.CloseEphemeralResourceResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	return er.CloseEphemeralResource(ctx, req)
+}
+
+func (r *Router) ValidateListResourceConfig(ctx context.Context, req *tfprotov5.ValidateListResourceConfigRequest) (*tfprotov5.ValidateListResourceConfigResponse, error) {
+	lr, d := r.listResource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ValidateListResourceConfigResponse{Diagnostics: []*tfprotov5.Diagnostic{d}}, nil
+	}
+	diags := validate(ctx, lr, lr.Schema(), req.Config)
+	resp, err := lr.ValidateListResourceConfig(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	resp.Diagnostics = append(diags, resp.Diagnostics...)
+	return resp, nil
+}
+
+func (r *Router) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	lr, d := r.listResource(req.TypeName)
+	if d != nil {
+		return &tfprotov5.ListResourceServerStream{
+			Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+				yield(tfprotov5.ListResourceResult{Diagnostics: []*tfprotov5.Diagnostic{d}})
+			},
+		}, nil
+	}
+	return lr.ListResource(ctx, req)
+}
@@ -0,0 +1,390 @@
+package tfrouter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tffunction"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfvalidate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type testResource struct {
+	tfprotov5.ResourceServer
+	schema  *tfprotov5.Schema
+	reads   int
+	schemas int
+}
+
+func (r *testResource) Schema() *tfprotov5.Schema {
+	r.schemas++
+	return r.schema
+}
+
+func (r *testResource) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	r.reads++
+	return &tfprotov5.ReadResourceResponse{NewState: req.CurrentState}, nil
+}
+
+func TestRouterDispatch(t *testing.T) {
+	widget := &testResource{schema: &tfprotov5.Schema{Version: 1}}
+	gadget := &testResource{schema: &tfprotov5.Schema{Version: 2}}
+	r := &Router{
+		Resources: map[string]Resource{
+			"example_widget": widget,
+			"example_gadget": gadget,
+		},
+	}
+
+	resp, err := r.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{TypeName: "example_widget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 0 {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+	if widget.reads != 1 || gadget.reads != 0 {
+		t.Errorf("expected exactly one read of the widget, got %d widget and %d gadget reads", widget.reads, gadget.reads)
+	}
+
+	resp, err = r.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{TypeName: "example_gizmo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Unsupported resource type" {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+}
+
+func TestRouterSchema(t *testing.T) {
+	widget := &testResource{schema: &tfprotov5.Schema{Version: 1}}
+	r := &Router{
+		Resources: map[string]Resource{
+			"example_widget": widget,
+		},
+	}
+	schema, err := r.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.ResourceSchemas["example_widget"] != widget.schema {
+		t.Errorf("expected widget schema, got %+v", schema.ResourceSchemas)
+	}
+	if schema.Provider == nil {
+		t.Error("expected an empty provider schema, got nil")
+	}
+
+	meta, err := r.GetMetadata(context.Background(), &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]tfprotov5.ResourceMetadata{{TypeName: "example_widget"}}, meta.Resources); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestRouterMetadata(t *testing.T) {
+	widget := &testResource{schema: &tfprotov5.Schema{}}
+	gadget := &testResource{schema: &tfprotov5.Schema{}}
+	r := &Router{
+		Resources: map[string]Resource{
+			"example_widget": widget,
+			"example_gadget": gadget,
+		},
+	}
+	meta, err := r.GetMetadata(context.Background(), &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &tfprotov5.GetMetadataResponse{
+		ServerCapabilities: &tfprotov5.ServerCapabilities{GetProviderSchemaOptional: true},
+		Resources: []tfprotov5.ResourceMetadata{
+			{TypeName: "example_gadget"},
+			{TypeName: "example_widget"},
+		},
+	}
+	if diff := cmp.Diff(expected, meta); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if widget.schemas != 0 || gadget.schemas != 0 {
+		t.Errorf("expected no schemas to be built, got %d widget and %d gadget schemas", widget.schemas, gadget.schemas)
+	}
+
+	r.Capabilities.GetProviderSchemaOptional = Disabled
+	schema, err := r.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&tfprotov5.ServerCapabilities{}, schema.ServerCapabilities); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+type testEphemeralResource struct {
+	tfprotov5.EphemeralResourceServer
+	schema *tfprotov5.Schema
+	opens  int
+}
+
+func (r *testEphemeralResource) Schema() *tfprotov5.Schema {
+	return r.schema
+}
+
+func (r *testEphemeralResource) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	r.opens++
+	return &tfprotov5.OpenEphemeralResourceResponse{Result: req.Config}, nil
+}
+
+func TestRouterEphemeralResources(t *testing.T) {
+	token := &testEphemeralResource{schema: &tfprotov5.Schema{Version: 1}}
+	r := &Router{
+		EphemeralResources: map[string]EphemeralResource{
+			"example_token": token,
+		},
+	}
+	resp, err := r.OpenEphemeralResource(context.Background(), &tfprotov5.OpenEphemeralResourceRequest{TypeName: "example_token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 0 || token.opens != 1 {
+		t.Errorf("expected the token to be opened, got %d opens and diagnostics %+v", token.opens, resp.Diagnostics)
+	}
+
+	resp, err = r.OpenEphemeralResource(context.Background(), &tfprotov5.OpenEphemeralResourceRequest{TypeName: "example_secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Unsupported ephemeral resource type" {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics)
+	}
+
+	schema, err := r.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.EphemeralResourceSchemas["example_token"] != token.schema {
+		t.Errorf("expected token schema, got %+v", schema.EphemeralResourceSchemas)
+	}
+	meta, err := r.GetMetadata(context.Background(), &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]tfprotov5.EphemeralResourceMetadata{{TypeName: "example_token"}}, meta.EphemeralResources); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+type testListResource struct {
+	tfprotov5.ListResourceServer
+	schema *tfprotov5.Schema
+}
+
+func (r *testListResource) Schema() *tfprotov5.Schema {
+	return r.schema
+}
+
+func (r *testListResource) ListResource(ctx context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	return &tfprotov5.ListResourceServerStream{
+		Results: func(yield func(tfprotov5.ListResourceResult) bool) {
+			yield(tfprotov5.ListResourceResult{DisplayName: "widget 1"})
+		},
+	}, nil
+}
+
+func TestRouterListResources(t *testing.T) {
+	widgets := &testListResource{schema: &tfprotov5.Schema{Version: 1}}
+	r := &Router{
+		ListResources: map[string]ListResource{
+			"example_widget": widgets,
+		},
+	}
+	for typeName, expected := range map[string]tfprotov5.ListResourceResult{
+		"example_widget": {DisplayName: "widget 1"},
+		"example_gadget": {
+			Diagnostics: []*tfprotov5.Diagnostic{{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Unsupported list resource type",
+				Detail:   `The provider does not support listing the resource type "example_gadget".`,
+			}},
+		},
+	} {
+		stream, err := r.ListResource(context.Background(), &tfprotov5.ListResourceRequest{TypeName: typeName})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var results []tfprotov5.ListResourceResult
+		for result := range stream.Results {
+			results = append(results, result)
+		}
+		if diff := cmp.Diff([]tfprotov5.ListResourceResult{expected}, results); diff != "" {
+			t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+		}
+	}
+
+	schema, err := r.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.ListResourceSchemas["example_widget"] != widgets.schema {
+		t.Errorf("expected widget list schema, got %+v", schema.ListResourceSchemas)
+	}
+	meta, err := r.GetMetadata(context.Background(), &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]tfprotov5.ListResourceMetadata{{TypeName: "example_widget"}}, meta.ListResources); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+type identifiedResource struct {
+	*testResource
+	identity *tfprotov5.ResourceIdentitySchema
+}
+
+func (r *identifiedResource) IdentitySchema() *tfprotov5.ResourceIdentitySchema {
+	return r.identity
+}
+
+func TestRouterIdentitySchemas(t *testing.T) {
+	widget := &identifiedResource{
+		testResource: &testResource{schema: &tfprotov5.Schema{}},
+		identity: &tfprotov5.ResourceIdentitySchema{
+			IdentityAttributes: []*tfprotov5.ResourceIdentitySchemaAttribute{
+				{Name: "id", Type: tftypes.String, RequiredForImport: true},
+			},
+		},
+	}
+	r := &Router{
+		Resources: map[string]Resource{
+			"example_widget": widget,
+			"example_gadget": &identifiedResource{testResource: &testResource{schema: &tfprotov5.Schema{}}},
+			"example_gizmo":  &testResource{schema: &tfprotov5.Schema{}},
+		},
+	}
+	resp, err := r.GetResourceIdentitySchemas(context.Background(), &tfprotov5.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]*tfprotov5.ResourceIdentitySchema{
+		"example_widget": widget.identity,
+	}
+	if diff := cmp.Diff(expected, resp.IdentitySchemas); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+type testFunction struct {
+	definition *tfprotov5.Function
+}
+
+func (f *testFunction) Definition() *tfprotov5.Function {
+	return f.definition
+}
+
+func (f *testFunction) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	return &tfprotov5.CallFunctionResponse{Error: &tfprotov5.FunctionError{Text: "called " + req.Name}}, nil
+}
+
+func TestRouterFunctions(t *testing.T) {
+	parse := &testFunction{definition: &tfprotov5.Function{Summary: "parse"}}
+	r := &Router{Functions: &tffunction.Registry{}}
+	r.Functions.Add("parse", parse)
+
+	schema, err := r.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Functions["parse"] != parse.definition {
+		t.Errorf("expected parse definition, got %+v", schema.Functions)
+	}
+
+	meta, err := r.GetMetadata(context.Background(), &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]tfprotov5.FunctionMetadata{{Name: "parse"}}, meta.Functions); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	resp, err := r.CallFunction(context.Background(), &tfprotov5.CallFunctionRequest{Name: "parse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Text != "called parse" {
+		t.Errorf("unexpected error: %+v", resp.Error)
+	}
+
+	resp, err = (&Router{}).CallFunction(context.Background(), &tfprotov5.CallFunctionRequest{Name: "parse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Text != `The provider does not support the function "parse".` {
+		t.Errorf("unexpected error: %+v", resp.Error)
+	}
+}
+
+type validatedResource struct {
+	testResource
+	validators *tfvalidate.Registry
+}
+
+func (r *validatedResource) Validators() *tfvalidate.Registry {
+	return r.validators
+}
+
+func (r *validatedResource) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	return &tfprotov5.ValidateResourceTypeConfigResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{
+			{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "from handler"},
+		},
+	}, nil
+}
+
+func TestRouterValidators(t *testing.T) {
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "name", Type: tftypes.String, Required: true},
+			},
+		},
+	}
+	name := tftypes.NewAttributePath().WithAttributeName("name")
+	validators := &tfvalidate.Registry{}
+	validators.Add(name, tfvalidate.StringOneOf("foo"))
+	r := &Router{
+		Resources: map[string]Resource{
+			"example_widget": &validatedResource{
+				testResource: testResource{schema: schema},
+				validators:   validators,
+			},
+		},
+	}
+	config, err := tfprotov5.NewDynamicValue(schema.ValueType(), tftypes.NewValue(schema.ValueType(), map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "bar"),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := r.ValidateResourceTypeConfig(context.Background(), &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName: "example_widget",
+		Config:   &config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*tfprotov5.Diagnostic{
+		{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid attribute value",
+			Detail:    `Must be one of "foo", got "bar".`,
+			Attribute: name,
+		},
+		{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "from handler"},
+	}
+	if diff := cmp.Diff(expected, resp.Diagnostics); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,138 @@
+// Package tfserve provides a helper for the main function of providers,
+// wrapping tf5server.Serve with flag and environment variable parsing so
+// providers can be started in debug mode without any extra code.
+//
+// A provider's main function only needs to call Serve:
+//
+//	func main() {
+//		err := tfserve.Serve("registry.terraform.io/example/example", provider.New)
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// Running the resulting binary with the -debug flag, or with the
+// TF_PROVIDER_DEBUG environment variable set to a true value, starts the
+// provider in managed debug mode: rather than waiting for Terraform to launch
+// it, the provider starts listening immediately and prints the
+// TF_REATTACH_PROVIDERS value Terraform needs to connect to it. This makes it
+// possible to start the provider under a debugger like delve and then run
+// Terraform against it. The reattach configuration can also be written to a
+// file using the -reattach-file flag or TF_PROVIDER_REATTACH_FILE environment
+// variable, which is handy for editor integrations that launch the debugger.
+package tfserve
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+)
+
+const (
+	// DebugEnvVar is the environment variable that, when set to a value
+	// strconv.ParseBool considers true, starts the provider in debug mode.
+	DebugEnvVar = "TF_PROVIDER_DEBUG"
+
+	// ReattachFileEnvVar is the environment variable that sets the path of
+	// the file the reattach configuration will be written to in debug mode.
+	ReattachFileEnvVar = "TF_PROVIDER_REATTACH_FILE"
+)
+
+// Config controls how a provider is served.
+type Config struct {
+	// Debug starts the provider in managed debug mode, printing the
+	// reattach configuration to stdout instead of waiting for Terraform to
+	// start the provider.
+	Debug bool
+
+	// ReattachFile, if set, is the path of a file the reattach
+	// configuration will be written to in debug mode, in the format of an
+	// env file.
+	ReattachFile string
+
+	// ReattachTimeout is how long to wait for the server to start and
+	// report its reattach configuration in debug mode. If zero,
+	// tf5server's default is used.
+	ReattachTimeout time.Duration
+
+	// ServeOpts are passed to tf5server.Serve after the options derived
+	// from the rest of the Config.
+	ServeOpts []tf5server.ServeOpt
+}
+
+// ParseArgs builds a Config from command line arguments, usually
+// os.Args[1:], and the environment, as exposed by `getenv`. Flags take
+// precedence over environment variables. If `getenv` is nil, os.Getenv is
+// used.
+//
+// The following flags are supported:
+//
+//	-debug                  start the provider in debug mode
+//	-reattach-file <path>   write the reattach configuration to <path>
+//	-reattach-timeout <d>   how long to wait for the reattach configuration
+func ParseArgs(args []string, getenv func(string) string) (Config, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	var conf Config
+	if v := getenv(DebugEnvVar); v != "" {
+		debug, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid value %q for %s: %w", v, DebugEnvVar, err)
+		}
+		conf.Debug = debug
+	}
+	conf.ReattachFile = getenv(ReattachFileEnvVar)
+
+	flags := flag.NewFlagSet("provider", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&conf.Debug, "debug", conf.Debug, "start the provider in debug mode")
+	flags.StringVar(&conf.ReattachFile, "reattach-file", conf.ReattachFile, "write the reattach configuration to this file in debug mode")
+	flags.DurationVar(&conf.ReattachTimeout, "reattach-timeout", conf.ReattachTimeout, "how long to wait for the reattach configuration in debug mode")
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+	return conf, nil
+}
+
+// Options returns the tf5server.ServeOpts that implement the Config.
+func (c Config) Options() []tf5server.ServeOpt {
+	var opts []tf5server.ServeOpt
+	if c.Debug {
+		opts = append(opts, tf5server.WithManagedDebug())
+		if c.ReattachFile != "" {
+			opts = append(opts, tf5server.WithManagedDebugEnvFilePath(c.ReattachFile))
+		}
+		if c.ReattachTimeout > 0 {
+			opts = append(opts, tf5server.WithManagedDebugReattachConfigTimeout(c.ReattachTimeout))
+		}
+	}
+	return append(opts, c.ServeOpts...)
+}
+
+// Serve parses os.Args and the environment using ParseArgs and serves the
+// provider returned by `factory` using tf5server.Serve, in either the
+// normal, Terraform-managed mode or in debug mode. `name` should be the fully
+// qualified name of the provider, like
+// "registry.terraform.io/hashicorp/time". Any `opts` are passed along to
+// tf5server.Serve.
+func Serve(name string, factory func() tfprotov5.ProviderServer, opts ...tf5server.ServeOpt) error {
+	conf, err := ParseArgs(os.Args[1:], nil)
+	if err != nil {
+		return err
+	}
+	conf.ServeOpts = append(conf.ServeOpts, opts...)
+	return ServeWithConfig(name, factory, conf)
+}
+
+// ServeWithConfig serves the provider returned by `factory` as described by
+// `conf`, without consulting os.Args or the environment.
+func ServeWithConfig(name string, factory func() tfprotov5.ProviderServer, conf Config) error {
+	return tf5server.Serve(name, factory, conf.Options()...)
+}
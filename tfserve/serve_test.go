@@ -0,0 +1,74 @@
+package tfserve
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseArgs(t *testing.T) {
+	type testCase struct {
+		args        []string
+		env         map[string]string
+		expected    Config
+		expectedErr bool
+		numOpts     int
+	}
+	cases := map[string]testCase{
+		"none": {
+			expected: Config{},
+		},
+		"flag": {
+			args:     []string{"-debug", "-reattach-file", "/tmp/reattach.env", "-reattach-timeout", "5s"},
+			expected: Config{Debug: true, ReattachFile: "/tmp/reattach.env", ReattachTimeout: 5 * time.Second},
+			numOpts:  3,
+		},
+		"env": {
+			env:      map[string]string{DebugEnvVar: "1", ReattachFileEnvVar: "/tmp/from-env"},
+			expected: Config{Debug: true, ReattachFile: "/tmp/from-env"},
+			numOpts:  2,
+		},
+		"flag-overrides-env": {
+			args:     []string{"-debug=false"},
+			env:      map[string]string{DebugEnvVar: "true"},
+			expected: Config{},
+		},
+		"reattach-file-without-debug": {
+			args:     []string{"-reattach-file", "/tmp/reattach.env"},
+			expected: Config{ReattachFile: "/tmp/reattach.env"},
+		},
+		"invalid-env": {
+			env:         map[string]string{DebugEnvVar: "sometimes"},
+			expectedErr: true,
+		},
+		"unknown-flag": {
+			args:        []string{"-verbose"},
+			expectedErr: true,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			conf, err := ParseArgs(test.args, func(k string) string {
+				return test.env[k]
+			})
+			if err != nil {
+				if !test.expectedErr {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if test.expectedErr {
+				t.Fatal("expected error, got none")
+			}
+			if diff := cmp.Diff(test.expected, conf, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+			if got := len(conf.Options()); got != test.numOpts {
+				t.Errorf("expected %d serve options, got %d", test.numOpts, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Package tfsmithy lets Terraform values be used as Smithy documents, the
+// free-form values aws-sdk-go-v2 APIs accept and return as
+// document.Interface, without marshaling them to JSON and back first.
+//
+// smithy-go's document.Marshaler and document.Unmarshaler are satisfied
+// structurally, so this package doesn't depend on smithy-go or the SDK.
+package tfsmithy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Marshaler is smithy-go's document.Marshaler.
+type Marshaler interface {
+	MarshalSmithyDocument() ([]byte, error)
+}
+
+// Unmarshaler is smithy-go's document.Unmarshaler, implemented by the
+// documents aws-sdk-go-v2 returns.
+type Unmarshaler interface {
+	UnmarshalSmithyDocument(v interface{}) error
+}
+
+// Document is a Smithy document backed by a GoPrimitive. It can be passed
+// to aws-sdk-go-v2 wherever a document is expected, and decoded from a
+// tftypes.Value with tftypes.Value.As, like a GoPrimitive.
+type Document struct {
+	asgotypes.GoPrimitive
+}
+
+var (
+	_ Marshaler   = (*Document)(nil)
+	_ Unmarshaler = (*Document)(nil)
+)
+
+// NewDocument returns a Document holding `val`, which must be wholly
+// known. Lists, sets, and tuples are held as []interface{}, and objects and
+// maps as map[string]interface{}.
+func NewDocument(val tftypes.Value) (*Document, error) {
+	d := &Document{GoPrimitive: asgotypes.GoPrimitive{Collections: asgotypes.CollectionsLenient}}
+	if err := d.FromTerraform5Value(val); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// MarshalSmithyDocument returns the JSON encoding of the document, with
+// numbers written at their full precision.
+func (d *Document) MarshalSmithyDocument() ([]byte, error) {
+	v, err := jsonValue(tftypes.NewAttributePath(), reflect.ValueOf(d.Value))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalSmithyDocument decodes the document into `v`, which must be a
+// non-nil pointer. Pointers to an interface{}, GoPrimitive, or Document are
+// set to the document's value directly; anything else is decoded from the
+// document's JSON encoding with encoding/json, like other documents are.
+func (d *Document) UnmarshalSmithyDocument(v interface{}) error {
+	switch target := v.(type) {
+	case *interface{}:
+		*target = d.Value
+		return nil
+	case *asgotypes.GoPrimitive:
+		*target = d.GoPrimitive
+		return nil
+	case *Document:
+		*target = *d
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal a document into %T, a non-nil pointer is required", v)
+	}
+	b, err := d.MarshalSmithyDocument()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// FromDocument converts `doc`, a document returned by aws-sdk-go-v2, into
+// a tftypes.Value of type `typ`, using asgotypes.Encode. Numbers are
+// converted at their full precision. `typ` must not be or contain
+// tftypes.DynamicPseudoType in place of a list, map, or object, as a
+// document doesn't say which of those it holds.
+func FromDocument(typ tftypes.Type, doc Unmarshaler) (tftypes.Value, error) {
+	if doc == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	var v interface{}
+	if err := doc.UnmarshalSmithyDocument(&v); err != nil {
+		return tftypes.Value{}, err
+	}
+	v, err := normalize(tftypes.NewAttributePath(), v)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return asgotypes.Encode(typ, v)
+}
+
+// bigFloater is implemented by smithy-go's document.Number, which the
+// documents aws-sdk-go-v2 returns hold numbers as.
+type bigFloater interface {
+	BigFloat() (*big.Float, error)
+}
+
+// normalize replaces the document.Numbers and json.Numbers in `v` with
+// *big.Floats, so asgotypes.Encode can encode them as numbers rather than
+// strings.
+func normalize(path *tftypes.AttributePath, v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case bigFloater:
+		f, err := x.BigFloat()
+		if err != nil {
+			return nil, path.NewError(err)
+		}
+		return f, nil
+	case json.Number:
+		f, _, err := big.ParseFloat(string(x), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, path.NewError(err)
+		}
+		return f, nil
+	case []interface{}:
+		res := make([]interface{}, len(x))
+		for i, elem := range x {
+			n, err := normalize(path.WithElementKeyInt(i), elem)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = n
+		}
+		return res, nil
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(x))
+		for k, elem := range x {
+			n, err := normalize(path.WithElementKeyString(k), elem)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = n
+		}
+		return res, nil
+	}
+	return v, nil
+}
+
+// jsonValue returns `v`, a GoPrimitive's Value, with its *big.Floats
+// replaced by json.Numbers, so encoding/json writes them as numbers rather
+// than strings, and without losing precision.
+func jsonValue(path *tftypes.AttributePath, v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch x := v.Interface().(type) {
+	case *big.Float:
+		if x == nil {
+			return nil, nil
+		}
+		if x.IsInf() {
+			return nil, path.NewErrorf("cannot marshal %s to a document", x.Text('g', -1))
+		}
+		return json.Number(x.Text('g', -1)), nil
+	case string, bool:
+		return x, nil
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return jsonValue(path, v.Elem())
+	case reflect.Slice, reflect.Array:
+		res := make([]interface{}, v.Len())
+		for i := range res {
+			elem, err := jsonValue(path.WithElementKeyInt(i), v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			res[i] = elem
+		}
+		return res, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		res := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			elem, err := jsonValue(path.WithElementKeyString(k.String()), v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+			res[k.String()] = elem
+		}
+		return res, nil
+	}
+	return nil, path.NewErrorf("cannot marshal %s to a document", v.Type())
+}
@@ -0,0 +1,152 @@
+package tfsmithy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var ruleType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"name":     tftypes.String,
+	"priority": tftypes.Number,
+	"enabled":  tftypes.Bool,
+	"tags":     tftypes.List{ElementType: tftypes.String},
+}}
+
+func rule(priority *big.Float) tftypes.Value {
+	return tftypes.NewValue(ruleType, map[string]tftypes.Value{
+		"name":     tftypes.NewValue(tftypes.String, "allow"),
+		"priority": tftypes.NewValue(tftypes.Number, priority),
+		"enabled":  tftypes.NewValue(tftypes.Bool, true),
+		"tags": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+		}),
+	})
+}
+
+// number behaves like smithy-go's document.Number.
+type number string
+
+func (n number) BigFloat() (*big.Float, error) {
+	f, _, err := big.ParseFloat(string(n), 10, 512, big.ToNearestEven)
+	return f, err
+}
+
+// sdkDocument behaves like the documents aws-sdk-go-v2 returns, which
+// unmarshal into an interface{} with numbers as document.Numbers.
+type sdkDocument struct {
+	value interface{}
+}
+
+func (d sdkDocument) UnmarshalSmithyDocument(v interface{}) error {
+	*v.(*interface{}) = d.value
+	return nil
+}
+
+func TestMarshalSmithyDocument(t *testing.T) {
+	priority, _, _ := big.ParseFloat("12345678901234567890.5", 10, 512, big.ToNearestEven)
+	doc, err := NewDocument(rule(priority))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := doc.MarshalSmithyDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := `{"enabled":true,"name":"allow","priority":1.23456789012345678905e+19,"tags":["a"]}`
+	if diff := cmp.Diff(expected, string(got)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestUnmarshalSmithyDocument(t *testing.T) {
+	doc, err := NewDocument(rule(big.NewFloat(10)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var v interface{}
+	if err := doc.UnmarshalSmithyDocument(&v); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := v.(map[string]interface{})["priority"].(*big.Float); !ok {
+		t.Errorf("expected priority to be a *big.Float, got %T", v.(map[string]interface{})["priority"])
+	}
+
+	var s struct {
+		Name     string   `json:"name"`
+		Priority int      `json:"priority"`
+		Tags     []string `json:"tags"`
+	}
+	if err := doc.UnmarshalSmithyDocument(&s); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s.Name != "allow" || s.Priority != 10 || len(s.Tags) != 1 {
+		t.Errorf("unexpected result %+v", s)
+	}
+
+	expectedErr := "cannot unmarshal a document into struct {}, a non-nil pointer is required"
+	if err := doc.UnmarshalSmithyDocument(struct{}{}); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestFromDocument(t *testing.T) {
+	type testCase struct {
+		typ         tftypes.Type
+		doc         Unmarshaler
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"sdk": {
+			typ: ruleType,
+			doc: sdkDocument{value: map[string]interface{}{
+				"name":     "allow",
+				"priority": number("10"),
+				"enabled":  true,
+				"tags":     []interface{}{"a"},
+			}},
+			expected: rule(big.NewFloat(10)),
+		},
+		"round-trip": {
+			typ: ruleType,
+			doc: func() Unmarshaler {
+				doc, _ := NewDocument(rule(big.NewFloat(2.5)))
+				return doc
+			}(),
+			expected: rule(big.NewFloat(2.5)),
+		},
+		"nil": {
+			typ:      ruleType,
+			expected: tftypes.NewValue(ruleType, nil),
+		},
+		"bad-number": {
+			typ: ruleType,
+			doc: sdkDocument{value: map[string]interface{}{
+				"priority": number("ten"),
+			}},
+			expectedErr: `ElementKeyString("priority"): number has no digits`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := FromDocument(tc.typ, tc.doc)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
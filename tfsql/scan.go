@@ -0,0 +1,211 @@
+// Package tfsql scans the results of database/sql queries into tftypes
+// values, for data sources backed by SQL queries.
+//
+// Each row becomes an object in a list, with an attribute for each column,
+// named after it. NULLs become null values. The object type can be
+// supplied, in which case the values drivers return are converted to it, or
+// inferred from the column types the driver reports.
+package tfsql
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	tfString tftypes.Type = tftypes.String
+	tfNumber tftypes.Type = tftypes.Number
+	tfBool   tftypes.Type = tftypes.Bool
+)
+
+var (
+	nullInt64Type   = reflect.TypeOf(sql.NullInt64{})
+	nullInt32Type   = reflect.TypeOf(sql.NullInt32{})
+	nullInt16Type   = reflect.TypeOf(sql.NullInt16{})
+	nullByteType    = reflect.TypeOf(sql.NullByte{})
+	nullFloat64Type = reflect.TypeOf(sql.NullFloat64{})
+	nullBoolType    = reflect.TypeOf(sql.NullBool{})
+)
+
+// ObjectType returns the object type the rows of `rows` are scanned as by
+// ScanInferred, with an attribute for each column. Columns the driver
+// reports as integers or floats are numbers, booleans are bools, and
+// anything else, including times and columns of unknown type, are strings.
+func ObjectType(rows *sql.Rows) (tftypes.Object, error) {
+	cols, err := rows.ColumnTypes()
+	if err != nil {
+		return tftypes.Object{}, err
+	}
+	attrs := make(map[string]tftypes.Type, len(cols))
+	for _, col := range cols {
+		if _, ok := attrs[col.Name()]; ok {
+			return tftypes.Object{}, fmt.Errorf("column %q is returned more than once", col.Name())
+		}
+		attrs[col.Name()] = inferType(col.ScanType())
+	}
+	return tftypes.Object{AttributeTypes: attrs}, nil
+}
+
+func inferType(typ reflect.Type) tftypes.Type {
+	if typ == nil {
+		return tftypes.String
+	}
+	switch typ {
+	case nullInt64Type, nullInt32Type, nullInt16Type, nullByteType, nullFloat64Type:
+		return tftypes.Number
+	case nullBoolType:
+		return tftypes.Bool
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return tftypes.Number
+	case reflect.Bool:
+		return tftypes.Bool
+	}
+	return tftypes.String
+}
+
+// ScanInferred scans `rows` like Scan, into objects of the type ObjectType
+// infers.
+func ScanInferred(rows *sql.Rows) (tftypes.Value, error) {
+	typ, err := ObjectType(rows)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return Scan(rows, typ)
+}
+
+// Scan reads the remaining rows of `rows` and returns them as a list of
+// objects of type `typ`. Every column must have an attribute of the same
+// name in `typ`, and attributes without a column are null. `rows` is
+// closed once it's been read.
+//
+// Values are converted to the attribute's type:
+//
+//   - strings from strings, byte slices, numbers, bools, and times, which
+//     are formatted as RFC 3339;
+//   - numbers from integers, floats, and strings or byte slices holding a
+//     decimal number, as drivers often return DECIMAL and NUMERIC columns;
+//   - bools from bools, the integers 0 and 1, and strings or byte slices
+//     strconv.ParseBool accepts.
+//
+// Errors converting a value will be tftypes.AttributePathErrors,
+// identifying the row and column it's from.
+func Scan(rows *sql.Rows, typ tftypes.Object) (tftypes.Value, error) {
+	defer rows.Close()
+	listType := tftypes.List{ElementType: typ}
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	seen := make(map[string]bool, len(cols))
+	for _, col := range cols {
+		if _, ok := typ.AttributeTypes[col]; !ok {
+			return tftypes.Value{}, fmt.Errorf("column %q has no attribute in %s", col, typ)
+		}
+		if seen[col] {
+			return tftypes.Value{}, fmt.Errorf("column %q is returned more than once", col)
+		}
+		seen[col] = true
+	}
+
+	dest := make([]interface{}, len(cols))
+	for i := range dest {
+		dest[i] = new(interface{})
+	}
+	elems := []tftypes.Value{}
+	for row := 0; rows.Next(); row++ {
+		if err := rows.Scan(dest...); err != nil {
+			return tftypes.Value{}, err
+		}
+		rowPath := tftypes.NewAttributePath().WithElementKeyInt(row)
+		attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+		for i, col := range cols {
+			val, err := convert(typ.AttributeTypes[col], *dest[i].(*interface{}))
+			if err != nil {
+				return tftypes.Value{}, rowPath.WithAttributeName(col).NewError(err)
+			}
+			attrs[col] = val
+		}
+		for name, attrType := range typ.AttributeTypes {
+			if !seen[name] {
+				attrs[name] = tftypes.NewValue(attrType, nil)
+			}
+		}
+		elems = append(elems, tftypes.NewValue(typ, attrs))
+	}
+	if err := rows.Err(); err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.NewValue(listType, elems), nil
+}
+
+// convert converts `v`, a value returned by a driver, into a value of
+// `typ`.
+func convert(typ tftypes.Type, v interface{}) (tftypes.Value, error) {
+	if v == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	switch {
+	case typ.Is(tfString):
+		switch x := v.(type) {
+		case string:
+			return tftypes.NewValue(typ, x), nil
+		case time.Time:
+			return tftypes.NewValue(typ, x.Format(time.RFC3339Nano)), nil
+		case bool:
+			return tftypes.NewValue(typ, strconv.FormatBool(x)), nil
+		case int64:
+			return tftypes.NewValue(typ, strconv.FormatInt(x, 10)), nil
+		case float64:
+			return tftypes.NewValue(typ, strconv.FormatFloat(x, 'g', -1, 64)), nil
+		}
+	case typ.Is(tfNumber):
+		switch x := v.(type) {
+		case int64:
+			return tftypes.NewValue(typ, new(big.Float).SetInt64(x)), nil
+		case float64:
+			return tftypes.NewValue(typ, big.NewFloat(x)), nil
+		case string:
+			f, _, err := big.ParseFloat(x, 10, 512, big.ToNearestEven)
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf("cannot convert %q to a number", x)
+			}
+			return tftypes.NewValue(typ, f), nil
+		}
+	case typ.Is(tfBool):
+		switch x := v.(type) {
+		case bool:
+			return tftypes.NewValue(typ, x), nil
+		case int64:
+			if x == 0 || x == 1 {
+				return tftypes.NewValue(typ, x == 1), nil
+			}
+			return tftypes.Value{}, fmt.Errorf("cannot convert %d to a bool", x)
+		case string:
+			b, err := strconv.ParseBool(x)
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf("cannot convert %q to a bool", x)
+			}
+			return tftypes.NewValue(typ, b), nil
+		}
+	default:
+		return tftypes.Value{}, fmt.Errorf("cannot scan a column into %s, only strings, numbers, and bools are supported", typ)
+	}
+	return tftypes.Value{}, fmt.Errorf("cannot convert %T to %s", v, typ)
+}
@@ -0,0 +1,213 @@
+package tfsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeDriver returns canned results for queries, keyed by the query
+// string.
+type fakeDriver map[string]*fakeRows
+
+func (d fakeDriver) Open(string) (driver.Conn, error) { return fakeConn(d), nil }
+
+type fakeConn fakeDriver
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{rows: c[query]}, nil
+}
+func (fakeConn) Close() error              { return nil }
+func (fakeConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type fakeStmt struct {
+	rows *fakeRows
+}
+
+func (fakeStmt) Close() error                                { return nil }
+func (fakeStmt) NumInput() int                               { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error)  { return nil, driver.ErrSkip }
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) { r := *s.rows; return &r, nil }
+
+type fakeRows struct {
+	columns   []string
+	scanTypes []reflect.Type
+	rows      [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+func (r *fakeRows) ColumnTypeScanType(i int) reflect.Type { return r.scanTypes[i] }
+
+var created = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func init() {
+	sql.Register("tfsql-fake", fakeDriver{
+		"servers": {
+			columns: []string{"name", "port", "enabled", "created", "weight"},
+			scanTypes: []reflect.Type{
+				reflect.TypeOf(""),
+				reflect.TypeOf(sql.NullInt64{}),
+				reflect.TypeOf(false),
+				reflect.TypeOf(time.Time{}),
+				reflect.TypeOf([]byte(nil)),
+			},
+			rows: [][]driver.Value{
+				{"web", int64(8080), true, created, []byte("1.50")},
+				{"db", nil, int64(0), nil, nil},
+			},
+		},
+		"duplicates": {
+			columns:   []string{"name", "name"},
+			scanTypes: []reflect.Type{reflect.TypeOf(""), reflect.TypeOf("")},
+		},
+		"bad-bool": {
+			columns:   []string{"enabled"},
+			scanTypes: []reflect.Type{reflect.TypeOf(false)},
+			rows:      [][]driver.Value{{int64(2)}},
+		},
+	})
+}
+
+func query(t *testing.T, q string) *sql.Rows {
+	t.Helper()
+	db, err := sql.Open("tfsql-fake", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	rows, err := db.Query(q)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return rows
+}
+
+func TestScanInferred(t *testing.T) {
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":    tftypes.String,
+		"port":    tftypes.Number,
+		"enabled": tftypes.Bool,
+		"created": tftypes.String,
+		"weight":  tftypes.String,
+	}}
+	expected := tftypes.NewValue(tftypes.List{ElementType: typ}, []tftypes.Value{
+		tftypes.NewValue(typ, map[string]tftypes.Value{
+			"name":    tftypes.NewValue(tftypes.String, "web"),
+			"port":    tftypes.NewValue(tftypes.Number, big.NewFloat(8080)),
+			"enabled": tftypes.NewValue(tftypes.Bool, true),
+			"created": tftypes.NewValue(tftypes.String, "2024-01-02T03:04:05Z"),
+			"weight":  tftypes.NewValue(tftypes.String, "1.50"),
+		}),
+		tftypes.NewValue(typ, map[string]tftypes.Value{
+			"name":    tftypes.NewValue(tftypes.String, "db"),
+			"port":    tftypes.NewValue(tftypes.Number, nil),
+			"enabled": tftypes.NewValue(tftypes.Bool, false),
+			"created": tftypes.NewValue(tftypes.String, nil),
+			"weight":  tftypes.NewValue(tftypes.String, nil),
+		}),
+	})
+	got, err := ScanInferred(query(t, "servers"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestScan(t *testing.T) {
+	type testCase struct {
+		query       string
+		typ         tftypes.Object
+		expected    tftypes.Value
+		expectedErr string
+	}
+	serverType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":    tftypes.String,
+		"port":    tftypes.String,
+		"enabled": tftypes.Bool,
+		"created": tftypes.String,
+		"weight":  tftypes.Number,
+		"region":  tftypes.String,
+	}}
+	weight, _, _ := big.ParseFloat("1.50", 10, 512, big.ToNearestEven)
+	cases := map[string]testCase{
+		"supplied-type": {
+			query: "servers",
+			typ:   serverType,
+			expected: tftypes.NewValue(tftypes.List{ElementType: serverType}, []tftypes.Value{
+				tftypes.NewValue(serverType, map[string]tftypes.Value{
+					"name":    tftypes.NewValue(tftypes.String, "web"),
+					"port":    tftypes.NewValue(tftypes.String, "8080"),
+					"enabled": tftypes.NewValue(tftypes.Bool, true),
+					"created": tftypes.NewValue(tftypes.String, "2024-01-02T03:04:05Z"),
+					"weight":  tftypes.NewValue(tftypes.Number, weight),
+					"region":  tftypes.NewValue(tftypes.String, nil),
+				}),
+				tftypes.NewValue(serverType, map[string]tftypes.Value{
+					"name":    tftypes.NewValue(tftypes.String, "db"),
+					"port":    tftypes.NewValue(tftypes.String, nil),
+					"enabled": tftypes.NewValue(tftypes.Bool, false),
+					"created": tftypes.NewValue(tftypes.String, nil),
+					"weight":  tftypes.NewValue(tftypes.Number, nil),
+					"region":  tftypes.NewValue(tftypes.String, nil),
+				}),
+			}),
+		},
+		"missing-attribute": {
+			query: "servers",
+			typ: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"name": tftypes.String,
+			}},
+			expectedErr: `column "port" has no attribute in tftypes.Object["name":tftypes.String]`,
+		},
+		"duplicate-columns": {
+			query: "duplicates",
+			typ: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"name": tftypes.String,
+			}},
+			expectedErr: `column "name" is returned more than once`,
+		},
+		"bad-value": {
+			query: "bad-bool",
+			typ: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"enabled": tftypes.Bool,
+			}},
+			expectedErr: `ElementKeyInt(0).AttributeName("enabled"): cannot convert 2 to a bool`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := Scan(query(t, tc.query), tc.typ)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Package tfstate provides helpers for building the new state returned from
+// ApplyResourceChange.
+//
+// Terraform requires the new state returned after apply to agree with the
+// planned state: every value that was known in the plan must be returned
+// unchanged, and every value that was unknown must be replaced with a known
+// one. Providers usually build the new state from whatever their API
+// returned, which makes it easy to break either rule by accident. Build
+// takes care of combining the two.
+//
+// It also reads the state Terraform records, both as the raw state sent to
+// UpgradeResourceState and MoveResourceState and from terraform.tfstate
+// files, for tooling and tests.
+package tfstate
+
+import (
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Builder constructs a resource's new state from its planned state and the
+// response of the API that applied it.
+//
+// Values that were known in the plan are always taken from the plan, and
+// values that were unknown are taken from the response. Null values in the
+// response are treated as "not reported" and never conflict with the plan.
+type Builder struct {
+	// Strict causes Build to return an error when the response contains a
+	// non-null value that differs from a value known in the plan, rather
+	// than silently keeping the planned value. Terraform would report the
+	// resulting state as inconsistent with the plan, so this is most
+	// useful for catching mistakes during development and testing.
+	Strict bool
+}
+
+// Build builds the new state for a resource described by `schema` using a
+// Builder with the default settings.
+func Build(schema *tfprotov5.Schema, planned tftypes.Value, response interface{}) (tftypes.Value, error) {
+	var b Builder
+	return b.Build(schema, planned, response)
+}
+
+// Build builds the new state for a resource described by `schema`.
+// `response` is encoded using asgotypes.Encode, so it may be a struct using
+// `tfsdk` tags or a tftypes.Value.
+//
+// If `planned` is null the resource is being destroyed, and the null value is
+// returned unchanged. It is an error for the new state to contain unknown
+// values. Write-only attributes are always null in the new state, even if
+// `response` sets them.
+func (b *Builder) Build(schema *tfprotov5.Schema, planned tftypes.Value, response interface{}) (tftypes.Value, error) {
+	if schema == nil || schema.Block == nil {
+		return tftypes.Value{}, errors.New("cannot build state without a schema")
+	}
+	if planned.IsNull() {
+		return planned, nil
+	}
+	resp, err := asgotypes.Encode(schema.ValueType(), response)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	state, err := b.merge(tftypes.NewAttributePath(), planned, resp)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return NullWriteOnly(schema, state)
+}
+
+func (b *Builder) merge(path *tftypes.AttributePath, planned, resp tftypes.Value) (tftypes.Value, error) {
+	switch {
+	case !planned.IsKnown():
+		if !resp.IsFullyKnown() {
+			return tftypes.Value{}, path.NewErrorf("value is still unknown after apply")
+		}
+		return resp, nil
+	case planned.IsFullyKnown() || planned.IsNull():
+		if b.Strict && !resp.IsNull() && !resp.Equal(planned) {
+			return tftypes.Value{}, path.NewErrorf("planned value %s does not match value %s returned after apply", planned, resp)
+		}
+		return planned, nil
+	}
+
+	// the planned value is known, but contains unknown values, so we need
+	// to recurse into it
+	typ := planned.Type()
+	switch {
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		var plannedAttrs, respAttrs map[string]tftypes.Value
+		if err := planned.As(&plannedAttrs); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		if !resp.IsNull() {
+			if err := resp.As(&respAttrs); err != nil {
+				return tftypes.Value{}, path.NewError(err)
+			}
+		}
+		isObject := typ.Is(tftypes.Object{})
+		attrs := make(map[string]tftypes.Value, len(plannedAttrs))
+		for k, p := range plannedAttrs {
+			attrPath := path.WithElementKeyString(k)
+			if isObject {
+				attrPath = path.WithAttributeName(k)
+			}
+			r, ok := respAttrs[k]
+			if !ok {
+				r = tftypes.NewValue(p.Type(), nil)
+			}
+			v, err := b.merge(attrPath, p, r)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[k] = v
+		}
+		if b.Strict && !isObject {
+			for k, r := range respAttrs {
+				if _, ok := plannedAttrs[k]; !ok && !r.IsNull() {
+					return tftypes.Value{}, path.NewErrorf("element %q was returned after apply, but was not planned", k)
+				}
+			}
+		}
+		return tftypes.NewValue(typ, attrs), nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Tuple{}):
+		var plannedElems, respElems []tftypes.Value
+		if err := planned.As(&plannedElems); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		if !resp.IsNull() {
+			if err := resp.As(&respElems); err != nil {
+				return tftypes.Value{}, path.NewError(err)
+			}
+			if len(respElems) != len(plannedElems) {
+				return tftypes.Value{}, path.NewErrorf("planned %d elements, but %d were returned after apply", len(plannedElems), len(respElems))
+			}
+		}
+		elems := make([]tftypes.Value, 0, len(plannedElems))
+		for i, p := range plannedElems {
+			r := tftypes.NewValue(p.Type(), nil)
+			if respElems != nil {
+				r = respElems[i]
+			}
+			v, err := b.merge(path.WithElementKeyInt(i), p, r)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, v)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case typ.Is(tftypes.Set{}):
+		// set elements containing unknown values can't be matched with
+		// the elements returned after apply, so the whole set is taken
+		// from the response
+		if !resp.IsFullyKnown() || resp.IsNull() {
+			return tftypes.Value{}, path.NewErrorf("set contains unknown values, but no value was returned after apply")
+		}
+		if b.Strict {
+			var plannedElems, respElems []tftypes.Value
+			if err := planned.As(&plannedElems); err != nil {
+				return tftypes.Value{}, path.NewError(err)
+			}
+			if err := resp.As(&respElems); err != nil {
+				return tftypes.Value{}, path.NewError(err)
+			}
+			if len(respElems) != len(plannedElems) {
+				return tftypes.Value{}, path.NewErrorf("planned %d elements, but %d were returned after apply", len(plannedElems), len(respElems))
+			}
+		}
+		return resp, nil
+	}
+	return tftypes.Value{}, path.NewErrorf("unexpected partially known value of type %s", typ)
+}
@@ -0,0 +1,125 @@
+package tfstate
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Computed: true},
+			{Name: "name", Type: tftypes.String, Required: true},
+			{Name: "tags", Type: tftypes.Map{ElementType: tftypes.String}, Optional: true, Computed: true},
+		},
+	},
+}
+
+var testType = testSchema.ValueType()
+
+type testResponse struct {
+	ID   string            `tfsdk:"id"`
+	Name string            `tfsdk:"name"`
+	Tags map[string]string `tfsdk:"tags"`
+}
+
+func testValue(id, name interface{}, tags map[string]interface{}) tftypes.Value {
+	var tagVals interface{}
+	if tags != nil {
+		m := map[string]tftypes.Value{}
+		for k, v := range tags {
+			m[k] = tftypes.NewValue(tftypes.String, v)
+		}
+		tagVals = m
+	}
+	return tftypes.NewValue(testType, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, id),
+		"name": tftypes.NewValue(tftypes.String, name),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tagVals),
+	})
+}
+
+func TestBuild(t *testing.T) {
+	type testCase struct {
+		strict      bool
+		planned     tftypes.Value
+		response    interface{}
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"fills-unknown": {
+			planned:  testValue(tftypes.UnknownValue, "foo", nil),
+			response: testResponse{ID: "abc", Name: "foo"},
+			expected: testValue("abc", "foo", nil),
+		},
+		"keeps-planned": {
+			planned:  testValue(tftypes.UnknownValue, "foo", nil),
+			response: testResponse{ID: "abc", Name: "FOO"},
+			expected: testValue("abc", "foo", nil),
+		},
+		"null-response": {
+			strict:   true,
+			planned:  testValue(tftypes.UnknownValue, "foo", map[string]interface{}{"a": "1"}),
+			response: map[string]interface{}{"id": "abc"},
+			expected: testValue("abc", "foo", map[string]interface{}{"a": "1"}),
+		},
+		"nested-unknown": {
+			planned:  testValue("abc", "foo", map[string]interface{}{"a": "1", "b": tftypes.UnknownValue}),
+			response: testResponse{ID: "abc", Name: "foo", Tags: map[string]string{"a": "1", "b": "2"}},
+			expected: testValue("abc", "foo", map[string]interface{}{"a": "1", "b": "2"}),
+		},
+		"value": {
+			planned:  testValue(tftypes.UnknownValue, "foo", nil),
+			response: testValue("abc", nil, nil),
+			expected: testValue("abc", "foo", nil),
+		},
+		"destroy": {
+			planned:  tftypes.NewValue(testType, nil),
+			response: testResponse{ID: "abc"},
+			expected: tftypes.NewValue(testType, nil),
+		},
+		"strict-inconsistent": {
+			strict:      true,
+			planned:     testValue(tftypes.UnknownValue, "foo", nil),
+			response:    testResponse{ID: "abc", Name: "FOO"},
+			expectedErr: `AttributeName("name"): planned value tftypes.String<"foo"> does not match value tftypes.String<"FOO"> returned after apply`,
+		},
+		"strict-extra-element": {
+			strict:      true,
+			planned:     testValue("abc", "foo", map[string]interface{}{"a": tftypes.UnknownValue}),
+			response:    testResponse{ID: "abc", Name: "foo", Tags: map[string]string{"a": "1", "b": "2"}},
+			expectedErr: `AttributeName("tags"): element "b" was returned after apply, but was not planned`,
+		},
+		"still-unknown": {
+			planned:     testValue(tftypes.UnknownValue, "foo", nil),
+			response:    testValue(tftypes.UnknownValue, "foo", nil),
+			expectedErr: `AttributeName("id"): value is still unknown after apply`,
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			b := Builder{Strict: test.strict}
+			got, err := b.Build(testSchema, test.planned, test.response)
+			if test.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", test.expectedErr)
+				}
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
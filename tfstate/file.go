@@ -0,0 +1,325 @@
+package tfstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// File is a Terraform state file, terraform.tfstate, in the version 4
+// format written by Terraform 0.12 and later. It's meant for tooling and
+// tests that inspect state, like migration tools and state analyzers;
+// providers never see state files.
+type File struct {
+	Version          int               `json:"version"`
+	TerraformVersion string            `json:"terraform_version"`
+	Serial           uint64            `json:"serial"`
+	Lineage          string            `json:"lineage"`
+	Outputs          map[string]Output `json:"outputs"`
+	Resources        []Resource        `json:"resources"`
+}
+
+// Output is a root module output value recorded in a state file.
+type Output struct {
+	Value     json.RawMessage `json:"value"`
+	Type      json.RawMessage `json:"type"`
+	Sensitive bool            `json:"sensitive"`
+}
+
+// Resource is a resource or data source recorded in a state file, with all
+// of its instances.
+type Resource struct {
+	// Module is the address of the module the resource is in, like
+	// module.network, or empty for the root module.
+	Module string `json:"module"`
+
+	// Mode is "managed" for resources and "data" for data sources.
+	Mode string `json:"mode"`
+
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+
+	// Each is "list" for resources using count, "map" for ones using
+	// for_each, and empty otherwise.
+	Each string `json:"each"`
+
+	Instances []Instance `json:"instances"`
+}
+
+// Instance is an instance of a resource recorded in a state file.
+type Instance struct {
+	// IndexKey is the instance's index: a float64 for resources using
+	// count, a string for ones using for_each, and nil otherwise.
+	IndexKey interface{} `json:"index_key"`
+
+	// Status is "tainted" if the instance is tainted, and empty otherwise.
+	Status string `json:"status"`
+
+	// Deposed is the key of a deposed object, left behind by
+	// create_before_destroy, and empty for the current object.
+	Deposed string `json:"deposed"`
+
+	// SchemaVersion is the version of the resource type's schema the
+	// instance's attributes were written with, which the provider's
+	// UpgradeResourceState will be called with.
+	SchemaVersion int64 `json:"schema_version"`
+
+	// AttributesJSON holds the instance's attributes, unless it was
+	// written by Terraform 0.11 or earlier, in which case AttributesFlat
+	// does.
+	AttributesJSON json.RawMessage   `json:"attributes"`
+	AttributesFlat map[string]string `json:"attributes_flat"`
+
+	// SensitiveAttributes are the paths of the attributes marked as
+	// sensitive, in cty's path format. SensitivePaths returns them as
+	// tftypes.AttributePaths.
+	SensitiveAttributes json.RawMessage `json:"sensitive_attributes"`
+
+	// Private is the provider's private data for the instance.
+	Private []byte `json:"private"`
+
+	Dependencies        []string `json:"dependencies"`
+	CreateBeforeDestroy bool     `json:"create_before_destroy"`
+}
+
+// ReadFile reads and parses the state file at `path`.
+func ReadFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseFile(f)
+}
+
+// ParseFile parses a state file from `r`. Only version 4 state files are
+// supported.
+func ParseFile(r io.Reader) (*File, error) {
+	var file File
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("error parsing state file: %w", err)
+	}
+	if file.Version != 4 {
+		return nil, fmt.Errorf("unsupported state file version %d, only version 4 is supported", file.Version)
+	}
+	return &file, nil
+}
+
+// Address returns the address of the resource, like
+// module.network.data.example_vpc.main.
+func (r *Resource) Address() string {
+	addr := r.Type + "." + r.Name
+	if r.Mode == "data" {
+		addr = "data." + addr
+	}
+	if r.Module != "" {
+		addr = r.Module + "." + addr
+	}
+	return addr
+}
+
+// InstanceAddress returns the address of the instance `inst` of `r`, like
+// example_server.web[0] or example_server.web["a"]. Deposed objects have
+// the same address as their instance, followed by their key, like
+// Terraform shows them: example_server.web (deposed object 1a2b3c4d).
+func (r *Resource) InstanceAddress(inst *Instance) string {
+	addr := r.Address()
+	switch key := inst.IndexKey.(type) {
+	case float64:
+		addr += "[" + strconv.FormatFloat(key, 'f', -1, 64) + "]"
+	case string:
+		addr += "[" + strconv.Quote(key) + "]"
+	}
+	if inst.Deposed != "" {
+		addr += " (deposed object " + inst.Deposed + ")"
+	}
+	return addr
+}
+
+// Instances returns every resource instance in the state file, keyed by
+// address, as described by Resource.InstanceAddress.
+func (f *File) Instances() map[string]*Instance {
+	res := map[string]*Instance{}
+	for i := range f.Resources {
+		r := &f.Resources[i]
+		for j := range r.Instances {
+			res[r.InstanceAddress(&r.Instances[j])] = &r.Instances[j]
+		}
+	}
+	return res
+}
+
+// Instance returns the resource instance at `address`, and false if there
+// isn't one.
+func (f *File) Instance(address string) (*Instance, bool) {
+	inst, ok := f.Instances()[address]
+	return inst, ok
+}
+
+// RawState returns the instance's attributes as a tfprotov5.RawState, as
+// Terraform would send them to UpgradeResourceState, for passing to
+// Chain.Upgrade along with SchemaVersion.
+func (i *Instance) RawState() *tfprotov5.RawState {
+	if i.AttributesJSON == nil && i.AttributesFlat != nil {
+		return &tfprotov5.RawState{Flatmap: i.AttributesFlat}
+	}
+	return &tfprotov5.RawState{JSON: i.AttributesJSON}
+}
+
+// Value returns the instance's attributes as a value of type `typ`, the
+// type of the resource's schema at SchemaVersion, using
+// UnmarshalRawState.
+func (i *Instance) Value(typ tftypes.Type) (tftypes.Value, error) {
+	return UnmarshalRawState(i.RawState(), typ)
+}
+
+// Primitive returns the instance's attributes as a GoPrimitive, for when
+// the resource's schema isn't available. The types of the attributes are
+// inferred from their JSON: arrays become tuples and objects objects, and
+// numbers keep their full precision. Attributes written by Terraform 0.11
+// and earlier are all strings, with their structure inferred as described
+// by UnmarshalRawState.
+func (i *Instance) Primitive() (asgotypes.GoPrimitive, error) {
+	val, err := UnmarshalRawState(i.RawState(), tftypes.DynamicPseudoType)
+	if err != nil {
+		return asgotypes.GoPrimitive{}, err
+	}
+	p := asgotypes.GoPrimitive{Collections: asgotypes.CollectionsLenient}
+	if err := p.FromTerraform5Value(val); err != nil {
+		return asgotypes.GoPrimitive{}, err
+	}
+	return p, nil
+}
+
+// inferJSON converts `v`, decoded from JSON with numbers as json.Numbers,
+// into a tftypes.Value, inferring its type.
+func inferJSON(path *tftypes.AttributePath, v interface{}) (tftypes.Value, error) {
+	switch x := v.(type) {
+	case nil:
+		return tftypes.NewValue(tftypes.DynamicPseudoType, nil), nil
+	case string:
+		return tftypes.NewValue(tftypes.String, x), nil
+	case bool:
+		return tftypes.NewValue(tftypes.Bool, x), nil
+	case json.Number:
+		f, _, err := big.ParseFloat(string(x), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(tftypes.Number, f), nil
+	case []interface{}:
+		elems := make([]tftypes.Value, 0, len(x))
+		types := make([]tftypes.Type, 0, len(x))
+		for i, e := range x {
+			elem, err := inferJSON(path.WithElementKeyInt(i), e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+			types = append(types, elem.Type())
+		}
+		return tftypes.NewValue(tftypes.Tuple{ElementTypes: types}, elems), nil
+	case map[string]interface{}:
+		attrs := make(map[string]tftypes.Value, len(x))
+		types := make(map[string]tftypes.Type, len(x))
+		for k, e := range x {
+			attr, err := inferJSON(path.WithAttributeName(k), e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[k] = attr
+			types[k] = attr.Type()
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, attrs), nil
+	}
+	return tftypes.Value{}, path.NewErrorf("unexpected %T in JSON", v)
+}
+
+// SensitivePaths returns the instance's SensitiveAttributes as
+// tftypes.AttributePaths, sorted by their string representation.
+func (i *Instance) SensitivePaths() ([]*tftypes.AttributePath, error) {
+	if len(i.SensitiveAttributes) == 0 {
+		return nil, nil
+	}
+	var raw [][]struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(i.SensitiveAttributes, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing sensitive attributes: %w", err)
+	}
+	paths := make([]*tftypes.AttributePath, 0, len(raw))
+	for _, steps := range raw {
+		path := tftypes.NewAttributePath()
+		for _, step := range steps {
+			switch step.Type {
+			case "get_attr":
+				var name string
+				if err := json.Unmarshal(step.Value, &name); err != nil {
+					return nil, fmt.Errorf("error parsing sensitive attributes: %w", err)
+				}
+				path = path.WithAttributeName(name)
+			case "index":
+				var err error
+				if path, err = withIndex(path, step.Value); err != nil {
+					return nil, fmt.Errorf("error parsing sensitive attributes: %w", err)
+				}
+			default:
+				return nil, fmt.Errorf("unsupported step type %q in sensitive attributes", step.Type)
+			}
+		}
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return paths[i].String() < paths[j].String() })
+	return paths, nil
+}
+
+// withIndex returns `path` followed by the index `raw`, a string or number
+// in cty's JSON format for dynamically-typed values.
+func withIndex(path *tftypes.AttributePath, raw json.RawMessage) (*tftypes.AttributePath, error) {
+	key, err := tftypes.ValueFromJSON(raw, tftypes.DynamicPseudoType)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case key.Type().Is(tftypes.String):
+		var s string
+		if err := key.As(&s); err != nil {
+			return nil, err
+		}
+		return path.WithElementKeyString(s), nil
+	case key.Type().Is(tftypes.Number):
+		var f big.Float
+		if err := key.As(&f); err != nil {
+			return nil, err
+		}
+		n, acc := f.Int64()
+		if acc != big.Exact {
+			return nil, fmt.Errorf("index %s is not a whole number", f.Text('g', -1))
+		}
+		return path.WithElementKeyInt(int(n)), nil
+	}
+	return nil, fmt.Errorf("unsupported index of type %s", key.Type())
+}
+
+// TypedValue returns the output's value, with the type recorded alongside
+// it.
+func (o Output) TypedValue() (tftypes.Value, error) {
+	var b strings.Builder
+	b.WriteString(`{"value":`)
+	b.Write(o.Value)
+	b.WriteString(`,"type":`)
+	b.Write(o.Type)
+	b.WriteString(`}`)
+	return tftypes.ValueFromJSON([]byte(b.String()), tftypes.DynamicPseudoType)
+}
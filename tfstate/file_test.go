@@ -0,0 +1,161 @@
+package tfstate
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var fileServerType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"id":       tftypes.String,
+	"name":     tftypes.String,
+	"size":     tftypes.Number,
+	"tags":     tftypes.Map{ElementType: tftypes.String},
+	"password": tftypes.String,
+}}
+
+func readTestFile(t *testing.T) *File {
+	t.Helper()
+	f, err := ReadFile("testdata/terraform.tfstate")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return f
+}
+
+func TestFileInstances(t *testing.T) {
+	f := readTestFile(t)
+	var addrs []string
+	for addr := range f.Instances() {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	expected := []string{
+		"example_server.web[0]",
+		"example_server.web[1]",
+		"module.legacy.data.example_image.base",
+	}
+	if diff := cmp.Diff(expected, addrs); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	inst, ok := f.Instance("example_server.web[1]")
+	if !ok {
+		t.Fatal("expected example_server.web[1] to be found")
+	}
+	if inst.SchemaVersion != 2 || inst.Status != "tainted" {
+		t.Errorf("unexpected instance metadata: schema version %d, status %q", inst.SchemaVersion, inst.Status)
+	}
+	if _, ok := f.Instance("example_server.web[2]"); ok {
+		t.Error("expected example_server.web[2] not to be found")
+	}
+}
+
+func TestInstanceValue(t *testing.T) {
+	f := readTestFile(t)
+	inst, _ := f.Instance("example_server.web[0]")
+	size, _, _ := big.ParseFloat("12345678901234567890", 10, 512, big.ToNearestEven)
+	expected := tftypes.NewValue(fileServerType, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "s-1"),
+		"name": tftypes.NewValue(tftypes.String, "web-0"),
+		"size": tftypes.NewValue(tftypes.Number, size),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"env": tftypes.NewValue(tftypes.String, "prod"),
+		}),
+		"password": tftypes.NewValue(tftypes.String, "hunter2"),
+	})
+	got, err := inst.Value(fileServerType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	paths, err := inst.SensitivePaths()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedPaths := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("password"),
+		tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyString("env"),
+	}
+	if diff := cmp.Diff(expectedPaths, paths); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if string(inst.Private) != `{"schema_version":"2"}` {
+		t.Errorf("unexpected private data %q", inst.Private)
+	}
+}
+
+func TestInstanceFlatmap(t *testing.T) {
+	f := readTestFile(t)
+	inst, _ := f.Instance("module.legacy.data.example_image.base")
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":   tftypes.String,
+		"tags": tftypes.Map{ElementType: tftypes.String},
+	}}
+	expected := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "img-1"),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"os": tftypes.NewValue(tftypes.String, "linux"),
+		}),
+	})
+	got, err := inst.Value(typ)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestInstancePrimitive(t *testing.T) {
+	f := readTestFile(t)
+	inst, _ := f.Instance("example_server.web[1]")
+	got, err := inst.Primitive()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]interface{}{
+		"id":       "s-2",
+		"name":     "web-1",
+		"size":     big.NewFloat(1),
+		"tags":     nil,
+		"password": nil,
+	}
+	if diff := cmp.Diff(expected, got.Value, cmp.Comparer(func(a, b *big.Float) bool { return a.Cmp(b) == 0 })); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestOutputTypedValue(t *testing.T) {
+	f := readTestFile(t)
+	out := f.Outputs["ports"]
+	if !out.Sensitive {
+		t.Error("expected ports to be sensitive")
+	}
+	expected := tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, []tftypes.Value{
+		tftypes.NewValue(tftypes.Number, big.NewFloat(80)),
+		tftypes.NewValue(tftypes.Number, big.NewFloat(443)),
+	})
+	got, err := out.TypedValue()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestParseFileVersion(t *testing.T) {
+	_, err := ParseFile(strings.NewReader(`{"version": 3}`))
+	expectedErr := "unsupported state file version 3, only version 4 is supported"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
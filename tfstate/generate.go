@@ -0,0 +1,19 @@
+package tfstate
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// GenerateConfig returns the configuration that produces `state`, which is
+// described by `schema`, for GenerateResourceConfig. Attributes that are
+// computed but not optional can't be configured, so they're null, as are
+// write-only attributes, which are never present in state.
+func GenerateConfig(schema *tfprotov5.Schema, state tftypes.Value) (tftypes.Value, error) {
+	if schema == nil {
+		return state, nil
+	}
+	return filterBlock(tftypes.NewAttributePath(), schema.Block, state, func(attr *tfprotov5.SchemaAttribute) bool {
+		return (attr.Computed && !attr.Optional) || attr.WriteOnly
+	})
+}
@@ -0,0 +1,58 @@
+package tfstate
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGenerateConfig(t *testing.T) {
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "id", Type: tftypes.String, Computed: true},
+				{Name: "name", Type: tftypes.String, Required: true},
+				{Name: "region", Type: tftypes.String, Optional: true, Computed: true},
+			},
+			BlockTypes: []*tfprotov5.SchemaNestedBlock{
+				{
+					TypeName: "rule",
+					Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+					Block: &tfprotov5.SchemaBlock{
+						Attributes: []*tfprotov5.SchemaAttribute{
+							{Name: "port", Type: tftypes.String, Required: true},
+							{Name: "arn", Type: tftypes.String, Computed: true},
+						},
+					},
+				},
+			},
+		},
+	}
+	ruleType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"port": tftypes.String,
+		"arn":  tftypes.String,
+	}}
+	value := func(id, arn interface{}) tftypes.Value {
+		return tftypes.NewValue(schema.ValueType(), map[string]tftypes.Value{
+			"id":     tftypes.NewValue(tftypes.String, id),
+			"name":   tftypes.NewValue(tftypes.String, "example"),
+			"region": tftypes.NewValue(tftypes.String, "us-east-1"),
+			"rule": tftypes.NewValue(tftypes.List{ElementType: ruleType}, []tftypes.Value{
+				tftypes.NewValue(ruleType, map[string]tftypes.Value{
+					"port": tftypes.NewValue(tftypes.String, "80"),
+					"arn":  tftypes.NewValue(tftypes.String, arn),
+				}),
+			}),
+		})
+	}
+
+	got, err := GenerateConfig(schema, value("abc", "arn:rule"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(value(nil, nil), got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,153 @@
+package tfstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Transformer transforms the state of a resource of another type into the
+// state of the resource it's being moved to.
+type Transformer interface {
+	// Type returns the type of the source resource's state.
+	Type() tftypes.Type
+
+	// Transform returns the state of the target resource. The result is
+	// encoded with asgotypes.Encode, so it may be a tagged struct, a
+	// GoPrimitive, or a tftypes.Value.
+	Transform(ctx context.Context, source tftypes.Value) (interface{}, error)
+}
+
+// TransformFunc returns a Transformer for source state of type `typ`, which
+// is decoded into `From` using asgotypes.Decode before being passed to `f`.
+func TransformFunc[From, To any](typ tftypes.Type, f func(ctx context.Context, source From) (To, error)) Transformer {
+	return transformFunc[From, To]{typ: typ, f: f}
+}
+
+type transformFunc[From, To any] struct {
+	typ tftypes.Type
+	f   func(ctx context.Context, source From) (To, error)
+}
+
+func (t transformFunc[From, To]) Type() tftypes.Type {
+	return t.typ
+}
+
+func (t transformFunc[From, To]) Transform(ctx context.Context, source tftypes.Value) (interface{}, error) {
+	var decoded From
+	if err := asgotypes.Decode(source, &decoded); err != nil {
+		return nil, err
+	}
+	return t.f(ctx, decoded)
+}
+
+// Move declares that state can be moved from resources of another type,
+// possibly in another provider.
+type Move struct {
+	// ProviderAddress is the address of the source resource's provider,
+	// like "registry.terraform.io/hashicorp/example". Moves with an empty
+	// ProviderAddress match any provider.
+	ProviderAddress string
+
+	// TypeName is the source resource type.
+	TypeName string
+
+	// SchemaVersion is the version of the source resource's schema that
+	// Transformer accepts state for.
+	SchemaVersion int64
+
+	// Transformer transforms the source resource's state.
+	Transformer Transformer
+}
+
+func (m Move) matches(req *tfprotov5.MoveResourceStateRequest) bool {
+	if m.ProviderAddress != "" && m.ProviderAddress != req.SourceProviderAddress {
+		return false
+	}
+	return m.TypeName == req.SourceTypeName
+}
+
+// Mover moves state from other resource types to a resource using the first
+// of its Moves that matches the source resource.
+type Mover struct {
+	// Schema is the schema of the target resource.
+	Schema *tfprotov5.Schema
+
+	// Moves declares the resources that state can be moved from.
+	Moves []Move
+}
+
+// Move decodes the source state of `req` and transforms it into a value
+// conforming to the target resource's schema.
+//
+// The source state is decoded using UnmarshalRawState, so attributes that
+// aren't part of the Transformer's type are ignored. It is an error for the
+// result to contain unknown values or non-null write-only attributes.
+func (m *Mover) Move(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (tftypes.Value, error) {
+	var move *Move
+	for i := range m.Moves {
+		if m.Moves[i].matches(req) {
+			move = &m.Moves[i]
+			break
+		}
+	}
+	if move == nil {
+		return tftypes.Value{}, fmt.Errorf("moving state from %s is not supported", req.SourceTypeName)
+	}
+	if req.SourceSchemaVersion != move.SchemaVersion {
+		return tftypes.Value{}, fmt.Errorf("moving state from schema version %d of %s is not supported, only version %d", req.SourceSchemaVersion, req.SourceTypeName, move.SchemaVersion)
+	}
+	if req.SourceState == nil {
+		return tftypes.Value{}, errors.New("source state is missing")
+	}
+	source, err := UnmarshalRawState(req.SourceState, move.Transformer.Type())
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("reading source state: %w", err)
+	}
+	transformed, err := move.Transformer.Transform(ctx, source)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	target, err := asgotypes.Encode(m.Schema.ValueType(), transformed)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if !target.IsFullyKnown() {
+		return tftypes.Value{}, errors.New("moved state cannot contain unknown values")
+	}
+	if err := ValidateWriteOnly(m.Schema, target); err != nil {
+		return tftypes.Value{}, err
+	}
+	return target, nil
+}
+
+// MoveResourceState implements the MoveResourceState RPC using Move. Any
+// error is returned as a diagnostic on the response.
+func (m *Mover) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) *tfprotov5.MoveResourceStateResponse {
+	resp := &tfprotov5.MoveResourceStateResponse{}
+	supported := false
+	for _, move := range m.Moves {
+		supported = supported || move.matches(req)
+	}
+	if !supported {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Errorf("Resource move not supported", "The %s resource does not support moving state from %s.", req.TargetTypeName, req.SourceTypeName))
+		return resp
+	}
+	state, err := m.Move(ctx, req)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error moving state", err))
+		return resp
+	}
+	dv, err := tfprotov5.NewDynamicValue(m.Schema.ValueType(), state)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error moving state", err))
+		return resp
+	}
+	resp.TargetState = &dv
+	return resp
+}
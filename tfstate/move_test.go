@@ -0,0 +1,149 @@
+package tfstate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type testLegacyState struct {
+	ID    string `tfsdk:"id"`
+	Label string `tfsdk:"label"`
+	Size  string `tfsdk:"size"`
+}
+
+var testLegacyType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"id":    tftypes.String,
+	"label": tftypes.String,
+	"size":  tftypes.String,
+}}
+
+func testMover() *Mover {
+	return &Mover{
+		Schema: testSchemaV2,
+		Moves: []Move{
+			{
+				ProviderAddress: "registry.terraform.io/example/legacy",
+				TypeName:        "legacy_widget",
+				SchemaVersion:   1,
+				Transformer: TransformFunc(testLegacyType, func(ctx context.Context, source testLegacyState) (testStateV2, error) {
+					if source.Size == "" {
+						return testStateV2{}, errors.New("size is missing")
+					}
+					return testStateV2{ID: source.ID, DisplayName: source.Label, Size: len(source.Size)}, nil
+				}),
+			},
+			{
+				TypeName: "example_gadget",
+				Transformer: TransformFunc(testLegacyType, func(ctx context.Context, source testLegacyState) (tftypes.Value, error) {
+					return tftypes.NewValue(testSchemaV2.ValueType(), map[string]tftypes.Value{
+						"id":           tftypes.NewValue(tftypes.String, source.ID),
+						"display_name": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+						"size":         tftypes.NewValue(tftypes.Number, 0),
+					}), nil
+				}),
+			},
+		},
+	}
+}
+
+func TestMoverMoveResourceState(t *testing.T) {
+	type testCase struct {
+		req           *tfprotov5.MoveResourceStateRequest
+		expected      tftypes.Value
+		expectedDiags []*tfprotov5.Diagnostic
+	}
+	cases := map[string]testCase{
+		"moved": {
+			req: &tfprotov5.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/example/legacy",
+				SourceTypeName:        "legacy_widget",
+				SourceSchemaVersion:   1,
+				SourceState:           &tfprotov5.RawState{JSON: []byte(`{"id": "w-1", "label": "foo", "size": "xxl", "removed": true}`)},
+				TargetTypeName:        "example_widget",
+			},
+			expected: tftypes.NewValue(testSchemaV2.ValueType(), map[string]tftypes.Value{
+				"id":           tftypes.NewValue(tftypes.String, "w-1"),
+				"display_name": tftypes.NewValue(tftypes.String, "foo"),
+				"size":         tftypes.NewValue(tftypes.Number, 3),
+			}),
+		},
+		"other-provider": {
+			req: &tfprotov5.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/example/other",
+				SourceTypeName:        "legacy_widget",
+				SourceState:           &tfprotov5.RawState{JSON: []byte(`{}`)},
+				TargetTypeName:        "example_widget",
+			},
+			expectedDiags: []*tfprotov5.Diagnostic{{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Resource move not supported",
+				Detail:   "The example_widget resource does not support moving state from legacy_widget.",
+			}},
+		},
+		"other-version": {
+			req: &tfprotov5.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/example/legacy",
+				SourceTypeName:        "legacy_widget",
+				SourceSchemaVersion:   2,
+				SourceState:           &tfprotov5.RawState{JSON: []byte(`{}`)},
+				TargetTypeName:        "example_widget",
+			},
+			expectedDiags: []*tfprotov5.Diagnostic{{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Error moving state",
+				Detail:   "moving state from schema version 2 of legacy_widget is not supported, only version 1",
+			}},
+		},
+		"transform-error": {
+			req: &tfprotov5.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/example/legacy",
+				SourceTypeName:        "legacy_widget",
+				SourceSchemaVersion:   1,
+				SourceState:           &tfprotov5.RawState{JSON: []byte(`{"id": "w-1"}`)},
+				TargetTypeName:        "example_widget",
+			},
+			expectedDiags: []*tfprotov5.Diagnostic{{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Error moving state",
+				Detail:   "size is missing",
+			}},
+		},
+		"unknown": {
+			req: &tfprotov5.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/example/gadgets",
+				SourceTypeName:        "example_gadget",
+				SourceState:           &tfprotov5.RawState{JSON: []byte(`{"id": "g-1"}`)},
+				TargetTypeName:        "example_widget",
+			},
+			expectedDiags: []*tfprotov5.Diagnostic{{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Error moving state",
+				Detail:   "moved state cannot contain unknown values",
+			}},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			resp := testMover().MoveResourceState(context.Background(), tc.req)
+			if diff := cmp.Diff(tc.expectedDiags, resp.Diagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics diff (-wanted, +got): %s", diff)
+			}
+			if tc.expectedDiags != nil {
+				return
+			}
+			got, err := resp.TargetState.Unmarshal(testSchemaV2.ValueType())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
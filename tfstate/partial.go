@@ -0,0 +1,29 @@
+package tfstate
+
+import (
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Partial builds the state to return from ApplyResourceChange when the apply
+// failed part of the way through, for a resource described by `schema`.
+// `response` is encoded using asgotypes.Encode, and should describe the
+// remote object as far as the apply got.
+//
+// Terraform doesn't require the state returned alongside errors to agree
+// with the plan, so unlike Build the planned state isn't consulted. State
+// can't contain unknown values, so any in the response are replaced with
+// nulls.
+func Partial(schema *tfprotov5.Schema, response interface{}) (tftypes.Value, error) {
+	val, err := asgotypes.Encode(schema.ValueType(), response)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.Transform(val, func(path *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !v.IsKnown() {
+			return tftypes.NewValue(v.Type(), nil), nil
+		}
+		return v, nil
+	})
+}
@@ -0,0 +1,59 @@
+package tfstate
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPartial(t *testing.T) {
+	type testCase struct {
+		response    interface{}
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"struct": {
+			response: testResponse{ID: "abc"},
+			expected: testValue("abc", "", nil),
+		},
+		"unknowns": {
+			response: testValue("abc", tftypes.UnknownValue, map[string]interface{}{
+				"a": tftypes.UnknownValue,
+			}),
+			expected: testValue("abc", nil, map[string]interface{}{
+				"a": nil,
+			}),
+		},
+		"null": {
+			response: tftypes.NewValue(testType, nil),
+			expected: tftypes.NewValue(testType, nil),
+		},
+		"wrong-type": {
+			response:    tftypes.NewValue(tftypes.String, "abc"),
+			expectedErr: "cannot use value of type tftypes.String as tftypes.Object[\"id\":tftypes.String, \"name\":tftypes.String, \"tags\":tftypes.Map[tftypes.String]]",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := Partial(testSchema, tc.response)
+			if err != nil {
+				if tc.expectedErr == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %q", tc.expectedErr, err.Error())
+				}
+				return
+			}
+			if tc.expectedErr != "" {
+				t.Fatalf("expected error %q, got none", tc.expectedErr)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package tfstate
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// unknownFlatmapValue is the placeholder the legacy SDK used for unknown
+// values in flatmap state.
+const unknownFlatmapValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// UnmarshalRawState decodes `raw` as a value of type `typ`, ignoring any
+// attributes that aren't part of `typ`.
+//
+// Unlike tfprotov5.RawState.Unmarshal, state in the legacy flatmap format
+// written by Terraform 0.11 and earlier is supported. Flatmap state doesn't
+// record types, so values are converted to the types described by `typ`.
+//
+// If `typ` is tftypes.DynamicPseudoType, the state's structure is inferred
+// instead, so it can be decoded into an asgotypes.GoPrimitive when its type
+// at an old schema version is no longer known. JSON arrays are read as
+// tuples and objects as objects. In flatmap state, every value is a string,
+// collections with a "#" count are tuples, ordered by index for lists or by
+// key for sets, collections with a "%" count are maps of strings, and any
+// other keys sharing a prefix are objects.
+func UnmarshalRawState(raw *tfprotov5.RawState, typ tftypes.Type) (tftypes.Value, error) {
+	if raw == nil {
+		return tftypes.Value{}, tfprotov5.ErrUnknownRawStateType
+	}
+	if typ.Is(tftypes.DynamicPseudoType) {
+		return inferRawState(raw)
+	}
+	if raw.JSON == nil && raw.Flatmap != nil {
+		return valueFromFlatmap(tftypes.NewAttributePath(), raw.Flatmap, "", typ)
+	}
+	return raw.UnmarshalWithOpts(typ, tfprotov5.UnmarshalOpts{
+		ValueFromJSONOpts: tftypes.ValueFromJSONOpts{
+			IgnoreUndefinedAttributes: true,
+		},
+	})
+}
+
+// DecodeRawState decodes `raw` as a value of type `typ` using
+// UnmarshalRawState, then decodes the result into `target` using
+// asgotypes.Decode. `target` is usually a pointer to a tagged struct or an
+// asgotypes.GoPrimitive.
+func DecodeRawState(raw *tfprotov5.RawState, typ tftypes.Type, target interface{}) error {
+	val, err := UnmarshalRawState(raw, typ)
+	if err != nil {
+		return err
+	}
+	return asgotypes.Decode(val, target)
+}
+
+func flatmapKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func valueFromFlatmap(path *tftypes.AttributePath, m map[string]string, prefix string, typ tftypes.Type) (tftypes.Value, error) {
+	switch {
+	case typ.Is(tftypes.String), typ.Is(tftypes.Number), typ.Is(tftypes.Bool):
+		return primitiveFromFlatmap(path, m, prefix, typ)
+	case typ.Is(tftypes.Object{}):
+		return objectFromFlatmap(path, m, prefix, typ.(tftypes.Object))
+	case typ.Is(tftypes.List{}):
+		return listFromFlatmap(path, m, prefix, typ, func(int) tftypes.Type {
+			return typ.(tftypes.List).ElementType
+		})
+	case typ.Is(tftypes.Tuple{}):
+		elemTypes := typ.(tftypes.Tuple).ElementTypes
+		return listFromFlatmap(path, m, prefix, typ, func(i int) tftypes.Type {
+			if i < len(elemTypes) {
+				return elemTypes[i]
+			}
+			return nil
+		})
+	case typ.Is(tftypes.Set{}):
+		return setFromFlatmap(path, m, prefix, typ.(tftypes.Set))
+	case typ.Is(tftypes.Map{}):
+		return mapFromFlatmap(path, m, prefix, typ.(tftypes.Map))
+	}
+	return tftypes.Value{}, path.NewErrorf("cannot read %s from flatmap state", typ)
+}
+
+func primitiveFromFlatmap(path *tftypes.AttributePath, m map[string]string, prefix string, typ tftypes.Type) (tftypes.Value, error) {
+	s, ok := m[prefix]
+	if !ok {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if s == unknownFlatmapValue {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	switch {
+	case typ.Is(tftypes.Number):
+		f, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+		if err != nil {
+			return tftypes.Value{}, path.NewErrorf("cannot read %q as a number", s)
+		}
+		return tftypes.NewValue(typ, f), nil
+	case typ.Is(tftypes.Bool):
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return tftypes.Value{}, path.NewErrorf("cannot read %q as a bool", s)
+		}
+		return tftypes.NewValue(typ, b), nil
+	}
+	return tftypes.NewValue(typ, s), nil
+}
+
+func objectFromFlatmap(path *tftypes.AttributePath, m map[string]string, prefix string, typ tftypes.Object) (tftypes.Value, error) {
+	if prefix != "" && !hasFlatmapPrefix(m, prefix) {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, attrType := range typ.AttributeTypes {
+		attr, err := valueFromFlatmap(path.WithAttributeName(name), m, flatmapKey(prefix, name), attrType)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		attrs[name] = attr
+	}
+	return tftypes.NewValue(typ, attrs), nil
+}
+
+// flatmapCount returns the number of elements in the collection at
+// `prefix`, which is stored under "prefix.#", or "prefix.%" for maps.
+func flatmapCount(path *tftypes.AttributePath, m map[string]string, prefix string) (int, bool, bool, error) {
+	s, ok := m[prefix+".#"]
+	if !ok {
+		s, ok = m[prefix+".%"]
+	}
+	if !ok {
+		return 0, false, false, nil
+	}
+	if s == unknownFlatmapValue {
+		return 0, true, false, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false, false, path.NewErrorf("cannot read %q as an element count", s)
+	}
+	return n, true, true, nil
+}
+
+func listFromFlatmap(path *tftypes.AttributePath, m map[string]string, prefix string, typ tftypes.Type, elemType func(int) tftypes.Type) (tftypes.Value, error) {
+	n, ok, known, err := flatmapCount(path, m, prefix)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if !ok {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if !known {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	elems := make([]tftypes.Value, 0, n)
+	for i := 0; i < n; i++ {
+		et := elemType(i)
+		if et == nil {
+			return tftypes.Value{}, path.NewErrorf("flatmap state has %d elements, more than the type allows", n)
+		}
+		elem, err := valueFromFlatmap(path.WithElementKeyInt(i), m, flatmapKey(prefix, strconv.Itoa(i)), et)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, elem)
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+func setFromFlatmap(path *tftypes.AttributePath, m map[string]string, prefix string, typ tftypes.Set) (tftypes.Value, error) {
+	_, ok, known, err := flatmapCount(path, m, prefix)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if !ok {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if !known {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	// set elements are keyed by their hash codes, which we don't need
+	// to recompute, only to group the keys by
+	var elems []tftypes.Value
+	for _, key := range flatmapSubkeys(m, prefix, false) {
+		elem, err := valueFromFlatmap(path, m, flatmapKey(prefix, key), typ.ElementType)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, elem)
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+func mapFromFlatmap(path *tftypes.AttributePath, m map[string]string, prefix string, typ tftypes.Map) (tftypes.Value, error) {
+	_, ok, known, err := flatmapCount(path, m, prefix)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if !ok {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if !known {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	// keys of maps of primitives may themselves contain dots
+	primitive := typ.ElementType.Is(tftypes.String) || typ.ElementType.Is(tftypes.Number) || typ.ElementType.Is(tftypes.Bool)
+	elems := map[string]tftypes.Value{}
+	for _, key := range flatmapSubkeys(m, prefix, primitive) {
+		elem, err := valueFromFlatmap(path.WithElementKeyString(key), m, flatmapKey(prefix, key), typ.ElementType)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems[key] = elem
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+// flatmapSubkeys returns the sorted, distinct keys of the elements of the
+// collection at `prefix`. If `whole` is true, the remainder of each key is
+// used rather than only its first segment.
+func flatmapSubkeys(m map[string]string, prefix string, whole bool) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for k := range m {
+		if !strings.HasPrefix(k, prefix+".") {
+			continue
+		}
+		rest := strings.TrimPrefix(k, prefix+".")
+		if rest == "#" || rest == "%" {
+			continue
+		}
+		if !whole {
+			rest, _, _ = strings.Cut(rest, ".")
+		}
+		if !seen[rest] {
+			seen[rest] = true
+			keys = append(keys, rest)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hasFlatmapPrefix(m map[string]string, prefix string) bool {
+	for k := range m {
+		if k == prefix || strings.HasPrefix(k, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func inferRawState(raw *tfprotov5.RawState) (tftypes.Value, error) {
+	if raw.JSON != nil {
+		dec := json.NewDecoder(bytes.NewReader(raw.JSON))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return tftypes.Value{}, err
+		}
+		return inferJSON(tftypes.NewAttributePath(), v)
+	}
+	if raw.Flatmap != nil {
+		return inferFlatmap(tftypes.NewAttributePath(), raw.Flatmap, "")
+	}
+	return tftypes.Value{}, tfprotov5.ErrUnknownRawStateType
+}
+
+// inferFlatmap converts the flatmap state at `prefix` into a value,
+// inferring its type from the keys present.
+func inferFlatmap(path *tftypes.AttributePath, m map[string]string, prefix string) (tftypes.Value, error) {
+	if s, ok := m[prefix]; ok && prefix != "" {
+		if s == unknownFlatmapValue {
+			return tftypes.NewValue(tftypes.String, tftypes.UnknownValue), nil
+		}
+		return tftypes.NewValue(tftypes.String, s), nil
+	}
+	if _, ok := m[prefix+".%"]; ok {
+		return inferFlatmapMap(path, m, prefix)
+	}
+	if _, ok := m[prefix+".#"]; ok {
+		return inferFlatmapList(path, m, prefix)
+	}
+	attrs := map[string]tftypes.Value{}
+	for _, name := range flatmapNames(m, prefix) {
+		attr, err := inferFlatmap(path.WithAttributeName(name), m, flatmapKey(prefix, name))
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		attrs[name] = attr
+	}
+	return inferredObject(attrs), nil
+}
+
+func inferFlatmapMap(path *tftypes.AttributePath, m map[string]string, prefix string) (tftypes.Value, error) {
+	typ := tftypes.Map{ElementType: tftypes.String}
+	_, _, known, err := flatmapCount(path, m, prefix)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if !known {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	elems := map[string]tftypes.Value{}
+	for _, key := range flatmapSubkeys(m, prefix, true) {
+		elem, err := primitiveFromFlatmap(path.WithElementKeyString(key), m, flatmapKey(prefix, key), tftypes.String)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems[key] = elem
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+func inferFlatmapList(path *tftypes.AttributePath, m map[string]string, prefix string) (tftypes.Value, error) {
+	n, _, known, err := flatmapCount(path, m, prefix)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if !known {
+		return tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue), nil
+	}
+	// lists are keyed by index, and sets by the elements' hash codes
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		key := strconv.Itoa(i)
+		if !hasFlatmapPrefix(m, flatmapKey(prefix, key)) {
+			keys = flatmapSubkeys(m, prefix, false)
+			break
+		}
+		keys = append(keys, key)
+	}
+	elems := make([]tftypes.Value, 0, len(keys))
+	for i, key := range keys {
+		elem, err := inferFlatmap(path.WithElementKeyInt(i), m, flatmapKey(prefix, key))
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, elem)
+	}
+	return inferredTuple(elems), nil
+}
+
+// flatmapNames returns the sorted, distinct names of the attributes of the
+// object at `prefix`, which is empty for the top-level object.
+func flatmapNames(m map[string]string, prefix string) []string {
+	if prefix != "" {
+		return flatmapSubkeys(m, prefix, false)
+	}
+	seen := map[string]bool{}
+	var names []string
+	for k := range m {
+		name, _, _ := strings.Cut(k, ".")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func inferredTuple(elems []tftypes.Value) tftypes.Value {
+	types := make([]tftypes.Type, 0, len(elems))
+	for _, elem := range elems {
+		types = append(types, elem.Type())
+	}
+	return tftypes.NewValue(tftypes.Tuple{ElementTypes: types}, elems)
+}
+
+func inferredObject(attrs map[string]tftypes.Value) tftypes.Value {
+	types := make(map[string]tftypes.Type, len(attrs))
+	for name, attr := range attrs {
+		types[name] = attr.Type()
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, attrs)
+}
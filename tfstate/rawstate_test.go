@@ -0,0 +1,272 @@
+package tfstate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	testRawRuleType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"port":    tftypes.Number,
+		"enabled": tftypes.Bool,
+	}}
+	testRawType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":     tftypes.String,
+		"tags":   tftypes.Map{ElementType: tftypes.String},
+		"names":  tftypes.List{ElementType: tftypes.String},
+		"rule":   tftypes.Set{ElementType: testRawRuleType},
+		"unused": tftypes.String,
+	}}
+)
+
+func testRawRule(port int64, enabled bool) tftypes.Value {
+	return tftypes.NewValue(testRawRuleType, map[string]tftypes.Value{
+		"port":    tftypes.NewValue(tftypes.Number, big.NewFloat(float64(port))),
+		"enabled": tftypes.NewValue(tftypes.Bool, enabled),
+	})
+}
+
+func TestUnmarshalRawState(t *testing.T) {
+	type testCase struct {
+		raw         *tfprotov5.RawState
+		expected    tftypes.Value
+		expectedErr string
+	}
+	expected := tftypes.NewValue(testRawType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "abc"),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"env":          tftypes.NewValue(tftypes.String, "prod"),
+			"example.com/": tftypes.NewValue(tftypes.String, "yes"),
+		}),
+		"names": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+			tftypes.NewValue(tftypes.String, "b"),
+		}),
+		"rule": tftypes.NewValue(tftypes.Set{ElementType: testRawRuleType}, []tftypes.Value{
+			testRawRule(80, true),
+			testRawRule(443, false),
+		}),
+		"unused": tftypes.NewValue(tftypes.String, nil),
+	})
+	cases := map[string]testCase{
+		"json": {
+			raw: &tfprotov5.RawState{JSON: []byte(`{
+				"id": "abc",
+				"tags": {"env": "prod", "example.com/": "yes"},
+				"names": ["a", "b"],
+				"rule": [{"port": 80, "enabled": true}, {"port": 443, "enabled": false}],
+				"removed": "ignored"
+			}`)},
+			expected: expected,
+		},
+		"flatmap": {
+			raw: &tfprotov5.RawState{Flatmap: map[string]string{
+				"id":                "abc",
+				"tags.%":            "2",
+				"tags.env":          "prod",
+				"tags.example.com/": "yes",
+				"names.#":           "2",
+				"names.0":           "a",
+				"names.1":           "b",
+				"rule.#":            "2",
+				"rule.1234.port":    "80",
+				"rule.1234.enabled": "true",
+				"rule.5678.port":    "443",
+				"rule.5678.enabled": "false",
+				"removed":           "ignored",
+			}},
+			expected: expected,
+		},
+		"flatmap-unknown": {
+			raw: &tfprotov5.RawState{Flatmap: map[string]string{
+				"id":      unknownFlatmapValue,
+				"names.#": unknownFlatmapValue,
+			}},
+			expected: tftypes.NewValue(testRawType, map[string]tftypes.Value{
+				"id":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"tags":   tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+				"names":  tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+				"rule":   tftypes.NewValue(tftypes.Set{ElementType: testRawRuleType}, nil),
+				"unused": tftypes.NewValue(tftypes.String, nil),
+			}),
+		},
+		"flatmap-invalid": {
+			raw: &tfprotov5.RawState{Flatmap: map[string]string{
+				"rule.#":         "1",
+				"rule.1234.port": "eighty",
+			}},
+			expectedErr: `AttributeName("rule").AttributeName("port"): cannot read "eighty" as a number`,
+		},
+		"empty": {
+			raw:         &tfprotov5.RawState{},
+			expectedErr: tfprotov5.ErrUnknownRawStateType.Error(),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			got, err := UnmarshalRawState(test.raw, testRawType)
+			if test.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", test.expectedErr)
+				}
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeRawState(t *testing.T) {
+	type rule struct {
+		Port    int  `tfsdk:"port"`
+		Enabled bool `tfsdk:"enabled"`
+	}
+	type state struct {
+		ID    string            `tfsdk:"id"`
+		Tags  map[string]string `tfsdk:"tags"`
+		Names []string          `tfsdk:"names"`
+		Rules []rule            `tfsdk:"rule"`
+	}
+	raw := &tfprotov5.RawState{Flatmap: map[string]string{
+		"id":             "abc",
+		"names.#":        "1",
+		"names.0":        "a",
+		"rule.#":         "1",
+		"rule.1234.port": "80",
+	}}
+
+	var got state
+	if err := DecodeRawState(raw, testRawType, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := state{ID: "abc", Names: []string{"a"}, Rules: []rule{{Port: 80}}}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	var prim asgotypes.GoPrimitive
+	if err := DecodeRawState(raw, testRawType, &prim); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	attrs, ok := prim.Value.(map[string]interface{})
+	if !ok || attrs["id"] != "abc" {
+		t.Errorf("unexpected GoPrimitive: %#v", prim.Value)
+	}
+}
+
+func TestUnmarshalRawStateDynamic(t *testing.T) {
+	type testCase struct {
+		raw         *tfprotov5.RawState
+		expected    interface{}
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"flatmap": {
+			raw: &tfprotov5.RawState{Flatmap: map[string]string{
+				"id":                "abc",
+				"tags.%":            "2",
+				"tags.env":          "prod",
+				"tags.example.com/": "yes",
+				"names.#":           "11",
+				"names.0":           "a",
+				"names.1":           "b",
+				"names.2":           "c",
+				"names.3":           "d",
+				"names.4":           "e",
+				"names.5":           "f",
+				"names.6":           "g",
+				"names.7":           "h",
+				"names.8":           "i",
+				"names.9":           "j",
+				"names.10":          "k",
+				"rule.#":            "2",
+				"rule.1234.port":    "80",
+				"rule.1234.enabled": "true",
+				"rule.5678.port":    "443",
+				"rule.5678.enabled": "false",
+				"empty.#":           "0",
+			}},
+			expected: map[string]interface{}{
+				"id":    "abc",
+				"tags":  map[string]interface{}{"env": "prod", "example.com/": "yes"},
+				"names": []interface{}{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+				"rule": []interface{}{
+					map[string]interface{}{"port": "80", "enabled": "true"},
+					map[string]interface{}{"port": "443", "enabled": "false"},
+				},
+				"empty": []interface{}{},
+			},
+		},
+		"flatmap-empty": {
+			raw:      &tfprotov5.RawState{Flatmap: map[string]string{}},
+			expected: map[string]interface{}{},
+		},
+		"flatmap-invalid-count": {
+			raw: &tfprotov5.RawState{Flatmap: map[string]string{
+				"names.#": "many",
+			}},
+			expectedErr: `AttributeName("names"): cannot read "many" as an element count`,
+		},
+		"json": {
+			raw: &tfprotov5.RawState{JSON: []byte(`{
+				"id": "abc",
+				"size": 10,
+				"enabled": true,
+				"names": ["a", "b"],
+				"removed": null
+			}`)},
+			expected: map[string]interface{}{
+				"id":      "abc",
+				"size":    big.NewFloat(10),
+				"enabled": true,
+				"names":   []interface{}{"a", "b"},
+				"removed": nil,
+			},
+		},
+		"empty": {
+			raw:         &tfprotov5.RawState{},
+			expectedErr: tfprotov5.ErrUnknownRawStateType.Error(),
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			val, err := UnmarshalRawState(test.raw, tftypes.DynamicPseudoType)
+			if test.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", test.expectedErr)
+				}
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := asgotypes.GoPrimitive{Collections: asgotypes.CollectionsLenient}
+			if err := got.FromTerraform5Value(val); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.expected, got.Value, cmp.Comparer(func(a, b *big.Float) bool {
+				return a.Cmp(b) == 0
+			})); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package tfstate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/diag"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Upgrader upgrades a resource's state from one schema version to the next.
+type Upgrader interface {
+	// Type returns the type of the state at the version being upgraded
+	// from. It may be tftypes.DynamicPseudoType to have the state's type
+	// inferred, as described by UnmarshalRawState, for decoding into an
+	// asgotypes.GoPrimitive.
+	Type() tftypes.Type
+
+	// Upgrade returns the state for the next version. The result is
+	// encoded with asgotypes.Encode, so it may be a tagged struct, a
+	// GoPrimitive, or a tftypes.Value.
+	Upgrade(ctx context.Context, prior tftypes.Value) (interface{}, error)
+}
+
+// UpgradeFunc returns an Upgrader for state of type `typ`, which is decoded
+// into `From` using asgotypes.Decode before being passed to `f`.
+func UpgradeFunc[From, To any](typ tftypes.Type, f func(ctx context.Context, prior From) (To, error)) Upgrader {
+	return upgradeFunc[From, To]{typ: typ, f: f}
+}
+
+type upgradeFunc[From, To any] struct {
+	typ tftypes.Type
+	f   func(ctx context.Context, prior From) (To, error)
+}
+
+func (u upgradeFunc[From, To]) Type() tftypes.Type {
+	return u.typ
+}
+
+func (u upgradeFunc[From, To]) Upgrade(ctx context.Context, prior tftypes.Value) (interface{}, error) {
+	var decoded From
+	if err := asgotypes.Decode(prior, &decoded); err != nil {
+		return nil, err
+	}
+	return u.f(ctx, decoded)
+}
+
+// Chain upgrades state written with any earlier schema version to the
+// current one by running Upgraders in sequence.
+type Chain struct {
+	// Schema is the current schema of the resource.
+	Schema *tfprotov5.Schema
+
+	// Upgraders holds the Upgraders for each earlier version, keyed by
+	// the version they upgrade from. There must be one for every version
+	// from the oldest supported version up to Schema.Version-1.
+	Upgraders map[int64]Upgrader
+}
+
+// Upgrade decodes `raw`, which was written with schema version `version`,
+// and upgrades it to the current version.
+//
+// `raw` is decoded using UnmarshalRawState, so attributes that aren't part
+// of the type being decoded are ignored, as Terraform may not have removed
+// them from state when they were removed from the schema, and legacy
+// flatmap state is supported.
+func (c *Chain) Upgrade(ctx context.Context, version int64, raw *tfprotov5.RawState) (tftypes.Value, error) {
+	if version > c.Schema.Version {
+		return tftypes.Value{}, fmt.Errorf("state was written with schema version %d, which is newer than the current version %d", version, c.Schema.Version)
+	}
+	typ, err := c.typeAt(version)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	state, err := UnmarshalRawState(raw, typ)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	for v := version; v < c.Schema.Version; v++ {
+		upgraded, err := c.Upgraders[v].Upgrade(ctx, state)
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("upgrading state from version %d to %d: %w", v, v+1, err)
+		}
+		next, err := c.typeAt(v + 1)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		state, err = asgotypes.Encode(next, upgraded)
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("upgrading state from version %d to %d: %w", v, v+1, err)
+		}
+	}
+	return state, nil
+}
+
+// typeAt returns the type of the state at `version`.
+func (c *Chain) typeAt(version int64) (tftypes.Type, error) {
+	if version == c.Schema.Version {
+		return c.Schema.ValueType(), nil
+	}
+	u, ok := c.Upgraders[version]
+	if !ok || u == nil {
+		return nil, fmt.Errorf("upgrading state from schema version %d is not supported", version)
+	}
+	return u.Type(), nil
+}
+
+// UpgradeResourceState implements the UpgradeResourceState RPC using
+// Upgrade. Any error is returned as a diagnostic on the response.
+func (c *Chain) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) *tfprotov5.UpgradeResourceStateResponse {
+	resp := &tfprotov5.UpgradeResourceStateResponse{}
+	state, err := c.Upgrade(ctx, req.Version, req.RawState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error upgrading state", err))
+		return resp
+	}
+	dv, err := tfprotov5.NewDynamicValue(c.Schema.ValueType(), state)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Error("Error upgrading state", err))
+		return resp
+	}
+	resp.UpgradedState = &dv
+	return resp
+}
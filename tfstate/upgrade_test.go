@@ -0,0 +1,181 @@
+package tfstate
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type testStateV0 struct {
+	ID   string `tfsdk:"id"`
+	Name string `tfsdk:"name"`
+	Size string `tfsdk:"size"`
+}
+
+type testStateV1 struct {
+	ID          string `tfsdk:"id"`
+	DisplayName string `tfsdk:"display_name"`
+	Size        string `tfsdk:"size"`
+}
+
+type testStateV2 struct {
+	ID          string `tfsdk:"id"`
+	DisplayName string `tfsdk:"display_name"`
+	Size        int    `tfsdk:"size"`
+}
+
+var (
+	testTypeV0 = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":   tftypes.String,
+		"name": tftypes.String,
+		"size": tftypes.String,
+	}}
+	testTypeV1 = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":           tftypes.String,
+		"display_name": tftypes.String,
+		"size":         tftypes.String,
+	}}
+	testSchemaV2 = &tfprotov5.Schema{
+		Version: 2,
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{Name: "id", Type: tftypes.String, Computed: true},
+				{Name: "display_name", Type: tftypes.String, Required: true},
+				{Name: "size", Type: tftypes.Number, Required: true},
+			},
+		},
+	}
+)
+
+func testChain() *Chain {
+	return &Chain{
+		Schema: testSchemaV2,
+		Upgraders: map[int64]Upgrader{
+			0: UpgradeFunc(testTypeV0, func(ctx context.Context, prior testStateV0) (testStateV1, error) {
+				return testStateV1{ID: prior.ID, DisplayName: prior.Name, Size: prior.Size}, nil
+			}),
+			1: UpgradeFunc(testTypeV1, func(ctx context.Context, prior testStateV1) (testStateV2, error) {
+				size, err := strconv.Atoi(prior.Size)
+				if err != nil {
+					return testStateV2{}, errors.New("size is not a number")
+				}
+				return testStateV2{ID: prior.ID, DisplayName: prior.DisplayName, Size: size}, nil
+			}),
+		},
+	}
+}
+
+func TestChainUpgradeResourceState(t *testing.T) {
+	type testCase struct {
+		version       int64
+		json          string
+		expected      *tfprotov5.DynamicValue
+		expectedDiags []*tfprotov5.Diagnostic
+	}
+	expected, err := tfprotov5.NewDynamicValue(testSchemaV2.ValueType(), tftypes.NewValue(testSchemaV2.ValueType(), map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, "abc"),
+		"display_name": tftypes.NewValue(tftypes.String, "foo"),
+		"size":         tftypes.NewValue(tftypes.Number, 10),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]testCase{
+		"v0": {
+			version:  0,
+			json:     `{"id": "abc", "name": "foo", "size": "10", "removed": true}`,
+			expected: &expected,
+		},
+		"v1": {
+			version:  1,
+			json:     `{"id": "abc", "display_name": "foo", "size": "10"}`,
+			expected: &expected,
+		},
+		"current": {
+			version:  2,
+			json:     `{"id": "abc", "display_name": "foo", "size": 10}`,
+			expected: &expected,
+		},
+		"upgrade-error": {
+			version: 0,
+			json:    `{"id": "abc", "name": "foo", "size": "ten"}`,
+			expectedDiags: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Error upgrading state",
+					Detail:   "upgrading state from version 1 to 2: size is not a number",
+				},
+			},
+		},
+		"newer": {
+			version: 3,
+			json:    `{}`,
+			expectedDiags: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Error upgrading state",
+					Detail:   "state was written with schema version 3, which is newer than the current version 2",
+				},
+			},
+		},
+	}
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			resp := testChain().UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+				Version:  test.version,
+				RawState: &tfprotov5.RawState{JSON: []byte(test.json)},
+			})
+			if diff := cmp.Diff(test.expectedDiags, resp.Diagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics diff (-wanted, +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.expected, resp.UpgradedState); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestChainUnsupportedVersion(t *testing.T) {
+	c := testChain()
+	delete(c.Upgraders, 0)
+	_, err := c.Upgrade(context.Background(), 0, &tfprotov5.RawState{JSON: []byte(`{}`)})
+	if err == nil || err.Error() != "upgrading state from schema version 0 is not supported" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestChainUpgradeFlatmapPrimitive(t *testing.T) {
+	c := testChain()
+	c.Upgraders[0] = UpgradeFunc(tftypes.DynamicPseudoType, func(ctx context.Context, prior asgotypes.GoPrimitive) (testStateV1, error) {
+		attrs := prior.Value.(map[string]interface{})
+		labels := attrs["labels"].(map[string]string)
+		return testStateV1{ID: attrs["id"].(string), DisplayName: labels["name"], Size: attrs["size"].(string)}, nil
+	})
+	raw := &tfprotov5.RawState{Flatmap: map[string]string{
+		"id":          "abc",
+		"labels.%":    "1",
+		"labels.name": "example",
+		"size":        "3",
+	}}
+
+	got, err := c.Upgrade(context.Background(), 0, raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := tftypes.NewValue(testSchemaV2.ValueType(), map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, "abc"),
+		"display_name": tftypes.NewValue(tftypes.String, "example"),
+		"size":         tftypes.NewValue(tftypes.Number, big.NewFloat(3)),
+	})
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,66 @@
+// Package upgradetest provides helpers for testing tfstate.Chains against
+// fixtures of state written by earlier versions of a provider.
+package upgradetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfstate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// Fixture is a state written with an earlier schema version, and the state
+// it's expected to be upgraded to.
+type Fixture struct {
+	// Version is the schema version the state was written with.
+	Version int64
+
+	// JSON is the state as Terraform stores it, usually copied from the
+	// "attributes" of the resource in a state file.
+	JSON string
+
+	// Expected is the expected upgraded state. It's encoded with
+	// asgotypes.Encode using the current schema, so it may be a tagged
+	// struct, a GoPrimitive, or a tftypes.Value.
+	Expected interface{}
+
+	// ExpectedErr, if set, is the error the upgrade is expected to fail
+	// with, instead of producing Expected.
+	ExpectedErr string
+}
+
+// Run runs each of `fixtures` through `chain` as a subtest, reporting any
+// differences from the expected state.
+func Run(t *testing.T, chain *tfstate.Chain, fixtures map[string]Fixture) {
+	t.Helper()
+	for name, fixture := range fixtures {
+		name, fixture := name, fixture
+		t.Run(name, func(t *testing.T) {
+			got, err := chain.Upgrade(context.Background(), fixture.Version, &tfprotov5.RawState{
+				JSON: []byte(fixture.JSON),
+			})
+			if fixture.ExpectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", fixture.ExpectedErr)
+				}
+				if err.Error() != fixture.ExpectedErr {
+					t.Fatalf("expected error %q, got %q", fixture.ExpectedErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			expected, err := asgotypes.Encode(chain.Schema.ValueType(), fixture.Expected)
+			if err != nil {
+				t.Fatalf("error encoding expected state: %s", err)
+			}
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
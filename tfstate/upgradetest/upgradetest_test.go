@@ -0,0 +1,59 @@
+package upgradetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfstate"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type stateV0 struct {
+	ID   string `tfsdk:"id"`
+	Name string `tfsdk:"name"`
+}
+
+type stateV1 struct {
+	ID    string `tfsdk:"id"`
+	Title string `tfsdk:"title"`
+}
+
+func TestRun(t *testing.T) {
+	chain := &tfstate.Chain{
+		Schema: &tfprotov5.Schema{
+			Version: 1,
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{Name: "id", Type: tftypes.String, Computed: true},
+					{Name: "title", Type: tftypes.String, Required: true},
+				},
+			},
+		},
+		Upgraders: map[int64]tfstate.Upgrader{
+			0: tfstate.UpgradeFunc(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"id":   tftypes.String,
+				"name": tftypes.String,
+			}}, func(ctx context.Context, prior stateV0) (stateV1, error) {
+				return stateV1{ID: prior.ID, Title: prior.Name}, nil
+			}),
+		},
+	}
+	Run(t, chain, map[string]Fixture{
+		"v0": {
+			Version:  0,
+			JSON:     `{"id": "abc", "name": "foo"}`,
+			Expected: stateV1{ID: "abc", Title: "foo"},
+		},
+		"v1": {
+			Version:  1,
+			JSON:     `{"id": "abc", "title": "foo"}`,
+			Expected: stateV1{ID: "abc", Title: "foo"},
+		},
+		"invalid": {
+			Version:     0,
+			JSON:        `{"id": 1`,
+			ExpectedErr: "error reading object attribute key token: unexpected end of JSON input",
+		},
+	})
+}
@@ -0,0 +1,181 @@
+package tfstate
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// WriteOnlyPaths returns the paths of the attributes marked as write-only in
+// `schema`, including those in nested blocks. Paths into nested blocks don't
+// include element keys.
+func WriteOnlyPaths(schema *tfprotov5.Schema) []*tftypes.AttributePath {
+	if schema == nil {
+		return nil
+	}
+	return writeOnlyBlockPaths(nil, tftypes.NewAttributePath(), schema.Block)
+}
+
+func writeOnlyBlockPaths(paths []*tftypes.AttributePath, path *tftypes.AttributePath, block *tfprotov5.SchemaBlock) []*tftypes.AttributePath {
+	if block == nil {
+		return paths
+	}
+	for _, attr := range block.Attributes {
+		if attr.WriteOnly {
+			paths = append(paths, path.WithAttributeName(attr.Name))
+		}
+	}
+	for _, nested := range block.BlockTypes {
+		paths = writeOnlyBlockPaths(paths, path.WithAttributeName(nested.TypeName), nested.Block)
+	}
+	return paths
+}
+
+// NullWriteOnly returns `val`, which is described by `schema`, with every
+// write-only attribute set to null. Terraform never persists write-only
+// values, so they must be null in planned and new state.
+func NullWriteOnly(schema *tfprotov5.Schema, val tftypes.Value) (tftypes.Value, error) {
+	if schema == nil {
+		return val, nil
+	}
+	return filterBlock(tftypes.NewAttributePath(), schema.Block, val, isWriteOnly)
+}
+
+// WriteOnly returns `config`, which is described by `schema`, with every
+// attribute that isn't write-only set to null, leaving only the write-only
+// values, which are never present in state.
+func WriteOnly(schema *tfprotov5.Schema, config tftypes.Value) (tftypes.Value, error) {
+	if schema == nil {
+		return config, nil
+	}
+	return filterBlock(tftypes.NewAttributePath(), schema.Block, config, func(attr *tfprotov5.SchemaAttribute) bool {
+		return !attr.WriteOnly
+	})
+}
+
+func isWriteOnly(attr *tfprotov5.SchemaAttribute) bool {
+	return attr.WriteOnly
+}
+
+// ValidateWriteOnly returns an error if any write-only attribute of `val`,
+// which is described by `schema`, isn't null.
+func ValidateWriteOnly(schema *tfprotov5.Schema, val tftypes.Value) error {
+	if schema == nil {
+		return nil
+	}
+	return tftypes.Walk(val, func(path *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+		if v.IsNull() {
+			return false, nil
+		}
+		attr := attributeAt(schema.Block, path)
+		if attr != nil && attr.WriteOnly {
+			return false, path.NewErrorf("write-only attribute must be null in state")
+		}
+		return true, nil
+	})
+}
+
+// attributeAt returns the attribute of `block` at `path`, ignoring element
+// keys, or nil if `path` isn't an attribute.
+func attributeAt(block *tfprotov5.SchemaBlock, path *tftypes.AttributePath) *tfprotov5.SchemaAttribute {
+	steps := path.Steps()
+	for i, step := range steps {
+		name, ok := step.(tftypes.AttributeName)
+		if !ok {
+			continue
+		}
+		if block == nil {
+			return nil
+		}
+		for _, attr := range block.Attributes {
+			if attr.Name == string(name) {
+				if i == len(steps)-1 {
+					return attr
+				}
+				return nil
+			}
+		}
+		var next *tfprotov5.SchemaBlock
+		for _, nested := range block.BlockTypes {
+			if nested.TypeName == string(name) {
+				next = nested.Block
+			}
+		}
+		block = next
+	}
+	return nil
+}
+
+// filterBlock nulls the attributes of `val` for which `null` returns true,
+// recursing into nested blocks.
+func filterBlock(path *tftypes.AttributePath, block *tfprotov5.SchemaBlock, val tftypes.Value, null func(*tfprotov5.SchemaAttribute) bool) (tftypes.Value, error) {
+	if block == nil || val.IsNull() || !val.IsKnown() {
+		return val, nil
+	}
+	var attrs map[string]tftypes.Value
+	if err := val.As(&attrs); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	filtered := make(map[string]tftypes.Value, len(attrs))
+	for name, attr := range attrs {
+		filtered[name] = attr
+	}
+	for _, attr := range block.Attributes {
+		if _, ok := attrs[attr.Name]; ok && null(attr) {
+			filtered[attr.Name] = tftypes.NewValue(attr.ValueType(), nil)
+		}
+	}
+	for _, nested := range block.BlockTypes {
+		v, ok := attrs[nested.TypeName]
+		if !ok {
+			continue
+		}
+		f, err := filterNestedBlock(path.WithAttributeName(nested.TypeName), nested, v, null)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		filtered[nested.TypeName] = f
+	}
+	return tftypes.NewValue(val.Type(), filtered), nil
+}
+
+func filterNestedBlock(path *tftypes.AttributePath, nested *tfprotov5.SchemaNestedBlock, val tftypes.Value, null func(*tfprotov5.SchemaAttribute) bool) (tftypes.Value, error) {
+	if val.IsNull() || !val.IsKnown() {
+		return val, nil
+	}
+	switch nested.Nesting {
+	case tfprotov5.SchemaNestedBlockNestingModeSingle, tfprotov5.SchemaNestedBlockNestingModeGroup:
+		return filterBlock(path, nested.Block, val, null)
+	case tfprotov5.SchemaNestedBlockNestingModeMap:
+		var elems map[string]tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		filtered := make(map[string]tftypes.Value, len(elems))
+		for key, elem := range elems {
+			f, err := filterBlock(path.WithElementKeyString(key), nested.Block, elem, null)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			filtered[key] = f
+		}
+		return tftypes.NewValue(val.Type(), filtered), nil
+	default:
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		filtered := make([]tftypes.Value, 0, len(elems))
+		for i, elem := range elems {
+			elemPath := path.WithElementKeyInt(i)
+			if nested.Nesting == tfprotov5.SchemaNestedBlockNestingModeSet {
+				elemPath = path.WithElementKeyValue(elem)
+			}
+			f, err := filterBlock(elemPath, nested.Block, elem, null)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			filtered = append(filtered, f)
+		}
+		return tftypes.NewValue(val.Type(), filtered), nil
+	}
+}
@@ -0,0 +1,134 @@
+package tfstate
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var writeOnlySchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Computed: true},
+			{Name: "password", Type: tftypes.String, Optional: true, WriteOnly: true},
+		},
+		BlockTypes: []*tfprotov5.SchemaNestedBlock{
+			{
+				TypeName: "user",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "name", Type: tftypes.String, Required: true},
+						{Name: "token", Type: tftypes.String, Optional: true, WriteOnly: true},
+					},
+				},
+			},
+		},
+	},
+}
+
+var (
+	writeOnlyType     = writeOnlySchema.ValueType()
+	writeOnlyUserType = writeOnlySchema.Block.BlockTypes[0].Block.ValueType()
+)
+
+func writeOnlyValue(id, password interface{}, users ...tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(writeOnlyType, map[string]tftypes.Value{
+		"id":       tftypes.NewValue(tftypes.String, id),
+		"password": tftypes.NewValue(tftypes.String, password),
+		"user":     tftypes.NewValue(tftypes.List{ElementType: writeOnlyUserType}, users),
+	})
+}
+
+func writeOnlyUser(name, token interface{}) tftypes.Value {
+	return tftypes.NewValue(writeOnlyUserType, map[string]tftypes.Value{
+		"name":  tftypes.NewValue(tftypes.String, name),
+		"token": tftypes.NewValue(tftypes.String, token),
+	})
+}
+
+func TestWriteOnlyPaths(t *testing.T) {
+	expected := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("password"),
+		tftypes.NewAttributePath().WithAttributeName("user").WithAttributeName("token"),
+	}
+	if diff := cmp.Diff(expected, WriteOnlyPaths(writeOnlySchema)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestNullWriteOnly(t *testing.T) {
+	val := writeOnlyValue("i-1", "hunter2", writeOnlyUser("alice", "t0k3n"))
+	got, err := NullWriteOnly(writeOnlySchema, val)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := writeOnlyValue("i-1", nil, writeOnlyUser("alice", nil))
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if err := ValidateWriteOnly(writeOnlySchema, got); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestWriteOnly(t *testing.T) {
+	config := writeOnlyValue(nil, "hunter2", writeOnlyUser("alice", "t0k3n"))
+	got, err := WriteOnly(writeOnlySchema, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := writeOnlyValue(nil, "hunter2", writeOnlyUser(nil, "t0k3n"))
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestValidateWriteOnly(t *testing.T) {
+	type testCase struct {
+		val tftypes.Value
+		err string
+	}
+	cases := map[string]testCase{
+		"null": {
+			val: writeOnlyValue("i-1", nil, writeOnlyUser("alice", nil)),
+		},
+		"top-level": {
+			val: writeOnlyValue("i-1", "hunter2"),
+			err: `AttributeName("password"): write-only attribute must be null in state`,
+		},
+		"nested": {
+			val: writeOnlyValue("i-1", nil, writeOnlyUser("alice", "t0k3n")),
+			err: `AttributeName("user").ElementKeyInt(0).AttributeName("token"): write-only attribute must be null in state`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			err := ValidateWriteOnly(writeOnlySchema, tc.val)
+			if tc.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestBuildWriteOnly(t *testing.T) {
+	planned := writeOnlyValue(tftypes.UnknownValue, nil, writeOnlyUser("alice", nil))
+	got, err := Build(writeOnlySchema, planned, writeOnlyValue("i-1", "hunter2", writeOnlyUser("alice", "t0k3n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := writeOnlyValue("i-1", nil, writeOnlyUser("alice", nil))
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,431 @@
+// Package tfstructpb converts between tftypes.Values and the protobuf
+// well-known types google.protobuf.Value and google.protobuf.Struct, for
+// providers wrapping gRPC APIs whose payloads are free-form Structs.
+//
+// google.protobuf.Values are JSON-like: they hold null, strings, float64
+// numbers, bools, lists, and Structs, which are objects. Terraform's
+// numbers are arbitrary precision, so a Converter can be told to refuse
+// numbers a float64 can't hold exactly, and how to handle the NaN and
+// infinite numbers a float64 can hold but Terraform's numbers can't.
+// Unknown values have no equivalent and are always an error.
+package tfstructpb
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Converter converts between tftypes.Values and google.protobuf.Values.
+// The zero value is ready to use, and rounds numbers to the nearest
+// float64.
+//
+// Any error returned by a Converter that relates to a specific part of a
+// value will be a tftypes.AttributePathError, identifying the path the
+// error occurred at.
+type Converter struct {
+	// ExactNumbers makes converting a number that a float64 can't hold
+	// exactly into a google.protobuf.Value an error. By default, it's
+	// rounded to the nearest float64.
+	ExactNumbers bool
+
+	// NonFinite controls how NaN and infinite numbers in
+	// google.protobuf.Values are converted into tftypes.Values, following
+	// the same rules as asgotypes.Encoder uses for floats. By default,
+	// they're an error.
+	NonFinite asgotypes.NonFinitePolicy
+}
+
+var (
+	tfString  tftypes.Type = tftypes.String
+	tfNumber  tftypes.Type = tftypes.Number
+	tfBool    tftypes.Type = tftypes.Bool
+	tfDynamic tftypes.Type = tftypes.DynamicPseudoType
+)
+
+// ToValue converts `val` into a google.protobuf.Value using a Converter
+// with the default settings.
+func ToValue(val tftypes.Value) (*structpb.Value, error) {
+	return Converter{}.ToValue(val)
+}
+
+// ToStruct converts the object or map `val` into a google.protobuf.Struct
+// using a Converter with the default settings.
+func ToStruct(val tftypes.Value) (*structpb.Struct, error) {
+	return Converter{}.ToStruct(val)
+}
+
+// FromValue converts `v` into a tftypes.Value of type `typ` using a
+// Converter with the default settings.
+func FromValue(typ tftypes.Type, v *structpb.Value) (tftypes.Value, error) {
+	return Converter{}.FromValue(typ, v)
+}
+
+// FromStruct converts `s` into a tftypes.Value of the object or map type
+// `typ` using a Converter with the default settings.
+func FromStruct(typ tftypes.Type, s *structpb.Struct) (tftypes.Value, error) {
+	return Converter{}.FromStruct(typ, s)
+}
+
+// ToValue converts `val` into a google.protobuf.Value. Null values are
+// converted to null, lists, sets, and tuples to lists, and objects and maps
+// to Structs. `val` must be wholly known.
+func (c Converter) ToValue(val tftypes.Value) (*structpb.Value, error) {
+	return c.toValue(tftypes.NewAttributePath(), val)
+}
+
+// ToStruct converts the object or map `val` into a
+// google.protobuf.Struct. A null `val` is converted to a nil Struct.
+func (c Converter) ToStruct(val tftypes.Value) (*structpb.Struct, error) {
+	if val.Type() == nil || (!val.Type().Is(tftypes.Object{}) && !val.Type().Is(tftypes.Map{})) {
+		return nil, fmt.Errorf("cannot convert %s to a Struct, an object or map is required", val.Type())
+	}
+	v, err := c.ToValue(val)
+	if err != nil {
+		return nil, err
+	}
+	return v.GetStructValue(), nil
+}
+
+func (c Converter) toValue(path *tftypes.AttributePath, val tftypes.Value) (*structpb.Value, error) {
+	if !val.IsKnown() {
+		return nil, path.NewErrorf("cannot convert unknown value to a google.protobuf.Value")
+	}
+	if val.IsNull() {
+		return structpb.NewNullValue(), nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tfString):
+		var s string
+		if err := val.As(&s); err != nil {
+			return nil, path.NewError(err)
+		}
+		return structpb.NewStringValue(s), nil
+	case typ.Is(tfNumber):
+		var f big.Float
+		if err := val.As(&f); err != nil {
+			return nil, path.NewError(err)
+		}
+		return c.number(path, &f)
+	case typ.Is(tfBool):
+		var b bool
+		if err := val.As(&b); err != nil {
+			return nil, path.NewError(err)
+		}
+		return structpb.NewBoolValue(b), nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		isSet := typ.Is(tftypes.Set{})
+		list := &structpb.ListValue{Values: make([]*structpb.Value, 0, len(elems))}
+		for i, elem := range elems {
+			elemPath := path.WithElementKeyInt(i)
+			if isSet {
+				elemPath = path.WithElementKeyValue(elem)
+			}
+			v, err := c.toValue(elemPath, elem)
+			if err != nil {
+				return nil, err
+			}
+			list.Values = append(list.Values, v)
+		}
+		return structpb.NewListValue(list), nil
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		var elems map[string]tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		isObject := typ.Is(tftypes.Object{})
+		s := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(elems))}
+		for _, k := range sorted.Keys(elems) {
+			elemPath := path.WithElementKeyString(k)
+			if isObject {
+				elemPath = path.WithAttributeName(k)
+			}
+			v, err := c.toValue(elemPath, elems[k])
+			if err != nil {
+				return nil, err
+			}
+			s.Fields[k] = v
+		}
+		return structpb.NewStructValue(s), nil
+	}
+	return nil, path.NewErrorf("cannot convert value of type %s to a google.protobuf.Value", typ)
+}
+
+// number converts `f` into a google.protobuf.Value.
+func (c Converter) number(path *tftypes.AttributePath, f *big.Float) (*structpb.Value, error) {
+	n, acc := f.Float64()
+	if math.IsInf(n, 0) && !f.IsInf() {
+		return nil, path.NewErrorf("cannot convert %s to a google.protobuf.Value, it's out of range of a float64", f.Text('g', -1))
+	}
+	if c.ExactNumbers && acc != big.Exact {
+		return nil, path.NewErrorf("cannot convert %s to a google.protobuf.Value exactly", f.Text('g', -1))
+	}
+	return structpb.NewNumberValue(n), nil
+}
+
+// FromValue converts `v` into a tftypes.Value of type `typ`. Lists are
+// converted to lists, sets, or tuples, and Structs to objects or maps, as
+// `typ` requires, and a nil `v` is converted to null. If `typ` is
+// tftypes.DynamicPseudoType, the type is inferred like it would be for
+// JSON: lists become tuples and Structs objects.
+func (c Converter) FromValue(typ tftypes.Type, v *structpb.Value) (tftypes.Value, error) {
+	return c.fromValue(tftypes.NewAttributePath(), typ, v)
+}
+
+// FromStruct converts `s` into a tftypes.Value of the object or map type
+// `typ`. A nil `s` is converted to null.
+func (c Converter) FromStruct(typ tftypes.Type, s *structpb.Struct) (tftypes.Value, error) {
+	if !typ.Is(tftypes.Object{}) && !typ.Is(tftypes.Map{}) && !typ.Is(tfDynamic) {
+		return tftypes.Value{}, fmt.Errorf("cannot convert a Struct to %s, an object or map type is required", typ)
+	}
+	if s == nil {
+		return c.FromValue(typ, nil)
+	}
+	return c.FromValue(typ, structpb.NewStructValue(s))
+}
+
+func (c Converter) fromValue(path *tftypes.AttributePath, typ tftypes.Type, v *structpb.Value) (tftypes.Value, error) {
+	switch kind := v.GetKind().(type) {
+	case nil, *structpb.Value_NullValue:
+		return tftypes.NewValue(typ, nil), nil
+	case *structpb.Value_StringValue:
+		if typ.Is(tfString) || typ.Is(tfDynamic) {
+			return tftypes.NewValue(tftypes.String, kind.StringValue), nil
+		}
+	case *structpb.Value_NumberValue:
+		n := kind.NumberValue
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return c.nonFinite(path, typ, n)
+		}
+		if typ.Is(tfNumber) || typ.Is(tfDynamic) {
+			return tftypes.NewValue(tftypes.Number, new(big.Float).SetFloat64(n)), nil
+		}
+	case *structpb.Value_BoolValue:
+		if typ.Is(tfBool) || typ.Is(tfDynamic) {
+			return tftypes.NewValue(tftypes.Bool, kind.BoolValue), nil
+		}
+	case *structpb.Value_ListValue:
+		return c.fromList(path, typ, kind.ListValue.GetValues())
+	case *structpb.Value_StructValue:
+		return c.fromStruct(path, typ, kind.StructValue.GetFields())
+	}
+	return tftypes.Value{}, path.NewErrorf("cannot convert %s to %s", describe(v), typ)
+}
+
+// nonFinite converts the NaN or infinite `n` into a value of `typ`
+// according to the NonFinite policy.
+func (c Converter) nonFinite(path *tftypes.AttributePath, typ tftypes.Type, n float64) (tftypes.Value, error) {
+	switch c.NonFinite {
+	case asgotypes.NonFiniteNull:
+		if typ.Is(tfDynamic) {
+			typ = tftypes.Number
+		}
+		return tftypes.NewValue(typ, nil), nil
+	case asgotypes.NonFiniteString:
+		if typ.Is(tfString) || typ.Is(tfDynamic) {
+			s := "NaN"
+			if math.IsInf(n, 1) {
+				s = "+Inf"
+			} else if math.IsInf(n, -1) {
+				s = "-Inf"
+			}
+			return tftypes.NewValue(tftypes.String, s), nil
+		}
+	}
+	return tftypes.Value{}, path.NewErrorf("cannot convert %v to %s", n, typ)
+}
+
+func (c Converter) fromList(path *tftypes.AttributePath, typ tftypes.Type, vals []*structpb.Value) (tftypes.Value, error) {
+	var elemTypes []tftypes.Type
+	switch t := typ.(type) {
+	case tftypes.List:
+		elemTypes = repeat(t.ElementType, len(vals))
+	case tftypes.Set:
+		elemTypes = repeat(t.ElementType, len(vals))
+	case tftypes.Tuple:
+		if len(t.ElementTypes) != len(vals) {
+			return tftypes.Value{}, path.NewErrorf("cannot convert a list of %d elements to %s", len(vals), typ)
+		}
+		elemTypes = t.ElementTypes
+	default:
+		if !typ.Is(tfDynamic) {
+			return tftypes.Value{}, path.NewErrorf("cannot convert a list to %s", typ)
+		}
+		elemTypes = repeat(tfDynamic, len(vals))
+	}
+	elems := make([]tftypes.Value, 0, len(vals))
+	for i, v := range vals {
+		elem, err := c.fromValue(path.WithElementKeyInt(i), elemTypes[i], v)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, elem)
+	}
+	if typ.Is(tfDynamic) {
+		types := make([]tftypes.Type, 0, len(elems))
+		for _, elem := range elems {
+			types = append(types, elem.Type())
+		}
+		typ = tftypes.Tuple{ElementTypes: types}
+	}
+	if err := tftypes.ValidateValue(typ, elems); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+func (c Converter) fromStruct(path *tftypes.AttributePath, typ tftypes.Type, fields map[string]*structpb.Value) (tftypes.Value, error) {
+	elems := make(map[string]tftypes.Value, len(fields))
+	switch t := typ.(type) {
+	case tftypes.Object:
+		for k := range fields {
+			if _, ok := t.AttributeTypes[k]; !ok {
+				return tftypes.Value{}, path.WithAttributeName(k).NewErrorf("unexpected attribute %q", k)
+			}
+		}
+		for k, attrType := range t.AttributeTypes {
+			// Fields missing from the Struct are converted to null.
+			elem, err := c.fromValue(path.WithAttributeName(k), attrType, fields[k])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = elem
+		}
+	case tftypes.Map:
+		for k, v := range fields {
+			elem, err := c.fromValue(path.WithElementKeyString(k), t.ElementType, v)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = elem
+		}
+	default:
+		if !typ.Is(tfDynamic) {
+			return tftypes.Value{}, path.NewErrorf("cannot convert a Struct to %s", typ)
+		}
+		types := make(map[string]tftypes.Type, len(fields))
+		for k, v := range fields {
+			elem, err := c.fromValue(path.WithAttributeName(k), tfDynamic, v)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = elem
+			types[k] = elem.Type()
+		}
+		typ = tftypes.Object{AttributeTypes: types}
+	}
+	if err := tftypes.ValidateValue(typ, elems); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+// PrimitiveToValue converts the Value of `p` into a google.protobuf.Value,
+// for values that have been decoded into a GoPrimitive, or built to look
+// like one.
+func (c Converter) PrimitiveToValue(p asgotypes.GoPrimitive) (*structpb.Value, error) {
+	return c.goToValue(tftypes.NewAttributePath(), reflect.ValueOf(p.Value))
+}
+
+func (c Converter) goToValue(path *tftypes.AttributePath, v reflect.Value) (*structpb.Value, error) {
+	if !v.IsValid() {
+		return structpb.NewNullValue(), nil
+	}
+	switch x := v.Interface().(type) {
+	case *big.Float:
+		if x == nil {
+			return structpb.NewNullValue(), nil
+		}
+		return c.number(path, x)
+	case string:
+		return structpb.NewStringValue(x), nil
+	case bool:
+		return structpb.NewBoolValue(x), nil
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return structpb.NewNullValue(), nil
+		}
+		return c.goToValue(path, v.Elem())
+	case reflect.Slice, reflect.Array:
+		list := &structpb.ListValue{Values: make([]*structpb.Value, 0, v.Len())}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := c.goToValue(path.WithElementKeyInt(i), v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list.Values = append(list.Values, elem)
+		}
+		return structpb.NewListValue(list), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		s := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(keys))}
+		for _, k := range keys {
+			elem, err := c.goToValue(path.WithElementKeyString(k.String()), v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+			s.Fields[k.String()] = elem
+		}
+		return structpb.NewStructValue(s), nil
+	}
+	return nil, path.NewErrorf("cannot convert %s to a google.protobuf.Value", v.Type())
+}
+
+// ValueToPrimitive converts `v` into a GoPrimitive, with null as nil,
+// strings as strings, numbers as *big.Floats, bools as bools, lists as
+// []interface{}, and Structs as map[string]interface{}, like a GoPrimitive
+// with asgotypes.CollectionsLenient.
+func (c Converter) ValueToPrimitive(v *structpb.Value) (asgotypes.GoPrimitive, error) {
+	val, err := c.FromValue(tftypes.DynamicPseudoType, v)
+	if err != nil {
+		return asgotypes.GoPrimitive{}, err
+	}
+	p := asgotypes.GoPrimitive{Collections: asgotypes.CollectionsLenient}
+	if err := p.FromTerraform5Value(val); err != nil {
+		return asgotypes.GoPrimitive{}, err
+	}
+	return p, nil
+}
+
+// describe returns the kind of `v`, for errors.
+func describe(v *structpb.Value) string {
+	switch v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		return "a string"
+	case *structpb.Value_NumberValue:
+		return "a number"
+	case *structpb.Value_BoolValue:
+		return "a bool"
+	case *structpb.Value_ListValue:
+		return "a list"
+	case *structpb.Value_StructValue:
+		return "a Struct"
+	}
+	return "null"
+}
+
+func repeat(typ tftypes.Type, n int) []tftypes.Type {
+	types := make([]tftypes.Type, n)
+	for i := range types {
+		types[i] = typ
+	}
+	return types
+}
@@ -0,0 +1,265 @@
+package tfstructpb
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var serverType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"name":  tftypes.String,
+	"port":  tftypes.Number,
+	"tags":  tftypes.Set{ElementType: tftypes.String},
+	"extra": tftypes.Map{ElementType: tftypes.Bool},
+}}
+
+func mustValue(t *testing.T, v interface{}) *structpb.Value {
+	t.Helper()
+	pv, err := structpb.NewValue(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return pv
+}
+
+func TestToValue(t *testing.T) {
+	type testCase struct {
+		converter   Converter
+		val         tftypes.Value
+		expected    interface{}
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"object": {
+			val: tftypes.NewValue(serverType, map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "web"),
+				"port": tftypes.NewValue(tftypes.Number, big.NewFloat(8080)),
+				"tags": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "a"),
+				}),
+				"extra": tftypes.NewValue(tftypes.Map{ElementType: tftypes.Bool}, nil),
+			}),
+			expected: map[string]interface{}{
+				"name":  "web",
+				"port":  8080.0,
+				"tags":  []interface{}{"a"},
+				"extra": nil,
+			},
+		},
+		"rounded": {
+			val:      tftypes.NewValue(tftypes.Number, new(big.Float).SetPrec(200).Quo(big.NewFloat(1), big.NewFloat(3))),
+			expected: 1.0 / 3,
+		},
+		"inexact": {
+			converter:   Converter{ExactNumbers: true},
+			val:         tftypes.NewValue(tftypes.Number, new(big.Float).SetUint64(1<<60+1)),
+			expectedErr: "cannot convert 1.152921504606846977e+18 to a google.protobuf.Value exactly",
+		},
+		"out-of-range": {
+			val:         tftypes.NewValue(tftypes.Number, new(big.Float).SetMantExp(big.NewFloat(1), 2000)),
+			expectedErr: "cannot convert 1.1481306952742545e+602 to a google.protobuf.Value, it's out of range of a float64",
+		},
+		"unknown": {
+			val: tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			}),
+			expectedErr: "ElementKeyInt(0): cannot convert unknown value to a google.protobuf.Value",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.converter.ToValue(tc.val)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(mustValue(t, tc.expected), got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFromValue(t *testing.T) {
+	type testCase struct {
+		converter   Converter
+		typ         tftypes.Type
+		val         *structpb.Value
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"object": {
+			typ: serverType,
+			val: structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+				"name": structpb.NewStringValue("web"),
+				"port": structpb.NewNumberValue(8080),
+				"tags": structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{
+					structpb.NewStringValue("a"),
+				}}),
+			}}),
+			expected: tftypes.NewValue(serverType, map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "web"),
+				"port": tftypes.NewValue(tftypes.Number, big.NewFloat(8080)),
+				"tags": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "a"),
+				}),
+				"extra": tftypes.NewValue(tftypes.Map{ElementType: tftypes.Bool}, nil),
+			}),
+		},
+		"dynamic": {
+			typ: tftypes.DynamicPseudoType,
+			val: structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{
+				structpb.NewBoolValue(true),
+				structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+					"n": structpb.NewNullValue(),
+				}}),
+			}}),
+			expected: tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{
+				tftypes.Bool,
+				tftypes.Object{AttributeTypes: map[string]tftypes.Type{"n": tftypes.DynamicPseudoType}},
+			}}, []tftypes.Value{
+				tftypes.NewValue(tftypes.Bool, true),
+				tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"n": tftypes.DynamicPseudoType}}, map[string]tftypes.Value{
+					"n": tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+				}),
+			}),
+		},
+		"nan-error": {
+			typ:         tftypes.Number,
+			val:         structpb.NewNumberValue(math.NaN()),
+			expectedErr: "cannot convert NaN to tftypes.Number",
+		},
+		"nan-null": {
+			converter: Converter{NonFinite: asgotypes.NonFiniteNull},
+			typ:       tftypes.Number,
+			val:       structpb.NewNumberValue(math.NaN()),
+			expected:  tftypes.NewValue(tftypes.Number, nil),
+		},
+		"inf-string": {
+			converter: Converter{NonFinite: asgotypes.NonFiniteString},
+			typ:       tftypes.DynamicPseudoType,
+			val:       structpb.NewNumberValue(math.Inf(-1)),
+			expected:  tftypes.NewValue(tftypes.String, "-Inf"),
+		},
+		"unexpected-attribute": {
+			typ: serverType,
+			val: structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+				"nmae": structpb.NewStringValue("web"),
+			}}),
+			expectedErr: "AttributeName(\"nmae\"): unexpected attribute \"nmae\"",
+		},
+		"wrong-type": {
+			typ: tftypes.List{ElementType: tftypes.Number},
+			val: structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{
+				structpb.NewStringValue("1"),
+			}}),
+			expectedErr: "ElementKeyInt(0): cannot convert a string to tftypes.Number",
+		},
+		"tuple-length": {
+			typ:         tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String}},
+			val:         structpb.NewListValue(&structpb.ListValue{}),
+			expectedErr: "cannot convert a list of 0 elements to tftypes.Tuple[tftypes.String]",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.converter.FromValue(tc.typ, tc.val)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestStruct(t *testing.T) {
+	val := tftypes.NewValue(tftypes.Map{ElementType: tftypes.Number}, map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+	})
+	s, err := ToStruct(val)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"a": 1.5}, s.AsMap()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	got, err := FromStruct(val.Type(), s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(val, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	expectedErr := "cannot convert tftypes.String to a Struct, an object or map is required"
+	if _, err := ToStruct(tftypes.NewValue(tftypes.String, "a")); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestPrimitive(t *testing.T) {
+	p := asgotypes.GoPrimitive{Value: map[string]interface{}{
+		"name":  "web",
+		"port":  big.NewFloat(8080),
+		"tags":  []string{"a", "b"},
+		"extra": nil,
+	}}
+	v, err := Converter{}.PrimitiveToValue(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]interface{}{
+		"name":  "web",
+		"port":  8080.0,
+		"tags":  []interface{}{"a", "b"},
+		"extra": nil,
+	}
+	if diff := cmp.Diff(expected, v.AsInterface()); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	got, err := Converter{}.ValueToPrimitive(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedPrimitive := asgotypes.GoPrimitive{
+		Value: map[string]interface{}{
+			"name":  "web",
+			"port":  big.NewFloat(8080),
+			"tags":  []interface{}{"a", "b"},
+			"extra": nil,
+		},
+		Type: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name":  tftypes.String,
+			"port":  tftypes.Number,
+			"tags":  tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.String}},
+			"extra": tftypes.DynamicPseudoType,
+		}},
+		Collections: asgotypes.CollectionsLenient,
+	}
+	if diff := cmp.Diff(expectedPrimitive, got, cmp.Comparer(func(a, b *big.Float) bool { return a.Cmp(b) == 0 })); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,327 @@
+// Package tftemplate renders strings from templates that use a
+// tftypes.Value as their data, for providers that compute string
+// attributes from others, like a display name or a URL built from a
+// resource's configuration.
+//
+// Templates are either Go text/template templates, like
+// `{{.name}}-{{index .tags "env"}}`, or simpler interpolations of paths in
+// tfpath's syntax, like `${name}-${tags["env"]}`. Either way, a template
+// that refers to a key the value doesn't have is an error, rather than
+// rendering as an empty string, and so is a template that refers to a
+// value at one of the Renderer's Sensitive paths, so secrets can't leak
+// into attributes that aren't sensitive.
+package tftemplate
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Renderer renders templates.
+type Renderer struct {
+	// Sensitive are the paths of values templates can't use. Paths made
+	// of only attribute names, like those of sensitive attributes in
+	// schemas, also match the attribute in every element of the
+	// collections they're nested in, like tfvalue.Printer's.
+	Sensitive []*tftypes.AttributePath
+
+	// Funcs are the functions available to Go templates, in addition to
+	// text/template's.
+	Funcs template.FuncMap
+}
+
+// Template renders the Go template `text` with a Renderer with the default
+// settings.
+func Template(text string, val tftypes.Value) (tftypes.Value, error) {
+	var r Renderer
+	return r.Template(text, val)
+}
+
+// Interpolate renders the interpolations in `text` with a Renderer with the
+// default settings.
+func Interpolate(text string, val tftypes.Value) (tftypes.Value, error) {
+	var r Renderer
+	return r.Interpolate(text, val)
+}
+
+// Template renders the Go template `text` with `val` as its data, and
+// returns the result as a string value.
+//
+// Objects and maps are the template's maps, lists, sets, and tuples its
+// slices, and nulls nil. Whole numbers are int64s, if they fit, and other
+// numbers float64s. Values at Sensitive paths are replaced with a value
+// that can't be printed, compared, or indexed, so templates that use them
+// fail to execute.
+//
+// Go templates can't report which parts of their data they use, so if
+// `val` isn't fully known, the result is unknown, once the template has
+// been parsed.
+func (r Renderer) Template(text string, val tftypes.Value) (tftypes.Value, error) {
+	tmpl, err := template.New("template").Option("missingkey=error").Funcs(r.Funcs).Parse(text)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if !val.IsFullyKnown() {
+		return tftypes.NewValue(tftypes.String, tftypes.UnknownValue), nil
+	}
+	data, err := r.data(tftypes.NewAttributePath(), val)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.NewValue(tftypes.String, b.String()), nil
+}
+
+// sensitiveValue replaces sensitive values in Go templates' data. Templates
+// can't print functions, compare them, or index them, and calling this one
+// returns an error.
+type sensitiveValue func() (interface{}, error)
+
+// data returns `val` as a Go template's data.
+func (r Renderer) data(path *tftypes.AttributePath, val tftypes.Value) (interface{}, error) {
+	if r.sensitive(path) {
+		return sensitiveValue(func() (interface{}, error) {
+			return nil, path.NewErrorf("value is sensitive")
+		}), nil
+	}
+	if val.IsNull() {
+		return nil, nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.String):
+		var s string
+		err := val.As(&s)
+		return s, err
+	case typ.Is(tftypes.Number):
+		f := new(big.Float)
+		if err := val.As(&f); err != nil {
+			return nil, err
+		}
+		if i, acc := f.Int64(); acc == big.Exact {
+			return i, nil
+		}
+		n, _ := f.Float64()
+		if math.IsInf(n, 0) {
+			return nil, path.NewErrorf("number %s is out of range", f.Text('g', 10))
+		}
+		return n, nil
+	case typ.Is(tftypes.Bool):
+		var b bool
+		err := val.As(&b)
+		return b, err
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		children := map[string]tftypes.Value{}
+		if err := val.As(&children); err != nil {
+			return nil, err
+		}
+		res := make(map[string]interface{}, len(children))
+		for k, child := range children {
+			next := path.WithAttributeName(k)
+			if typ.Is(tftypes.Map{}) {
+				next = path.WithElementKeyString(k)
+			}
+			v, err := r.data(next, child)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = v
+		}
+		return res, nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		children := []tftypes.Value{}
+		if err := val.As(&children); err != nil {
+			return nil, err
+		}
+		res := make([]interface{}, 0, len(children))
+		for i, child := range children {
+			next := path.WithElementKeyInt(i)
+			if typ.Is(tftypes.Set{}) {
+				next = path.WithElementKeyValue(child)
+			}
+			v, err := r.data(next, child)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, v)
+		}
+		return res, nil
+	}
+	return nil, path.NewErrorf("unsupported type %s", typ)
+}
+
+// Interpolate renders `text`, replacing each ${path} with the value at that
+// path in `val`, written in tfpath's syntax, except that map elements can
+// also be written like attributes, like `${tags.env}`. $${ is written as a
+// literal ${.
+//
+// The values interpolated must be strings, numbers, or bools, and can't be
+// null. If any of them are unknown, the result is unknown.
+func (r Renderer) Interpolate(text string, val tftypes.Value) (tftypes.Value, error) {
+	var b strings.Builder
+	known := true
+	for {
+		i := strings.Index(text, "${")
+		if i < 0 {
+			b.WriteString(text)
+			break
+		}
+		if i > 0 && text[i-1] == '$' {
+			b.WriteString(text[:i-1] + "${")
+			text = text[i+2:]
+			continue
+		}
+		b.WriteString(text[:i])
+		end := strings.IndexByte(text[i:], '}')
+		if end < 0 {
+			return tftypes.Value{}, fmt.Errorf("unterminated interpolation %q", text[i:])
+		}
+		s, ok, err := r.interpolate(text[i+2:i+end], val)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		known = known && ok
+		b.WriteString(s)
+		text = text[i+end+1:]
+	}
+	if !known {
+		return tftypes.NewValue(tftypes.String, tftypes.UnknownValue), nil
+	}
+	return tftypes.NewValue(tftypes.String, b.String()), nil
+}
+
+// interpolate returns the value at the path written as `expr` in `val` as a
+// string, and false if it's unknown.
+func (r Renderer) interpolate(expr string, val tftypes.Value) (string, bool, error) {
+	steps, err := tfpath.Parse(strings.TrimSpace(expr))
+	if err != nil {
+		return "", false, err
+	}
+	path := tftypes.NewAttributePath()
+	for _, step := range steps.Steps() {
+		if r.sensitive(path) {
+			return "", false, path.NewErrorf("value is sensitive")
+		}
+		if !val.IsKnown() {
+			return "", false, nil
+		}
+		if val.IsNull() {
+			return "", false, path.NewErrorf("value is null")
+		}
+		if path, val, err = child(path, val, step); err != nil {
+			return "", false, err
+		}
+	}
+	if r.sensitive(path) {
+		return "", false, path.NewErrorf("value is sensitive")
+	}
+	if !val.IsKnown() {
+		return "", false, nil
+	}
+	if val.IsNull() {
+		return "", false, path.NewErrorf("value is null")
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.String):
+		var s string
+		err := val.As(&s)
+		return s, true, err
+	case typ.Is(tftypes.Number):
+		f := new(big.Float)
+		err := val.As(&f)
+		return f.Text('f', -1), true, err
+	case typ.Is(tftypes.Bool):
+		var b bool
+		err := val.As(&b)
+		return fmt.Sprint(b), true, err
+	}
+	return "", false, path.NewErrorf("can't interpolate %s, only strings, numbers, and bools", typ)
+}
+
+// child returns the element of `val` at `step`, and its path.
+func child(path *tftypes.AttributePath, val tftypes.Value, step tftypes.AttributePathStep) (*tftypes.AttributePath, tftypes.Value, error) {
+	typ := val.Type()
+	switch s := step.(type) {
+	case tftypes.AttributeName:
+		switch {
+		case typ.Is(tftypes.Object{}):
+			children := map[string]tftypes.Value{}
+			if err := val.As(&children); err != nil {
+				return nil, tftypes.Value{}, err
+			}
+			next := path.WithAttributeName(string(s))
+			v, ok := children[string(s)]
+			if !ok {
+				return nil, tftypes.Value{}, next.NewErrorf("no such attribute")
+			}
+			return next, v, nil
+		case typ.Is(tftypes.Map{}):
+			return child(path, val, tftypes.ElementKeyString(s))
+		}
+	case tftypes.ElementKeyString:
+		if typ.Is(tftypes.Map{}) {
+			children := map[string]tftypes.Value{}
+			if err := val.As(&children); err != nil {
+				return nil, tftypes.Value{}, err
+			}
+			next := path.WithElementKeyString(string(s))
+			v, ok := children[string(s)]
+			if !ok {
+				return nil, tftypes.Value{}, next.NewErrorf("no such element")
+			}
+			return next, v, nil
+		}
+	case tftypes.ElementKeyInt:
+		if typ.Is(tftypes.List{}) || typ.Is(tftypes.Tuple{}) {
+			children := []tftypes.Value{}
+			if err := val.As(&children); err != nil {
+				return nil, tftypes.Value{}, err
+			}
+			next := path.WithElementKeyInt(int(s))
+			if s < 0 || int(s) >= len(children) {
+				return nil, tftypes.Value{}, next.NewErrorf("no such element")
+			}
+			return next, children[s], nil
+		}
+	}
+	return nil, tftypes.Value{}, path.NewErrorf("can't get %s of %s", tfpath.String(tftypes.NewAttributePathWithSteps([]tftypes.AttributePathStep{step})), typ)
+}
+
+// sensitive returns true if the value at `path` can't be used.
+func (r Renderer) sensitive(path *tftypes.AttributePath) bool {
+	for _, s := range r.Sensitive {
+		if s.Equal(path) || (onlyAttributeNames(s) && s.Equal(withoutElementKeys(path))) {
+			return true
+		}
+	}
+	return false
+}
+
+func onlyAttributeNames(path *tftypes.AttributePath) bool {
+	for _, step := range path.Steps() {
+		if _, ok := step.(tftypes.AttributeName); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func withoutElementKeys(path *tftypes.AttributePath) *tftypes.AttributePath {
+	var steps []tftypes.AttributePathStep
+	for _, step := range path.Steps() {
+		if _, ok := step.(tftypes.AttributeName); ok {
+			steps = append(steps, step)
+		}
+	}
+	return tftypes.NewAttributePathWithSteps(steps)
+}
@@ -0,0 +1,217 @@
+package tftemplate
+
+import (
+	"math/big"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	diskType   = tftypes.Object{AttributeTypes: map[string]tftypes.Type{"size": tftypes.Number}}
+	serverType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":     tftypes.String,
+		"port":     tftypes.Number,
+		"public":   tftypes.Bool,
+		"password": tftypes.String,
+		"tags":     tftypes.Map{ElementType: tftypes.String},
+		"disks":    tftypes.List{ElementType: diskType},
+		"region":   tftypes.String,
+	}}
+)
+
+func testServer(name, region interface{}) tftypes.Value {
+	return tftypes.NewValue(serverType, map[string]tftypes.Value{
+		"name":     tftypes.NewValue(tftypes.String, name),
+		"port":     tftypes.NewValue(tftypes.Number, big.NewFloat(8080)),
+		"public":   tftypes.NewValue(tftypes.Bool, true),
+		"password": tftypes.NewValue(tftypes.String, "hunter2"),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"env": tftypes.NewValue(tftypes.String, "prod"),
+		}),
+		"disks": tftypes.NewValue(tftypes.List{ElementType: diskType}, []tftypes.Value{
+			tftypes.NewValue(diskType, map[string]tftypes.Value{
+				"size": tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+			}),
+		}),
+		"region": tftypes.NewValue(tftypes.String, region),
+	})
+}
+
+var testRenderer = Renderer{
+	Sensitive: []*tftypes.AttributePath{tfpath.MustParse("password")},
+	Funcs: template.FuncMap{
+		"shout": func(s string) string { return s + "!" },
+	},
+}
+
+func TestTemplate(t *testing.T) {
+	type testCase struct {
+		text     string
+		val      tftypes.Value
+		expected tftypes.Value
+	}
+	cases := map[string]testCase{
+		"fields": {
+			text:     `{{.name}}:{{.port}} {{index .tags "env"}} {{(index .disks 0).size}} {{.public}}`,
+			val:      testServer("web", "us-east-1"),
+			expected: tftypes.NewValue(tftypes.String, "web:8080 prod 1.5 true"),
+		},
+		"funcs": {
+			text:     `{{shout .name}}{{range $k, $v := .tags}} {{$k}}={{$v}}{{end}}`,
+			val:      testServer("web", "us-east-1"),
+			expected: tftypes.NewValue(tftypes.String, "web! env=prod"),
+		},
+		"null": {
+			text:     `{{if .region}}{{.region}}{{else}}global{{end}}`,
+			val:      testServer("web", nil),
+			expected: tftypes.NewValue(tftypes.String, "global"),
+		},
+		"unknown": {
+			text:     `{{.name}}`,
+			val:      testServer(tftypes.UnknownValue, "us-east-1"),
+			expected: tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := testRenderer.Template(tc.text, tc.val)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	type testCase struct {
+		text        string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"missing-key": {
+			text:        `{{.nope}}`,
+			expectedErr: `template: template:1:2: executing "template" at <.nope>: map has no entry for key "nope"`,
+		},
+		"sensitive": {
+			text:        `{{.password}}`,
+			expectedErr: `template: template:1:2: executing "template" at <{{.password}}>: can't print {{.password}} of type tftemplate.sensitiveValue`,
+		},
+		"sensitive-comparison": {
+			text:        `{{if eq .password "hunter2"}}yes{{end}}`,
+			expectedErr: `template: template:1:5: executing "template" at <eq .password "hunter2">: error calling eq: incompatible types for comparison: tftemplate.sensitiveValue and string`,
+		},
+		"sensitive-call": {
+			text:        `{{call .password}}`,
+			expectedErr: `template: template:1:2: executing "template" at <call .password>: error calling call: AttributeName("password"): value is sensitive`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := testRenderer.Template(tc.text, testServer("web", "us-east-1"))
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	type testCase struct {
+		text     string
+		val      tftypes.Value
+		expected tftypes.Value
+	}
+	cases := map[string]testCase{
+		"paths": {
+			text:     `${name}:${port} ${tags["env"]}/${tags.env} ${disks[0].size} ${ public }`,
+			val:      testServer("web", "us-east-1"),
+			expected: tftypes.NewValue(tftypes.String, "web:8080 prod/prod 1.5 true"),
+		},
+		"escape": {
+			text:     `$${name} costs $5`,
+			val:      testServer("web", "us-east-1"),
+			expected: tftypes.NewValue(tftypes.String, "${name} costs $5"),
+		},
+		"unknown": {
+			text:     `${name}.${region}`,
+			val:      testServer(tftypes.UnknownValue, "us-east-1"),
+			expected: tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		},
+		"unknown-unused": {
+			text:     `${region}`,
+			val:      testServer(tftypes.UnknownValue, "us-east-1"),
+			expected: tftypes.NewValue(tftypes.String, "us-east-1"),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := testRenderer.Interpolate(tc.text, tc.val)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	type testCase struct {
+		text        string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"missing-attribute": {
+			text:        `${nope}`,
+			expectedErr: `AttributeName("nope"): no such attribute`,
+		},
+		"missing-element": {
+			text:        `${tags.team}`,
+			expectedErr: `AttributeName("tags").ElementKeyString("team"): no such element`,
+		},
+		"out-of-range": {
+			text:        `${disks[1].size}`,
+			expectedErr: `AttributeName("disks").ElementKeyInt(1): no such element`,
+		},
+		"sensitive": {
+			text:        `${password}`,
+			expectedErr: `AttributeName("password"): value is sensitive`,
+		},
+		"null": {
+			text:        `${region}`,
+			expectedErr: `AttributeName("region"): value is null`,
+		},
+		"collection": {
+			text:        `${tags}`,
+			expectedErr: `AttributeName("tags"): can't interpolate tftypes.Map[tftypes.String], only strings, numbers, and bools`,
+		},
+		"wrong-step": {
+			text:        `${name[0]}`,
+			expectedErr: `AttributeName("name"): can't get [0] of tftypes.String`,
+		},
+		"unterminated": {
+			text:        `a ${name`,
+			expectedErr: `unterminated interpolation "${name"`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := testRenderer.Interpolate(tc.text, testServer("web", nil))
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
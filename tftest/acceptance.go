@@ -0,0 +1,68 @@
+package tftest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ProtoV5ProviderFactories returns factories for the providers in
+// `providers`, keyed by provider name, in the shape of the
+// ProtoV5ProviderFactories field of terraform-plugin-testing's
+// resource.TestCase, for running acceptance tests against a
+// tfprotov5.ProviderServer built with this module:
+//
+//	resource.Test(t, resource.TestCase{
+//		ProtoV5ProviderFactories: tftest.ProtoV5ProviderFactories(map[string]func() tfprotov5.ProviderServer{
+//			"example": func() tfprotov5.ProviderServer { return newRouter() },
+//		}),
+//		...
+//	})
+//
+// Each factory returns a new server every time it's called.
+func ProtoV5ProviderFactories(providers map[string]func() tfprotov5.ProviderServer) map[string]func() (tfprotov5.ProviderServer, error) {
+	factories := make(map[string]func() (tfprotov5.ProviderServer, error), len(providers))
+	for name, newServer := range providers {
+		newServer := newServer
+		factories[name] = func() (tfprotov5.ProviderServer, error) {
+			return newServer(), nil
+		}
+	}
+	return factories
+}
+
+// StateValue returns the value of type `typ` represented by `values`, the
+// attribute values of a resource in Terraform's JSON state, like the
+// AttributeValues of the terraform-json StateResources that
+// terraform-plugin-testing passes to state checks. Attributes in `values`
+// that aren't in `typ` are ignored, and attributes in `typ` that aren't in
+// `values` are null.
+func StateValue(typ tftypes.Type, values map[string]interface{}) (tftypes.Value, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return tftypes.ValueFromJSONWithOpts(data, typ, tftypes.ValueFromJSONOpts{
+		IgnoreUndefinedAttributes: true,
+	})
+}
+
+// DecodeState decodes `values`, the attribute values of a resource in
+// Terraform's JSON state, into `target` using asgotypes.Decode, so state
+// checks can make assertions about Go structs rather than attribute paths:
+//
+//	func (c widgetCheck) CheckState(ctx context.Context, req statecheck.CheckStateRequest, resp *statecheck.CheckStateResponse) {
+//		var w widget
+//		err := tftest.DecodeState(widgetType, req.State.Values.RootModule.Resources[0].AttributeValues, &w)
+//		...
+//	}
+func DecodeState(typ tftypes.Type, values map[string]interface{}, target interface{}) error {
+	val, err := StateValue(typ, values)
+	if err != nil {
+		return fmt.Errorf("error parsing state: %w", err)
+	}
+	return asgotypes.Decode(val, target)
+}
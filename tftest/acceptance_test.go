@@ -0,0 +1,87 @@
+package tftest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestProtoV5ProviderFactories(t *testing.T) {
+	created := 0
+	factories := ProtoV5ProviderFactories(map[string]func() tfprotov5.ProviderServer{
+		"example": func() tfprotov5.ProviderServer {
+			created++
+			return &ProviderServer{}
+		},
+	})
+	for i := 0; i < 2; i++ {
+		srv, err := factories["example"]()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, ok := srv.(*ProviderServer); !ok {
+			t.Errorf("expected a *ProviderServer, got %T", srv)
+		}
+	}
+	if created != 2 {
+		t.Errorf("expected 2 servers to be created, got %d", created)
+	}
+}
+
+func TestDecodeState(t *testing.T) {
+	type state struct {
+		ID   string            `tfsdk:"id"`
+		Port *int64            `tfsdk:"port"`
+		Name *string           `tfsdk:"name"`
+		Tags map[string]string `tfsdk:"tags"`
+	}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":   tftypes.String,
+		"port": tftypes.Number,
+		"name": tftypes.String,
+		"tags": tftypes.Map{ElementType: tftypes.String},
+	}}
+	port := int64(8080)
+
+	type testCase struct {
+		values      map[string]interface{}
+		expected    state
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"basic": {
+			values: map[string]interface{}{
+				"id":       "abc",
+				"port":     float64(8080),
+				"tags":     map[string]interface{}{"env": "prod"},
+				"timeouts": nil,
+			},
+			expected: state{ID: "abc", Port: &port, Tags: map[string]string{"env": "prod"}},
+		},
+		"wrong-type": {
+			values:      map[string]interface{}{"tags": "prod"},
+			expectedErr: `error parsing state: AttributeName("tags"): invalid JSON, expected "{", got "prod"`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var got state
+			err := DecodeState(typ, tc.values, &got)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package tftest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// AssertValueEqual fails the test if `got` isn't equal to `expected`,
+// listing each differing value with its attribute path:
+//
+//	values differ:
+//	  at AttributeName("tags").ElementKeyString("env"):
+//	    - tftypes.String<"prod">
+//	    + tftypes.String<"dev">
+func AssertValueEqual(t testing.TB, expected, got tftypes.Value) {
+	t.Helper()
+	if diff := valueDiff(expected, got); diff != "" {
+		t.Errorf("values differ:\n%s", diff)
+	}
+}
+
+// valueDiff returns a description of the differences between `expected` and
+// `got`, or "" if they're equal.
+func valueDiff(expected, got tftypes.Value) string {
+	if expected.Equal(got) {
+		return ""
+	}
+	if !expected.Type().Equal(got.Type()) {
+		return fmt.Sprintf("  at the root:\n    - %s\n    + %s\n", expected.Type(), got.Type())
+	}
+	diffs, err := expected.Diff(got)
+	if err != nil || len(diffs) == 0 {
+		return fmt.Sprintf("  at the root:\n    - %s\n    + %s\n", expected, got)
+	}
+	var b strings.Builder
+	for _, d := range deepest(diffs) {
+		fmt.Fprintf(&b, "  at %s:\n    - %s\n    + %s\n", describePath(d.Path), describeValue(d.Value1), describeValue(d.Value2))
+	}
+	return b.String()
+}
+
+// AssertDiagnostics fails the test if `got` doesn't contain the same
+// diagnostics as `expected`, in the same order, listing the diagnostics that
+// are missing or unexpected:
+//
+//	diagnostics differ (-wanted, +got):
+//	    WARNING "Deprecated"
+//	  - ERROR "Invalid port": "Port 0 is invalid." at AttributeName("port")
+//	  + ERROR "Invalid port": "Port 1 is invalid." at AttributeName("port")
+func AssertDiagnostics(t testing.TB, expected, got []*tfprotov5.Diagnostic) {
+	t.Helper()
+	if diff := lineDiff(describeDiagnostics(expected), describeDiagnostics(got)); diff != "" {
+		t.Errorf("diagnostics differ (-wanted, +got):\n%s", diff)
+	}
+}
+
+// lineDiff returns a diff of the lines `a` and `b`, or "" if they're the
+// same.
+func lineDiff(a, b []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	if lcs[0][0] == len(a) && len(a) == len(b) {
+		return ""
+	}
+	var s strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&s, "    %s\n", a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&s, "  - %s\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&s, "  + %s\n", b[j])
+			j++
+		}
+	}
+	return s.String()
+}
+
+func describeDiagnostics(diags []*tfprotov5.Diagnostic) []string {
+	lines := make([]string, 0, len(diags))
+	for _, d := range diags {
+		lines = append(lines, describeDiagnostic(d))
+	}
+	return lines
+}
+
+func describeDiagnostic(d *tfprotov5.Diagnostic) string {
+	if d == nil {
+		return "nil"
+	}
+	s := fmt.Sprintf("%s %q", d.Severity, d.Summary)
+	if d.Detail != "" {
+		s += fmt.Sprintf(": %q", d.Detail)
+	}
+	if d.Attribute != nil {
+		s += " at " + describePath(d.Attribute)
+	}
+	return s
+}
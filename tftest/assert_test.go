@@ -0,0 +1,107 @@
+package tftest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAssertValueEqual(t *testing.T) {
+	tagsType := tftypes.Map{ElementType: tftypes.String}
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":   tftypes.String,
+		"tags": tagsType,
+	}}
+	obj := func(id string, tags map[string]tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{
+			"id":   tftypes.NewValue(tftypes.String, id),
+			"tags": tftypes.NewValue(tagsType, tags),
+		})
+	}
+
+	type testCase struct {
+		expected tftypes.Value
+		got      tftypes.Value
+		errors   []string
+	}
+	cases := map[string]testCase{
+		"equal": {
+			expected: obj("a", nil),
+			got:      obj("a", nil),
+		},
+		"attributes": {
+			expected: obj("a", map[string]tftypes.Value{
+				"env": tftypes.NewValue(tftypes.String, "prod"),
+			}),
+			got: obj("b", map[string]tftypes.Value{
+				"env":  tftypes.NewValue(tftypes.String, "dev"),
+				"team": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			}),
+			errors: []string{`values differ:
+  at AttributeName("id"):
+    - tftypes.String<"a">
+    + tftypes.String<"b">
+  at AttributeName("tags").ElementKeyString("env"):
+    - tftypes.String<"prod">
+    + tftypes.String<"dev">
+  at AttributeName("tags").ElementKeyString("team"):
+    - nothing
+    + tftypes.String<unknown>
+`},
+		},
+		"types": {
+			expected: tftypes.NewValue(tftypes.String, "1"),
+			got:      tftypes.NewValue(tftypes.Bool, true),
+			errors: []string{`values differ:
+  at the root:
+    - tftypes.String
+    + tftypes.Bool
+`},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			AssertValueEqual(r, tc.expected, tc.got)
+			if diff := cmp.Diff(tc.errors, r.errors); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestAssertDiagnostics(t *testing.T) {
+	port := tftypes.NewAttributePath().WithAttributeName("port")
+	expected := []*tfprotov5.Diagnostic{
+		{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Deprecated"},
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Invalid port", Detail: "Port 0 is invalid.", Attribute: port},
+	}
+
+	r := &recorder{}
+	AssertDiagnostics(r, expected, []*tfprotov5.Diagnostic{
+		{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Deprecated"},
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Invalid port", Detail: "Port 0 is invalid.", Attribute: tftypes.NewAttributePath().WithAttributeName("port")},
+	})
+	if len(r.errors) != 0 {
+		t.Errorf("unexpected failures: %q", r.errors)
+	}
+
+	r = &recorder{}
+	AssertDiagnostics(r, expected, []*tfprotov5.Diagnostic{
+		{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "Deprecated"},
+		{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Invalid port", Detail: "Port 1 is invalid.", Attribute: port},
+		nil,
+	})
+	expectedErrors := []string{`diagnostics differ (-wanted, +got):
+    WARNING "Deprecated"
+  - ERROR "Invalid port": "Port 0 is invalid." at AttributeName("port")
+  + ERROR "Invalid port": "Port 1 is invalid." at AttributeName("port")
+  + nil
+`}
+	if diff := cmp.Diff(expectedErrors, r.errors); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
@@ -0,0 +1,191 @@
+package tftest
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Converter is implemented by types that can be converted both to and from
+// tftypes.Values, like custom attribute types decoded by asgotypes.
+type Converter interface {
+	tftypes.ValueConverter
+	tftypes.ValueCreator
+}
+
+// Expectation is how a Converter is expected to handle a kind of value.
+type Expectation int
+
+const (
+	// ExpectRoundTrip expects the value to be converted into the Converter
+	// and back again unchanged.
+	ExpectRoundTrip Expectation = iota
+
+	// ExpectError expects FromTerraform5Value to return an error.
+	ExpectError
+
+	// ExpectAnything doesn't check how the value is handled, beyond it
+	// not causing a panic.
+	ExpectAnything
+)
+
+// ConverterTest describes the expected behavior of a Converter, to be
+// checked by CheckConverter.
+type ConverterTest struct {
+	// Type is the type of the values the Converter handles.
+	Type tftypes.Type
+
+	// New returns a new, empty Converter, like a pointer to a zero value.
+	New func() Converter
+
+	// Values are values of Type that should round-trip through the
+	// Converter unchanged.
+	Values []tftypes.Value
+
+	// Invalid are values FromTerraform5Value should return an error for.
+	Invalid []tftypes.Value
+
+	// Null is how a null value of Type should be handled.
+	Null Expectation
+
+	// Unknown is how an unknown value of Type should be handled.
+	Unknown Expectation
+}
+
+// CheckConverter checks that the Converter described by `c` converts each of
+// its Values to and from a tftypes.Value without changing it, including when
+// the Converter already holds another value, that it handles null and
+// unknown values as expected, and that it rejects its Invalid values. Each
+// problem found fails the test, with the attribute paths of any values that
+// changed.
+func CheckConverter(t testing.TB, c ConverterTest) {
+	t.Helper()
+	for i, val := range c.Values {
+		checkRoundTrip(t, fmt.Sprintf("value %d", i), c, c.New(), val)
+		if i > 0 {
+			conv := c.New()
+			if _, err := convert(c.Type, conv, c.Values[i-1]); err == nil {
+				checkRoundTrip(t, fmt.Sprintf("value %d, decoded over value %d", i, i-1), c, conv, val)
+			}
+		}
+	}
+	checkExpectation(t, "null value", c, c.Null, tftypes.NewValue(c.Type, nil))
+	checkExpectation(t, "unknown value", c, c.Unknown, tftypes.NewValue(c.Type, tftypes.UnknownValue))
+	for i, val := range c.Invalid {
+		if _, err := convert(c.Type, c.New(), val); err == nil {
+			t.Errorf("invalid value %d: expected an error converting %s, got none", i, val)
+		} else if _, ok := err.(*panicError); ok {
+			t.Errorf("invalid value %d: %s", i, err)
+		}
+	}
+}
+
+func checkExpectation(t testing.TB, name string, c ConverterTest, expected Expectation, val tftypes.Value) {
+	t.Helper()
+	switch expected {
+	case ExpectRoundTrip:
+		checkRoundTrip(t, name, c, c.New(), val)
+	case ExpectError:
+		if _, err := convert(c.Type, c.New(), val); err == nil {
+			t.Errorf("%s: expected an error converting %s, got none", name, val)
+		} else if _, ok := err.(*panicError); ok {
+			t.Errorf("%s: %s", name, err)
+		}
+	default:
+		if _, err := convert(c.Type, c.New(), val); err != nil {
+			if _, ok := err.(*panicError); ok {
+				t.Errorf("%s: %s", name, err)
+			}
+		}
+	}
+}
+
+func checkRoundTrip(t testing.TB, name string, c ConverterTest, conv Converter, val tftypes.Value) {
+	t.Helper()
+	got, err := convert(c.Type, conv, val)
+	if err != nil {
+		t.Errorf("%s: %s", name, err)
+		return
+	}
+	if got.Equal(val) {
+		return
+	}
+	diffs, err := val.Diff(got)
+	if err != nil || len(diffs) == 0 {
+		t.Errorf("%s: expected %s, got %s", name, val, got)
+		return
+	}
+	for _, d := range deepest(diffs) {
+		t.Errorf("%s: value changed at %s: expected %s, got %s", name, describePath(d.Path), describeValue(d.Value1), describeValue(d.Value2))
+	}
+}
+
+// deepest returns the diffs in `diffs` that don't contain other diffs, in
+// order of their paths.
+func deepest(diffs []tftypes.ValueDiff) []tftypes.ValueDiff {
+	var result []tftypes.ValueDiff
+	for _, d := range diffs {
+		contains := false
+		for _, other := range diffs {
+			if len(other.Path.Steps()) > len(d.Path.Steps()) && d.Path.Equal(tftypes.NewAttributePathWithSteps(other.Path.Steps()[:len(d.Path.Steps())])) {
+				contains = true
+				break
+			}
+		}
+		if !contains {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path.String() < result[j].Path.String()
+	})
+	return result
+}
+
+// panicError is returned by convert when the Converter panics.
+type panicError struct {
+	method string
+	value  interface{}
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.method, e.value)
+}
+
+// convert converts `val` into `conv` and back into a value of type `typ`.
+func convert(typ tftypes.Type, conv Converter, val tftypes.Value) (got tftypes.Value, err error) {
+	method := "FromTerraform5Value"
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{method: method, value: r}
+		}
+	}()
+	if err := conv.FromTerraform5Value(val); err != nil {
+		return tftypes.Value{}, fmt.Errorf("error converting %s: %w", val, err)
+	}
+	method = "ToTerraform5Value"
+	raw, err := conv.ToTerraform5Value()
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("error converting back to a value: %w", err)
+	}
+	if err := tftypes.ValidateValue(typ, raw); err != nil {
+		return tftypes.Value{}, fmt.Errorf("ToTerraform5Value returned an invalid %s: %w", typ, err)
+	}
+	return tftypes.NewValue(typ, raw), nil
+}
+
+func describePath(path *tftypes.AttributePath) string {
+	if len(path.Steps()) == 0 {
+		return "the root"
+	}
+	return path.String()
+}
+
+func describeValue(val *tftypes.Value) string {
+	if val == nil {
+		return "nothing"
+	}
+	return val.String()
+}
@@ -0,0 +1,181 @@
+package tftest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// optionalString is a correct Converter for strings.
+type optionalString struct {
+	value   *string
+	unknown bool
+}
+
+func (s *optionalString) FromTerraform5Value(val tftypes.Value) error {
+	*s = optionalString{unknown: !val.IsKnown()}
+	if !val.IsKnown() || val.IsNull() {
+		return nil
+	}
+	var str string
+	if err := val.As(&str); err != nil {
+		return err
+	}
+	if str == "invalid" {
+		return errors.New("invalid string")
+	}
+	s.value = &str
+	return nil
+}
+
+func (s *optionalString) ToTerraform5Value() (interface{}, error) {
+	if s.unknown {
+		return tftypes.UnknownValue, nil
+	}
+	if s.value == nil {
+		return nil, nil
+	}
+	return *s.value, nil
+}
+
+// trimmedTags trims its elements, doesn't reset itself, and can't hold null
+// or unknown values.
+type trimmedTags struct {
+	tags []string
+}
+
+func (s *trimmedTags) FromTerraform5Value(val tftypes.Value) error {
+	var elems []tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		var str string
+		if err := elem.As(&str); err != nil {
+			return err
+		}
+		s.tags = append(s.tags, strings.TrimSpace(str))
+	}
+	return nil
+}
+
+func (s *trimmedTags) ToTerraform5Value() (interface{}, error) {
+	elems := []tftypes.Value{}
+	for _, tag := range s.tags {
+		elems = append(elems, tftypes.NewValue(tftypes.String, tag))
+	}
+	return elems, nil
+}
+
+func TestCheckConverter(t *testing.T) {
+	tagsType := tftypes.List{ElementType: tftypes.String}
+	tags := func(s ...string) tftypes.Value {
+		elems := []tftypes.Value{}
+		for _, e := range s {
+			elems = append(elems, tftypes.NewValue(tftypes.String, e))
+		}
+		return tftypes.NewValue(tagsType, elems)
+	}
+
+	type testCase struct {
+		test     ConverterTest
+		expected []string
+	}
+	cases := map[string]testCase{
+		"conforming": {
+			test: ConverterTest{
+				Type: tftypes.String,
+				New:  func() Converter { return &optionalString{} },
+				Values: []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "a"),
+					tftypes.NewValue(tftypes.String, ""),
+				},
+				Invalid: []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "invalid"),
+					tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+				},
+			},
+		},
+		"invalid-accepted": {
+			test: ConverterTest{
+				Type:    tftypes.String,
+				New:     func() Converter { return &optionalString{} },
+				Invalid: []tftypes.Value{tftypes.NewValue(tftypes.String, "valid")},
+				Null:    ExpectError,
+				Unknown: ExpectAnything,
+			},
+			expected: []string{
+				`null value: expected an error converting tftypes.String<null>, got none`,
+				`invalid value 0: expected an error converting tftypes.String<"valid">, got none`,
+			},
+		},
+		"broken": {
+			test: ConverterTest{
+				Type:   tagsType,
+				New:    func() Converter { return &trimmedTags{} },
+				Values: []tftypes.Value{tags("a", " b"), tags("c")},
+			},
+			expected: []string{
+				`value 0: value changed at ElementKeyInt(1): expected tftypes.String<" b">, got tftypes.String<"b">`,
+				`value 1, decoded over value 0: value changed at ElementKeyInt(0): expected tftypes.String<"c">, got tftypes.String<"a">`,
+				`value 1, decoded over value 0: value changed at ElementKeyInt(1): expected nothing, got tftypes.String<"b">`,
+				`value 1, decoded over value 0: value changed at ElementKeyInt(2): expected nothing, got tftypes.String<"c">`,
+				`null value: value changed at the root: expected tftypes.List[tftypes.String]<null>, got tftypes.List[tftypes.String]<>`,
+				`unknown value: error converting tftypes.List[tftypes.String]<unknown>: unmarshaling unknown values is not supported`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			CheckConverter(r, tc.test)
+			if diff := cmp.Diff(tc.expected, r.errors); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+type panickingConverter struct{}
+
+func (panickingConverter) FromTerraform5Value(tftypes.Value) error {
+	panic("oops")
+}
+
+func (panickingConverter) ToTerraform5Value() (interface{}, error) {
+	return nil, nil
+}
+
+func TestCheckConverterPanic(t *testing.T) {
+	r := &recorder{}
+	CheckConverter(r, ConverterTest{
+		Type:    tftypes.String,
+		New:     func() Converter { return panickingConverter{} },
+		Null:    ExpectAnything,
+		Unknown: ExpectError,
+	})
+	expected := []string{
+		"null value: FromTerraform5Value panicked: oops",
+		"unknown value: FromTerraform5Value panicked: oops",
+	}
+	if diff := cmp.Diff(expected, r.errors); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
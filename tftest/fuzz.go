@@ -0,0 +1,126 @@
+package tftest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// fuzzSeeds is the number of seed inputs the Fuzz functions add.
+const fuzzSeeds = 8
+
+// bytesSource is a rand.Source that reads its output from a byte slice, so
+// a fuzzer mutating the slice controls the values generated from it. Once
+// the slice is exhausted, it returns zeros.
+type bytesSource struct {
+	data []byte
+}
+
+func (s *bytesSource) Int63() int64 {
+	var buf [8]byte
+	n := copy(buf[:], s.data)
+	s.data = s.data[n:]
+	return int64(binary.BigEndian.Uint64(buf[:]) >> 1)
+}
+
+func (s *bytesSource) Seed(int64) {}
+
+// ValueFromBytes returns a value of type `typ` chosen by `data`, which may
+// be null, unknown, or contain null or unknown values. Every input produces
+// a valid value, and the same input always produces the same value, so it
+// can be used to turn the arbitrary bytes produced by a fuzzer into values.
+func ValueFromBytes(typ tftypes.Type, data []byte) tftypes.Value {
+	g := Generator{Nulls: true, Unknowns: true}
+	return g.Value(rand.New(&bytesSource{data: data}), typ)
+}
+
+// FuzzValue fuzzes `fn` with values of type `typ` created by ValueFromBytes,
+// for finding inputs that cause code handling values, like a
+// tftypes.ValueConverter, to panic or misbehave:
+//
+//	func FuzzWidget(f *testing.F) {
+//		tftest.FuzzValue(f, widgetType, func(t *testing.T, val tftypes.Value) {
+//			var w widget
+//			_ = asgotypes.Decode(val, &w)
+//		})
+//	}
+func FuzzValue(f *testing.F, typ tftypes.Type, fn func(t *testing.T, val tftypes.Value)) {
+	f.Helper()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < fuzzSeeds; i++ {
+		seed := make([]byte, 8*(i+1)*4)
+		r.Read(seed)
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fn(t, ValueFromBytes(typ, data))
+	})
+}
+
+// FuzzDynamicValue fuzzes `fn` with values of type `typ` unmarshaled from
+// arbitrary MessagePack and JSON DynamicValues, like those in state and
+// configuration sent by Terraform. Inputs that aren't valid for `typ` are
+// skipped, including those that make tfprotov5.DynamicValue.Unmarshal itself
+// panic, which Terraform never sends. The seed corpus is made up of valid
+// encodings of arbitrary values.
+func FuzzDynamicValue(f *testing.F, typ tftypes.Type, fn func(t *testing.T, val tftypes.Value)) {
+	f.Helper()
+	r := rand.New(rand.NewSource(1))
+	g := Generator{Nulls: true, Unknowns: true}
+	for i := 0; i < fuzzSeeds; i++ {
+		dv, err := tfprotov5.NewDynamicValue(typ, g.Value(r, typ))
+		if err != nil {
+			f.Fatalf("error creating seed: %s", err)
+		}
+		f.Add(dv.MsgPack, false)
+	}
+	g = Generator{Nulls: true}
+	for i := 0; i < fuzzSeeds; i++ {
+		val := g.Value(r, typ)
+		data, err := marshalValue(tftypes.NewAttributePath(), typ, val)
+		if err != nil {
+			continue
+		}
+		f.Add([]byte(data), true)
+	}
+	f.Fuzz(func(t *testing.T, data []byte, json bool) {
+		dv := tfprotov5.DynamicValue{MsgPack: data}
+		if json {
+			dv = tfprotov5.DynamicValue{JSON: data}
+		}
+		val, err := unmarshal(dv, typ)
+		if err != nil {
+			t.Skip()
+		}
+		fn(t, val)
+	})
+}
+
+// unmarshal unmarshals `dv`, returning an error if unmarshaling panics.
+// MessagePack is checked to be well-formed first, as
+// tfprotov5.DynamicValue.Unmarshal allocates collections of the size they
+// claim to be before reading their elements.
+func unmarshal(dv tfprotov5.DynamicValue, typ tftypes.Type) (val tftypes.Value, err error) {
+	if dv.MsgPack != nil {
+		r := bytes.NewReader(dv.MsgPack)
+		if err := msgpack.NewDecoder(r).Skip(); err != nil {
+			return tftypes.Value{}, err
+		}
+		if r.Len() > 0 {
+			return tftypes.Value{}, errors.New("trailing data after value")
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic unmarshaling value: %v", r)
+		}
+	}()
+	return dv.Unmarshal(typ)
+}
@@ -0,0 +1,63 @@
+package tftest
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestValueFromBytes(t *testing.T) {
+	type testCase struct {
+		data []byte
+	}
+	cases := map[string]testCase{
+		"empty": {},
+		"short": {
+			data: []byte{0xff},
+		},
+		"long": {
+			data: []byte("the quick brown fox jumps over the lazy dog, many times over"),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			a := ValueFromBytes(widgetType, tc.data)
+			b := ValueFromBytes(widgetType, tc.data)
+			if !a.Type().Equal(widgetType) {
+				t.Errorf("expected a value of type %s, got %s", widgetType, a.Type())
+			}
+			if !a.Equal(b) {
+				t.Errorf("expected the same value from the same input, got %s and %s", a, b)
+			}
+		})
+	}
+}
+
+func FuzzGoldenRoundTrip(f *testing.F) {
+	FuzzValue(f, widgetType, func(t *testing.T, val tftypes.Value) {
+		data, err := MarshalValue(val)
+		if err != nil {
+			return
+		}
+		got, err := UnmarshalValue(data)
+		if err != nil {
+			t.Fatalf("error unmarshaling %s: %s", data, err)
+		}
+		AssertValueEqual(t, val, got)
+	})
+}
+
+func FuzzGoldenDynamicValue(f *testing.F) {
+	FuzzDynamicValue(f, widgetType, func(t *testing.T, val tftypes.Value) {
+		data, err := MarshalValue(val)
+		if err != nil {
+			return
+		}
+		got, err := UnmarshalValue(data)
+		if err != nil {
+			t.Fatalf("error unmarshaling %s: %s", data, err)
+		}
+		AssertValueEqual(t, val, got)
+	})
+}
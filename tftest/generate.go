@@ -0,0 +1,239 @@
+package tftest
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const (
+	// DefaultMaxDepth is the default depth to which generated types
+	// nest collections and objects.
+	DefaultMaxDepth = 3
+
+	// DefaultMaxElements is the default maximum number of elements in
+	// generated collections, and attributes in generated objects.
+	DefaultMaxElements = 4
+)
+
+var _ quick.Generator = Pair{}
+
+// Generator generates arbitrary types and values conforming to them, for
+// property-based tests of code that converts values, like the asgotypes
+// codec. The zero value generates known, non-null values of types nested up
+// to DefaultMaxDepth deep, with up to DefaultMaxElements elements.
+//
+// Generator takes its randomness from a *rand.Rand so it can be used with
+// testing/quick, or with libraries like rapid by seeding a rand.Rand from a
+// value they draw.
+type Generator struct {
+	// MaxDepth is how deeply generated types can nest collections and
+	// objects. A negative depth only generates primitive types.
+	MaxDepth int
+
+	// MaxElements is the maximum number of elements in generated
+	// collections, and attributes in generated objects.
+	MaxElements int
+
+	// Nulls, if true, generates null values.
+	Nulls bool
+
+	// Unknowns, if true, generates unknown values.
+	Unknowns bool
+
+	// Dynamic, if true, generates object attributes with the
+	// DynamicPseudoType type, except in the elements of collections.
+	Dynamic bool
+}
+
+func (g *Generator) maxDepth() int {
+	if g.MaxDepth == 0 {
+		return DefaultMaxDepth
+	}
+	return g.MaxDepth
+}
+
+func (g *Generator) maxElements() int {
+	if g.MaxElements <= 0 {
+		return DefaultMaxElements
+	}
+	return g.MaxElements
+}
+
+// Type returns an arbitrary type.
+func (g *Generator) Type(r *rand.Rand) tftypes.Type {
+	return g.typ(r, g.maxDepth(), false, false)
+}
+
+// typ returns an arbitrary type. DynamicPseudoType is only returned for
+// `attribute`s not nested in a collection, as the elements of a collection
+// must all have the same type.
+func (g *Generator) typ(r *rand.Rand, depth int, attribute, collection bool) tftypes.Type {
+	kinds := 3
+	if depth > 0 {
+		kinds = 8
+	}
+	if attribute && !collection && g.Dynamic && r.Intn(kinds+1) == 0 {
+		return tftypes.DynamicPseudoType
+	}
+	switch r.Intn(kinds) {
+	case 0:
+		return tftypes.String
+	case 1:
+		return tftypes.Number
+	case 2:
+		return tftypes.Bool
+	case 3:
+		return tftypes.List{ElementType: g.typ(r, depth-1, false, true)}
+	case 4:
+		return tftypes.Set{ElementType: g.typ(r, depth-1, false, true)}
+	case 5:
+		return tftypes.Map{ElementType: g.typ(r, depth-1, false, true)}
+	case 6:
+		n := r.Intn(g.maxElements() + 1)
+		elems := make([]tftypes.Type, 0, n)
+		for i := 0; i < n; i++ {
+			elems = append(elems, g.typ(r, depth-1, false, collection))
+		}
+		return tftypes.Tuple{ElementTypes: elems}
+	}
+	n := r.Intn(g.maxElements() + 1)
+	attrs := make(map[string]tftypes.Type, n)
+	for i := 0; i < n; i++ {
+		attrs[fmt.Sprintf("attr_%d", i)] = g.typ(r, depth-1, true, collection)
+	}
+	return tftypes.Object{AttributeTypes: attrs}
+}
+
+// Value returns an arbitrary value of type `typ`. Values of
+// DynamicPseudoType are given an arbitrary primitive type.
+func (g *Generator) Value(r *rand.Rand, typ tftypes.Type) tftypes.Value {
+	if g.Unknowns && r.Intn(8) == 0 {
+		return tftypes.NewValue(typ, tftypes.UnknownValue)
+	}
+	if g.Nulls && r.Intn(8) == 0 {
+		return tftypes.NewValue(typ, nil)
+	}
+	switch {
+	case typ.Is(tftypes.DynamicPseudoType):
+		return g.Value(r, g.typ(r, -1, false, false))
+	case typ.Is(tftypes.String):
+		return tftypes.NewValue(typ, randomString(r))
+	case typ.Is(tftypes.Number):
+		return tftypes.NewValue(typ, randomNumber(r))
+	case typ.Is(tftypes.Bool):
+		return tftypes.NewValue(typ, r.Intn(2) == 0)
+	case typ.Is(tftypes.List{}):
+		n := r.Intn(g.maxElements() + 1)
+		elems := make([]tftypes.Value, 0, n)
+		for i := 0; i < n; i++ {
+			elems = append(elems, g.Value(r, typ.(tftypes.List).ElementType))
+		}
+		return tftypes.NewValue(typ, elems)
+	case typ.Is(tftypes.Set{}):
+		n := r.Intn(g.maxElements() + 1)
+		elems := make([]tftypes.Value, 0, n)
+	elements:
+		for i := 0; i < n; i++ {
+			elem := g.Value(r, typ.(tftypes.Set).ElementType)
+			for _, e := range elems {
+				if e.Equal(elem) {
+					continue elements
+				}
+			}
+			elems = append(elems, elem)
+		}
+		return tftypes.NewValue(typ, elems)
+	case typ.Is(tftypes.Map{}):
+		n := r.Intn(g.maxElements() + 1)
+		elems := make(map[string]tftypes.Value, n)
+		for i := 0; i < n; i++ {
+			elems[randomString(r)] = g.Value(r, typ.(tftypes.Map).ElementType)
+		}
+		return tftypes.NewValue(typ, elems)
+	case typ.Is(tftypes.Tuple{}):
+		types := typ.(tftypes.Tuple).ElementTypes
+		elems := make([]tftypes.Value, 0, len(types))
+		for _, t := range types {
+			elems = append(elems, g.Value(r, t))
+		}
+		return tftypes.NewValue(typ, elems)
+	case typ.Is(tftypes.Object{}):
+		types := typ.(tftypes.Object).AttributeTypes
+		attrs := make(map[string]tftypes.Value, len(types))
+		for name, t := range types {
+			attrs[name] = g.Value(r, t)
+		}
+		return tftypes.NewValue(typ, attrs)
+	}
+	panic(fmt.Sprintf("tftest: can't generate a value of type %s", typ))
+}
+
+// Pair is an arbitrary type and a value of that type. It implements
+// quick.Generator, so it can be used as an argument to functions tested with
+// quick.Check:
+//
+//	err := quick.Check(func(p tftest.Pair) bool {
+//		return roundTrip(p.Value).Equal(p.Value)
+//	}, nil)
+//
+// Generated values may be null or unknown; use a Generator directly for more
+// control.
+type Pair struct {
+	Type  tftypes.Type
+	Value tftypes.Value
+}
+
+// Generate implements quick.Generator. Collections have at most `size`
+// elements, or DefaultMaxElements if that's fewer.
+func (Pair) Generate(r *rand.Rand, size int) reflect.Value {
+	if size > DefaultMaxElements {
+		size = DefaultMaxElements
+	}
+	g := Generator{MaxElements: size, Nulls: true, Unknowns: true, Dynamic: true}
+	return reflect.ValueOf(g.Pair(r))
+}
+
+// Pair returns an arbitrary type and a value of that type.
+func (g *Generator) Pair(r *rand.Rand) Pair {
+	typ := g.Type(r)
+	return Pair{Type: typ, Value: g.Value(r, typ)}
+}
+
+// stringRunes are the runes generated strings are made of, including some
+// that need escaping or more than one byte to encode.
+var stringRunes = []rune("abcxyz019 _-.\"\\\n\tééß日本🙂")
+
+func randomString(r *rand.Rand) string {
+	n := r.Intn(8)
+	s := make([]rune, 0, n)
+	for i := 0; i < n; i++ {
+		s = append(s, stringRunes[r.Intn(len(stringRunes))])
+	}
+	return string(s)
+}
+
+// randomNumber returns an arbitrary number, parsed from its decimal form with
+// the same precision Terraform uses.
+func randomNumber(r *rand.Rand) *big.Float {
+	var s string
+	switch r.Intn(4) {
+	case 0:
+		s = fmt.Sprint(r.Intn(201) - 100)
+	case 1:
+		s = fmt.Sprint(r.Int63() - r.Int63())
+	case 2:
+		s = fmt.Sprintf("%d.%d", r.Intn(2001)-1000, r.Intn(1000))
+	default:
+		s = fmt.Sprintf("%de%d", r.Intn(1000)+1, r.Intn(400)-200)
+	}
+	f, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
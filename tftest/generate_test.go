@@ -0,0 +1,96 @@
+package tftest
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGeneratorDeterministic(t *testing.T) {
+	var g Generator
+	a := g.Pair(rand.New(rand.NewSource(42)))
+	b := g.Pair(rand.New(rand.NewSource(42)))
+	if !a.Type.Equal(b.Type) {
+		t.Errorf("expected the same type from the same seed, got %s and %s", a.Type, b.Type)
+	}
+	if !a.Value.Equal(b.Value) {
+		t.Errorf("expected the same value from the same seed, got %s and %s", a.Value, b.Value)
+	}
+}
+
+func TestGeneratorOptions(t *testing.T) {
+	type testCase struct {
+		gen   Generator
+		check func(*testing.T, tftypes.Value)
+	}
+	cases := map[string]testCase{
+		"primitives": {
+			gen: Generator{MaxDepth: -1},
+			check: func(t *testing.T, v tftypes.Value) {
+				typ := v.Type()
+				if !typ.Is(tftypes.String) && !typ.Is(tftypes.Number) && !typ.Is(tftypes.Bool) {
+					t.Errorf("expected a primitive type, got %s", typ)
+				}
+			},
+		},
+		"known": {
+			gen: Generator{},
+			check: func(t *testing.T, v tftypes.Value) {
+				if !v.IsFullyKnown() {
+					t.Errorf("expected a fully known value, got %s", v)
+				}
+				_ = tftypes.Walk(v, func(_ *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+					if v.IsNull() {
+						t.Errorf("expected no null values, got %s", v)
+					}
+					return true, nil
+				})
+			},
+		},
+		"max-elements": {
+			gen: Generator{MaxElements: 1},
+			check: func(t *testing.T, v tftypes.Value) {
+				_ = tftypes.Walk(v, func(_ *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+					if v.Type().Is(tftypes.Object{}) || v.Type().Is(tftypes.Tuple{}) {
+						return true, nil
+					}
+					var elems []tftypes.Value
+					if v.As(&elems) == nil && len(elems) > 1 {
+						t.Errorf("expected at most 1 element, got %d", len(elems))
+					}
+					return true, nil
+				})
+			},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 100; i++ {
+				tc.check(t, tc.gen.Pair(r).Value)
+			}
+		})
+	}
+}
+
+func TestPairRoundTripsGolden(t *testing.T) {
+	err := quick.Check(func(p Pair) bool {
+		data, err := MarshalValue(p.Value)
+		if err != nil {
+			t.Logf("error marshaling %s: %s", p.Value, err)
+			return false
+		}
+		got, err := UnmarshalValue(data)
+		if err != nil {
+			t.Logf("error unmarshaling %s: %s", data, err)
+			return false
+		}
+		return got.Equal(p.Value)
+	}, &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(1))})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,487 @@
+package tftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var update = flag.Bool("update", false, "update golden files instead of comparing against them")
+
+// Update returns true if the -update flag was passed to the test binary,
+// meaning golden files should be written rather than compared against.
+func Update() bool {
+	return *update
+}
+
+// unknownMarker is how unknown values are represented in golden files.
+var unknownMarker = []byte(`{"$unknown":true}`)
+
+// goldenFile is the contents of a golden file.
+type goldenFile struct {
+	Type  json.RawMessage `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalValue returns the golden file representation of `val`: a JSON object
+// holding its type and its value. The value is encoded much like Terraform's
+// JSON encoding, with set elements sorted so the result is stable, unknown
+// values written as {"$unknown":true}, and values of dynamic attributes
+// written as an object holding their type and value.
+//
+// Strings that aren't valid UTF-8, which Terraform never sends, and maps and
+// objects whose only element is a true bool with the key "$unknown" can't be
+// represented, and return an error.
+func MarshalValue(val tftypes.Value) ([]byte, error) {
+	typ, err := marshalType(val.Type())
+	if err != nil {
+		return nil, err
+	}
+	v, err := marshalValue(tftypes.NewAttributePath(), val.Type(), val)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(goldenFile{Type: typ, Value: v}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// UnmarshalValue returns the value represented by `data`, which must have
+// been produced by MarshalValue.
+func UnmarshalValue(data []byte) (tftypes.Value, error) {
+	var f goldenFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return tftypes.Value{}, err
+	}
+	if f.Type == nil {
+		return tftypes.Value{}, errors.New("missing type")
+	}
+	typ, err := unmarshalType(f.Type)
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("error parsing type: %w", err)
+	}
+	return unmarshalValue(tftypes.NewAttributePath(), typ, f.Value)
+}
+
+// GoldenPath returns the path of the golden file called `name`, which is
+// testdata/NAME.golden.json.
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden.json")
+}
+
+// Golden compares `val` to the value in the golden file called `name`, and
+// fails the test with a diff if they differ. If the -update flag was passed,
+// the golden file is written with `val` instead.
+func Golden(t testing.TB, name string, val tftypes.Value) {
+	t.Helper()
+	got, err := MarshalValue(val)
+	if err != nil {
+		t.Fatalf("error marshaling value: %s", err)
+	}
+	path := GoldenPath(name)
+	if Update() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("error creating golden file directory: %s", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("error writing golden file: %s", err)
+		}
+		return
+	}
+	expected, err := MarshalValue(ReadGolden(t, name))
+	if err != nil {
+		t.Fatalf("error marshaling golden value: %s", err)
+	}
+	if diff := cmp.Diff(string(expected), string(got)); diff != "" {
+		t.Errorf("value doesn't match golden file %s, run with -update to update it (-wanted, +got): %s", path, diff)
+	}
+}
+
+// ReadGolden returns the value in the golden file called `name`, failing the
+// test if it can't be read.
+func ReadGolden(t testing.TB, name string) tftypes.Value {
+	t.Helper()
+	path := GoldenPath(name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading golden file: %s", err)
+	}
+	val, err := UnmarshalValue(data)
+	if err != nil {
+		t.Fatalf("error parsing golden file %s: %s", path, err)
+	}
+	return val
+}
+
+func marshalType(typ tftypes.Type) (json.RawMessage, error) {
+	var v interface{}
+	switch {
+	case typ.Is(tftypes.DynamicPseudoType):
+		v = "dynamic"
+	case typ.Is(tftypes.String):
+		v = "string"
+	case typ.Is(tftypes.Number):
+		v = "number"
+	case typ.Is(tftypes.Bool):
+		v = "bool"
+	case typ.Is(tftypes.List{}):
+		elem, err := marshalType(typ.(tftypes.List).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		v = []interface{}{"list", elem}
+	case typ.Is(tftypes.Set{}):
+		elem, err := marshalType(typ.(tftypes.Set).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		v = []interface{}{"set", elem}
+	case typ.Is(tftypes.Map{}):
+		elem, err := marshalType(typ.(tftypes.Map).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		v = []interface{}{"map", elem}
+	case typ.Is(tftypes.Tuple{}):
+		elems := []json.RawMessage{}
+		for _, e := range typ.(tftypes.Tuple).ElementTypes {
+			elem, err := marshalType(e)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		v = []interface{}{"tuple", elems}
+	case typ.Is(tftypes.Object{}):
+		obj := typ.(tftypes.Object)
+		attrs := map[string]json.RawMessage{}
+		for name, a := range obj.AttributeTypes {
+			attr, err := marshalType(a)
+			if err != nil {
+				return nil, err
+			}
+			attrs[name] = attr
+		}
+		if len(obj.OptionalAttributes) == 0 {
+			v = []interface{}{"object", attrs}
+			break
+		}
+		optional := make([]string, 0, len(obj.OptionalAttributes))
+		for name := range obj.OptionalAttributes {
+			optional = append(optional, name)
+		}
+		sort.Strings(optional)
+		v = []interface{}{"object", attrs, optional}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+	return json.Marshal(v)
+}
+
+func unmarshalType(data json.RawMessage) (tftypes.Type, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		switch name {
+		case "dynamic":
+			return tftypes.DynamicPseudoType, nil
+		case "string":
+			return tftypes.String, nil
+		case "number":
+			return tftypes.Number, nil
+		case "bool":
+			return tftypes.Bool, nil
+		}
+		return nil, fmt.Errorf("unknown type %q", name)
+	}
+	var parts []json.RawMessage
+	if err := json.Unmarshal(data, &parts); err != nil || len(parts) < 2 {
+		return nil, fmt.Errorf("invalid type %s", data)
+	}
+	if err := json.Unmarshal(parts[0], &name); err != nil {
+		return nil, fmt.Errorf("invalid type %s", data)
+	}
+	switch name {
+	case "list", "set", "map":
+		elem, err := unmarshalType(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case "list":
+			return tftypes.List{ElementType: elem}, nil
+		case "set":
+			return tftypes.Set{ElementType: elem}, nil
+		}
+		return tftypes.Map{ElementType: elem}, nil
+	case "tuple":
+		var raw []json.RawMessage
+		if err := json.Unmarshal(parts[1], &raw); err != nil {
+			return nil, fmt.Errorf("invalid tuple type %s", data)
+		}
+		elems := make([]tftypes.Type, 0, len(raw))
+		for _, r := range raw {
+			elem, err := unmarshalType(r)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return tftypes.Tuple{ElementTypes: elems}, nil
+	case "object":
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(parts[1], &raw); err != nil {
+			return nil, fmt.Errorf("invalid object type %s", data)
+		}
+		obj := tftypes.Object{AttributeTypes: map[string]tftypes.Type{}}
+		for name, r := range raw {
+			attr, err := unmarshalType(r)
+			if err != nil {
+				return nil, err
+			}
+			obj.AttributeTypes[name] = attr
+		}
+		if len(parts) > 2 {
+			var optional []string
+			if err := json.Unmarshal(parts[2], &optional); err != nil {
+				return nil, fmt.Errorf("invalid object type %s", data)
+			}
+			obj.OptionalAttributes = map[string]struct{}{}
+			for _, name := range optional {
+				obj.OptionalAttributes[name] = struct{}{}
+			}
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", name)
+}
+
+// marshalValue encodes `val`, which is at `path` and was declared with the
+// type `typ`.
+func marshalValue(path *tftypes.AttributePath, typ tftypes.Type, val tftypes.Value) (json.RawMessage, error) {
+	switch {
+	case !val.IsKnown():
+		return unknownMarker, nil
+	case val.IsNull():
+		return json.RawMessage("null"), nil
+	case typ.Is(tftypes.DynamicPseudoType):
+		t, err := marshalType(val.Type())
+		if err != nil {
+			return nil, path.NewError(err)
+		}
+		v, err := marshalValue(path, val.Type(), val)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]json.RawMessage{"type": t, "value": v})
+	case typ.Is(tftypes.String):
+		var s string
+		if err := val.As(&s); err != nil {
+			return nil, path.NewError(err)
+		}
+		if !utf8.ValidString(s) {
+			return nil, path.NewErrorf("string %q isn't valid UTF-8", s)
+		}
+		return json.Marshal(s)
+	case typ.Is(tftypes.Number):
+		n := new(big.Float)
+		if err := val.As(&n); err != nil {
+			return nil, path.NewError(err)
+		}
+		if n.IsInt() {
+			return json.RawMessage(n.Text('f', 0)), nil
+		}
+		return json.RawMessage(n.Text('g', -1)), nil
+	case typ.Is(tftypes.Bool):
+		var b bool
+		if err := val.As(&b); err != nil {
+			return nil, path.NewError(err)
+		}
+		return json.Marshal(b)
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		out := make([]json.RawMessage, 0, len(elems))
+		for i, elem := range elems {
+			var elemType tftypes.Type
+			elemPath := path.WithElementKeyInt(i)
+			switch t := typ.(type) {
+			case tftypes.List:
+				elemType = t.ElementType
+			case tftypes.Set:
+				elemType = t.ElementType
+				elemPath = path.WithElementKeyValue(elem)
+			case tftypes.Tuple:
+				elemType = t.ElementTypes[i]
+			}
+			e, err := marshalValue(elemPath, elemType, elem)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, e)
+		}
+		if typ.Is(tftypes.Set{}) {
+			sort.Slice(out, func(i, j int) bool {
+				return bytes.Compare(out[i], out[j]) < 0
+			})
+		}
+		return json.Marshal(out)
+	case typ.Is(tftypes.Map{}), typ.Is(tftypes.Object{}):
+		var elems map[string]tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		out := make(map[string]json.RawMessage, len(elems))
+		for key, elem := range elems {
+			var e json.RawMessage
+			var err error
+			if m, ok := typ.(tftypes.Map); ok {
+				e, err = marshalValue(path.WithElementKeyString(key), m.ElementType, elem)
+			} else {
+				e, err = marshalValue(path.WithAttributeName(key), typ.(tftypes.Object).AttributeTypes[key], elem)
+			}
+			if err != nil {
+				return nil, err
+			}
+			out[key] = e
+		}
+		b, err := json.Marshal(out)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(b, unknownMarker) {
+			return nil, path.NewErrorf("value can't be represented in a golden file, because it's indistinguishable from an unknown value")
+		}
+		return b, nil
+	}
+	return nil, path.NewErrorf("unsupported type %s", typ)
+}
+
+// unmarshalValue decodes `data` as a value of type `typ` at `path`.
+func unmarshalValue(path *tftypes.AttributePath, typ tftypes.Type, data json.RawMessage) (tftypes.Value, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if isUnknown(data) {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	switch {
+	case typ.Is(tftypes.DynamicPseudoType):
+		var dyn goldenFile
+		if err := dec.Decode(&dyn); err != nil || dyn.Type == nil {
+			return tftypes.Value{}, path.NewErrorf("invalid dynamic value %s", data)
+		}
+		t, err := unmarshalType(dyn.Type)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return unmarshalValue(path, t, dyn.Value)
+	case typ.Is(tftypes.String):
+		var s string
+		if err := dec.Decode(&s); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(typ, s), nil
+	case typ.Is(tftypes.Number):
+		var n json.Number
+		if err := dec.Decode(&n); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		f, _, err := big.ParseFloat(string(n), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(typ, f), nil
+	case typ.Is(tftypes.Bool):
+		var b bool
+		if err := dec.Decode(&b); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(typ, b), nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		var raw []json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		if t, ok := typ.(tftypes.Tuple); ok && len(raw) != len(t.ElementTypes) {
+			return tftypes.Value{}, path.NewErrorf("expected %d tuple elements, got %d", len(t.ElementTypes), len(raw))
+		}
+		elems := make([]tftypes.Value, 0, len(raw))
+		for i, r := range raw {
+			var elemType tftypes.Type
+			switch t := typ.(type) {
+			case tftypes.List:
+				elemType = t.ElementType
+			case tftypes.Set:
+				elemType = t.ElementType
+			case tftypes.Tuple:
+				elemType = t.ElementTypes[i]
+			}
+			elem, err := unmarshalValue(path.WithElementKeyInt(i), elemType, r)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		return newValue(path, typ, elems)
+	case typ.Is(tftypes.Map{}), typ.Is(tftypes.Object{}):
+		var raw map[string]json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		elems := make(map[string]tftypes.Value, len(raw))
+		for key, r := range raw {
+			var elem tftypes.Value
+			var err error
+			if m, ok := typ.(tftypes.Map); ok {
+				elem, err = unmarshalValue(path.WithElementKeyString(key), m.ElementType, r)
+			} else {
+				attrType, ok := typ.(tftypes.Object).AttributeTypes[key]
+				if !ok {
+					return tftypes.Value{}, path.NewErrorf("unexpected attribute %q", key)
+				}
+				elem, err = unmarshalValue(path.WithAttributeName(key), attrType, r)
+			}
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[key] = elem
+		}
+		return newValue(path, typ, elems)
+	}
+	return tftypes.Value{}, path.NewErrorf("unsupported type %s", typ)
+}
+
+// newValue wraps tftypes.NewValue, returning an error instead of panicking
+// when `val` isn't valid for `typ`.
+func newValue(path *tftypes.AttributePath, typ tftypes.Type, val interface{}) (tftypes.Value, error) {
+	if err := tftypes.ValidateValue(typ, val); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	return tftypes.NewValue(typ, val), nil
+}
+
+func isUnknown(data json.RawMessage) bool {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return false
+	}
+	return bytes.Equal(buf.Bytes(), unknownMarker)
+}
@@ -0,0 +1,169 @@
+package tftest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var widgetType = tftypes.Object{
+	AttributeTypes: map[string]tftypes.Type{
+		"id":      tftypes.String,
+		"size":    tftypes.Number,
+		"enabled": tftypes.Bool,
+		"tags":    tftypes.Map{ElementType: tftypes.String},
+		"ports":   tftypes.Set{ElementType: tftypes.Number},
+		"rules": tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"cidr": tftypes.String,
+		}}},
+		"pair":  tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Bool}},
+		"extra": tftypes.DynamicPseudoType,
+	},
+	OptionalAttributes: map[string]struct{}{"extra": {}},
+}
+
+func widget() tftypes.Value {
+	ruleType := widgetType.AttributeTypes["rules"].(tftypes.List).ElementType
+	return tftypes.NewValue(widgetType, map[string]tftypes.Value{
+		"id":      tftypes.NewValue(tftypes.String, "w-1"),
+		"size":    tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+		"enabled": tftypes.NewValue(tftypes.Bool, true),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"env":  tftypes.NewValue(tftypes.String, "prod"),
+			"team": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}),
+		"ports": tftypes.NewValue(tftypes.Set{ElementType: tftypes.Number}, []tftypes.Value{
+			tftypes.NewValue(tftypes.Number, big.NewFloat(443)),
+			tftypes.NewValue(tftypes.Number, big.NewFloat(80)),
+		}),
+		"rules": tftypes.NewValue(tftypes.List{ElementType: ruleType}, []tftypes.Value{
+			tftypes.NewValue(ruleType, map[string]tftypes.Value{
+				"cidr": tftypes.NewValue(tftypes.String, "10.0.0.0/8"),
+			}),
+		}),
+		"pair": tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Bool}}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+			tftypes.NewValue(tftypes.Bool, nil),
+		}),
+		"extra": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "x"),
+		}),
+	})
+}
+
+func TestGolden(t *testing.T) {
+	Golden(t, "widget", widget())
+}
+
+func TestMarshalValueRoundTrip(t *testing.T) {
+	type testCase struct {
+		val tftypes.Value
+	}
+	cases := map[string]testCase{
+		"widget": {
+			val: widget(),
+		},
+		"null": {
+			val: tftypes.NewValue(widgetType, nil),
+		},
+		"unknown": {
+			val: tftypes.NewValue(widgetType, tftypes.UnknownValue),
+		},
+		"unknown-dynamic": {
+			val: tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue),
+		},
+		"large-number": {
+			val: tftypes.NewValue(tftypes.Number, new(big.Float).SetMantExp(big.NewFloat(1), 200)),
+		},
+		"empty-set": {
+			val: tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{}),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			data, err := MarshalValue(tc.val)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := UnmarshalValue(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equal(tc.val) {
+				t.Errorf("expected %s, got %s", tc.val, got)
+			}
+		})
+	}
+}
+
+func TestMarshalValueStable(t *testing.T) {
+	a := tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "b"),
+		tftypes.NewValue(tftypes.String, "a"),
+	})
+	b := tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "a"),
+		tftypes.NewValue(tftypes.String, "b"),
+	})
+	gotA, err := MarshalValue(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	gotB, err := MarshalValue(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(gotA) != string(gotB) {
+		t.Errorf("expected equal sets to marshal the same, got %s and %s", gotA, gotB)
+	}
+}
+
+func TestMarshalValueErrors(t *testing.T) {
+	val := tftypes.NewValue(tftypes.Map{ElementType: tftypes.Bool}, map[string]tftypes.Value{
+		"$unknown": tftypes.NewValue(tftypes.Bool, true),
+	})
+	_, err := MarshalValue(val)
+	expectedErr := "value can't be represented in a golden file, because it's indistinguishable from an unknown value"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestUnmarshalValueErrors(t *testing.T) {
+	type testCase struct {
+		data        string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"missing-type": {
+			data:        `{"value": "a"}`,
+			expectedErr: "missing type",
+		},
+		"unknown-type": {
+			data:        `{"type": "integer", "value": 1}`,
+			expectedErr: `error parsing type: unknown type "integer"`,
+		},
+		"wrong-value": {
+			data:        `{"type": ["list", "string"], "value": [1]}`,
+			expectedErr: "ElementKeyInt(0): json: cannot unmarshal number into Go value of type string",
+		},
+		"unexpected-attribute": {
+			data:        `{"type": ["object", {"id": "string"}], "value": {"name": "a"}}`,
+			expectedErr: `unexpected attribute "name"`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := UnmarshalValue([]byte(tc.data))
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tc.expectedErr)
+			}
+			if err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %q", tc.expectedErr, err.Error())
+			}
+		})
+	}
+}
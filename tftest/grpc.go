@@ -0,0 +1,212 @@
+package tftest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// providerService is the full name of the gRPC service Terraform uses to talk
+// to providers using protocol version 5.
+const providerService = "tfplugin5.Provider"
+
+// GRPCClient is a client for a tfprotov5.ProviderServer served in-process by
+// ServeGRPC.
+//
+// terraform-plugin-go doesn't export the protocol's generated types, so
+// requests and responses are dynamic protobuf messages, with fields named as
+// in the protocol's .proto file, like "type_name". RPCs are named as in the
+// .proto file too, so GetProviderSchema is "GetSchema", ConfigureProvider is
+// "Configure", and StopProvider is "Stop". SetDynamicValue, DynamicValue, and
+// Diagnostics convert the fields most tests need to and from their
+// tfprotov5 equivalents.
+type GRPCClient struct {
+	// Conn is the client's connection to the server.
+	Conn *grpc.ClientConn
+
+	t       testing.TB
+	service protoreflect.ServiceDescriptor
+}
+
+// ServeGRPC serves `srv` over an in-memory connection using the same gRPC
+// server Terraform talks to, so requests and responses go through the same
+// encoding and decoding they would in a real provider, and returns a client
+// connected to it. The server is stopped when the test finishes.
+//
+// Streaming RPCs, like ListResource, aren't served.
+func ServeGRPC(t testing.TB, srv tfprotov5.ProviderServer) *GRPCClient {
+	t.Helper()
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(providerService)
+	if err != nil {
+		t.Fatalf("error finding %s service: %s", providerService, err)
+	}
+	service := desc.(protoreflect.ServiceDescriptor)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	server.RegisterService(serviceDesc(service), tf5server.New("tftest", srv))
+	go func() {
+		_ = server.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		server.Stop()
+		t.Fatalf("error connecting to provider server: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		server.Stop()
+	})
+	return &GRPCClient{Conn: conn, t: t, service: service}
+}
+
+// serviceDesc returns a description of `service` for registering with a
+// grpc.Server, whose handlers call the method of the same name on the
+// registered implementation.
+func serviceDesc(service protoreflect.ServiceDescriptor) *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: string(service.FullName()),
+		HandlerType: (*interface{})(nil),
+	}
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		if method.IsStreamingClient() || method.IsStreamingServer() {
+			continue
+		}
+		name := string(method.Name())
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: name,
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				fn := reflect.ValueOf(srv).MethodByName(name)
+				req := reflect.New(fn.Type().In(1).Elem())
+				if err := dec(req.Interface()); err != nil {
+					return nil, err
+				}
+				out := fn.Call([]reflect.Value{reflect.ValueOf(ctx), req})
+				if err, _ := out[1].Interface().(error); err != nil {
+					return nil, err
+				}
+				return out[0].Interface(), nil
+			},
+		})
+	}
+	return desc
+}
+
+// Request returns an empty request message for the RPC named `rpc`.
+func (c *GRPCClient) Request(rpc string) *dynamicpb.Message {
+	c.t.Helper()
+	return dynamicpb.NewMessage(c.method(rpc).Input())
+}
+
+// Call calls the RPC named `rpc` with `req`, which should have been created
+// with Request, and returns its response.
+func (c *GRPCClient) Call(ctx context.Context, rpc string, req *dynamicpb.Message) (*dynamicpb.Message, error) {
+	c.t.Helper()
+	method := c.method(rpc)
+	resp := dynamicpb.NewMessage(method.Output())
+	err := c.Conn.Invoke(ctx, fmt.Sprintf("/%s/%s", c.service.FullName(), rpc), req, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *GRPCClient) method(rpc string) protoreflect.MethodDescriptor {
+	c.t.Helper()
+	method := c.service.Methods().ByName(protoreflect.Name(rpc))
+	if method == nil {
+		c.t.Fatalf("%s has no RPC named %q", c.service.FullName(), rpc)
+	}
+	return method
+}
+
+// SetDynamicValue sets the field of `msg` named `field` to `val`. It panics
+// if `msg` has no such field.
+func SetDynamicValue(msg *dynamicpb.Message, field string, val *tfprotov5.DynamicValue) {
+	fd := fieldByName(msg, field)
+	dv := dynamicpb.NewMessage(fd.Message())
+	dv.Set(dv.Descriptor().Fields().ByName("msgpack"), protoreflect.ValueOfBytes(val.MsgPack))
+	dv.Set(dv.Descriptor().Fields().ByName("json"), protoreflect.ValueOfBytes(val.JSON))
+	msg.Set(fd, protoreflect.ValueOfMessage(dv))
+}
+
+// DynamicValue returns the field of `msg` named `field`, or nil if it isn't
+// set. It panics if `msg` has no such field.
+func DynamicValue(msg *dynamicpb.Message, field string) *tfprotov5.DynamicValue {
+	fd := fieldByName(msg, field)
+	if !msg.Has(fd) {
+		return nil
+	}
+	dv := msg.Get(fd).Message()
+	return &tfprotov5.DynamicValue{
+		MsgPack: dv.Get(dv.Descriptor().Fields().ByName("msgpack")).Bytes(),
+		JSON:    dv.Get(dv.Descriptor().Fields().ByName("json")).Bytes(),
+	}
+}
+
+// Diagnostics returns the diagnostics in the "diagnostics" field of `msg`. It
+// panics if `msg` has no such field.
+func Diagnostics(msg *dynamicpb.Message) []*tfprotov5.Diagnostic {
+	list := msg.Get(fieldByName(msg, "diagnostics")).List()
+	var diags []*tfprotov5.Diagnostic
+	for i := 0; i < list.Len(); i++ {
+		d := list.Get(i).Message()
+		fields := d.Descriptor().Fields()
+		diag := &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverity(d.Get(fields.ByName("severity")).Enum()),
+			Summary:  d.Get(fields.ByName("summary")).String(),
+			Detail:   d.Get(fields.ByName("detail")).String(),
+		}
+		if attr := fields.ByName("attribute"); d.Has(attr) {
+			diag.Attribute = attributePath(d.Get(attr).Message())
+		}
+		diags = append(diags, diag)
+	}
+	return diags
+}
+
+func attributePath(msg protoreflect.Message) *tftypes.AttributePath {
+	path := tftypes.NewAttributePath()
+	steps := msg.Get(msg.Descriptor().Fields().ByName("steps")).List()
+	for i := 0; i < steps.Len(); i++ {
+		step := steps.Get(i).Message()
+		fields := step.Descriptor().Fields()
+		switch {
+		case step.Has(fields.ByName("attribute_name")):
+			path = path.WithAttributeName(step.Get(fields.ByName("attribute_name")).String())
+		case step.Has(fields.ByName("element_key_string")):
+			path = path.WithElementKeyString(step.Get(fields.ByName("element_key_string")).String())
+		case step.Has(fields.ByName("element_key_int")):
+			path = path.WithElementKeyInt(int(step.Get(fields.ByName("element_key_int")).Int()))
+		}
+	}
+	return path
+}
+
+func fieldByName(msg *dynamicpb.Message, name string) protoreflect.FieldDescriptor {
+	fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if fd == nil {
+		panic(fmt.Sprintf("tftest: %s has no field named %q", msg.Descriptor().FullName(), name))
+	}
+	return fd
+}
@@ -0,0 +1,93 @@
+package tftest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestServeGRPC(t *testing.T) {
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":   tftypes.String,
+		"tags": tftypes.Map{ElementType: tftypes.String},
+	}}
+	state, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "abc"),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	fake := &ProviderServer{
+		ReadResourceFunc: func(_ context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+			return &tfprotov5.ReadResourceResponse{
+				NewState: req.CurrentState,
+				Diagnostics: []*tfprotov5.Diagnostic{{
+					Severity:  tfprotov5.DiagnosticSeverityWarning,
+					Summary:   "Deprecated tag",
+					Detail:    "The tag is deprecated.",
+					Attribute: tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyString("env"),
+				}},
+			}, nil
+		},
+	}
+	client := ServeGRPC(t, fake)
+
+	req := client.Request("ReadResource")
+	req.Set(req.Descriptor().Fields().ByName("type_name"), protoreflect.ValueOfString("test_widget"))
+	SetDynamicValue(req, "current_state", &state)
+	resp, err := client.Call(context.Background(), "ReadResource", req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	calls := fake.Calls("ReadResource")
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	got := calls[0].Request.(*tfprotov5.ReadResourceRequest)
+	if got.TypeName != "test_widget" {
+		t.Errorf("expected type name %q, got %q", "test_widget", got.TypeName)
+	}
+
+	newState, err := DynamicValue(resp, "new_state").Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	oldState, err := state.Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !newState.Equal(oldState) {
+		t.Errorf("expected new state %s, got %s", oldState, newState)
+	}
+
+	expected := []*tfprotov5.Diagnostic{{
+		Severity:  tfprotov5.DiagnosticSeverityWarning,
+		Summary:   "Deprecated tag",
+		Detail:    "The tag is deprecated.",
+		Attribute: tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyString("env"),
+	}}
+	if diff := cmp.Diff(expected, Diagnostics(resp)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestServeGRPCError(t *testing.T) {
+	fake := &ProviderServer{
+		Errors: map[string]error{"GetProviderSchema": errors.New("schema unavailable")},
+	}
+	client := ServeGRPC(t, fake)
+
+	_, err := client.Call(context.Background(), "GetSchema", client.Request("GetSchema"))
+	if err == nil || !strings.Contains(err.Error(), "schema unavailable") {
+		t.Errorf("expected error containing %q, got %v", "schema unavailable", err)
+	}
+}
@@ -0,0 +1,90 @@
+// Command genmocks generates the mock servers in package tftest from the
+// tfprotov5 server interfaces, so they keep up with new RPCs. It writes
+// mock_gen.go in the current directory, and is run by go generate.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+type mock struct {
+	Name      string
+	Interface string
+	RPCs      []string
+}
+
+var mocks = []mock{
+	{Name: "MockProviderServer", Interface: "ProviderServer", RPCs: rpcs((*tfprotov5.ProviderServer)(nil))},
+	{Name: "MockResourceServer", Interface: "ResourceServer", RPCs: rpcs((*tfprotov5.ResourceServer)(nil))},
+	{Name: "MockDataSourceServer", Interface: "DataSourceServer", RPCs: rpcs((*tfprotov5.DataSourceServer)(nil))},
+}
+
+// rpcs returns the names of the methods of the interface `iface` points to.
+func rpcs(iface interface{}) []string {
+	typ := reflect.TypeOf(iface).Elem()
+	var names []string
+	for i := 0; i < typ.NumMethod(); i++ {
+		names = append(names, typ.Method(i).Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var tmpl = template.Must(template.New("").Parse(`// Code generated by genmocks. DO NOT EDIT.
+
+package tftest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+{{ range . }}
+var _ tfprotov5.{{ .Interface }} = &{{ .Name }}{}
+
+// {{ .Name }} is a mock tfprotov5.{{ .Interface }}. See Mock for how
+// calls are checked against its expectations.
+type {{ .Name }} struct {
+	*Mock
+}
+
+// New{{ .Name }} returns a {{ .Name }} with no expectations, which
+// reports unexpected and missing calls to ` + "`t`" + `.
+func New{{ .Name }}(t testing.TB) *{{ .Name }} {
+	return &{{ .Name }}{Mock: newMock(t)}
+}
+{{ $mock := .Name }}{{ range .RPCs }}
+// Expect{{ . }} adds an expected call to {{ . }}.
+func (m *{{ $mock }}) Expect{{ . }}() *ExpectedCall[tfprotov5.{{ . }}Request, tfprotov5.{{ . }}Response] {
+	return expect[tfprotov5.{{ . }}Request, tfprotov5.{{ . }}Response](m.Mock, "{{ . }}")
+}
+
+func (m *{{ $mock }}) {{ . }}(ctx context.Context, req *tfprotov5.{{ . }}Request) (*tfprotov5.{{ . }}Response, error) {
+	m.t.Helper()
+	return handle[tfprotov5.{{ . }}Request, tfprotov5.{{ . }}Response](ctx, m.Mock, "{{ . }}", req)
+}
+{{ end }}{{ end }}`))
+
+func main() {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, mocks); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(fmt.Errorf("error formatting generated code: %w", err))
+	}
+	if err := os.WriteFile("mock_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,181 @@
+package tftest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+//go:generate go run ./internal/genmocks
+
+// Mock holds the expectations set on a mock server, like a
+// MockProviderServer, and checks the calls the server receives against them.
+//
+// Expectations are set with the server's ExpectRPC methods, like
+// ExpectReadResource. Each call is answered by the first expectation set for
+// its RPC that matches its request and hasn't been called as many times as
+// it expects yet. Calls no expectation matches fail the test and return an
+// error. Expectations that weren't called as many times as they expect fail
+// the test when it finishes, or when Verify is called.
+type Mock struct {
+	t testing.TB
+
+	mu           sync.Mutex
+	expectations []expectation
+}
+
+func newMock(t testing.TB) *Mock {
+	m := &Mock{t: t}
+	t.Cleanup(m.Verify)
+	return m
+}
+
+// Verify fails the test if any expectation hasn't been called as many times
+// as it expects.
+func (m *Mock) Verify() {
+	m.t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.expectations {
+		if !e.satisfied() {
+			m.t.Errorf("%s: expected %s, got %d", e, e.expectedCalls(), e.called())
+		}
+	}
+}
+
+// expectation is an *ExpectedCall of any type.
+type expectation interface {
+	fmt.Stringer
+	rpc() string
+	matches(req interface{}) bool
+	called() int
+	call()
+	exhausted() bool
+	satisfied() bool
+	expectedCalls() string
+}
+
+// find returns the expectation that should answer a call to `rpc` with
+// `req`, and records the call, or returns nil and fails the test if there
+// isn't one.
+func (m *Mock) find(rpc string, req interface{}) expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.expectations {
+		if e.rpc() != rpc || e.exhausted() || !e.matches(req) {
+			continue
+		}
+		e.call()
+		return e
+	}
+	m.t.Errorf("unexpected call to %s", rpc)
+	return nil
+}
+
+// ExpectedCall is an expected call to an RPC, which has requests of type
+// `Req` and responses of type `Resp`. By default, it expects to be called
+// once, with any request, and returns an empty response.
+//
+// An ExpectedCall's methods must not be called while the server it was set
+// on is in use.
+type ExpectedCall[Req, Resp any] struct {
+	name     string
+	match    func(*Req) bool
+	fn       func(context.Context, *Req) (*Resp, error)
+	resp     *Resp
+	err      error
+	min, max int
+	calls    int
+}
+
+func expect[Req, Resp any](m *Mock, rpc string) *ExpectedCall[Req, Resp] {
+	e := &ExpectedCall[Req, Resp]{name: rpc, min: 1, max: 1}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// When limits the ExpectedCall to calls whose requests `match` returns true
+// for.
+func (e *ExpectedCall[Req, Resp]) When(match func(req *Req) bool) *ExpectedCall[Req, Resp] {
+	e.match = match
+	return e
+}
+
+// Return sets the response and error the ExpectedCall returns.
+func (e *ExpectedCall[Req, Resp]) Return(resp *Resp, err error) *ExpectedCall[Req, Resp] {
+	e.resp, e.err = resp, err
+	return e
+}
+
+// Do sets a function the ExpectedCall calls to respond, instead of returning
+// a fixed response.
+func (e *ExpectedCall[Req, Resp]) Do(fn func(ctx context.Context, req *Req) (*Resp, error)) *ExpectedCall[Req, Resp] {
+	e.fn = fn
+	return e
+}
+
+// Times sets the number of times the ExpectedCall expects to be called.
+func (e *ExpectedCall[Req, Resp]) Times(n int) *ExpectedCall[Req, Resp] {
+	e.min, e.max = n, n
+	return e
+}
+
+// AnyTimes lets the ExpectedCall be called any number of times, including
+// none.
+func (e *ExpectedCall[Req, Resp]) AnyTimes() *ExpectedCall[Req, Resp] {
+	e.min, e.max = 0, -1
+	return e
+}
+
+func (e *ExpectedCall[Req, Resp]) String() string {
+	if e.match != nil {
+		return e.name + " (with matching request)"
+	}
+	return e.name
+}
+
+func (e *ExpectedCall[Req, Resp]) rpc() string { return e.name }
+
+func (e *ExpectedCall[Req, Resp]) matches(req interface{}) bool {
+	return e.match == nil || e.match(req.(*Req))
+}
+
+func (e *ExpectedCall[Req, Resp]) called() int { return e.calls }
+
+func (e *ExpectedCall[Req, Resp]) call() { e.calls++ }
+
+func (e *ExpectedCall[Req, Resp]) exhausted() bool {
+	return e.max >= 0 && e.calls >= e.max
+}
+
+func (e *ExpectedCall[Req, Resp]) satisfied() bool { return e.calls >= e.min }
+
+func (e *ExpectedCall[Req, Resp]) expectedCalls() string {
+	if e.min == 1 {
+		return "1 call"
+	}
+	return fmt.Sprintf("%d calls", e.min)
+}
+
+func (e *ExpectedCall[Req, Resp]) respond(ctx context.Context, req *Req) (*Resp, error) {
+	if e.fn != nil {
+		return e.fn(ctx, req)
+	}
+	if e.resp == nil && e.err == nil {
+		return new(Resp), nil
+	}
+	return e.resp, e.err
+}
+
+// handle answers a call to `rpc` with `req` using the matching expectation.
+func handle[Req, Resp any](ctx context.Context, m *Mock, rpc string, req *Req) (*Resp, error) {
+	m.t.Helper()
+	e := m.find(rpc, req)
+	if e == nil {
+		return nil, fmt.Errorf("unexpected call to %s", rpc)
+	}
+	return e.(*ExpectedCall[Req, Resp]).respond(ctx, req)
+}
@@ -0,0 +1,392 @@
+// Code generated by genmocks. DO NOT EDIT.
+
+package tftest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+var _ tfprotov5.ProviderServer = &MockProviderServer{}
+
+// MockProviderServer is a mock tfprotov5.ProviderServer. See Mock for how
+// calls are checked against its expectations.
+type MockProviderServer struct {
+	*Mock
+}
+
+// NewMockProviderServer returns a MockProviderServer with no expectations, which
+// reports unexpected and missing calls to `t`.
+func NewMockProviderServer(t testing.TB) *MockProviderServer {
+	return &MockProviderServer{Mock: newMock(t)}
+}
+
+// ExpectApplyResourceChange adds an expected call to ApplyResourceChange.
+func (m *MockProviderServer) ExpectApplyResourceChange() *ExpectedCall[tfprotov5.ApplyResourceChangeRequest, tfprotov5.ApplyResourceChangeResponse] {
+	return expect[tfprotov5.ApplyResourceChangeRequest, tfprotov5.ApplyResourceChangeResponse](m.Mock, "ApplyResourceChange")
+}
+
+func (m *MockProviderServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ApplyResourceChangeRequest, tfprotov5.ApplyResourceChangeResponse](ctx, m.Mock, "ApplyResourceChange", req)
+}
+
+// ExpectCallFunction adds an expected call to CallFunction.
+func (m *MockProviderServer) ExpectCallFunction() *ExpectedCall[tfprotov5.CallFunctionRequest, tfprotov5.CallFunctionResponse] {
+	return expect[tfprotov5.CallFunctionRequest, tfprotov5.CallFunctionResponse](m.Mock, "CallFunction")
+}
+
+func (m *MockProviderServer) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.CallFunctionRequest, tfprotov5.CallFunctionResponse](ctx, m.Mock, "CallFunction", req)
+}
+
+// ExpectCloseEphemeralResource adds an expected call to CloseEphemeralResource.
+func (m *MockProviderServer) ExpectCloseEphemeralResource() *ExpectedCall[tfprotov5.CloseEphemeralResourceRequest, tfprotov5.CloseEphemeralResourceResponse] {
+	return expect[tfprotov5.CloseEphemeralResourceRequest, tfprotov5.CloseEphemeralResourceResponse](m.Mock, "CloseEphemeralResource")
+}
+
+func (m *MockProviderServer) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.CloseEphemeralResourceRequest, tfprotov5.CloseEphemeralResourceResponse](ctx, m.Mock, "CloseEphemeralResource", req)
+}
+
+// ExpectConfigureProvider adds an expected call to ConfigureProvider.
+func (m *MockProviderServer) ExpectConfigureProvider() *ExpectedCall[tfprotov5.ConfigureProviderRequest, tfprotov5.ConfigureProviderResponse] {
+	return expect[tfprotov5.ConfigureProviderRequest, tfprotov5.ConfigureProviderResponse](m.Mock, "ConfigureProvider")
+}
+
+func (m *MockProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ConfigureProviderRequest, tfprotov5.ConfigureProviderResponse](ctx, m.Mock, "ConfigureProvider", req)
+}
+
+// ExpectGenerateResourceConfig adds an expected call to GenerateResourceConfig.
+func (m *MockProviderServer) ExpectGenerateResourceConfig() *ExpectedCall[tfprotov5.GenerateResourceConfigRequest, tfprotov5.GenerateResourceConfigResponse] {
+	return expect[tfprotov5.GenerateResourceConfigRequest, tfprotov5.GenerateResourceConfigResponse](m.Mock, "GenerateResourceConfig")
+}
+
+func (m *MockProviderServer) GenerateResourceConfig(ctx context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.GenerateResourceConfigRequest, tfprotov5.GenerateResourceConfigResponse](ctx, m.Mock, "GenerateResourceConfig", req)
+}
+
+// ExpectGetFunctions adds an expected call to GetFunctions.
+func (m *MockProviderServer) ExpectGetFunctions() *ExpectedCall[tfprotov5.GetFunctionsRequest, tfprotov5.GetFunctionsResponse] {
+	return expect[tfprotov5.GetFunctionsRequest, tfprotov5.GetFunctionsResponse](m.Mock, "GetFunctions")
+}
+
+func (m *MockProviderServer) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.GetFunctionsRequest, tfprotov5.GetFunctionsResponse](ctx, m.Mock, "GetFunctions", req)
+}
+
+// ExpectGetMetadata adds an expected call to GetMetadata.
+func (m *MockProviderServer) ExpectGetMetadata() *ExpectedCall[tfprotov5.GetMetadataRequest, tfprotov5.GetMetadataResponse] {
+	return expect[tfprotov5.GetMetadataRequest, tfprotov5.GetMetadataResponse](m.Mock, "GetMetadata")
+}
+
+func (m *MockProviderServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.GetMetadataRequest, tfprotov5.GetMetadataResponse](ctx, m.Mock, "GetMetadata", req)
+}
+
+// ExpectGetProviderSchema adds an expected call to GetProviderSchema.
+func (m *MockProviderServer) ExpectGetProviderSchema() *ExpectedCall[tfprotov5.GetProviderSchemaRequest, tfprotov5.GetProviderSchemaResponse] {
+	return expect[tfprotov5.GetProviderSchemaRequest, tfprotov5.GetProviderSchemaResponse](m.Mock, "GetProviderSchema")
+}
+
+func (m *MockProviderServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.GetProviderSchemaRequest, tfprotov5.GetProviderSchemaResponse](ctx, m.Mock, "GetProviderSchema", req)
+}
+
+// ExpectGetResourceIdentitySchemas adds an expected call to GetResourceIdentitySchemas.
+func (m *MockProviderServer) ExpectGetResourceIdentitySchemas() *ExpectedCall[tfprotov5.GetResourceIdentitySchemasRequest, tfprotov5.GetResourceIdentitySchemasResponse] {
+	return expect[tfprotov5.GetResourceIdentitySchemasRequest, tfprotov5.GetResourceIdentitySchemasResponse](m.Mock, "GetResourceIdentitySchemas")
+}
+
+func (m *MockProviderServer) GetResourceIdentitySchemas(ctx context.Context, req *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.GetResourceIdentitySchemasRequest, tfprotov5.GetResourceIdentitySchemasResponse](ctx, m.Mock, "GetResourceIdentitySchemas", req)
+}
+
+// ExpectImportResourceState adds an expected call to ImportResourceState.
+func (m *MockProviderServer) ExpectImportResourceState() *ExpectedCall[tfprotov5.ImportResourceStateRequest, tfprotov5.ImportResourceStateResponse] {
+	return expect[tfprotov5.ImportResourceStateRequest, tfprotov5.ImportResourceStateResponse](m.Mock, "ImportResourceState")
+}
+
+func (m *MockProviderServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ImportResourceStateRequest, tfprotov5.ImportResourceStateResponse](ctx, m.Mock, "ImportResourceState", req)
+}
+
+// ExpectMoveResourceState adds an expected call to MoveResourceState.
+func (m *MockProviderServer) ExpectMoveResourceState() *ExpectedCall[tfprotov5.MoveResourceStateRequest, tfprotov5.MoveResourceStateResponse] {
+	return expect[tfprotov5.MoveResourceStateRequest, tfprotov5.MoveResourceStateResponse](m.Mock, "MoveResourceState")
+}
+
+func (m *MockProviderServer) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.MoveResourceStateRequest, tfprotov5.MoveResourceStateResponse](ctx, m.Mock, "MoveResourceState", req)
+}
+
+// ExpectOpenEphemeralResource adds an expected call to OpenEphemeralResource.
+func (m *MockProviderServer) ExpectOpenEphemeralResource() *ExpectedCall[tfprotov5.OpenEphemeralResourceRequest, tfprotov5.OpenEphemeralResourceResponse] {
+	return expect[tfprotov5.OpenEphemeralResourceRequest, tfprotov5.OpenEphemeralResourceResponse](m.Mock, "OpenEphemeralResource")
+}
+
+func (m *MockProviderServer) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.OpenEphemeralResourceRequest, tfprotov5.OpenEphemeralResourceResponse](ctx, m.Mock, "OpenEphemeralResource", req)
+}
+
+// ExpectPlanResourceChange adds an expected call to PlanResourceChange.
+func (m *MockProviderServer) ExpectPlanResourceChange() *ExpectedCall[tfprotov5.PlanResourceChangeRequest, tfprotov5.PlanResourceChangeResponse] {
+	return expect[tfprotov5.PlanResourceChangeRequest, tfprotov5.PlanResourceChangeResponse](m.Mock, "PlanResourceChange")
+}
+
+func (m *MockProviderServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.PlanResourceChangeRequest, tfprotov5.PlanResourceChangeResponse](ctx, m.Mock, "PlanResourceChange", req)
+}
+
+// ExpectPrepareProviderConfig adds an expected call to PrepareProviderConfig.
+func (m *MockProviderServer) ExpectPrepareProviderConfig() *ExpectedCall[tfprotov5.PrepareProviderConfigRequest, tfprotov5.PrepareProviderConfigResponse] {
+	return expect[tfprotov5.PrepareProviderConfigRequest, tfprotov5.PrepareProviderConfigResponse](m.Mock, "PrepareProviderConfig")
+}
+
+func (m *MockProviderServer) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.PrepareProviderConfigRequest, tfprotov5.PrepareProviderConfigResponse](ctx, m.Mock, "PrepareProviderConfig", req)
+}
+
+// ExpectReadDataSource adds an expected call to ReadDataSource.
+func (m *MockProviderServer) ExpectReadDataSource() *ExpectedCall[tfprotov5.ReadDataSourceRequest, tfprotov5.ReadDataSourceResponse] {
+	return expect[tfprotov5.ReadDataSourceRequest, tfprotov5.ReadDataSourceResponse](m.Mock, "ReadDataSource")
+}
+
+func (m *MockProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ReadDataSourceRequest, tfprotov5.ReadDataSourceResponse](ctx, m.Mock, "ReadDataSource", req)
+}
+
+// ExpectReadResource adds an expected call to ReadResource.
+func (m *MockProviderServer) ExpectReadResource() *ExpectedCall[tfprotov5.ReadResourceRequest, tfprotov5.ReadResourceResponse] {
+	return expect[tfprotov5.ReadResourceRequest, tfprotov5.ReadResourceResponse](m.Mock, "ReadResource")
+}
+
+func (m *MockProviderServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ReadResourceRequest, tfprotov5.ReadResourceResponse](ctx, m.Mock, "ReadResource", req)
+}
+
+// ExpectRenewEphemeralResource adds an expected call to RenewEphemeralResource.
+func (m *MockProviderServer) ExpectRenewEphemeralResource() *ExpectedCall[tfprotov5.RenewEphemeralResourceRequest, tfprotov5.RenewEphemeralResourceResponse] {
+	return expect[tfprotov5.RenewEphemeralResourceRequest, tfprotov5.RenewEphemeralResourceResponse](m.Mock, "RenewEphemeralResource")
+}
+
+func (m *MockProviderServer) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.RenewEphemeralResourceRequest, tfprotov5.RenewEphemeralResourceResponse](ctx, m.Mock, "RenewEphemeralResource", req)
+}
+
+// ExpectStopProvider adds an expected call to StopProvider.
+func (m *MockProviderServer) ExpectStopProvider() *ExpectedCall[tfprotov5.StopProviderRequest, tfprotov5.StopProviderResponse] {
+	return expect[tfprotov5.StopProviderRequest, tfprotov5.StopProviderResponse](m.Mock, "StopProvider")
+}
+
+func (m *MockProviderServer) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.StopProviderRequest, tfprotov5.StopProviderResponse](ctx, m.Mock, "StopProvider", req)
+}
+
+// ExpectUpgradeResourceIdentity adds an expected call to UpgradeResourceIdentity.
+func (m *MockProviderServer) ExpectUpgradeResourceIdentity() *ExpectedCall[tfprotov5.UpgradeResourceIdentityRequest, tfprotov5.UpgradeResourceIdentityResponse] {
+	return expect[tfprotov5.UpgradeResourceIdentityRequest, tfprotov5.UpgradeResourceIdentityResponse](m.Mock, "UpgradeResourceIdentity")
+}
+
+func (m *MockProviderServer) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.UpgradeResourceIdentityRequest, tfprotov5.UpgradeResourceIdentityResponse](ctx, m.Mock, "UpgradeResourceIdentity", req)
+}
+
+// ExpectUpgradeResourceState adds an expected call to UpgradeResourceState.
+func (m *MockProviderServer) ExpectUpgradeResourceState() *ExpectedCall[tfprotov5.UpgradeResourceStateRequest, tfprotov5.UpgradeResourceStateResponse] {
+	return expect[tfprotov5.UpgradeResourceStateRequest, tfprotov5.UpgradeResourceStateResponse](m.Mock, "UpgradeResourceState")
+}
+
+func (m *MockProviderServer) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.UpgradeResourceStateRequest, tfprotov5.UpgradeResourceStateResponse](ctx, m.Mock, "UpgradeResourceState", req)
+}
+
+// ExpectValidateDataSourceConfig adds an expected call to ValidateDataSourceConfig.
+func (m *MockProviderServer) ExpectValidateDataSourceConfig() *ExpectedCall[tfprotov5.ValidateDataSourceConfigRequest, tfprotov5.ValidateDataSourceConfigResponse] {
+	return expect[tfprotov5.ValidateDataSourceConfigRequest, tfprotov5.ValidateDataSourceConfigResponse](m.Mock, "ValidateDataSourceConfig")
+}
+
+func (m *MockProviderServer) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ValidateDataSourceConfigRequest, tfprotov5.ValidateDataSourceConfigResponse](ctx, m.Mock, "ValidateDataSourceConfig", req)
+}
+
+// ExpectValidateEphemeralResourceConfig adds an expected call to ValidateEphemeralResourceConfig.
+func (m *MockProviderServer) ExpectValidateEphemeralResourceConfig() *ExpectedCall[tfprotov5.ValidateEphemeralResourceConfigRequest, tfprotov5.ValidateEphemeralResourceConfigResponse] {
+	return expect[tfprotov5.ValidateEphemeralResourceConfigRequest, tfprotov5.ValidateEphemeralResourceConfigResponse](m.Mock, "ValidateEphemeralResourceConfig")
+}
+
+func (m *MockProviderServer) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ValidateEphemeralResourceConfigRequest, tfprotov5.ValidateEphemeralResourceConfigResponse](ctx, m.Mock, "ValidateEphemeralResourceConfig", req)
+}
+
+// ExpectValidateResourceTypeConfig adds an expected call to ValidateResourceTypeConfig.
+func (m *MockProviderServer) ExpectValidateResourceTypeConfig() *ExpectedCall[tfprotov5.ValidateResourceTypeConfigRequest, tfprotov5.ValidateResourceTypeConfigResponse] {
+	return expect[tfprotov5.ValidateResourceTypeConfigRequest, tfprotov5.ValidateResourceTypeConfigResponse](m.Mock, "ValidateResourceTypeConfig")
+}
+
+func (m *MockProviderServer) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ValidateResourceTypeConfigRequest, tfprotov5.ValidateResourceTypeConfigResponse](ctx, m.Mock, "ValidateResourceTypeConfig", req)
+}
+
+var _ tfprotov5.ResourceServer = &MockResourceServer{}
+
+// MockResourceServer is a mock tfprotov5.ResourceServer. See Mock for how
+// calls are checked against its expectations.
+type MockResourceServer struct {
+	*Mock
+}
+
+// NewMockResourceServer returns a MockResourceServer with no expectations, which
+// reports unexpected and missing calls to `t`.
+func NewMockResourceServer(t testing.TB) *MockResourceServer {
+	return &MockResourceServer{Mock: newMock(t)}
+}
+
+// ExpectApplyResourceChange adds an expected call to ApplyResourceChange.
+func (m *MockResourceServer) ExpectApplyResourceChange() *ExpectedCall[tfprotov5.ApplyResourceChangeRequest, tfprotov5.ApplyResourceChangeResponse] {
+	return expect[tfprotov5.ApplyResourceChangeRequest, tfprotov5.ApplyResourceChangeResponse](m.Mock, "ApplyResourceChange")
+}
+
+func (m *MockResourceServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ApplyResourceChangeRequest, tfprotov5.ApplyResourceChangeResponse](ctx, m.Mock, "ApplyResourceChange", req)
+}
+
+// ExpectGenerateResourceConfig adds an expected call to GenerateResourceConfig.
+func (m *MockResourceServer) ExpectGenerateResourceConfig() *ExpectedCall[tfprotov5.GenerateResourceConfigRequest, tfprotov5.GenerateResourceConfigResponse] {
+	return expect[tfprotov5.GenerateResourceConfigRequest, tfprotov5.GenerateResourceConfigResponse](m.Mock, "GenerateResourceConfig")
+}
+
+func (m *MockResourceServer) GenerateResourceConfig(ctx context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.GenerateResourceConfigRequest, tfprotov5.GenerateResourceConfigResponse](ctx, m.Mock, "GenerateResourceConfig", req)
+}
+
+// ExpectImportResourceState adds an expected call to ImportResourceState.
+func (m *MockResourceServer) ExpectImportResourceState() *ExpectedCall[tfprotov5.ImportResourceStateRequest, tfprotov5.ImportResourceStateResponse] {
+	return expect[tfprotov5.ImportResourceStateRequest, tfprotov5.ImportResourceStateResponse](m.Mock, "ImportResourceState")
+}
+
+func (m *MockResourceServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ImportResourceStateRequest, tfprotov5.ImportResourceStateResponse](ctx, m.Mock, "ImportResourceState", req)
+}
+
+// ExpectMoveResourceState adds an expected call to MoveResourceState.
+func (m *MockResourceServer) ExpectMoveResourceState() *ExpectedCall[tfprotov5.MoveResourceStateRequest, tfprotov5.MoveResourceStateResponse] {
+	return expect[tfprotov5.MoveResourceStateRequest, tfprotov5.MoveResourceStateResponse](m.Mock, "MoveResourceState")
+}
+
+func (m *MockResourceServer) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.MoveResourceStateRequest, tfprotov5.MoveResourceStateResponse](ctx, m.Mock, "MoveResourceState", req)
+}
+
+// ExpectPlanResourceChange adds an expected call to PlanResourceChange.
+func (m *MockResourceServer) ExpectPlanResourceChange() *ExpectedCall[tfprotov5.PlanResourceChangeRequest, tfprotov5.PlanResourceChangeResponse] {
+	return expect[tfprotov5.PlanResourceChangeRequest, tfprotov5.PlanResourceChangeResponse](m.Mock, "PlanResourceChange")
+}
+
+func (m *MockResourceServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.PlanResourceChangeRequest, tfprotov5.PlanResourceChangeResponse](ctx, m.Mock, "PlanResourceChange", req)
+}
+
+// ExpectReadResource adds an expected call to ReadResource.
+func (m *MockResourceServer) ExpectReadResource() *ExpectedCall[tfprotov5.ReadResourceRequest, tfprotov5.ReadResourceResponse] {
+	return expect[tfprotov5.ReadResourceRequest, tfprotov5.ReadResourceResponse](m.Mock, "ReadResource")
+}
+
+func (m *MockResourceServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ReadResourceRequest, tfprotov5.ReadResourceResponse](ctx, m.Mock, "ReadResource", req)
+}
+
+// ExpectUpgradeResourceIdentity adds an expected call to UpgradeResourceIdentity.
+func (m *MockResourceServer) ExpectUpgradeResourceIdentity() *ExpectedCall[tfprotov5.UpgradeResourceIdentityRequest, tfprotov5.UpgradeResourceIdentityResponse] {
+	return expect[tfprotov5.UpgradeResourceIdentityRequest, tfprotov5.UpgradeResourceIdentityResponse](m.Mock, "UpgradeResourceIdentity")
+}
+
+func (m *MockResourceServer) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.UpgradeResourceIdentityRequest, tfprotov5.UpgradeResourceIdentityResponse](ctx, m.Mock, "UpgradeResourceIdentity", req)
+}
+
+// ExpectUpgradeResourceState adds an expected call to UpgradeResourceState.
+func (m *MockResourceServer) ExpectUpgradeResourceState() *ExpectedCall[tfprotov5.UpgradeResourceStateRequest, tfprotov5.UpgradeResourceStateResponse] {
+	return expect[tfprotov5.UpgradeResourceStateRequest, tfprotov5.UpgradeResourceStateResponse](m.Mock, "UpgradeResourceState")
+}
+
+func (m *MockResourceServer) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.UpgradeResourceStateRequest, tfprotov5.UpgradeResourceStateResponse](ctx, m.Mock, "UpgradeResourceState", req)
+}
+
+// ExpectValidateResourceTypeConfig adds an expected call to ValidateResourceTypeConfig.
+func (m *MockResourceServer) ExpectValidateResourceTypeConfig() *ExpectedCall[tfprotov5.ValidateResourceTypeConfigRequest, tfprotov5.ValidateResourceTypeConfigResponse] {
+	return expect[tfprotov5.ValidateResourceTypeConfigRequest, tfprotov5.ValidateResourceTypeConfigResponse](m.Mock, "ValidateResourceTypeConfig")
+}
+
+func (m *MockResourceServer) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ValidateResourceTypeConfigRequest, tfprotov5.ValidateResourceTypeConfigResponse](ctx, m.Mock, "ValidateResourceTypeConfig", req)
+}
+
+var _ tfprotov5.DataSourceServer = &MockDataSourceServer{}
+
+// MockDataSourceServer is a mock tfprotov5.DataSourceServer. See Mock for how
+// calls are checked against its expectations.
+type MockDataSourceServer struct {
+	*Mock
+}
+
+// NewMockDataSourceServer returns a MockDataSourceServer with no expectations, which
+// reports unexpected and missing calls to `t`.
+func NewMockDataSourceServer(t testing.TB) *MockDataSourceServer {
+	return &MockDataSourceServer{Mock: newMock(t)}
+}
+
+// ExpectReadDataSource adds an expected call to ReadDataSource.
+func (m *MockDataSourceServer) ExpectReadDataSource() *ExpectedCall[tfprotov5.ReadDataSourceRequest, tfprotov5.ReadDataSourceResponse] {
+	return expect[tfprotov5.ReadDataSourceRequest, tfprotov5.ReadDataSourceResponse](m.Mock, "ReadDataSource")
+}
+
+func (m *MockDataSourceServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ReadDataSourceRequest, tfprotov5.ReadDataSourceResponse](ctx, m.Mock, "ReadDataSource", req)
+}
+
+// ExpectValidateDataSourceConfig adds an expected call to ValidateDataSourceConfig.
+func (m *MockDataSourceServer) ExpectValidateDataSourceConfig() *ExpectedCall[tfprotov5.ValidateDataSourceConfigRequest, tfprotov5.ValidateDataSourceConfigResponse] {
+	return expect[tfprotov5.ValidateDataSourceConfigRequest, tfprotov5.ValidateDataSourceConfigResponse](m.Mock, "ValidateDataSourceConfig")
+}
+
+func (m *MockDataSourceServer) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	m.t.Helper()
+	return handle[tfprotov5.ValidateDataSourceConfigRequest, tfprotov5.ValidateDataSourceConfigResponse](ctx, m.Mock, "ValidateDataSourceConfig", req)
+}
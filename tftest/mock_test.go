@@ -0,0 +1,86 @@
+package tftest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+func TestMockProviderServer(t *testing.T) {
+	ctx := context.Background()
+	m := NewMockProviderServer(t)
+	m.ExpectReadResource().
+		When(func(req *tfprotov5.ReadResourceRequest) bool { return req.TypeName == "test_a" }).
+		Return(&tfprotov5.ReadResourceResponse{Private: []byte("a")}, nil).
+		Times(2)
+	m.ExpectReadResource().
+		Do(func(_ context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+			return &tfprotov5.ReadResourceResponse{Private: []byte(req.TypeName)}, nil
+		})
+	m.ExpectApplyResourceChange().Return(nil, errors.New("connection reset"))
+	m.ExpectStopProvider().AnyTimes()
+
+	var got []string
+	for _, typ := range []string{"test_a", "test_b", "test_a"} {
+		resp, err := m.ReadResource(ctx, &tfprotov5.ReadResourceRequest{TypeName: typ})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		got = append(got, string(resp.Private))
+	}
+	if diff := cmp.Diff([]string{"a", "test_b", "a"}, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	_, err := m.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{})
+	if err == nil || err.Error() != "connection reset" {
+		t.Errorf("expected error %q, got %v", "connection reset", err)
+	}
+}
+
+func TestMockUnexpectedCalls(t *testing.T) {
+	ctx := context.Background()
+	r := &recorder{}
+	t.Run("mock", func(t *testing.T) {
+		r.TB = t
+		m := NewMockResourceServer(r)
+		m.ExpectPlanResourceChange().Times(2)
+		m.ExpectReadResource().When(func(req *tfprotov5.ReadResourceRequest) bool {
+			return req.TypeName == "test_a"
+		})
+		m.ExpectImportResourceState()
+
+		if _, err := m.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		_, err := m.ReadResource(ctx, &tfprotov5.ReadResourceRequest{TypeName: "test_b"})
+		if err == nil || err.Error() != "unexpected call to ReadResource" {
+			t.Errorf("expected error %q, got %v", "unexpected call to ReadResource", err)
+		}
+	})
+	expected := []string{
+		"unexpected call to ReadResource",
+		"PlanResourceChange: expected 2 calls, got 1",
+		"ReadResource (with matching request): expected 1 call, got 0",
+		"ImportResourceState: expected 1 call, got 0",
+	}
+	if diff := cmp.Diff(expected, r.errors); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestMockDataSourceServer(t *testing.T) {
+	m := NewMockDataSourceServer(t)
+	m.ExpectReadDataSource()
+	resp, err := m.ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(&tfprotov5.ReadDataSourceResponse{}, resp); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	m.Verify()
+}
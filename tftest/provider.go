@@ -0,0 +1,332 @@
+// Package tftest provides helpers for testing providers, and the packages
+// used to build them, without running Terraform.
+package tftest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+var _ tfprotov5.ProviderServer = &ProviderServer{}
+
+// Call is an RPC received by a ProviderServer.
+type Call struct {
+	// RPC is the name of the RPC, like "ReadResource".
+	RPC string
+
+	// Request is the request the RPC was called with, like a
+	// *tfprotov5.ReadResourceRequest.
+	Request interface{}
+}
+
+// ProviderServer is a fake tfprotov5.ProviderServer for testing code that
+// wraps or routes to one, like middleware. Each RPC calls the matching
+// function field, like ReadResourceFunc, if it's set, and returns an empty
+// response otherwise. Every call is recorded, and can be retrieved with
+// Calls.
+//
+// The zero value is ready to use, and a ProviderServer is safe to use
+// concurrently, though its fields must not be changed while it's in use.
+type ProviderServer struct {
+	// Errors maps RPC names to errors they return instead of a response,
+	// for testing how callers handle errors from the gRPC layer.
+	Errors map[string]error
+
+	GetMetadataFunc                     func(context.Context, *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error)
+	GetProviderSchemaFunc               func(context.Context, *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error)
+	GetResourceIdentitySchemasFunc      func(context.Context, *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error)
+	PrepareProviderConfigFunc           func(context.Context, *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error)
+	ConfigureProviderFunc               func(context.Context, *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error)
+	StopProviderFunc                    func(context.Context, *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error)
+	ValidateResourceTypeConfigFunc      func(context.Context, *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error)
+	UpgradeResourceStateFunc            func(context.Context, *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error)
+	ReadResourceFunc                    func(context.Context, *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error)
+	PlanResourceChangeFunc              func(context.Context, *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error)
+	ApplyResourceChangeFunc             func(context.Context, *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error)
+	ImportResourceStateFunc             func(context.Context, *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error)
+	MoveResourceStateFunc               func(context.Context, *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error)
+	UpgradeResourceIdentityFunc         func(context.Context, *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error)
+	GenerateResourceConfigFunc          func(context.Context, *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error)
+	ValidateDataSourceConfigFunc        func(context.Context, *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error)
+	ReadDataSourceFunc                  func(context.Context, *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error)
+	CallFunctionFunc                    func(context.Context, *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error)
+	GetFunctionsFunc                    func(context.Context, *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error)
+	ValidateEphemeralResourceConfigFunc func(context.Context, *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error)
+	OpenEphemeralResourceFunc           func(context.Context, *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error)
+	RenewEphemeralResourceFunc          func(context.Context, *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error)
+	CloseEphemeralResourceFunc          func(context.Context, *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns the calls the ProviderServer has received, in order. If
+// `rpcs` are given, only calls to those RPCs are returned.
+func (p *ProviderServer) Calls(rpcs ...string) []Call {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var calls []Call
+	for _, c := range p.calls {
+		if len(rpcs) == 0 || contains(rpcs, c.RPC) {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the calls the ProviderServer has received.
+func (p *ProviderServer) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = nil
+}
+
+// record records a call to `rpc`, and returns the error injected for it, if
+// any.
+func (p *ProviderServer) record(rpc string, req interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, Call{RPC: rpc, Request: req})
+	return p.Errors[rpc]
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ProviderServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	if err := p.record("GetMetadata", req); err != nil {
+		return nil, err
+	}
+	if p.GetMetadataFunc != nil {
+		return p.GetMetadataFunc(ctx, req)
+	}
+	return &tfprotov5.GetMetadataResponse{}, nil
+}
+
+func (p *ProviderServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	if err := p.record("GetProviderSchema", req); err != nil {
+		return nil, err
+	}
+	if p.GetProviderSchemaFunc != nil {
+		return p.GetProviderSchemaFunc(ctx, req)
+	}
+	return &tfprotov5.GetProviderSchemaResponse{}, nil
+}
+
+func (p *ProviderServer) GetResourceIdentitySchemas(ctx context.Context, req *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	if err := p.record("GetResourceIdentitySchemas", req); err != nil {
+		return nil, err
+	}
+	if p.GetResourceIdentitySchemasFunc != nil {
+		return p.GetResourceIdentitySchemasFunc(ctx, req)
+	}
+	return &tfprotov5.GetResourceIdentitySchemasResponse{}, nil
+}
+
+func (p *ProviderServer) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	if err := p.record("PrepareProviderConfig", req); err != nil {
+		return nil, err
+	}
+	if p.PrepareProviderConfigFunc != nil {
+		return p.PrepareProviderConfigFunc(ctx, req)
+	}
+	return &tfprotov5.PrepareProviderConfigResponse{}, nil
+}
+
+func (p *ProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	if err := p.record("ConfigureProvider", req); err != nil {
+		return nil, err
+	}
+	if p.ConfigureProviderFunc != nil {
+		return p.ConfigureProviderFunc(ctx, req)
+	}
+	return &tfprotov5.ConfigureProviderResponse{}, nil
+}
+
+func (p *ProviderServer) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	if err := p.record("StopProvider", req); err != nil {
+		return nil, err
+	}
+	if p.StopProviderFunc != nil {
+		return p.StopProviderFunc(ctx, req)
+	}
+	return &tfprotov5.StopProviderResponse{}, nil
+}
+
+func (p *ProviderServer) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	if err := p.record("ValidateResourceTypeConfig", req); err != nil {
+		return nil, err
+	}
+	if p.ValidateResourceTypeConfigFunc != nil {
+		return p.ValidateResourceTypeConfigFunc(ctx, req)
+	}
+	return &tfprotov5.ValidateResourceTypeConfigResponse{}, nil
+}
+
+func (p *ProviderServer) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	if err := p.record("UpgradeResourceState", req); err != nil {
+		return nil, err
+	}
+	if p.UpgradeResourceStateFunc != nil {
+		return p.UpgradeResourceStateFunc(ctx, req)
+	}
+	return &tfprotov5.UpgradeResourceStateResponse{}, nil
+}
+
+func (p *ProviderServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	if err := p.record("ReadResource", req); err != nil {
+		return nil, err
+	}
+	if p.ReadResourceFunc != nil {
+		return p.ReadResourceFunc(ctx, req)
+	}
+	return &tfprotov5.ReadResourceResponse{}, nil
+}
+
+func (p *ProviderServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	if err := p.record("PlanResourceChange", req); err != nil {
+		return nil, err
+	}
+	if p.PlanResourceChangeFunc != nil {
+		return p.PlanResourceChangeFunc(ctx, req)
+	}
+	return &tfprotov5.PlanResourceChangeResponse{}, nil
+}
+
+func (p *ProviderServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	if err := p.record("ApplyResourceChange", req); err != nil {
+		return nil, err
+	}
+	if p.ApplyResourceChangeFunc != nil {
+		return p.ApplyResourceChangeFunc(ctx, req)
+	}
+	return &tfprotov5.ApplyResourceChangeResponse{}, nil
+}
+
+func (p *ProviderServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	if err := p.record("ImportResourceState", req); err != nil {
+		return nil, err
+	}
+	if p.ImportResourceStateFunc != nil {
+		return p.ImportResourceStateFunc(ctx, req)
+	}
+	return &tfprotov5.ImportResourceStateResponse{}, nil
+}
+
+func (p *ProviderServer) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	if err := p.record("MoveResourceState", req); err != nil {
+		return nil, err
+	}
+	if p.MoveResourceStateFunc != nil {
+		return p.MoveResourceStateFunc(ctx, req)
+	}
+	return &tfprotov5.MoveResourceStateResponse{}, nil
+}
+
+func (p *ProviderServer) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	if err := p.record("UpgradeResourceIdentity", req); err != nil {
+		return nil, err
+	}
+	if p.UpgradeResourceIdentityFunc != nil {
+		return p.UpgradeResourceIdentityFunc(ctx, req)
+	}
+	return &tfprotov5.UpgradeResourceIdentityResponse{}, nil
+}
+
+func (p *ProviderServer) GenerateResourceConfig(ctx context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	if err := p.record("GenerateResourceConfig", req); err != nil {
+		return nil, err
+	}
+	if p.GenerateResourceConfigFunc != nil {
+		return p.GenerateResourceConfigFunc(ctx, req)
+	}
+	return &tfprotov5.GenerateResourceConfigResponse{}, nil
+}
+
+func (p *ProviderServer) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	if err := p.record("ValidateDataSourceConfig", req); err != nil {
+		return nil, err
+	}
+	if p.ValidateDataSourceConfigFunc != nil {
+		return p.ValidateDataSourceConfigFunc(ctx, req)
+	}
+	return &tfprotov5.ValidateDataSourceConfigResponse{}, nil
+}
+
+func (p *ProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	if err := p.record("ReadDataSource", req); err != nil {
+		return nil, err
+	}
+	if p.ReadDataSourceFunc != nil {
+		return p.ReadDataSourceFunc(ctx, req)
+	}
+	return &tfprotov5.ReadDataSourceResponse{}, nil
+}
+
+func (p *ProviderServer) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	if err := p.record("CallFunction", req); err != nil {
+		return nil, err
+	}
+	if p.CallFunctionFunc != nil {
+		return p.CallFunctionFunc(ctx, req)
+	}
+	return &tfprotov5.CallFunctionResponse{}, nil
+}
+
+func (p *ProviderServer) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	if err := p.record("GetFunctions", req); err != nil {
+		return nil, err
+	}
+	if p.GetFunctionsFunc != nil {
+		return p.GetFunctionsFunc(ctx, req)
+	}
+	return &tfprotov5.GetFunctionsResponse{}, nil
+}
+
+func (p *ProviderServer) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	if err := p.record("ValidateEphemeralResourceConfig", req); err != nil {
+		return nil, err
+	}
+	if p.ValidateEphemeralResourceConfigFunc != nil {
+		return p.ValidateEphemeralResourceConfigFunc(ctx, req)
+	}
+	return &tfprotov5.ValidateEphemeralResourceConfigResponse{}, nil
+}
+
+func (p *ProviderServer) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	if err := p.record("OpenEphemeralResource", req); err != nil {
+		return nil, err
+	}
+	if p.OpenEphemeralResourceFunc != nil {
+		return p.OpenEphemeralResourceFunc(ctx, req)
+	}
+	return &tfprotov5.OpenEphemeralResourceResponse{}, nil
+}
+
+func (p *ProviderServer) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	if err := p.record("RenewEphemeralResource", req); err != nil {
+		return nil, err
+	}
+	if p.RenewEphemeralResourceFunc != nil {
+		return p.RenewEphemeralResourceFunc(ctx, req)
+	}
+	return &tfprotov5.RenewEphemeralResourceResponse{}, nil
+}
+
+func (p *ProviderServer) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	if err := p.record("CloseEphemeralResource", req); err != nil {
+		return nil, err
+	}
+	if p.CloseEphemeralResourceFunc != nil {
+		return p.CloseEphemeralResourceFunc(ctx, req)
+	}
+	return &tfprotov5.CloseEphemeralResourceResponse{}, nil
+}
@@ -0,0 +1,119 @@
+package tftest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+func TestProviderServerDefaults(t *testing.T) {
+	var p ProviderServer
+	resp, err := p.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{TypeName: "test_widget"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(&tfprotov5.ReadResourceResponse{}, resp); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestProviderServerFunc(t *testing.T) {
+	p := &ProviderServer{
+		ReadDataSourceFunc: func(_ context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+			return &tfprotov5.ReadDataSourceResponse{
+				Diagnostics: []*tfprotov5.Diagnostic{{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  req.TypeName,
+				}},
+			}, nil
+		},
+	}
+	resp, err := p.ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{TypeName: "test_thing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := &tfprotov5.ReadDataSourceResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  "test_thing",
+		}},
+	}
+	if diff := cmp.Diff(expected, resp); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestProviderServerErrors(t *testing.T) {
+	called := false
+	p := &ProviderServer{
+		Errors: map[string]error{"ApplyResourceChange": errors.New("connection reset")},
+		ApplyResourceChangeFunc: func(context.Context, *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+			called = true
+			return nil, nil
+		},
+	}
+	resp, err := p.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{})
+	if err == nil || err.Error() != "connection reset" {
+		t.Errorf("expected error %q, got %v", "connection reset", err)
+	}
+	if resp != nil {
+		t.Errorf("expected no response, got %v", resp)
+	}
+	if called {
+		t.Error("expected ApplyResourceChangeFunc not to be called")
+	}
+	if got := len(p.Calls("ApplyResourceChange")); got != 1 {
+		t.Errorf("expected the call to be recorded, got %d calls", got)
+	}
+}
+
+func TestProviderServerCalls(t *testing.T) {
+	var p ProviderServer
+	ctx := context.Background()
+	getSchema := &tfprotov5.GetProviderSchemaRequest{}
+	read := &tfprotov5.ReadResourceRequest{TypeName: "test_widget"}
+	stop := &tfprotov5.StopProviderRequest{}
+	_, _ = p.GetProviderSchema(ctx, getSchema)
+	_, _ = p.ReadResource(ctx, read)
+	_, _ = p.StopProvider(ctx, stop)
+
+	type testCase struct {
+		rpcs     []string
+		expected []Call
+	}
+	cases := map[string]testCase{
+		"all": {
+			expected: []Call{
+				{RPC: "GetProviderSchema", Request: getSchema},
+				{RPC: "ReadResource", Request: read},
+				{RPC: "StopProvider", Request: stop},
+			},
+		},
+		"filtered": {
+			rpcs: []string{"StopProvider", "ReadResource"},
+			expected: []Call{
+				{RPC: "ReadResource", Request: read},
+				{RPC: "StopProvider", Request: stop},
+			},
+		},
+		"none": {
+			rpcs: []string{"CallFunction"},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, p.Calls(tc.rpcs...)); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+
+	p.Reset()
+	if got := p.Calls(); len(got) != 0 {
+		t.Errorf("expected no calls after Reset, got %d", len(got))
+	}
+}
@@ -0,0 +1,245 @@
+package tftest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	attributePathType = reflect.TypeOf(&tftypes.AttributePath{})
+	typeType          = reflect.TypeOf((*tftypes.Type)(nil)).Elem()
+	valueType         = reflect.TypeOf(tftypes.Value{})
+	timeType          = reflect.TypeOf(time.Time{})
+	bytesType         = reflect.TypeOf([]byte(nil))
+)
+
+// encodeRecord returns the JSON representation of `v`, a request or
+// response of one of the protocol's RPCs, or part of one, for storing in a
+// Cassette. Structs are encoded as objects keyed by field name, omitting
+// fields with zero values, and tftypes.Types, tftypes.Values, and
+// *tftypes.AttributePaths are encoded as in golden files.
+func encodeRecord(v reflect.Value) (json.RawMessage, error) {
+	if !v.IsValid() {
+		return json.RawMessage("null"), nil
+	}
+	switch v.Type() {
+	case attributePathType:
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		return encodeAttributePath(v.Interface().(*tftypes.AttributePath))
+	case typeType:
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		return marshalType(v.Interface().(tftypes.Type))
+	case valueType:
+		return MarshalValue(v.Interface().(tftypes.Value))
+	case timeType, bytesType:
+		return json.Marshal(v.Interface())
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		return encodeRecord(v.Elem())
+	case reflect.Struct:
+		fields := map[string]json.RawMessage{}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			data, err := encodeRecord(v.Field(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			fields[f.Name] = data
+		}
+		return json.Marshal(fields)
+	case reflect.Slice:
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		elems := make([]json.RawMessage, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			data, err := encodeRecord(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			elems = append(elems, data)
+		}
+		return json.Marshal(elems)
+	case reflect.Map:
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		elems := make(map[string]json.RawMessage, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			data, err := encodeRecord(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("[%q]: %w", iter.Key().String(), err)
+			}
+			elems[iter.Key().String()] = data
+		}
+		return json.Marshal(elems)
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return json.Marshal(v.Interface())
+	}
+	return nil, fmt.Errorf("can't record values of type %s", v.Type())
+}
+
+// decodeRecord decodes `data`, produced by encodeRecord, into `v`, which
+// must be settable.
+func decodeRecord(data json.RawMessage, v reflect.Value) error {
+	if string(data) == "null" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	switch v.Type() {
+	case attributePathType:
+		path, err := decodeAttributePath(data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(path))
+		return nil
+	case typeType:
+		typ, err := unmarshalType(data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(typ))
+		return nil
+	case valueType:
+		val, err := UnmarshalValue(data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(val))
+		return nil
+	case timeType, bytesType:
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeRecord(data, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		for name, fd := range fields {
+			f, ok := v.Type().FieldByName(name)
+			if !ok || !f.IsExported() {
+				return fmt.Errorf("%s has no field %s", v.Type(), name)
+			}
+			if err := decodeRecord(fd, v.FieldByIndex(f.Index)); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		return nil
+	case reflect.Slice:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, e := range elems {
+			if err := decodeRecord(e, s.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Map:
+		var elems map[string]json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(elems))
+		for k, e := range elems {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeRecord(e, elem); err != nil {
+				return fmt.Errorf("[%q]: %w", k, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+		return nil
+	}
+	return json.Unmarshal(data, v.Addr().Interface())
+}
+
+// recordedStep is the JSON representation of a step in an attribute path.
+type recordedStep struct {
+	AttributeName    *string         `json:"attribute_name,omitempty"`
+	ElementKeyString *string         `json:"element_key_string,omitempty"`
+	ElementKeyInt    *int64          `json:"element_key_int,omitempty"`
+	ElementKeyValue  json.RawMessage `json:"element_key_value,omitempty"`
+}
+
+func encodeAttributePath(path *tftypes.AttributePath) (json.RawMessage, error) {
+	steps := []recordedStep{}
+	for _, step := range path.Steps() {
+		switch s := step.(type) {
+		case tftypes.AttributeName:
+			name := string(s)
+			steps = append(steps, recordedStep{AttributeName: &name})
+		case tftypes.ElementKeyString:
+			key := string(s)
+			steps = append(steps, recordedStep{ElementKeyString: &key})
+		case tftypes.ElementKeyInt:
+			key := int64(s)
+			steps = append(steps, recordedStep{ElementKeyInt: &key})
+		case tftypes.ElementKeyValue:
+			data, err := MarshalValue(tftypes.Value(s))
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, recordedStep{ElementKeyValue: data})
+		}
+	}
+	return json.Marshal(steps)
+}
+
+func decodeAttributePath(data json.RawMessage) (*tftypes.AttributePath, error) {
+	var steps []recordedStep
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return nil, err
+	}
+	path := tftypes.NewAttributePath()
+	for _, step := range steps {
+		switch {
+		case step.AttributeName != nil:
+			path = path.WithAttributeName(*step.AttributeName)
+		case step.ElementKeyString != nil:
+			path = path.WithElementKeyString(*step.ElementKeyString)
+		case step.ElementKeyInt != nil:
+			path = path.WithElementKeyInt(int(*step.ElementKeyInt))
+		case step.ElementKeyValue != nil:
+			val, err := UnmarshalValue(step.ElementKeyValue)
+			if err != nil {
+				return nil, err
+			}
+			path = path.WithElementKeyValue(val)
+		default:
+			return nil, fmt.Errorf("invalid attribute path step")
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,334 @@
+package tftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfdiags"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Redacted replaces the string values Recorders redact from cassettes.
+// Redacted values of other types are replaced with null.
+const Redacted = "REDACTED"
+
+var (
+	dynamicValueType = reflect.TypeOf(&tfprotov5.DynamicValue{})
+	rawStateType     = reflect.TypeOf(&tfprotov5.RawState{})
+)
+
+// RedactFunc returns `record`, the request or response of a call to `rpc`
+// encoded as it's stored in a Cassette, with any values that shouldn't be
+// written to the cassette removed.
+//
+// Replayers call it with the requests they receive before matching them to
+// the recorded ones, so it must redact requests the same way every time.
+type RedactFunc func(rpc string, record json.RawMessage) (json.RawMessage, error)
+
+// CassetteOption configures a Recorder or Replayer.
+type CassetteOption func(*redactor)
+
+// WithRedact adds `f` to the functions that redact requests and responses
+// before they're recorded, for values the provider's schema doesn't mark as
+// sensitive, like private state, identities, or diagnostics. Functions are
+// called in the order they're added, after the default redaction.
+func WithRedact(f RedactFunc) CassetteOption {
+	return func(x *redactor) {
+		x.funcs = append(x.funcs, f)
+	}
+}
+
+// redactor redacts the requests and responses of RPCs. By default, every
+// value of the provider's configuration is redacted, as are the attributes
+// the provider's schema marks as sensitive, wherever they appear.
+type redactor struct {
+	funcs []RedactFunc
+}
+
+func newRedactor(opts []CassetteOption) redactor {
+	var x redactor
+	for _, opt := range opts {
+		opt(&x)
+	}
+	return x
+}
+
+// encode returns the redacted JSON representation of `msg`, the request
+// `req` or its response, of a call to `rpc`, for storing in a Cassette.
+// `schema` returns the provider's schema, and is only called if `msg` has
+// values that may need redacting. If `schema` is nil, only the RedactFuncs
+// are applied.
+func (x redactor) encode(rpc string, req, msg interface{}, schema func() (*tfprotov5.GetProviderSchemaResponse, error)) (json.RawMessage, error) {
+	v := reflect.ValueOf(msg)
+	if schema != nil {
+		var err error
+		v, err = redactMessage(rpc, typeNameOf(reflect.ValueOf(req)), v, schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := encodeRecord(v)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range x.funcs {
+		data, err = f(rpc, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// redactMessage returns a copy of `v`, a pointer to the request or response
+// of a call to `rpc` for `typeName`, or to part of one, with its
+// DynamicValues and RawStates redacted. Identities aren't redacted, as
+// they're needed to tell resources apart.
+func redactMessage(rpc, typeName string, v reflect.Value, schema func() (*tfprotov5.GetProviderSchemaResponse, error)) (reflect.Value, error) {
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return v, nil
+	}
+	if name := typeNameOf(v); name != "" {
+		typeName = name
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	for i := 0; i < cp.Elem().NumField(); i++ {
+		f := cp.Elem().Field(i)
+		name := cp.Elem().Type().Field(i).Name
+		if !cp.Elem().Type().Field(i).IsExported() || f.IsZero() || strings.Contains(name, "Identity") {
+			continue
+		}
+		switch {
+		case f.Type() == dynamicValueType:
+			dv, err := redactDynamicValue(rpc, typeName, name, f.Interface().(*tfprotov5.DynamicValue), schema)
+			if err != nil {
+				return v, fmt.Errorf("%s: %w", name, err)
+			}
+			f.Set(reflect.ValueOf(dv))
+		case f.Type() == rawStateType:
+			rs, err := redactRawState(rpc, typeName, f.Interface().(*tfprotov5.RawState), schema)
+			if err != nil {
+				return v, fmt.Errorf("%s: %w", name, err)
+			}
+			f.Set(reflect.ValueOf(rs))
+		case f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.Ptr && f.Type().Elem().Elem().Kind() == reflect.Struct:
+			elems := reflect.MakeSlice(f.Type(), f.Len(), f.Len())
+			for j := 0; j < f.Len(); j++ {
+				elem, err := redactMessage(rpc, typeName, f.Index(j), schema)
+				if err != nil {
+					return v, fmt.Errorf("%s[%d]: %w", name, j, err)
+				}
+				elems.Index(j).Set(elem)
+			}
+			f.Set(elems)
+		}
+	}
+	return cp, nil
+}
+
+// typeNameOf returns the name of the resource, data source, or ephemeral
+// resource type `v`, a pointer to a request or part of a response, is for,
+// if any.
+func typeNameOf(v reflect.Value) string {
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+	for _, name := range []string{"TypeName", "TargetTypeName"} {
+		if f := v.Elem().FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+	return ""
+}
+
+// redactDynamicValue returns `dv`, the `field` of a request or response of a
+// call to `rpc` for `typeName`, with the provider's configuration or the
+// sensitive attributes of its schema redacted.
+func redactDynamicValue(rpc, typeName, field string, dv *tfprotov5.DynamicValue, schema func() (*tfprotov5.GetProviderSchemaResponse, error)) (*tfprotov5.DynamicValue, error) {
+	s, all, err := schemaFor(rpc, typeName, field, schema)
+	if err != nil {
+		return nil, err
+	}
+	sensitive := sensitiveNames(s)
+	if !all && len(sensitive) == 0 {
+		return dv, nil
+	}
+	typ := s.ValueType()
+	val, err := dv.Unmarshal(typ)
+	if err != nil {
+		return nil, err
+	}
+	val, err = tftypes.Transform(val, func(path *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if all || isSensitive(attrpath.Names(path), sensitive) {
+			return redactValue(v), nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	redacted, err := tfprotov5.NewDynamicValue(typ, val)
+	if err != nil {
+		return nil, err
+	}
+	return &redacted, nil
+}
+
+// redactRawState returns `rs`, the prior state of a resource of `typeName`
+// sent to `rpc`, with the attributes its schema marks as sensitive redacted.
+// As the state may have been written by another version of the schema, or
+// for another resource type, values are matched by attribute name alone, and
+// the state sent to MoveResourceState is redacted entirely.
+func redactRawState(rpc, typeName string, rs *tfprotov5.RawState, schema func() (*tfprotov5.GetProviderSchemaResponse, error)) (*tfprotov5.RawState, error) {
+	var sensitive [][]string
+	all := rpc == "MoveResourceState"
+	if !all {
+		s, _, err := schemaFor(rpc, typeName, "", schema)
+		if err != nil {
+			return nil, err
+		}
+		sensitive = sensitiveNames(s)
+		if len(sensitive) == 0 {
+			return rs, nil
+		}
+	}
+	redacted := &tfprotov5.RawState{}
+	if rs.JSON != nil {
+		var state interface{}
+		dec := json.NewDecoder(bytes.NewReader(rs.JSON))
+		dec.UseNumber()
+		if err := dec.Decode(&state); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(redactJSON(nil, state, sensitive, all))
+		if err != nil {
+			return nil, err
+		}
+		redacted.JSON = data
+	}
+	if rs.Flatmap != nil {
+		redacted.Flatmap = make(map[string]string, len(rs.Flatmap))
+		for k, v := range rs.Flatmap {
+			if all || isSensitive(flatmapNames(k), sensitive) {
+				v = Redacted
+			}
+			redacted.Flatmap[k] = v
+		}
+	}
+	return redacted, nil
+}
+
+// redactJSON returns `v`, the JSON value of the attribute at `names` in a
+// resource's raw state, with its sensitive values redacted.
+func redactJSON(names []string, v interface{}, sensitive [][]string, all bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			redacted[k] = redactJSON(append(names[:len(names):len(names)], k), elem, sensitive, all)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, elem := range v {
+			redacted[i] = redactJSON(names, elem, sensitive, all)
+		}
+		return redacted
+	case nil:
+		return nil
+	}
+	if !all && !isSensitive(names, sensitive) {
+		return v
+	}
+	if _, ok := v.(string); ok {
+		return Redacted
+	}
+	return nil
+}
+
+// flatmapNames returns the attribute names in the flatmap key `k`, leaving
+// out list indexes and element counts.
+func flatmapNames(k string) []string {
+	var names []string
+	for _, part := range strings.Split(k, ".") {
+		if _, err := strconv.Atoi(part); err == nil || part == "#" || part == "%" {
+			continue
+		}
+		names = append(names, part)
+	}
+	return names
+}
+
+// redactValue returns `v` redacted, if it's a known, non-null primitive
+// value.
+func redactValue(v tftypes.Value) tftypes.Value {
+	if !v.IsKnown() || v.IsNull() {
+		return v
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		return tftypes.NewValue(tftypes.String, Redacted)
+	case v.Type().Is(tftypes.Number), v.Type().Is(tftypes.Bool):
+		return tftypes.NewValue(v.Type(), nil)
+	}
+	return v
+}
+
+// schemaFor returns the schema of the `field` of a request or response of a
+// call to `rpc` for `typeName`, and whether all of its values should be
+// redacted, as they should for the provider's configuration.
+func schemaFor(rpc, typeName, field string, schema func() (*tfprotov5.GetProviderSchemaResponse, error)) (*tfprotov5.Schema, bool, error) {
+	resp, err := schema()
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting provider schema: %w", err)
+	}
+	if resp == nil {
+		return nil, false, fmt.Errorf("no provider schema")
+	}
+	var s *tfprotov5.Schema
+	all := false
+	switch {
+	case rpc == "PrepareProviderConfig" || rpc == "ConfigureProvider":
+		s, all = resp.Provider, true
+	case field == "ProviderMeta":
+		s = resp.ProviderMeta
+	case strings.Contains(rpc, "DataSource"):
+		s = resp.DataSourceSchemas[typeName]
+	case strings.Contains(rpc, "EphemeralResource"):
+		s = resp.EphemeralResourceSchemas[typeName]
+	case typeName != "":
+		s = resp.ResourceSchemas[typeName]
+	}
+	if s == nil {
+		return nil, false, fmt.Errorf("no schema for %s %q, can't redact it", rpc, typeName)
+	}
+	return s, all, nil
+}
+
+// sensitiveNames returns the attribute names of the sensitive attributes
+// in `s`.
+func sensitiveNames(s *tfprotov5.Schema) [][]string {
+	var names [][]string
+	for _, path := range tfdiags.SensitivePaths(s) {
+		names = append(names, attrpath.Names(path))
+	}
+	return names
+}
+
+// isSensitive returns true if `names` is, or is nested in, one of the
+// `sensitive` attributes.
+func isSensitive(names []string, sensitive [][]string) bool {
+	for _, s := range sensitive {
+		if attrpath.HasPrefix(names, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,584 @@
+package tftest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// Interaction is an RPC recorded in a Cassette.
+type Interaction struct {
+	// RPC is the name of the RPC, like "ReadResource".
+	RPC string `json:"rpc"`
+
+	// Request is the request the RPC was called with.
+	Request json.RawMessage `json:"request"`
+
+	// Response is the response the RPC returned, if it didn't return an
+	// error.
+	Response json.RawMessage `json:"response,omitempty"`
+
+	// Error is the error the RPC returned, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Cassette is a recording of the RPCs a provider server received and how it
+// responded to them, made by a Recorder and played back by a Replayer.
+//
+// Requests and responses are stored as JSON, with tftypes.Values and
+// tftypes.Types encoded as in golden files, so cassettes can be reviewed and
+// edited by hand. DynamicValues are stored as they were sent, apart from
+// redacted values.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+
+	// Schema is the provider's schema, the response to GetProviderSchema,
+	// if it was needed to redact the interactions but no GetProviderSchema
+	// call was recorded. Replayers use it to redact the requests they
+	// receive the same way.
+	Schema json.RawMessage `json:"schema,omitempty"`
+}
+
+// LoadCassette reads the Cassette at `path`.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("error parsing cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the Cassette to `path`, creating its directory if needed.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+var _ tfprotov5.ProviderServer = &Recorder{}
+
+// Recorder is a tfprotov5.ProviderServer that passes every RPC through to
+// another tfprotov5.ProviderServer, recording the requests and responses in
+// a Cassette.
+//
+// Before anything is recorded, the provider's configuration and the
+// attributes its schema marks as sensitive are replaced with Redacted, and
+// the requests and responses are passed through any RedactFuncs. If the
+// provider's schema hasn't been requested by the time it's needed, the
+// Recorder requests it itself, without recording the call, and stores it in
+// the Cassette. Values that can't be redacted aren't recorded, and the
+// Cassette returns an error.
+//
+// A Recorder is safe to use concurrently.
+type Recorder struct {
+	srv      tfprotov5.ProviderServer
+	redactor redactor
+
+	schemaMu      sync.Mutex
+	schema        *tfprotov5.GetProviderSchemaResponse
+	schemaErr     error
+	fetchedSchema bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	err      error
+}
+
+// NewRecorder returns a Recorder that records the RPCs `srv` receives.
+func NewRecorder(srv tfprotov5.ProviderServer, opts ...CassetteOption) *Recorder {
+	return &Recorder{srv: srv, redactor: newRedactor(opts)}
+}
+
+// Cassette returns the RPCs recorded so far. It returns an error if any of
+// them couldn't be recorded.
+func (r *Recorder) Cassette() (*Cassette, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	c := &Cassette{
+		Interactions: append([]Interaction(nil), r.cassette.Interactions...),
+	}
+	r.schemaMu.Lock()
+	defer r.schemaMu.Unlock()
+	if r.fetchedSchema && r.schema != nil {
+		schema, err := encodeRecord(reflect.ValueOf(r.schema))
+		if err != nil {
+			return nil, fmt.Errorf("error recording provider schema: %w", err)
+		}
+		c.Schema = schema
+	}
+	return c, nil
+}
+
+// Save writes the RPCs recorded so far to `path`.
+func (r *Recorder) Save(path string) error {
+	c, err := r.Cassette()
+	if err != nil {
+		return err
+	}
+	return c.Save(path)
+}
+
+// providerSchema returns the provider's schema, requesting it from the
+// server the first time it's needed if no GetProviderSchema call has been
+// recorded.
+func (r *Recorder) providerSchema(ctx context.Context) (*tfprotov5.GetProviderSchemaResponse, error) {
+	r.schemaMu.Lock()
+	defer r.schemaMu.Unlock()
+	if r.schema == nil && r.schemaErr == nil {
+		r.schema, r.schemaErr = r.srv.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+		r.fetchedSchema = true
+	}
+	return r.schema, r.schemaErr
+}
+
+// record records a call to `rpc`, redacted. Errors encoding the call are
+// returned by Cassette, rather than to the caller, so recording never
+// changes the behavior of the server.
+func (r *Recorder) record(ctx context.Context, rpc string, req, resp interface{}, respErr error) {
+	if schema, ok := resp.(*tfprotov5.GetProviderSchemaResponse); ok && schema != nil && respErr == nil {
+		r.schemaMu.Lock()
+		if r.schema == nil {
+			r.schema, r.schemaErr = schema, nil
+		}
+		r.schemaMu.Unlock()
+	}
+	schema := func() (*tfprotov5.GetProviderSchemaResponse, error) {
+		return r.providerSchema(ctx)
+	}
+	i := Interaction{RPC: rpc}
+	var err error
+	i.Request, err = r.redactor.encode(rpc, req, req, schema)
+	if err == nil && respErr != nil {
+		i.Error = respErr.Error()
+	} else if err == nil {
+		i.Response, err = r.redactor.encode(rpc, req, resp, schema)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		if r.err == nil {
+			r.err = fmt.Errorf("error recording %s: %w", rpc, err)
+		}
+		return
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+}
+
+var _ tfprotov5.ProviderServer = &Replayer{}
+
+// Replayer is a tfprotov5.ProviderServer that responds to RPCs with the
+// responses recorded in a Cassette.
+//
+// Each RPC is answered by the first interaction in the Cassette that hasn't
+// been replayed yet and was recorded for the same RPC with an identical
+// request, so the same requests always get the same responses, regardless of
+// the order concurrent RPCs arrive in. RPCs with no matching interaction
+// return an error.
+//
+// Requests are redacted as a Recorder would have redacted them before
+// they're matched, using the provider schema in the Cassette. Cassettes
+// without one are matched without the default redaction, so they must be
+// replayed with the same CassetteOptions they were recorded with.
+type Replayer struct {
+	redactor redactor
+
+	schemaOnce sync.Once
+	schema     *tfprotov5.GetProviderSchemaResponse
+	schemaErr  error
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayer returns a Replayer that replays `c`.
+func NewReplayer(c *Cassette, opts ...CassetteOption) *Replayer {
+	return &Replayer{
+		redactor: newRedactor(opts),
+		cassette: c,
+		used:     make([]bool, len(c.Interactions)),
+	}
+}
+
+// recordedSchema returns the provider schema recorded in the Cassette, if
+// there is one.
+func (r *Replayer) recordedSchema() json.RawMessage {
+	if len(r.cassette.Schema) > 0 {
+		return r.cassette.Schema
+	}
+	for _, interaction := range r.cassette.Interactions {
+		if interaction.RPC == "GetProviderSchema" && interaction.Error == "" && len(interaction.Response) > 0 {
+			return interaction.Response
+		}
+	}
+	return nil
+}
+
+// providerSchema returns the provider schema recorded in the Cassette.
+func (r *Replayer) providerSchema() (*tfprotov5.GetProviderSchemaResponse, error) {
+	r.schemaOnce.Do(func() {
+		r.schemaErr = decodeRecord(r.recordedSchema(), reflect.ValueOf(&r.schema).Elem())
+	})
+	return r.schema, r.schemaErr
+}
+
+// Unused returns the interactions that haven't been replayed yet, in the
+// order they were recorded.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// find returns the first unused interaction recorded for `rpc` with `req`,
+// and marks it as used.
+func (r *Replayer) find(rpc string, req interface{}) (Interaction, error) {
+	var schema func() (*tfprotov5.GetProviderSchemaResponse, error)
+	if r.recordedSchema() != nil {
+		schema = r.providerSchema
+	}
+	data, err := r.redactor.encode(rpc, req, req, schema)
+	if err != nil {
+		return Interaction{}, fmt.Errorf("error encoding %s request: %w", rpc, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.RPC != rpc || !equalJSON(interaction.Request, data) {
+			continue
+		}
+		r.used[i] = true
+		return interaction, nil
+	}
+	return Interaction{}, fmt.Errorf("no recorded %s call with request %s", rpc, data)
+}
+
+// replay returns the response recorded for a call to `rpc` with `req`.
+func replay[T any](r *Replayer, rpc string, req interface{}) (*T, error) {
+	interaction, err := r.find(rpc, req)
+	if err != nil {
+		return nil, err
+	}
+	if interaction.Error != "" {
+		return nil, errors.New(interaction.Error)
+	}
+	var resp *T
+	if err := decodeRecord(interaction.Response, reflect.ValueOf(&resp).Elem()); err != nil {
+		return nil, fmt.Errorf("error decoding recorded %s response: %w", rpc, err)
+	}
+	return resp, nil
+}
+
+// equalJSON returns true if `a` and `b` are the same JSON, ignoring
+// insignificant whitespace.
+func equalJSON(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// CassettePath returns the path of the cassette called `name`, which is
+// testdata/NAME.cassette.json.
+func CassettePath(name string) string {
+	return filepath.Join("testdata", name+".cassette.json")
+}
+
+// VCR returns a tfprotov5.ProviderServer for tests to use in place of the
+// one returned by `newServer`, so tests of plan and apply behavior can run
+// without reaching the APIs the provider manages.
+//
+// If the -update flag was passed or the cassette called `name` doesn't
+// exist, the server returned by `newServer` is used, and the RPCs it
+// receives are recorded to the cassette when the test finishes. Otherwise
+// `newServer` isn't called, the RPCs are answered from the cassette, and the
+// test fails if any recorded RPCs weren't replayed.
+//
+// `opts` configure how requests and responses are redacted, and must be the
+// same when recording and replaying.
+func VCR(t testing.TB, name string, newServer func() tfprotov5.ProviderServer, opts ...CassetteOption) tfprotov5.ProviderServer {
+	t.Helper()
+	path := CassettePath(name)
+	_, err := os.Stat(path)
+	if Update() || errors.Is(err, os.ErrNotExist) {
+		r := NewRecorder(newServer(), opts...)
+		t.Cleanup(func() {
+			if t.Failed() {
+				return
+			}
+			if err := r.Save(path); err != nil {
+				t.Errorf("error saving cassette: %s", err)
+			}
+		})
+		return r
+	}
+	c, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("error loading cassette: %s", err)
+	}
+	r := NewReplayer(c, opts...)
+	t.Cleanup(func() {
+		for _, i := range r.Unused() {
+			t.Errorf("recorded %s call wasn't replayed, run with -update to update %s: %s", i.RPC, path, i.Request)
+		}
+	})
+	return r
+}
+
+func (r *Recorder) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := r.srv.GetMetadata(ctx, req)
+	r.record(ctx, "GetMetadata", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := r.srv.GetProviderSchema(ctx, req)
+	r.record(ctx, "GetProviderSchema", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) GetResourceIdentitySchemas(ctx context.Context, req *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	resp, err := r.srv.GetResourceIdentitySchemas(ctx, req)
+	r.record(ctx, "GetResourceIdentitySchemas", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	resp, err := r.srv.PrepareProviderConfig(ctx, req)
+	r.record(ctx, "PrepareProviderConfig", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	resp, err := r.srv.ConfigureProvider(ctx, req)
+	r.record(ctx, "ConfigureProvider", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	resp, err := r.srv.StopProvider(ctx, req)
+	r.record(ctx, "StopProvider", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	resp, err := r.srv.ValidateResourceTypeConfig(ctx, req)
+	r.record(ctx, "ValidateResourceTypeConfig", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	resp, err := r.srv.UpgradeResourceState(ctx, req)
+	r.record(ctx, "UpgradeResourceState", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	resp, err := r.srv.ReadResource(ctx, req)
+	r.record(ctx, "ReadResource", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp, err := r.srv.PlanResourceChange(ctx, req)
+	r.record(ctx, "PlanResourceChange", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	resp, err := r.srv.ApplyResourceChange(ctx, req)
+	r.record(ctx, "ApplyResourceChange", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	resp, err := r.srv.ImportResourceState(ctx, req)
+	r.record(ctx, "ImportResourceState", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	resp, err := r.srv.MoveResourceState(ctx, req)
+	r.record(ctx, "MoveResourceState", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) UpgradeResourceIdentity(ctx context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	resp, err := r.srv.UpgradeResourceIdentity(ctx, req)
+	r.record(ctx, "UpgradeResourceIdentity", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) GenerateResourceConfig(ctx context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	resp, err := r.srv.GenerateResourceConfig(ctx, req)
+	r.record(ctx, "GenerateResourceConfig", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	resp, err := r.srv.ValidateDataSourceConfig(ctx, req)
+	r.record(ctx, "ValidateDataSourceConfig", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	resp, err := r.srv.ReadDataSource(ctx, req)
+	r.record(ctx, "ReadDataSource", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	resp, err := r.srv.CallFunction(ctx, req)
+	r.record(ctx, "CallFunction", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	resp, err := r.srv.GetFunctions(ctx, req)
+	r.record(ctx, "GetFunctions", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	resp, err := r.srv.ValidateEphemeralResourceConfig(ctx, req)
+	r.record(ctx, "ValidateEphemeralResourceConfig", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	resp, err := r.srv.OpenEphemeralResource(ctx, req)
+	r.record(ctx, "OpenEphemeralResource", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	resp, err := r.srv.RenewEphemeralResource(ctx, req)
+	r.record(ctx, "RenewEphemeralResource", req, resp, err)
+	return resp, err
+}
+
+func (r *Recorder) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	resp, err := r.srv.CloseEphemeralResource(ctx, req)
+	r.record(ctx, "CloseEphemeralResource", req, resp, err)
+	return resp, err
+}
+
+func (r *Replayer) GetMetadata(_ context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	return replay[tfprotov5.GetMetadataResponse](r, "GetMetadata", req)
+}
+
+func (r *Replayer) GetProviderSchema(_ context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	return replay[tfprotov5.GetProviderSchemaResponse](r, "GetProviderSchema", req)
+}
+
+func (r *Replayer) GetResourceIdentitySchemas(_ context.Context, req *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	return replay[tfprotov5.GetResourceIdentitySchemasResponse](r, "GetResourceIdentitySchemas", req)
+}
+
+func (r *Replayer) PrepareProviderConfig(_ context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	return replay[tfprotov5.PrepareProviderConfigResponse](r, "PrepareProviderConfig", req)
+}
+
+func (r *Replayer) ConfigureProvider(_ context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	return replay[tfprotov5.ConfigureProviderResponse](r, "ConfigureProvider", req)
+}
+
+func (r *Replayer) StopProvider(_ context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	return replay[tfprotov5.StopProviderResponse](r, "StopProvider", req)
+}
+
+func (r *Replayer) ValidateResourceTypeConfig(_ context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	return replay[tfprotov5.ValidateResourceTypeConfigResponse](r, "ValidateResourceTypeConfig", req)
+}
+
+func (r *Replayer) UpgradeResourceState(_ context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	return replay[tfprotov5.UpgradeResourceStateResponse](r, "UpgradeResourceState", req)
+}
+
+func (r *Replayer) ReadResource(_ context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	return replay[tfprotov5.ReadResourceResponse](r, "ReadResource", req)
+}
+
+func (r *Replayer) PlanResourceChange(_ context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	return replay[tfprotov5.PlanResourceChangeResponse](r, "PlanResourceChange", req)
+}
+
+func (r *Replayer) ApplyResourceChange(_ context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	return replay[tfprotov5.ApplyResourceChangeResponse](r, "ApplyResourceChange", req)
+}
+
+func (r *Replayer) ImportResourceState(_ context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	return replay[tfprotov5.ImportResourceStateResponse](r, "ImportResourceState", req)
+}
+
+func (r *Replayer) MoveResourceState(_ context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	return replay[tfprotov5.MoveResourceStateResponse](r, "MoveResourceState", req)
+}
+
+func (r *Replayer) UpgradeResourceIdentity(_ context.Context, req *tfprotov5.UpgradeResourceIdentityRequest) (*tfprotov5.UpgradeResourceIdentityResponse, error) {
+	return replay[tfprotov5.UpgradeResourceIdentityResponse](r, "UpgradeResourceIdentity", req)
+}
+
+func (r *Replayer) GenerateResourceConfig(_ context.Context, req *tfprotov5.GenerateResourceConfigRequest) (*tfprotov5.GenerateResourceConfigResponse, error) {
+	return replay[tfprotov5.GenerateResourceConfigResponse](r, "GenerateResourceConfig", req)
+}
+
+func (r *Replayer) ValidateDataSourceConfig(_ context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	return replay[tfprotov5.ValidateDataSourceConfigResponse](r, "ValidateDataSourceConfig", req)
+}
+
+func (r *Replayer) ReadDataSource(_ context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	return replay[tfprotov5.ReadDataSourceResponse](r, "ReadDataSource", req)
+}
+
+func (r *Replayer) CallFunction(_ context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	return replay[tfprotov5.CallFunctionResponse](r, "CallFunction", req)
+}
+
+func (r *Replayer) GetFunctions(_ context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	return replay[tfprotov5.GetFunctionsResponse](r, "GetFunctions", req)
+}
+
+func (r *Replayer) ValidateEphemeralResourceConfig(_ context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	return replay[tfprotov5.ValidateEphemeralResourceConfigResponse](r, "ValidateEphemeralResourceConfig", req)
+}
+
+func (r *Replayer) OpenEphemeralResource(_ context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	return replay[tfprotov5.OpenEphemeralResourceResponse](r, "OpenEphemeralResource", req)
+}
+
+func (r *Replayer) RenewEphemeralResource(_ context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	return replay[tfprotov5.RenewEphemeralResourceResponse](r, "RenewEphemeralResource", req)
+}
+
+func (r *Replayer) CloseEphemeralResource(_ context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	return replay[tfprotov5.CloseEphemeralResourceResponse](r, "CloseEphemeralResource", req)
+}
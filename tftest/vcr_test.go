@@ -0,0 +1,333 @@
+package tftest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var vcrWidgetType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"id":   tftypes.String,
+	"tags": tftypes.Set{ElementType: tftypes.String},
+}}
+
+func vcrWidget(t *testing.T, id interface{}) *tfprotov5.DynamicValue {
+	t.Helper()
+	dv, err := tfprotov5.NewDynamicValue(vcrWidgetType, tftypes.NewValue(vcrWidgetType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, id),
+		"tags": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+			tftypes.NewValue(tftypes.String, "b"),
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("error creating value: %s", err)
+	}
+	return &dv
+}
+
+// vcrServer returns a fake provider that plans widgets with an unknown ID,
+// and fails to apply them.
+func vcrServer(t *testing.T) *ProviderServer {
+	return &ProviderServer{
+		Errors: map[string]error{"ApplyResourceChange": errors.New("connection reset")},
+		GetProviderSchemaFunc: func(context.Context, *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+			return &tfprotov5.GetProviderSchemaResponse{
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_widget": {Block: &tfprotov5.SchemaBlock{Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "id", Type: tftypes.String, Computed: true},
+						{Name: "tags", Type: tftypes.Set{ElementType: tftypes.String}, Optional: true},
+					}}},
+				},
+			}, nil
+		},
+		PlanResourceChangeFunc: func(_ context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+			return &tfprotov5.PlanResourceChangeResponse{
+				PlannedState:    vcrWidget(t, tftypes.UnknownValue),
+				RequiresReplace: []*tftypes.AttributePath{tftypes.NewAttributePath().WithAttributeName("tags").WithElementKeyValue(tftypes.NewValue(tftypes.String, "a"))},
+				Diagnostics: []*tfprotov5.Diagnostic{{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "Planning " + req.TypeName,
+				}},
+			}, nil
+		},
+		RenewEphemeralResourceFunc: func(context.Context, *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+			return &tfprotov5.RenewEphemeralResourceResponse{
+				RenewAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			}, nil
+		},
+	}
+}
+
+// exercise calls `srv` with the same RPCs every time, returning the
+// responses and errors it got.
+func exercise(t *testing.T, srv tfprotov5.ProviderServer) []interface{} {
+	ctx := context.Background()
+	var got []interface{}
+	schema, err := srv.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	got = append(got, schema, err)
+	plan, err := srv.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "test_widget",
+		ProposedNewState: vcrWidget(t, nil),
+	})
+	got = append(got, plan, err)
+	apply, err := srv.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{TypeName: "test_widget"})
+	got = append(got, apply, err)
+	renew, err := srv.RenewEphemeralResource(ctx, &tfprotov5.RenewEphemeralResourceRequest{TypeName: "test_token"})
+	got = append(got, renew, err)
+	return got
+}
+
+var compareErrors = cmp.Comparer(func(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Error() == b.Error()
+})
+
+func TestRecorderReplayer(t *testing.T) {
+	rec := NewRecorder(vcrServer(t))
+	expected := exercise(t, rec)
+
+	path := filepath.Join(t.TempDir(), "widget.cassette.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatalf("error saving cassette: %s", err)
+	}
+	c, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("error loading cassette: %s", err)
+	}
+	if len(c.Interactions) != 4 {
+		t.Fatalf("expected 4 interactions, got %d", len(c.Interactions))
+	}
+
+	rep := NewReplayer(c)
+	got := exercise(t, rep)
+	if diff := cmp.Diff(expected, got, compareErrors); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if unused := rep.Unused(); len(unused) != 0 {
+		t.Errorf("unexpected unused interactions: %v", unused)
+	}
+
+	_, err = rep.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err == nil {
+		t.Error("expected error replaying an RPC twice, got nil")
+	}
+}
+
+func TestReplayerMatchesRequests(t *testing.T) {
+	rec := NewRecorder(&ProviderServer{
+		ReadResourceFunc: func(_ context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+			return &tfprotov5.ReadResourceResponse{Private: []byte(req.TypeName)}, nil
+		},
+	})
+	ctx := context.Background()
+	for _, typ := range []string{"test_a", "test_b"} {
+		if _, err := rec.ReadResource(ctx, &tfprotov5.ReadResourceRequest{TypeName: typ}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	c, err := rec.Cassette()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	rep := NewReplayer(c)
+	for _, typ := range []string{"test_b", "test_a"} {
+		resp, err := rep.ReadResource(ctx, &tfprotov5.ReadResourceRequest{TypeName: typ})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(resp.Private) != typ {
+			t.Errorf("expected response for %s, got %s", typ, resp.Private)
+		}
+	}
+	_, err = rep.ReadResource(ctx, &tfprotov5.ReadResourceRequest{TypeName: "test_c"})
+	expectedErr := `no recorded ReadResource call with request {"TypeName":"test_c"}`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestVCR(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var expected []interface{}
+	t.Run("record", func(t *testing.T) {
+		srv := VCR(t, "widget", func() tfprotov5.ProviderServer { return vcrServer(t) })
+		if _, ok := srv.(*Recorder); !ok {
+			t.Fatalf("expected a *Recorder, got %T", srv)
+		}
+		expected = exercise(t, srv)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		srv := VCR(t, "widget", func() tfprotov5.ProviderServer {
+			t.Fatal("unexpected call to newServer")
+			return nil
+		})
+		got := exercise(t, srv)
+		if diff := cmp.Diff(expected, got, compareErrors); diff != "" {
+			t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+		}
+	})
+
+	r := &recorder{}
+	t.Run("unused", func(t *testing.T) {
+		r.TB = t
+		srv := VCR(r, "widget", nil)
+		if _, err := srv.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+	if len(r.errors) != 3 {
+		t.Errorf("expected 3 errors for unused interactions, got %q", r.errors)
+	}
+}
+
+var vcrLoginType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"username": tftypes.String,
+	"password": tftypes.String,
+}}
+
+func vcrLogin(t *testing.T, password string) *tfprotov5.DynamicValue {
+	t.Helper()
+	dv, err := tfprotov5.NewDynamicValue(vcrLoginType, tftypes.NewValue(vcrLoginType, map[string]tftypes.Value{
+		"username": tftypes.NewValue(tftypes.String, "admin"),
+		"password": tftypes.NewValue(tftypes.String, password),
+	}))
+	if err != nil {
+		t.Fatalf("error creating value: %s", err)
+	}
+	return &dv
+}
+
+// recordedValue returns the `field` DynamicValue of the request or response
+// `data`, recorded in a cassette.
+func recordedValue(t *testing.T, data json.RawMessage, field string, typ tftypes.Type) map[string]tftypes.Value {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var dv *tfprotov5.DynamicValue
+	if err := decodeRecord(fields[field], reflect.ValueOf(&dv).Elem()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	val, err := dv.Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var attrs map[string]tftypes.Value
+	if err := val.As(&attrs); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return attrs
+}
+
+func TestRecorderRedacts(t *testing.T) {
+	providerType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"token": tftypes.String}}
+	config, err := tfprotov5.NewDynamicValue(providerType, tftypes.NewValue(providerType, map[string]tftypes.Value{
+		"token": tftypes.NewValue(tftypes.String, "s3cr3t"),
+	}))
+	if err != nil {
+		t.Fatalf("error creating value: %s", err)
+	}
+	rec := NewRecorder(&ProviderServer{
+		GetProviderSchemaFunc: func(context.Context, *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+			return &tfprotov5.GetProviderSchemaResponse{
+				Provider: &tfprotov5.Schema{Block: &tfprotov5.SchemaBlock{Attributes: []*tfprotov5.SchemaAttribute{
+					{Name: "token", Type: tftypes.String, Optional: true},
+				}}},
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_login": {Block: &tfprotov5.SchemaBlock{Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "username", Type: tftypes.String, Required: true},
+						{Name: "password", Type: tftypes.String, Required: true, Sensitive: true},
+					}}},
+				},
+			}, nil
+		},
+		PlanResourceChangeFunc: func(_ context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+			return &tfprotov5.PlanResourceChangeResponse{
+				PlannedState:   req.ProposedNewState,
+				PlannedPrivate: []byte("hunter2"),
+			}, nil
+		},
+	}, WithRedact(func(rpc string, record json.RawMessage) (json.RawMessage, error) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(record, &fields); err != nil {
+			return nil, err
+		}
+		delete(fields, "PlannedPrivate")
+		return json.Marshal(fields)
+	}))
+
+	ctx := context.Background()
+	if _, err := rec.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &config}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	planReq := &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "test_login",
+		ProposedNewState: vcrLogin(t, "hunter2"),
+	}
+	plan, err := rec.PlanResourceChange(ctx, planReq)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(vcrLogin(t, "hunter2"), plan.PlannedState); diff != "" {
+		t.Errorf("expected the live response to be unredacted, got diff (-wanted, +got): %s", diff)
+	}
+
+	path := filepath.Join(t.TempDir(), "login.cassette.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatalf("error saving cassette: %s", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) || bytes.Contains(data, []byte("s3cr3t")) {
+		t.Fatalf("expected secrets to be redacted, got %s", data)
+	}
+	c, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("error loading cassette: %s", err)
+	}
+	if len(c.Interactions) != 2 || len(c.Schema) == 0 {
+		t.Fatalf("expected 2 interactions and a schema, got %s", data)
+	}
+
+	redacted := tftypes.NewValue(tftypes.String, Redacted)
+	recorded := []map[string]tftypes.Value{
+		recordedValue(t, c.Interactions[0].Request, "Config", providerType),
+		recordedValue(t, c.Interactions[1].Request, "ProposedNewState", vcrLoginType),
+		recordedValue(t, c.Interactions[1].Response, "PlannedState", vcrLoginType),
+	}
+	expected := []map[string]tftypes.Value{
+		{"token": redacted},
+		{"username": tftypes.NewValue(tftypes.String, "admin"), "password": redacted},
+		{"username": tftypes.NewValue(tftypes.String, "admin"), "password": redacted},
+	}
+	if diff := cmp.Diff(expected, recorded); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	rep := NewReplayer(c)
+	if _, err := rep.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &config}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := rep.PlanResourceChange(ctx, planReq); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,310 @@
+// Package tfunstructured converts between tftypes.Values and the
+// map[string]interface{} trees Kubernetes' unstructured.Unstructured
+// objects hold, for providers that manage raw Kubernetes manifests.
+//
+// Unstructured objects only hold the types Kubernetes' JSON decoder
+// produces: nil, string, bool, int64, float64, []interface{}, and
+// map[string]interface{}. Anything else, even an int, makes
+// runtime.DeepCopyJSONValue panic, so the values this package produces
+// only ever use those types. Whole numbers that fit in an int64 are
+// int64s, and other numbers float64s, matching what the decoder would
+// produce for the same manifest.
+package tfunstructured
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	tfString  tftypes.Type = tftypes.String
+	tfNumber  tftypes.Type = tftypes.Number
+	tfBool    tftypes.Type = tftypes.Bool
+	tfDynamic tftypes.Type = tftypes.DynamicPseudoType
+)
+
+// ToUnstructured converts the object or map `val` into the contents of an
+// unstructured.Unstructured. A null `val` is converted to a nil map.
+//
+// Any error returned that relates to a specific part of `val` will be a
+// tftypes.AttributePathError, identifying the path the error occurred at.
+func ToUnstructured(val tftypes.Value) (map[string]interface{}, error) {
+	if val.Type() == nil || (!val.Type().Is(tftypes.Object{}) && !val.Type().Is(tftypes.Map{})) {
+		return nil, fmt.Errorf("cannot convert %s to an unstructured object, an object or map is required", val.Type())
+	}
+	v, err := ToUnstructuredValue(val)
+	if err != nil {
+		return nil, err
+	}
+	obj, _ := v.(map[string]interface{})
+	return obj, nil
+}
+
+// ToUnstructuredValue converts `val` into a value that can be held in an
+// unstructured.Unstructured. Null values are converted to nil, lists,
+// sets, and tuples to []interface{}, and objects and maps to
+// map[string]interface{}. `val` must be wholly known.
+func ToUnstructuredValue(val tftypes.Value) (interface{}, error) {
+	return toUnstructured(tftypes.NewAttributePath(), val)
+}
+
+func toUnstructured(path *tftypes.AttributePath, val tftypes.Value) (interface{}, error) {
+	if !val.IsKnown() {
+		return nil, path.NewErrorf("cannot convert unknown value to an unstructured value")
+	}
+	if val.IsNull() {
+		return nil, nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tfString):
+		var s string
+		if err := val.As(&s); err != nil {
+			return nil, path.NewError(err)
+		}
+		return s, nil
+	case typ.Is(tfNumber):
+		var f big.Float
+		if err := val.As(&f); err != nil {
+			return nil, path.NewError(err)
+		}
+		return number(path, &f)
+	case typ.Is(tfBool):
+		var b bool
+		if err := val.As(&b); err != nil {
+			return nil, path.NewError(err)
+		}
+		return b, nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		isSet := typ.Is(tftypes.Set{})
+		list := make([]interface{}, 0, len(elems))
+		for i, elem := range elems {
+			elemPath := path.WithElementKeyInt(i)
+			if isSet {
+				elemPath = path.WithElementKeyValue(elem)
+			}
+			v, err := toUnstructured(elemPath, elem)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		var elems map[string]tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return nil, path.NewError(err)
+		}
+		isObject := typ.Is(tftypes.Object{})
+		obj := make(map[string]interface{}, len(elems))
+		for _, k := range sorted.Keys(elems) {
+			elemPath := path.WithElementKeyString(k)
+			if isObject {
+				elemPath = path.WithAttributeName(k)
+			}
+			v, err := toUnstructured(elemPath, elems[k])
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = v
+		}
+		return obj, nil
+	}
+	return nil, path.NewErrorf("cannot convert value of type %s to an unstructured value", typ)
+}
+
+// number converts `f` into an int64 if it's a whole number that fits in
+// one, and a float64 otherwise.
+func number(path *tftypes.AttributePath, f *big.Float) (interface{}, error) {
+	if f.IsInt() {
+		if i, acc := f.Int64(); acc == big.Exact {
+			return i, nil
+		}
+	}
+	n, _ := f.Float64()
+	if math.IsInf(n, 0) {
+		return nil, path.NewErrorf("cannot convert %s to an unstructured value, it's out of range of a float64", f.Text('g', -1))
+	}
+	return n, nil
+}
+
+// FromUnstructured converts the contents of an unstructured.Unstructured
+// into a tftypes.Value of the object or map type `typ`. A nil `obj` is
+// converted to null.
+//
+// Any error returned that relates to a specific part of `obj` will be a
+// tftypes.AttributePathError, identifying the path the error occurred at.
+func FromUnstructured(typ tftypes.Type, obj map[string]interface{}) (tftypes.Value, error) {
+	if !typ.Is(tftypes.Object{}) && !typ.Is(tftypes.Map{}) && !typ.Is(tfDynamic) {
+		return tftypes.Value{}, fmt.Errorf("cannot convert an unstructured object to %s, an object or map type is required", typ)
+	}
+	if obj == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	return FromUnstructuredValue(typ, obj)
+}
+
+// FromUnstructuredValue converts `v`, a value held in an
+// unstructured.Unstructured, into a tftypes.Value of type `typ`. Slices are
+// converted to lists, sets, or tuples, and maps to objects or maps, as
+// `typ` requires. If `typ` is tftypes.DynamicPseudoType, the type is
+// inferred like it would be for JSON: slices become tuples and maps
+// objects.
+//
+// As well as the types unstructured objects hold, ints, int32s, and
+// float32s are accepted as numbers, as manifests built in Go code often
+// contain them.
+func FromUnstructuredValue(typ tftypes.Type, v interface{}) (tftypes.Value, error) {
+	return fromUnstructured(tftypes.NewAttributePath(), typ, v)
+}
+
+func fromUnstructured(path *tftypes.AttributePath, typ tftypes.Type, v interface{}) (tftypes.Value, error) {
+	switch x := v.(type) {
+	case nil:
+		return tftypes.NewValue(typ, nil), nil
+	case string:
+		if typ.Is(tfString) || typ.Is(tfDynamic) {
+			return tftypes.NewValue(tftypes.String, x), nil
+		}
+		return tftypes.Value{}, path.NewErrorf("cannot convert a string to %s", typ)
+	case bool:
+		if typ.Is(tfBool) || typ.Is(tfDynamic) {
+			return tftypes.NewValue(tftypes.Bool, x), nil
+		}
+		return tftypes.Value{}, path.NewErrorf("cannot convert a bool to %s", typ)
+	case int64, int, int32, float64, float32:
+		if !typ.Is(tfNumber) && !typ.Is(tfDynamic) {
+			return tftypes.Value{}, path.NewErrorf("cannot convert a number to %s", typ)
+		}
+		f, err := fromNumber(x)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(tftypes.Number, f), nil
+	case []interface{}:
+		return fromSlice(path, typ, x)
+	case map[string]interface{}:
+		return fromMap(path, typ, x)
+	}
+	return tftypes.Value{}, path.NewErrorf("cannot convert %T to %s, it's not a type unstructured objects hold", v, typ)
+}
+
+func fromNumber(v interface{}) (*big.Float, error) {
+	switch x := v.(type) {
+	case int64:
+		return new(big.Float).SetInt64(x), nil
+	case int:
+		return new(big.Float).SetInt64(int64(x)), nil
+	case int32:
+		return new(big.Float).SetInt64(int64(x)), nil
+	case float32:
+		v = float64(x)
+	}
+	f := v.(float64)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("cannot convert %v to a number", f)
+	}
+	return new(big.Float).SetFloat64(f), nil
+}
+
+func fromSlice(path *tftypes.AttributePath, typ tftypes.Type, vals []interface{}) (tftypes.Value, error) {
+	var elemTypes []tftypes.Type
+	switch t := typ.(type) {
+	case tftypes.List:
+		elemTypes = repeat(t.ElementType, len(vals))
+	case tftypes.Set:
+		elemTypes = repeat(t.ElementType, len(vals))
+	case tftypes.Tuple:
+		if len(t.ElementTypes) != len(vals) {
+			return tftypes.Value{}, path.NewErrorf("cannot convert a slice of %d elements to %s", len(vals), typ)
+		}
+		elemTypes = t.ElementTypes
+	default:
+		if !typ.Is(tfDynamic) {
+			return tftypes.Value{}, path.NewErrorf("cannot convert a slice to %s", typ)
+		}
+		elemTypes = repeat(tfDynamic, len(vals))
+	}
+	elems := make([]tftypes.Value, 0, len(vals))
+	for i, v := range vals {
+		elem, err := fromUnstructured(path.WithElementKeyInt(i), elemTypes[i], v)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, elem)
+	}
+	if typ.Is(tfDynamic) {
+		types := make([]tftypes.Type, 0, len(elems))
+		for _, elem := range elems {
+			types = append(types, elem.Type())
+		}
+		typ = tftypes.Tuple{ElementTypes: types}
+	}
+	if err := tftypes.ValidateValue(typ, elems); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+func fromMap(path *tftypes.AttributePath, typ tftypes.Type, vals map[string]interface{}) (tftypes.Value, error) {
+	elems := make(map[string]tftypes.Value, len(vals))
+	switch t := typ.(type) {
+	case tftypes.Object:
+		for k := range vals {
+			if _, ok := t.AttributeTypes[k]; !ok {
+				return tftypes.Value{}, path.WithAttributeName(k).NewErrorf("unexpected attribute %q", k)
+			}
+		}
+		for k, attrType := range t.AttributeTypes {
+			// Fields missing from the map, which Kubernetes omits when
+			// they're empty, are converted to null.
+			elem, err := fromUnstructured(path.WithAttributeName(k), attrType, vals[k])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = elem
+		}
+	case tftypes.Map:
+		for k, v := range vals {
+			elem, err := fromUnstructured(path.WithElementKeyString(k), t.ElementType, v)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = elem
+		}
+	default:
+		if !typ.Is(tfDynamic) {
+			return tftypes.Value{}, path.NewErrorf("cannot convert a map to %s", typ)
+		}
+		types := make(map[string]tftypes.Type, len(vals))
+		for k, v := range vals {
+			elem, err := fromUnstructured(path.WithAttributeName(k), tfDynamic, v)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = elem
+			types[k] = elem.Type()
+		}
+		typ = tftypes.Object{AttributeTypes: types}
+	}
+	if err := tftypes.ValidateValue(typ, elems); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+func repeat(typ tftypes.Type, n int) []tftypes.Type {
+	types := make([]tftypes.Type, n)
+	for i := range types {
+		types[i] = typ
+	}
+	return types
+}
@@ -0,0 +1,190 @@
+package tfunstructured
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var metadataType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"name":   tftypes.String,
+	"labels": tftypes.Map{ElementType: tftypes.String},
+}}
+
+var deploymentType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"apiVersion": tftypes.String,
+	"kind":       tftypes.String,
+	"metadata":   metadataType,
+	"spec": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"replicas": tftypes.Number,
+		"paused":   tftypes.Bool,
+		"ratio":    tftypes.Number,
+		"args":     tftypes.List{ElementType: tftypes.String},
+	}},
+}}
+
+func deployment(replicas *big.Float) tftypes.Value {
+	return tftypes.NewValue(deploymentType, map[string]tftypes.Value{
+		"apiVersion": tftypes.NewValue(tftypes.String, "apps/v1"),
+		"kind":       tftypes.NewValue(tftypes.String, "Deployment"),
+		"metadata": tftypes.NewValue(metadataType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "web"),
+			"labels": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+				"app": tftypes.NewValue(tftypes.String, "web"),
+			}),
+		}),
+		"spec": tftypes.NewValue(deploymentType.AttributeTypes["spec"], map[string]tftypes.Value{
+			"replicas": tftypes.NewValue(tftypes.Number, replicas),
+			"paused":   tftypes.NewValue(tftypes.Bool, false),
+			"ratio":    tftypes.NewValue(tftypes.Number, big.NewFloat(0.5)),
+			"args":     tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		}),
+	})
+}
+
+func TestToUnstructured(t *testing.T) {
+	type testCase struct {
+		val         tftypes.Value
+		expected    map[string]interface{}
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"deployment": {
+			val: deployment(big.NewFloat(3)),
+			expected: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":   "web",
+					"labels": map[string]interface{}{"app": "web"},
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"paused":   false,
+					"ratio":    0.5,
+					"args":     nil,
+				},
+			},
+		},
+		"large-int": {
+			val: tftypes.NewValue(tftypes.Map{ElementType: tftypes.Number}, map[string]tftypes.Value{
+				"n": tftypes.NewValue(tftypes.Number, new(big.Float).SetMantExp(big.NewFloat(1), 70)),
+			}),
+			expected: map[string]interface{}{"n": math.Pow(2, 70)},
+		},
+		"null": {
+			val: tftypes.NewValue(deploymentType, nil),
+		},
+		"unknown": {
+			val: tftypes.NewValue(metadataType, map[string]tftypes.Value{
+				"name":   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"labels": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+			}),
+			expectedErr: "AttributeName(\"name\"): cannot convert unknown value to an unstructured value",
+		},
+		"not-object": {
+			val:         tftypes.NewValue(tftypes.String, "web"),
+			expectedErr: "cannot convert tftypes.String to an unstructured object, an object or map is required",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := ToUnstructured(tc.val)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFromUnstructured(t *testing.T) {
+	type testCase struct {
+		typ         tftypes.Type
+		obj         map[string]interface{}
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"deployment": {
+			typ: deploymentType,
+			obj: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":   "web",
+					"labels": map[string]interface{}{"app": "web"},
+				},
+				"spec": map[string]interface{}{
+					"replicas": 3,
+					"paused":   false,
+					"ratio":    0.5,
+				},
+			},
+			expected: deployment(big.NewFloat(3)),
+		},
+		"dynamic": {
+			typ: tftypes.DynamicPseudoType,
+			obj: map[string]interface{}{
+				"ports": []interface{}{int64(80), "http"},
+			},
+			expected: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"ports": tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.String}},
+			}}, map[string]tftypes.Value{
+				"ports": tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.String}}, []tftypes.Value{
+					tftypes.NewValue(tftypes.Number, big.NewFloat(80)),
+					tftypes.NewValue(tftypes.String, "http"),
+				}),
+			}),
+		},
+		"null": {
+			typ:      deploymentType,
+			expected: tftypes.NewValue(deploymentType, nil),
+		},
+		"unexpected-attribute": {
+			typ:         metadataType,
+			obj:         map[string]interface{}{"namespace": "default"},
+			expectedErr: "AttributeName(\"namespace\"): unexpected attribute \"namespace\"",
+		},
+		"wrong-type": {
+			typ:         metadataType,
+			obj:         map[string]interface{}{"name": int64(1)},
+			expectedErr: "AttributeName(\"name\"): cannot convert a number to tftypes.String",
+		},
+		"unsupported-type": {
+			typ:         metadataType,
+			obj:         map[string]interface{}{"labels": map[string]string{"app": "web"}},
+			expectedErr: "AttributeName(\"labels\"): cannot convert map[string]string to tftypes.Map[tftypes.String], it's not a type unstructured objects hold",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := FromUnstructured(tc.typ, tc.obj)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package tfvalidate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// SizeBetween returns a Validator for lists, sets, tuples, and maps with at
+// least `min` and at most `max` elements. A negative `max` means there's no
+// maximum.
+func SizeBetween(min, max int) Validator {
+	desc := fmt.Sprintf("Must have at least %d elements.", min)
+	if max >= 0 {
+		desc = fmt.Sprintf("Must have between %d and %d elements.", min, max)
+	}
+	return Describe(ValidatorFunc(func(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic {
+		if skip(val) {
+			return nil
+		}
+		var n int
+		typ := val.Type()
+		switch {
+		case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+			var elems []tftypes.Value
+			if err := val.As(&elems); err != nil {
+				return invalid(path, "Expected a collection, got %s.", typ)
+			}
+			n = len(elems)
+		case typ.Is(tftypes.Map{}):
+			var elems map[string]tftypes.Value
+			if err := val.As(&elems); err != nil {
+				return invalid(path, "Expected a collection, got %s.", typ)
+			}
+			n = len(elems)
+		default:
+			return invalid(path, "Expected a collection, got %s.", typ)
+		}
+		if n < min {
+			return invalid(path, "Must have at least %d elements, got %d.", min, n)
+		}
+		if max >= 0 && n > max {
+			return invalid(path, "Must have at most %d elements, got %d.", max, n)
+		}
+		return nil
+	}), desc)
+}
+
+// MapKeys returns a Validator for maps whose keys are valid according to
+// `validators`. Each key is validated as a string value, with diagnostics
+// attached to the path of its element.
+func MapKeys(validators ...Validator) Validator {
+	all := All(validators...)
+	return Describe(ValidatorFunc(func(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic {
+		if skip(val) {
+			return nil
+		}
+		var elems map[string]tftypes.Value
+		if !val.Type().Is(tftypes.Map{}) || val.As(&elems) != nil {
+			return invalid(path, "Expected a map, got %s.", val.Type())
+		}
+		var diags []*tfprotov5.Diagnostic
+		for _, k := range sorted.Keys(elems) {
+			diags = append(diags, all.ValidateValue(ctx, path.WithElementKeyString(k), tftypes.NewValue(tftypes.String, k))...)
+		}
+		return diags
+	}), prefixDescription("Keys: ", all))
+}
+
+// Elements returns a Validator for lists, sets, tuples, and maps whose
+// elements are valid according to `validators`.
+func Elements(validators ...Validator) Validator {
+	all := All(validators...)
+	return Describe(ValidatorFunc(func(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic {
+		if skip(val) {
+			return nil
+		}
+		typ := val.Type()
+		var diags []*tfprotov5.Diagnostic
+		switch {
+		case typ.Is(tftypes.List{}), typ.Is(tftypes.Tuple{}), typ.Is(tftypes.Set{}):
+			var elems []tftypes.Value
+			if err := val.As(&elems); err != nil {
+				return invalid(path, "Expected a collection, got %s.", typ)
+			}
+			isSet := typ.Is(tftypes.Set{})
+			for i, elem := range elems {
+				elemPath := path.WithElementKeyInt(i)
+				if isSet {
+					elemPath = path.WithElementKeyValue(elem)
+				}
+				diags = append(diags, all.ValidateValue(ctx, elemPath, elem)...)
+			}
+		case typ.Is(tftypes.Map{}):
+			var elems map[string]tftypes.Value
+			if err := val.As(&elems); err != nil {
+				return invalid(path, "Expected a collection, got %s.", typ)
+			}
+			for _, k := range sorted.Keys(elems) {
+				diags = append(diags, all.ValidateValue(ctx, path.WithElementKeyString(k), elems[k])...)
+			}
+		default:
+			return invalid(path, "Expected a collection, got %s.", typ)
+		}
+		return diags
+	}), prefixDescription("Elements: ", all))
+}
+
+// prefixDescription returns the description of `validator` preceded by
+// `prefix`, or "" if it has none.
+func prefixDescription(prefix string, validator Validator) string {
+	if desc := Description(validator); desc != "" {
+		return prefix + desc
+	}
+	return ""
+}
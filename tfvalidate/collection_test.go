@@ -0,0 +1,51 @@
+package tfvalidate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func list(elems ...string) tftypes.Value {
+	vals := make([]tftypes.Value, 0, len(elems))
+	for _, e := range elems {
+		vals = append(vals, str(e))
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, vals)
+}
+
+func strMap(elems map[string]string) tftypes.Value {
+	vals := make(map[string]tftypes.Value, len(elems))
+	for k, v := range elems {
+		vals[k] = str(v)
+	}
+	return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, vals)
+}
+
+func TestCollectionValidators(t *testing.T) {
+	runValidatorTests(t, map[string]validatorTestCase{
+		"size-valid":     {validator: SizeBetween(1, 2), val: list("a", "b")},
+		"size-too-small": {validator: SizeBetween(1, 2), val: list(), expected: []string{"Must have at least 1 elements, got 0."}},
+		"size-too-large": {validator: SizeBetween(1, 2), val: list("a", "b", "c"), expected: []string{"Must have at most 2 elements, got 3."}},
+		"size-map":       {validator: SizeBetween(0, 1), val: strMap(map[string]string{"a": "1", "b": "2"}), expected: []string{"Must have at most 1 elements, got 2."}},
+		"size-string":    {validator: SizeBetween(0, 1), val: str("a"), expected: []string{"Expected a collection, got tftypes.String."}},
+		"map-keys": {
+			validator: MapKeys(StringLength(1, 3)),
+			val:       strMap(map[string]string{"a": "1", "long": "2", "longer": "3"}),
+			expected: []string{
+				"Must be at most 3 characters long, got 4.",
+				"Must be at most 3 characters long, got 6.",
+			},
+		},
+		"elements": {
+			validator: Elements(StringOneOf("a", "b")),
+			val:       list("a", "c"),
+			expected:  []string{`Must be one of "a", "b", got "c".`},
+		},
+		"elements-map": {
+			validator: Elements(StringOneOf("1")),
+			val:       strMap(map[string]string{"a": "1", "b": "2"}),
+			expected:  []string{`Must be one of "1", got "2".`},
+		},
+	})
+}
@@ -0,0 +1,31 @@
+package tfvalidate
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// IntBetween returns a Validator for whole numbers between `min` and `max`,
+// inclusive.
+func IntBetween(min, max int64) Validator {
+	return Describe(ValidatorFunc(func(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic {
+		if skip(val) {
+			return nil
+		}
+		f := new(big.Float)
+		if err := val.As(&f); err != nil {
+			return invalid(path, "Expected a number, got %s.", val.Type())
+		}
+		if !f.IsInt() {
+			return invalid(path, "Must be a whole number, got %s.", f.Text('f', -1))
+		}
+		if f.Cmp(new(big.Float).SetInt64(min)) < 0 || f.Cmp(new(big.Float).SetInt64(max)) > 0 {
+			return invalid(path, "Must be between %d and %d, got %s.", min, max, f.Text('f', -1))
+		}
+		return nil
+	}), fmt.Sprintf("Must be a whole number between %d and %d.", min, max))
+}
@@ -0,0 +1,23 @@
+package tfvalidate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func num(f float64) tftypes.Value {
+	return tftypes.NewValue(tftypes.Number, big.NewFloat(f))
+}
+
+func TestIntBetween(t *testing.T) {
+	runValidatorTests(t, map[string]validatorTestCase{
+		"valid":    {validator: IntBetween(1, 10), val: num(10)},
+		"too-low":  {validator: IntBetween(1, 10), val: num(0), expected: []string{"Must be between 1 and 10, got 0."}},
+		"too-high": {validator: IntBetween(1, 10), val: num(11), expected: []string{"Must be between 1 and 10, got 11."}},
+		"fraction": {validator: IntBetween(1, 10), val: num(1.5), expected: []string{"Must be a whole number, got 1.5."}},
+		"null":     {validator: IntBetween(1, 10), val: tftypes.NewValue(tftypes.Number, nil)},
+		"string":   {validator: IntBetween(1, 10), val: str("1"), expected: []string{"Expected a number, got tftypes.String."}},
+	})
+}
@@ -0,0 +1,96 @@
+package tfvalidate
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/attrpath"
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Validated is implemented by resource, data source, and provider handlers
+// that declare Validators for their configuration. tfrouter.Router runs
+// them automatically when handling the validation RPCs.
+type Validated interface {
+	Validators() *Registry
+}
+
+// Registry associates Validators with attribute paths. The zero value is an
+// empty Registry ready to use, and a nil *Registry has no Validators.
+//
+// Element keys in paths are ignored when matching, so Validators registered
+// for an attribute of a nested block validate that attribute in every
+// element of the block.
+type Registry struct {
+	validators attrpath.Registry[Validator]
+}
+
+// Add registers `validators` for the attribute at `path`. Validators are run
+// in the order they were added.
+func (r *Registry) Add(path *tftypes.AttributePath, validators ...Validator) {
+	r.validators.Add(path, validators...)
+}
+
+// Validators returns the Validators registered for the attribute at `path`.
+func (r *Registry) Validators(path *tftypes.AttributePath) []Validator {
+	if r == nil {
+		return nil
+	}
+	return r.validators.Get(path)
+}
+
+// Validate runs the registered Validators against every attribute in
+// `config` they apply to, and returns all of their diagnostics.
+func (r *Registry) Validate(ctx context.Context, config tftypes.Value) []*tfprotov5.Diagnostic {
+	if r == nil || r.validators.Len() == 0 {
+		return nil
+	}
+	return r.validate(ctx, tftypes.NewAttributePath(), config)
+}
+
+// validate walks `val` in a stable order, so diagnostics are always returned
+// in the same order.
+func (r *Registry) validate(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic {
+	var diags []*tfprotov5.Diagnostic
+	if steps := path.Steps(); len(steps) > 0 {
+		if _, ok := steps[len(steps)-1].(tftypes.AttributeName); ok {
+			for _, v := range r.Validators(path) {
+				diags = append(diags, v.ValidateValue(ctx, path, val)...)
+			}
+		}
+	}
+	if skip(val) {
+		return diags
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		var elems map[string]tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return diags
+		}
+		isObject := typ.Is(tftypes.Object{})
+		for _, k := range sorted.Keys(elems) {
+			elemPath := path.WithElementKeyString(k)
+			if isObject {
+				elemPath = path.WithAttributeName(k)
+			}
+			diags = append(diags, r.validate(ctx, elemPath, elems[k])...)
+		}
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Tuple{}), typ.Is(tftypes.Set{}):
+		var elems []tftypes.Value
+		if err := val.As(&elems); err != nil {
+			return diags
+		}
+		isSet := typ.Is(tftypes.Set{})
+		for i, elem := range elems {
+			elemPath := path.WithElementKeyInt(i)
+			if isSet {
+				elemPath = path.WithElementKeyValue(elem)
+			}
+			diags = append(diags, r.validate(ctx, elemPath, elem)...)
+		}
+	}
+	return diags
+}
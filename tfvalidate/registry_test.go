@@ -0,0 +1,66 @@
+package tfvalidate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestRegistryValidate(t *testing.T) {
+	ruleType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name": tftypes.String,
+	}}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name": tftypes.String,
+		"tags": tftypes.Map{ElementType: tftypes.String},
+		"rule": tftypes.List{ElementType: ruleType},
+	}}
+	rule := func(name interface{}) tftypes.Value {
+		return tftypes.NewValue(ruleType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, name),
+		})
+	}
+	config := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "toolong"),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"b": tftypes.NewValue(tftypes.String, "x"),
+			"a": tftypes.NewValue(tftypes.String, "y"),
+		}),
+		"rule": tftypes.NewValue(tftypes.List{ElementType: ruleType}, []tftypes.Value{
+			rule("ok"),
+			rule("toolong"),
+			rule(tftypes.UnknownValue),
+		}),
+	})
+
+	r := &Registry{}
+	r.Add(tftypes.NewAttributePath().WithAttributeName("name"), StringLength(1, 3))
+	r.Add(tftypes.NewAttributePath().WithAttributeName("rule").WithAttributeName("name"), StringLength(1, 3))
+	r.Add(tftypes.NewAttributePath().WithAttributeName("tags"), Elements(StringOneOf("z")))
+	r.Add(tftypes.NewAttributePath().WithAttributeName("tags"), SizeBetween(0, 5))
+
+	type result struct {
+		Path   string
+		Detail string
+	}
+	var got []result
+	for _, d := range r.Validate(context.Background(), config) {
+		got = append(got, result{Path: d.Attribute.String(), Detail: d.Detail})
+	}
+	expected := []result{
+		{Path: `AttributeName("name")`, Detail: "Must be at most 3 characters long, got 7."},
+		{Path: `AttributeName("rule").ElementKeyInt(1).AttributeName("name")`, Detail: "Must be at most 3 characters long, got 7."},
+		{Path: `AttributeName("tags").ElementKeyString("a")`, Detail: `Must be one of "z", got "y".`},
+		{Path: `AttributeName("tags").ElementKeyString("b")`, Detail: `Must be one of "z", got "x".`},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	var nilRegistry *Registry
+	if diags := nilRegistry.Validate(context.Background(), config); len(diags) > 0 {
+		t.Errorf("unexpected diagnostics from nil registry: %+v", diags)
+	}
+}
@@ -0,0 +1,106 @@
+package tfvalidate
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// stringValidator returns a Validator for strings that calls `f` with the
+// value of each known, non-null string.
+func stringValidator(f func(path *tftypes.AttributePath, s string) []*tfprotov5.Diagnostic) Validator {
+	return ValidatorFunc(func(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic {
+		if skip(val) {
+			return nil
+		}
+		var s string
+		if err := val.As(&s); err != nil {
+			return invalid(path, "Expected a string, got %s.", val.Type())
+		}
+		return f(path, s)
+	})
+}
+
+// StringLength returns a Validator for strings with at least `min` and at
+// most `max` characters. A negative `max` means there's no maximum.
+func StringLength(min, max int) Validator {
+	desc := fmt.Sprintf("Must be at least %d characters long.", min)
+	if max >= 0 {
+		desc = fmt.Sprintf("Must be between %d and %d characters long.", min, max)
+	}
+	return Describe(stringValidator(func(path *tftypes.AttributePath, s string) []*tfprotov5.Diagnostic {
+		n := utf8.RuneCountInString(s)
+		if n < min {
+			return invalid(path, "Must be at least %d characters long, got %d.", min, n)
+		}
+		if max >= 0 && n > max {
+			return invalid(path, "Must be at most %d characters long, got %d.", max, n)
+		}
+		return nil
+	}), desc)
+}
+
+// StringMatches returns a Validator for strings matching `re`. `message`
+// describes the expected format in the diagnostic returned for strings that
+// don't match; if empty, the regular expression is shown instead.
+func StringMatches(re *regexp.Regexp, message string) Validator {
+	desc := message + "."
+	if message == "" {
+		desc = fmt.Sprintf("Must match the regular expression `%s`.", re)
+	}
+	return Describe(stringValidator(func(path *tftypes.AttributePath, s string) []*tfprotov5.Diagnostic {
+		if re.MatchString(s) {
+			return nil
+		}
+		if message == "" {
+			return invalid(path, "Must match the regular expression %q, got %q.", re, s)
+		}
+		return invalid(path, "%s, got %q.", message, s)
+	}), desc)
+}
+
+// StringOneOf returns a Validator for strings that are one of `values`.
+func StringOneOf(values ...string) Validator {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, `"`+v+`"`)
+	}
+	return Describe(stringValidator(func(path *tftypes.AttributePath, s string) []*tfprotov5.Diagnostic {
+		for _, v := range values {
+			if s == v {
+				return nil
+			}
+		}
+		return invalid(path, "Must be one of %s, got %q.", strings.Join(quoted, ", "), s)
+	}), fmt.Sprintf("Must be one of %s.", strings.Join(quoted, ", ")))
+}
+
+// URL returns a Validator for strings that are absolute URLs. If `schemes`
+// are given, the URL must use one of them.
+func URL(schemes ...string) Validator {
+	desc := "Must be an absolute URL."
+	if len(schemes) > 0 {
+		desc = fmt.Sprintf("Must be a URL using one of the schemes %s.", strings.Join(schemes, ", "))
+	}
+	return Describe(stringValidator(func(path *tftypes.AttributePath, s string) []*tfprotov5.Diagnostic {
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return invalid(path, "Must be an absolute URL, got %q.", s)
+		}
+		if len(schemes) == 0 {
+			return nil
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold(u.Scheme, scheme) {
+				return nil
+			}
+		}
+		return invalid(path, "Must be a URL using one of the schemes %s, got %q.", strings.Join(schemes, ", "), s)
+	}), desc)
+}
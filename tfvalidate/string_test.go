@@ -0,0 +1,58 @@
+package tfvalidate
+
+import (
+	"math/big"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func str(s string) tftypes.Value {
+	return tftypes.NewValue(tftypes.String, s)
+}
+
+func TestStringValidators(t *testing.T) {
+	runValidatorTests(t, map[string]validatorTestCase{
+		"length-valid":     {validator: StringLength(1, 3), val: str("héé")},
+		"length-short":     {validator: StringLength(2, 3), val: str("a"), expected: []string{"Must be at least 2 characters long, got 1."}},
+		"length-long":      {validator: StringLength(0, 3), val: str("abcd"), expected: []string{"Must be at most 3 characters long, got 4."}},
+		"length-unbounded": {validator: StringLength(0, -1), val: str("abcdefghijklmnop")},
+		"length-null":      {validator: StringLength(1, 3), val: tftypes.NewValue(tftypes.String, nil)},
+		"length-unknown":   {validator: StringLength(1, 3), val: tftypes.NewValue(tftypes.String, tftypes.UnknownValue)},
+		"length-number": {
+			validator: StringLength(1, 3),
+			val:       tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+			expected:  []string{"Expected a string, got tftypes.Number."},
+		},
+		"matches-valid": {validator: StringMatches(regexp.MustCompile(`^[a-z]+$`), ""), val: str("abc")},
+		"matches-invalid": {
+			validator: StringMatches(regexp.MustCompile(`^[a-z]+$`), ""),
+			val:       str("ABC"),
+			expected:  []string{`Must match the regular expression "^[a-z]+$", got "ABC".`},
+		},
+		"matches-message": {
+			validator: StringMatches(regexp.MustCompile(`^[a-z]+$`), "Must only contain lowercase letters"),
+			val:       str("ABC"),
+			expected:  []string{`Must only contain lowercase letters, got "ABC".`},
+		},
+		"one-of-valid": {validator: StringOneOf("a", "b"), val: str("b")},
+		"one-of-invalid": {
+			validator: StringOneOf("a", "b"),
+			val:       str("c"),
+			expected:  []string{`Must be one of "a", "b", got "c".`},
+		},
+		"url-valid":  {validator: URL(), val: str("ftp://example.com/path")},
+		"url-scheme": {validator: URL("https"), val: str("HTTPS://example.com")},
+		"url-relative": {
+			validator: URL(),
+			val:       str("/path"),
+			expected:  []string{`Must be an absolute URL, got "/path".`},
+		},
+		"url-wrong-scheme": {
+			validator: URL("https"),
+			val:       str("http://example.com"),
+			expected:  []string{`Must be a URL using one of the schemes https, got "http://example.com".`},
+		},
+	})
+}
@@ -0,0 +1,103 @@
+// Package tfvalidate provides reusable validators for the values of
+// attributes, for use when handling ValidateResourceTypeConfig,
+// ValidateDataSourceConfig, and PrepareProviderConfig.
+//
+// Validators only check values that are known and not null. Unknown values
+// will be validated again once they're known, and whether an attribute may
+// be null is decided by the schema.
+package tfvalidate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/describe"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Validator validates a single value.
+type Validator interface {
+	// ValidateValue returns diagnostics describing any problems with
+	// `val`, which is found at `path`. The diagnostics should be
+	// attached to `path`, or a path within it.
+	ValidateValue(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic
+}
+
+// ValidatorFunc is a function that implements Validator.
+type ValidatorFunc func(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic
+
+// ValidateValue calls `f`.
+func (f ValidatorFunc) ValidateValue(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic {
+	return f(ctx, path, val)
+}
+
+// Describer is implemented by Validators that can describe the constraint
+// they enforce, for documentation. The Validators in this package implement
+// it, and other Validators can be given a description with Describe.
+type Describer = describe.Describer
+
+// described is a Validator with a description.
+type described struct {
+	Validator
+	description string
+}
+
+func (d described) Description() string {
+	return d.description
+}
+
+// Describe returns `validator` with `description`, so it's documented by
+// docsgen.
+func Describe(validator Validator, description string) Validator {
+	return described{Validator: validator, description: description}
+}
+
+// Description returns the description of `validators`, joined by spaces, or
+// "" if none of them implement Describer.
+func Description(validators ...Validator) string {
+	return describe.Join(validators...)
+}
+
+// Attribute runs `validators` against the value at `path` in `config`. If
+// there's no value at `path`, because it's inside a null or unknown block,
+// no validators are run.
+func Attribute(ctx context.Context, config tftypes.Value, path *tftypes.AttributePath, validators ...Validator) []*tfprotov5.Diagnostic {
+	v, _, err := tftypes.WalkAttributePath(config, path)
+	if err != nil {
+		return nil
+	}
+	val, ok := v.(tftypes.Value)
+	if !ok {
+		return nil
+	}
+	return All(validators...).ValidateValue(ctx, path, val)
+}
+
+// All returns a Validator that runs each of `validators` and returns all of
+// their diagnostics.
+func All(validators ...Validator) Validator {
+	return Describe(ValidatorFunc(func(ctx context.Context, path *tftypes.AttributePath, val tftypes.Value) []*tfprotov5.Diagnostic {
+		var diags []*tfprotov5.Diagnostic
+		for _, v := range validators {
+			diags = append(diags, v.ValidateValue(ctx, path, val)...)
+		}
+		return diags
+	}), Description(validators...))
+}
+
+// skip reports whether validators should ignore `val`.
+func skip(val tftypes.Value) bool {
+	return !val.IsKnown() || val.IsNull()
+}
+
+func invalid(path *tftypes.AttributePath, format string, args ...interface{}) []*tfprotov5.Diagnostic {
+	return []*tfprotov5.Diagnostic{
+		{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid attribute value",
+			Detail:    fmt.Sprintf(format, args...),
+			Attribute: path,
+		},
+	}
+}
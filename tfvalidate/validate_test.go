@@ -0,0 +1,131 @@
+package tfvalidate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type validatorTestCase struct {
+	validator Validator
+	val       tftypes.Value
+	expected  []string
+}
+
+var testPath = tftypes.NewAttributePath().WithAttributeName("attr")
+
+// runValidatorTests runs each test case's validator against its value at
+// testPath, comparing the details of the diagnostics returned.
+func runValidatorTests(t *testing.T, cases map[string]validatorTestCase) {
+	t.Helper()
+	for name, test := range cases {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := test.validator.ValidateValue(context.Background(), testPath, test.val)
+			var got []string
+			for _, d := range diags {
+				if d.Severity != tfprotov5.DiagnosticSeverityError {
+					t.Errorf("unexpected severity %s", d.Severity)
+				}
+				if d.Attribute == nil || !d.Attribute.WithoutLastStep().Equal(testPath) && !d.Attribute.Equal(testPath) {
+					t.Errorf("unexpected attribute path %s", d.Attribute)
+				}
+				got = append(got, d.Detail)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name": tftypes.String,
+		"block": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name": tftypes.String,
+		}},
+	}}
+	config := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"name":  tftypes.NewValue(tftypes.String, "a-much-too-long-name"),
+		"block": tftypes.NewValue(typ.AttributeTypes["block"], nil),
+	})
+	name := tftypes.NewAttributePath().WithAttributeName("name")
+	diags := Attribute(context.Background(), config, name, StringLength(1, 10), StringOneOf("foo"))
+	expected := []*tfprotov5.Diagnostic{
+		{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid attribute value",
+			Detail:    "Must be at most 10 characters long, got 20.",
+			Attribute: name,
+		},
+		{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid attribute value",
+			Detail:    `Must be one of "foo", got "a-much-too-long-name".`,
+			Attribute: name,
+		},
+	}
+	if diff := cmp.Diff(expected, diags); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	nested := tftypes.NewAttributePath().WithAttributeName("block").WithAttributeName("name")
+	if diags := Attribute(context.Background(), config, nested, StringLength(100, -1)); len(diags) > 0 {
+		t.Errorf("unexpected diagnostics for attribute of null block: %+v", diags)
+	}
+}
+
+func TestDescription(t *testing.T) {
+	type testCase struct {
+		validators []Validator
+		expected   string
+	}
+	cases := map[string]testCase{
+		"string-length": {
+			validators: []Validator{StringLength(1, 10)},
+			expected:   "Must be between 1 and 10 characters long.",
+		},
+		"string-length-no-max": {
+			validators: []Validator{StringLength(3, -1)},
+			expected:   "Must be at least 3 characters long.",
+		},
+		"one-of": {
+			validators: []Validator{StringOneOf("a", "b")},
+			expected:   `Must be one of "a", "b".`,
+		},
+		"all": {
+			validators: []Validator{All(IntBetween(1, 5), SizeBetween(0, 2))},
+			expected:   "Must be a whole number between 1 and 5. Must have between 0 and 2 elements.",
+		},
+		"elements": {
+			validators: []Validator{Elements(URL("https"))},
+			expected:   "Elements: Must be a URL using one of the schemes https.",
+		},
+		"undescribed": {
+			validators: []Validator{
+				ValidatorFunc(func(context.Context, *tftypes.AttributePath, tftypes.Value) []*tfprotov5.Diagnostic { return nil }),
+				Elements(),
+			},
+			expected: "",
+		},
+		"describe": {
+			validators: []Validator{
+				Describe(ValidatorFunc(func(context.Context, *tftypes.AttributePath, tftypes.Value) []*tfprotov5.Diagnostic { return nil }), "Must be even."),
+				StringMatches(nil, "Must be a lowercase name"),
+			},
+			expected: "Must be even. Must be a lowercase name.",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if got := Description(tc.validators...); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package tfvalue
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Canonicalizer converts values to a canonical form, so values that are
+// equivalent but were encoded differently, like sets with their elements in
+// different orders or numbers decoded with different precisions from
+// MessagePack and JSON, compare as equal and have the same Hash.
+//
+// In canonical form:
+//
+//   - numbers have 512 bits of precision, and zero is never negative;
+//   - set elements are sorted by their formatting, and duplicates removed;
+//   - strings registered with AddString are normalized.
+//
+// Unknown and null values are left as they are. The zero value is ready to
+// use.
+type Canonicalizer struct {
+	strings []stringEntry
+}
+
+type stringEntry struct {
+	path *tftypes.AttributePath
+	f    func(string) string
+}
+
+// AddString registers `f` to normalize the string at `path`, replacing any
+// function already registered for it. Element keys in `path` are ignored,
+// so `f` applies to the attribute in every element of the collections it's
+// nested in, and to the elements of collections of strings at `path`:
+//
+//	c.AddString(tfpath.MustParse("rule.protocol"), strings.ToLower)
+func (c *Canonicalizer) AddString(path *tftypes.AttributePath, f func(string) string) {
+	path = withoutElementKeys(path)
+	for i, entry := range c.strings {
+		if entry.path.Equal(path) {
+			c.strings[i].f = f
+			return
+		}
+	}
+	c.strings = append(c.strings, stringEntry{path: path, f: f})
+}
+
+// Canonicalize returns `val` in canonical form, using a Canonicalizer with
+// no strings registered.
+func Canonicalize(val tftypes.Value) (tftypes.Value, error) {
+	var c Canonicalizer
+	return c.Canonicalize(val)
+}
+
+// Canonicalize returns `val` in canonical form.
+func (c *Canonicalizer) Canonicalize(val tftypes.Value) (tftypes.Value, error) {
+	return tftypes.Transform(val, func(path *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !v.IsKnown() || v.IsNull() {
+			return v, nil
+		}
+		typ := v.Type()
+		switch {
+		case typ.Is(tftypes.String):
+			f := c.stringFunc(path)
+			if f == nil {
+				return v, nil
+			}
+			var s string
+			if err := v.As(&s); err != nil {
+				return v, path.NewError(err)
+			}
+			return tftypes.NewValue(typ, f(s)), nil
+		case typ.Is(tftypes.Number):
+			n := new(big.Float)
+			if err := v.As(&n); err != nil {
+				return v, path.NewError(err)
+			}
+			canonical := new(big.Float).SetPrec(512)
+			if n.Sign() != 0 {
+				canonical.Set(n)
+			}
+			return tftypes.NewValue(typ, canonical), nil
+		case typ.Is(tftypes.Set{}):
+			elems := []tftypes.Value{}
+			if err := v.As(&elems); err != nil {
+				return v, path.NewError(err)
+			}
+			return tftypes.NewValue(typ, sortSet(elems)), nil
+		}
+		return v, nil
+	})
+}
+
+func (c *Canonicalizer) stringFunc(path *tftypes.AttributePath) func(string) string {
+	if c == nil || len(c.strings) == 0 {
+		return nil
+	}
+	path = withoutElementKeys(path)
+	for _, entry := range c.strings {
+		if entry.path.Equal(path) {
+			return entry.f
+		}
+	}
+	return nil
+}
+
+// sortSet returns `elems` sorted by their formatting, without duplicates.
+// Elements that aren't fully known are never duplicates, as they may turn out
+// to be different.
+func sortSet(elems []tftypes.Value) []tftypes.Value {
+	keys := make(map[int]string, len(elems))
+	indexes := make([]int, len(elems))
+	for i, elem := range elems {
+		keys[i] = Format(elem)
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return keys[indexes[i]] < keys[indexes[j]]
+	})
+	sorted := make([]tftypes.Value, 0, len(elems))
+	for _, i := range indexes {
+		if len(sorted) > 0 && elems[i].IsFullyKnown() && sorted[len(sorted)-1].Equal(elems[i]) {
+			continue
+		}
+		sorted = append(sorted, elems[i])
+	}
+	return sorted
+}
@@ -0,0 +1,102 @@
+package tfvalue
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestCanonicalize(t *testing.T) {
+	str := func(s interface{}) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	num := func(n *big.Float) tftypes.Value { return tftypes.NewValue(tftypes.Number, n) }
+	setType := tftypes.Set{ElementType: tftypes.String}
+	ruleType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"protocol": tftypes.String,
+		"cidrs":    setType,
+	}}
+	rulesType := tftypes.List{ElementType: ruleType}
+	rule := func(protocol string, cidrs ...tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(ruleType, map[string]tftypes.Value{
+			"protocol": str(protocol),
+			"cidrs":    tftypes.NewValue(setType, cidrs),
+		})
+	}
+
+	var c Canonicalizer
+	c.AddString(tfpath.MustParse("protocol"), strings.ToUpper)
+	c.AddString(tfpath.MustParse("protocol"), strings.ToLower)
+	c.AddString(tfpath.MustParse("cidrs"), strings.TrimSpace)
+
+	type testCase struct {
+		c        *Canonicalizer
+		val      tftypes.Value
+		expected string
+	}
+	cases := map[string]testCase{
+		"set-order": {
+			val:      tftypes.NewValue(setType, []tftypes.Value{str("b"), str("a"), str(tftypes.UnknownValue), str(tftypes.UnknownValue)}),
+			expected: "[\n  \"a\",\n  \"b\",\n  (known after apply),\n  (known after apply),\n]",
+		},
+		"numbers": {
+			val:      num(big.NewFloat(0.5)),
+			expected: "0.5",
+		},
+		"negative-zero": {
+			val:      num(new(big.Float).Neg(big.NewFloat(0))),
+			expected: "0",
+		},
+		"no-strings": {
+			val:      rule("TCP", str(" 10.0.0.0/8")),
+			expected: "{\n  cidrs    = [\n    \" 10.0.0.0/8\",\n  ]\n  protocol = \"TCP\"\n}",
+		},
+		"strings": {
+			c: &c,
+			val: tftypes.NewValue(rulesType, []tftypes.Value{
+				rule("TCP", str("10.0.0.0/8 "), str(" 10.0.0.0/8"), str("1.0.0.0/8")),
+			}),
+			expected: "[\n  {\n    cidrs    = [\n      \"1.0.0.0/8\",\n      \"10.0.0.0/8\",\n    ]\n    protocol = \"tcp\"\n  },\n]",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			canonicalize := Canonicalize
+			if tc.c != nil {
+				canonicalize = tc.c.Canonicalize
+			}
+			got, err := canonicalize(tc.val)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if s := Format(got); s != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeEqual(t *testing.T) {
+	setType := tftypes.Set{ElementType: tftypes.Number}
+	a := tftypes.NewValue(setType, []tftypes.Value{
+		tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+		tftypes.NewValue(tftypes.Number, big.NewFloat(2)),
+	})
+	b := tftypes.NewValue(setType, []tftypes.Value{
+		tftypes.NewValue(tftypes.Number, new(big.Float).SetPrec(512).SetInt64(2)),
+		tftypes.NewValue(tftypes.Number, new(big.Float).SetPrec(512).SetInt64(1)),
+	})
+	ca, err := Canonicalize(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cb, err := Canonicalize(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ca.Equal(cb) {
+		t.Errorf("expected %s to equal %s", ca, cb)
+	}
+}
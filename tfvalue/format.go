@@ -0,0 +1,203 @@
+package tfvalue
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Printer formats tftypes.Values as text resembling Terraform's
+// configuration language, for logs, error details, and test failures:
+//
+//	{
+//	  id       = (known after apply)
+//	  listener = [
+//	    {
+//	      port     = 80
+//	      protocol = "http"
+//	    },
+//	  ]
+//	  password = (sensitive value)
+//	  tags     = null
+//	}
+//
+// Unknown values are written as "(known after apply)", like in Terraform's
+// plans, and null values as "null". Attributes of objects and elements of
+// maps are written in order of their names and keys, so the result is the
+// same every time.
+//
+// The zero value is ready to use.
+type Printer struct {
+	// Indent is written once for each level of nesting. It defaults to
+	// two spaces.
+	Indent string
+
+	// Sensitive are the paths of values that are written as
+	// "(sensitive value)" instead of their contents. Paths made of only
+	// attribute names, like those of sensitive attributes in schemas, also
+	// match the attribute in every element of the collections they're
+	// nested in.
+	Sensitive []*tftypes.AttributePath
+}
+
+// Format returns `val` formatted by a Printer with the default settings.
+func Format(val tftypes.Value) string {
+	var p Printer
+	return p.Format(val)
+}
+
+// Format returns `val` formatted as text.
+func (p Printer) Format(val tftypes.Value) string {
+	indent := p.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	f := formatter{Printer: p, indent: indent}
+	f.value(tftypes.NewAttributePath(), val, 0)
+	return f.b.String()
+}
+
+type formatter struct {
+	Printer
+	indent string
+	b      strings.Builder
+}
+
+func (f *formatter) value(path *tftypes.AttributePath, val tftypes.Value, depth int) {
+	switch {
+	case f.sensitive(path):
+		f.b.WriteString("(sensitive value)")
+		return
+	case !val.IsKnown():
+		f.b.WriteString("(known after apply)")
+		return
+	case val.IsNull():
+		f.b.WriteString("null")
+		return
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.String):
+		var s string
+		_ = val.As(&s)
+		f.b.WriteString(strconv.Quote(s))
+	case typ.Is(tftypes.Number):
+		n := new(big.Float)
+		_ = val.As(&n)
+		f.b.WriteString(n.Text('f', -1))
+	case typ.Is(tftypes.Bool):
+		var b bool
+		_ = val.As(&b)
+		f.b.WriteString(strconv.FormatBool(b))
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		children := map[string]tftypes.Value{}
+		_ = val.As(&children)
+		if len(children) == 0 {
+			f.b.WriteString("{}")
+			return
+		}
+		keys := make([]string, 0, len(children))
+		labels := make(map[string]string, len(children))
+		width := 0
+		for k := range children {
+			keys = append(keys, k)
+			labels[k] = k
+			if typ.Is(tftypes.Map{}) || !isIdentifier(k) {
+				labels[k] = strconv.Quote(k)
+			}
+			if n := len(labels[k]); n > width {
+				width = n
+			}
+		}
+		sort.Strings(keys)
+		f.b.WriteString("{\n")
+		for _, k := range keys {
+			next := path.WithAttributeName(k)
+			if typ.Is(tftypes.Map{}) {
+				next = path.WithElementKeyString(k)
+			}
+			f.line(depth + 1)
+			f.b.WriteString(labels[k])
+			f.b.WriteString(strings.Repeat(" ", width-len(labels[k])))
+			f.b.WriteString(" = ")
+			f.value(next, children[k], depth+1)
+			f.b.WriteString("\n")
+		}
+		f.line(depth)
+		f.b.WriteString("}")
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		children := []tftypes.Value{}
+		_ = val.As(&children)
+		if len(children) == 0 {
+			f.b.WriteString("[]")
+			return
+		}
+		f.b.WriteString("[\n")
+		for i, child := range children {
+			next := path.WithElementKeyInt(i)
+			if typ.Is(tftypes.Set{}) {
+				next = path.WithElementKeyValue(child)
+			}
+			f.line(depth + 1)
+			f.value(next, child, depth+1)
+			f.b.WriteString(",\n")
+		}
+		f.line(depth)
+		f.b.WriteString("]")
+	default:
+		f.b.WriteString(val.String())
+	}
+}
+
+func (f *formatter) line(depth int) {
+	f.b.WriteString(strings.Repeat(f.indent, depth))
+}
+
+// sensitive returns true if the value at `path` should be masked.
+func (f *formatter) sensitive(path *tftypes.AttributePath) bool {
+	for _, s := range f.Sensitive {
+		if s.Equal(path) || (onlyAttributeNames(s) && s.Equal(withoutElementKeys(path))) {
+			return true
+		}
+	}
+	return false
+}
+
+func onlyAttributeNames(path *tftypes.AttributePath) bool {
+	for _, step := range path.Steps() {
+		if _, ok := step.(tftypes.AttributeName); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func withoutElementKeys(path *tftypes.AttributePath) *tftypes.AttributePath {
+	var steps []tftypes.AttributePathStep
+	for _, step := range path.Steps() {
+		if _, ok := step.(tftypes.AttributeName); ok {
+			steps = append(steps, step)
+		}
+	}
+	return tftypes.NewAttributePathWithSteps(steps)
+}
+
+// isIdentifier returns true if `s` can be written as an attribute name
+// without quotes.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case (r == '-' || (r >= '0' && r <= '9')) && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package tfvalue
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestFormat(t *testing.T) {
+	expected := `{
+  aliases  = [
+    "www",
+  ]
+  extra    = [
+    "x",
+  ]
+  listener = [
+    {
+      port     = 80
+      protocol = "http"
+    },
+    {
+      port     = (known after apply)
+      protocol = null
+    },
+  ]
+  name     = "web"
+  pair     = [
+    "a",
+    true,
+  ]
+  tags     = {
+    "env" = "prod"
+  }
+}`
+	if diff := cmp.Diff(expected, Format(server())); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"dns name": tftypes.String,
+		"ratio":    tftypes.Number,
+		"password": tftypes.String,
+		"listener": tftypes.List{ElementType: listenerType},
+		"empty":    tftypes.Map{ElementType: tftypes.String},
+		"none":     tftypes.List{ElementType: tftypes.String},
+	}}
+	val := tftypes.NewValue(objType, map[string]tftypes.Value{
+		"dns name": tftypes.NewValue(tftypes.String, "a\"b"),
+		"ratio":    tftypes.NewValue(tftypes.Number, 0.25),
+		"password": tftypes.NewValue(tftypes.String, "hunter2"),
+		"listener": tftypes.NewValue(tftypes.List{ElementType: listenerType}, []tftypes.Value{
+			listener(80, "http"),
+			listener(443, "https"),
+		}),
+		"empty": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{}),
+		"none":  tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{}),
+	})
+	p := Printer{
+		Indent: "\t",
+		Sensitive: []*tftypes.AttributePath{
+			tfpath.MustParse("password"),
+			tfpath.MustParse("listener.port"),
+			tfpath.MustParse("listener[1].protocol"),
+		},
+	}
+	expected := `{
+	"dns name" = "a\"b"
+	empty      = {}
+	listener   = [
+		{
+			port     = (sensitive value)
+			protocol = "http"
+		},
+		{
+			port     = (sensitive value)
+			protocol = (sensitive value)
+		},
+	]
+	none       = []
+	password   = (sensitive value)
+	ratio      = 0.25
+}`
+	if diff := cmp.Diff(expected, p.Format(val)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
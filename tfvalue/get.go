@@ -0,0 +1,153 @@
+// Package tfvalue provides helpers for inspecting and manipulating
+// tftypes.Values without converting them to Go types, so unknown and null
+// values are preserved.
+package tfvalue
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	// ErrNotFound is returned when a path refers to an attribute or
+	// element that doesn't exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnknown is returned when a path traverses an unknown value.
+	ErrUnknown = errors.New("value is unknown")
+
+	// ErrNull is returned when a path traverses a null value.
+	ErrNull = errors.New("value is null")
+)
+
+// Get returns the value at `path` in `val`.
+//
+// Errors are tftypes.AttributePathErrors, identifying the value Get
+// couldn't traverse, and wrap ErrNotFound if the attribute or element `path`
+// refers to doesn't exist, including indexes out of range, ErrUnknown if the
+// value is unknown, or ErrNull if it's null, so they can be checked with
+// errors.Is:
+//
+//	port, err := tfvalue.Get(config, tfpath.MustParse("listener[0].port"))
+//	if errors.Is(err, tfvalue.ErrUnknown) {
+//		// wait for it to be known
+//	}
+func Get(val tftypes.Value, path *tftypes.AttributePath) (tftypes.Value, error) {
+	if path == nil {
+		return val, nil
+	}
+	steps := path.Steps()
+	for i, step := range steps {
+		var err error
+		val, err = child(val, step)
+		if err != nil {
+			return tftypes.Value{}, tftypes.NewAttributePathWithSteps(steps[:i]).NewError(err)
+		}
+	}
+	return val, nil
+}
+
+// GetPrimitive returns the value at `path` in `val` as an
+// asgotypes.GoPrimitive would, for when the types involved don't matter.
+// The value must be fully known.
+func GetPrimitive(val tftypes.Value, path *tftypes.AttributePath) (interface{}, error) {
+	val, err := Get(val, path)
+	if err != nil {
+		return nil, err
+	}
+	var p asgotypes.GoPrimitive
+	if err := p.FromTerraform5Value(val); err != nil {
+		var pathErr tftypes.AttributePathError
+		if errors.As(err, &pathErr) {
+			steps := append(path.Steps(), pathErr.Path.Steps()...)
+			return nil, tftypes.NewAttributePathWithSteps(steps).NewError(pathErr.Unwrap())
+		}
+		return nil, path.NewError(err)
+	}
+	return p.Value, nil
+}
+
+// child returns the attribute or element of `val` identified by `step`.
+func child(val tftypes.Value, step tftypes.AttributePathStep) (tftypes.Value, error) {
+	if !val.IsKnown() {
+		return tftypes.Value{}, ErrUnknown
+	}
+	if val.IsNull() {
+		return tftypes.Value{}, ErrNull
+	}
+	typ := val.Type()
+	switch s := step.(type) {
+	case tftypes.AttributeName:
+		if !typ.Is(tftypes.Object{}) {
+			return tftypes.Value{}, stepError(step, typ)
+		}
+		attrs := map[string]tftypes.Value{}
+		if err := val.As(&attrs); err != nil {
+			return tftypes.Value{}, err
+		}
+		attr, ok := attrs[string(s)]
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("attribute %q %w", string(s), ErrNotFound)
+		}
+		return attr, nil
+	case tftypes.ElementKeyInt:
+		if !typ.Is(tftypes.List{}) && !typ.Is(tftypes.Tuple{}) {
+			return tftypes.Value{}, stepError(step, typ)
+		}
+		elems := []tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return tftypes.Value{}, err
+		}
+		if s < 0 || int64(s) >= int64(len(elems)) {
+			return tftypes.Value{}, fmt.Errorf("element %d %w, there are %d elements", int64(s), ErrNotFound, len(elems))
+		}
+		return elems[s], nil
+	case tftypes.ElementKeyString:
+		if !typ.Is(tftypes.Map{}) {
+			return tftypes.Value{}, stepError(step, typ)
+		}
+		elems := map[string]tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return tftypes.Value{}, err
+		}
+		elem, ok := elems[string(s)]
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("element %q %w", string(s), ErrNotFound)
+		}
+		return elem, nil
+	case tftypes.ElementKeyValue:
+		if !typ.Is(tftypes.Set{}) {
+			return tftypes.Value{}, stepError(step, typ)
+		}
+		elems := []tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return tftypes.Value{}, err
+		}
+		for _, elem := range elems {
+			if elem.Equal(tftypes.Value(s)) {
+				return elem, nil
+			}
+		}
+		return tftypes.Value{}, fmt.Errorf("element %s %w", tftypes.Value(s), ErrNotFound)
+	}
+	return tftypes.Value{}, stepError(step, typ)
+}
+
+// stepError returns the error for `step` not applying to a value of type
+// `typ`.
+func stepError(step tftypes.AttributePathStep, typ tftypes.Type) error {
+	switch s := step.(type) {
+	case tftypes.AttributeName:
+		return fmt.Errorf("can't get attribute %q of %s", string(s), typ)
+	case tftypes.ElementKeyInt:
+		return fmt.Errorf("can't get element %d of %s", int64(s), typ)
+	case tftypes.ElementKeyString:
+		return fmt.Errorf("can't get element %q of %s", string(s), typ)
+	case tftypes.ElementKeyValue:
+		return fmt.Errorf("can't get element %s of %s", tftypes.Value(s), typ)
+	}
+	return fmt.Errorf("unsupported attribute path step %T", step)
+}
@@ -0,0 +1,188 @@
+package tfvalue
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	listenerType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"port":     tftypes.Number,
+		"protocol": tftypes.String,
+	}}
+	serverType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":     tftypes.String,
+		"listener": tftypes.List{ElementType: listenerType},
+		"tags":     tftypes.Map{ElementType: tftypes.String},
+		"aliases":  tftypes.Set{ElementType: tftypes.String},
+		"pair":     tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Bool}},
+		"extra":    tftypes.DynamicPseudoType,
+	}}
+)
+
+func listener(port interface{}, protocol interface{}) tftypes.Value {
+	return tftypes.NewValue(listenerType, map[string]tftypes.Value{
+		"port":     tftypes.NewValue(tftypes.Number, port),
+		"protocol": tftypes.NewValue(tftypes.String, protocol),
+	})
+}
+
+func server() tftypes.Value {
+	return tftypes.NewValue(serverType, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "web"),
+		"listener": tftypes.NewValue(tftypes.List{ElementType: listenerType}, []tftypes.Value{
+			listener(80, "http"),
+			listener(tftypes.UnknownValue, nil),
+		}),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"env": tftypes.NewValue(tftypes.String, "prod"),
+		}),
+		"aliases": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "www"),
+		}),
+		"pair": tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Bool}}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+			tftypes.NewValue(tftypes.Bool, true),
+		}),
+		"extra": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "x"),
+		}),
+	})
+}
+
+func TestGet(t *testing.T) {
+	type testCase struct {
+		path        string
+		expected    tftypes.Value
+		expectedErr string
+		is          error
+	}
+	cases := map[string]testCase{
+		"root": {
+			path:     "",
+			expected: server(),
+		},
+		"attribute": {
+			path:     "name",
+			expected: tftypes.NewValue(tftypes.String, "web"),
+		},
+		"list-element": {
+			path:     "listener[0].port",
+			expected: tftypes.NewValue(tftypes.Number, 80),
+		},
+		"unknown": {
+			path:     "listener[1].port",
+			expected: tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+		},
+		"map-element": {
+			path:     `tags["env"]`,
+			expected: tftypes.NewValue(tftypes.String, "prod"),
+		},
+		"tuple-element": {
+			path:     "pair[1]",
+			expected: tftypes.NewValue(tftypes.Bool, true),
+		},
+		"dynamic": {
+			path:     "extra[0]",
+			expected: tftypes.NewValue(tftypes.String, "x"),
+		},
+		"missing-attribute": {
+			path:        "listener[0].address",
+			expectedErr: `AttributeName("listener").ElementKeyInt(0): attribute "address" not found`,
+			is:          ErrNotFound,
+		},
+		"out-of-range": {
+			path:        "listener[2]",
+			expectedErr: `AttributeName("listener"): element 2 not found, there are 2 elements`,
+			is:          ErrNotFound,
+		},
+		"negative-index": {
+			path:        "listener[-1]",
+			expectedErr: `AttributeName("listener"): element -1 not found, there are 2 elements`,
+			is:          ErrNotFound,
+		},
+		"missing-key": {
+			path:        `tags["team"]`,
+			expectedErr: `AttributeName("tags"): element "team" not found`,
+			is:          ErrNotFound,
+		},
+		"through-unknown": {
+			path:        "listener[1].port.x",
+			expectedErr: `AttributeName("listener").ElementKeyInt(1).AttributeName("port"): value is unknown`,
+			is:          ErrUnknown,
+		},
+		"through-null": {
+			path:        `listener[1].protocol.x`,
+			expectedErr: `AttributeName("listener").ElementKeyInt(1).AttributeName("protocol"): value is null`,
+			is:          ErrNull,
+		},
+		"wrong-step": {
+			path:        `listener["a"]`,
+			expectedErr: `AttributeName("listener"): can't get element "a" of tftypes.List[tftypes.Object["port":tftypes.Number, "protocol":tftypes.String]]`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := Get(server(), tfpath.MustParse(tc.path))
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				if tc.is != nil && !errors.Is(err, tc.is) {
+					t.Errorf("expected error to wrap %q", tc.is)
+				}
+				var pathErr tftypes.AttributePathError
+				if !errors.As(err, &pathErr) {
+					t.Errorf("expected a tftypes.AttributePathError, got %T", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetSetElement(t *testing.T) {
+	path := tftypes.NewAttributePath().WithAttributeName("aliases").WithElementKeyValue(tftypes.NewValue(tftypes.String, "www"))
+	got, err := Get(server(), path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := tftypes.NewValue(tftypes.String, "www"); !got.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	path = tftypes.NewAttributePath().WithAttributeName("aliases").WithElementKeyValue(tftypes.NewValue(tftypes.String, "api"))
+	_, err = Get(server(), path)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected error wrapping %q, got %v", ErrNotFound, err)
+	}
+}
+
+func TestGetPrimitive(t *testing.T) {
+	got, err := GetPrimitive(server(), tfpath.MustParse("listener[0]"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]interface{}{"port": big.NewFloat(80), "protocol": "http"}
+	if diff := cmp.Diff(expected, got, cmp.Comparer(func(a, b *big.Float) bool { return a.Cmp(b) == 0 })); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	_, err = GetPrimitive(server(), tfpath.MustParse("listener[1]"))
+	expectedErr := `AttributeName("listener").ElementKeyInt(1).AttributeName("port"): cannot decode unknown values to Go types`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
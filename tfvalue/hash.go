@@ -0,0 +1,143 @@
+package tfvalue
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math/big"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Hash returns a digest of `val`, which must be fully known, as a
+// hexadecimal string. Values that are equal have the same digest, however
+// they were created: the order of set elements and the precision of numbers
+// don't affect it. Values of different types, like a list and a set with the
+// same elements, have different digests.
+//
+// Digests are stable across releases, so they can be stored, and used for
+// cache keys and change detection.
+func Hash(val tftypes.Value) (string, error) {
+	sum, err := hashValue(tftypes.NewAttributePath(), val)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+func hashValue(path *tftypes.AttributePath, val tftypes.Value) ([]byte, error) {
+	h := sha256.New()
+	if err := writeValue(h, path, val); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func writeValue(h hash.Hash, path *tftypes.AttributePath, val tftypes.Value) error {
+	if !val.IsKnown() {
+		return path.NewError(ErrUnknown)
+	}
+	// the type is written for every value, as values of dynamic
+	// attributes can have any type
+	writeString(h, val.Type().String())
+	if val.IsNull() {
+		h.Write([]byte{'N'})
+		return nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.String):
+		var s string
+		if err := val.As(&s); err != nil {
+			return path.NewError(err)
+		}
+		h.Write([]byte{'S'})
+		writeString(h, s)
+	case typ.Is(tftypes.Number):
+		n := new(big.Float)
+		if err := val.As(&n); err != nil {
+			return path.NewError(err)
+		}
+		h.Write([]byte{'D'})
+		writeString(h, n.Text('g', -1))
+	case typ.Is(tftypes.Bool):
+		var b bool
+		if err := val.As(&b); err != nil {
+			return path.NewError(err)
+		}
+		if b {
+			h.Write([]byte{'T'})
+		} else {
+			h.Write([]byte{'F'})
+		}
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Tuple{}):
+		elems := []tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return path.NewError(err)
+		}
+		h.Write([]byte{'L'})
+		writeLength(h, len(elems))
+		for i, elem := range elems {
+			if err := writeValue(h, path.WithElementKeyInt(i), elem); err != nil {
+				return err
+			}
+		}
+	case typ.Is(tftypes.Set{}):
+		elems := []tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return path.NewError(err)
+		}
+		sums := make([][]byte, 0, len(elems))
+		for _, elem := range elems {
+			sum, err := hashValue(path.WithElementKeyValue(elem), elem)
+			if err != nil {
+				return err
+			}
+			sums = append(sums, sum)
+		}
+		sort.Slice(sums, func(i, j int) bool { return bytes.Compare(sums[i], sums[j]) < 0 })
+		h.Write([]byte{'E'})
+		writeLength(h, len(sums))
+		for _, sum := range sums {
+			h.Write(sum)
+		}
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		elems := map[string]tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return path.NewError(err)
+		}
+		keys := make([]string, 0, len(elems))
+		for k := range elems {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		h.Write([]byte{'M'})
+		writeLength(h, len(keys))
+		for _, k := range keys {
+			next := path.WithAttributeName(k)
+			if typ.Is(tftypes.Map{}) {
+				next = path.WithElementKeyString(k)
+			}
+			writeString(h, k)
+			if err := writeValue(h, next, elems[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return path.NewErrorf("can't hash values of type %s", typ)
+	}
+	return nil
+}
+
+func writeLength(h hash.Hash, n int) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(n))])
+}
+
+func writeString(h hash.Hash, s string) {
+	writeLength(h, len(s))
+	h.Write([]byte(s))
+}
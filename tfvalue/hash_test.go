@@ -0,0 +1,107 @@
+package tfvalue
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestHash(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	setType := tftypes.Set{ElementType: tftypes.String}
+	listType := tftypes.List{ElementType: tftypes.String}
+	dynType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"v": tftypes.DynamicPseudoType}}
+	dyn := func(v tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(dynType, map[string]tftypes.Value{"v": v})
+	}
+	known := func() tftypes.Value {
+		val, err := Set(server(), tfpath.MustParse("listener[1].port"), tftypes.NewValue(tftypes.Number, 443))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return val
+	}
+
+	type testCase struct {
+		a, b  tftypes.Value
+		equal bool
+	}
+	cases := map[string]testCase{
+		"same": {
+			a:     known(),
+			b:     known(),
+			equal: true,
+		},
+		"set-order": {
+			a:     tftypes.NewValue(setType, []tftypes.Value{str("a"), str("b")}),
+			b:     tftypes.NewValue(setType, []tftypes.Value{str("b"), str("a")}),
+			equal: true,
+		},
+		"number-precision": {
+			a:     tftypes.NewValue(tftypes.Number, big.NewFloat(80)),
+			b:     tftypes.NewValue(tftypes.Number, new(big.Float).SetPrec(512).SetInt64(80)),
+			equal: true,
+		},
+		"list-order": {
+			a: tftypes.NewValue(listType, []tftypes.Value{str("a"), str("b")}),
+			b: tftypes.NewValue(listType, []tftypes.Value{str("b"), str("a")}),
+		},
+		"list-and-set": {
+			a: tftypes.NewValue(listType, []tftypes.Value{str("a")}),
+			b: tftypes.NewValue(setType, []tftypes.Value{str("a")}),
+		},
+		"null-and-empty": {
+			a: tftypes.NewValue(tftypes.String, nil),
+			b: str(""),
+		},
+		"string-boundaries": {
+			a: tftypes.NewValue(listType, []tftypes.Value{str("ab"), str("c")}),
+			b: tftypes.NewValue(listType, []tftypes.Value{str("a"), str("bc")}),
+		},
+		"dynamic-types": {
+			a: dyn(tftypes.NewValue(listType, []tftypes.Value{})),
+			b: dyn(tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, []tftypes.Value{})),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			a, err := Hash(tc.a)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			b, err := Hash(tc.b)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if (a == b) != tc.equal {
+				t.Errorf("expected equal digests to be %v, got %s and %s", tc.equal, a, b)
+			}
+		})
+	}
+}
+
+func TestHashStable(t *testing.T) {
+	got, err := Hash(tftypes.NewValue(tftypes.String, "hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := "50a00c3205fd50132dff5572382edc626984d005d76bc2e0c22c3a7497e7de73"
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestHashUnknown(t *testing.T) {
+	_, err := Hash(server())
+	expectedErr := `AttributeName("listener").ElementKeyInt(1).AttributeName("port"): value is unknown`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+	if !errors.Is(err, ErrUnknown) {
+		t.Errorf("expected error to wrap %q", ErrUnknown)
+	}
+}
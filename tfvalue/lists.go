@@ -0,0 +1,151 @@
+package tfvalue
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Contains reports whether the list `list` has an element equal to `elem`.
+// If `eq` isn't nil, elements that are semantically equal to `elem`
+// according to it also count; it's only called with known, non-null values,
+// like in a tfequal.Registry. Null lists are treated as empty.
+func Contains(list, elem tftypes.Value, eq tfequal.Func) (bool, error) {
+	i, err := Index(list, elem, eq)
+	return i >= 0, err
+}
+
+// Index returns the index of the first element of the list `list` equal to
+// `elem`, or -1 if there isn't one. Elements are compared like in Contains.
+func Index(list, elem tftypes.Value, eq tfequal.Func) (int, error) {
+	elems, err := listElems(list)
+	if err != nil {
+		return -1, err
+	}
+	for i, e := range elems {
+		ok, err := equal(eq, e, elem)
+		if err != nil {
+			return -1, tftypes.NewAttributePath().WithElementKeyInt(i).NewError(err)
+		}
+		if ok {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// Dedupe returns the list `list` without elements equal to an earlier
+// element, keeping the order of the rest. Elements are compared like in
+// Contains, so each group of semantically equal elements is replaced by the
+// first of them. Null lists are returned unchanged.
+func Dedupe(list tftypes.Value, eq tfequal.Func) (tftypes.Value, error) {
+	elems, err := listElems(list)
+	if err != nil || list.IsNull() {
+		return list, err
+	}
+	result := []tftypes.Value{}
+	for i, e := range elems {
+		dup := false
+		for _, r := range result {
+			dup, err = equal(eq, r, e)
+			if err != nil {
+				return tftypes.Value{}, tftypes.NewAttributePath().WithElementKeyInt(i).NewError(err)
+			}
+			if dup {
+				break
+			}
+		}
+		if !dup {
+			result = append(result, e)
+		}
+	}
+	return tftypes.NewValue(list.Type(), result), nil
+}
+
+// Chunk splits the list `list` into lists of the same type with at most
+// `size` elements each, for APIs that limit how many items can be sent in
+// one request:
+//
+//	chunks, err := tfvalue.Chunk(members, 100)
+//	for _, chunk := range chunks {
+//		// add the members in chunk
+//	}
+//
+// Null and empty lists have no chunks.
+func Chunk(list tftypes.Value, size int) ([]tftypes.Value, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("chunk size must be at least 1, got %d", size)
+	}
+	elems, err := listElems(list)
+	if err != nil {
+		return nil, err
+	}
+	var chunks []tftypes.Value
+	for _, c := range ChunkSlice(elems, size) {
+		chunks = append(chunks, tftypes.NewValue(list.Type(), c))
+	}
+	return chunks, nil
+}
+
+// listElems returns the elements of the list `val`.
+func listElems(val tftypes.Value) ([]tftypes.Value, error) {
+	if typ := val.Type(); !typ.Is(tftypes.List{}) {
+		return nil, fmt.Errorf("expected a list, got %s", typ)
+	}
+	if !val.IsKnown() {
+		return nil, ErrUnknown
+	}
+	var elems []tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return nil, err
+	}
+	return elems, nil
+}
+
+// equal reports whether `a` and `b` are equal, or semantically equal
+// according to `eq`.
+func equal(eq tfequal.Func, a, b tftypes.Value) (bool, error) {
+	if a.Equal(b) {
+		return true, nil
+	}
+	if eq == nil || !a.IsKnown() || a.IsNull() || !b.IsKnown() || b.IsNull() {
+		return false, nil
+	}
+	if !a.Type().Equal(b.Type()) {
+		return false, nil
+	}
+	return eq(a, b)
+}
+
+// DedupeSlice returns `s` without elements equal to an earlier element,
+// keeping the order of the rest, for lists decoded into slices. Use the
+// slices package to find elements of slices.
+func DedupeSlice[T comparable](s []T) []T {
+	var result []T
+	seen := make(map[T]bool, len(s))
+	for _, e := range s {
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// ChunkSlice splits `s` into slices with at most `size` elements each. It
+// panics if `size` is less than 1.
+func ChunkSlice[T any](s []T, size int) [][]T {
+	if size < 1 {
+		panic(fmt.Sprintf("tfvalue: chunk size must be at least 1, got %d", size))
+	}
+	var chunks [][]T
+	for len(s) > size {
+		chunks = append(chunks, s[:size:size])
+		s = s[size:]
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
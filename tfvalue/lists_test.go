@@ -0,0 +1,179 @@
+package tfvalue
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfequal"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var stringListType = tftypes.List{ElementType: tftypes.String}
+
+func stringList(elems ...string) tftypes.Value {
+	vals := []tftypes.Value{}
+	for _, e := range elems {
+		vals = append(vals, tftypes.NewValue(tftypes.String, e))
+	}
+	return tftypes.NewValue(stringListType, vals)
+}
+
+func TestIndex(t *testing.T) {
+	type testCase struct {
+		list        tftypes.Value
+		elem        string
+		eq          tfequal.Func
+		expected    int
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"found": {
+			list:     stringList("a", "b", "b"),
+			elem:     "b",
+			expected: 1,
+		},
+		"not-found": {
+			list:     stringList("a", "b"),
+			elem:     "B",
+			expected: -1,
+		},
+		"semantic": {
+			list:     stringList("a", "b"),
+			elem:     "B",
+			eq:       tfequal.CaseInsensitive(),
+			expected: 1,
+		},
+		"null": {
+			list:     tftypes.NewValue(stringListType, nil),
+			elem:     "a",
+			expected: -1,
+		},
+		"unknown": {
+			list:        tftypes.NewValue(stringListType, tftypes.UnknownValue),
+			elem:        "a",
+			expected:    -1,
+			expectedErr: "value is unknown",
+		},
+		"not-a-list": {
+			list:        tftypes.NewValue(tftypes.String, "a"),
+			elem:        "a",
+			expected:    -1,
+			expectedErr: "expected a list, got tftypes.String",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			elem := tftypes.NewValue(tftypes.String, tc.elem)
+			got, err := Index(tc.list, elem, tc.eq)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, got)
+			}
+			contains, _ := Contains(tc.list, elem, tc.eq)
+			if contains != (tc.expected >= 0) {
+				t.Errorf("expected Contains to be %t", tc.expected >= 0)
+			}
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	type testCase struct {
+		list     tftypes.Value
+		eq       tfequal.Func
+		expected tftypes.Value
+	}
+	cases := map[string]testCase{
+		"exact": {
+			list:     stringList("b", "a", "b", "A"),
+			expected: stringList("b", "a", "A"),
+		},
+		"semantic": {
+			list:     stringList("b", "a", "b", "A"),
+			eq:       tfequal.CaseInsensitive(),
+			expected: stringList("b", "a"),
+		},
+		"unknown-elements": {
+			list: tftypes.NewValue(stringListType, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				tftypes.NewValue(tftypes.String, "a"),
+				tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			}),
+			eq: tfequal.CaseInsensitive(),
+			expected: tftypes.NewValue(stringListType, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				tftypes.NewValue(tftypes.String, "a"),
+			}),
+		},
+		"null": {
+			list:     tftypes.NewValue(stringListType, nil),
+			expected: tftypes.NewValue(stringListType, nil),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := Dedupe(tc.list, tc.eq)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestChunk(t *testing.T) {
+	got, err := Chunk(stringList("a", "b", "c", "d", "e"), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []tftypes.Value{stringList("a", "b"), stringList("c", "d"), stringList("e")}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d chunks, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if !got[i].Equal(expected[i]) {
+			t.Errorf("chunk %d: expected %s, got %s", i, expected[i], got[i])
+		}
+	}
+
+	got, err = Chunk(tftypes.NewValue(stringListType, nil), 2)
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected no chunks, got %v, %v", got, err)
+	}
+
+	expectedErr := "chunk size must be at least 1, got 0"
+	if _, err := Chunk(stringList("a"), 0); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestDedupeSlice(t *testing.T) {
+	if diff := cmp.Diff([]string{"b", "a", "c"}, DedupeSlice([]string{"b", "a", "b", "c", "a"})); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestChunkSlice(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	chunks := ChunkSlice(s, 2)
+	if diff := cmp.Diff([][]int{{1, 2}, {3, 4}, {5}}, chunks); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	chunks[0] = append(chunks[0], 6)
+	if s[2] != 3 {
+		t.Errorf("appending to a chunk modified the slice")
+	}
+	if diff := cmp.Diff([][]int(nil), ChunkSlice([]int{}, 2)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
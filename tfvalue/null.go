@@ -0,0 +1,89 @@
+package tfvalue
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// NullPaths returns the paths of the null values in `val`, in the order Walk
+// visits them. It returns the empty path if `val` itself is null.
+func NullPaths(val tftypes.Value) []*tftypes.AttributePath {
+	var paths []*tftypes.AttributePath
+	_ = Walk(val, func(path *tftypes.AttributePath, v tftypes.Value) error {
+		if v.IsKnown() && v.IsNull() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths
+}
+
+// IsWhollyNull returns true if `val` is null, or is an object or tuple whose
+// attributes or elements are all wholly null. Lists, sets, and maps that
+// aren't null aren't wholly null, even if they're empty, as they were set to
+// an empty collection.
+func IsWhollyNull(val tftypes.Value) bool {
+	if !val.IsKnown() {
+		return false
+	}
+	if val.IsNull() {
+		return true
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.Object{}):
+		attrs := map[string]tftypes.Value{}
+		if err := val.As(&attrs); err != nil {
+			return false
+		}
+		for _, attr := range attrs {
+			if !IsWhollyNull(attr) {
+				return false
+			}
+		}
+		return true
+	case typ.Is(tftypes.Tuple{}):
+		elems := []tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return false
+		}
+		for _, elem := range elems {
+			if !IsWhollyNull(elem) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// IsEmpty returns true if `val` is present but empty: a list, set, or map
+// with no elements, or an object that isn't null but is wholly null. This
+// distinguishes blocks that were written in configuration without any
+// arguments from blocks that were omitted, which are null:
+//
+//	timeouts {}
+//
+// IsEmpty returns false for null and unknown values.
+func IsEmpty(val tftypes.Value) bool {
+	if !val.IsKnown() || val.IsNull() {
+		return false
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.Object{}):
+		return IsWhollyNull(val)
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}):
+		elems := []tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return false
+		}
+		return len(elems) == 0
+	case typ.Is(tftypes.Map{}):
+		elems := map[string]tftypes.Value{}
+		if err := val.As(&elems); err != nil {
+			return false
+		}
+		return len(elems) == 0
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package tfvalue
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var timeoutsType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"create": tftypes.String,
+	"delete": tftypes.String,
+}}
+
+func TestNullPaths(t *testing.T) {
+	var got []string
+	for _, path := range NullPaths(server()) {
+		got = append(got, tfpath.String(path))
+	}
+	if diff := cmp.Diff([]string{"listener[1].protocol"}, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	got = nil
+	for _, path := range NullPaths(tftypes.NewValue(timeoutsType, nil)) {
+		got = append(got, tfpath.String(path))
+	}
+	if diff := cmp.Diff([]string{""}, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
+
+func TestIsWhollyNullAndIsEmpty(t *testing.T) {
+	listType := tftypes.List{ElementType: tftypes.String}
+	mapType := tftypes.Map{ElementType: tftypes.String}
+	tupleType := tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, timeoutsType}}
+
+	type testCase struct {
+		val                tftypes.Value
+		expectedWhollyNull bool
+		expectedEmpty      bool
+	}
+	cases := map[string]testCase{
+		"null": {
+			val:                tftypes.NewValue(timeoutsType, nil),
+			expectedWhollyNull: true,
+		},
+		"unknown": {
+			val: tftypes.NewValue(timeoutsType, tftypes.UnknownValue),
+		},
+		"empty-block": {
+			val: tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+				"create": tftypes.NewValue(tftypes.String, nil),
+				"delete": tftypes.NewValue(tftypes.String, nil),
+			}),
+			expectedWhollyNull: true,
+			expectedEmpty:      true,
+		},
+		"block": {
+			val: tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+				"create": tftypes.NewValue(tftypes.String, "10m"),
+				"delete": tftypes.NewValue(tftypes.String, nil),
+			}),
+		},
+		"block-with-unknown": {
+			val: tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+				"create": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"delete": tftypes.NewValue(tftypes.String, nil),
+			}),
+		},
+		"empty-list": {
+			val:           tftypes.NewValue(listType, []tftypes.Value{}),
+			expectedEmpty: true,
+		},
+		"list": {
+			val: tftypes.NewValue(listType, []tftypes.Value{tftypes.NewValue(tftypes.String, nil)}),
+		},
+		"empty-map": {
+			val:           tftypes.NewValue(mapType, map[string]tftypes.Value{}),
+			expectedEmpty: true,
+		},
+		"tuple": {
+			val: tftypes.NewValue(tupleType, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, nil),
+				tftypes.NewValue(timeoutsType, nil),
+			}),
+			expectedWhollyNull: true,
+		},
+		"empty-string": {
+			val: tftypes.NewValue(tftypes.String, ""),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if got := IsWhollyNull(tc.val); got != tc.expectedWhollyNull {
+				t.Errorf("expected IsWhollyNull to return %v, got %v", tc.expectedWhollyNull, got)
+			}
+			if got := IsEmpty(tc.val); got != tc.expectedEmpty {
+				t.Errorf("expected IsEmpty to return %v, got %v", tc.expectedEmpty, got)
+			}
+		})
+	}
+}
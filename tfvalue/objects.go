@@ -0,0 +1,209 @@
+package tfvalue
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/internal/sorted"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Keys returns the attribute names of the object `val`, or the keys of the
+// map `val`, in order. The attribute names of objects come from their type,
+// so they're returned even if the object is unknown or null. Null maps have
+// no keys.
+func Keys(val tftypes.Value) ([]string, error) {
+	typ := val.Type()
+	if obj, ok := typ.(tftypes.Object); ok {
+		keys := make([]string, 0, len(obj.AttributeTypes))
+		for k := range obj.AttributeTypes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys, nil
+	}
+	elems, err := mapElems(val)
+	if err != nil {
+		return nil, err
+	}
+	return sorted.Keys(elems), nil
+}
+
+// Values returns the attributes of the object `val`, or the elements of the
+// map `val`, in the order of their names or keys. Null maps have no values,
+// but null objects return an error wrapping ErrNull.
+func Values(val tftypes.Value) ([]tftypes.Value, error) {
+	if val.Type().Is(tftypes.Object{}) && val.IsKnown() && val.IsNull() {
+		return nil, ErrNull
+	}
+	elems, err := mapElems(val)
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]tftypes.Value, 0, len(elems))
+	for _, k := range sorted.Keys(elems) {
+		vals = append(vals, elems[k])
+	}
+	return vals, nil
+}
+
+// Pick returns the object `val` with only the attributes named `names`, or
+// the map `val` with only the elements with those keys, such as to build an
+// API payload from the attributes of a configuration it accepts:
+//
+//	payload, err := tfvalue.Pick(config, "name", "description", "tags")
+//
+// The type of the result is adjusted to match. Every name must be an
+// attribute of an object, but keys that aren't in a map are ignored.
+// Unknown and null objects result in unknown and null objects of the new
+// type.
+func Pick(val tftypes.Value, names ...string) (tftypes.Value, error) {
+	picked := make(map[string]bool, len(names))
+	for _, name := range names {
+		picked[name] = true
+	}
+	if err := checkAttributes(val.Type(), names); err != nil {
+		return tftypes.Value{}, err
+	}
+	return renameKeys(val, func(k string) (string, bool) { return k, picked[k] })
+}
+
+// Omit returns the object `val` without the attributes named `names`, or the
+// map `val` without the elements with those keys, adjusting its type like
+// Pick. Names that aren't attributes or keys are ignored.
+func Omit(val tftypes.Value, names ...string) (tftypes.Value, error) {
+	omitted := make(map[string]bool, len(names))
+	for _, name := range names {
+		omitted[name] = true
+	}
+	return renameKeys(val, func(k string) (string, bool) { return k, !omitted[k] })
+}
+
+// Rename returns the object `val` with its attributes renamed, or the map
+// `val` with its keys changed, according to `names`, which maps the old
+// names to the new ones, adjusting its type like Pick. Renaming is
+// simultaneous, so names can be swapped, but it's an error for two
+// attributes or elements to end up with the same name. Every old name must
+// be an attribute of an object, but keys that aren't in a map are ignored.
+func Rename(val tftypes.Value, names map[string]string) (tftypes.Value, error) {
+	old := make([]string, 0, len(names))
+	for k := range names {
+		old = append(old, k)
+	}
+	sort.Strings(old)
+	if err := checkAttributes(val.Type(), old); err != nil {
+		return tftypes.Value{}, err
+	}
+	return renameKeys(val, func(k string) (string, bool) {
+		if n, ok := names[k]; ok {
+			return n, true
+		}
+		return k, true
+	})
+}
+
+// checkAttributes returns an error if `typ` is an object type without all
+// the attributes named `names`.
+func checkAttributes(typ tftypes.Type, names []string) error {
+	obj, ok := typ.(tftypes.Object)
+	if !ok {
+		return nil
+	}
+	for _, name := range names {
+		if _, ok := obj.AttributeTypes[name]; !ok {
+			return fmt.Errorf("attribute %q %w", name, ErrNotFound)
+		}
+	}
+	return nil
+}
+
+// renameKeys returns the object or map `val` with each attribute or element
+// renamed to the name returned by `f`, or dropped if it returns false.
+func renameKeys(val tftypes.Value, f func(k string) (string, bool)) (tftypes.Value, error) {
+	typ := val.Type()
+	if !typ.Is(tftypes.Object{}) && !typ.Is(tftypes.Map{}) {
+		return tftypes.Value{}, fmt.Errorf("expected an object or map, got %s", typ)
+	}
+	renamed := map[string]string{}
+	rename := func(k string) (string, bool, error) {
+		n, ok := f(k)
+		if !ok {
+			return "", false, nil
+		}
+		if prev, ok := renamed[n]; ok {
+			return "", false, fmt.Errorf("can't rename both %q and %q to %q", prev, k, n)
+		}
+		renamed[n] = k
+		return n, true, nil
+	}
+	if obj, ok := typ.(tftypes.Object); ok {
+		result := tftypes.Object{AttributeTypes: map[string]tftypes.Type{}}
+		names := make([]string, 0, len(obj.AttributeTypes))
+		for k := range obj.AttributeTypes {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			n, ok, err := rename(k)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			if !ok {
+				continue
+			}
+			result.AttributeTypes[n] = obj.AttributeTypes[k]
+			if _, ok := obj.OptionalAttributes[k]; ok {
+				if result.OptionalAttributes == nil {
+					result.OptionalAttributes = map[string]struct{}{}
+				}
+				result.OptionalAttributes[n] = struct{}{}
+			}
+		}
+		typ = result
+	}
+	if !val.IsKnown() {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	if val.IsNull() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	elems, err := mapElems(val)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if typ.Is(tftypes.Object{}) {
+		// the attributes were renamed with the type
+		result := make(map[string]tftypes.Value, len(renamed))
+		for n, k := range renamed {
+			result[n] = elems[k]
+		}
+		return tftypes.NewValue(typ, result), nil
+	}
+	result := make(map[string]tftypes.Value, len(elems))
+	for _, k := range sorted.Keys(elems) {
+		n, ok, err := rename(k)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		if ok {
+			result[n] = elems[k]
+		}
+	}
+	return tftypes.NewValue(typ, result), nil
+}
+
+// mapElems returns the attributes of the object `val`, or the elements of the
+// map `val`.
+func mapElems(val tftypes.Value) (map[string]tftypes.Value, error) {
+	if typ := val.Type(); !typ.Is(tftypes.Object{}) && !typ.Is(tftypes.Map{}) {
+		return nil, fmt.Errorf("expected an object or map, got %s", typ)
+	}
+	if !val.IsKnown() {
+		return nil, ErrUnknown
+	}
+	elems := map[string]tftypes.Value{}
+	if err := val.As(&elems); err != nil {
+		return nil, err
+	}
+	return elems, nil
+}
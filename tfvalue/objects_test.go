@@ -0,0 +1,216 @@
+package tfvalue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var stringMapType = tftypes.Map{ElementType: tftypes.String}
+
+func stringMap(elems map[string]string) tftypes.Value {
+	vals := map[string]tftypes.Value{}
+	for k, v := range elems {
+		vals[k] = tftypes.NewValue(tftypes.String, v)
+	}
+	return tftypes.NewValue(stringMapType, vals)
+}
+
+func TestKeys(t *testing.T) {
+	type testCase struct {
+		val         tftypes.Value
+		expected    []string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"object": {
+			val:      listener(80, "http"),
+			expected: []string{"port", "protocol"},
+		},
+		"unknown-object": {
+			val:      tftypes.NewValue(listenerType, tftypes.UnknownValue),
+			expected: []string{"port", "protocol"},
+		},
+		"map": {
+			val:      stringMap(map[string]string{"b": "1", "a": "2"}),
+			expected: []string{"a", "b"},
+		},
+		"null-map": {
+			val:      tftypes.NewValue(stringMapType, nil),
+			expected: []string{},
+		},
+		"unknown-map": {
+			val:         tftypes.NewValue(stringMapType, tftypes.UnknownValue),
+			expectedErr: "value is unknown",
+		},
+		"list": {
+			val:         stringList("a"),
+			expectedErr: "expected an object or map, got tftypes.List[tftypes.String]",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := Keys(tc.val)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestValues(t *testing.T) {
+	got, err := Values(stringMap(map[string]string{"b": "1", "a": "2"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []tftypes.Value{tftypes.NewValue(tftypes.String, "2"), tftypes.NewValue(tftypes.String, "1")}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	got, err = Values(listener(80, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected = []tftypes.Value{tftypes.NewValue(tftypes.Number, 80), tftypes.NewValue(tftypes.String, nil)}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	_, err = Values(tftypes.NewValue(listenerType, nil))
+	if !errors.Is(err, ErrNull) {
+		t.Errorf("expected error wrapping %q, got %v", ErrNull, err)
+	}
+}
+
+func TestPickOmitRename(t *testing.T) {
+	renamedType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"number":   tftypes.Number,
+		"protocol": tftypes.String,
+	}}
+	type testCase struct {
+		fn          func(tftypes.Value) (tftypes.Value, error)
+		val         tftypes.Value
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"pick-object": {
+			fn:  func(v tftypes.Value) (tftypes.Value, error) { return Pick(v, "name", "tags") },
+			val: server(),
+			expected: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"name": tftypes.String,
+				"tags": stringMapType,
+			}}, map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "web"),
+				"tags": stringMap(map[string]string{"env": "prod"}),
+			}),
+		},
+		"pick-missing-attribute": {
+			fn:          func(v tftypes.Value) (tftypes.Value, error) { return Pick(v, "name", "id") },
+			val:         server(),
+			expectedErr: `attribute "id" not found`,
+		},
+		"pick-map": {
+			fn:       func(v tftypes.Value) (tftypes.Value, error) { return Pick(v, "a", "c") },
+			val:      stringMap(map[string]string{"a": "1", "b": "2"}),
+			expected: stringMap(map[string]string{"a": "1"}),
+		},
+		"pick-unknown": {
+			fn:       func(v tftypes.Value) (tftypes.Value, error) { return Pick(v, "port") },
+			val:      tftypes.NewValue(listenerType, tftypes.UnknownValue),
+			expected: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"port": tftypes.Number}}, tftypes.UnknownValue),
+		},
+		"omit-object": {
+			fn:  func(v tftypes.Value) (tftypes.Value, error) { return Omit(v, "protocol", "id") },
+			val: listener(80, "http"),
+			expected: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"port": tftypes.Number,
+			}}, map[string]tftypes.Value{
+				"port": tftypes.NewValue(tftypes.Number, 80),
+			}),
+		},
+		"omit-map": {
+			fn:       func(v tftypes.Value) (tftypes.Value, error) { return Omit(v, "a") },
+			val:      stringMap(map[string]string{"a": "1", "b": "2"}),
+			expected: stringMap(map[string]string{"b": "2"}),
+		},
+		"omit-optional": {
+			fn: func(v tftypes.Value) (tftypes.Value, error) { return Omit(v, "port") },
+			val: tftypes.NewValue(tftypes.Object{
+				AttributeTypes:     listenerType.AttributeTypes,
+				OptionalAttributes: map[string]struct{}{"port": {}, "protocol": {}},
+			}, nil),
+			expected: tftypes.NewValue(tftypes.Object{
+				AttributeTypes:     map[string]tftypes.Type{"protocol": tftypes.String},
+				OptionalAttributes: map[string]struct{}{"protocol": {}},
+			}, nil),
+		},
+		"rename-object": {
+			fn: func(v tftypes.Value) (tftypes.Value, error) {
+				return Rename(v, map[string]string{"port": "number"})
+			},
+			val: listener(80, "http"),
+			expected: tftypes.NewValue(renamedType, map[string]tftypes.Value{
+				"number":   tftypes.NewValue(tftypes.Number, 80),
+				"protocol": tftypes.NewValue(tftypes.String, "http"),
+			}),
+		},
+		"rename-swap": {
+			fn: func(v tftypes.Value) (tftypes.Value, error) {
+				return Rename(v, map[string]string{"a": "b", "b": "a"})
+			},
+			val:      stringMap(map[string]string{"a": "1", "b": "2"}),
+			expected: stringMap(map[string]string{"a": "2", "b": "1"}),
+		},
+		"rename-conflict": {
+			fn: func(v tftypes.Value) (tftypes.Value, error) {
+				return Rename(v, map[string]string{"port": "protocol"})
+			},
+			val:         listener(80, "http"),
+			expectedErr: `can't rename both "port" and "protocol" to "protocol"`,
+		},
+		"rename-missing-attribute": {
+			fn: func(v tftypes.Value) (tftypes.Value, error) {
+				return Rename(v, map[string]string{"address": "host"})
+			},
+			val:         listener(80, "http"),
+			expectedErr: `attribute "address" not found`,
+		},
+		"not-an-object": {
+			fn:          func(v tftypes.Value) (tftypes.Value, error) { return Omit(v, "a") },
+			val:         tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			expectedErr: "expected an object or map, got tftypes.String",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.fn(tc.val)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package tfvalue
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Set returns a copy of `val` with the value at `path` replaced by `newVal`.
+// `val` isn't modified. Setting an element of a map with a key that isn't in
+// it inserts the element, but other steps of `path` must refer to
+// attributes and elements that exist.
+//
+// `newVal` must be usable as the type of the value it replaces, as declared
+// by its parent: the element type of a collection, or the type of an
+// attribute or tuple element. Replacing an element of a set with a value
+// that's already in it removes the element, as sets can't contain
+// duplicates.
+//
+// Errors are tftypes.AttributePathErrors, and wrap ErrNotFound, ErrUnknown,
+// and ErrNull like those returned by Get.
+func Set(val tftypes.Value, path *tftypes.AttributePath, newVal tftypes.Value) (tftypes.Value, error) {
+	return replace(tftypes.NewAttributePath(), val, val.Type(), path.Steps(), func(p *tftypes.AttributePath, typ tftypes.Type, _ tftypes.Value) (tftypes.Value, error) {
+		if !newVal.Type().UsableAs(typ) {
+			return tftypes.Value{}, p.NewErrorf("can't use %s as %s", newVal.Type(), typ)
+		}
+		return newVal, nil
+	})
+}
+
+// replaceFunc returns the value to replace `old`, found at `path` and
+// declared as `typ`, with.
+type replaceFunc func(path *tftypes.AttributePath, typ tftypes.Type, old tftypes.Value) (tftypes.Value, error)
+
+// replace returns `val`, found at `path` and declared as `typ`, with the
+// value at `steps` replaced by the result of `f`.
+func replace(path *tftypes.AttributePath, val tftypes.Value, typ tftypes.Type, steps []tftypes.AttributePathStep, f replaceFunc) (tftypes.Value, error) {
+	if len(steps) == 0 {
+		return f(path, typ, val)
+	}
+	if !val.IsKnown() {
+		return tftypes.Value{}, path.NewError(ErrUnknown)
+	}
+	if val.IsNull() {
+		return tftypes.Value{}, path.NewError(ErrNull)
+	}
+	step, rest := steps[0], steps[1:]
+	next := tftypes.NewAttributePathWithSteps(append(path.Steps(), step))
+	switch t := val.Type().(type) {
+	case tftypes.Object:
+		name, ok := step.(tftypes.AttributeName)
+		if !ok {
+			break
+		}
+		attrs, err := copyMap(val)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		attr, ok := attrs[string(name)]
+		if !ok {
+			return tftypes.Value{}, path.NewError(fmt.Errorf("attribute %q %w", string(name), ErrNotFound))
+		}
+		attr, err = replace(next, attr, t.AttributeTypes[string(name)], rest, f)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		attrs[string(name)] = attr
+		return rebuild(path, t, attrs)
+	case tftypes.List, tftypes.Tuple:
+		i, ok := step.(tftypes.ElementKeyInt)
+		if !ok {
+			break
+		}
+		elems, err := copySlice(val)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		if i < 0 || int64(i) >= int64(len(elems)) {
+			return tftypes.Value{}, path.NewError(fmt.Errorf("element %d %w, there are %d elements", int64(i), ErrNotFound, len(elems)))
+		}
+		var elemType tftypes.Type
+		if list, ok := t.(tftypes.List); ok {
+			elemType = list.ElementType
+		} else {
+			elemType = t.(tftypes.Tuple).ElementTypes[i]
+		}
+		elem, err := replace(next, elems[i], elemType, rest, f)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems[i] = elem
+		return rebuild(path, t, elems)
+	case tftypes.Map:
+		key, ok := step.(tftypes.ElementKeyString)
+		if !ok {
+			break
+		}
+		elems, err := copyMap(val)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		elem, ok := elems[string(key)]
+		if !ok {
+			if len(rest) > 0 {
+				return tftypes.Value{}, path.NewError(fmt.Errorf("element %q %w", string(key), ErrNotFound))
+			}
+			elem = tftypes.NewValue(t.ElementType, nil)
+		}
+		elem, err = replace(next, elem, t.ElementType, rest, f)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems[string(key)] = elem
+		return rebuild(path, t, elems)
+	case tftypes.Set:
+		key, ok := step.(tftypes.ElementKeyValue)
+		if !ok {
+			break
+		}
+		elems, err := copySlice(val)
+		if err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		i := indexOf(elems, tftypes.Value(key))
+		if i < 0 {
+			return tftypes.Value{}, path.NewError(fmt.Errorf("element %s %w", tftypes.Value(key), ErrNotFound))
+		}
+		elem, err := replace(next, elems[i], t.ElementType, rest, f)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems[:i], elems[i+1:]...)
+		if indexOf(elems, elem) < 0 {
+			elems = append(elems[:i], append([]tftypes.Value{elem}, elems[i:]...)...)
+		}
+		return rebuild(path, t, elems)
+	}
+	return tftypes.Value{}, path.NewError(stepError(step, val.Type()))
+}
+
+// copyMap returns the attributes or elements of `val`, an object or map, in
+// a map that can be modified. As returns the map the value holds, which
+// mustn't be.
+func copyMap(val tftypes.Value) (map[string]tftypes.Value, error) {
+	var m map[string]tftypes.Value
+	if err := val.As(&m); err != nil {
+		return nil, err
+	}
+	c := make(map[string]tftypes.Value, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c, nil
+}
+
+// copySlice returns the elements of `val`, a list, set, or tuple, in a slice
+// that can be modified.
+func copySlice(val tftypes.Value) ([]tftypes.Value, error) {
+	var s []tftypes.Value
+	if err := val.As(&s); err != nil {
+		return nil, err
+	}
+	return append([]tftypes.Value(nil), s...), nil
+}
+
+// rebuild returns a value of type `typ` holding `elems`.
+func rebuild(path *tftypes.AttributePath, typ tftypes.Type, elems interface{}) (tftypes.Value, error) {
+	if err := tftypes.ValidateValue(typ, elems); err != nil {
+		return tftypes.Value{}, path.NewError(err)
+	}
+	return tftypes.NewValue(typ, elems), nil
+}
+
+func indexOf(elems []tftypes.Value, val tftypes.Value) int {
+	for i, elem := range elems {
+		if elem.Equal(val) {
+			return i
+		}
+	}
+	return -1
+}
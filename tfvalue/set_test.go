@@ -0,0 +1,159 @@
+package tfvalue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSet(t *testing.T) {
+	type testCase struct {
+		path        string
+		val         tftypes.Value
+		expectedErr string
+		is          error
+	}
+	cases := map[string]testCase{
+		"attribute": {
+			path: "name",
+			val:  tftypes.NewValue(tftypes.String, "api"),
+		},
+		"unknown-attribute": {
+			path: "name",
+			val:  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		},
+		"list-element-attribute": {
+			path: "listener[1].port",
+			val:  tftypes.NewValue(tftypes.Number, 443),
+		},
+		"list-element": {
+			path: "listener[0]",
+			val:  listener(8080, nil),
+		},
+		"map-element": {
+			path: `tags["env"]`,
+			val:  tftypes.NewValue(tftypes.String, "dev"),
+		},
+		"map-insert": {
+			path: `tags["team"]`,
+			val:  tftypes.NewValue(tftypes.String, "core"),
+		},
+		"tuple-element": {
+			path: "pair[1]",
+			val:  tftypes.NewValue(tftypes.Bool, false),
+		},
+		"dynamic": {
+			path: "extra",
+			val:  tftypes.NewValue(tftypes.Number, 1),
+		},
+		"wrong-type": {
+			path:        "listener[0].port",
+			val:         tftypes.NewValue(tftypes.String, "80"),
+			expectedErr: `AttributeName("listener").ElementKeyInt(0).AttributeName("port"): can't use tftypes.String as tftypes.Number`,
+		},
+		"missing-attribute": {
+			path:        "listener[0].address",
+			val:         tftypes.NewValue(tftypes.String, "::"),
+			expectedErr: `AttributeName("listener").ElementKeyInt(0): attribute "address" not found`,
+			is:          ErrNotFound,
+		},
+		"out-of-range": {
+			path:        "listener[2].port",
+			val:         tftypes.NewValue(tftypes.Number, 1),
+			expectedErr: `AttributeName("listener"): element 2 not found, there are 2 elements`,
+			is:          ErrNotFound,
+		},
+		"missing-key-parent": {
+			path:        `tags["team"].x`,
+			val:         tftypes.NewValue(tftypes.String, "core"),
+			expectedErr: `AttributeName("tags"): element "team" not found`,
+			is:          ErrNotFound,
+		},
+		"through-unknown": {
+			path:        "listener[1].port.x",
+			val:         tftypes.NewValue(tftypes.String, "x"),
+			expectedErr: `AttributeName("listener").ElementKeyInt(1).AttributeName("port"): value is unknown`,
+			is:          ErrUnknown,
+		},
+		"wrong-step": {
+			path:        "name[0]",
+			val:         tftypes.NewValue(tftypes.String, "x"),
+			expectedErr: `AttributeName("name"): can't get element 0 of tftypes.String`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			original := server()
+			path := tfpath.MustParse(tc.path)
+			got, err := Set(original, path, tc.val)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				if tc.is != nil && !errors.Is(err, tc.is) {
+					t.Errorf("expected error to wrap %q", tc.is)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !original.Equal(server()) {
+				t.Errorf("original value was modified: %s", original)
+			}
+			if !got.Type().Equal(serverType) {
+				t.Errorf("expected type %s, got %s", serverType, got.Type())
+			}
+			val, err := Get(got, path)
+			if err != nil {
+				t.Fatalf("unexpected error getting value: %s", err)
+			}
+			if !val.Equal(tc.val) {
+				t.Errorf("expected %s, got %s", tc.val, val)
+			}
+			name, err := Get(got, tfpath.MustParse("name"))
+			if err != nil {
+				t.Fatalf("unexpected error getting name: %s", err)
+			}
+			if tc.path != "name" && !name.Equal(tftypes.NewValue(tftypes.String, "web")) {
+				t.Errorf("unrelated attribute changed to %s", name)
+			}
+		})
+	}
+}
+
+func TestSetRoot(t *testing.T) {
+	val := tftypes.NewValue(tftypes.String, "a")
+	got, err := Set(val, nil, tftypes.NewValue(tftypes.String, "b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := tftypes.NewValue(tftypes.String, "b"); !got.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSetSetElement(t *testing.T) {
+	setType := tftypes.Set{ElementType: tftypes.String}
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	val := tftypes.NewValue(setType, []tftypes.Value{str("a"), str("b")})
+
+	got, err := Set(val, tftypes.NewAttributePath().WithElementKeyValue(str("a")), str("c"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := tftypes.NewValue(setType, []tftypes.Value{str("c"), str("b")}); !got.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	got, err = Set(val, tftypes.NewAttributePath().WithElementKeyValue(str("a")), str("b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := tftypes.NewValue(setType, []tftypes.Value{str("b")}); !got.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
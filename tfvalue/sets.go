@@ -0,0 +1,136 @@
+package tfvalue
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Union returns a set of the elements in either of the sets `a` and `b`,
+// which must have the same type and be fully known. Null sets are treated as
+// empty. Elements are compared by their Hash, so the result takes time
+// proportional to the number of elements.
+func Union(a, b tftypes.Value) (tftypes.Value, error) {
+	return setOp(a, b, func(inA, inB bool) bool { return inA || inB })
+}
+
+// Intersect returns a set of the elements in both of the sets `a` and `b`,
+// which must be fully known sets of the same type, like Union.
+func Intersect(a, b tftypes.Value) (tftypes.Value, error) {
+	return setOp(a, b, func(inA, inB bool) bool { return inA && inB })
+}
+
+// Difference returns a set of the elements in the set `a` that aren't in the
+// set `b`, which must be fully known sets of the same type, like Union. The
+// elements to add to and remove from a remote collection to get from
+// `prior` to `planned` are:
+//
+//	attach, err := tfvalue.Difference(planned, prior)
+//	detach, err := tfvalue.Difference(prior, planned)
+func Difference(a, b tftypes.Value) (tftypes.Value, error) {
+	return setOp(a, b, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// setOp returns a set of the elements of `a` and `b`, in order, for which
+// `keep` returns true.
+func setOp(a, b tftypes.Value, keep func(inA, inB bool) bool) (tftypes.Value, error) {
+	typ := a.Type()
+	if !typ.Is(tftypes.Set{}) {
+		return tftypes.Value{}, fmt.Errorf("expected a set, got %s", typ)
+	}
+	if !b.Type().Equal(typ) {
+		return tftypes.Value{}, fmt.Errorf("can't combine %s with %s", typ, b.Type())
+	}
+	setA, err := hashSet(a)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	setB, err := hashSet(b)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	result := []tftypes.Value{}
+	seen := map[string]bool{}
+	for _, set := range []hashedSet{setA, setB} {
+		for i, elem := range set.elems {
+			h := set.hashes[i]
+			if seen[h] {
+				continue
+			}
+			seen[h] = true
+			if keep(setA.has[h], setB.has[h]) {
+				result = append(result, elem)
+			}
+		}
+	}
+	return tftypes.NewValue(typ, result), nil
+}
+
+// hashedSet is the elements of a set, and their Hashes.
+type hashedSet struct {
+	elems  []tftypes.Value
+	hashes []string
+	has    map[string]bool
+}
+
+func hashSet(val tftypes.Value) (hashedSet, error) {
+	if !val.IsKnown() {
+		return hashedSet{}, ErrUnknown
+	}
+	set := hashedSet{has: map[string]bool{}}
+	if err := val.As(&set.elems); err != nil {
+		return hashedSet{}, err
+	}
+	for _, elem := range set.elems {
+		h, err := Hash(elem)
+		if err != nil {
+			return hashedSet{}, tftypes.NewAttributePath().WithElementKeyValue(elem).NewError(err)
+		}
+		set.hashes = append(set.hashes, h)
+		set.has[h] = true
+	}
+	return set, nil
+}
+
+// UnionSlices returns the elements in either of `a` and `b`, in the order
+// they first appear, without duplicates, for sets decoded into slices.
+func UnionSlices[T comparable](a, b []T) []T {
+	return sliceOp(a, b, func(inA, inB bool) bool { return inA || inB })
+}
+
+// IntersectSlices returns the elements in both `a` and `b`, in the order they
+// first appear, without duplicates.
+func IntersectSlices[T comparable](a, b []T) []T {
+	return sliceOp(a, b, func(inA, inB bool) bool { return inA && inB })
+}
+
+// DifferenceSlices returns the elements in `a` that aren't in `b`, in the
+// order they appear, without duplicates.
+func DifferenceSlices[T comparable](a, b []T) []T {
+	return sliceOp(a, b, func(inA, inB bool) bool { return inA && !inB })
+}
+
+func sliceOp[T comparable](a, b []T, keep func(inA, inB bool) bool) []T {
+	inA := make(map[T]bool, len(a))
+	for _, e := range a {
+		inA[e] = true
+	}
+	inB := make(map[T]bool, len(b))
+	for _, e := range b {
+		inB[e] = true
+	}
+	var result []T
+	seen := make(map[T]bool, len(a)+len(b))
+	for _, s := range [][]T{a, b} {
+		for _, e := range s {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			if keep(inA[e], inB[e]) {
+				result = append(result, e)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package tfvalue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSetOperations(t *testing.T) {
+	setType := tftypes.Set{ElementType: tftypes.String}
+	set := func(elems ...string) tftypes.Value {
+		vals := []tftypes.Value{}
+		for _, e := range elems {
+			vals = append(vals, tftypes.NewValue(tftypes.String, e))
+		}
+		return tftypes.NewValue(setType, vals)
+	}
+	null := tftypes.NewValue(setType, nil)
+
+	type testCase struct {
+		op          func(a, b tftypes.Value) (tftypes.Value, error)
+		a, b        tftypes.Value
+		expected    tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"union": {
+			op:       Union,
+			a:        set("a", "b"),
+			b:        set("c", "b"),
+			expected: set("a", "b", "c"),
+		},
+		"union-null": {
+			op:       Union,
+			a:        null,
+			b:        set("a"),
+			expected: set("a"),
+		},
+		"intersect": {
+			op:       Intersect,
+			a:        set("a", "b", "c"),
+			b:        set("d", "c", "a"),
+			expected: set("a", "c"),
+		},
+		"intersect-empty": {
+			op:       Intersect,
+			a:        set("a"),
+			b:        null,
+			expected: set(),
+		},
+		"difference": {
+			op:       Difference,
+			a:        set("a", "b", "c"),
+			b:        set("b"),
+			expected: set("a", "c"),
+		},
+		"different-types": {
+			op:          Union,
+			a:           set("a"),
+			b:           tftypes.NewValue(tftypes.Set{ElementType: tftypes.Number}, []tftypes.Value{}),
+			expectedErr: "can't combine tftypes.Set[tftypes.String] with tftypes.Set[tftypes.Number]",
+		},
+		"not-a-set": {
+			op:          Union,
+			a:           tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{}),
+			b:           set("a"),
+			expectedErr: "expected a set, got tftypes.List[tftypes.String]",
+		},
+		"unknown-element": {
+			op:          Difference,
+			a:           set("a"),
+			b:           tftypes.NewValue(setType, []tftypes.Value{tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}),
+			expectedErr: "value is unknown",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.op(tc.a, tc.b)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+
+	_, err := Union(tftypes.NewValue(setType, tftypes.UnknownValue), set())
+	if !errors.Is(err, ErrUnknown) {
+		t.Errorf("expected error wrapping %q, got %v", ErrUnknown, err)
+	}
+}
+
+func TestSliceOperations(t *testing.T) {
+	a := []string{"a", "b", "a", "c"}
+	b := []string{"d", "c", "b"}
+	if diff := cmp.Diff([]string{"a", "b", "c", "d"}, UnionSlices(a, b)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"b", "c"}, IntersectSlices(a, b)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"a"}, DifferenceSlices(a, b)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]int(nil), DifferenceSlices([]int{1}, []int{1, 2})); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}
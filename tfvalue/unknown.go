@@ -0,0 +1,45 @@
+package tfvalue
+
+import (
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// UnknownPaths returns the paths of the unknown values in `val`, in the
+// order Walk visits them. It returns the empty path if `val` itself is
+// unknown, and nil if `val` is fully known.
+func UnknownPaths(val tftypes.Value) []*tftypes.AttributePath {
+	var paths []*tftypes.AttributePath
+	_ = Walk(val, func(path *tftypes.AttributePath, v tftypes.Value) error {
+		if !v.IsKnown() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths
+}
+
+// MarkUnknown returns a copy of `val` with the values at `paths` replaced by
+// unknown values of the types declared for them, like when planning computed
+// attributes whose values won't be known until apply:
+//
+//	planned, err := tfvalue.MarkUnknown(proposed, tfpath.MustParse("id"), tfpath.MustParse("endpoint"))
+//
+// Paths that traverse values that are already unknown are ignored. Other
+// paths must refer to values that exist, and errors are returned like Set.
+func MarkUnknown(val tftypes.Value, paths ...*tftypes.AttributePath) (tftypes.Value, error) {
+	for _, path := range paths {
+		marked, err := replace(tftypes.NewAttributePath(), val, val.Type(), path.Steps(), func(_ *tftypes.AttributePath, typ tftypes.Type, _ tftypes.Value) (tftypes.Value, error) {
+			return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+		})
+		if errors.Is(err, ErrUnknown) {
+			continue
+		}
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		val = marked
+	}
+	return val, nil
+}
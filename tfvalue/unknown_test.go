@@ -0,0 +1,112 @@
+package tfvalue
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUnknownPaths(t *testing.T) {
+	withUnknowns, err := Set(server(), tfpath.MustParse(`tags["env"]`), tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	type testCase struct {
+		val      tftypes.Value
+		expected []string
+	}
+	cases := map[string]testCase{
+		"known": {
+			val: tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "a"),
+				tftypes.NewValue(tftypes.String, nil),
+			}),
+		},
+		"unknown": {
+			val:      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			expected: []string{""},
+		},
+		"nested": {
+			val:      withUnknowns,
+			expected: []string{"listener[1].port", `tags["env"]`},
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, path := range UnknownPaths(tc.val) {
+				got = append(got, tfpath.String(path))
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestMarkUnknown(t *testing.T) {
+	unknown := func(typ tftypes.Type) tftypes.Value { return tftypes.NewValue(typ, tftypes.UnknownValue) }
+
+	type testCase struct {
+		paths       []string
+		expected    map[string]tftypes.Value
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"none": {
+			expected: map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "web")},
+		},
+		"attributes": {
+			paths: []string{"name", "listener[0].port", "extra"},
+			expected: map[string]tftypes.Value{
+				"name":             unknown(tftypes.String),
+				"listener[0].port": unknown(tftypes.Number),
+				"extra":            unknown(tftypes.DynamicPseudoType),
+			},
+		},
+		"collection": {
+			paths:    []string{"listener"},
+			expected: map[string]tftypes.Value{"listener": unknown(tftypes.List{ElementType: listenerType})},
+		},
+		"through-unknown": {
+			paths:    []string{"listener[1].port.x", "tags"},
+			expected: map[string]tftypes.Value{"tags": unknown(tftypes.Map{ElementType: tftypes.String})},
+		},
+		"missing": {
+			paths:       []string{"name", "listener[5].port"},
+			expectedErr: `AttributeName("listener"): element 5 not found, there are 2 elements`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var paths []*tftypes.AttributePath
+			for _, p := range tc.paths {
+				paths = append(paths, tfpath.MustParse(p))
+			}
+			got, err := MarkUnknown(server(), paths...)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for p, expected := range tc.expected {
+				val, err := Get(got, tfpath.MustParse(p))
+				if err != nil {
+					t.Fatalf("unexpected error getting %s: %s", p, err)
+				}
+				if !val.Equal(expected) {
+					t.Errorf("%s: expected %s, got %s", p, expected, val)
+				}
+			}
+		})
+	}
+}
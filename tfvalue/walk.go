@@ -0,0 +1,89 @@
+package tfvalue
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	// SkipChildren can be returned by a WalkFunc to skip the attributes or
+	// elements of the value it was called with.
+	SkipChildren = errors.New("skip children")
+
+	// SkipAll can be returned by a WalkFunc to stop walking.
+	SkipAll = errors.New("skip all")
+)
+
+// WalkFunc is called by Walk for each value it visits, with the path of the
+// value. If it returns SkipChildren, the value's attributes or elements
+// aren't visited, and if it returns SkipAll, no more values are visited.
+// Any other error stops the walk, and is returned by Walk.
+type WalkFunc func(path *tftypes.AttributePath, val tftypes.Value) error
+
+// Walk calls `fn` for `val` and every value nested in it, depth-first, with
+// each value visited before its attributes or elements. Attributes of objects
+// and elements of maps are visited in order of their names and keys, and
+// elements of lists, sets, and tuples in order, so the order is the same
+// every time. Unknown and null values are visited, but have no children.
+//
+// Unlike tftypes.Walk, Walk can stop early, and passes the paths of set
+// elements with ElementKeyValue steps.
+func Walk(val tftypes.Value, fn WalkFunc) error {
+	err := walk(tftypes.NewAttributePath(), val, fn)
+	if err == SkipAll || err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+func walk(path *tftypes.AttributePath, val tftypes.Value, fn WalkFunc) error {
+	err := fn(path, val)
+	if err == SkipChildren {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !val.IsKnown() || val.IsNull() {
+		return nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		children := map[string]tftypes.Value{}
+		if err := val.As(&children); err != nil {
+			return path.NewError(err)
+		}
+		keys := make([]string, 0, len(children))
+		for k := range children {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			next := path.WithAttributeName(k)
+			if typ.Is(tftypes.Map{}) {
+				next = path.WithElementKeyString(k)
+			}
+			if err := walk(next, children[k], fn); err != nil {
+				return err
+			}
+		}
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		children := []tftypes.Value{}
+		if err := val.As(&children); err != nil {
+			return path.NewError(err)
+		}
+		for i, child := range children {
+			next := path.WithElementKeyInt(i)
+			if typ.Is(tftypes.Set{}) {
+				next = path.WithElementKeyValue(child)
+			}
+			if err := walk(next, child, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
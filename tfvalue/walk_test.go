@@ -0,0 +1,132 @@
+package tfvalue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/tfpath"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestWalk(t *testing.T) {
+	type testCase struct {
+		fn          func(path string) error
+		expected    []string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"all": {
+			fn: func(string) error { return nil },
+			expected: []string{
+				"",
+				"aliases",
+				"aliases[...]",
+				"extra",
+				"extra[0]",
+				"listener",
+				"listener[0]",
+				"listener[0].port",
+				"listener[0].protocol",
+				"listener[1]",
+				"listener[1].port",
+				"listener[1].protocol",
+				"name",
+				"pair",
+				"pair[0]",
+				"pair[1]",
+				"tags",
+				`tags["env"]`,
+			},
+		},
+		"skip-children": {
+			fn: func(path string) error {
+				if path == "listener" || path == "pair" {
+					return SkipChildren
+				}
+				return nil
+			},
+			expected: []string{
+				"",
+				"aliases",
+				"aliases[...]",
+				"extra",
+				"extra[0]",
+				"listener",
+				"name",
+				"pair",
+				"tags",
+				`tags["env"]`,
+			},
+		},
+		"skip-all": {
+			fn: func(path string) error {
+				if path == "listener[0].port" {
+					return SkipAll
+				}
+				return nil
+			},
+			expected: []string{
+				"",
+				"aliases",
+				"aliases[...]",
+				"extra",
+				"extra[0]",
+				"listener",
+				"listener[0]",
+				"listener[0].port",
+			},
+		},
+		"skip-root": {
+			fn:       func(string) error { return SkipChildren },
+			expected: []string{""},
+		},
+		"error": {
+			fn: func(path string) error {
+				if path == "extra" {
+					return errors.New("oops")
+				}
+				return nil
+			},
+			expected:    []string{"", "aliases", "aliases[...]", "extra"},
+			expectedErr: "oops",
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			err := Walk(server(), func(path *tftypes.AttributePath, val tftypes.Value) error {
+				got = append(got, tfpath.String(path))
+				return tc.fn(tfpath.String(path))
+			})
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestWalkPaths(t *testing.T) {
+	val := server()
+	err := Walk(val, func(path *tftypes.AttributePath, v tftypes.Value) error {
+		got, err := Get(val, path)
+		if err != nil {
+			return err
+		}
+		if !got.Equal(v) {
+			t.Errorf("%s: expected %s, got %s", path, v, got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
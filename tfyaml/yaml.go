@@ -0,0 +1,531 @@
+// Package tfyaml converts tftypes.Values and asgotypes.GoPrimitives to and
+// from YAML, for providers that accept YAML documents, like Kubernetes
+// manifests, as string attributes and need structured access to them.
+//
+// Numbers keep their full precision in both directions, rather than passing
+// through float64. YAML features that don't survive the round trip, or that
+// make documents mean something other than what they appear to, are
+// rejected with errors identifying their line: anchors and aliases, merge
+// keys, keys that aren't strings, duplicate keys, custom tags, non-finite
+// numbers, and streams of more than one document.
+package tfyaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	tfString  tftypes.Type = tftypes.String
+	tfNumber  tftypes.Type = tftypes.Number
+	tfBool    tftypes.Type = tftypes.Bool
+	tfDynamic tftypes.Type = tftypes.DynamicPseudoType
+	tfList    tftypes.Type = tftypes.List{}
+	tfSet     tftypes.Type = tftypes.Set{}
+	tfTuple   tftypes.Type = tftypes.Tuple{}
+	tfMap     tftypes.Type = tftypes.Map{}
+	tfObject  tftypes.Type = tftypes.Object{}
+)
+
+const (
+	strTag   = "!!str"
+	intTag   = "!!int"
+	floatTag = "!!float"
+	boolTag  = "!!bool"
+	nullTag  = "!!null"
+)
+
+// ToYAML returns the known value `val` as a YAML document. Maps and
+// objects are written with their keys sorted, so the output is
+// deterministic.
+func ToYAML(val tftypes.Value) ([]byte, error) {
+	n, err := valueNode(tftypes.NewAttributePath(), val)
+	if err != nil {
+		return nil, err
+	}
+	return encode(n)
+}
+
+// PrimitiveToYAML returns the Value of `p` as a YAML document, for values
+// that have been decoded into a GoPrimitive, or built to look like one.
+func PrimitiveToYAML(p asgotypes.GoPrimitive) ([]byte, error) {
+	n, err := goNode(tftypes.NewAttributePath(), reflect.ValueOf(p.Value))
+	if err != nil {
+		return nil, err
+	}
+	return encode(n)
+}
+
+func encode(n *yaml.Node) ([]byte, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func scalar(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+// yaml11Bools are the strings YAML 1.1 reads as bools, which YAML 1.2, and
+// so yaml.v3, doesn't quote, but which many YAML parsers still read as
+// bools.
+var yaml11Bools = map[string]bool{
+	"y": true, "yes": true, "n": true, "no": true, "on": true, "off": true,
+}
+
+func stringNode(s string) *yaml.Node {
+	n := scalar(strTag, s)
+	if yaml11Bools[strings.ToLower(s)] {
+		n.Style = yaml.DoubleQuotedStyle
+	}
+	return n
+}
+
+func numberNode(f *big.Float) *yaml.Node {
+	if f.IsInt() {
+		return scalar(intTag, f.Text('f', -1))
+	}
+	return scalar(floatTag, f.Text('f', -1))
+}
+
+func boolNode(b bool) *yaml.Node {
+	return scalar(boolTag, fmt.Sprint(b))
+}
+
+func valueNode(path *tftypes.AttributePath, val tftypes.Value) (*yaml.Node, error) {
+	if !val.IsKnown() {
+		return nil, path.NewErrorf("cannot convert an unknown value to YAML")
+	}
+	if val.IsNull() {
+		return scalar(nullTag, "null"), nil
+	}
+	typ := val.Type()
+	switch {
+	case typ.Is(tfString):
+		var s string
+		err := val.As(&s)
+		return stringNode(s), err
+	case typ.Is(tfNumber):
+		f := new(big.Float)
+		err := val.As(&f)
+		return numberNode(f), err
+	case typ.Is(tfBool):
+		var b bool
+		err := val.As(&b)
+		return boolNode(b), err
+	case typ.Is(tfMap), typ.Is(tfObject):
+		var attrs map[string]tftypes.Value
+		if err := val.As(&attrs); err != nil {
+			return nil, path.NewError(err)
+		}
+		keys := make([]string, 0, len(attrs))
+		for k := range attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range keys {
+			next := path.WithAttributeName(k)
+			if typ.Is(tfMap) {
+				next = path.WithElementKeyString(k)
+			}
+			v, err := valueNode(next, attrs[k])
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, stringNode(k), v)
+		}
+		return n, nil
+	}
+	var elems []tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return nil, path.NewError(err)
+	}
+	n := &yaml.Node{Kind: yaml.SequenceNode}
+	for i, elem := range elems {
+		next := path.WithElementKeyInt(i)
+		if typ.Is(tfSet) {
+			next = path.WithElementKeyValue(elem)
+		}
+		v, err := valueNode(next, elem)
+		if err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, v)
+	}
+	return n, nil
+}
+
+func goNode(path *tftypes.AttributePath, v reflect.Value) (*yaml.Node, error) {
+	if !v.IsValid() {
+		return scalar(nullTag, "null"), nil
+	}
+	switch x := v.Interface().(type) {
+	case *big.Float:
+		if x == nil {
+			return scalar(nullTag, "null"), nil
+		}
+		return numberNode(x), nil
+	case string:
+		return stringNode(x), nil
+	case bool:
+		return boolNode(x), nil
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return scalar(nullTag, "null"), nil
+		}
+		return goNode(path, v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return scalar(nullTag, "null"), nil
+		}
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := goNode(path.WithElementKeyInt(i), v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, elem)
+		}
+		return n, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			return scalar(nullTag, "null"), nil
+		}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range keys {
+			elem, err := goNode(path.WithAttributeName(k), v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key())))
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, stringNode(k), elem)
+		}
+		return n, nil
+	}
+	return nil, path.NewErrorf("unsupported %s in GoPrimitive", v.Type())
+}
+
+// FromYAML parses the YAML document `data` as a value of type `typ`. An
+// empty document is null.
+//
+// Any scalar can be read as a string, as it's written, so
+// `version: 1.10` read as a string is "1.10". If `typ` is, or contains,
+// tftypes.DynamicPseudoType, the type is inferred from the YAML:
+// sequences are tuples, mappings objects, and scalars strings, numbers,
+// bools, or null, following YAML's rules for plain scalars.
+func FromYAML(data []byte, typ tftypes.Type) (tftypes.Value, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+		return tftypes.NewValue(typ, nil), nil
+	} else if err != nil {
+		return tftypes.Value{}, err
+	}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.Value{}, fmt.Errorf("line %d: only one YAML document is supported", extra.Line)
+	}
+	if len(doc.Content) == 0 {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	return fromNode(tftypes.NewAttributePath(), doc.Content[0], typ)
+}
+
+// PrimitiveFromYAML parses the YAML document `data` as a GoPrimitive, like
+// FromYAML with tftypes.DynamicPseudoType, with lists and maps as
+// []interface{} and map[string]interface{}.
+func PrimitiveFromYAML(data []byte) (asgotypes.GoPrimitive, error) {
+	val, err := FromYAML(data, tfDynamic)
+	if err != nil {
+		return asgotypes.GoPrimitive{}, err
+	}
+	p := asgotypes.GoPrimitive{Collections: asgotypes.CollectionsLenient}
+	if err := p.FromTerraform5Value(val); err != nil {
+		return asgotypes.GoPrimitive{}, err
+	}
+	return p, nil
+}
+
+func nodeErrorf(path *tftypes.AttributePath, n *yaml.Node, format string, args ...interface{}) error {
+	return path.NewErrorf("line %d: %s", n.Line, fmt.Sprintf(format, args...))
+}
+
+// fromNode converts `n` into a value of type `typ`.
+func fromNode(path *tftypes.AttributePath, n *yaml.Node, typ tftypes.Type) (tftypes.Value, error) {
+	if n.Kind == yaml.AliasNode {
+		return tftypes.Value{}, nodeErrorf(path, n, "aliases are not supported, found *%s", n.Value)
+	}
+	if n.Anchor != "" {
+		return tftypes.Value{}, nodeErrorf(path, n, "anchors are not supported, found &%s", n.Anchor)
+	}
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == nullTag {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	switch tag := n.ShortTag(); tag {
+	case strTag, intTag, floatTag, boolTag, nullTag, "!!seq", "!!map", "!!timestamp", "!!binary":
+	default:
+		return tftypes.Value{}, nodeErrorf(path, n, "unsupported tag %s", tag)
+	}
+	if typ.Is(tfDynamic) {
+		return inferNode(path, n)
+	}
+
+	switch {
+	case typ.Is(tfString):
+		if n.Kind != yaml.ScalarNode {
+			return tftypes.Value{}, nodeErrorf(path, n, "expected a string, got a %s", kindName(n))
+		}
+		return tftypes.NewValue(typ, n.Value), nil
+	case typ.Is(tfNumber):
+		f, err := number(path, n)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(typ, f), nil
+	case typ.Is(tfBool):
+		if n.Kind != yaml.ScalarNode || n.ShortTag() != boolTag {
+			return tftypes.Value{}, nodeErrorf(path, n, "expected a bool, got %s", describe(n))
+		}
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return tftypes.Value{}, nodeErrorf(path, n, "%s", err)
+		}
+		return tftypes.NewValue(typ, b), nil
+	case typ.Is(tfList), typ.Is(tfSet), typ.Is(tfTuple):
+		if n.Kind != yaml.SequenceNode {
+			return tftypes.Value{}, nodeErrorf(path, n, "expected a sequence, got %s", describe(n))
+		}
+		var elemTypes []tftypes.Type
+		switch t := typ.(type) {
+		case tftypes.List:
+			elemTypes = repeat(t.ElementType, len(n.Content))
+		case tftypes.Set:
+			elemTypes = repeat(t.ElementType, len(n.Content))
+		case tftypes.Tuple:
+			if len(t.ElementTypes) != len(n.Content) {
+				return tftypes.Value{}, nodeErrorf(path, n, "expected %d elements, got %d", len(t.ElementTypes), len(n.Content))
+			}
+			elemTypes = t.ElementTypes
+		}
+		elems := make([]tftypes.Value, 0, len(n.Content))
+		for i, c := range n.Content {
+			elem, err := fromNode(path.WithElementKeyInt(i), c, elemTypes[i])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		if typ.Is(tfSet) {
+			elems = setElements(elems)
+		}
+		if err := tftypes.ValidateValue(typ, elems); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case typ.Is(tfMap), typ.Is(tfObject):
+		entries, err := mapping(path, n)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		attrs := make(map[string]tftypes.Value, len(entries))
+		if m, ok := typ.(tftypes.Map); ok {
+			for k, c := range entries {
+				if attrs[k], err = fromNode(path.WithElementKeyString(k), c, m.ElementType); err != nil {
+					return tftypes.Value{}, err
+				}
+			}
+		} else {
+			obj := typ.(tftypes.Object)
+			for k, c := range entries {
+				attrType, ok := obj.AttributeTypes[k]
+				if !ok {
+					return tftypes.Value{}, nodeErrorf(path.WithAttributeName(k), c, "unexpected attribute")
+				}
+				if attrs[k], err = fromNode(path.WithAttributeName(k), c, attrType); err != nil {
+					return tftypes.Value{}, err
+				}
+			}
+			for k, attrType := range obj.AttributeTypes {
+				if _, ok := attrs[k]; !ok {
+					attrs[k] = tftypes.NewValue(attrType, nil)
+				}
+			}
+		}
+		if err := tftypes.ValidateValue(typ, attrs); err != nil {
+			return tftypes.Value{}, path.NewError(err)
+		}
+		return tftypes.NewValue(typ, attrs), nil
+	}
+	return tftypes.Value{}, path.NewErrorf("unsupported type %s", typ)
+}
+
+// inferNode converts `n` into a value, inferring its type.
+func inferNode(path *tftypes.AttributePath, n *yaml.Node) (tftypes.Value, error) {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		elems := make([]tftypes.Value, 0, len(n.Content))
+		types := make([]tftypes.Type, 0, len(n.Content))
+		for i, c := range n.Content {
+			elem, err := fromNode(path.WithElementKeyInt(i), c, tfDynamic)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+			types = append(types, elem.Type())
+		}
+		return tftypes.NewValue(tftypes.Tuple{ElementTypes: types}, elems), nil
+	case yaml.MappingNode:
+		entries, err := mapping(path, n)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		attrs := make(map[string]tftypes.Value, len(entries))
+		types := make(map[string]tftypes.Type, len(entries))
+		for k, c := range entries {
+			attr, err := fromNode(path.WithAttributeName(k), c, tfDynamic)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrs[k] = attr
+			types[k] = attr.Type()
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, attrs), nil
+	}
+	switch n.ShortTag() {
+	case intTag, floatTag:
+		f, err := number(path, n)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(tfNumber, f), nil
+	case boolTag:
+		return fromNode(path, n, tfBool)
+	}
+	return tftypes.NewValue(tfString, n.Value), nil
+}
+
+// mapping returns the entries of the mapping node `n`, by key.
+func mapping(path *tftypes.AttributePath, n *yaml.Node) (map[string]*yaml.Node, error) {
+	if n.Kind != yaml.MappingNode {
+		return nil, nodeErrorf(path, n, "expected a mapping, got %s", describe(n))
+	}
+	entries := make(map[string]*yaml.Node, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind == yaml.AliasNode || k.Anchor != "" {
+			return nil, nodeErrorf(path, k, "anchors and aliases are not supported")
+		}
+		if k.ShortTag() == "!!merge" {
+			return nil, nodeErrorf(path, k, "merge keys are not supported")
+		}
+		if k.Kind != yaml.ScalarNode || k.ShortTag() != strTag {
+			return nil, nodeErrorf(path, k, "keys must be strings, got %s; quote it to use it as a string", describe(k))
+		}
+		if _, ok := entries[k.Value]; ok {
+			return nil, nodeErrorf(path, k, "duplicate key %q", k.Value)
+		}
+		entries[k.Value] = v
+	}
+	return entries, nil
+}
+
+// number returns the number the scalar `n` holds, in full precision.
+func number(path *tftypes.AttributePath, n *yaml.Node) (*big.Float, error) {
+	if n.Kind != yaml.ScalarNode {
+		return nil, nodeErrorf(path, n, "expected a number, got %s", describe(n))
+	}
+	switch n.ShortTag() {
+	case intTag:
+		i, ok := new(big.Int).SetString(strings.ReplaceAll(n.Value, "_", ""), 0)
+		if !ok {
+			return nil, nodeErrorf(path, n, "invalid integer %q", n.Value)
+		}
+		return new(big.Float).SetInt(i), nil
+	case floatTag:
+		switch strings.ToLower(strings.TrimLeft(n.Value, "+-")) {
+		case ".inf", ".nan":
+			return nil, nodeErrorf(path, n, "non-finite number %s is not supported", n.Value)
+		}
+		f, _, err := big.ParseFloat(strings.ReplaceAll(n.Value, "_", ""), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, nodeErrorf(path, n, "invalid number %q", n.Value)
+		}
+		return f, nil
+	}
+	return nil, nodeErrorf(path, n, "expected a number, got %s", describe(n))
+}
+
+func kindName(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		return "sequence"
+	case yaml.MappingNode:
+		return "mapping"
+	}
+	return "scalar"
+}
+
+// describe describes `n`, for errors.
+func describe(n *yaml.Node) string {
+	if n.Kind != yaml.ScalarNode {
+		return "a " + kindName(n)
+	}
+	return fmt.Sprintf("%s %q", n.ShortTag(), n.Value)
+}
+
+func repeat(typ tftypes.Type, n int) []tftypes.Type {
+	types := make([]tftypes.Type, n)
+	for i := range types {
+		types[i] = typ
+	}
+	return types
+}
+
+// setElements returns `elems` without duplicates, which YAML sequences can
+// have but sets can't.
+func setElements(elems []tftypes.Value) []tftypes.Value {
+	res := make([]tftypes.Value, 0, len(elems))
+	for _, elem := range elems {
+		dup := false
+		for _, seen := range res {
+			if seen.Equal(elem) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			res = append(res, elem)
+		}
+	}
+	return res
+}
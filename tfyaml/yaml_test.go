@@ -0,0 +1,235 @@
+package tfyaml
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go-contrib/asgotypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	containerType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":  tftypes.String,
+		"ports": tftypes.List{ElementType: tftypes.Number},
+	}}
+	manifestType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"apiVersion": tftypes.String,
+		"replicas":   tftypes.Number,
+		"paused":     tftypes.Bool,
+		"labels":     tftypes.Map{ElementType: tftypes.String},
+		"containers": tftypes.List{ElementType: containerType},
+		"version":    tftypes.String,
+	}}
+)
+
+func bigNumber(t *testing.T, s string) *big.Float {
+	t.Helper()
+	f, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return f
+}
+
+func testManifest(t *testing.T) tftypes.Value {
+	return tftypes.NewValue(manifestType, map[string]tftypes.Value{
+		"apiVersion": tftypes.NewValue(tftypes.String, "apps/v1"),
+		"replicas":   tftypes.NewValue(tftypes.Number, bigNumber(t, "12345678901234567890")),
+		"paused":     tftypes.NewValue(tftypes.Bool, false),
+		"labels": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"app":  tftypes.NewValue(tftypes.String, "web"),
+			"tier": tftypes.NewValue(tftypes.String, "true"),
+		}),
+		"containers": tftypes.NewValue(tftypes.List{ElementType: containerType}, []tftypes.Value{
+			tftypes.NewValue(containerType, map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "nginx"),
+				"ports": tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, []tftypes.Value{
+					tftypes.NewValue(tftypes.Number, big.NewFloat(80)),
+					tftypes.NewValue(tftypes.Number, bigNumber(t, "0.1")),
+				}),
+			}),
+		}),
+		"version": tftypes.NewValue(tftypes.String, nil),
+	})
+}
+
+const testManifestYAML = `apiVersion: apps/v1
+containers:
+  - name: nginx
+    ports:
+      - 80
+      - 0.1
+labels:
+  app: web
+  tier: "true"
+paused: false
+replicas: 12345678901234567890
+version: null
+`
+
+func TestToYAML(t *testing.T) {
+	got, err := ToYAML(testManifest(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(testManifestYAML, string(got)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	_, err = ToYAML(tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+	expectedErr := "cannot convert an unknown value to YAML"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
+
+func TestFromYAML(t *testing.T) {
+	type testCase struct {
+		yaml     string
+		typ      tftypes.Type
+		expected tftypes.Value
+	}
+	cases := map[string]testCase{
+		"round-trip": {
+			yaml:     testManifestYAML,
+			typ:      manifestType,
+			expected: testManifest(t),
+		},
+		"scalars-as-strings": {
+			yaml: "version: 1.10\napiVersion: 0x10\n",
+			typ:  manifestType,
+			expected: tftypes.NewValue(manifestType, map[string]tftypes.Value{
+				"apiVersion": tftypes.NewValue(tftypes.String, "0x10"),
+				"replicas":   tftypes.NewValue(tftypes.Number, nil),
+				"paused":     tftypes.NewValue(tftypes.Bool, nil),
+				"labels":     tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+				"containers": tftypes.NewValue(tftypes.List{ElementType: containerType}, nil),
+				"version":    tftypes.NewValue(tftypes.String, "1.10"),
+			}),
+		},
+		"number-forms": {
+			yaml: "[0x1F, 1_000, 1.5e3]",
+			typ:  tftypes.List{ElementType: tftypes.Number},
+			expected: tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, []tftypes.Value{
+				tftypes.NewValue(tftypes.Number, big.NewFloat(31)),
+				tftypes.NewValue(tftypes.Number, big.NewFloat(1000)),
+				tftypes.NewValue(tftypes.Number, big.NewFloat(1500)),
+			}),
+		},
+		"dynamic": {
+			yaml: "a: [1, x, true, ~]\n",
+			typ:  tftypes.DynamicPseudoType,
+			expected: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"a": tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.String, tftypes.Bool, tftypes.DynamicPseudoType}},
+			}}, map[string]tftypes.Value{
+				"a": tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.String, tftypes.Bool, tftypes.DynamicPseudoType}}, []tftypes.Value{
+					tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+					tftypes.NewValue(tftypes.String, "x"),
+					tftypes.NewValue(tftypes.Bool, true),
+					tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+				}),
+			}),
+		},
+		"empty": {
+			yaml:     "",
+			typ:      manifestType,
+			expected: tftypes.NewValue(manifestType, nil),
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			got, err := FromYAML([]byte(tc.yaml), tc.typ)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFromYAMLErrors(t *testing.T) {
+	type testCase struct {
+		yaml        string
+		expectedErr string
+	}
+	cases := map[string]testCase{
+		"anchor": {
+			yaml:        "labels: &l\n  app: web\n",
+			expectedErr: `AttributeName("labels"): line 1: anchors are not supported, found &l`,
+		},
+		"alias": {
+			yaml:        "apiVersion: v1\nversion: *v\n",
+			expectedErr: `yaml: unknown anchor 'v' referenced`,
+		},
+		"merge-key": {
+			yaml:        "labels:\n  <<: {app: web}\n",
+			expectedErr: `AttributeName("labels"): line 2: merge keys are not supported`,
+		},
+		"non-string-key": {
+			yaml:        "labels:\n  1: web\n",
+			expectedErr: `AttributeName("labels"): line 2: keys must be strings, got !!int "1"; quote it to use it as a string`,
+		},
+		"duplicate-key": {
+			yaml:        "paused: true\npaused: false\n",
+			expectedErr: `line 2: duplicate key "paused"`,
+		},
+		"custom-tag": {
+			yaml:        "apiVersion: !Ref name\n",
+			expectedErr: `AttributeName("apiVersion"): line 1: unsupported tag !Ref`,
+		},
+		"non-finite": {
+			yaml:        "replicas: .inf\n",
+			expectedErr: `AttributeName("replicas"): line 1: non-finite number .inf is not supported`,
+		},
+		"wrong-type": {
+			yaml:        "paused: yes\n",
+			expectedErr: `AttributeName("paused"): line 1: expected a bool, got !!str "yes"`,
+		},
+		"unexpected-attribute": {
+			yaml:        "kind: Deployment\n",
+			expectedErr: `AttributeName("kind"): line 1: unexpected attribute`,
+		},
+		"multiple-documents": {
+			yaml:        "paused: true\n---\npaused: false\n",
+			expectedErr: `line 2: only one YAML document is supported`,
+		},
+	}
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, err := FromYAML([]byte(tc.yaml), manifestType)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPrimitive(t *testing.T) {
+	p, err := PrimitiveFromYAML([]byte("b: [1, x]\na: 0.1\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := PrimitiveToYAML(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := "a: 0.1\nb:\n  - 1\n  - x\n"
+	if diff := cmp.Diff(expected, string(got)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+
+	got, err = PrimitiveToYAML(asgotypes.GoPrimitive{Value: map[string][]string{"k": {"1", "yes"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected = "k:\n  - \"1\"\n  - \"yes\"\n"
+	if diff := cmp.Diff(expected, string(got)); diff != "" {
+		t.Errorf("unexpected diff (-wanted, +got): %s", diff)
+	}
+}